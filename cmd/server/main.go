@@ -7,12 +7,23 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/crdant/replicated-mcp-server/pkg/config"
 	"github.com/crdant/replicated-mcp-server/pkg/logging"
 	"github.com/crdant/replicated-mcp-server/pkg/mcp"
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+	"github.com/crdant/replicated-mcp-server/pkg/tracing"
+)
+
+// Shutdown settings
+const (
+	// tracingFlushTimeout bounds how long shutdown waits for buffered traces to be exported
+	tracingFlushTimeout = 5 * time.Second
+	// stopTimeout bounds how long shutdown waits for the MCP server to clean up
+	stopTimeout = 10 * time.Second
 )
 
 var (
@@ -32,12 +43,109 @@ enabling AI agents to interact with Replicated Vendor Portal accounts.`,
 
 func init() {
 	// Define flags and configuration settings
+	rootCmd.PersistentFlags().String("config", "", "Config file (default ~/.config/replicated-mcp/config.yaml)")
+	rootCmd.PersistentFlags().String("profile", "", "Config file profile to use")
 	rootCmd.PersistentFlags().String("api-token", "", "Replicated Vendor Portal API token")
+	rootCmd.PersistentFlags().String("api-token-file", "", "Read the API token from this file")
+	rootCmd.PersistentFlags().String("api-token-command", "", "Read the API token from this command's output "+
+		"(e.g. \"op read op://vault/replicated/token\")")
+	rootCmd.PersistentFlags().String("api-token-keychain", "", "Read the API token from the OS keychain item "+
+		"with this service name")
 	rootCmd.PersistentFlags().String("log-level", "fatal", "Log level (fatal, error, info, debug, trace)")
 	const defaultTimeout = 30
 	rootCmd.PersistentFlags().Int("timeout", defaultTimeout, "API request timeout in seconds")
 	rootCmd.PersistentFlags().String("endpoint", "", "API endpoint (hidden)")
 	_ = rootCmd.PersistentFlags().MarkHidden("endpoint")
+	rootCmd.PersistentFlags().String("app", "", "Application ID or slug that tools use when a call omits app_id")
+	rootCmd.PersistentFlags().String("fallback-endpoint", "", "Fallback API endpoint (such as a proxy) that serves "+
+		"reads while the primary endpoint is failing")
+	rootCmd.PersistentFlags().String("proxy", "", "Send API requests through this proxy "+
+		"(e.g. http://proxy.example.com:3128), overriding HTTP_PROXY, HTTPS_PROXY, and NO_PROXY")
+	rootCmd.PersistentFlags().String("ca-bundle", "", "PEM file of certificate authorities to trust for API "+
+		"requests in addition to the system's, such as an intercepting proxy's")
+	rootCmd.PersistentFlags().Bool("insecure-skip-tls-verify", false, "Do not verify the API's TLS certificate "+
+		"(exposes the API token to interception; for troubleshooting only)")
+	rootCmd.PersistentFlags().Bool("hedge-reads", false, "Send a second request for slow reads and use "+
+		"the first response")
+	rootCmd.PersistentFlags().StringSlice("hedge-classes", nil, "Endpoint classes to hedge, optionally with "+
+		"a fixed delay (e.g. customers,releases=250ms)")
+	rootCmd.PersistentFlags().Int("max-concurrent-requests", config.DefaultMaxConcurrentRequests,
+		"Maximum in-flight API requests per host (0 for no limit)")
+	rootCmd.PersistentFlags().Float64("requests-per-second", config.DefaultRequestsPerSecond,
+		"Maximum API requests sent per second to each host (0 for no limit)")
+	rootCmd.PersistentFlags().Int("search-scan-limit", config.DefaultSearchScanLimit,
+		"Maximum customers a search reads before filtering (0 for no limit)")
+	rootCmd.PersistentFlags().Bool("search-index", false, "Rank search results by relevance using a local "+
+		"index of each application's entities")
+	rootCmd.PersistentFlags().Int("max-request-bytes", config.DefaultMaxRequestBytes,
+		"Maximum size of an HTTP transport request body in bytes (0 for no limit)")
+	rootCmd.PersistentFlags().Int("max-argument-bytes", config.DefaultMaxArgumentBytes,
+		"Maximum JSON size of each tool call argument in bytes (0 for no limit)")
+	rootCmd.PersistentFlags().Int("max-array-length", config.DefaultMaxArrayLength,
+		"Maximum number of items in any list in tool call arguments (0 for no limit)")
+	rootCmd.PersistentFlags().Int("max-result-bytes", config.DefaultMaxResultBytes,
+		"Maximum size of each tool result in bytes; longer lists are cut to fit (0 for no limit)")
+	rootCmd.PersistentFlags().String("har-file", "", "Capture API traffic to this HAR file, with tokens and "+
+		"personal data redacted")
+	rootCmd.PersistentFlags().String("audit-log", "", "Append a JSON Lines record of every tool call that "+
+		"changes the vendor account to this file, with secrets redacted")
+	rootCmd.PersistentFlags().StringArray("redact-pattern", nil, "Also mask text matching this regular "+
+		"expression (e.g. a license ID format) in logs, HAR captures, and audit records (repeatable)")
+	rootCmd.PersistentFlags().Bool("dev", false, "Enable developer mode (adds _debug details to tool results)")
+	rootCmd.PersistentFlags().String("locale", "", "Locale for numbers in formatted reports (e.g. en-US, de-DE)")
+	rootCmd.PersistentFlags().String("data-dir", "", "Directory for exports and server-side state "+
+		"(default ~/.replicated-mcp-server)")
+	rootCmd.PersistentFlags().Bool("disk-cache", false, "Cache published releases on disk so they are "+
+		"downloaded once across sessions")
+	rootCmd.PersistentFlags().String("cache-dir", "", "Directory for the disk cache "+
+		"(default $XDG_CACHE_HOME/replicated-mcp-server)")
+	rootCmd.PersistentFlags().Duration("resource-poll-interval", config.DefaultResourcePollInterval,
+		"How often resources clients subscribe to are checked for changes (0 turns the checks off)")
+	rootCmd.PersistentFlags().Bool("read-only", false, "Do not expose tools that modify the vendor account")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Tools that modify the vendor account describe the change "+
+		"they would make instead of making it")
+	rootCmd.PersistentFlags().Bool("mock", false, "Answer Vendor Portal API requests from JSON fixtures instead "+
+		"of the network, for demos and offline testing without an API token")
+	rootCmd.PersistentFlags().String("mock-fixtures", "", "Directory of JSON fixtures used in mock mode "+
+		"(default: built-in demo fixtures)")
+	rootCmd.PersistentFlags().String("record-fixtures", "", "Directory to record redacted API responses to "+
+		"as fixtures that --mock-fixtures can replay")
+	rootCmd.PersistentFlags().Bool("cluster-commands", false, "Expose run_cluster_command for running "+
+		"allow-listed kubectl commands against clusters the server created")
+	rootCmd.PersistentFlags().Float64("slo-availability", config.DefaultSLOAvailability, "Fraction of Vendor "+
+		"Portal API requests that should succeed")
+	rootCmd.PersistentFlags().Duration("slo-latency", config.DefaultSLOLatency, "Latency within which Vendor "+
+		"Portal API requests should complete")
+	rootCmd.PersistentFlags().Float64("slo-latency-target", config.DefaultSLOLatencyTarget, "Fraction of Vendor "+
+		"Portal API requests that should complete within --slo-latency")
+	rootCmd.PersistentFlags().StringSlice("enabled-tools", nil, "Expose only these tools or tool categories "+
+		"(e.g. customers,releases)")
+	rootCmd.PersistentFlags().StringSlice("disable-tool", nil, "Do not expose this tool or tool category "+
+		"(repeatable)")
+	rootCmd.PersistentFlags().Duration("tool-timeout", config.DefaultToolTimeout, "Cancel tool calls that run "+
+		"longer than this (0 for no limit)")
+	rootCmd.PersistentFlags().StringSlice("tool-timeouts", nil, "Time limits for individual tools overriding "+
+		"--tool-timeout (e.g. create_cluster=10m)")
+	rootCmd.PersistentFlags().String("transport", config.TransportStdio, "Transport for MCP clients (stdio, http)")
+	rootCmd.PersistentFlags().String("listen", config.DefaultListenAddress, "Address the HTTP transport "+
+		"listens on")
+	rootCmd.PersistentFlags().String("http-auth-token", "", "Bearer token HTTP transport clients must send "+
+		"(required to listen on an address other than loopback)")
+	rootCmd.PersistentFlags().String("access-log-file", "", "Write HTTP transport access logs to this file "+
+		"(default stderr)")
+	rootCmd.PersistentFlags().Float64("access-log-sample-rate", config.DefaultAccessLogSampleRate,
+		"Fraction of successful HTTP requests to write to the access log (failed requests are always logged)")
+	rootCmd.PersistentFlags().Bool("metrics-endpoint", false, "Serve metrics for Prometheus to scrape at "+
+		"/metrics on the HTTP transport")
+	rootCmd.PersistentFlags().String("otlp-endpoint", "", "Export traces of tool calls and API requests to "+
+		"this OTLP/HTTP collector (e.g. http://localhost:4318)")
+	rootCmd.PersistentFlags().String("tls-cert", "", "Serve the HTTP transport over TLS with this certificate "+
+		"file, reloaded when it changes")
+	rootCmd.PersistentFlags().String("tls-key", "", "Private key file for --tls-cert")
+	rootCmd.PersistentFlags().StringSlice("tls-autocert-domains", nil, "Serve the HTTP transport over TLS "+
+		"with Let's Encrypt certificates for these domains (requires port 443)")
+	rootCmd.PersistentFlags().StringSlice("allowed-origins", nil, "Browser origins allowed to call the HTTP "+
+		"transport (e.g. https://agent.example.com, or * for any)")
 }
 
 func runServer(cmd *cobra.Command, _ []string) error {
@@ -47,8 +155,12 @@ func runServer(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Initialize structured logger
-	logger := logging.NewLogger(cfg.LogLevel)
+	// Initialize structured logger, masking credentials and personal data
+	redactor, err := redact.New(cfg.RedactPatterns)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger := logging.NewLoggerWithRedactor(cfg.LogLevel, os.Stderr, redactor)
 
 	// Log startup information
 	logger.Info("Replicated MCP Server starting",
@@ -57,11 +169,25 @@ func runServer(cmd *cobra.Command, _ []string) error {
 		"commit", commit,
 		"config", cfg.String())
 
+	// Export traces when a collector is configured, flushing them on shutdown
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.OTLPEndpoint, version)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	defer func() {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer flushCancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
 	// Initialize MCP server
 	mcpServer, err := mcp.NewServer(cfg, logger)
 	if err != nil {
 		return fmt.Errorf("failed to initialize MCP server: %w", err)
 	}
+	mcpServer.SetBuildInfo(mcp.BuildInfo{Version: version, BuildDate: buildDate, Commit: commit})
 
 	// Set up context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -77,12 +203,59 @@ func runServer(cmd *cobra.Command, _ []string) error {
 		cancel()
 	}()
 
-	// Start MCP server (this blocks until shutdown)
+	// Reload configuration on SIGHUP so token or mode changes apply without reconnecting;
+	// settings only read at startup are logged as needing a restart
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reloadChan:
+				logger.Info("Received reload signal, reloading configuration")
+				reloaded, err := config.Load(cmd)
+				if err != nil {
+					logger.Error("Failed to reload configuration", "error", err)
+					continue
+				}
+				if err := mcpServer.Reconfigure(reloaded); err != nil {
+					logger.Error("Failed to apply reloaded configuration", "error", err)
+				}
+			}
+		}
+	}()
+
+	// Dump metrics to stderr on SIGUSR1, where supported
+	metricsChan := make(chan os.Signal, 1)
+	notifyMetricsDump(metricsChan)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-metricsChan:
+				if err := mcpServer.Metrics().WriteText(os.Stderr); err != nil {
+					logger.Error("Failed to write metrics", "error", err)
+				}
+			}
+		}
+	}()
+
+	// Start MCP server (this blocks until the client disconnects or a shutdown signal arrives)
 	logger.Info("Starting MCP server - ready for AI agent connections")
 	if err := mcpServer.Start(ctx); err != nil {
 		return fmt.Errorf("MCP server error: %w", err)
 	}
 
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer stopCancel()
+	if err := mcpServer.Stop(stopCtx); err != nil {
+		return fmt.Errorf("failed to stop MCP server: %w", err)
+	}
+
 	logger.Info("Server shutdown complete")
 	return nil
 }
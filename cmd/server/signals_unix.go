@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyMetricsDump relays SIGUSR1, which asks the server to dump its metrics
+func notifyMetricsDump(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// notifyMetricsDump is a no-op on Windows, which has no SIGUSR1
+func notifyMetricsDump(_ chan<- os.Signal) {}
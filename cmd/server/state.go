@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+)
+
+// backupFileMode keeps state archives private, since notes may name customers
+const backupFileMode = 0o600
+
+var backupStateCmd = &cobra.Command{
+	Use:   "backup-state <archive>",
+	Short: "Export server-side state to an archive",
+	Long: `Export the server's local state (tags, notes, job records, and cached lookups) from the
+data directory to a portable archive, so it can be restored on another host. The server
+can keep running while the backup is taken.`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runBackupState,
+	SilenceUsage: true,
+}
+
+var restoreStateCmd = &cobra.Command{
+	Use:   "restore-state <archive>",
+	Short: "Import server-side state from an archive",
+	Long: `Import server-side state from an archive written by backup-state into the data directory.
+Stop servers using the data directory first. Existing state is only replaced with --force,
+and is then kept beside the restored state with a .bak suffix.`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runRestoreState,
+	SilenceUsage: true,
+}
+
+func init() {
+	restoreStateCmd.Flags().Bool("force", false, "Replace the state already in the data directory")
+	rootCmd.AddCommand(backupStateCmd, restoreStateCmd)
+}
+
+func runBackupState(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadLocal(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Never overwrite an existing archive, which may be the only copy of older state
+	file, err := os.OpenFile(filepath.Clean(args[0]), os.O_WRONLY|os.O_CREATE|os.O_EXCL, backupFileMode)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	manifest, err := store.Open(cfg.DataDirectory()).Backup(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(args[0])
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Backed up state from %s to %s\n", cfg.DataDirectory(), args[0])
+	printManifest(cmd.OutOrStdout(), manifest)
+	return nil
+}
+
+func runRestoreState(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadLocal(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filepath.Clean(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	manifest, err := store.Open(cfg.DataDirectory()).Restore(file, force)
+	if errors.Is(err, store.ErrStateExists) {
+		return fmt.Errorf("%w in %s; use --force to replace it", err, cfg.DataDirectory())
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Restored state from %s to %s\n", args[0], cfg.DataDirectory())
+	printManifest(cmd.OutOrStdout(), manifest)
+	return nil
+}
+
+// printManifest summarizes a backup's contents
+func printManifest(w io.Writer, manifest *store.BackupManifest) {
+	fmt.Fprintf(w, "  created:        %s\n", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(w, "  schema version: %d\n", manifest.SchemaVersion)
+
+	buckets := make([]string, 0, len(manifest.Records))
+	for bucket := range manifest.Records {
+		buckets = append(buckets, bucket)
+	}
+	slices.Sort(buckets)
+	for _, bucket := range buckets {
+		fmt.Fprintf(w, "  %-15s %d\n", bucket+":", manifest.Records[bucket])
+	}
+}
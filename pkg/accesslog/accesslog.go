@@ -0,0 +1,142 @@
+// Package accesslog writes structured access logs for the server's HTTP transport. Access
+// logs are kept apart from application logs: they have their own destination, are written
+// regardless of the configured log level, and carry "log": "access" so they can be told
+// apart when both go to stderr. Successful requests can be sampled to keep busy
+// deployments' logs manageable; failed requests are always logged.
+package accesslog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// sessionHeader is the header carrying the MCP session ID in streamable HTTP requests and
+// in the response that starts a session
+const sessionHeader = "Mcp-Session-Id"
+
+// failureStatus is the lowest status code that is always logged
+const failureStatus = http.StatusBadRequest
+
+// Logger writes access log entries for HTTP requests
+type Logger struct {
+	logger     *slog.Logger
+	sampleRate float64
+	random     func() float64
+	now        func() time.Time
+}
+
+// New returns a logger writing JSON access log entries to w. sampleRate is the fraction of
+// successful requests to log, from 0 (none) to 1 (all).
+func New(w io.Writer, sampleRate float64) *Logger {
+	logger := slog.New(slog.NewJSONHandler(w, nil)).With("log", "access")
+	return &Logger{
+		logger:     logger,
+		sampleRate: min(max(sampleRate, 0), 1),
+		random:     rand.Float64,
+		now:        time.Now,
+	}
+}
+
+// Handler wraps next so that each request it serves is logged once it completes
+func (l *Logger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := l.now()
+		recorder := &responseRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r)
+
+		l.log(r, recorder, l.now().Sub(started))
+	})
+}
+
+// log writes the entry for a completed request, if it is sampled
+func (l *Logger) log(r *http.Request, recorder *responseRecorder, duration time.Duration) {
+	status := recorder.statusCode()
+	if status < failureStatus && !l.sampled() {
+		return
+	}
+
+	// Requests that start a session only have its ID in the response
+	session := r.Header.Get(sessionHeader)
+	if session == "" {
+		session = recorder.Header().Get(sessionHeader)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("session", session),
+		slog.Int("status", status),
+		slog.Float64("duration_ms", float64(duration)/float64(time.Millisecond)),
+		slog.Int64("bytes", recorder.written),
+		slog.String("remote_addr", r.RemoteAddr),
+		slog.String("user_agent", r.UserAgent()),
+	}
+	if l.sampleRate < 1 {
+		// Lets readers scale sampled counts back up
+		attrs = append(attrs, slog.Float64("sample_rate", l.sampleRate))
+	}
+
+	l.logger.LogAttrs(context.Background(), slog.LevelInfo, "access", attrs...)
+}
+
+// sampled decides whether to log a successful request
+func (l *Logger) sampled() bool {
+	switch l.sampleRate {
+	case 1:
+		return true
+	case 0:
+		return false
+	default:
+		return l.random() < l.sampleRate
+	}
+}
+
+// responseRecorder captures the status code and body size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+// WriteHeader records the status code
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(data)
+	r.written += int64(n)
+	return n, err
+}
+
+// Flush passes flushes through, since streamed responses depend on them
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// statusCode returns the response status, which is 200 if the handler never set one
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
@@ -0,0 +1,185 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// entry is a decoded access log line
+type entry struct {
+	Log        string   `json:"log"`
+	Msg        string   `json:"msg"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Session    string   `json:"session"`
+	Status     int      `json:"status"`
+	DurationMS float64  `json:"duration_ms"`
+	Bytes      int64    `json:"bytes"`
+	SampleRate *float64 `json:"sample_rate"`
+}
+
+// decodeEntries parses the JSON lines written by a logger
+func decodeEntries(t *testing.T, output string) []entry {
+	t.Helper()
+
+	var entries []entry
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("Failed to decode access log line %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestLogger_Handler(t *testing.T) {
+	var output bytes.Buffer
+	logger := New(&output, 1)
+
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	logger.now = func() time.Time {
+		clock = clock.Add(25 * time.Millisecond)
+		return clock
+	}
+
+	handler := logger.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mcp":
+			if r.Header.Get(sessionHeader) == "" {
+				w.Header().Set(sessionHeader, "session-new")
+			}
+			fmt.Fprint(w, `{"jsonrpc": "2.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		session string
+		want    entry
+	}{
+		{
+			name:   "request starting a session",
+			method: http.MethodPost,
+			path:   "/mcp",
+			want:   entry{Method: http.MethodPost, Path: "/mcp", Session: "session-new", Status: http.StatusOK, Bytes: 18},
+		},
+		{
+			name:    "request in a session",
+			method:  http.MethodPost,
+			path:    "/mcp",
+			session: "session-1",
+			want:    entry{Method: http.MethodPost, Path: "/mcp", Session: "session-1", Status: http.StatusOK, Bytes: 18},
+		},
+		{
+			name:   "unknown path",
+			method: http.MethodGet,
+			path:   "/other",
+			want:   entry{Method: http.MethodGet, Path: "/other", Status: http.StatusNotFound, Bytes: 19},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output.Reset()
+
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.session != "" {
+				request.Header.Set(sessionHeader, tt.session)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			entries := decodeEntries(t, output.String())
+			if len(entries) != 1 {
+				t.Fatalf("Expected 1 access log entry, got %d", len(entries))
+			}
+			got := entries[0]
+			if got.Log != "access" || got.Msg != "access" {
+				t.Errorf("Expected the entry to be marked as an access log, got log=%q msg=%q", got.Log, got.Msg)
+			}
+			if got.DurationMS != 25 {
+				t.Errorf("Expected a duration of 25ms, got %v", got.DurationMS)
+			}
+			if got.SampleRate != nil {
+				t.Errorf("Expected no sample rate when every request is logged, got %v", *got.SampleRate)
+			}
+			got.Log, got.Msg, got.DurationMS = "", "", 0
+			if got != tt.want {
+				t.Errorf("Expected entry %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLogger_Sampling(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampleRate  float64
+		random      float64
+		status      int
+		expectEntry bool
+	}{
+		{name: "sampled in", sampleRate: 0.25, random: 0.1, status: http.StatusOK, expectEntry: true},
+		{name: "sampled out", sampleRate: 0.25, random: 0.5, status: http.StatusOK},
+		{name: "failures are always logged", sampleRate: 0.25, random: 0.5, status: http.StatusBadRequest,
+			expectEntry: true},
+		{name: "zero rate logs only failures", sampleRate: 0, random: 0, status: http.StatusOK},
+		{name: "zero rate still logs failures", sampleRate: 0, status: http.StatusInternalServerError,
+			expectEntry: true},
+		{name: "rates above one log everything", sampleRate: 5, random: 0.99, status: http.StatusOK,
+			expectEntry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			logger := New(&output, tt.sampleRate)
+			logger.random = func() float64 { return tt.random }
+
+			handler := logger.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/mcp", nil))
+
+			entries := decodeEntries(t, output.String())
+			if (len(entries) == 1) != tt.expectEntry {
+				t.Fatalf("Expected entry = %t, got %d entries", tt.expectEntry, len(entries))
+			}
+			if tt.expectEntry && tt.sampleRate > 0 && tt.sampleRate < 1 {
+				if entries[0].SampleRate == nil || *entries[0].SampleRate != tt.sampleRate {
+					t.Errorf("Expected sample rate %v in the entry, got %v", tt.sampleRate, entries[0].SampleRate)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseRecorder_Flush(t *testing.T) {
+	logger := New(&bytes.Buffer{}, 1)
+
+	response := httptest.NewRecorder()
+	handler := logger.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "event: message\n\n")
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Expected streamed responses to flush, got %v", err)
+		}
+	}))
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+
+	if !response.Flushed {
+		t.Error("Expected the flush to reach the underlying writer")
+	}
+}
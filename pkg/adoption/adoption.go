@@ -0,0 +1,168 @@
+// Package adoption aggregates which releases customers are running, per channel, how a release
+// was adopted after its promotion, which customers lag their channel's latest release, and the
+// Kubernetes platforms instances run on, from the Vendor Portal's channel, release, and
+// customer listings.
+package adoption
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// Percentage constants: ratios are scaled to percentages with one decimal place
+const (
+	percentScale     = 100
+	percentPrecision = 10
+)
+
+// Account holds the listings that adoption is computed from. Archived customers are ignored,
+// and only active instances count as running a release. When CheckedIn is set, only active
+// instances that last checked in within it count.
+type Account struct {
+	Channels  []models.Channel
+	Releases  []models.Release
+	Customers []models.Customer
+	CheckedIn *timerange.Range
+}
+
+// ChannelAdoption summarizes how many of a channel's customers and instances run its latest release.
+// Percentages of customers are of those with at least one active instance; a customer is on the
+// latest release when all of its active instances are.
+type ChannelAdoption struct {
+	ChannelID                string         `json:"channel_id"`
+	ChannelName              string         `json:"channel_name"`
+	LatestSequence           int64          `json:"latest_sequence"`
+	LatestVersion            string         `json:"latest_version,omitempty"`
+	Customers                int            `json:"customers"`
+	CustomersInstalled       int            `json:"customers_installed"`
+	CustomersOnLatest        int            `json:"customers_on_latest"`
+	PercentCustomersOnLatest float64        `json:"percent_customers_on_latest"`
+	Instances                int            `json:"instances"`
+	InstancesOnLatest        int            `json:"instances_on_latest"`
+	PercentInstancesOnLatest float64        `json:"percent_instances_on_latest"`
+	Versions                 []VersionCount `json:"versions"`
+}
+
+// VersionCount counts the instances and customers running a release on a channel
+type VersionCount struct {
+	VersionLabel    string `json:"version_label"`
+	ReleaseSequence int64  `json:"release_sequence"`
+	Instances       int    `json:"instances"`
+	Customers       int    `json:"customers"`
+	IsLatest        bool   `json:"is_latest"`
+}
+
+// ChannelAdoptions computes adoption for every channel, in listing order
+func (a *Account) ChannelAdoptions() []ChannelAdoption {
+	adoptions := make([]ChannelAdoption, 0, len(a.Channels))
+	for i := range a.Channels {
+		adoptions = append(adoptions, a.channelAdoption(&a.Channels[i]))
+	}
+	return adoptions
+}
+
+// channelAdoption computes adoption for one channel
+func (a *Account) channelAdoption(channel *models.Channel) ChannelAdoption {
+	adoption := ChannelAdoption{
+		ChannelID:      channel.ID,
+		ChannelName:    channel.Name,
+		LatestSequence: channel.ReleaseSequence,
+		LatestVersion:  a.versionLabel(channel.ReleaseSequence, ""),
+	}
+
+	versions := make(map[int64]*VersionCount)
+	for i := range a.Customers {
+		customer := &a.Customers[i]
+		if customer.IsArchived || customer.ChannelID != channel.ID {
+			continue
+		}
+		adoption.Customers++
+
+		instances := a.activeInstances(customer)
+		if len(instances) == 0 {
+			continue
+		}
+		adoption.CustomersInstalled++
+
+		onLatest := true
+		counted := make(map[int64]bool)
+		for _, instance := range instances {
+			count, ok := versions[instance.ReleaseSequence]
+			if !ok {
+				count = &VersionCount{
+					VersionLabel:    a.versionLabel(instance.ReleaseSequence, instance.VersionLabel),
+					ReleaseSequence: instance.ReleaseSequence,
+					IsLatest:        instance.ReleaseSequence == channel.ReleaseSequence,
+				}
+				versions[instance.ReleaseSequence] = count
+			}
+			count.Instances++
+			if !counted[instance.ReleaseSequence] {
+				count.Customers++
+				counted[instance.ReleaseSequence] = true
+			}
+
+			adoption.Instances++
+			if count.IsLatest {
+				adoption.InstancesOnLatest++
+			} else {
+				onLatest = false
+			}
+		}
+		if onLatest {
+			adoption.CustomersOnLatest++
+		}
+	}
+
+	adoption.PercentCustomersOnLatest = percent(adoption.CustomersOnLatest, adoption.CustomersInstalled)
+	adoption.PercentInstancesOnLatest = percent(adoption.InstancesOnLatest, adoption.Instances)
+	adoption.Versions = sortedVersions(versions)
+	return adoption
+}
+
+// activeInstances returns a customer's active instances that checked in within the account's
+// time range, or all of them when there is no range
+func (a *Account) activeInstances(customer *models.Customer) []models.Instance {
+	instances := customer.ActiveInstances()
+	if a.CheckedIn == nil {
+		return instances
+	}
+	return slices.DeleteFunc(instances, func(instance models.Instance) bool {
+		return instance.LastCheckinAt == nil || !a.CheckedIn.Contains(*instance.LastCheckinAt)
+	})
+}
+
+// versionLabel returns the label of the release with a sequence, or the fallback when the
+// release is not listed
+func (a *Account) versionLabel(sequence int64, fallback string) string {
+	for i := range a.Releases {
+		if a.Releases[i].Sequence == sequence && a.Releases[i].Version != "" {
+			return a.Releases[i].Version
+		}
+	}
+	return fallback
+}
+
+// sortedVersions returns version counts from the newest release to the oldest
+func sortedVersions(versions map[int64]*VersionCount) []VersionCount {
+	sorted := make([]VersionCount, 0, len(versions))
+	for _, count := range versions {
+		sorted = append(sorted, *count)
+	}
+	slices.SortFunc(sorted, func(a, b VersionCount) int {
+		return cmp.Compare(b.ReleaseSequence, a.ReleaseSequence)
+	})
+	return sorted
+}
+
+// percent returns part as a percentage of total, rounded to one decimal place, or 0 when total is 0
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*percentScale*percentPrecision/float64(total)) / percentPrecision
+}
@@ -0,0 +1,138 @@
+package adoption
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// testAccount has a stable channel at release 3 and a beta channel at release 4
+func testAccount() *Account {
+	return &Account{
+		Channels: []models.Channel{
+			{ID: "stable", Name: "Stable", ReleaseSequence: 3},
+			{ID: "beta", Name: "Beta", ReleaseSequence: 4},
+		},
+		Releases: []models.Release{
+			{Sequence: 2, Version: "1.1.0"},
+			{Sequence: 3, Version: "1.2.0"},
+			{Sequence: 4, Version: "1.3.0-beta.1"},
+		},
+		Customers: []models.Customer{
+			{ID: "acme", Name: "Acme", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "acme-1", ReleaseSequence: 3, IsActive: true},
+				{ID: "acme-2", ReleaseSequence: 3, IsActive: true},
+			}},
+			{ID: "globex", Name: "Globex", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "globex-1", ReleaseSequence: 3, IsActive: true},
+				{ID: "globex-2", ReleaseSequence: 2, IsActive: true},
+				{ID: "globex-3", ReleaseSequence: 1, VersionLabel: "1.0.0"},
+			}},
+			{ID: "initech", Name: "Initech", ChannelID: "stable"},
+			{ID: "hooli", Name: "Hooli", ChannelID: "stable", IsArchived: true, Instances: []models.Instance{
+				{ID: "hooli-1", ReleaseSequence: 2, IsActive: true},
+			}},
+			{ID: "umbrella", Name: "Umbrella", ChannelID: "beta", Instances: []models.Instance{
+				{ID: "umbrella-1", ReleaseSequence: 4, IsActive: true},
+			}},
+		},
+	}
+}
+
+func TestAccount_ChannelAdoptions(t *testing.T) {
+	adoptions := testAccount().ChannelAdoptions()
+	if len(adoptions) != 2 {
+		t.Fatalf("Expected 2 channels, got %d", len(adoptions))
+	}
+
+	tests := []struct {
+		name     string
+		got      ChannelAdoption
+		expected ChannelAdoption
+	}{
+		{
+			name: "stable",
+			got:  adoptions[0],
+			expected: ChannelAdoption{
+				ChannelID: "stable", ChannelName: "Stable", LatestSequence: 3, LatestVersion: "1.2.0",
+				Customers: 3, CustomersInstalled: 2, CustomersOnLatest: 1, PercentCustomersOnLatest: 50,
+				Instances: 4, InstancesOnLatest: 3, PercentInstancesOnLatest: 75,
+			},
+		},
+		{
+			name: "beta",
+			got:  adoptions[1],
+			expected: ChannelAdoption{
+				ChannelID: "beta", ChannelName: "Beta", LatestSequence: 4, LatestVersion: "1.3.0-beta.1",
+				Customers: 1, CustomersInstalled: 1, CustomersOnLatest: 1, PercentCustomersOnLatest: 100,
+				Instances: 1, InstancesOnLatest: 1, PercentInstancesOnLatest: 100,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.got
+			got.Versions = nil
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ChannelAdoption = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+
+	versions := adoptions[0].Versions
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 versions on stable, got %+v", versions)
+	}
+	latest, previous := versions[0], versions[1]
+	if latest.VersionLabel != "1.2.0" || latest.Instances != 3 || latest.Customers != 2 || !latest.IsLatest {
+		t.Errorf("Unexpected latest version count: %+v", latest)
+	}
+	if previous.VersionLabel != "1.1.0" || previous.Instances != 1 || previous.Customers != 1 || previous.IsLatest {
+		t.Errorf("Unexpected previous version count: %+v", previous)
+	}
+}
+
+func TestAccount_ChannelAdoptionsCheckedIn(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent, stale := now.Add(-24*time.Hour), now.Add(-60*24*time.Hour)
+
+	account := testAccount()
+	account.Customers[0].Instances[0].LastCheckinAt = &recent
+	account.Customers[0].Instances[1].LastCheckinAt = &stale
+	account.Customers[1].Instances[1].LastCheckinAt = &recent
+	account.CheckedIn = &timerange.Range{Start: now.Add(-30 * 24 * time.Hour), End: now}
+
+	stable := account.ChannelAdoptions()[0]
+	if stable.CustomersInstalled != 2 || stable.Instances != 2 || stable.InstancesOnLatest != 1 {
+		t.Errorf("Expected only instances checked in within the range to count, got %+v", stable)
+	}
+	if stable.CustomersOnLatest != 1 || stable.PercentCustomersOnLatest != 50 {
+		t.Errorf("Expected Acme alone to be on the latest release, got %+v", stable)
+	}
+
+	breakdown := account.VersionBreakdown("beta")
+	if len(breakdown.Versions) != 0 || len(breakdown.NotInstalled) != 1 {
+		t.Errorf("Expected an instance without check-ins to be left out, got %+v", breakdown)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		part, total int
+		want        float64
+	}{
+		{part: 1, total: 3, want: 33.3},
+		{part: 2, total: 3, want: 66.7},
+		{part: 0, total: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := percent(tt.part, tt.total); got != tt.want {
+			t.Errorf("percent(%d, %d) = %v, want %v", tt.part, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package adoption
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// VersionBreakdown groups customers by the releases their active instances run
+type VersionBreakdown struct {
+	Versions     []VersionGroup     `json:"versions"`
+	NotInstalled []CustomerInstance `json:"not_installed"`
+}
+
+// VersionGroup lists the customers running a release. A customer with instances on
+// several releases appears in each of their groups.
+type VersionGroup struct {
+	VersionLabel    string             `json:"version_label"`
+	ReleaseSequence int64              `json:"release_sequence"`
+	Customers       []CustomerInstance `json:"customers"`
+}
+
+// CustomerInstance identifies a customer and how many of its instances run a release
+type CustomerInstance struct {
+	CustomerID   string `json:"customer_id"`
+	CustomerName string `json:"customer_name"`
+	ChannelName  string `json:"channel_name,omitempty"`
+	Instances    int    `json:"instances"`
+}
+
+// VersionBreakdown groups customers by release, optionally limited to one channel's customers
+func (a *Account) VersionBreakdown(channelID string) VersionBreakdown {
+	breakdown := VersionBreakdown{Versions: []VersionGroup{}, NotInstalled: []CustomerInstance{}}
+	groups := make(map[int64]*VersionGroup)
+
+	for i := range a.Customers {
+		customer := &a.Customers[i]
+		if customer.IsArchived || (channelID != "" && customer.ChannelID != channelID) {
+			continue
+		}
+
+		entry := CustomerInstance{
+			CustomerID:   customer.ID,
+			CustomerName: customer.Name,
+			ChannelName:  a.channelName(customer),
+		}
+
+		instances := a.activeInstances(customer)
+		if len(instances) == 0 {
+			breakdown.NotInstalled = append(breakdown.NotInstalled, entry)
+			continue
+		}
+
+		perRelease := make(map[int64]int)
+		for _, instance := range instances {
+			if _, ok := groups[instance.ReleaseSequence]; !ok {
+				groups[instance.ReleaseSequence] = &VersionGroup{
+					VersionLabel:    a.versionLabel(instance.ReleaseSequence, instance.VersionLabel),
+					ReleaseSequence: instance.ReleaseSequence,
+				}
+			}
+			perRelease[instance.ReleaseSequence]++
+		}
+		for sequence, count := range perRelease {
+			entry.Instances = count
+			groups[sequence].Customers = append(groups[sequence].Customers, entry)
+		}
+	}
+
+	for _, group := range groups {
+		slices.SortFunc(group.Customers, func(a, b CustomerInstance) int {
+			return cmp.Compare(a.CustomerName, b.CustomerName)
+		})
+		breakdown.Versions = append(breakdown.Versions, *group)
+	}
+	slices.SortFunc(breakdown.Versions, func(a, b VersionGroup) int {
+		return cmp.Compare(b.ReleaseSequence, a.ReleaseSequence)
+	})
+	return breakdown
+}
+
+// channelName returns the name of a customer's channel, preferring the channel listing
+func (a *Account) channelName(customer *models.Customer) string {
+	for i := range a.Channels {
+		if a.Channels[i].ID == customer.ChannelID {
+			return a.Channels[i].Name
+		}
+	}
+	return customer.ChannelName
+}
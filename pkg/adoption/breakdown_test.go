@@ -0,0 +1,64 @@
+package adoption
+
+import "testing"
+
+func TestAccount_VersionBreakdown(t *testing.T) {
+	tests := []struct {
+		name             string
+		channelID        string
+		wantVersions     []string
+		wantCustomers    map[string][]string
+		wantNotInstalled int
+	}{
+		{
+			name:         "all channels",
+			wantVersions: []string{"1.3.0-beta.1", "1.2.0", "1.1.0"},
+			wantCustomers: map[string][]string{
+				"1.3.0-beta.1": {"Umbrella"},
+				"1.2.0":        {"Acme", "Globex"},
+				"1.1.0":        {"Globex"},
+			},
+			wantNotInstalled: 1,
+		},
+		{
+			name:             "one channel",
+			channelID:        "beta",
+			wantVersions:     []string{"1.3.0-beta.1"},
+			wantCustomers:    map[string][]string{"1.3.0-beta.1": {"Umbrella"}},
+			wantNotInstalled: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := testAccount().VersionBreakdown(tt.channelID)
+
+			if len(breakdown.Versions) != len(tt.wantVersions) {
+				t.Fatalf("Expected versions %v, got %+v", tt.wantVersions, breakdown.Versions)
+			}
+			for i, group := range breakdown.Versions {
+				if group.VersionLabel != tt.wantVersions[i] {
+					t.Errorf("Expected version %d to be %s, got %s", i, tt.wantVersions[i], group.VersionLabel)
+				}
+				var names []string
+				for _, customer := range group.Customers {
+					names = append(names, customer.CustomerName)
+				}
+				want := tt.wantCustomers[group.VersionLabel]
+				if len(names) != len(want) || (len(names) > 0 && names[0] != want[0]) {
+					t.Errorf("Expected customers %v on %s, got %v", want, group.VersionLabel, names)
+				}
+			}
+			if len(breakdown.NotInstalled) != tt.wantNotInstalled {
+				t.Errorf("Expected %d customers not installed, got %+v", tt.wantNotInstalled, breakdown.NotInstalled)
+			}
+		})
+	}
+
+	// Instances are counted per release for each customer
+	breakdown := testAccount().VersionBreakdown("stable")
+	acme := breakdown.Versions[0].Customers[0]
+	if acme.CustomerName != "Acme" || acme.Instances != 2 || acme.ChannelName != "Stable" {
+		t.Errorf("Unexpected customer entry: %+v", acme)
+	}
+}
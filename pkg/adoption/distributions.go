@@ -0,0 +1,136 @@
+package adoption
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// unknownPlatform groups instances that did not report their distribution or version
+const unknownPlatform = "unknown"
+
+// minorVersionPattern finds the major and minor version in a Kubernetes version such as
+// v1.29.3+k3s1
+var minorVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// DistributionReport summarizes the Kubernetes distributions and versions active instances
+// run. It holds only counts, never customer names or IDs.
+type DistributionReport struct {
+	Instances     int                 `json:"instances"`
+	Customers     int                 `json:"customers"`
+	Distributions []DistributionCount `json:"distributions"`
+	Versions      []PlatformCount     `json:"versions"`
+}
+
+// DistributionCount counts the instances and customers on a Kubernetes distribution, and
+// the versions of it they run
+type DistributionCount struct {
+	PlatformCount
+	Versions []PlatformCount `json:"versions"`
+}
+
+// PlatformCount counts the instances and customers on a distribution or Kubernetes minor
+// version. Percentages are of all counted instances.
+type PlatformCount struct {
+	Name             string  `json:"name"`
+	Instances        int     `json:"instances"`
+	Customers        int     `json:"customers"`
+	PercentInstances float64 `json:"percent_instances"`
+}
+
+// platformTally accumulates instance and customer counts for one distribution or version
+type platformTally struct {
+	instances int
+	customers map[string]bool
+}
+
+// Distributions summarizes the Kubernetes distributions and minor versions that active
+// instances run, optionally limited to one channel's customers. Versions are reduced to
+// their minor version, such as 1.29, and are counted both overall and per distribution.
+func (a *Account) Distributions(channelID string) DistributionReport {
+	distributions := make(map[string]*platformTally)
+	versions := make(map[string]*platformTally)
+	distributionVersions := make(map[string]map[string]*platformTally)
+	customers := make(map[string]bool)
+
+	report := DistributionReport{}
+	for i := range a.Customers {
+		customer := &a.Customers[i]
+		if customer.IsArchived || (channelID != "" && customer.ChannelID != channelID) {
+			continue
+		}
+
+		for _, instance := range a.activeInstances(customer) {
+			distribution, version := platformOf(&instance)
+			if distributionVersions[distribution] == nil {
+				distributionVersions[distribution] = make(map[string]*platformTally)
+			}
+
+			tally(distributions, distribution, customer.ID)
+			tally(versions, version, customer.ID)
+			tally(distributionVersions[distribution], version, customer.ID)
+			customers[customer.ID] = true
+			report.Instances++
+		}
+	}
+
+	report.Customers = len(customers)
+	report.Versions = platformCounts(versions, report.Instances)
+	report.Distributions = make([]DistributionCount, 0, len(distributions))
+	for _, count := range platformCounts(distributions, report.Instances) {
+		report.Distributions = append(report.Distributions, DistributionCount{
+			PlatformCount: count,
+			Versions:      platformCounts(distributionVersions[count.Name], report.Instances),
+		})
+	}
+	return report
+}
+
+// platformOf returns an instance's distribution, lowercased, and Kubernetes minor version,
+// or unknown for either when the instance did not report it
+func platformOf(instance *models.Instance) (distribution, version string) {
+	distribution = strings.ToLower(strings.TrimSpace(instance.KubernetesDistribution))
+	if distribution == "" {
+		distribution = unknownPlatform
+	}
+
+	version = unknownPlatform
+	if match := minorVersionPattern.FindStringSubmatch(instance.KubernetesVersion); match != nil {
+		version = match[1] + "." + match[2]
+	}
+	return distribution, version
+}
+
+// tally counts an instance of a customer under a name
+func tally(tallies map[string]*platformTally, name, customerID string) {
+	t, ok := tallies[name]
+	if !ok {
+		t = &platformTally{customers: make(map[string]bool)}
+		tallies[name] = t
+	}
+	t.instances++
+	t.customers[customerID] = true
+}
+
+// platformCounts converts tallies to counts, most instances first and then by name
+func platformCounts(tallies map[string]*platformTally, total int) []PlatformCount {
+	counts := make([]PlatformCount, 0, len(tallies))
+	for name, t := range tallies {
+		counts = append(counts, PlatformCount{
+			Name:             name,
+			Instances:        t.instances,
+			Customers:        len(t.customers),
+			PercentInstances: percent(t.instances, total),
+		})
+	}
+	slices.SortFunc(counts, func(a, b PlatformCount) int {
+		if c := cmp.Compare(b.Instances, a.Instances); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return counts
+}
@@ -0,0 +1,93 @@
+package adoption
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// platformAccount has instances on two distributions, including one that reports nothing
+func platformAccount() *Account {
+	return &Account{
+		Channels: []models.Channel{{ID: "stable", Name: "Stable"}, {ID: "beta", Name: "Beta"}},
+		Customers: []models.Customer{
+			{ID: "acme", Name: "Acme", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "acme-1", IsActive: true, KubernetesDistribution: "EKS", KubernetesVersion: "v1.29.3-eks-1"},
+				{ID: "acme-2", IsActive: true, KubernetesDistribution: "eks", KubernetesVersion: "1.28.9"},
+				{ID: "acme-3", KubernetesDistribution: "gke", KubernetesVersion: "1.27.0"},
+			}},
+			{ID: "globex", Name: "Globex", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "globex-1", IsActive: true, KubernetesDistribution: "k3s", KubernetesVersion: "v1.29.1+k3s2"},
+			}},
+			{ID: "initech", Name: "Initech", ChannelID: "beta", Instances: []models.Instance{
+				{ID: "initech-1", IsActive: true},
+			}},
+			{ID: "hooli", Name: "Hooli", ChannelID: "stable", IsArchived: true, Instances: []models.Instance{
+				{ID: "hooli-1", IsActive: true, KubernetesDistribution: "aks", KubernetesVersion: "1.30.0"},
+			}},
+		},
+	}
+}
+
+func TestAccount_Distributions(t *testing.T) {
+	report := platformAccount().Distributions("")
+
+	if report.Instances != 4 || report.Customers != 3 {
+		t.Errorf("Expected 4 active instances of 3 customers, got %d of %d", report.Instances, report.Customers)
+	}
+
+	expectedVersions := []PlatformCount{
+		{Name: "1.29", Instances: 2, Customers: 2, PercentInstances: 50},
+		{Name: "1.28", Instances: 1, Customers: 1, PercentInstances: 25},
+		{Name: "unknown", Instances: 1, Customers: 1, PercentInstances: 25},
+	}
+	if !reflect.DeepEqual(report.Versions, expectedVersions) {
+		t.Errorf("Versions = %+v, want %+v", report.Versions, expectedVersions)
+	}
+
+	expectedDistributions := []DistributionCount{
+		{
+			PlatformCount: PlatformCount{Name: "eks", Instances: 2, Customers: 1, PercentInstances: 50},
+			Versions: []PlatformCount{
+				{Name: "1.28", Instances: 1, Customers: 1, PercentInstances: 25},
+				{Name: "1.29", Instances: 1, Customers: 1, PercentInstances: 25},
+			},
+		},
+		{
+			PlatformCount: PlatformCount{Name: "k3s", Instances: 1, Customers: 1, PercentInstances: 25},
+			Versions:      []PlatformCount{{Name: "1.29", Instances: 1, Customers: 1, PercentInstances: 25}},
+		},
+		{
+			PlatformCount: PlatformCount{Name: "unknown", Instances: 1, Customers: 1, PercentInstances: 25},
+			Versions:      []PlatformCount{{Name: "unknown", Instances: 1, Customers: 1, PercentInstances: 25}},
+		},
+	}
+	if !reflect.DeepEqual(report.Distributions, expectedDistributions) {
+		t.Errorf("Distributions = %+v, want %+v", report.Distributions, expectedDistributions)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Failed to encode report: %v", err)
+	}
+	for _, identifying := range []string{"acme", "Acme", "globex", "initech"} {
+		if strings.Contains(string(data), identifying) {
+			t.Errorf("Expected the report to hold no customer details, found '%s' in %s", identifying, data)
+		}
+	}
+}
+
+func TestAccount_DistributionsForChannel(t *testing.T) {
+	report := platformAccount().Distributions("beta")
+	if report.Instances != 1 || len(report.Distributions) != 1 || report.Distributions[0].Name != unknownPlatform {
+		t.Errorf("Expected only the beta customer's unreported instance, got %+v", report)
+	}
+
+	empty := (&Account{}).Distributions("")
+	if empty.Instances != 0 || len(empty.Distributions) != 0 || len(empty.Versions) != 0 {
+		t.Errorf("Expected an empty report, got %+v", empty)
+	}
+}
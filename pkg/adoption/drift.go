@@ -0,0 +1,113 @@
+package adoption
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// VersionDrift lists the customers with active instances more than MaxLag sequences behind
+// their channel's latest release, furthest behind first. CustomersInstalled counts the
+// customers with active instances that were checked.
+type VersionDrift struct {
+	MaxLag             int64              `json:"max_lag"`
+	CustomersInstalled int                `json:"customers_installed"`
+	CustomersDrifting  int                `json:"customers_drifting"`
+	Customers          []DriftingCustomer `json:"customers"`
+}
+
+// DriftingCustomer is a customer with active instances behind its channel's latest release.
+// SequencesBehind is how far its furthest-behind instance lags.
+type DriftingCustomer struct {
+	CustomerID      string             `json:"customer_id"`
+	CustomerName    string             `json:"customer_name"`
+	ChannelID       string             `json:"channel_id"`
+	ChannelName     string             `json:"channel_name"`
+	LatestSequence  int64              `json:"latest_sequence"`
+	LatestVersion   string             `json:"latest_version,omitempty"`
+	SequencesBehind int64              `json:"sequences_behind"`
+	Instances       []DriftingInstance `json:"instances"`
+}
+
+// DriftingInstance is an active instance running a release behind its channel's latest
+type DriftingInstance struct {
+	InstanceID      string     `json:"instance_id"`
+	VersionLabel    string     `json:"version_label"`
+	ReleaseSequence int64      `json:"release_sequence"`
+	SequencesBehind int64      `json:"sequences_behind"`
+	LastCheckinAt   *time.Time `json:"last_checkin_at,omitempty"`
+}
+
+// VersionDrift finds the customers whose active instances run a release more than maxLag
+// sequences behind their channel's latest release, optionally limited to one channel's
+// customers. Channels without a release are skipped.
+func (a *Account) VersionDrift(channelID string, maxLag int64) VersionDrift {
+	drift := VersionDrift{MaxLag: maxLag, Customers: []DriftingCustomer{}}
+
+	channels := make(map[string]*models.Channel, len(a.Channels))
+	for i := range a.Channels {
+		channels[a.Channels[i].ID] = &a.Channels[i]
+	}
+
+	for i := range a.Customers {
+		customer := &a.Customers[i]
+		channel, ok := channels[customer.ChannelID]
+		if customer.IsArchived || !ok || channel.ReleaseSequence == 0 ||
+			(channelID != "" && customer.ChannelID != channelID) {
+			continue
+		}
+
+		instances := a.activeInstances(customer)
+		if len(instances) == 0 {
+			continue
+		}
+		drift.CustomersInstalled++
+
+		if entry, ok := a.driftingCustomer(customer, channel, instances, maxLag); ok {
+			drift.Customers = append(drift.Customers, entry)
+		}
+	}
+
+	slices.SortFunc(drift.Customers, func(a, b DriftingCustomer) int {
+		return cmp.Or(cmp.Compare(b.SequencesBehind, a.SequencesBehind), cmp.Compare(a.CustomerName, b.CustomerName))
+	})
+	drift.CustomersDrifting = len(drift.Customers)
+	return drift
+}
+
+// driftingCustomer returns a customer's instances more than maxLag sequences behind its
+// channel's latest release, furthest behind first, reporting whether there are any
+func (a *Account) driftingCustomer(
+	customer *models.Customer, channel *models.Channel, instances []models.Instance, maxLag int64,
+) (DriftingCustomer, bool) {
+	entry := DriftingCustomer{
+		CustomerID:     customer.ID,
+		CustomerName:   customer.Name,
+		ChannelID:      channel.ID,
+		ChannelName:    channel.Name,
+		LatestSequence: channel.ReleaseSequence,
+		LatestVersion:  a.versionLabel(channel.ReleaseSequence, ""),
+	}
+
+	for _, instance := range instances {
+		behind := channel.ReleaseSequence - instance.ReleaseSequence
+		if behind <= maxLag {
+			continue
+		}
+		entry.Instances = append(entry.Instances, DriftingInstance{
+			InstanceID:      instance.ID,
+			VersionLabel:    a.versionLabel(instance.ReleaseSequence, instance.VersionLabel),
+			ReleaseSequence: instance.ReleaseSequence,
+			SequencesBehind: behind,
+			LastCheckinAt:   instance.LastCheckinAt,
+		})
+		entry.SequencesBehind = max(entry.SequencesBehind, behind)
+	}
+
+	slices.SortFunc(entry.Instances, func(a, b DriftingInstance) int {
+		return cmp.Or(cmp.Compare(b.SequencesBehind, a.SequencesBehind), cmp.Compare(a.InstanceID, b.InstanceID))
+	})
+	return entry, len(entry.Instances) > 0
+}
@@ -0,0 +1,75 @@
+package adoption
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAccount_VersionDrift(t *testing.T) {
+	tests := []struct {
+		name          string
+		channelID     string
+		maxLag        int64
+		wantInstalled int
+		wantCustomers []string
+		wantInstances map[string][]string
+	}{
+		{
+			name:          "anything behind",
+			wantInstalled: 3,
+			wantCustomers: []string{"Globex"},
+			wantInstances: map[string][]string{"Globex": {"globex-2"}},
+		},
+		{
+			name:          "more than one sequence behind",
+			maxLag:        1,
+			wantInstalled: 3,
+			wantCustomers: []string{},
+		},
+		{
+			name:          "channel with every customer current",
+			channelID:     "beta",
+			wantInstalled: 1,
+			wantCustomers: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := testAccount().VersionDrift(tt.channelID, tt.maxLag)
+
+			if drift.CustomersInstalled != tt.wantInstalled {
+				t.Errorf("Expected %d customers installed, got %d", tt.wantInstalled, drift.CustomersInstalled)
+			}
+			names := []string{}
+			for _, customer := range drift.Customers {
+				names = append(names, customer.CustomerName)
+
+				var instances []string
+				for _, instance := range customer.Instances {
+					instances = append(instances, instance.InstanceID)
+				}
+				if !slices.Equal(instances, tt.wantInstances[customer.CustomerName]) {
+					t.Errorf("Expected drifting instances %v for %s, got %v",
+						tt.wantInstances[customer.CustomerName], customer.CustomerName, instances)
+				}
+			}
+			if !slices.Equal(names, tt.wantCustomers) || drift.CustomersDrifting != len(tt.wantCustomers) {
+				t.Errorf("Expected drifting customers %v, got %v (%d)", tt.wantCustomers, names, drift.CustomersDrifting)
+			}
+		})
+	}
+
+	// Customers are ordered by how far behind their furthest instance is
+	account := testAccount()
+	account.Channels[0].ReleaseSequence = 4
+	drift := account.VersionDrift("stable", 0)
+	if len(drift.Customers) != 2 || drift.Customers[0].CustomerName != "Globex" ||
+		drift.Customers[0].SequencesBehind != 2 || drift.Customers[1].SequencesBehind != 1 {
+		t.Fatalf("Expected Globex (2 behind) then Acme (1 behind), got %+v", drift.Customers)
+	}
+	globex := drift.Customers[0]
+	if globex.LatestVersion != "1.3.0-beta.1" || globex.Instances[0].VersionLabel != "1.1.0" {
+		t.Errorf("Expected version labels from the release listing, got %+v", globex)
+	}
+}
@@ -0,0 +1,176 @@
+package adoption
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// MaxFunnelBuckets bounds how many time buckets a release funnel reports; the last bucket
+// of an older release extends to the present
+const MaxFunnelBuckets = 60
+
+// ReleasePromotion records when a release was promoted to a channel
+type ReleasePromotion struct {
+	ChannelID   string    `json:"channel_id"`
+	ChannelName string    `json:"channel_name"`
+	PromotedAt  time.Time `json:"promoted_at"`
+}
+
+// ReleaseFunnel reports how a release was adopted after it was promoted: how many of the
+// active instances on the channels it was promoted to have upgraded to it or later, when
+// they upgraded, and which customers have not. UpgradedUndated counts upgraded instances
+// whose version history does not say when, so they are left out of the buckets.
+type ReleaseFunnel struct {
+	ReleaseSequence   int64              `json:"release_sequence"`
+	VersionLabel      string             `json:"version_label,omitempty"`
+	Promotions        []ReleasePromotion `json:"promotions"`
+	Instances         int                `json:"instances"`
+	InstancesUpgraded int                `json:"instances_upgraded"`
+	PercentUpgraded   float64            `json:"percent_upgraded"`
+	UpgradedUndated   int                `json:"upgraded_undated"`
+	Buckets           []UpgradeBucket    `json:"buckets"`
+	Stragglers        []Straggler        `json:"stragglers"`
+}
+
+// UpgradeBucket counts the instances that upgraded within a period after the release was
+// first promoted. Cumulative counts and percentages include every earlier bucket.
+type UpgradeBucket struct {
+	Start                     time.Time `json:"start"`
+	End                       time.Time `json:"end"`
+	Upgraded                  int       `json:"upgraded"`
+	CumulativeUpgraded        int       `json:"cumulative_upgraded"`
+	PercentCumulativeUpgraded float64   `json:"percent_cumulative_upgraded"`
+}
+
+// Straggler is a customer with active instances still running an older release than one
+// promoted to its channel
+type Straggler struct {
+	CustomerID         string `json:"customer_id"`
+	CustomerName       string `json:"customer_name"`
+	ChannelID          string `json:"channel_id"`
+	InstancesBehind    int    `json:"instances_behind"`
+	OldestSequence     int64  `json:"oldest_sequence"`
+	OldestVersion      string `json:"oldest_version,omitempty"`
+	DaysSincePromotion int    `json:"days_since_promotion"`
+}
+
+// ReleaseFunnel computes the adoption funnel of a release from its promotions, counting the
+// active instances of customers on the promoted channels. Upgrades are counted in buckets of
+// the given width from the first promotion until now.
+func (a *Account) ReleaseFunnel(
+	sequence int64,
+	promotions []ReleasePromotion,
+	bucket time.Duration,
+	now time.Time,
+) ReleaseFunnel {
+	funnel := ReleaseFunnel{
+		ReleaseSequence: sequence,
+		VersionLabel:    a.versionLabel(sequence, ""),
+		Promotions:      promotions,
+		Buckets:         []UpgradeBucket{},
+		Stragglers:      []Straggler{},
+	}
+	if len(promotions) == 0 {
+		return funnel
+	}
+
+	promotedAt := make(map[string]time.Time, len(promotions))
+	first := promotions[0].PromotedAt
+	for _, promotion := range promotions {
+		promotedAt[promotion.ChannelID] = promotion.PromotedAt
+		if promotion.PromotedAt.Before(first) {
+			first = promotion.PromotedAt
+		}
+	}
+	funnel.Buckets = upgradeBuckets(first, now, bucket)
+
+	for i := range a.Customers {
+		customer := &a.Customers[i]
+		promoted, ok := promotedAt[customer.ChannelID]
+		if customer.IsArchived || !ok {
+			continue
+		}
+
+		straggler := Straggler{
+			CustomerID:         customer.ID,
+			CustomerName:       customer.Name,
+			ChannelID:          customer.ChannelID,
+			DaysSincePromotion: int(now.Sub(promoted) / timerange.Day),
+		}
+		for _, instance := range a.activeInstances(customer) {
+			funnel.Instances++
+			if instance.ReleaseSequence < sequence {
+				if straggler.InstancesBehind == 0 || instance.ReleaseSequence < straggler.OldestSequence {
+					straggler.OldestSequence = instance.ReleaseSequence
+					straggler.OldestVersion = a.versionLabel(instance.ReleaseSequence, instance.VersionLabel)
+				}
+				straggler.InstancesBehind++
+				continue
+			}
+
+			funnel.InstancesUpgraded++
+			reached := instance.ReachedAt(sequence)
+			if reached == nil {
+				funnel.UpgradedUndated++
+				continue
+			}
+			funnel.Buckets[bucketIndex(funnel.Buckets, *reached)].Upgraded++
+		}
+		if straggler.InstancesBehind > 0 {
+			funnel.Stragglers = append(funnel.Stragglers, straggler)
+		}
+	}
+
+	funnel.PercentUpgraded = percent(funnel.InstancesUpgraded, funnel.Instances)
+	cumulative := 0
+	for i := range funnel.Buckets {
+		cumulative += funnel.Buckets[i].Upgraded
+		funnel.Buckets[i].CumulativeUpgraded = cumulative
+		funnel.Buckets[i].PercentCumulativeUpgraded = percent(cumulative, funnel.Instances)
+	}
+	slices.SortFunc(funnel.Stragglers, func(a, b Straggler) int {
+		if c := cmp.Compare(b.InstancesBehind, a.InstancesBehind); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.OldestSequence, b.OldestSequence); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.CustomerName, b.CustomerName)
+	})
+	return funnel
+}
+
+// upgradeBuckets divides the time from start until now into buckets of the given width, at
+// most MaxFunnelBuckets of them. The last bucket ends now, however wide that makes it.
+func upgradeBuckets(start, now time.Time, width time.Duration) []UpgradeBucket {
+	count := 1
+	if now.After(start) {
+		count = int((now.Sub(start) + width - 1) / width)
+	}
+	count = min(max(count, 1), MaxFunnelBuckets)
+
+	buckets := make([]UpgradeBucket, count)
+	for i := range buckets {
+		buckets[i].Start = start.Add(time.Duration(i) * width)
+		buckets[i].End = buckets[i].Start.Add(width)
+	}
+	if last := &buckets[count-1]; now.After(last.Start) {
+		last.End = now
+	}
+	return buckets
+}
+
+// bucketIndex returns the bucket an upgrade falls in. Upgrades before the first promotion,
+// such as an instance that installed the release from another channel, count in the first
+// bucket, and upgrades after the last bucket count in the last.
+func bucketIndex(buckets []UpgradeBucket, at time.Time) int {
+	for i := range buckets {
+		if at.Before(buckets[i].End) {
+			return i
+		}
+	}
+	return len(buckets) - 1
+}
@@ -0,0 +1,112 @@
+package adoption
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// funnelDay returns midnight UTC on a day of March 2025
+func funnelDay(day int) time.Time {
+	return time.Date(2025, time.March, day, 0, 0, 0, 0, time.UTC)
+}
+
+// funnelAccount has release 5 promoted to Stable on March 1 and Beta on March 3. Acme upgraded
+// one instance on March 2 and one on March 9; Globex upgraded without a version history; Initech
+// is still on release 3 and 4.
+func funnelAccount() *Account {
+	history := func(day int) []models.InstanceVersion {
+		return []models.InstanceVersion{
+			{ReleaseSequence: 4, InstalledAt: funnelDay(1).AddDate(0, -1, 0)},
+			{ReleaseSequence: 5, InstalledAt: funnelDay(day)},
+		}
+	}
+	return &Account{
+		Releases: []models.Release{{Sequence: 3, Version: "1.0.0"}, {Sequence: 5, Version: "1.2.0"}},
+		Customers: []models.Customer{
+			{ID: "acme", Name: "Acme", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "acme-1", IsActive: true, ReleaseSequence: 5, VersionHistory: history(2)},
+				{ID: "acme-2", IsActive: true, ReleaseSequence: 5, VersionHistory: history(9)},
+			}},
+			{ID: "globex", Name: "Globex", ChannelID: "beta", Instances: []models.Instance{
+				{ID: "globex-1", IsActive: true, ReleaseSequence: 6},
+			}},
+			{ID: "initech", Name: "Initech", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "initech-1", IsActive: true, ReleaseSequence: 4, VersionLabel: "1.1.0"},
+				{ID: "initech-2", IsActive: true, ReleaseSequence: 3},
+				{ID: "initech-3", ReleaseSequence: 1},
+			}},
+			{ID: "hooli", Name: "Hooli", ChannelID: "unstable", Instances: []models.Instance{
+				{ID: "hooli-1", IsActive: true, ReleaseSequence: 2},
+			}},
+		},
+	}
+}
+
+func TestAccount_ReleaseFunnel(t *testing.T) {
+	promotions := []ReleasePromotion{
+		{ChannelID: "stable", ChannelName: "Stable", PromotedAt: funnelDay(1)},
+		{ChannelID: "beta", ChannelName: "Beta", PromotedAt: funnelDay(3)},
+	}
+	funnel := funnelAccount().ReleaseFunnel(5, promotions, timerange.Week, funnelDay(11))
+
+	if funnel.VersionLabel != "1.2.0" || funnel.Instances != 5 || funnel.InstancesUpgraded != 3 ||
+		funnel.UpgradedUndated != 1 || funnel.PercentUpgraded != 60 {
+		t.Errorf("Unexpected funnel totals: %+v", funnel)
+	}
+
+	expectedBuckets := []UpgradeBucket{
+		{Start: funnelDay(1), End: funnelDay(8), Upgraded: 1, CumulativeUpgraded: 1, PercentCumulativeUpgraded: 20},
+		{Start: funnelDay(8), End: funnelDay(11), Upgraded: 1, CumulativeUpgraded: 2, PercentCumulativeUpgraded: 40},
+	}
+	if !reflect.DeepEqual(funnel.Buckets, expectedBuckets) {
+		t.Errorf("Buckets = %+v, want %+v", funnel.Buckets, expectedBuckets)
+	}
+
+	expectedStragglers := []Straggler{{
+		CustomerID: "initech", CustomerName: "Initech", ChannelID: "stable",
+		InstancesBehind: 2, OldestSequence: 3, OldestVersion: "1.0.0", DaysSincePromotion: 10,
+	}}
+	if !reflect.DeepEqual(funnel.Stragglers, expectedStragglers) {
+		t.Errorf("Stragglers = %+v, want %+v", funnel.Stragglers, expectedStragglers)
+	}
+}
+
+func TestAccount_ReleaseFunnelNotPromoted(t *testing.T) {
+	funnel := funnelAccount().ReleaseFunnel(5, nil, time.Hour, funnelDay(11))
+
+	if funnel.Instances != 0 || len(funnel.Buckets) != 0 || len(funnel.Stragglers) != 0 {
+		t.Errorf("Expected an empty funnel for a release that was never promoted, got %+v", funnel)
+	}
+}
+
+func TestUpgradeBuckets(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     time.Time
+		now       time.Time
+		width     time.Duration
+		wantCount int
+		wantEnd   time.Time
+	}{
+		{name: "partial last bucket", start: funnelDay(1), now: funnelDay(3).Add(time.Hour),
+			width: timerange.Day, wantCount: 3, wantEnd: funnelDay(3).Add(time.Hour)},
+		{name: "promoted just now", start: funnelDay(1), now: funnelDay(1),
+			width: timerange.Day, wantCount: 1, wantEnd: funnelDay(2)},
+		{name: "capped", start: funnelDay(1), now: funnelDay(1).Add(100 * time.Hour),
+			width: time.Hour, wantCount: MaxFunnelBuckets, wantEnd: funnelDay(1).Add(100 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := upgradeBuckets(tt.start, tt.now, tt.width)
+			if len(buckets) != tt.wantCount || !buckets[len(buckets)-1].End.Equal(tt.wantEnd) {
+				t.Errorf("Expected %d buckets ending %v, got %d ending %v",
+					tt.wantCount, tt.wantEnd, len(buckets), buckets[len(buckets)-1].End)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package adoption
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// MaxTimeseriesDays bounds how many days an adoption time series reports
+const MaxTimeseriesDays = 366
+
+// dateLayout is the layout of the days of a time series
+const dateLayout = "2006-01-02"
+
+// AdoptionTimeseries reports, for each day of a window, how many of a channel's instances ran
+// each release at the end of the day. Instances count from the first release they installed
+// until their last check-in, so instances that have since stopped checking in still count on
+// the days they ran. InstancesUndated counts instances left out because their version history
+// or last check-in does not say when they ran what.
+type AdoptionTimeseries struct {
+	ChannelID        string        `json:"channel_id"`
+	ChannelName      string        `json:"channel_name"`
+	Instances        int           `json:"instances"`
+	InstancesUndated int           `json:"instances_undated"`
+	Days             []AdoptionDay `json:"days"`
+}
+
+// AdoptionDay counts the instances running each release at the end of a day, newest release
+// first. Percentages are of the instances running any release that day.
+type AdoptionDay struct {
+	Date      string        `json:"date"`
+	Instances int           `json:"instances"`
+	Versions  []DayAdoption `json:"versions"`
+	counts    map[int64]int
+}
+
+// DayAdoption counts the instances running a release at the end of a day
+type DayAdoption struct {
+	ReleaseSequence int64   `json:"release_sequence"`
+	VersionLabel    string  `json:"version_label,omitempty"`
+	Instances       int     `json:"instances"`
+	PercentOfDay    float64 `json:"percent_of_day"`
+}
+
+// Timeseries computes the daily adoption of releases on a channel over the days, in UTC,
+// from the start of the window until its end or now, whichever is earlier. Unlike the other
+// reports it counts every instance of the channel's customers, active or not, over the days
+// it was checking in, so CheckedIn does not apply.
+func (a *Account) Timeseries(channel *models.Channel, window timerange.Range, now time.Time) AdoptionTimeseries {
+	series := AdoptionTimeseries{
+		ChannelID:   channel.ID,
+		ChannelName: channel.Name,
+		Days:        seriesDays(window, now),
+	}
+
+	labels := map[int64]string{}
+	for i := range a.Customers {
+		customer := &a.Customers[i]
+		if customer.IsArchived || customer.ChannelID != channel.ID {
+			continue
+		}
+
+		for j := range customer.Instances {
+			instance := &customer.Instances[j]
+			lastSeen := now
+			if instance.LastCheckinAt != nil {
+				lastSeen = *instance.LastCheckinAt
+			}
+			undated := instance.LastCheckinAt == nil && !instance.IsActive
+			if undated || !slices.ContainsFunc(instance.VersionHistory, dated) {
+				series.InstancesUndated++
+				continue
+			}
+
+			series.Instances++
+			for k := range series.Days {
+				day := &series.Days[k]
+				start, end := dayBounds(day.Date, now)
+				if lastSeen.Before(start) {
+					continue
+				}
+				version := runningAt(instance, end)
+				if version == nil {
+					continue
+				}
+				labels[version.ReleaseSequence] = a.versionLabel(version.ReleaseSequence, version.VersionLabel)
+				day.count(version.ReleaseSequence)
+			}
+		}
+	}
+
+	for i := range series.Days {
+		series.Days[i].summarize(labels)
+	}
+	return series
+}
+
+// seriesDays returns the empty days of the window, at most MaxTimeseriesDays of them ending
+// with the window's last day. A window without a start begins MaxTimeseriesDays before its
+// end, and one without an end, or ending after now, ends now.
+func seriesDays(window timerange.Range, now time.Time) []AdoptionDay {
+	end := now
+	if !window.End.IsZero() && window.End.Before(now) {
+		end = window.End
+	}
+	first := end.UTC().Add(-time.Nanosecond).Truncate(timerange.Day)
+	start := first.Add(-(MaxTimeseriesDays - 1) * timerange.Day)
+	if !window.Start.IsZero() && window.Start.After(start) {
+		start = window.Start.UTC().Truncate(timerange.Day)
+	}
+
+	days := []AdoptionDay{}
+	for day := start; !day.After(first); day = day.Add(timerange.Day) {
+		days = append(days, AdoptionDay{Date: day.Format(dateLayout), Versions: []DayAdoption{}})
+	}
+	return days
+}
+
+// dayBounds returns when a day starts and when it ends, or now for a day still under way
+func dayBounds(date string, now time.Time) (start, end time.Time) {
+	start, _ = time.Parse(dateLayout, date)
+	end = start.Add(timerange.Day)
+	if now.Before(end) {
+		end = now
+	}
+	return start, end
+}
+
+// dated reports whether a version history entry says when the release was installed
+func dated(version models.InstanceVersion) bool {
+	return !version.InstalledAt.IsZero()
+}
+
+// runningAt returns the release an instance was running at a time, the one it most recently
+// installed before then, or nil if it had not installed any yet
+func runningAt(instance *models.Instance, at time.Time) *models.InstanceVersion {
+	var running *models.InstanceVersion
+	for i := range instance.VersionHistory {
+		version := &instance.VersionHistory[i]
+		if version.InstalledAt.IsZero() || !version.InstalledAt.Before(at) {
+			continue
+		}
+		if running == nil || !version.InstalledAt.Before(running.InstalledAt) {
+			running = version
+		}
+	}
+	return running
+}
+
+// count counts an instance running a release at the end of the day
+func (d *AdoptionDay) count(sequence int64) {
+	if d.counts == nil {
+		d.counts = map[int64]int{}
+	}
+	d.counts[sequence]++
+	d.Instances++
+}
+
+// summarize lists the day's counts, newest release first
+func (d *AdoptionDay) summarize(labels map[int64]string) {
+	for sequence, count := range d.counts {
+		d.Versions = append(d.Versions, DayAdoption{
+			ReleaseSequence: sequence,
+			VersionLabel:    labels[sequence],
+			Instances:       count,
+			PercentOfDay:    percent(count, d.Instances),
+		})
+	}
+	slices.SortFunc(d.Versions, func(a, b DayAdoption) int {
+		return cmp.Compare(b.ReleaseSequence, a.ReleaseSequence)
+	})
+	d.counts = nil
+}
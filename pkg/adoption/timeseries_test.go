@@ -0,0 +1,119 @@
+package adoption
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// timeseriesAccount has Acme upgrade from release 4 to 5 on March 2, Globex install release 4
+// on March 3 and stop checking in on March 3, and Initech run an instance without a version
+// history. Hooli is on another channel and Soylent is archived.
+func timeseriesAccount() *Account {
+	stopped := funnelDay(3).Add(12 * time.Hour)
+	return &Account{
+		Releases: []models.Release{{Sequence: 4, Version: "1.1.0"}, {Sequence: 5, Version: "1.2.0"}},
+		Customers: []models.Customer{
+			{ID: "acme", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "acme-1", IsActive: true, VersionHistory: []models.InstanceVersion{
+					{ReleaseSequence: 4, InstalledAt: funnelDay(1).AddDate(0, -1, 0)},
+					{ReleaseSequence: 5, InstalledAt: funnelDay(2).Add(6 * time.Hour)},
+				}},
+			}},
+			{ID: "globex", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "globex-1", LastCheckinAt: &stopped, VersionHistory: []models.InstanceVersion{
+					{ReleaseSequence: 4, InstalledAt: funnelDay(3).Add(time.Hour)},
+				}},
+			}},
+			{ID: "initech", ChannelID: "stable", Instances: []models.Instance{
+				{ID: "initech-1", IsActive: true, ReleaseSequence: 5},
+			}},
+			{ID: "hooli", ChannelID: "beta", Instances: []models.Instance{
+				{ID: "hooli-1", IsActive: true, VersionHistory: []models.InstanceVersion{
+					{ReleaseSequence: 5, InstalledAt: funnelDay(1)},
+				}},
+			}},
+			{ID: "soylent", ChannelID: "stable", IsArchived: true, Instances: []models.Instance{
+				{ID: "soylent-1", IsActive: true, VersionHistory: []models.InstanceVersion{
+					{ReleaseSequence: 5, InstalledAt: funnelDay(1)},
+				}},
+			}},
+		},
+	}
+}
+
+func TestAccount_Timeseries(t *testing.T) {
+	channel := &models.Channel{ID: "stable", Name: "Stable"}
+	window := timerange.Range{Start: funnelDay(1), End: funnelDay(10)}
+	series := timeseriesAccount().Timeseries(channel, window, funnelDay(4).Add(12*time.Hour))
+
+	if series.ChannelName != "Stable" || series.Instances != 2 || series.InstancesUndated != 1 {
+		t.Errorf("Unexpected series totals: %+v", series)
+	}
+
+	want := []AdoptionDay{
+		{Date: "2025-03-01", Instances: 1, Versions: []DayAdoption{
+			{ReleaseSequence: 4, VersionLabel: "1.1.0", Instances: 1, PercentOfDay: 100},
+		}},
+		{Date: "2025-03-02", Instances: 1, Versions: []DayAdoption{
+			{ReleaseSequence: 5, VersionLabel: "1.2.0", Instances: 1, PercentOfDay: 100},
+		}},
+		{Date: "2025-03-03", Instances: 2, Versions: []DayAdoption{
+			{ReleaseSequence: 5, VersionLabel: "1.2.0", Instances: 1, PercentOfDay: 50},
+			{ReleaseSequence: 4, VersionLabel: "1.1.0", Instances: 1, PercentOfDay: 50},
+		}},
+		{Date: "2025-03-04", Instances: 1, Versions: []DayAdoption{
+			{ReleaseSequence: 5, VersionLabel: "1.2.0", Instances: 1, PercentOfDay: 100},
+		}},
+	}
+	if !reflect.DeepEqual(series.Days, want) {
+		t.Errorf("Expected days %+v, got %+v", want, series.Days)
+	}
+}
+
+func TestSeriesDays(t *testing.T) {
+	now := funnelDay(10).Add(8 * time.Hour)
+
+	tests := []struct {
+		name      string
+		window    timerange.Range
+		wantFirst string
+		wantLast  string
+		wantDays  int
+	}{
+		{
+			name:      "closed window",
+			window:    timerange.Range{Start: funnelDay(1), End: funnelDay(4)},
+			wantFirst: "2025-03-01",
+			wantLast:  "2025-03-03",
+			wantDays:  3,
+		},
+		{
+			name:      "window ending after now",
+			window:    timerange.Range{Start: funnelDay(8).Add(time.Hour), End: funnelDay(20)},
+			wantFirst: "2025-03-08",
+			wantLast:  "2025-03-10",
+			wantDays:  3,
+		},
+		{
+			name:      "window without a start",
+			window:    timerange.Range{End: funnelDay(4)},
+			wantFirst: "2024-03-03",
+			wantLast:  "2025-03-03",
+			wantDays:  MaxTimeseriesDays,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := seriesDays(tt.window, now)
+			if len(days) != tt.wantDays || days[0].Date != tt.wantFirst || days[len(days)-1].Date != tt.wantLast {
+				t.Errorf("Expected %d days from %s to %s, got %d from %s to %s", tt.wantDays, tt.wantFirst,
+					tt.wantLast, len(days), days[0].Date, days[len(days)-1].Date)
+			}
+		})
+	}
+}
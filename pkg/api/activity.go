@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// ActivityList represents entries of an application's activity feed
+type ActivityList struct {
+	Activity []models.Activity `json:"activity"`
+}
+
+// ActivityFilter selects entries of an application's activity feed. Since and Until bound
+// when the change was made, Until exclusive, and a zero time leaves that side open. Actor
+// selects the entries whose actor contains it, ignoring case. Empty fields match every entry.
+type ActivityFilter struct {
+	Since time.Time
+	Until time.Time
+	Actor string
+}
+
+// Matches reports whether an activity feed entry passes every filter
+func (f ActivityFilter) Matches(activity *models.Activity) bool {
+	switch {
+	case !f.Since.IsZero() && activity.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !activity.CreatedAt.Before(f.Until):
+		return false
+	case f.Actor != "" && !models.MatchesQuery(f.Actor, activity.Actor):
+		return false
+	}
+	return true
+}
+
+// query returns the time bounds as the activity endpoint's query parameters
+func (f ActivityFilter) query() url.Values {
+	params := url.Values{}
+	if !f.Since.IsZero() {
+		params.Set("since", f.Since.UTC().Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		params.Set("until", f.Until.UTC().Format(time.RFC3339))
+	}
+	return params
+}
+
+// ListActivity retrieves the entries of an application's activity feed that pass the filter.
+// The time bounds are sent to the API, and the whole filter is applied to the entries it
+// returns, so part of an actor's email address or token name is enough to select them.
+func (s *ApplicationService) ListActivity(
+	ctx context.Context, appID string, filter ActivityFilter,
+) (*ActivityList, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s/activity", url.PathEscape(appID))
+	if params := filter.query(); len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	s.client.logger.DebugContext(ctx, "Listing application activity",
+		"app_id", appID,
+		"actor", filter.Actor)
+
+	var response ActivityList
+	if err := s.client.getJSON(ctx, path, &response); err != nil {
+		return nil, fmt.Errorf("failed to list activity: %w", err)
+	}
+
+	result := ActivityList{Activity: []models.Activity{}}
+	for i := range response.Activity {
+		if filter.Matches(&response.Activity[i]) {
+			result.Activity = append(result.Activity, response.Activity[i])
+		}
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed application activity",
+		"app_id", appID,
+		"total_entries", len(response.Activity),
+		"filtered_count", len(result.Activity))
+
+	return &result, nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApplicationService_ListActivity(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/activity" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		gotQuery = r.URL.RawQuery
+		fmt.Fprint(w, `{"activity": [
+			{"id": "activity-1", "actor": "ana@example.com", "action": "release.promoted",
+			 "createdAt": "2025-01-10T12:00:00Z"},
+			{"id": "activity-2", "actor": "Bo@Example.com", "action": "customer.updated",
+			 "createdAt": "2025-01-20T12:00:00Z"},
+			{"id": "activity-3", "actor": "ci-token", "action": "release.created", "createdAt": "2025-02-01T12:00:00Z"}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewApplicationService(client)
+
+	tests := []struct {
+		name        string
+		appID       string
+		filter      ActivityFilter
+		expectError bool
+		wantIDs     []string
+		wantQuery   string
+	}{
+		{
+			name:    "whole feed",
+			appID:   "app-1",
+			wantIDs: []string{"activity-1", "activity-2", "activity-3"},
+		},
+		{
+			name:  "time range",
+			appID: "app-1",
+			filter: ActivityFilter{
+				Since: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+			},
+			wantIDs:   []string{"activity-2"},
+			wantQuery: "since=2025-01-15T00%3A00%3A00Z&until=2025-02-01T12%3A00%3A00Z",
+		},
+		{
+			name:    "actor ignoring case",
+			appID:   "app-1",
+			filter:  ActivityFilter{Actor: "bo@example"},
+			wantIDs: []string{"activity-2"},
+		},
+		{name: "unknown application", appID: "app-9", expectError: true},
+		{name: "missing application ID", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery = ""
+			result, err := service.ListActivity(context.Background(), tt.appID, tt.filter)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ids := []string{}
+			for _, activity := range result.Activity {
+				ids = append(ids, activity.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected activity %v, got %v", tt.wantIDs, ids)
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("Expected query '%s', got '%s'", tt.wantQuery, gotQuery)
+			}
+		})
+	}
+}
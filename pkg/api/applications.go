@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/crdant/replicated-mcp-server/pkg/models"
 )
@@ -71,15 +72,16 @@ func (s *ApplicationService) ListApplications(
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var result ApplicationList
-	if err := json.Unmarshal(body, &result); err != nil {
+	var envelope appsResponse
+	if err := json.Unmarshal(body, &envelope); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	result := envelope.list()
 
 	s.client.logger.DebugContext(ctx, "Successfully listed applications",
 		"count", len(result.Applications))
 
-	return &result, nil
+	return result, nil
 }
 
 // GetApplication retrieves a specific application by ID
@@ -140,14 +142,11 @@ func (s *ApplicationService) SearchApplications(
 
 	// Filter applications client-side based on query
 	var filteredApps []models.Application
-	queryLower := strings.ToLower(strings.TrimSpace(query))
 
 	for i := range allApps.Applications {
 		app := &allApps.Applications[i]
-		// Search in name, slug, and description (case-insensitive)
-		if strings.Contains(strings.ToLower(app.Name), queryLower) ||
-			strings.Contains(strings.ToLower(app.Slug), queryLower) ||
-			strings.Contains(strings.ToLower(app.Description), queryLower) {
+		// Search in name, slug, and description (ignoring case and diacritics)
+		if models.MatchesQuery(query, app.Name, app.Slug, app.Description) {
 			filteredApps = append(filteredApps, *app)
 		}
 	}
@@ -163,3 +162,56 @@ func (s *ApplicationService) SearchApplications(
 
 	return result, nil
 }
+
+// createApplicationRequest is the request body for creating an application. The Vendor
+// Portal derives the slug from the name.
+type createApplicationRequest struct {
+	Name string `json:"name"`
+}
+
+// Create creates an application with the given name, returning it as stored by the API.
+// The name must give a valid slug, since the Vendor Portal derives the slug from it.
+func (s *ApplicationService) Create(ctx context.Context, name string) (*models.Application, error) {
+	name = models.NormalizeName(name)
+	if name == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+	if utf8.RuneCountInString(name) > models.MaxNameLength {
+		return nil, fmt.Errorf("application name must be %d characters or less", models.MaxNameLength)
+	}
+	if err := models.ValidateSlug(models.SlugFromName(name)); err != nil {
+		return nil, fmt.Errorf("application name '%s' does not give a valid slug: %w", name, err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Creating application", "app_name", name)
+
+	var envelope appResponse
+	if err := s.client.postJSON(ctx, "/vendor/v3/app", createApplicationRequest{Name: name}, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to create application: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully created application",
+		"app_id", envelope.App.ID,
+		"app_slug", envelope.App.Slug)
+
+	return &envelope.App, nil
+}
+
+// Archive removes an application from the team's applications in the Vendor Portal
+func (s *ApplicationService) Archive(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("application ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s", url.PathEscape(id))
+
+	s.client.logger.DebugContext(ctx, "Archiving application", "app_id", id)
+
+	if err := s.client.deleteJSON(ctx, path, nil); err != nil {
+		return fmt.Errorf("failed to archive application: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully archived application", "app_id", id)
+
+	return nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -35,23 +36,23 @@ func TestApplicationService_ListApplications(t *testing.T) {
 						"id": "app-1",
 						"name": "Test App 1",
 						"slug": "test-app-1",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Test application 1",
-						"is_active": true
+						"isActive": true
 					},
 					{
 						"id": "app-2",
 						"name": "Test App 2",
 						"slug": "test-app-2",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Test application 2",
-						"is_active": true
+						"isActive": true
 					}
 				]
 			}`,
@@ -69,12 +70,12 @@ func TestApplicationService_ListApplications(t *testing.T) {
 						"id": "app-1",
 						"name": "Test App 1",
 						"slug": "test-app-1",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Test application 1",
-						"is_active": true
+						"isActive": true
 					}
 				]
 			}`,
@@ -201,12 +202,12 @@ func TestApplicationService_GetApplication(t *testing.T) {
 				"id": "app-1",
 				"name": "Test App 1",
 				"slug": "test-app-1",
-				"team_id": "team-1",
-				"team_name": "Test Team",
-				"created_at": "2023-01-01T00:00:00Z",
-				"updated_at": "2023-01-01T00:00:00Z",
+				"teamId": "team-1",
+				"teamName": "Test Team",
+				"createdAt": "2023-01-01T00:00:00Z",
+				"updatedAt": "2023-01-01T00:00:00Z",
 				"description": "Test application 1",
-				"is_active": true
+				"isActive": true
 			}`,
 			mockStatus:   http.StatusOK,
 			expectError:  false,
@@ -349,23 +350,23 @@ func TestApplicationService_SearchApplications(t *testing.T) {
 						"id": "app-1",
 						"name": "Test App 1",
 						"slug": "test-app-1",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Test application 1",
-						"is_active": true
+						"isActive": true
 					},
 					{
 						"id": "app-2",
 						"name": "Different App",
 						"slug": "different-app",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Different application",
-						"is_active": true
+						"isActive": true
 					}
 				]
 			}`,
@@ -383,23 +384,23 @@ func TestApplicationService_SearchApplications(t *testing.T) {
 						"id": "app-1",
 						"name": "Test App 1",
 						"slug": "test-app-1",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Test application 1",
-						"is_active": true
+						"isActive": true
 					},
 					{
 						"id": "app-2",
 						"name": "Some App",
 						"slug": "different-app",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Some application",
-						"is_active": true
+						"isActive": true
 					}
 				]
 			}`,
@@ -417,23 +418,23 @@ func TestApplicationService_SearchApplications(t *testing.T) {
 						"id": "app-1",
 						"name": "Test App 1",
 						"slug": "test-app-1",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "App with special feature",
-						"is_active": true
+						"isActive": true
 					},
 					{
 						"id": "app-2",
 						"name": "Different App",
 						"slug": "different-app",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Standard application",
-						"is_active": true
+						"isActive": true
 					}
 				]
 			}`,
@@ -451,12 +452,12 @@ func TestApplicationService_SearchApplications(t *testing.T) {
 						"id": "app-1",
 						"name": "Test App 1",
 						"slug": "test-app-1",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Test application 1",
-						"is_active": true
+						"isActive": true
 					}
 				]
 			}`,
@@ -474,12 +475,12 @@ func TestApplicationService_SearchApplications(t *testing.T) {
 						"id": "app-1",
 						"name": "Test App 1",
 						"slug": "test-app-1",
-						"team_id": "team-1",
-						"team_name": "Test Team",
-						"created_at": "2023-01-01T00:00:00Z",
-						"updated_at": "2023-01-01T00:00:00Z",
+						"teamId": "team-1",
+						"teamName": "Test Team",
+						"createdAt": "2023-01-01T00:00:00Z",
+						"updatedAt": "2023-01-01T00:00:00Z",
 						"description": "Test application 1",
-						"is_active": true
+						"isActive": true
 					}
 				]
 			}`,
@@ -565,3 +566,62 @@ func TestApplicationService_SearchApplications(t *testing.T) {
 		})
 	}
 }
+
+func TestApplicationService_CreateArchive(t *testing.T) {
+	var created, archived []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /vendor/v3/app", func(w http.ResponseWriter, r *http.Request) {
+		var body createApplicationRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		created = append(created, body.Name)
+		fmt.Fprintf(w, `{"app": {"id": "app-new", "name": %q, "slug": "new-product"}}`, body.Name)
+	})
+	mux.HandleFunc("DELETE /vendor/v3/app/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "app-1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		archived = append(archived, r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewApplicationService(client)
+
+	app, err := service.Create(context.Background(), "  New   Product ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if app.ID != "app-new" || app.Slug != "new-product" {
+		t.Errorf("Expected the created application, got %+v", app)
+	}
+	for _, name := range []string{"", "!!!", strings.Repeat("a", 256)} {
+		if _, err := service.Create(context.Background(), name); err == nil {
+			t.Errorf("Expected an error creating an application named %q", name)
+		}
+	}
+	if len(created) != 1 || created[0] != "New Product" {
+		t.Errorf("Expected one application named 'New Product' to be created, got %v", created)
+	}
+
+	if err := service.Archive(context.Background(), "app-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := service.Archive(context.Background(), "missing"); err == nil {
+		t.Error("Expected an error archiving an unknown application")
+	}
+	if err := service.Archive(context.Background(), ""); err == nil {
+		t.Error("Expected an error without an application ID")
+	}
+	if len(archived) != 1 {
+		t.Errorf("Expected one application to be archived, got %v", archived)
+	}
+}
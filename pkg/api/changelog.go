@@ -0,0 +1,162 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// ChangelogQuery selects the releases of a changelog. Releases after FromSequence, up to and
+// including ToSequence, are included, where zero leaves that end of the range open. ChannelID
+// limits the changelog to releases promoted to that channel. SinceChannelID starts the range
+// after the release most recently promoted to that channel instead of at FromSequence, for
+// changelogs such as everything promoted to Beta since the last Stable promotion.
+type ChangelogQuery struct {
+	FromSequence   int64
+	ToSequence     int64
+	ChannelID      string
+	SinceChannelID string
+}
+
+// ChangelogEntry is a release in a changelog. PromotedAt is when the release was first
+// promoted to the changelog's channel, if it follows one.
+type ChangelogEntry struct {
+	Sequence   int64      `json:"sequence"`
+	Version    string     `json:"version"`
+	Notes      string     `json:"notes,omitempty"`
+	IsRequired bool       `json:"is_required"`
+	CreatedAt  time.Time  `json:"created_at"`
+	PromotedAt *time.Time `json:"promoted_at,omitempty"`
+}
+
+// Changelog lists the releases in a sequence range with their notes, oldest first.
+// FromSequence is the exclusive start of the range, as given or found from a channel.
+type Changelog struct {
+	FromSequence int64            `json:"from_sequence"`
+	ToSequence   int64            `json:"to_sequence,omitempty"`
+	Entries      []ChangelogEntry `json:"entries"`
+}
+
+// Changelog gathers the release notes of an application's releases in a sequence range,
+// for turning into customer-facing notes. Drafts are left out, since they have not shipped.
+func (s *ReleaseService) Changelog(ctx context.Context, appID string, query ChangelogQuery) (*Changelog, error) {
+	if query.FromSequence != 0 && query.SinceChannelID != "" {
+		return nil, fmt.Errorf("a changelog starts at a sequence or a channel's latest promotion, not both")
+	}
+	if query.ToSequence != 0 && query.ToSequence <= query.FromSequence {
+		return nil, fmt.Errorf("to sequence %d must be after from sequence %d", query.ToSequence, query.FromSequence)
+	}
+
+	from, promotions, err := s.changelogRange(ctx, appID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := s.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases for changelog: %w", err)
+	}
+
+	changelog := &Changelog{FromSequence: from, ToSequence: query.ToSequence, Entries: []ChangelogEntry{}}
+	for i := range releases.Releases {
+		release := &releases.Releases[i]
+		if release.Status == models.ReleaseStatusDraft || release.Sequence <= from ||
+			(query.ToSequence != 0 && release.Sequence > query.ToSequence) {
+			continue
+		}
+		if entry, ok := changelogEntry(release, promotions); ok {
+			changelog.Entries = append(changelog.Entries, entry)
+		}
+	}
+
+	slices.SortFunc(changelog.Entries, func(a, b ChangelogEntry) int { return cmp.Compare(a.Sequence, b.Sequence) })
+
+	s.client.logger.DebugContext(ctx, "Successfully built changelog",
+		"app_id", appID,
+		"from_sequence", from,
+		"to_sequence", query.ToSequence,
+		"count", len(changelog.Entries))
+
+	return changelog, nil
+}
+
+// changelogRange returns the exclusive start of a changelog's sequence range and, when it
+// follows a channel, the first promotion of each release to that channel
+func (s *ReleaseService) changelogRange(
+	ctx context.Context, appID string, query ChangelogQuery,
+) (int64, map[int64]models.ChannelRelease, error) {
+	channels := NewChannelService(s.client)
+
+	from := query.FromSequence
+	if query.SinceChannelID != "" {
+		history, err := channels.ListChannelReleases(ctx, appID, query.SinceChannelID)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to list releases of channel %s: %w", query.SinceChannelID, err)
+		}
+		from = latestPromotion(history.Releases).ReleaseSequence
+	}
+
+	if query.ChannelID == "" {
+		return from, nil, nil
+	}
+	history, err := channels.ListChannelReleases(ctx, appID, query.ChannelID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list releases of channel %s: %w", query.ChannelID, err)
+	}
+	return from, firstPromotions(history.Releases), nil
+}
+
+// changelogEntry returns the changelog entry of a release. When the changelog follows a
+// channel, releases never promoted to it have no entry, and the others carry the version
+// label they were promoted with, which is what customers see.
+func changelogEntry(release *models.Release, promotions map[int64]models.ChannelRelease) (ChangelogEntry, bool) {
+	entry := ChangelogEntry{
+		Sequence:   release.Sequence,
+		Version:    release.Version,
+		Notes:      release.Notes,
+		IsRequired: release.IsRequired,
+		CreatedAt:  release.CreatedAt,
+	}
+	if promotions == nil {
+		return entry, true
+	}
+
+	promotion, ok := promotions[release.Sequence]
+	if !ok {
+		return entry, false
+	}
+	if promotion.VersionLabel != "" {
+		entry.Version = promotion.VersionLabel
+	}
+	entry.PromotedAt = &promotion.PromotedAt
+	return entry, true
+}
+
+// latestPromotion returns the most recent promotion in a channel's history, or a zero value
+// when the channel has never had a release
+func latestPromotion(history []models.ChannelRelease) models.ChannelRelease {
+	var latest models.ChannelRelease
+	for _, promotion := range history {
+		if promotion.PromotedAt.After(latest.PromotedAt) ||
+			(promotion.PromotedAt.Equal(latest.PromotedAt) && promotion.ChannelSequence > latest.ChannelSequence) {
+			latest = promotion
+		}
+	}
+	return latest
+}
+
+// firstPromotions returns the first promotion of each release in a channel's history, by
+// release sequence
+func firstPromotions(history []models.ChannelRelease) map[int64]models.ChannelRelease {
+	promotions := make(map[int64]models.ChannelRelease, len(history))
+	for _, promotion := range history {
+		if first, ok := promotions[promotion.ReleaseSequence]; !ok || promotion.PromotedAt.Before(first.PromotedAt) {
+			promotions[promotion.ReleaseSequence] = promotion
+		}
+	}
+	return promotions
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReleaseService_Changelog(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/app/app-1/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [
+			{"sequence": 4, "version": "1.3.0-beta.1", "notes": "Adds SAML login", "status": "released"},
+			{"sequence": 1, "version": "1.0.0", "notes": "First release", "status": "released"},
+			{"sequence": 2, "version": "1.1.0", "notes": "Fixes upgrades", "status": "released", "isRequired": true},
+			{"sequence": 3, "version": "1.2.0", "notes": "Faster startup", "status": "released"},
+			{"sequence": 5, "version": "1.3.0-beta.2", "notes": "Work in progress", "status": "draft"}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/app-1/channel/{channel}/releases", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("channel") {
+		case "stable":
+			fmt.Fprint(w, `{"releases": [
+				{"channelSequence": 1, "releaseSequence": 1, "promotedAt": "2025-01-01T00:00:00Z"},
+				{"channelSequence": 2, "releaseSequence": 2, "promotedAt": "2025-02-01T00:00:00Z"}
+			]}`)
+		case "beta":
+			fmt.Fprint(w, `{"releases": [
+				{"channelSequence": 3, "releaseSequence": 4, "versionLabel": "1.3.0-beta",
+				 "promotedAt": "2025-03-01T00:00:00Z"},
+				{"channelSequence": 2, "releaseSequence": 3, "promotedAt": "2025-02-15T00:00:00Z"},
+				{"channelSequence": 1, "releaseSequence": 2, "promotedAt": "2025-01-20T00:00:00Z"}
+			]}`)
+		case "empty":
+			fmt.Fprint(w, `{"releases": []}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewReleaseService(client)
+
+	tests := []struct {
+		name          string
+		query         ChangelogQuery
+		wantFrom      int64
+		wantSequences []int64
+		wantVersions  []string
+		expectError   bool
+	}{
+		{
+			name:          "every shipped release",
+			wantSequences: []int64{1, 2, 3, 4},
+		},
+		{
+			name:          "sequence range",
+			query:         ChangelogQuery{FromSequence: 1, ToSequence: 3},
+			wantFrom:      1,
+			wantSequences: []int64{2, 3},
+		},
+		{
+			name:          "beta since the last stable promotion",
+			query:         ChangelogQuery{ChannelID: "beta", SinceChannelID: "stable"},
+			wantFrom:      2,
+			wantSequences: []int64{3, 4},
+			wantVersions:  []string{"1.2.0", "1.3.0-beta"},
+		},
+		{
+			name:          "channel that never shipped",
+			query:         ChangelogQuery{SinceChannelID: "empty"},
+			wantSequences: []int64{1, 2, 3, 4},
+		},
+		{
+			name:        "both starts",
+			query:       ChangelogQuery{FromSequence: 1, SinceChannelID: "stable"},
+			expectError: true,
+		},
+		{
+			name:        "empty range",
+			query:       ChangelogQuery{FromSequence: 3, ToSequence: 2},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			query:       ChangelogQuery{ChannelID: "nightly"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changelog, err := service.Changelog(context.Background(), "app-1", tt.query)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if changelog.FromSequence != tt.wantFrom {
+				t.Errorf("Expected from sequence %d, got %d", tt.wantFrom, changelog.FromSequence)
+			}
+			var sequences []int64
+			var versions []string
+			for _, entry := range changelog.Entries {
+				sequences = append(sequences, entry.Sequence)
+				versions = append(versions, entry.Version)
+				if (entry.PromotedAt != nil) != (tt.query.ChannelID != "") {
+					t.Errorf("Expected promotion times only for a channel's changelog, got %+v", entry)
+				}
+			}
+			if !slices.Equal(sequences, tt.wantSequences) {
+				t.Errorf("Expected releases %v, got %v", tt.wantSequences, sequences)
+			}
+			if tt.wantVersions != nil && !slices.Equal(versions, tt.wantVersions) {
+				t.Errorf("Expected versions %v, got %v", tt.wantVersions, versions)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// ChannelService provides methods for interacting with channel APIs
+type ChannelService struct {
+	client *Client
+}
+
+// NewChannelService creates a new ChannelService
+func NewChannelService(client *Client) *ChannelService {
+	return &ChannelService{
+		client: client,
+	}
+}
+
+// ChannelList represents a list of channels
+type ChannelList struct {
+	Channels []models.Channel `json:"channels"`
+}
+
+// ListChannels retrieves all channels for an application
+func (s *ChannelService) ListChannels(ctx context.Context, appID string) (*ChannelList, error) {
+	var result ChannelList
+	if err := s.client.getAppResource(ctx, appID, "channels", &result); err != nil {
+		return nil, err
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed channels",
+		"app_id", appID,
+		"count", len(result.Channels))
+
+	return &result, nil
+}
+
+// SearchChannels returns the channels of an application whose name, slug, description, or ID
+// contains the query (ignoring case). The channels endpoint returns every channel in one
+// response, so no channels are left unsearched.
+func (s *ChannelService) SearchChannels(ctx context.Context, appID, query string) (*ChannelList, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	all, err := s.ListChannels(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channels for search: %w", err)
+	}
+
+	matches := []models.Channel{}
+	for i := range all.Channels {
+		channel := &all.Channels[i]
+		if models.MatchesQuery(query, channel.Name, channel.ChannelSlug, channel.Description, channel.ID) {
+			matches = append(matches, *channel)
+		}
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully searched channels",
+		"query", query,
+		"total_channels", len(all.Channels),
+		"filtered_count", len(matches))
+
+	return &ChannelList{Channels: matches}, nil
+}
+
+// ChannelReleaseList represents a channel's release history
+type ChannelReleaseList struct {
+	Releases []models.ChannelRelease `json:"releases"`
+}
+
+// ListChannelReleases retrieves the history of releases promoted to a channel, with when
+// each was promoted
+func (s *ChannelService) ListChannelReleases(
+	ctx context.Context,
+	appID, channelID string,
+) (*ChannelReleaseList, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID is required")
+	}
+
+	var result ChannelReleaseList
+	resource := fmt.Sprintf("channel/%s/releases", url.PathEscape(channelID))
+	if err := s.client.getAppResource(ctx, appID, resource, &result); err != nil {
+		return nil, err
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed channel releases",
+		"app_id", appID,
+		"channel_id", channelID,
+		"count", len(result.Releases))
+
+	return &result, nil
+}
+
+// updateChannelRequest is the request body for updating a channel. The API replaces the
+// channel's settings, so its name and description are sent with every setting, changed or not.
+type updateChannelRequest struct {
+	Name                 string `json:"name"`
+	Description          string `json:"description,omitempty"`
+	IsDefault            bool   `json:"isDefault"`
+	AirgapEnabled        bool   `json:"airgapEnabled"`
+	SemverRequired       bool   `json:"semverRequired"`
+	EnforceVersionLabel  bool   `json:"enforceVersionLabel"`
+	ReleaseNotesRequired bool   `json:"releaseNotesRequired"`
+}
+
+// UpdateSettings saves a channel's settings, returning the channel as stored by the API
+func (s *ChannelService) UpdateSettings(
+	ctx context.Context, appID string, channel *models.Channel, settings models.ChannelSettings,
+) (*models.Channel, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+	if channel.ID == "" {
+		return nil, fmt.Errorf("channel ID is required")
+	}
+
+	request := updateChannelRequest{
+		Name:                 channel.Name,
+		Description:          channel.Description,
+		IsDefault:            settings.IsDefault,
+		AirgapEnabled:        settings.AirgapEnabled,
+		SemverRequired:       settings.SemverRequired,
+		EnforceVersionLabel:  settings.EnforceVersionLabel,
+		ReleaseNotesRequired: settings.ReleaseNotesRequired,
+	}
+	path := fmt.Sprintf("/vendor/v3/app/%s/channel/%s", url.PathEscape(appID), url.PathEscape(channel.ID))
+
+	s.client.logger.DebugContext(ctx, "Updating channel settings",
+		"app_id", appID,
+		"channel_id", channel.ID,
+		"is_default", settings.IsDefault,
+		"airgap_enabled", settings.AirgapEnabled,
+		"semver_required", settings.SemverRequired,
+		"enforce_version_label", settings.EnforceVersionLabel,
+		"release_notes_required", settings.ReleaseNotesRequired)
+
+	var envelope channelResponse
+	if err := s.client.putJSON(ctx, path, request, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to update channel: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully updated channel settings", "channel_id", channel.ID)
+
+	return &envelope.Channel, nil
+}
+
+// UpdateVersioning saves a channel's semantic versioning settings, keeping its other
+// settings, and returns the channel as stored by the API
+func (s *ChannelService) UpdateVersioning(
+	ctx context.Context, appID string, channel *models.Channel, versioning models.ChannelVersioning,
+) (*models.Channel, error) {
+	settings := channel.Settings()
+	settings.ChannelVersioning = versioning
+	return s.UpdateSettings(ctx, appID, channel, settings)
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestChannelService_ListChannels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/channels" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"channels": [{"id": "channel-1", "name": "Stable"}, {"id": "channel-2", "name": "Beta"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewChannelService(client)
+
+	tests := []struct {
+		name          string
+		appID         string
+		expectError   bool
+		expectedCount int
+	}{
+		{name: "list channels", appID: "app-1", expectedCount: 2},
+		{name: "unknown application", appID: "missing", expectError: true},
+		{name: "missing application ID", appID: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListChannels(context.Background(), tt.appID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Channels) != tt.expectedCount {
+				t.Errorf("Expected %d channels, got %d", tt.expectedCount, len(result.Channels))
+			}
+		})
+	}
+}
+
+func TestChannelService_SearchChannels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [
+			{"id": "channel-1", "name": "Stable", "channelSlug": "stable"},
+			{"id": "channel-2", "name": "Beta", "channelSlug": "beta", "description": "Early access builds"},
+			{"id": "channel-3", "name": "Unstable", "channelSlug": "unstable"}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewChannelService(client)
+
+	tests := []struct {
+		name        string
+		query       string
+		expectError bool
+		expectedIDs []string
+	}{
+		{name: "match on name ignoring case", query: "STABLE", expectedIDs: []string{"channel-1", "channel-3"}},
+		{name: "match on description", query: "early access", expectedIDs: []string{"channel-2"}},
+		{name: "match on ID", query: "channel-3", expectedIDs: []string{"channel-3"}},
+		{name: "no matches", query: "nightly", expectedIDs: []string{}},
+		{name: "empty query", query: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.SearchChannels(context.Background(), "app-1", tt.query)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ids := []string{}
+			for _, channel := range result.Channels {
+				ids = append(ids, channel.ID)
+			}
+			if !slices.Equal(ids, tt.expectedIDs) {
+				t.Errorf("Expected channels %v, got %v", tt.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestChannelService_ListChannelReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/channel/channel-1/releases" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"releases": [
+			{"channelSequence": 2, "releaseSequence": 5, "versionLabel": "1.1.0", "promotedAt": "2025-03-01T00:00:00Z"},
+			{"channelSequence": 1, "releaseSequence": 3, "versionLabel": "1.0.0", "promotedAt": "2025-01-01T00:00:00Z"}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewChannelService(client)
+
+	tests := []struct {
+		name          string
+		channelID     string
+		expectError   bool
+		expectedCount int
+	}{
+		{name: "list channel releases", channelID: "channel-1", expectedCount: 2},
+		{name: "unknown channel", channelID: "channel-9", expectError: true},
+		{name: "missing channel ID", channelID: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListChannelReleases(context.Background(), "app-1", tt.channelID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Releases) != tt.expectedCount {
+				t.Fatalf("Expected %d releases, got %d", tt.expectedCount, len(result.Releases))
+			}
+			if result.Releases[0].ReleaseSequence != 5 || result.Releases[0].PromotedAt.IsZero() {
+				t.Errorf("Unexpected channel release: %+v", result.Releases[0])
+			}
+		})
+	}
+}
+
+func TestChannelService_UpdateVersioning(t *testing.T) {
+	var gotBody updateChannelRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/vendor/v3/app/app-1/channel/channel-1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		gotBody = updateChannelRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		fmt.Fprintf(w, `{"channel": {"id": "channel-1", "name": %q, "semverRequired": %t, "enforceVersionLabel": %t}}`,
+			gotBody.Name, gotBody.SemverRequired, gotBody.EnforceVersionLabel)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewChannelService(client)
+
+	channel := &models.Channel{
+		ID: "channel-1", Name: "Stable", Description: "Production releases", AirgapEnabled: true,
+	}
+	versioning := models.ChannelVersioning{SemverRequired: true}
+	result, err := service.UpdateVersioning(context.Background(), "app-1", channel, versioning)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The channel's other settings are kept
+	want := updateChannelRequest{
+		Name: "Stable", Description: "Production releases", AirgapEnabled: true, SemverRequired: true,
+	}
+	if gotBody != want {
+		t.Errorf("Expected request body %+v, got %+v", want, gotBody)
+	}
+	if result.Versioning() != versioning {
+		t.Errorf("Expected the stored settings %+v, got %+v", versioning, result.Versioning())
+	}
+
+	if _, err := service.UpdateVersioning(context.Background(), "app-1", &models.Channel{ID: "channel-9"},
+		versioning); err == nil {
+		t.Error("Expected an error updating an unknown channel")
+	}
+	if _, err := service.UpdateVersioning(context.Background(), "app-1", &models.Channel{}, versioning); err == nil {
+		t.Error("Expected an error without a channel ID")
+	}
+}
+
+func TestChannelService_UpdateSettings(t *testing.T) {
+	var gotBody updateChannelRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/vendor/v3/app/app-1/channel/channel-1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		gotBody = updateChannelRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		fmt.Fprintf(w, `{"channel": {"id": "channel-1", "name": %q, "isDefault": %t, "airgapEnabled": %t, `+
+			`"semverRequired": %t, "enforceVersionLabel": %t, "releaseNotesRequired": %t}}`,
+			gotBody.Name, gotBody.IsDefault, gotBody.AirgapEnabled, gotBody.SemverRequired,
+			gotBody.EnforceVersionLabel, gotBody.ReleaseNotesRequired)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewChannelService(client)
+
+	channel := &models.Channel{ID: "channel-1", Name: "Stable", IsDefault: true, SemverRequired: true}
+	settings := channel.Settings()
+	settings.AirgapEnabled = true
+	settings.ReleaseNotesRequired = true
+	result, err := service.UpdateSettings(context.Background(), "app-1", channel, settings)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := updateChannelRequest{
+		Name: "Stable", IsDefault: true, AirgapEnabled: true, SemverRequired: true, ReleaseNotesRequired: true,
+	}
+	if gotBody != want {
+		t.Errorf("Expected request body %+v, got %+v", want, gotBody)
+	}
+	if result.Settings() != settings {
+		t.Errorf("Expected the stored settings %+v, got %+v", settings, result.Settings())
+	}
+
+	if _, err := service.UpdateSettings(context.Background(), "", channel, settings); err == nil {
+		t.Error("Expected an error without an application ID")
+	}
+	if _, err := service.UpdateSettings(context.Background(), "app-1", &models.Channel{ID: "channel-9"},
+		settings); err == nil {
+		t.Error("Expected an error updating an unknown channel")
+	}
+}
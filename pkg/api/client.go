@@ -1,28 +1,73 @@
 // Package api provides HTTP client functionality for communicating with the Replicated Vendor Portal API.
+// Services decode responses directly into the pkg/models types, so entities are defined, validated, and
+// serialized in one place; this package only adds list envelopes and request bodies.
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"sync"
 	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/diskcache"
 )
 
 // Constants for HTTP client configuration
 const (
+	DefaultBaseURL     = "https://api.replicated.com"
 	DefaultTimeout     = 30 * time.Second
 	DefaultUserAgent   = "replicated-mcp-server"
 	HTTPErrorThreshold = 400
 )
 
+// RequestObserver receives the outcome of every API request, for example to record metrics.
+// The status is 0 when the request failed before a response was received.
+type RequestObserver interface {
+	ObserveAPIRequest(method string, status int, duration time.Duration, err error)
+}
+
+// Observers combines request observers into one that notifies each of them in turn
+func Observers(observers ...RequestObserver) RequestObserver {
+	return requestObservers(observers)
+}
+
+// requestObservers notifies several observers of each request
+type requestObservers []RequestObserver
+
+// ObserveAPIRequest implements RequestObserver
+func (o requestObservers) ObserveAPIRequest(method string, status int, duration time.Duration, err error) {
+	for _, observer := range o {
+		observer.ObserveAPIRequest(method, status, duration, err)
+	}
+}
+
 // Client provides HTTP client functionality for the Replicated API
 type Client struct {
+	mu         sync.RWMutex
 	config     ClientConfig
 	httpClient *http.Client
+	failover   *failover
+	hedger     *hedger
+	limiter    *hostLimiter
+	throttle   *rateLimiter
+	har        *harRecorder
+	recorder   *fixtureRecorder
+	diskCache  *diskcache.Cache
+	validators *validatorCache
+	observer   RequestObserver
 	logger     *slog.Logger
 }
 
@@ -43,31 +88,324 @@ func NewClientWithLogger(config ClientConfig, logger *slog.Logger) (*Client, err
 		config.Timeout = DefaultTimeout
 	}
 
+	httpClient, err := httpClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+
 	client := &Client{
-		config: config,
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
-		logger: logger,
+		config:     config,
+		httpClient: httpClient,
+		failover:   failoverFor(config),
+		hedger:     newHedger(config.Hedging),
+		limiter:    newHostLimiter(config.MaxConcurrentRequests),
+		throttle:   newRateLimiter(config.RequestsPerSecond),
+		har:        newHARRecorder(config.HARFile, config.redactor(), logger),
+		recorder:   newFixtureRecorder(config.RecordDir, config.redactor()),
+		diskCache:  diskCacheFor(config),
+		validators: newValidatorCache(),
+		logger:     logger,
 	}
 
 	return client, nil
 }
 
-// GetAuthHeaders returns the authentication headers for API requests
+// UpdateConfig replaces the client configuration at runtime, for example after a
+// token rotation or endpoint change. In-flight requests finish with the previous settings.
+func (c *Client) UpdateConfig(config ClientConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	httpClient, err := httpClientFor(config)
+	if err != nil {
+		return err
+	}
+
+	// A replaced HAR capture is written out once the new configuration is in place
+	var retired *harRecorder
+	defer func() {
+		if retired == nil {
+			return
+		}
+		if err := retired.flush(); err != nil {
+			c.logger.Warn("Failed to capture API traffic", "path", retired.path, "error", err)
+		}
+	}()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Responses kept for conditional requests were read with the previous token
+	if config.APIToken != c.config.APIToken {
+		c.validators = newValidatorCache()
+	}
+	c.config = config
+	c.httpClient = httpClient
+	c.failover = failoverFor(config)
+	c.hedger = newHedger(config.Hedging)
+	if c.limiter == nil || c.limiter.limit != config.MaxConcurrentRequests {
+		c.limiter = newHostLimiter(config.MaxConcurrentRequests)
+	}
+	if c.throttle == nil || c.throttle.rate != config.RequestsPerSecond {
+		c.throttle = newRateLimiter(config.RequestsPerSecond)
+	}
+	if c.har == nil || c.har.path != config.HARFile {
+		retired = c.har
+		c.har = newHARRecorder(config.HARFile, config.redactor(), c.logger)
+	} else {
+		c.har.setRedactor(config.redactor())
+	}
+	if c.recorder == nil || c.recorder.dir != config.RecordDir {
+		c.recorder = newFixtureRecorder(config.RecordDir, config.redactor())
+	} else {
+		c.recorder.setRedactor(config.redactor())
+	}
+	if c.diskCache == nil || c.diskCache.Dir() != config.DiskCacheDir {
+		c.diskCache = diskCacheFor(config)
+	}
+
+	return nil
+}
+
+// httpClientFor returns the HTTP client for the configuration, answering requests from
+// fixtures when they are configured
+func httpClientFor(config ClientConfig) (*http.Client, error) {
+	httpClient := &http.Client{
+		Timeout: config.Timeout,
+	}
+	if config.Fixtures != nil {
+		httpClient.Transport = newFixtureTransport(config.Fixtures)
+		return httpClient, nil
+	}
+
+	transport, err := transportFor(config)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = transport
+	return httpClient, nil
+}
+
+// transportFor returns the transport sending requests through the configured proxy, or the
+// one the environment names, trusting the configured certificate authorities, and presenting
+// the client certificate, if any
+func transportFor(config ClientConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL '%s': %w", config.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.CABundle == "" && !config.InsecureSkipVerify && config.ClientCertFile == "" {
+		return transport, nil
+	}
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		//nolint:gosec // Verification is only skipped when the operator explicitly asks for it
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	if config.CABundle != "" {
+		roots, err := certPoolWith(config.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.RootCAs = roots
+	}
+	if config.ClientCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.ClientCertFile, config.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return transport, nil
+}
+
+// certPoolWith returns the system's certificate authorities with those in a PEM bundle added
+func certPoolWith(bundle string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", bundle)
+	}
+	return roots, nil
+}
+
+// failoverFor returns fresh failover state when the configuration has a fallback endpoint
+func failoverFor(config ClientConfig) *failover {
+	if config.FallbackURL == "" {
+		return nil
+	}
+	return newFailover()
+}
+
+// diskCacheFor returns the disk cache for the configuration, or nil when it is disabled
+func diskCacheFor(config ClientConfig) *diskcache.Cache {
+	if config.DiskCacheDir == "" {
+		return nil
+	}
+	return diskcache.Open(config.DiskCacheDir, diskcache.DefaultMaxBytes)
+}
+
+// SetObserver registers an observer that is notified of every API request
+func (c *Client) SetObserver(observer RequestObserver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observer = observer
+}
+
+// observeRequest notifies the registered observer, if any, of a completed request
+func (c *Client) observeRequest(method string, status int, duration time.Duration, err error) {
+	c.mu.RLock()
+	observer := c.observer
+	c.mu.RUnlock()
+
+	if observer != nil {
+		observer.ObserveAPIRequest(method, status, duration, err)
+	}
+}
+
+// snapshot returns the current configuration and HTTP client
+func (c *Client) snapshot() (ClientConfig, *http.Client) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config, c.httpClient
+}
+
+// failoverState returns the current failover state, or nil if no fallback is configured
+func (c *Client) failoverState() *failover {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.failover
+}
+
+// limiterState returns the current per-host request limiter, or nil if requests are unlimited
+func (c *Client) limiterState() *hostLimiter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.limiter
+}
+
+// throttleState returns the current request rate limiter, or nil if the rate is unlimited
+func (c *Client) throttleState() *rateLimiter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.throttle
+}
+
+// recorderState returns the current fixture recorder, or nil if responses are not being recorded
+func (c *Client) recorderState() *fixtureRecorder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.recorder
+}
+
+// harState returns the current HAR recorder, or nil if traffic is not being captured
+func (c *Client) harState() *harRecorder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.har
+}
+
+// diskCacheState returns the current disk cache, or nil if it is disabled
+func (c *Client) diskCacheState() *diskcache.Cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.diskCache
+}
+
+// validatorState returns the current conditional request cache
+func (c *Client) validatorState() *validatorCache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.validators
+}
+
+// hedgerState returns the current hedging state, or nil if hedged reads are disabled
+func (c *Client) hedgerState() *hedger {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.hedger
+}
+
+// Close writes out the HAR capture, if traffic is being captured. The client can still be
+// used afterwards.
+func (c *Client) Close() error {
+	if recorder := c.harState(); recorder != nil {
+		return recorder.flush()
+	}
+	return nil
+}
+
+// GetAuthHeaders returns the authentication headers for API requests, along with the
+// configured extra headers
 func (c *Client) GetAuthHeaders() http.Header {
+	config, _ := c.snapshot()
+
 	headers := make(http.Header)
-	headers.Set("Authorization", c.config.APIToken)
+	for name, value := range config.Headers {
+		headers.Set(name, value)
+	}
+	headers.Set("Authorization", config.APIToken)
 	headers.Set("User-Agent", DefaultUserAgent)
 	return headers
 }
 
-// makeRequest creates and executes an HTTP request with proper authentication
+// makeRequest creates and executes an HTTP request with proper authentication.
+// Reads fail over to the fallback endpoint when one is configured and may be hedged.
 func (c *Client) makeRequest(
 	ctx context.Context, method, path, contentType string, body io.Reader,
+) (*http.Response, error) {
+	config, httpClient := c.snapshot()
+
+	if method != http.MethodGet {
+		return c.send(ctx, httpClient, config.BaseURL, method, path, contentType, body, false)
+	}
+	if state := c.failoverState(); state != nil {
+		return c.readWithFailover(ctx, config, httpClient, state, path)
+	}
+	return c.read(ctx, httpClient, config.BaseURL, path)
+}
+
+// send executes a single HTTP request against the given base URL, traced as a client span
+// of the caller's trace. A streamed response's body is left for the caller to read as it
+// arrives: it is neither buffered to capture it nor validated against earlier responses.
+func (c *Client) send(
+	ctx context.Context, httpClient *http.Client, base, method, path, contentType string, body io.Reader,
+	streamed bool,
+) (*http.Response, error) {
+	ctx, span := startRequestSpan(ctx, base, method, path)
+	resp, err := c.sendRequest(ctx, httpClient, base, method, path, contentType, body, streamed)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	endRequestSpan(span, status, err)
+	return resp, err
+}
+
+// sendRequest executes a single HTTP request against the given base URL
+func (c *Client) sendRequest(
+	ctx context.Context, httpClient *http.Client, base, method, path, contentType string, body io.Reader,
+	streamed bool,
 ) (*http.Response, error) {
 	// Build full URL
-	baseURL, err := url.Parse(c.config.BaseURL)
+	baseURL, err := url.Parse(base)
 	if err != nil {
 		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
@@ -84,40 +422,72 @@ func (c *Client) makeRequest(
 		"content_type", contentType,
 	)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, method, fullURL.String(), body)
+	// Buffer the request body when traffic is captured
+	recorder := c.harState()
+	var requestBody []byte
+	if recorder != nil && body != nil {
+		if requestBody, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		body = bytes.NewReader(requestBody)
+	}
+
+	req, err := c.newRequest(ctx, method, fullURL.String(), contentType, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Add authentication headers
-	headers := c.GetAuthHeaders()
-	for key, values := range headers {
-		for _, value := range values {
-			req.Header.Add(key, value)
+	// Ask for reads only if the resource changed since it was last read
+	var validators *validatorCache
+	var cached *validatedResponse
+	if method == http.MethodGet && !streamed {
+		if validators = c.validatorState(); validators != nil {
+			if cached = validators.lookup(fullURL.String()); cached != nil {
+				cached.setConditionalHeaders(req)
+			}
 		}
 	}
 
-	// Set content type if provided
-	if contentType != "" && body != nil {
-		req.Header.Set("Content-Type", contentType)
+	// Wait for the request's turn under the rate limit, then for a free request slot for
+	// this host, so requests waiting for their turn don't hold slots
+	if throttle := c.throttleState(); throttle != nil {
+		if err := throttle.wait(ctx, fullURL.Host); err != nil {
+			return nil, classifyRequestError(ctx, method, fullURL.String(), err)
+		}
+	}
+	release := func() {}
+	if limiter := c.limiterState(); limiter != nil {
+		release, err = limiter.acquire(ctx, fullURL.Host)
+		if err != nil {
+			return nil, classifyRequestError(ctx, method, fullURL.String(), err)
+		}
 	}
 
 	// Execute request
 	start := time.Now()
-	resp, err := c.httpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	duration := time.Since(start)
 
 	if err != nil {
+		release()
+		recordRequest(ctx, TracedRequest{Method: method, URL: fullURL.String()}, duration, err)
+		c.observeRequest(method, 0, duration, err)
 		c.logger.ErrorContext(ctx, "API request failed",
 			"method", method,
 			"url", fullURL.String(),
 			"duration", duration,
 			"error", err,
 		)
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", classifyRequestError(ctx, method, fullURL.String(), err))
 	}
 
+	traced := TracedRequest{Method: method, URL: fullURL.String(), Status: resp.StatusCode}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		traced.CacheStatus = CacheStatusRevalidated
+	}
+	recordRequest(ctx, traced, duration, nil)
+	c.observeRequest(method, resp.StatusCode, duration, nil)
+
 	// Log the response
 	c.logger.DebugContext(ctx, "API request completed",
 		"method", method,
@@ -126,9 +496,94 @@ func (c *Client) makeRequest(
 		"duration", duration,
 	)
 
+	if err := decompress(resp); err != nil {
+		release()
+		return nil, err
+	}
+
+	if streamed {
+		// Captured without its body, which is never held in memory, and never recorded as a
+		// fixture, which could not replay it
+		if recorder != nil {
+			recorder.record(req, requestBody, resp, nil, start, duration)
+		}
+	} else if fixtures := c.recorderState(); recorder != nil || fixtures != nil {
+		responseBody, bufferErr := bufferResponseBody(resp)
+		if bufferErr != nil {
+			release()
+			return nil, bufferErr
+		}
+		if recorder != nil {
+			recorder.record(req, requestBody, resp, responseBody, start, duration)
+		}
+		if fixtures != nil {
+			if recordErr := fixtures.record(method, path, resp.StatusCode, responseBody); recordErr != nil {
+				c.logger.WarnContext(ctx, "Failed to record API fixture", "path", path, "error", recordErr)
+			}
+		}
+	}
+
+	if validators != nil {
+		if resp, err = validators.resolve(fullURL.String(), cached, resp); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
 	return resp, nil
 }
 
+// newRequest creates an HTTP request with authentication headers, the content type, if any, and
+// the trace context
+func (c *Client) newRequest(
+	ctx context.Context, method, requestURL, contentType string, body io.Reader,
+) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Add authentication headers
+	headers := c.GetAuthHeaders()
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// Set content type if provided
+	if contentType != "" && body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	acceptGzip(req)
+
+	// Carry the trace on to the API
+	propagateTrace(ctx, req)
+
+	return req, nil
+}
+
+// classifyRequestError wraps the error of a request that was canceled or timed out in a
+// *RequestError, telling the caller's cancellation and deadline apart from the client's own
+// timeout. Other errors are returned unchanged.
+func classifyRequestError(ctx context.Context, method, requestURL string, err error) error {
+	var kind RequestErrorKind
+	var netErr net.Error
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		kind = RequestCanceled
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		kind = RequestDeadlineExceeded
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		kind = RequestTimeout
+	default:
+		return err
+	}
+	return &RequestError{Kind: kind, Method: method, URL: requestURL, Err: err}
+}
+
 // Get performs a GET request to the specified path
 func (c *Client) Get(ctx context.Context, path string) (*http.Response, error) {
 	return c.makeRequest(ctx, "GET", path, "", nil)
@@ -149,6 +604,140 @@ func (c *Client) Delete(ctx context.Context, path string) (*http.Response, error
 	return c.makeRequest(ctx, "DELETE", path, "", nil)
 }
 
+// VerifyToken checks that the API token is accepted by making a lightweight authenticated
+// read. A rejected token is reported as an *Error with status 401 or 403.
+func (c *Client) VerifyToken(ctx context.Context) error {
+	return c.getJSON(ctx, healthCheckPath, nil)
+}
+
+// getJSON performs a GET request and decodes a successful JSON response into result.
+// Error responses are converted into an *Error wrapped with context.
+func (c *Client) getJSON(ctx context.Context, path string, result any) error {
+	return c.doJSON(ctx, http.MethodGet, path, nil, result)
+}
+
+// getText performs a GET request and returns the body of a successful response, for endpoints
+// that answer with a document rather than JSON. Error responses are converted into an *Error.
+func (c *Client) getText(ctx context.Context, path string) (string, error) {
+	resp, err := c.makeRequest(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= HTTPErrorThreshold {
+		return "", fmt.Errorf("API error: %w", c.ConvertHTTPError(resp))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	return string(data), nil
+}
+
+// getCachedJSON performs a GET request like getJSON, answering it from the disk cache when
+// the response was cached before under its URL for the same API token. A response is cached
+// once cacheable reports that the decoded result can no longer change. Failing to write the
+// cache only loses the saving, so it is logged rather than returned.
+func (c *Client) getCachedJSON(ctx context.Context, path string, result any, cacheable func() bool) error {
+	cache := c.diskCacheState()
+	if cache == nil {
+		return c.getJSON(ctx, path, result)
+	}
+
+	config, _ := c.snapshot()
+	requestURL := config.BaseURL + path
+	key := diskCacheKey(config.APIToken, requestURL)
+	start := time.Now()
+	if data, found := cache.Get(key); found && json.Unmarshal(data, result) == nil {
+		recordRequest(ctx, TracedRequest{
+			Method: http.MethodGet, URL: requestURL, Status: http.StatusOK, CacheStatus: CacheStatusHit,
+		}, time.Since(start), nil)
+		c.logger.DebugContext(ctx, "Answered API request from the disk cache", "path", path)
+		return nil
+	}
+
+	var data json.RawMessage
+	if err := c.getJSON(ctx, path, &data); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if cacheable() {
+		if err := cache.Put(key, data); err != nil {
+			c.logger.WarnContext(ctx, "Failed to write the disk cache", "path", path, "error", err)
+		}
+	}
+	return nil
+}
+
+// diskCacheKey returns the disk cache key of a response: its URL scoped to a hash of the token
+// that read it, so a response is never served to a token, or profile, that did not read it
+func diskCacheKey(token, requestURL string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:]) + " " + requestURL
+}
+
+// putJSON performs a PUT request with a JSON-encoded payload and decodes a successful
+// JSON response into result. A nil result discards the response body.
+func (c *Client) putJSON(ctx context.Context, path string, payload, result any) error {
+	return c.doJSON(ctx, http.MethodPut, path, payload, result)
+}
+
+// postJSON performs a POST request with a JSON-encoded payload and decodes a successful
+// JSON response into result. A nil result discards the response body.
+func (c *Client) postJSON(ctx context.Context, path string, payload, result any) error {
+	return c.doJSON(ctx, http.MethodPost, path, payload, result)
+}
+
+// deleteJSON performs a DELETE request and decodes a successful JSON response into result.
+// A nil result discards the response body.
+func (c *Client) deleteJSON(ctx context.Context, path string, result any) error {
+	return c.doJSON(ctx, http.MethodDelete, path, nil, result)
+}
+
+// doJSON performs a request with an optional JSON payload and decodes the JSON response
+func (c *Client) doJSON(ctx context.Context, method, path string, payload, result any) error {
+	var body io.Reader
+	contentType := ""
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	resp, err := c.makeRequest(ctx, method, path, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= HTTPErrorThreshold {
+		apiErr := c.ConvertHTTPError(resp)
+		return fmt.Errorf("API error: %w", apiErr)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
 // ConvertHTTPError converts an HTTP error response to an Error
 func (c *Client) ConvertHTTPError(resp *http.Response) *Error {
 	if resp.StatusCode < HTTPErrorThreshold {
@@ -180,3 +769,21 @@ func (c *Client) ConvertHTTPError(resp *http.Response) *Error {
 
 	return apiError
 }
+
+// getAppResource retrieves an application-scoped collection such as channels or releases,
+// decoding the response into result
+func (c *Client) getAppResource(ctx context.Context, appID, resource string, result any) error {
+	if appID == "" {
+		return fmt.Errorf("application ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s/%s", url.PathEscape(appID), resource)
+
+	c.logger.DebugContext(ctx, "Listing application resources", "app_id", appID, "resource", resource)
+
+	if err := c.getJSON(ctx, path, result); err != nil {
+		return fmt.Errorf("failed to list %s: %w", resource, err)
+	}
+
+	return nil
+}
@@ -2,11 +2,21 @@ package api
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -348,6 +358,121 @@ func TestClient_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestClient_RequestErrorKinds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		clientTimeout time.Duration
+		context       func() (context.Context, context.CancelFunc)
+		wantKind      RequestErrorKind
+	}{
+		{
+			name: "caller cancellation",
+			context: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantKind: RequestCanceled,
+		},
+		{
+			name: "caller deadline",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			wantKind: RequestDeadlineExceeded,
+		},
+		{
+			name:          "client timeout",
+			clientTimeout: 20 * time.Millisecond,
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			wantKind: RequestTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(ClientConfig{
+				APIToken: "test-token",
+				BaseURL:  server.URL,
+				Timeout:  tt.clientTimeout,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			ctx, cancel := tt.context()
+			defer cancel()
+
+			resp, err := client.Get(ctx, "/slow")
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			var requestErr *RequestError
+			if !errors.As(err, &requestErr) {
+				t.Fatalf("Expected a RequestError, got %v", err)
+			}
+			if requestErr.Kind != tt.wantKind {
+				t.Errorf("Expected kind %s, got %s", tt.wantKind, requestErr.Kind)
+			}
+		})
+	}
+}
+
+func TestClient_VerifyToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "valid-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "Unauthorized"}`)
+			return
+		}
+		fmt.Fprint(w, `{"apps": []}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "valid token", token: "valid-token"},
+		{name: "rejected token", token: "expired-token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(ClientConfig{APIToken: tt.token, BaseURL: server.URL})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			err = client.VerifyToken(context.Background())
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Errorf("Expected API error with status %d, got %v", tt.wantStatus, err)
+			}
+		})
+	}
+}
+
 func TestClient_Logging(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -384,3 +509,287 @@ func TestClient_Logging(t *testing.T) {
 		t.Error("Expected client to have a logger")
 	}
 }
+
+func TestClient_UpdateConfig(t *testing.T) {
+	var receivedAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{
+		APIToken: "old-token",
+		BaseURL:  server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if err := client.UpdateConfig(ClientConfig{BaseURL: server.URL}); err == nil {
+		t.Error("Expected error when updating to an invalid configuration")
+	}
+
+	if err := client.UpdateConfig(ClientConfig{APIToken: "new-token", BaseURL: server.URL}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	resp, err := client.Get(context.Background(), testPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if receivedAuth != "new-token" {
+		t.Errorf("Expected updated token to be used, got %s", receivedAuth)
+	}
+
+	if client.config.Timeout != DefaultTimeout {
+		t.Errorf("Expected default timeout after update, got %v", client.config.Timeout)
+	}
+}
+
+// writeCABundle writes a test server's certificate to a PEM file and returns its path
+func writeCABundle(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+	return path
+}
+
+func TestClient_TLSVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bundle := writeCABundle(t, server)
+	notPEM := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		caBundle       string
+		skipVerify     bool
+		wantClientErr  bool
+		wantRequestErr bool
+	}{
+		{name: "untrusted certificate", wantRequestErr: true},
+		{name: "certificate authority from bundle", caBundle: bundle},
+		{name: "verification skipped", skipVerify: true},
+		{name: "missing bundle", caBundle: filepath.Join(t.TempDir(), "missing.pem"), wantClientErr: true},
+		{name: "bundle without certificates", caBundle: notPEM, wantClientErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(ClientConfig{
+				APIToken:           "test-token",
+				BaseURL:            server.URL,
+				CABundle:           tt.caBundle,
+				InsecureSkipVerify: tt.skipVerify,
+			})
+			if (err != nil) != tt.wantClientErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantClientErr)
+			}
+			if tt.wantClientErr {
+				return
+			}
+
+			resp, err := client.Get(context.Background(), testPath)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantRequestErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantRequestErr)
+			}
+		})
+	}
+}
+
+func TestClient_Proxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	if _, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: DefaultBaseURL, ProxyURL: "proxy"}); err == nil {
+		t.Error("Expected a proxy without a scheme and host to be rejected")
+	}
+
+	client, err := NewClient(ClientConfig{
+		APIToken: "test-token",
+		BaseURL:  "http://vendor-api.example.com",
+		ProxyURL: proxy.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.Get(context.Background(), testPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if proxiedHost != "vendor-api.example.com" {
+		t.Errorf("Expected the request to go through the proxy, got host '%s'", proxiedHost)
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key to PEM files,
+// returning their paths and the certificate
+func writeClientCertificate(t *testing.T) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "vendor-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return certFile, keyFile, certificate
+}
+
+func TestClient_APIGateway(t *testing.T) {
+	certFile, keyFile, certificate := writeClientCertificate(t)
+
+	var received http.Header
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(certificate)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	bundle := writeCABundle(t, server)
+	headers := map[string]string{"X-Gateway-Key": "gateway-key", "Authorization": "replaced"}
+
+	tests := []struct {
+		name           string
+		certFile       string
+		keyFile        string
+		wantClientErr  bool
+		wantRequestErr bool
+	}{
+		{name: "client certificate presented", certFile: certFile, keyFile: keyFile},
+		{name: "no client certificate", wantRequestErr: true},
+		{name: "certificate without key", certFile: certFile, wantClientErr: true},
+		{name: "unreadable certificate", certFile: bundle + ".missing", keyFile: keyFile, wantClientErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			client, err := NewClient(ClientConfig{
+				APIToken:       "test-token",
+				BaseURL:        server.URL,
+				CABundle:       bundle,
+				ClientCertFile: tt.certFile,
+				ClientKeyFile:  tt.keyFile,
+				Headers:        headers,
+			})
+			if (err != nil) != tt.wantClientErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantClientErr)
+			}
+			if tt.wantClientErr {
+				return
+			}
+
+			resp, err := client.Get(context.Background(), testPath)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantRequestErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantRequestErr)
+			}
+			if tt.wantRequestErr {
+				return
+			}
+
+			if received.Get("X-Gateway-Key") != "gateway-key" {
+				t.Errorf("Expected the extra header to be sent, got %v", received)
+			}
+			if received.Get("Authorization") != "test-token" {
+				t.Errorf("Expected the API token to be kept, got '%s'", received.Get("Authorization"))
+			}
+		})
+	}
+}
+
+// countingObserver counts the requests it is notified of
+type countingObserver struct {
+	requests int
+	statuses []int
+}
+
+func (o *countingObserver) ObserveAPIRequest(_ string, status int, _ time.Duration, _ error) {
+	o.requests++
+	o.statuses = append(o.statuses, status)
+}
+
+func TestClient_Observers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	first, second := &countingObserver{}, &countingObserver{}
+	client.SetObserver(Observers(first, second))
+
+	if _, err := client.Get(context.Background(), "/v3/apps"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	for _, observer := range []*countingObserver{first, second} {
+		if observer.requests != 1 || observer.statuses[0] != http.StatusOK {
+			t.Errorf("Expected each observer to see the request, got %+v", observer)
+		}
+	}
+}
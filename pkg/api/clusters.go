@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// ClusterService provides methods for interacting with Compatibility Matrix cluster APIs
+type ClusterService struct {
+	client *Client
+}
+
+// NewClusterService creates a new ClusterService
+func NewClusterService(client *Client) *ClusterService {
+	return &ClusterService{
+		client: client,
+	}
+}
+
+// ClusterList represents a list of Compatibility Matrix clusters
+type ClusterList struct {
+	Clusters []models.Cluster `json:"clusters"`
+}
+
+// ListClusters retrieves the team's Compatibility Matrix clusters. Terminated clusters are
+// only included when requested.
+func (s *ClusterService) ListClusters(ctx context.Context, includeTerminated bool) (*ClusterList, error) {
+	path := "/vendor/v3/clusters"
+	if includeTerminated {
+		path += "?show-terminated=true"
+	}
+
+	s.client.logger.DebugContext(ctx, "Listing clusters", "path", path)
+
+	var result ClusterList
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed clusters", "count", len(result.Clusters))
+
+	return &result, nil
+}
+
+// CreateCluster requests a new Compatibility Matrix cluster. The cluster is returned while
+// it is still queued or provisioning.
+func (s *ClusterService) CreateCluster(ctx context.Context, spec *models.ClusterSpec) (*models.Cluster, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.client.logger.DebugContext(ctx, "Creating cluster",
+		"distribution", spec.Distribution,
+		"version", spec.Version)
+
+	var result clusterResponse
+	if err := s.client.postJSON(ctx, "/vendor/v3/cluster", spec, &result); err != nil {
+		return nil, fmt.Errorf("failed to create cluster: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully created cluster",
+		"cluster_id", result.Cluster.ID,
+		"status", result.Cluster.Status)
+
+	return &result.Cluster, nil
+}
+
+// DeleteCluster terminates a Compatibility Matrix cluster
+func (s *ClusterService) DeleteCluster(ctx context.Context, clusterID string) error {
+	if clusterID == "" {
+		return fmt.Errorf("cluster ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/cluster/%s", url.PathEscape(clusterID))
+
+	s.client.logger.DebugContext(ctx, "Deleting cluster", "cluster_id", clusterID)
+
+	if err := s.client.deleteJSON(ctx, path, nil); err != nil {
+		return fmt.Errorf("failed to delete cluster: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully deleted cluster", "cluster_id", clusterID)
+
+	return nil
+}
+
+// GetKubeconfig retrieves the kubeconfig of a running Compatibility Matrix cluster
+func (s *ClusterService) GetKubeconfig(ctx context.Context, clusterID string) (string, error) {
+	if clusterID == "" {
+		return "", fmt.Errorf("cluster ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/cluster/%s/kubeconfig", url.PathEscape(clusterID))
+
+	s.client.logger.DebugContext(ctx, "Getting cluster kubeconfig", "cluster_id", clusterID)
+
+	var result kubeconfigResponse
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return "", fmt.Errorf("failed to get cluster kubeconfig: %w", err)
+	}
+
+	return string(result.Kubeconfig), nil
+}
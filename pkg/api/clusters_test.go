@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// newTestClusterService serves a running cluster and one terminated cluster
+func newTestClusterService(t *testing.T) *ClusterService {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/clusters", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("show-terminated") == "true" {
+			fmt.Fprint(w, `{"clusters": [
+				{"id": "cluster-1", "status": "running"}, {"id": "cluster-0", "status": "terminated"}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"clusters": [{"id": "cluster-1", "status": "running"}]}`)
+	})
+	mux.HandleFunc("POST /vendor/v3/cluster", func(w http.ResponseWriter, r *http.Request) {
+		var spec models.ClusterSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"cluster": {"id": "cluster-2", "distribution": %q, "status": "queued"}}`, spec.Distribution)
+	})
+	mux.HandleFunc("DELETE /vendor/v3/cluster/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "cluster-1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/cluster/cluster-1/kubeconfig", func(w http.ResponseWriter, _ *http.Request) {
+		// "apiVersion: v1" encoded as base64
+		fmt.Fprint(w, `{"kubeconfig": "YXBpVmVyc2lvbjogdjE="}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return NewClusterService(client)
+}
+
+func TestClusterService_ListClusters(t *testing.T) {
+	service := newTestClusterService(t)
+
+	tests := []struct {
+		name              string
+		includeTerminated bool
+		expectedCount     int
+	}{
+		{name: "active clusters", expectedCount: 1},
+		{name: "including terminated clusters", includeTerminated: true, expectedCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListClusters(context.Background(), tt.includeTerminated)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Clusters) != tt.expectedCount {
+				t.Errorf("Expected %d clusters, got %d", tt.expectedCount, len(result.Clusters))
+			}
+		})
+	}
+}
+
+func TestClusterService_CreateCluster(t *testing.T) {
+	service := newTestClusterService(t)
+
+	cluster, err := service.CreateCluster(context.Background(), &models.ClusterSpec{Distribution: "k3s", TTL: "1h"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cluster.ID != "cluster-2" || cluster.Distribution != "k3s" || cluster.Status != models.ClusterStatusQueued {
+		t.Errorf("Unexpected cluster: %+v", cluster)
+	}
+
+	if _, err := service.CreateCluster(context.Background(), &models.ClusterSpec{}); err == nil {
+		t.Error("Expected error for a spec without a distribution")
+	}
+}
+
+func TestClusterService_DeleteCluster(t *testing.T) {
+	service := newTestClusterService(t)
+
+	tests := []struct {
+		name        string
+		clusterID   string
+		expectError bool
+	}{
+		{name: "delete cluster", clusterID: "cluster-1"},
+		{name: "unknown cluster", clusterID: "cluster-9", expectError: true},
+		{name: "missing cluster ID", clusterID: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.DeleteCluster(context.Background(), tt.clusterID)
+			if (err != nil) != tt.expectError {
+				t.Errorf("DeleteCluster() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestClusterService_GetKubeconfig(t *testing.T) {
+	service := newTestClusterService(t)
+
+	kubeconfig, err := service.GetKubeconfig(context.Background(), "cluster-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if kubeconfig != "apiVersion: v1" {
+		t.Errorf("Expected the decoded kubeconfig, got %q", kubeconfig)
+	}
+
+	if _, err := service.GetKubeconfig(context.Background(), "cluster-9"); err == nil {
+		t.Error("Expected error for an unknown cluster")
+	}
+}
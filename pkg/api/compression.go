@@ -0,0 +1,61 @@
+package api
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipEncoding is the content encoding the client asks the API to compress responses with
+const gzipEncoding = "gzip"
+
+// acceptGzip asks for a gzip-compressed response, unless the operator's extra headers already
+// name the encodings to accept. Asking explicitly turns off the transport's own decompression,
+// which stops whenever a request names its encodings, so responses are always decompressed by
+// decompress instead, whatever the headers.
+func acceptGzip(req *http.Request) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", gzipEncoding)
+	}
+}
+
+// decompress replaces the body of a gzip-encoded response with its decompressed content, so
+// callers, traffic captures, and recorded fixtures always see the plain body. A gzip-encoded
+// response without a body, such as 304 Not Modified, is left empty.
+func decompress(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), gzipEncoding) {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	switch {
+	case errors.Is(err, io.EOF):
+		resp.Body.Close()
+		resp.Body = http.NoBody
+	case err != nil:
+		resp.Body.Close()
+		return fmt.Errorf("failed to decompress response: %w", err)
+	default:
+		resp.Body = &gzipBody{Reader: reader, compressed: resp.Body}
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody reads a response body through a gzip reader, closing both when closed
+type gzipBody struct {
+	*gzip.Reader
+	compressed io.ReadCloser
+}
+
+// Close implements io.Closer
+func (b *gzipBody) Close() error {
+	return errors.Join(b.Reader.Close(), b.compressed.Close())
+}
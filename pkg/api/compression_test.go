@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// gzipped compresses data with gzip
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := io.WriteString(writer, data); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestClient_Compression(t *testing.T) {
+	const body = `{"id":"app-1"}`
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantAccept  string
+		compress    bool
+		corrupt     bool
+		expectError bool
+	}{
+		{name: "compressed response", wantAccept: "gzip", compress: true},
+		{name: "uncompressed response", wantAccept: "gzip"},
+		{
+			name:       "operator accept encoding",
+			headers:    map[string]string{"Accept-Encoding": "gzip, deflate"},
+			wantAccept: "gzip, deflate",
+			compress:   true,
+		},
+		{name: "corrupt response", wantAccept: "gzip", compress: true, corrupt: true, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accepted string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accepted = r.Header.Get("Accept-Encoding")
+				switch {
+				case tt.corrupt:
+					w.Header().Set("Content-Encoding", "gzip")
+					_, _ = io.WriteString(w, body)
+				case tt.compress:
+					w.Header().Set("Content-Encoding", "gzip")
+					_, _ = w.Write(gzipped(t, body))
+				default:
+					_, _ = io.WriteString(w, body)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Headers: tt.headers})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			resp, err := client.Get(context.Background(), testPath)
+			if accepted != tt.wantAccept {
+				t.Errorf("Expected Accept-Encoding %q, got %q", tt.wantAccept, accepted)
+			}
+			if tt.expectError {
+				if err == nil {
+					resp.Body.Close()
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if string(data) != body {
+				t.Errorf("Expected the decompressed body, got %q", data)
+			}
+			if encoding := resp.Header.Get("Content-Encoding"); encoding != "" {
+				t.Errorf("Expected no content encoding on the decompressed response, got %q", encoding)
+			}
+		})
+	}
+}
+
+func TestDecompress_EmptyBody(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNotModified,
+		Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+	}
+
+	if err := decompress(resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, _ := io.ReadAll(resp.Body); len(data) != 0 {
+		t.Errorf("Expected an empty body, got %q", data)
+	}
+}
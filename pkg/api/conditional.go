@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Bounds of the conditional request cache, which holds response bodies in memory
+const (
+	maxValidatedBodyBytes  = 1 << 20
+	maxValidatedTotalBytes = 16 << 20
+)
+
+// validatedResponse is a response body kept with the validators the API sent for it
+type validatedResponse struct {
+	url          string
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+// validatorCache remembers the ETag and Last-Modified validators of recent GET responses, so
+// that repeated reads of the same resource can be sent as conditional requests and a 304 Not
+// Modified answered from the kept body. Polling agents then spend less bandwidth and rate
+// limit on resources that have not changed. The least recently used bodies are dropped once
+// the cache grows past its size limit.
+type validatorCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	size    int
+}
+
+// newValidatorCache creates an empty conditional request cache
+func newValidatorCache() *validatorCache {
+	return &validatorCache{entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// lookup returns the kept response for a URL, marking it as recently used
+func (v *validatorCache) lookup(requestURL string) *validatedResponse {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	element, ok := v.entries[requestURL]
+	if !ok {
+		return nil
+	}
+	v.order.MoveToFront(element)
+	return element.Value.(*validatedResponse)
+}
+
+// setConditionalHeaders adds the validators of a kept response to a request
+func (r *validatedResponse) setConditionalHeaders(req *http.Request) {
+	if r.etag != "" {
+		req.Header.Set("If-None-Match", r.etag)
+	}
+	if r.lastModified != "" {
+		req.Header.Set("If-Modified-Since", r.lastModified)
+	}
+}
+
+// store keeps a response body with its validators, replacing any earlier one for the URL
+func (v *validatorCache) store(entry *validatedResponse) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.removeLocked(entry.url)
+	v.entries[entry.url] = v.order.PushFront(entry)
+	v.size += len(entry.body)
+
+	for v.size > maxValidatedTotalBytes {
+		oldest := v.order.Back()
+		v.removeLocked(oldest.Value.(*validatedResponse).url)
+	}
+}
+
+// remove drops the kept response for a URL, if any
+func (v *validatorCache) remove(requestURL string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.removeLocked(requestURL)
+}
+
+// removeLocked drops the kept response for a URL; the caller holds the lock
+func (v *validatorCache) removeLocked(requestURL string) {
+	element, ok := v.entries[requestURL]
+	if !ok {
+		return
+	}
+	v.order.Remove(element)
+	delete(v.entries, requestURL)
+	v.size -= len(element.Value.(*validatedResponse).body)
+}
+
+// resolve updates the cache from the response to a GET. A 304 answering a conditional request
+// is replaced by a 200 carrying the kept body; a successful response with validators is kept
+// for the next request. Other responses are returned as they are.
+func (v *validatorCache) resolve(
+	requestURL string, cached *validatedResponse, resp *http.Response,
+) (*http.Response, error) {
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return cached.response(resp), nil
+	case resp.StatusCode != http.StatusOK:
+		return resp, nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		v.remove(requestURL)
+		return resp, nil
+	}
+
+	// Read one byte past the limit to tell whether the body is too large to keep
+	original := resp.Body
+	data, err := io.ReadAll(io.LimitReader(original, maxValidatedBodyBytes+1))
+	if err != nil {
+		original.Close()
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(data) > maxValidatedBodyBytes {
+		v.remove(requestURL)
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), original), original}
+		return resp, nil
+	}
+	original.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	v.store(&validatedResponse{
+		url:          requestURL,
+		etag:         etag,
+		lastModified: lastModified,
+		header:       resp.Header.Clone(),
+		body:         data,
+	})
+	return resp, nil
+}
+
+// response builds a 200 response from the kept body, in reply to the request of a 304
+func (r *validatedResponse) response(notModified *http.Response) *http.Response {
+	header := r.header.Clone()
+	// A 304 carries the current validators and caching headers of the resource
+	for _, name := range []string{"ETag", "Last-Modified", "Cache-Control", "Expires", "Date"} {
+		if value := notModified.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       notModified.Request,
+	}
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_ConditionalRequests(t *testing.T) {
+	const body = `{"id":"app-1"}`
+
+	tests := []struct {
+		name            string
+		header          string
+		value           string
+		conditional     string
+		wantNotModified bool
+	}{
+		{name: "etag", header: "ETag", value: `"v1"`, conditional: "If-None-Match", wantNotModified: true},
+		{
+			name:            "last modified",
+			header:          "Last-Modified",
+			value:           "Wed, 01 Oct 2025 12:00:00 GMT",
+			conditional:     "If-Modified-Since",
+			wantNotModified: true,
+		},
+		{name: "no validators"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notModified atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.conditional != "" && r.Header.Get(tt.conditional) == tt.value {
+					notModified.Add(1)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				if tt.header != "" {
+					w.Header().Set(tt.header, tt.value)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, body)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			for i := 0; i < 3; i++ {
+				resp, err := client.Get(context.Background(), testPath)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				data, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusOK || string(data) != body {
+					t.Errorf("Request %d: expected 200 with the body, got %d %q", i, resp.StatusCode, data)
+				}
+				if got := resp.Header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("Request %d: expected the kept headers, got Content-Type %q", i, got)
+				}
+			}
+
+			want := int32(0)
+			if tt.wantNotModified {
+				want = 2
+			}
+			if got := notModified.Load(); got != want {
+				t.Errorf("Expected %d requests answered with 304, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestClient_ConditionalRequestsTokenChange(t *testing.T) {
+	var conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional.Add(1)
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(w, "{}")
+	}))
+	defer server.Close()
+
+	config := ClientConfig{APIToken: "first-token", BaseURL: server.URL}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	get := func() {
+		t.Helper()
+		resp, err := client.Get(context.Background(), testPath)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	get()
+	config.APIToken = "second-token"
+	if err := client.UpdateConfig(config); err != nil {
+		t.Fatalf("UpdateConfig() unexpected error: %v", err)
+	}
+	get()
+
+	if got := conditional.Load(); got != 0 {
+		t.Errorf("Expected no conditional requests after a token change, got %d", got)
+	}
+}
+
+func TestValidatorCache_Bounds(t *testing.T) {
+	cache := newValidatorCache()
+	large := []byte(strings.Repeat("x", maxValidatedBodyBytes))
+
+	for i := 0; i <= maxValidatedTotalBytes/maxValidatedBodyBytes; i++ {
+		cache.store(&validatedResponse{url: string(rune('a' + i)), etag: `"v"`, body: large})
+	}
+
+	if cache.size > maxValidatedTotalBytes {
+		t.Errorf("Expected at most %d bytes kept, got %d", maxValidatedTotalBytes, cache.size)
+	}
+	if cache.lookup("a") != nil {
+		t.Error("Expected the least recently used response to be dropped")
+	}
+	if cache.lookup(string(rune('a'+maxValidatedTotalBytes/maxValidatedBodyBytes))) == nil {
+		t.Error("Expected the most recent response to be kept")
+	}
+}
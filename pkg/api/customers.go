@@ -0,0 +1,492 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Customer list paging
+const (
+	// customersPageSize is the number of customers requested per page
+	customersPageSize = 100
+	// customerPageConcurrency is the number of pages fetched at once after the first
+	customerPageConcurrency = 4
+)
+
+// ErrCustomFieldsNotSupported is returned when the API does not keep a customer custom field,
+// such as when the field is not defined for the application
+var ErrCustomFieldsNotSupported = errors.New("customer custom field is not defined for the application")
+
+// CustomerService provides methods for interacting with customer APIs
+type CustomerService struct {
+	client *Client
+}
+
+// NewCustomerService creates a new CustomerService
+func NewCustomerService(client *Client) *CustomerService {
+	return &CustomerService{
+		client: client,
+	}
+}
+
+// CustomerList represents a list of customers. Truncated is set when the list stopped at
+// a requested limit before every customer had been fetched.
+type CustomerList struct {
+	Customers  []models.Customer `json:"customers"`
+	TotalCount int               `json:"total_count"`
+	Truncated  bool              `json:"truncated,omitempty"`
+}
+
+// CustomerFilter selects customers by their settings. Empty fields match every customer.
+type CustomerFilter struct {
+	Type           string     `json:"type,omitempty"`
+	LicenseType    string     `json:"license_type,omitempty"`
+	IsArchived     *bool      `json:"is_archived,omitempty"`
+	ChannelID      string     `json:"channel_id,omitempty"`
+	ExpiringBefore *time.Time `json:"expiring_before,omitempty"`
+}
+
+// Matches reports whether a customer passes every filter. Customers without an expiry never
+// match ExpiringBefore.
+func (f CustomerFilter) Matches(customer *models.Customer) bool {
+	switch {
+	case f.Type != "" && !strings.EqualFold(customer.Type, f.Type):
+		return false
+	case f.LicenseType != "" && !strings.EqualFold(customer.LicenseType, f.LicenseType):
+		return false
+	case f.IsArchived != nil && customer.IsArchived != *f.IsArchived:
+		return false
+	case f.ChannelID != "" && customer.ChannelID != f.ChannelID:
+		return false
+	case f.ExpiringBefore != nil && (customer.ExpiresAt == nil || !customer.ExpiresAt.Before(*f.ExpiringBefore)):
+		return false
+	}
+	return true
+}
+
+// query returns the filters the customers endpoint accepts as query parameters
+func (f CustomerFilter) query() url.Values {
+	params := url.Values{}
+	if f.Type != "" {
+		params.Set("customerType", f.Type)
+	}
+	if f.ChannelID != "" {
+		params.Set("channelId", f.ChannelID)
+	}
+	if f.IsArchived != nil {
+		params.Set("includeArchived", strconv.FormatBool(*f.IsArchived))
+	}
+	return params
+}
+
+// DownloadList represents the artifacts a customer can download from the download portal
+type DownloadList struct {
+	Downloads []models.Download `json:"downloads"`
+}
+
+// InstanceEventList represents the events of a customer's instance
+type InstanceEventList struct {
+	Events []models.InstanceEvent `json:"events"`
+}
+
+// updateCustomerRequest is the request body for updating a customer. The API replaces the
+// customer's settings, so every field is sent rather than only the changed ones, including the
+// entitlement values and install type flags an update leaves as they are.
+type updateCustomerRequest struct {
+	AppID                            string             `json:"appId"`
+	Name                             string             `json:"name"`
+	Email                            string             `json:"email,omitempty"`
+	ChannelID                        string             `json:"channelId"`
+	ExpiresAt                        *time.Time         `json:"expiresAt,omitempty"`
+	Type                             string             `json:"type"`
+	CustomFields                     map[string]string  `json:"customFields,omitempty"`
+	EntitlementValues                []entitlementValue `json:"entitlementValues,omitempty"`
+	IsGitOpsSupported                bool               `json:"isGitopsSupported"`
+	IsAirgapEnabled                  bool               `json:"isAirgapEnabled"`
+	IsSnapshotSupported              bool               `json:"isSnapshotSupported"`
+	IsKotsInstallEnabled             bool               `json:"isKotsInstallEnabled"`
+	IsEmbeddedClusterDownloadEnabled bool               `json:"isEmbeddedClusterDownloadEnabled"`
+	IsHelmVMDownloadEnabled          bool               `json:"isHelmVmDownloadEnabled"`
+	IsIdentityServiceSupported       bool               `json:"isIdentityServiceSupported"`
+	IsGeoaxisSupported               bool               `json:"isGeoaxisSupported"`
+	IsSupportBundleUploadEnabled     bool               `json:"isSupportBundleUploadEnabled"`
+}
+
+// entitlementValue is a customer's value for a license field, as sent when updating the customer
+type entitlementValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// entitlementValues lists a customer's entitlement values by license field name
+func entitlementValues(entitlements map[string]string) []entitlementValue {
+	values := make([]entitlementValue, 0, len(entitlements))
+	for _, name := range slices.Sorted(maps.Keys(entitlements)) {
+		values = append(values, entitlementValue{Name: name, Value: entitlements[name]})
+	}
+	return values
+}
+
+// ListCustomers retrieves all customers for an application
+func (s *CustomerService) ListCustomers(ctx context.Context, appID string) (*CustomerList, error) {
+	return s.ListCustomersUpTo(ctx, appID, 0)
+}
+
+// ListCustomersUpTo retrieves an application's customers, stopping once limit customers have
+// been fetched (0 means no limit). The first page reports the total, and the remaining pages
+// are then fetched concurrently.
+func (s *CustomerService) ListCustomersUpTo(ctx context.Context, appID string, limit int) (*CustomerList, error) {
+	return s.listCustomers(ctx, appID, url.Values{}, limit)
+}
+
+// listCustomers retrieves up to limit of an application's customers (0 means no limit),
+// sending query with every page request
+func (s *CustomerService) listCustomers(
+	ctx context.Context, appID string, query url.Values, limit int,
+) (*CustomerList, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+
+	s.client.logger.DebugContext(ctx, "Listing customers", "app_id", appID, "limit", limit)
+
+	first, err := s.customersPage(ctx, appID, query, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	total := max(first.TotalCustomers, len(first.Customers))
+	wanted := total
+	if limit > 0 {
+		wanted = min(total, limit)
+	}
+
+	// Responses without a total are not paginated
+	pages := [][]models.Customer{first.Customers}
+	if pageSize := len(first.Customers); pageSize > 0 && wanted > pageSize {
+		rest, err := s.customerPages(ctx, appID, query, (wanted+pageSize-1)/pageSize)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, rest...)
+	}
+
+	result := CustomerList{Customers: slices.Concat(pages...), TotalCount: total}
+	if result.Customers == nil {
+		result.Customers = []models.Customer{}
+	}
+	if len(result.Customers) > wanted {
+		result.Customers = result.Customers[:wanted]
+	}
+	result.Truncated = len(result.Customers) < total
+
+	s.client.logger.DebugContext(ctx, "Successfully listed customers",
+		"app_id", appID,
+		"count", len(result.Customers),
+		"truncated", result.Truncated)
+
+	return &result, nil
+}
+
+// customerPages fetches pages 1 through count-1 concurrently, returning them in order
+func (s *CustomerService) customerPages(
+	ctx context.Context, appID string, query url.Values, count int,
+) ([][]models.Customer, error) {
+	pages := make([][]models.Customer, count-1)
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(customerPageConcurrency)
+	for page := 1; page < count; page++ {
+		group.Go(func() error {
+			envelope, err := s.customersPage(groupCtx, appID, query, page)
+			if err != nil {
+				return err
+			}
+			pages[page-1] = envelope.Customers
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// customersPage fetches one page of an application's customers, with query added to the
+// paging parameters
+func (s *CustomerService) customersPage(
+	ctx context.Context, appID string, query url.Values, page int,
+) (*customersResponse, error) {
+	params := maps.Clone(query)
+	params.Set("pageSize", strconv.Itoa(customersPageSize))
+	params.Set("currentPage", strconv.Itoa(page))
+	path := fmt.Sprintf("/vendor/v3/app/%s/customers?%s", url.PathEscape(appID), params.Encode())
+
+	var envelope customersResponse
+	if err := s.client.getJSON(ctx, path, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to list customers: %w", err)
+	}
+	return &envelope, nil
+}
+
+// SearchCustomers returns the customers of an application whose name, email, or ID contains
+// the query (ignoring case), using client-side filtering of at most scanLimit customers
+// (0 means every customer). The result is truncated when customers were left unsearched.
+func (s *CustomerService) SearchCustomers(
+	ctx context.Context,
+	appID, query string,
+	scanLimit int,
+) (*CustomerList, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	s.client.logger.DebugContext(ctx, "Searching customers", "app_id", appID, "query", query)
+
+	all, err := s.ListCustomersUpTo(ctx, appID, scanLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customers for search: %w", err)
+	}
+
+	matches := []models.Customer{}
+	for i := range all.Customers {
+		customer := &all.Customers[i]
+		if models.MatchesQuery(query, customer.Name, customer.Email, customer.ID) {
+			matches = append(matches, *customer)
+		}
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully searched customers",
+		"query", query,
+		"scanned", len(all.Customers),
+		"filtered_count", len(matches),
+		"truncated", all.Truncated)
+
+	return &CustomerList{Customers: matches, TotalCount: len(matches), Truncated: all.Truncated}, nil
+}
+
+// FilterCustomers returns the customers of an application matching filter, reading at most
+// scanLimit customers (0 means every customer). Filters the API accepts are sent with the
+// request to narrow what is fetched, and every filter is applied again to the response, so
+// the result is correct whether or not the API honored them. The result is truncated when
+// customers were left unread.
+func (s *CustomerService) FilterCustomers(
+	ctx context.Context,
+	appID string,
+	filter CustomerFilter,
+	scanLimit int,
+) (*CustomerList, error) {
+	s.client.logger.DebugContext(ctx, "Filtering customers", "app_id", appID, "filter", filter)
+
+	all, err := s.listCustomers(ctx, appID, filter.query(), scanLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []models.Customer{}
+	for i := range all.Customers {
+		if filter.Matches(&all.Customers[i]) {
+			matches = append(matches, all.Customers[i])
+		}
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully filtered customers",
+		"scanned", len(all.Customers),
+		"filtered_count", len(matches),
+		"truncated", all.Truncated)
+
+	return &CustomerList{Customers: matches, TotalCount: len(matches), Truncated: all.Truncated}, nil
+}
+
+// Archive archives a customer, hiding it from customer lists and preventing its license
+// from being used for new installations
+func (s *CustomerService) Archive(ctx context.Context, customerID string) error {
+	return s.setArchived(ctx, customerID, "archive")
+}
+
+// Unarchive restores an archived customer
+func (s *CustomerService) Unarchive(ctx context.Context, customerID string) error {
+	return s.setArchived(ctx, customerID, "unarchive")
+}
+
+// setArchived performs a customer archive or unarchive action
+func (s *CustomerService) setArchived(ctx context.Context, customerID, action string) error {
+	if customerID == "" {
+		return fmt.Errorf("customer ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/customer/%s/%s", url.PathEscape(customerID), action)
+
+	s.client.logger.DebugContext(ctx, "Changing customer archive state", "customer_id", customerID, "action", action)
+
+	if err := s.client.postJSON(ctx, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to %s customer: %w", action, err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully changed customer archive state",
+		"customer_id", customerID,
+		"action", action)
+
+	return nil
+}
+
+// Update saves a customer's name, email, channel, expiration, license type, custom fields,
+// entitlement values, and install type flags, returning the customer as stored by the API. A
+// nil ExpiresAt removes the expiration.
+func (s *CustomerService) Update(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	if customer.ID == "" {
+		return nil, fmt.Errorf("customer ID is required")
+	}
+
+	licenseType := customer.LicenseType
+	if licenseType == "" {
+		licenseType = customer.Type
+	}
+	request := updateCustomerRequest{
+		AppID:                            customer.ApplicationID,
+		Name:                             customer.Name,
+		Email:                            customer.Email,
+		ChannelID:                        customer.ChannelID,
+		ExpiresAt:                        customer.ExpiresAt,
+		Type:                             licenseType,
+		CustomFields:                     customer.CustomFields,
+		EntitlementValues:                entitlementValues(customer.Entitlements),
+		IsGitOpsSupported:                customer.IsGitOpsSupported,
+		IsAirgapEnabled:                  customer.IsAirgapEnabled,
+		IsSnapshotSupported:              customer.IsSnapshotSupported,
+		IsKotsInstallEnabled:             customer.IsKotsInstallEnabled,
+		IsEmbeddedClusterDownloadEnabled: customer.IsEmbeddedClusterDownloadEnabled,
+		IsHelmVMDownloadEnabled:          customer.IsHelmVMDownloadEnabled,
+		IsIdentityServiceSupported:       customer.IsIdentityServiceSupported,
+		IsGeoaxisSupported:               customer.IsGeoaxisSupported,
+		IsSupportBundleUploadEnabled:     customer.IsSupportBundleUploadEnabled,
+	}
+	path := fmt.Sprintf("/vendor/v3/customer/%s", url.PathEscape(customer.ID))
+
+	s.client.logger.DebugContext(ctx, "Updating customer", "customer_id", customer.ID)
+
+	var envelope customerResponse
+	if err := s.client.putJSON(ctx, path, request, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully updated customer", "customer_id", customer.ID)
+
+	return &envelope.Customer, nil
+}
+
+// UpdateCustomFields saves a customer with the given custom fields set, keeping its other
+// custom fields and settings. Custom fields must be defined for the application in the Vendor
+// Portal; when the API does not keep a field, ErrCustomFieldsNotSupported is returned.
+func (s *CustomerService) UpdateCustomFields(
+	ctx context.Context, customer *models.Customer, fields map[string]string,
+) (*models.Customer, error) {
+	updated := *customer
+	updated.CustomFields = maps.Clone(customer.CustomFields)
+	if updated.CustomFields == nil {
+		updated.CustomFields = make(map[string]string, len(fields))
+	}
+	maps.Copy(updated.CustomFields, fields)
+
+	stored, err := s.Update(ctx, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range fields {
+		if stored.CustomFields[name] != value {
+			return nil, fmt.Errorf("%w: custom field %q of customer %s was not saved",
+				ErrCustomFieldsNotSupported, name, customer.ID)
+		}
+	}
+	return stored, nil
+}
+
+// DownloadLicense retrieves a customer's license file, the YAML document the customer installs
+// the application with
+func (s *CustomerService) DownloadLicense(ctx context.Context, appID, customerID string) (string, error) {
+	if appID == "" {
+		return "", fmt.Errorf("application ID is required")
+	}
+	if customerID == "" {
+		return "", fmt.Errorf("customer ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s/customer/%s/license-download",
+		url.PathEscape(appID), url.PathEscape(customerID))
+
+	s.client.logger.DebugContext(ctx, "Downloading customer license", "app_id", appID, "customer_id", customerID)
+
+	license, err := s.client.getText(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to download license: %w", err)
+	}
+	return license, nil
+}
+
+// ListDownloads retrieves the artifacts a customer can download from the download portal,
+// such as their license, airgap bundles, and installers
+func (s *CustomerService) ListDownloads(ctx context.Context, customerID string) (*DownloadList, error) {
+	if customerID == "" {
+		return nil, fmt.Errorf("customer ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/customer/%s/downloads", url.PathEscape(customerID))
+
+	s.client.logger.DebugContext(ctx, "Listing customer downloads", "customer_id", customerID)
+
+	var result DownloadList
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to list customer downloads: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed customer downloads",
+		"customer_id", customerID,
+		"count", len(result.Downloads))
+
+	return &result, nil
+}
+
+// ListInstanceEvents retrieves the check-ins, version changes, and status changes of a
+// customer's instance
+func (s *CustomerService) ListInstanceEvents(
+	ctx context.Context, customerID, instanceID string,
+) (*InstanceEventList, error) {
+	if customerID == "" {
+		return nil, fmt.Errorf("customer ID is required")
+	}
+	if instanceID == "" {
+		return nil, fmt.Errorf("instance ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/customer/%s/instance/%s/events",
+		url.PathEscape(customerID), url.PathEscape(instanceID))
+
+	s.client.logger.DebugContext(ctx, "Listing instance events",
+		"customer_id", customerID,
+		"instance_id", instanceID)
+
+	var result InstanceEventList
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to list instance events: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed instance events",
+		"customer_id", customerID,
+		"instance_id", instanceID,
+		"count", len(result.Events))
+
+	return &result, nil
+}
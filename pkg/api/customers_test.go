@@ -0,0 +1,727 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestCustomerService_ListCustomers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/customers" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"customers": [{"id": "customer-1", "name": "Acme"}, {"id": "customer-2", "name": "Globex"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	tests := []struct {
+		name          string
+		appID         string
+		expectError   bool
+		expectedCount int
+	}{
+		{name: "list customers", appID: "app-1", expectedCount: 2},
+		{name: "unknown application", appID: "missing", expectError: true},
+		{name: "missing application ID", appID: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListCustomers(context.Background(), tt.appID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Customers) != tt.expectedCount {
+				t.Errorf("Expected %d customers, got %d", tt.expectedCount, len(result.Customers))
+			}
+		})
+	}
+}
+
+func TestCustomerService_ArchiveUnarchive(t *testing.T) {
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		if r.URL.Path == "/vendor/v3/customer/missing/archive" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	tests := []struct {
+		name        string
+		action      func(ctx context.Context, customerID string) error
+		customerID  string
+		wantPath    string
+		expectError bool
+	}{
+		{
+			name:       "archive",
+			action:     service.Archive,
+			customerID: "customer-1",
+			wantPath:   "/vendor/v3/customer/customer-1/archive",
+		},
+		{
+			name:       "unarchive",
+			action:     service.Unarchive,
+			customerID: "customer-1",
+			wantPath:   "/vendor/v3/customer/customer-1/unarchive",
+		},
+		{name: "unknown customer", action: service.Archive, customerID: "missing", expectError: true},
+		{name: "missing customer ID", action: service.Unarchive, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMethod, gotPath = "", ""
+			err := tt.action(context.Background(), tt.customerID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if gotMethod != http.MethodPost || gotPath != tt.wantPath {
+				t.Errorf("Expected POST %s, got %s %s", tt.wantPath, gotMethod, gotPath)
+			}
+		})
+	}
+}
+
+func TestCustomerService_Update(t *testing.T) {
+	var gotBody updateCustomerRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/vendor/v3/customer/customer-1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		gotBody = updateCustomerRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		fmt.Fprintf(w, `{"customer": {"id": "customer-1", "name": %q, "channelId": %q}}`, gotBody.Name, gotBody.ChannelID)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	expiresAt := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		customer    models.Customer
+		wantBody    updateCustomerRequest
+		expectError bool
+	}{
+		{
+			name: "update customer",
+			customer: models.Customer{
+				ID: "customer-1", ApplicationID: "app-1", Name: "Acme Corp", ChannelID: "channel-2",
+				ExpiresAt: &expiresAt, Type: models.CustomerTypeTrial, LicenseType: models.LicenseTypePaid,
+			},
+			wantBody: updateCustomerRequest{
+				AppID: "app-1", Name: "Acme Corp", ChannelID: "channel-2", ExpiresAt: &expiresAt, Type: "paid",
+			},
+		},
+		{
+			name: "license type falls back to customer type",
+			customer: models.Customer{
+				ID: "customer-1", ApplicationID: "app-1", Name: "Acme", Email: "ops@acme.example",
+				ChannelID: "channel-1", Type: models.CustomerTypeTrial,
+			},
+			wantBody: updateCustomerRequest{
+				AppID: "app-1", Name: "Acme", Email: "ops@acme.example", ChannelID: "channel-1", Type: "trial",
+			},
+		},
+		{name: "unknown customer", customer: models.Customer{ID: "missing"}, expectError: true},
+		{name: "missing customer ID", customer: models.Customer{Name: "Acme"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.Update(context.Background(), &tt.customer)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(gotBody, tt.wantBody) {
+				t.Errorf("Expected request body %+v, got %+v", tt.wantBody, gotBody)
+			}
+			if result.ID != tt.customer.ID || result.Name != tt.customer.Name {
+				t.Errorf("Expected the updated customer in the result, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestCustomerService_UpdateCustomFields(t *testing.T) {
+	var gotFields map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body updateCustomerRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		gotFields = body.CustomFields
+
+		// Only fields defined for the application are kept
+		stored := map[string]string{}
+		for name, value := range body.CustomFields {
+			if name != "undefined" {
+				stored[name] = value
+			}
+		}
+		if err := json.NewEncoder(w).Encode(customerResponse{
+			Customer: models.Customer{ID: "customer-1", Name: body.Name, CustomFields: stored},
+		}); err != nil {
+			t.Errorf("Failed to encode customer: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	customer := &models.Customer{
+		ID: "customer-1", ApplicationID: "app-1", Name: "Acme", CustomFields: map[string]string{"region": "emea"},
+	}
+
+	t.Run("kept fields", func(t *testing.T) {
+		result, err := service.UpdateCustomFields(context.Background(), customer,
+			map[string]string{models.CustomerNotesField: "Prefers email"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := map[string]string{"region": "emea", models.CustomerNotesField: "Prefers email"}
+		if !reflect.DeepEqual(gotFields, want) {
+			t.Errorf("Expected custom fields %v to be sent, got %v", want, gotFields)
+		}
+		if result.Notes() != "Prefers email" {
+			t.Errorf("Expected the stored notes in the result, got %+v", result)
+		}
+		if len(customer.CustomFields) != 1 {
+			t.Errorf("Expected the given customer to be left unchanged, got %v", customer.CustomFields)
+		}
+	})
+
+	t.Run("undefined field", func(t *testing.T) {
+		_, err := service.UpdateCustomFields(context.Background(), customer, map[string]string{"undefined": "x"})
+		if !errors.Is(err, ErrCustomFieldsNotSupported) {
+			t.Errorf("Expected ErrCustomFieldsNotSupported, got %v", err)
+		}
+	})
+}
+
+func TestCustomerService_UpdateKeepsSettings(t *testing.T) {
+	// The API replaces the customer with what is sent, so anything left out is reset
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body updateCustomerRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		stored := models.Customer{
+			ID: "customer-1", ApplicationID: body.AppID, Name: body.Name, ChannelID: body.ChannelID,
+			Type: body.Type, CustomFields: body.CustomFields, Entitlements: map[string]string{},
+			IsGitOpsSupported:                body.IsGitOpsSupported,
+			IsAirgapEnabled:                  body.IsAirgapEnabled,
+			IsSnapshotSupported:              body.IsSnapshotSupported,
+			IsKotsInstallEnabled:             body.IsKotsInstallEnabled,
+			IsEmbeddedClusterDownloadEnabled: body.IsEmbeddedClusterDownloadEnabled,
+			IsHelmVMDownloadEnabled:          body.IsHelmVMDownloadEnabled,
+			IsIdentityServiceSupported:       body.IsIdentityServiceSupported,
+			IsGeoaxisSupported:               body.IsGeoaxisSupported,
+			IsSupportBundleUploadEnabled:     body.IsSupportBundleUploadEnabled,
+		}
+		for _, value := range body.EntitlementValues {
+			stored.Entitlements[value.Name] = value.Value
+		}
+		if err := json.NewEncoder(w).Encode(customerResponse{Customer: stored}); err != nil {
+			t.Errorf("Failed to encode customer: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	customer := &models.Customer{
+		ID: "customer-1", ApplicationID: "app-1", Name: "Acme", ChannelID: "channel-1", Type: "paid",
+		Entitlements:                     map[string]string{"seat_count": "10", "tier": "gold"},
+		IsGitOpsSupported:                true,
+		IsAirgapEnabled:                  true,
+		IsSnapshotSupported:              true,
+		IsKotsInstallEnabled:             true,
+		IsEmbeddedClusterDownloadEnabled: true,
+		IsHelmVMDownloadEnabled:          true,
+		IsIdentityServiceSupported:       true,
+		IsGeoaxisSupported:               true,
+		IsSupportBundleUploadEnabled:     true,
+	}
+
+	result, err := service.UpdateCustomFields(context.Background(), customer,
+		map[string]string{models.CustomerTagsField: "strategic"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := *customer
+	want.CustomFields = map[string]string{models.CustomerTagsField: "strategic"}
+	if !reflect.DeepEqual(*result, want) {
+		t.Errorf("Expected the entitlements and install type flags to survive the update,\nwant %+v\ngot  %+v",
+			want, *result)
+	}
+}
+
+// newPagedCustomersServer serves total customers for app-1 in pages of the requested size,
+// recording the pages requested
+func newPagedCustomersServer(t *testing.T, total int, requested *[]int, mu *sync.Mutex) *CustomerService {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("currentPage"))
+		mu.Lock()
+		*requested = append(*requested, page)
+		mu.Unlock()
+
+		customers := []models.Customer{}
+		for i := page * pageSize; i < min(total, (page+1)*pageSize); i++ {
+			customers = append(customers, models.Customer{
+				ID:    fmt.Sprintf("customer-%03d", i),
+				Name:  fmt.Sprintf("Customer %d", i),
+				Email: fmt.Sprintf("ops@customer%d.example", i),
+			})
+		}
+		if err := json.NewEncoder(w).Encode(customersResponse{Customers: customers, TotalCustomers: total}); err != nil {
+			t.Errorf("Failed to encode customers: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return NewCustomerService(client)
+}
+
+func TestCustomerService_ListCustomersUpTo(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		limit         int
+		wantCount     int
+		wantPages     int
+		wantTruncated bool
+	}{
+		{name: "single page", total: 40, wantCount: 40, wantPages: 1},
+		{name: "every page", total: 350, wantCount: 350, wantPages: 4},
+		{name: "limit within the first page", total: 350, limit: 50, wantCount: 50, wantPages: 1, wantTruncated: true},
+		{name: "limit across pages", total: 350, limit: 150, wantCount: 150, wantPages: 2, wantTruncated: true},
+		{name: "limit above the total", total: 350, limit: 1000, wantCount: 350, wantPages: 4},
+		{name: "no customers", total: 0, wantCount: 0, wantPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var requested []int
+			service := newPagedCustomersServer(t, tt.total, &requested, &mu)
+
+			result, err := service.ListCustomersUpTo(context.Background(), "app-1", tt.limit)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(result.Customers) != tt.wantCount {
+				t.Errorf("Expected %d customers, got %d", tt.wantCount, len(result.Customers))
+			}
+			if result.TotalCount != tt.total {
+				t.Errorf("Expected total count %d, got %d", tt.total, result.TotalCount)
+			}
+			if result.Truncated != tt.wantTruncated {
+				t.Errorf("Expected truncated = %t, got %t", tt.wantTruncated, result.Truncated)
+			}
+			for i := range result.Customers {
+				if want := fmt.Sprintf("customer-%03d", i); result.Customers[i].ID != want {
+					t.Fatalf("Expected customers in page order, got %s at %d", result.Customers[i].ID, i)
+				}
+			}
+			if len(requested) != tt.wantPages {
+				t.Errorf("Expected %d pages to be requested, got %v", tt.wantPages, requested)
+			}
+		})
+	}
+}
+
+func TestCustomerService_SearchCustomers(t *testing.T) {
+	var mu sync.Mutex
+	var requested []int
+	service := newPagedCustomersServer(t, 350, &requested, &mu)
+
+	tests := []struct {
+		name          string
+		query         string
+		scanLimit     int
+		wantIDs       []string
+		wantTruncated bool
+		expectError   bool
+	}{
+		{name: "match on a later page", query: "Customer 342", wantIDs: []string{"customer-342"}},
+		{name: "match on email", query: "OPS@CUSTOMER7.", wantIDs: []string{"customer-007"}},
+		{name: "match ignoring diacritics and spacing", query: " Cüstomer  342", wantIDs: []string{"customer-342"}},
+		{
+			name:          "match beyond the scan limit",
+			query:         "customer 342",
+			scanLimit:     200,
+			wantIDs:       []string{},
+			wantTruncated: true,
+		},
+		{name: "empty query", query: " ", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.SearchCustomers(context.Background(), "app-1", tt.query, tt.scanLimit)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ids := []string{}
+			for _, customer := range result.Customers {
+				ids = append(ids, customer.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected customers %v, got %v", tt.wantIDs, ids)
+			}
+			if result.Truncated != tt.wantTruncated {
+				t.Errorf("Expected truncated = %t, got %t", tt.wantTruncated, result.Truncated)
+			}
+		})
+	}
+}
+
+func TestCustomerService_FilterCustomers(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		// Filters are ignored, as by an API that does not support them
+		fmt.Fprint(w, `{"totalCustomers": 3, "customers": [
+			{"id": "customer-1", "type": "paid", "licenseType": "paid", "channelId": "channel-1",
+			 "expiresAt": "2025-06-30T00:00:00Z"},
+			{"id": "customer-2", "type": "trial", "licenseType": "trial", "channelId": "channel-2",
+			 "expiresAt": "2025-01-31T00:00:00Z"},
+			{"id": "customer-3", "type": "paid", "licenseType": "paid", "channelId": "channel-1", "isArchived": true}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	archived := true
+	expiry := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    CustomerFilter
+		wantIDs   []string
+		wantQuery map[string]string
+	}{
+		{
+			name:      "no filter",
+			wantIDs:   []string{"customer-1", "customer-2", "customer-3"},
+			wantQuery: map[string]string{"customerType": "", "channelId": "", "includeArchived": ""},
+		},
+		{
+			name:      "type and channel passed through",
+			filter:    CustomerFilter{Type: "paid", ChannelID: "channel-1"},
+			wantIDs:   []string{"customer-1", "customer-3"},
+			wantQuery: map[string]string{"customerType": "paid", "channelId": "channel-1"},
+		},
+		{
+			name:      "archived",
+			filter:    CustomerFilter{IsArchived: &archived},
+			wantIDs:   []string{"customer-3"},
+			wantQuery: map[string]string{"includeArchived": "true"},
+		},
+		{
+			name:    "license type filtered locally",
+			filter:  CustomerFilter{LicenseType: "TRIAL"},
+			wantIDs: []string{"customer-2"},
+		},
+		{
+			name:    "expiring before",
+			filter:  CustomerFilter{ExpiringBefore: &expiry},
+			wantIDs: []string{"customer-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.FilterCustomers(context.Background(), "app-1", tt.filter, 0)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ids := []string{}
+			for _, customer := range result.Customers {
+				ids = append(ids, customer.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected customers %v, got %v", tt.wantIDs, ids)
+			}
+			if result.TotalCount != len(tt.wantIDs) {
+				t.Errorf("Expected total count %d, got %d", len(tt.wantIDs), result.TotalCount)
+			}
+			for name, want := range tt.wantQuery {
+				if got := query.Get(name); got != want {
+					t.Errorf("Expected query parameter %s = '%s', got '%s'", name, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomerService_ListDownloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/customer/customer-1/downloads" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"downloads": [
+			{"id": "download-1", "type": "license", "name": "Acme license"},
+			{"id": "download-2", "type": "airgap_bundle", "name": "1.2.0 airgap bundle", "releaseSequence": 3,
+			 "status": "building"}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	tests := []struct {
+		name        string
+		customerID  string
+		expectError bool
+		wantIDs     []string
+	}{
+		{name: "list downloads", customerID: "customer-1", wantIDs: []string{"download-1", "download-2"}},
+		{name: "unknown customer", customerID: "missing", expectError: true},
+		{name: "missing customer ID", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListDownloads(context.Background(), tt.customerID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ids := []string{}
+			for _, download := range result.Downloads {
+				ids = append(ids, download.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected downloads %v, got %v", tt.wantIDs, ids)
+			}
+			if result.Downloads[1].Status != models.DownloadStatusBuilding {
+				t.Errorf("Expected the airgap bundle to be building, got %q", result.Downloads[1].Status)
+			}
+		})
+	}
+}
+
+func TestCustomerService_ListInstanceEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/customer/customer-1/instance/instance-1/events" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"events": [
+			{"type": "version_change", "occurredAt": "2025-01-10T12:00:00Z", "versionLabel": "1.2.0",
+			 "releaseSequence": 3, "previousVersionLabel": "1.1.0", "previousReleaseSequence": 2},
+			{"type": "checkin", "occurredAt": "2025-01-11T12:00:00Z", "versionLabel": "1.2.0", "releaseSequence": 3}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	tests := []struct {
+		name        string
+		customerID  string
+		instanceID  string
+		expectError bool
+		wantTypes   []string
+	}{
+		{
+			name:       "list events",
+			customerID: "customer-1",
+			instanceID: "instance-1",
+			wantTypes:  []string{models.InstanceEventVersionChange, models.InstanceEventCheckin},
+		},
+		{name: "unknown instance", customerID: "customer-1", instanceID: "instance-9", expectError: true},
+		{name: "missing customer ID", instanceID: "instance-1", expectError: true},
+		{name: "missing instance ID", customerID: "customer-1", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListInstanceEvents(context.Background(), tt.customerID, tt.instanceID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			types := []string{}
+			for _, event := range result.Events {
+				types = append(types, event.Type)
+			}
+			if !slices.Equal(types, tt.wantTypes) {
+				t.Errorf("Expected events %v, got %v", tt.wantTypes, types)
+			}
+			if !result.Events[0].IsUpgrade() {
+				t.Errorf("Expected the version change to be an upgrade, got %+v", result.Events[0])
+			}
+		})
+	}
+}
+
+func TestCustomerService_DownloadLicense(t *testing.T) {
+	const license = "apiVersion: kots.io/v1beta1\nkind: License\nspec:\n  customerName: Acme\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/customer/customer-1/license-download" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "customer not found"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-yaml")
+		fmt.Fprint(w, license)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewCustomerService(client)
+
+	tests := []struct {
+		name        string
+		appID       string
+		customerID  string
+		expectError string
+	}{
+		{name: "download license", appID: "app-1", customerID: "customer-1"},
+		{name: "unknown customer", appID: "app-1", customerID: "missing", expectError: "customer not found"},
+		{name: "missing customer ID", appID: "app-1", expectError: "customer ID is required"},
+		{name: "missing application ID", customerID: "customer-1", expectError: "application ID is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.DownloadLicense(context.Background(), tt.appID, tt.customerID)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing '%s', got %v", tt.expectError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != license {
+				t.Errorf("Expected the license file, got '%s'", result)
+			}
+		})
+	}
+}
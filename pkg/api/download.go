@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// downloadPattern names the temporary files downloads are streamed to
+const downloadPattern = "replicated-download-*"
+
+// FileBody is a response body streamed to a temporary file rather than held in memory, for
+// large responses such as release archives and airgap metadata. The caller owns the file and
+// removes it with Remove once done with it.
+type FileBody struct {
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// Open opens the downloaded file for reading
+func (f *FileBody) Open() (*os.File, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open download: %w", err)
+	}
+	return file, nil
+}
+
+// Remove deletes the downloaded file
+func (f *FileBody) Remove() error {
+	if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove download: %w", err)
+	}
+	return nil
+}
+
+// Download performs a GET request and streams the body of a successful response to a
+// temporary file in dir, or the default directory for temporary files when dir is empty, so
+// the body is never held in memory. The file is removed if the download fails.
+func (c *Client) Download(ctx context.Context, path, dir string) (*FileBody, error) {
+	resp, err := c.stream(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= HTTPErrorThreshold {
+		return nil, fmt.Errorf("API error: %w", c.ConvertHTTPError(resp))
+	}
+
+	file, err := os.CreateTemp(dir, downloadPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download file: %w", err)
+	}
+	body := &FileBody{Path: file.Name(), ContentType: resp.Header.Get("Content-Type")}
+
+	body.Size, err = io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = body.Remove()
+		return nil, fmt.Errorf("failed to download response body: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "Downloaded API response", "path", path, "file", body.Path, "size", body.Size)
+	return body, nil
+}
+
+// stream performs a GET request whose body is read as it arrives, however long that takes.
+// The request goes out through a copy of the HTTP client without its timeout, which would
+// cut off a large body partway, so only ctx's deadline bounds it. It goes to the endpoint
+// reads currently use, once: a hedged second attempt would download the body twice.
+func (c *Client) stream(ctx context.Context, path string) (*http.Response, error) {
+	config, httpClient := c.snapshot()
+	streamClient := *httpClient
+	streamClient.Timeout = 0
+
+	base := config.BaseURL
+	if state := c.failoverState(); state != nil && state.usingFallback() {
+		base = config.FallbackURL
+	}
+	return c.send(ctx, &streamClient, base, http.MethodGet, path, "", nil, true)
+}
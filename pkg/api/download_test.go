@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Download(t *testing.T) {
+	archive := strings.Repeat("release archive contents\n", 1000)
+
+	tests := []struct {
+		name        string
+		status      int
+		compress    bool
+		expectError bool
+	}{
+		{name: "download", status: http.StatusOK},
+		{name: "compressed download", status: http.StatusOK, compress: true},
+		{name: "not found", status: http.StatusNotFound, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/gzip")
+				if tt.compress {
+					w.Header().Set("Content-Encoding", "gzip")
+				}
+				w.WriteHeader(tt.status)
+				if tt.compress {
+					_, _ = w.Write(gzipped(t, archive))
+					return
+				}
+				_, _ = io.WriteString(w, archive)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			dir := t.TempDir()
+			body, err := client.Download(context.Background(), testPath, dir)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+					t.Errorf("Expected no files left behind, got %d", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if body.Size != int64(len(archive)) || body.ContentType != "application/gzip" {
+				t.Errorf("Unexpected download: %+v", body)
+			}
+
+			file, err := body.Open()
+			if err != nil {
+				t.Fatalf("Failed to open download: %v", err)
+			}
+			data, _ := io.ReadAll(file)
+			file.Close()
+			if string(data) != archive {
+				t.Errorf("Expected the downloaded file to hold the body, got %d bytes", len(data))
+			}
+
+			if err := body.Remove(); err != nil {
+				t.Fatalf("Failed to remove download: %v", err)
+			}
+			if _, err := os.Stat(body.Path); !os.IsNotExist(err) {
+				t.Errorf("Expected the download to be removed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadStreams(t *testing.T) {
+	const downloadPath = "/vendor/v3/app/app-1/release/1/download"
+	half := strings.Repeat("release archive contents\n", 1000)
+
+	// The body arrives in two halves, the second after the client's timeout has passed
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = io.WriteString(w, half)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(200 * time.Millisecond):
+		}
+		_, _ = io.WriteString(w, half)
+	}))
+	defer server.Close()
+
+	harFile := filepath.Join(t.TempDir(), "traffic.har")
+	client, err := NewClient(ClientConfig{
+		APIToken: "test-token",
+		BaseURL:  server.URL,
+		Timeout:  50 * time.Millisecond,
+		HARFile:  harFile,
+		Hedging: HedgePolicy{
+			Enabled: true,
+			Delays:  map[string]time.Duration{"release": 10 * time.Millisecond},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	body, err := client.Download(context.Background(), downloadPath, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected the download to outlast the client timeout, got %v", err)
+	}
+	defer body.Remove()
+
+	if body.Size != int64(2*len(half)) {
+		t.Errorf("Expected %d bytes, got %d", 2*len(half), body.Size)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected a single request, got %d", requests.Load())
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Failed to close client: %v", err)
+	}
+	if data, err := os.ReadFile(harFile); err != nil || !strings.Contains(string(data), downloadPath) ||
+		strings.Contains(string(data), "release archive contents") {
+		t.Errorf("Expected the download captured without its body, got %d bytes (%v)", len(data), err)
+	}
+
+	// A deadline on the caller's context still bounds the download
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Download(ctx, downloadPath, t.TempDir()); err == nil {
+		t.Error("Expected the download to stop at the context's deadline")
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// EntitlementService provides methods for interacting with license field and customer entitlement APIs
+type EntitlementService struct {
+	client *Client
+}
+
+// NewEntitlementService creates a new EntitlementService
+func NewEntitlementService(client *Client) *EntitlementService {
+	return &EntitlementService{
+		client: client,
+	}
+}
+
+// LicenseFieldList represents the license fields defined for an application
+type LicenseFieldList struct {
+	LicenseFields []models.LicenseField `json:"licenseFields"`
+}
+
+// CustomerEntitlements represents the entitlement values assigned to a customer
+type CustomerEntitlements struct {
+	CustomerID   string                    `json:"customerId"`
+	Entitlements []models.EntitlementValue `json:"entitlements"`
+}
+
+// setEntitlementRequest is the request body for updating a single entitlement value
+type setEntitlementRequest struct {
+	Value string `json:"value"`
+}
+
+// ListLicenseFields retrieves the license fields defined for an application
+func (s *EntitlementService) ListLicenseFields(ctx context.Context, appID string) (*LicenseFieldList, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s/license-fields", url.PathEscape(appID))
+
+	s.client.logger.DebugContext(ctx, "Listing license fields", "app_id", appID)
+
+	var result LicenseFieldList
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to list license fields: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed license fields",
+		"app_id", appID,
+		"count", len(result.LicenseFields))
+
+	return &result, nil
+}
+
+// GetCustomerEntitlements retrieves the entitlement values assigned to a customer
+func (s *EntitlementService) GetCustomerEntitlements(
+	ctx context.Context,
+	customerID string,
+) (*CustomerEntitlements, error) {
+	if customerID == "" {
+		return nil, fmt.Errorf("customer ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/customer/%s/entitlements", url.PathEscape(customerID))
+
+	s.client.logger.DebugContext(ctx, "Getting customer entitlements", "customer_id", customerID)
+
+	var result CustomerEntitlements
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to get customer entitlements: %w", err)
+	}
+	if result.CustomerID == "" {
+		result.CustomerID = customerID
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully retrieved customer entitlements",
+		"customer_id", customerID,
+		"count", len(result.Entitlements))
+
+	return &result, nil
+}
+
+// SetCustomerEntitlement updates a single entitlement value for a customer and returns the stored value
+func (s *EntitlementService) SetCustomerEntitlement(
+	ctx context.Context,
+	customerID, fieldName, value string,
+) (*models.EntitlementValue, error) {
+	if customerID == "" {
+		return nil, fmt.Errorf("customer ID is required")
+	}
+	if fieldName == "" {
+		return nil, fmt.Errorf("license field name is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/customer/%s/entitlements/%s",
+		url.PathEscape(customerID), url.PathEscape(fieldName))
+
+	s.client.logger.DebugContext(ctx, "Setting customer entitlement",
+		"customer_id", customerID,
+		"field", fieldName)
+
+	var result models.EntitlementValue
+	if err := s.client.putJSON(ctx, path, setEntitlementRequest{Value: value}, &result); err != nil {
+		return nil, fmt.Errorf("failed to set customer entitlement: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully set customer entitlement",
+		"customer_id", customerID,
+		"field", result.Name)
+
+	return &result, nil
+}
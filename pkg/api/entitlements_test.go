@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestEntitlementService(t *testing.T, handler http.HandlerFunc) *EntitlementService {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return NewEntitlementService(client)
+}
+
+func TestEntitlementService_ListLicenseFields(t *testing.T) {
+	service := newTestEntitlementService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/license-fields" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"licenseFields": [
+			{"name": "seat_count", "title": "Seats", "type": "integer", "default": "10"},
+			{"name": "expires_at", "type": "date", "isBuiltIn": true}
+		]}`)
+	})
+
+	result, err := service.ListLicenseFields(context.Background(), "app-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.LicenseFields) != 2 {
+		t.Errorf("Expected 2 license fields, got %d", len(result.LicenseFields))
+	}
+
+	if _, err := service.ListLicenseFields(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for unknown application")
+	}
+	if _, err := service.ListLicenseFields(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty application ID")
+	}
+}
+
+func TestEntitlementService_GetCustomerEntitlements(t *testing.T) {
+	service := newTestEntitlementService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/customer/customer-1/entitlements" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"entitlements": [{"name": "seat_count", "type": "integer", "value": "25"}]}`)
+	})
+
+	result, err := service.GetCustomerEntitlements(context.Background(), "customer-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.CustomerID != "customer-1" {
+		t.Errorf("Expected customer ID 'customer-1', got '%s'", result.CustomerID)
+	}
+	if len(result.Entitlements) != 1 || result.Entitlements[0].Value != "25" {
+		t.Errorf("Unexpected entitlements: %+v", result.Entitlements)
+	}
+
+	if _, err := service.GetCustomerEntitlements(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for unknown customer")
+	}
+}
+
+func TestEntitlementService_SetCustomerEntitlement(t *testing.T) {
+	tests := []struct {
+		name        string
+		customerID  string
+		fieldName   string
+		value       string
+		mockStatus  int
+		expectError bool
+	}{
+		{
+			name:       "update value",
+			customerID: "customer-1",
+			fieldName:  "seat_count",
+			value:      "50",
+			mockStatus: http.StatusOK,
+		},
+		{
+			name:        "API rejects value",
+			customerID:  "customer-1",
+			fieldName:   "seat_count",
+			value:       "-1",
+			mockStatus:  http.StatusBadRequest,
+			expectError: true,
+		},
+		{
+			name:        "missing customer ID",
+			fieldName:   "seat_count",
+			expectError: true,
+		},
+		{
+			name:        "missing field name",
+			customerID:  "customer-1",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestEntitlementService(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					t.Errorf("Expected PUT request, got %s", r.Method)
+				}
+				if r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("Expected JSON content type, got '%s'", r.Header.Get("Content-Type"))
+				}
+
+				var body setEntitlementRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+
+				w.WriteHeader(tt.mockStatus)
+				if tt.mockStatus != http.StatusOK {
+					fmt.Fprint(w, `{"message": "invalid value"}`)
+					return
+				}
+				fmt.Fprintf(w, `{"name": "seat_count", "type": "integer", "value": %q}`, body.Value)
+			})
+
+			result, err := service.SetCustomerEntitlement(context.Background(), tt.customerID, tt.fieldName, tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Value != tt.value {
+				t.Errorf("Expected value '%s', got '%s'", tt.value, result.Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import "github.com/crdant/replicated-mcp-server/pkg/models"
+
+// The Vendor Portal wraps each list endpoint's items in its own envelope rather than a
+// common paginated shape. Endpoints whose envelope differs from the list type returned
+// to callers are decoded into these types first. Channels, releases, and installers are
+// returned as {"channels": [...]}, {"releases": [...]}, and {"installers": [...]}, which
+// ChannelList, ReleaseList, and InstallerList decode directly.
+
+// appsResponse is the envelope of GET /vendor/v3/apps: {"apps": [...]}. Some proxies and
+// older API versions use "applications" instead, so both keys are accepted.
+type appsResponse struct {
+	Apps         []models.Application `json:"apps"`
+	Applications []models.Application `json:"applications"`
+}
+
+// list converts the envelope into an ApplicationList
+func (r *appsResponse) list() *ApplicationList {
+	if r.Apps != nil {
+		return &ApplicationList{Applications: r.Apps}
+	}
+	return &ApplicationList{Applications: r.Applications}
+}
+
+// appResponse is the envelope of POST /vendor/v3/app: {"app": {...}}
+type appResponse struct {
+	App models.Application `json:"app"`
+}
+
+// channelResponse is the envelope of PUT /vendor/v3/app/{appId}/channel/{channelId}:
+// {"channel": {...}}
+type channelResponse struct {
+	Channel models.Channel `json:"channel"`
+}
+
+// customersResponse is one page of GET /vendor/v3/app/{appId}/customers:
+// {"customers": [...], "totalCustomers": n}. TotalCustomers counts every page.
+type customersResponse struct {
+	Customers      []models.Customer `json:"customers"`
+	TotalCustomers int               `json:"totalCustomers"`
+}
+
+// customerResponse is the envelope of PUT /vendor/v3/customer/{customerId}:
+// {"customer": {...}}
+type customerResponse struct {
+	Customer models.Customer `json:"customer"`
+}
+
+// releaseResponse is the envelope of GET /vendor/v3/app/{appId}/release/{sequence}:
+// {"release": {...}}
+type releaseResponse struct {
+	Release models.Release `json:"release"`
+}
+
+// clusterResponse is the envelope of POST /vendor/v3/cluster: {"cluster": {...}}
+type clusterResponse struct {
+	Cluster models.Cluster `json:"cluster"`
+}
+
+// kubeconfigResponse is the body of GET /vendor/v3/cluster/{clusterId}/kubeconfig, whose
+// kubeconfig is base64-encoded: {"kubeconfig": "..."}
+type kubeconfigResponse struct {
+	Kubeconfig []byte `json:"kubeconfig"`
+}
+
+// vmResponse is the envelope of POST /vendor/v3/vm: {"vm": {...}}
+type vmResponse struct {
+	VM models.VM `json:"vm"`
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Fixtures in the shapes returned by the Vendor Portal API's list endpoints
+const (
+	vendorAppsFixture = `{"apps": [
+		{"id": "app-1", "name": "Acme App", "slug": "acme-app", "teamId": "team-1"},
+		{"id": "app-2", "name": "Globex App", "slug": "globex-app", "teamId": "team-1"}
+	]}`
+	vendorChannelsFixture = `{"channels": [
+		{"id": "channel-1", "name": "Stable", "channelSlug": "stable"},
+		{"id": "channel-2", "name": "Beta", "channelSlug": "beta"},
+		{"id": "channel-3", "name": "Unstable", "channelSlug": "unstable"}
+	]}`
+	vendorReleasesFixture = `{"releases": [
+		{"sequence": 1, "version": "1.0.0"},
+		{"sequence": 2, "version": "1.1.0"}
+	]}`
+)
+
+// vendorCustomerPages are the pages of a customer listing reporting 3 customers in total
+var vendorCustomerPages = map[string]string{
+	"0": `{"customers": [{"id": "customer-1", "name": "Acme"}, {"id": "customer-2", "name": "Globex"}],
+		"totalCustomers": 3}`,
+	"1": `{"customers": [{"id": "customer-3", "name": "Initech"}], "totalCustomers": 3}`,
+}
+
+func TestVendorResponseEnvelopes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, vendorAppsFixture)
+	})
+	mux.HandleFunc("/vendor/v3/app/app-1/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, vendorChannelsFixture)
+	})
+	mux.HandleFunc("/vendor/v3/app/app-1/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, vendorReleasesFixture)
+	})
+	mux.HandleFunc("/vendor/v3/app/app-1/customers", func(w http.ResponseWriter, r *http.Request) {
+		page, ok := vendorCustomerPages[r.URL.Query().Get("currentPage")]
+		if !ok {
+			page = `{"customers": [], "totalCustomers": 3}`
+		}
+		fmt.Fprint(w, page)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		list          func() (int, error)
+		expectedCount int
+	}{
+		{
+			name: "applications",
+			list: func() (int, error) {
+				list, err := NewApplicationService(client).ListApplications(ctx, nil)
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Applications), nil
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "channels",
+			list: func() (int, error) {
+				list, err := NewChannelService(client).ListChannels(ctx, "app-1")
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Channels), nil
+			},
+			expectedCount: 3,
+		},
+		{
+			name: "releases",
+			list: func() (int, error) {
+				list, err := NewReleaseService(client).ListReleases(ctx, "app-1")
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Releases), nil
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "customers across pages",
+			list: func() (int, error) {
+				list, err := NewCustomerService(client).ListCustomers(ctx, "app-1")
+				if err != nil {
+					return 0, err
+				}
+				if list.TotalCount != 3 {
+					return 0, fmt.Errorf("expected total count 3, got %d", list.TotalCount)
+				}
+				return len(list.Customers), nil
+			},
+			expectedCount: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := tt.list()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected %d items, got %d", tt.expectedCount, count)
+			}
+		})
+	}
+}
+
+// Captured Vendor Portal payloads for single entities, with the camelCase fields the models decode
+const (
+	vendorCustomerPayload = `{"customers": [{
+		"id": "customer-1", "appId": "app-1", "name": "Acme", "email": "ops@acme.example",
+		"channelId": "channel-1", "channelName": "Stable", "createdAt": "2025-01-01T00:00:00Z",
+		"updatedAt": "2025-02-01T00:00:00Z", "expiresAt": "2026-01-01T00:00:00Z", "type": "paid",
+		"isArchived": false, "isGitopsSupported": true, "isAirgapEnabled": true, "licenseId": "license-1",
+		"licenseType": "paid", "entitlements": {"seat_count": "10"}, "customFields": {"tier": "gold"},
+		"instances": [{"id": "instance-1", "versionLabel": "1.1.0", "releaseSequence": 2,
+			"lastCheckinAt": "2025-03-01T00:00:00Z", "isActive": true, "kubernetesDistribution": "eks",
+			"kubernetesVersion": "1.31.2"}]
+	}], "totalCustomers": 1}`
+	vendorChannelPayload = `{"channels": [{
+		"id": "channel-1", "appId": "app-1", "name": "Stable", "channelSlug": "stable", "releaseId": "release-2",
+		"releaseSequence": 2, "createdAt": "2025-01-01T00:00:00Z", "updatedAt": "2025-02-01T00:00:00Z",
+		"isDefault": true, "isArchived": false, "semverRequired": true, "enforceVersionLabel": false,
+		"airgapEnabled": true, "releaseNotesRequired": true
+	}]}`
+	vendorReleasePayload = `{"release": {
+		"id": "release-2", "appId": "app-1", "version": "1.1.0", "sequence": 2, "createdAt": "2025-01-01T00:00:00Z",
+		"updatedAt": "2025-02-01T00:00:00Z", "releasedAt": "2025-02-01T00:00:00Z", "isRequired": true,
+		"isPrerelease": false, "status": "released"
+	}}`
+)
+
+func TestVendorPayloadFields(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/app/app-1/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, vendorCustomerPayload)
+	})
+	mux.HandleFunc("/vendor/v3/app/app-1/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, vendorChannelPayload)
+	})
+	mux.HandleFunc("/vendor/v3/app/app-1/release/2", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, vendorReleasePayload)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	at := func(value string) time.Time {
+		parsed, _ := time.Parse(time.RFC3339, value)
+		return parsed
+	}
+	ptr := func(value time.Time) *time.Time { return &value }
+
+	tests := []struct {
+		name   string
+		decode func() (any, error)
+		want   any
+	}{
+		{
+			name: "customer",
+			decode: func() (any, error) {
+				list, err := NewCustomerService(client).ListCustomers(ctx, "app-1")
+				if err != nil || len(list.Customers) != 1 {
+					return list, err
+				}
+				return list.Customers[0], nil
+			},
+			want: models.Customer{
+				ID: "customer-1", ApplicationID: "app-1", Name: "Acme", Email: "ops@acme.example",
+				ChannelID: "channel-1", ChannelName: "Stable", CreatedAt: at("2025-01-01T00:00:00Z"),
+				UpdatedAt: at("2025-02-01T00:00:00Z"), ExpiresAt: ptr(at("2026-01-01T00:00:00Z")), Type: "paid",
+				IsGitOpsSupported: true, IsAirgapEnabled: true, LicenseID: "license-1", LicenseType: "paid",
+				Entitlements: map[string]string{"seat_count": "10"}, CustomFields: map[string]string{"tier": "gold"},
+				Instances: []models.Instance{{
+					ID: "instance-1", VersionLabel: "1.1.0", ReleaseSequence: 2,
+					LastCheckinAt: ptr(at("2025-03-01T00:00:00Z")), IsActive: true,
+					KubernetesDistribution: "eks", KubernetesVersion: "1.31.2",
+				}},
+			},
+		},
+		{
+			name: "channel",
+			decode: func() (any, error) {
+				list, err := NewChannelService(client).ListChannels(ctx, "app-1")
+				if err != nil || len(list.Channels) != 1 {
+					return list, err
+				}
+				return list.Channels[0], nil
+			},
+			want: models.Channel{
+				ID: "channel-1", ApplicationID: "app-1", Name: "Stable", ChannelSlug: "stable", ReleaseID: "release-2",
+				ReleaseSequence: 2, CreatedAt: at("2025-01-01T00:00:00Z"), UpdatedAt: at("2025-02-01T00:00:00Z"),
+				IsDefault: true, SemverRequired: true, AirgapEnabled: true, ReleaseNotesRequired: true,
+			},
+		},
+		{
+			name: "release",
+			decode: func() (any, error) {
+				release, err := NewReleaseService(client).GetRelease(ctx, "app-1", 2)
+				if err != nil {
+					return nil, err
+				}
+				return *release, nil
+			},
+			want: models.Release{
+				ID: "release-2", ApplicationID: "app-1", Version: "1.1.0", Sequence: 2,
+				CreatedAt: at("2025-01-01T00:00:00Z"), UpdatedAt: at("2025-02-01T00:00:00Z"),
+				ReleasedAt: ptr(at("2025-02-01T00:00:00Z")), IsRequired: true, Status: "released",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.decode()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
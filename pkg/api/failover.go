@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Constants for read failover between the primary and fallback endpoints
+const (
+	DefaultFailoverThreshold   = 3
+	DefaultHealthCheckInterval = 30 * time.Second
+	DefaultHealthCheckTimeout  = 5 * time.Second
+	healthCheckPath            = "/vendor/v3/apps"
+	serverErrorThreshold       = 500
+)
+
+// failover tracks the health of the primary endpoint. After a run of consecutive
+// failed reads, reads are routed to the fallback endpoint until a health check
+// shows the primary has recovered. Writes always go to the primary endpoint.
+type failover struct {
+	mu        sync.Mutex
+	threshold int
+	interval  time.Duration
+	failures  int
+	active    bool
+	lastCheck time.Time
+}
+
+// newFailover creates failover state with the default threshold and health check interval
+func newFailover() *failover {
+	return &failover{
+		threshold: DefaultFailoverThreshold,
+		interval:  DefaultHealthCheckInterval,
+	}
+}
+
+// usingFallback reports whether reads are currently routed to the fallback endpoint
+func (f *failover) usingFallback() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// recordPrimary records the outcome of a read against the primary endpoint and
+// reports whether reads are now routed to the fallback endpoint
+func (f *failover) recordPrimary(failed bool) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !failed {
+		f.failures = 0
+		return false
+	}
+
+	f.failures++
+	if f.failures >= f.threshold && !f.active {
+		f.active = true
+		f.lastCheck = time.Now()
+	}
+	return f.active
+}
+
+// healthCheckDue reports whether the primary endpoint should be checked again,
+// claiming the check so concurrent reads do not all probe at once
+func (f *failover) healthCheckDue() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.active || time.Since(f.lastCheck) < f.interval {
+		return false
+	}
+	f.lastCheck = time.Now()
+	return true
+}
+
+// recover routes reads back to the primary endpoint
+func (f *failover) recover() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active = false
+	f.failures = 0
+}
+
+// readWithFailover performs a GET request against the primary endpoint, falling back to
+// the secondary endpoint when the primary fails persistently
+func (c *Client) readWithFailover(
+	ctx context.Context, config ClientConfig, httpClient *http.Client, state *failover, path string,
+) (*http.Response, error) {
+	if state.usingFallback() {
+		if !state.healthCheckDue() || !c.primaryHealthy(ctx, config, httpClient) {
+			return c.read(ctx, httpClient, config.FallbackURL, path)
+		}
+
+		state.recover()
+		c.logger.InfoContext(ctx, "Primary API endpoint recovered", "url", config.BaseURL)
+	}
+
+	resp, err := c.read(ctx, httpClient, config.BaseURL, path)
+	if !state.recordPrimary(primaryFailed(ctx, resp, err)) {
+		return resp, err
+	}
+
+	c.logger.WarnContext(ctx, "Primary API endpoint failing, reading from fallback endpoint",
+		"primary", config.BaseURL,
+		"fallback", config.FallbackURL,
+	)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return c.read(withRetry(ctx), httpClient, config.FallbackURL, path)
+}
+
+// primaryFailed reports whether a primary endpoint response indicates the endpoint is
+// unavailable. Caller cancellation is not held against the endpoint.
+func primaryFailed(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return resp.StatusCode >= serverErrorThreshold
+}
+
+// primaryHealthy checks whether the primary endpoint answers requests again. Any response
+// below 500, including authorization errors, shows the endpoint is reachable.
+func (c *Client) primaryHealthy(ctx context.Context, config ClientConfig, httpClient *http.Client) bool {
+	ctx, cancel := context.WithTimeout(ctx, DefaultHealthCheckTimeout)
+	defer cancel()
+
+	checkURL, err := url.JoinPath(config.BaseURL, healthCheckPath)
+	if err != nil {
+		return false
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, http.NoBody)
+	if err != nil {
+		return false
+	}
+	req.Header = c.GetAuthHeaders()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		c.logger.DebugContext(ctx, "Primary API endpoint health check failed", "error", err)
+		return false
+	}
+	resp.Body.Close()
+
+	c.logger.DebugContext(ctx, "Primary API endpoint health check completed", "status", resp.StatusCode)
+	return resp.StatusCode < serverErrorThreshold
+}
+
+// validateFallbackURL ensures a configured fallback endpoint is an absolute URL
+func validateFallbackURL(fallbackURL string) error {
+	if fallbackURL == "" {
+		return nil
+	}
+	u, err := url.Parse(fallbackURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid fallback URL '%s': must include scheme and host", fallbackURL)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// endpointServer is a test endpoint that can be switched between healthy and failing
+type endpointServer struct {
+	*httptest.Server
+	failing  atomic.Bool
+	requests atomic.Int32
+}
+
+func newEndpointServer(t *testing.T, body string) *endpointServer {
+	t.Helper()
+
+	endpoint := &endpointServer{}
+	endpoint.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		endpoint.requests.Add(1)
+		if endpoint.failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(endpoint.Close)
+	return endpoint
+}
+
+func TestClient_ReadFailover(t *testing.T) {
+	primary := newEndpointServer(t, `{"source":"primary"}`)
+	fallback := newEndpointServer(t, `{"source":"fallback"}`)
+
+	client, err := NewClient(ClientConfig{
+		APIToken:    "test-token",
+		BaseURL:     primary.URL,
+		FallbackURL: fallback.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	read := func() string {
+		t.Helper()
+		var result struct {
+			Source string `json:"source"`
+		}
+		if err := client.getJSON(context.Background(), testPath, &result); err != nil {
+			return "error"
+		}
+		return result.Source
+	}
+
+	if got := read(); got != "primary" {
+		t.Fatalf("Expected healthy primary to serve reads, got %s", got)
+	}
+
+	// Failures below the threshold surface to the caller without failing over
+	primary.failing.Store(true)
+	for i := 1; i < DefaultFailoverThreshold; i++ {
+		if got := read(); got != "error" {
+			t.Fatalf("Expected read %d to fail before failover, got %s", i, got)
+		}
+	}
+
+	// The read that reaches the threshold is served by the fallback
+	if got := read(); got != "fallback" {
+		t.Fatalf("Expected failover to fallback, got %s", got)
+	}
+
+	// Subsequent reads skip the primary until a health check is due
+	before := primary.requests.Load()
+	if got := read(); got != "fallback" {
+		t.Errorf("Expected reads to stay on fallback, got %s", got)
+	}
+	if primary.requests.Load() != before {
+		t.Error("Expected no primary requests before the health check interval elapses")
+	}
+
+	// Writes always go to the primary endpoint
+	if err := client.putJSON(context.Background(), testPath, map[string]string{}, nil); err == nil {
+		t.Error("Expected write to the failing primary to return an error")
+	}
+
+	// A failed health check keeps reads on the fallback
+	client.failoverState().interval = 0
+	if got := read(); got != "fallback" {
+		t.Errorf("Expected fallback while primary is still failing, got %s", got)
+	}
+
+	// A successful health check routes reads back to the primary
+	primary.failing.Store(false)
+	if got := read(); got != "primary" {
+		t.Errorf("Expected recovery to primary, got %s", got)
+	}
+	if client.failoverState().usingFallback() {
+		t.Error("Expected failover to be cleared after recovery")
+	}
+}
+
+func TestClient_ReadFailoverIgnoresCancellation(t *testing.T) {
+	primary := newEndpointServer(t, `{}`)
+	fallback := newEndpointServer(t, `{}`)
+
+	client, err := NewClient(ClientConfig{
+		APIToken:    "test-token",
+		BaseURL:     primary.URL,
+		FallbackURL: fallback.URL,
+		Timeout:     time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < DefaultFailoverThreshold; i++ {
+		_, _ = client.Get(ctx, testPath)
+	}
+
+	if client.failoverState().usingFallback() {
+		t.Error("Expected canceled requests not to trigger failover")
+	}
+	if fallback.requests.Load() != 0 {
+		t.Errorf("Expected no fallback requests, got %d", fallback.requests.Load())
+	}
+}
+
+func TestClientConfig_ValidateFallbackURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		fallbackURL string
+		wantErr     bool
+	}{
+		{name: "no fallback", fallbackURL: "", wantErr: false},
+		{name: "absolute fallback", fallbackURL: "https://proxy.example.com", wantErr: false},
+		{name: "missing scheme", fallbackURL: "proxy.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := ClientConfig{
+				APIToken:    "test-token",
+				BaseURL:     DefaultBaseURL,
+				FallbackURL: tt.fallbackURL,
+			}
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Constants for serving responses from fixtures
+const (
+	fixtureExtension       = ".json"
+	fixtureNotFoundBody    = `{"message": "Not Found"}`
+	fixtureUnavailableBody = `{"message": "%s %s is not available in mock mode"}`
+
+	// fixtureQueryPrefix and fixtureQueryHashBytes name the fixtures of requests with a query
+	// string, such as vendor/v3/app/{id}/customers.q-3f2a9c1b7d4e.json
+	fixtureQueryPrefix    = "q-"
+	fixtureQueryHashBytes = 6
+)
+
+//go:embed fixtures
+var builtinFixtures embed.FS
+
+// DefaultFixtures returns the fixtures built into the server: a demo application with
+// channels, releases, and customers, enough to try the read-only tools without a token
+func DefaultFixtures() fs.FS {
+	fixtures, err := fs.Sub(builtinFixtures, "fixtures")
+	if err != nil {
+		panic(fmt.Sprintf("built-in fixtures are missing: %v", err))
+	}
+	return fixtures
+}
+
+// fixtureTransport answers Vendor Portal API requests from JSON files instead of the
+// network, so the server can be demonstrated and tested offline. A GET of
+// /vendor/v3/app/{id}/channels is answered with vendor/v3/app/{id}/channels.json, and other
+// methods with a file named for the method, such as vendor/v3/app.post.json. A request with a
+// query string is answered with the fixture for that query when there is one, such as a
+// recorded page of a listing, and otherwise with the fixture for its path. Requests without a
+// fixture are answered with 404 for reads and 501 for writes, as the real API would have no
+// way to answer them either.
+type fixtureTransport struct {
+	fixtures fs.FS
+}
+
+// newFixtureTransport creates a transport serving responses from fixtures
+func newFixtureTransport(fixtures fs.FS) *fixtureTransport {
+	return &fixtureTransport{fixtures: fixtures}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// The request body has nothing to go to, but is drained so callers see it sent
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+
+	name := fixtureName(req.Method, req.URL.Path, req.URL.Query())
+	data, err := fs.ReadFile(t.fixtures, name)
+	if errors.Is(err, fs.ErrNotExist) && req.URL.RawQuery != "" {
+		name = fixtureName(req.Method, req.URL.Path, nil)
+		data, err = fs.ReadFile(t.fixtures, name)
+	}
+	switch {
+	case err == nil:
+		return fixtureResponse(req, http.StatusOK, data), nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read fixture %s: %w", name, err)
+	case req.Method == http.MethodGet:
+		return fixtureResponse(req, http.StatusNotFound, []byte(fixtureNotFoundBody)), nil
+	default:
+		body := fmt.Sprintf(fixtureUnavailableBody, req.Method, req.URL.Path)
+		return fixtureResponse(req, http.StatusNotImplemented, []byte(body)), nil
+	}
+}
+
+// fixtureName returns the fixture file answering a request for the method, URL path, and
+// query. A query is named by a hash of its canonical encoding, with parameters sorted, so
+// each page of a listing has a fixture of its own however its parameters were ordered.
+func fixtureName(method, urlPath string, query url.Values) string {
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if len(query) > 0 {
+		sum := sha256.Sum256([]byte(query.Encode()))
+		name += "." + fixtureQueryPrefix + hex.EncodeToString(sum[:fixtureQueryHashBytes])
+	}
+	if method != http.MethodGet {
+		name += "." + strings.ToLower(method)
+	}
+	return name + fixtureExtension
+}
+
+// fixtureResponse builds a JSON response to a request with the given status and body
+func fixtureResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFixtureTransport(t *testing.T) {
+	fixtures := fstest.MapFS{
+		"vendor/v3/apps.json":             {Data: []byte(`{"apps": [{"id": "app-1", "slug": "fixture-app"}]}`)},
+		"vendor/v3/customer/c-1.put.json": {Data: []byte(`{"customer": {"id": "c-1", "name": "Updated"}}`)},
+		fixtureName(http.MethodGet, "/vendor/v3/apps", url.Values{"excludeChannels": {"false"}}): {
+			Data: []byte(`{"apps": [{"id": "app-1", "slug": "fixture-app-with-channels"}]}`),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "read with a fixture",
+			method:     http.MethodGet,
+			path:       "/vendor/v3/apps?excludeChannels=true",
+			wantStatus: http.StatusOK,
+			wantBody:   `"fixture-app"`,
+		},
+		{
+			name:       "read with a fixture for its query",
+			method:     http.MethodGet,
+			path:       "/vendor/v3/apps?excludeChannels=false",
+			wantStatus: http.StatusOK,
+			wantBody:   `"fixture-app-with-channels"`,
+		},
+		{
+			name:       "read without a fixture",
+			method:     http.MethodGet,
+			path:       "/vendor/v3/app/app-1/channels",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "Not Found",
+		},
+		{
+			name:       "write with a fixture",
+			method:     http.MethodPut,
+			path:       "/vendor/v3/customer/c-1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"Updated"`,
+		},
+		{
+			name:       "write without a fixture",
+			method:     http.MethodDelete,
+			path:       "/vendor/v3/app/app-1",
+			wantStatus: http.StatusNotImplemented,
+			wantBody:   "DELETE /vendor/v3/app/app-1 is not available in mock mode",
+		},
+		{
+			name:       "path escaping the fixtures",
+			method:     http.MethodGet,
+			path:       "/../../vendor/v3/apps",
+			wantStatus: http.StatusOK,
+			wantBody:   `"fixture-app"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(ClientConfig{BaseURL: DefaultBaseURL, Fixtures: fixtures})
+			if err != nil {
+				t.Fatalf("Failed to create client without a token: %v", err)
+			}
+
+			resp, err := client.makeRequest(context.Background(), tt.method, tt.path, "application/json",
+				strings.NewReader(`{}`))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read response: %v", err)
+			}
+			if !strings.Contains(string(body), tt.wantBody) {
+				t.Errorf("Expected response containing '%s', got '%s'", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestDefaultFixtures(t *testing.T) {
+	client, err := NewClient(ClientConfig{BaseURL: DefaultBaseURL, Fixtures: DefaultFixtures()})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	apps, err := NewApplicationService(client).ListApplications(ctx, nil)
+	if err != nil || len(apps.Applications) != 1 {
+		t.Fatalf("Expected the demo application, got %+v (%v)", apps, err)
+	}
+	appID := apps.Applications[0].ID
+
+	if _, err := NewApplicationService(client).GetApplication(ctx, appID); err != nil {
+		t.Errorf("Failed to get the demo application: %v", err)
+	}
+
+	channels, err := NewChannelService(client).ListChannels(ctx, appID)
+	if err != nil || len(channels.Channels) == 0 {
+		t.Fatalf("Expected demo channels, got %+v (%v)", channels, err)
+	}
+	for _, channel := range channels.Channels {
+		if _, err := NewChannelService(client).ListChannelReleases(ctx, appID, channel.ID); err != nil {
+			t.Errorf("Failed to list releases of channel %s: %v", channel.ID, err)
+		}
+	}
+
+	releases, err := NewReleaseService(client).ListReleases(ctx, appID)
+	if err != nil || len(releases.Releases) == 0 {
+		t.Fatalf("Expected demo releases, got %+v (%v)", releases, err)
+	}
+	for _, release := range releases.Releases {
+		if _, err := NewReleaseService(client).GetRelease(ctx, appID, release.Sequence); err != nil {
+			t.Errorf("Failed to get release %d: %v", release.Sequence, err)
+		}
+	}
+
+	customers, err := NewCustomerService(client).ListCustomers(ctx, appID)
+	if err != nil || len(customers.Customers) == 0 {
+		t.Fatalf("Expected demo customers, got %+v (%v)", customers, err)
+	}
+
+	if _, err := NewEntitlementService(client).ListLicenseFields(ctx, appID); err != nil {
+		t.Errorf("Failed to list license fields: %v", err)
+	}
+
+	// Changes are refused rather than pretending to succeed
+	var apiErr *Error
+	err = NewCustomerService(client).Archive(ctx, customers.Customers[0].ID)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Errorf("Expected archiving to be unavailable, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+)
+
+// Constants for HAR capture
+const (
+	harVersion     = "1.2"
+	harUnknownSize = -1
+	harFilePerm    = 0o600
+	microsPerMilli = 1000
+
+	// maxHAREntries bounds the requests a capture keeps; the oldest are dropped past it
+	maxHAREntries = 1000
+	// harWriteDelay is how long after a request the HAR file is rewritten, so a burst of
+	// requests is written once
+	harWriteDelay = time.Second
+)
+
+// harRecorder captures Vendor Portal API traffic to a HAR (HTTP Archive) file, with
+// credentials and personal data scrubbed, so it can be attached to a support request
+// or analyzed offline. The most recent requests are kept, and the file is rewritten in the
+// background shortly after each one, and by flush when the client is closed.
+type harRecorder struct {
+	mu        sync.Mutex
+	path      string
+	redactor  *redact.Redactor
+	logger    *slog.Logger
+	entries   []harEntry
+	dirty     bool
+	scheduled bool
+
+	// writeMu keeps rewrites of the file from overlapping
+	writeMu sync.Mutex
+}
+
+// newHARRecorder creates a recorder writing to path, or returns nil when path is empty.
+// Failures to write the file in the background are logged to logger.
+func newHARRecorder(path string, redactor *redact.Redactor, logger *slog.Logger) *harRecorder {
+	if path == "" {
+		return nil
+	}
+	return &harRecorder{path: path, redactor: redactor, logger: logger}
+}
+
+// setRedactor changes how entries recorded from now on are redacted, keeping those
+// already captured
+func (r *harRecorder) setRedactor(redactor *redact.Redactor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor = redactor
+}
+
+// HAR 1.2 document structure (http://www.softwareishard.com/blog/har-12-spec/)
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// bufferResponseBody reads a response body into memory so it can be captured, replacing
+// it with an in-memory copy the caller can still read
+func bufferResponseBody(resp *http.Response) ([]byte, error) {
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// record captures a completed request and its response, scheduling a rewrite of the HAR file
+func (r *harRecorder) record(
+	req *http.Request, requestBody []byte, resp *http.Response, responseBody []byte,
+	start time.Time, duration time.Duration,
+) {
+	r.mu.Lock()
+	redactor := r.redactor
+	r.mu.Unlock()
+
+	millis := float64(duration.Microseconds()) / microsPerMilli
+	entry := harEntry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Time:            millis,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         redactor.String(req.URL.String()),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(redactor, req.Header),
+			QueryString: harQuery(redactor, req),
+			HeadersSize: harUnknownSize,
+			BodySize:    len(requestBody),
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Headers:     harHeaders(redactor, resp.Header),
+			Content: harContent{
+				Size:     len(responseBody),
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     redactBody(redactor, responseBody),
+			},
+			HeadersSize: harUnknownSize,
+			BodySize:    len(responseBody),
+		},
+		Timings: harTimings{Wait: millis},
+	}
+	if requestBody != nil {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     redactBody(redactor, requestBody),
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	if dropped := len(r.entries) - maxHAREntries; dropped > 0 {
+		r.entries = slices.Delete(r.entries, 0, dropped)
+	}
+	r.dirty = true
+	if !r.scheduled {
+		r.scheduled = true
+		time.AfterFunc(harWriteDelay, r.scheduledFlush)
+	}
+}
+
+// scheduledFlush rewrites the HAR file once the write delay after a request has passed
+func (r *harRecorder) scheduledFlush() {
+	r.mu.Lock()
+	r.scheduled = false
+	r.mu.Unlock()
+
+	if err := r.flush(); err != nil {
+		r.logger.Warn("Failed to capture API traffic", "path", r.path, "error", err)
+	}
+}
+
+// flush rewrites the HAR file with the entries recorded so far, if any were recorded since
+// it was last written. Recording continues while the file is written.
+func (r *harRecorder) flush() error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	r.mu.Lock()
+	if !r.dirty {
+		r.mu.Unlock()
+		return nil
+	}
+	entries := slices.Clone(r.entries)
+	r.dirty = false
+	r.mu.Unlock()
+
+	return r.write(entries)
+}
+
+// write replaces the HAR file with the given entries
+func (r *harRecorder) write(entries []harEntry) error {
+	data, err := json.MarshalIndent(harFile{Log: harLog{
+		Version: harVersion,
+		Creator: harCreator{Name: DefaultUserAgent, Version: harVersion},
+		Entries: entries,
+	}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode HAR capture: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, harFilePerm); err != nil {
+		return fmt.Errorf("failed to write HAR capture: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to write HAR capture: %w", err)
+	}
+	return nil
+}
+
+// harHeaders converts headers to HAR name/value pairs, redacting credentials
+func harHeaders(redactor *redact.Redactor, header http.Header) []harNameValue {
+	pairs := make([]harNameValue, 0, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			pairs = append(pairs, harNameValue{Name: name, Value: redactor.Header(name, value)})
+		}
+	}
+	return pairs
+}
+
+// harQuery converts a request's query parameters to HAR name/value pairs, redacting
+// sensitive parameters and patterns
+func harQuery(redactor *redact.Redactor, req *http.Request) []harNameValue {
+	pairs := []harNameValue{}
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			if redact.IsSensitiveKey(name) {
+				value = redact.Mask
+			}
+			pairs = append(pairs, harNameValue{Name: name, Value: redactor.String(value)})
+		}
+	}
+	return pairs
+}
+
+// redactBody scrubs sensitive values from a captured body. JSON bodies have the values
+// of sensitive keys replaced; the redactor's patterns are removed from any remaining text.
+func redactBody(redactor *redact.Redactor, body []byte) string {
+	var value any
+	if json.Unmarshal(body, &value) == nil {
+		if redacted, err := json.Marshal(redactor.Value(value)); err == nil {
+			body = redacted
+		}
+	}
+	return redactor.String(string(body))
+}
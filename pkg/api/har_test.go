@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		patterns  []string
+		body      string
+		wantIn    []string
+		wantNotIn []string
+	}{
+		{
+			name:      "sensitive JSON keys",
+			body:      `{"customers": [{"name": "Acme", "email": "ops@acme.example", "installationId": "i-1"}]}`,
+			wantIn:    []string{`"name":"Acme"`, `"email":"[REDACTED]"`},
+			wantNotIn: []string{"ops@acme.example", "i-1"},
+		},
+		{
+			name:      "nested tokens",
+			body:      `{"registry": {"apiToken": "abc123", "url": "https://registry.example"}}`,
+			wantIn:    []string{`"apiToken":"[REDACTED]"`, "https://registry.example"},
+			wantNotIn: []string{"abc123"},
+		},
+		{
+			name:      "emails in plain text",
+			body:      "contact support@vendor.example for help",
+			wantIn:    []string{"contact [REDACTED] for help"},
+			wantNotIn: []string{"support@vendor.example"},
+		},
+		{
+			name:      "configured patterns",
+			patterns:  []string{`lic_[a-z0-9]+`},
+			body:      `{"licenses": ["lic_abc123"], "note": "renew lic_def456"}`,
+			wantIn:    []string{`"licenses":["[REDACTED]"]`, `"note":"renew [REDACTED]"`},
+			wantNotIn: []string{"lic_abc123", "lic_def456"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := redact.New(tt.patterns)
+			if err != nil {
+				t.Fatalf("redact.New() error = %v", err)
+			}
+			got := redactBody(redactor, []byte(tt.body))
+			for _, want := range tt.wantIn {
+				if !strings.Contains(got, want) {
+					t.Errorf("redactBody() = %s, expected to contain %s", got, want)
+				}
+			}
+			for _, unwanted := range tt.wantNotIn {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("redactBody() = %s, should not contain %s", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_HARCapture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"customer": {"id": "cust-1", "email": "buyer@acme.example"}}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	client, err := NewClient(ClientConfig{
+		APIToken: "secret-token",
+		BaseURL:  server.URL,
+		HARFile:  path,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp, err := client.Post(context.Background(), "/vendor/v3/customer", "application/json",
+		strings.NewReader(`{"name": "Acme", "email": "buyer@acme.example"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// The caller still receives the unredacted response
+	if !strings.Contains(string(body), "buyer@acme.example") {
+		t.Errorf("Expected the original response body, got %s", body)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Failed to close client: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read HAR file: %v", err)
+	}
+	for _, secret := range []string{"secret-token", "buyer@acme.example"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("HAR capture contains %s", secret)
+		}
+	}
+
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("Failed to decode HAR file: %v", err)
+	}
+	if len(har.Log.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(har.Log.Entries))
+	}
+	entry := har.Log.Entries[0]
+	if entry.Request.Method != http.MethodPost || entry.Response.Status != http.StatusOK {
+		t.Errorf("Unexpected entry: %s %d", entry.Request.Method, entry.Response.Status)
+	}
+	if entry.Request.PostData == nil || !strings.Contains(entry.Request.PostData.Text, `"name":"Acme"`) {
+		t.Errorf("Expected captured request body, got %+v", entry.Request.PostData)
+	}
+}
+
+func TestHARRecorder_Bounds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	recorder := newHARRecorder(path, redact.Default(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	for i := range maxHAREntries + 5 {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://api.example/requests/%d", i), nil)
+		recorder.record(req, nil, resp, []byte(`{}`), time.Now(), time.Millisecond)
+	}
+
+	// Requests don't wait for the file to be written
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected the HAR file to be written after the requests, got %v", err)
+	}
+
+	if err := recorder.flush(); err != nil {
+		t.Fatalf("Failed to write HAR file: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read HAR file: %v", err)
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("Failed to decode HAR file: %v", err)
+	}
+	if len(har.Log.Entries) != maxHAREntries {
+		t.Fatalf("Expected %d entries, got %d", maxHAREntries, len(har.Log.Entries))
+	}
+	if first := har.Log.Entries[0].Request.URL; first != "https://api.example/requests/5" {
+		t.Errorf("Expected the oldest requests to be dropped, got %s first", first)
+	}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Constants for hedged reads
+const (
+	// DefaultMinHedgeDelay is the shortest delay before a hedged request is sent, so that
+	// endpoints that are already fast are not sent duplicate requests
+	DefaultMinHedgeDelay = 50 * time.Millisecond
+	hedgeLatencyWindow   = 100
+	hedgeMinSamples      = 20
+	hedgePercentile      = 0.95
+	vendorAPIPrefix      = "/vendor/v3/"
+)
+
+// HedgeClasses lists the endpoint classes that can be hedged. A request's class is the
+// resource named in its path, for example "channels" for /vendor/v3/app/{id}/channels
+// and "app" for /vendor/v3/app/{id}.
+var HedgeClasses = []string{
+	"apps", "app", "channels", "releases", "customers", "customer",
+	"entitlements", "license-fields", "supportbundles", "supportbundle",
+}
+
+// HedgePolicy configures hedged reads. When a GET request to a hedged endpoint class has not
+// completed after the class's delay, a second identical request is sent and the first
+// response wins. Delays maps endpoint classes to a fixed delay; a zero delay uses the
+// class's observed P95 latency. An empty Delays map hedges every class adaptively.
+type HedgePolicy struct {
+	Enabled bool
+	Delays  map[string]time.Duration
+}
+
+// ParseHedgeClasses parses hedge class entries of the form "class" (adaptive delay) or
+// "class=duration" (fixed delay), such as "customers" or "releases=250ms"
+func ParseHedgeClasses(entries []string) (map[string]time.Duration, error) {
+	delays := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		class, delayStr, hasDelay := strings.Cut(entry, "=")
+		class = strings.TrimSpace(class)
+		if !slices.Contains(HedgeClasses, class) {
+			return nil, fmt.Errorf("unknown hedge class '%s'. Valid classes are: %s",
+				class, strings.Join(HedgeClasses, ", "))
+		}
+
+		var delay time.Duration
+		if hasDelay {
+			var err error
+			delay, err = time.ParseDuration(strings.TrimSpace(delayStr))
+			if err != nil || delay <= 0 {
+				return nil, fmt.Errorf("invalid hedge delay '%s' for class '%s': must be a positive duration",
+					delayStr, class)
+			}
+		}
+		delays[class] = delay
+	}
+	return delays, nil
+}
+
+// endpointClass returns the hedge class for a request path. Vendor API paths alternate
+// between resource names and IDs, so the class is the last resource name in the path.
+func endpointClass(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, vendorAPIPrefix), "/"), "/")
+	if len(segments)%2 == 0 {
+		return segments[len(segments)-2]
+	}
+	return segments[len(segments)-1]
+}
+
+// hedger tracks per-class read latencies and decides when to send hedged requests
+type hedger struct {
+	mu        sync.Mutex
+	policy    HedgePolicy
+	latencies map[string][]time.Duration
+}
+
+// newHedger creates hedging state for a policy, or returns nil when hedging is disabled
+func newHedger(policy HedgePolicy) *hedger {
+	if !policy.Enabled {
+		return nil
+	}
+	return &hedger{policy: policy, latencies: make(map[string][]time.Duration)}
+}
+
+// delay returns how long to wait before hedging a request of the given class, and whether
+// the class should be hedged at all. Adaptive classes are not hedged until enough
+// latencies have been observed to estimate the P95.
+func (h *hedger) delay(class string) (time.Duration, bool) {
+	fixed, listed := h.policy.Delays[class]
+	if len(h.policy.Delays) > 0 && !listed {
+		return 0, false
+	}
+	if fixed > 0 {
+		return fixed, true
+	}
+
+	h.mu.Lock()
+	samples := slices.Clone(h.latencies[class])
+	h.mu.Unlock()
+
+	if len(samples) < hedgeMinSamples {
+		return 0, false
+	}
+
+	slices.Sort(samples)
+	p95 := samples[int(float64(len(samples)-1)*hedgePercentile)]
+	return max(p95, DefaultMinHedgeDelay), true
+}
+
+// observe records the latency of a completed request, keeping a rolling window per class
+func (h *hedger) observe(class string, latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := append(h.latencies[class], latency)
+	if len(samples) > hedgeLatencyWindow {
+		samples = samples[len(samples)-hedgeLatencyWindow:]
+	}
+	h.latencies[class] = samples
+}
+
+// hedgeAttempt is the outcome of one of the requests sent for a hedged read
+type hedgeAttempt struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// read performs a GET request against base, hedging it when the policy covers its class
+func (c *Client) read(ctx context.Context, httpClient *http.Client, base, path string) (*http.Response, error) {
+	h := c.hedgerState()
+	if h == nil {
+		return c.send(ctx, httpClient, base, http.MethodGet, path, "", nil, false)
+	}
+
+	class := endpointClass(path)
+	delay, hedge := h.delay(class)
+
+	results := make(chan hedgeAttempt, 2)
+	var cancels []context.CancelFunc
+	launch := func() {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		if index > 0 {
+			attemptCtx = withRetry(attemptCtx)
+		}
+		go func() {
+			start := time.Now()
+			resp, err := c.send(attemptCtx, httpClient, base, http.MethodGet, path, "", nil, false)
+			if err == nil {
+				h.observe(class, time.Since(start))
+			}
+			results <- hedgeAttempt{index: index, resp: resp, err: err}
+		}()
+	}
+
+	launch()
+	if !hedge {
+		return deliver(<-results, cancels)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case first := <-results:
+		return deliver(first, cancels)
+	case <-timer.C:
+		c.logger.DebugContext(ctx, "Sending hedged request", "class", class, "delay", delay, "path", path)
+		launch()
+	}
+
+	first := <-results
+	if first.err != nil {
+		cancels[first.index]()
+		return deliver(<-results, cancels)
+	}
+
+	// The first response wins; the slower request is canceled and its response discarded
+	for index, cancel := range cancels {
+		if index != first.index {
+			cancel()
+		}
+	}
+	go discard(<-results)
+	return deliver(first, cancels)
+}
+
+// deliver returns an attempt's response, releasing its context once the body is closed
+func deliver(attempt hedgeAttempt, cancels []context.CancelFunc) (*http.Response, error) {
+	cancel := cancels[attempt.index]
+	if attempt.err != nil {
+		cancel()
+		return nil, attempt.err
+	}
+	attempt.resp.Body = &cancelOnClose{ReadCloser: attempt.resp.Body, cancel: cancel}
+	return attempt.resp, nil
+}
+
+// discard closes the response of a losing attempt, if any
+func discard(attempt hedgeAttempt) {
+	if attempt.resp != nil {
+		attempt.resp.Body.Close()
+	}
+}
+
+// cancelOnClose releases a request context when the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEndpointClass(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/vendor/v3/apps", want: "apps"},
+		{path: "/vendor/v3/app/app-123", want: "app"},
+		{path: "/vendor/v3/app/app-123/channels", want: "channels"},
+		{path: "/vendor/v3/app/app-123/license-fields", want: "license-fields"},
+		{path: "/vendor/v3/customer/cust-1/entitlements", want: "entitlements"},
+		{path: "/vendor/v3/supportbundles?appId=app-123", want: "supportbundles"},
+		{path: "/vendor/v3/supportbundle/bundle-1", want: "supportbundle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := endpointClass(tt.path); got != tt.want {
+				t.Errorf("endpointClass(%s) = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHedgeClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "adaptive and fixed classes",
+			entries: []string{"customers", "releases=250ms"},
+			want:    map[string]time.Duration{"customers": 0, "releases": 250 * time.Millisecond},
+		},
+		{
+			name:    "unknown class",
+			entries: []string{"widgets"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid delay",
+			entries: []string{"customers=soon"},
+			wantErr: true,
+		},
+		{
+			name:    "negative delay",
+			entries: []string{"customers=-1s"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHedgeClasses(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHedgeClasses() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseHedgeClasses() = %v, want %v", got, tt.want)
+			}
+			for class, delay := range tt.want {
+				if got[class] != delay {
+					t.Errorf("ParseHedgeClasses()[%s] = %v, want %v", class, got[class], delay)
+				}
+			}
+		})
+	}
+}
+
+func TestHedgerDelay(t *testing.T) {
+	h := newHedger(HedgePolicy{
+		Enabled: true,
+		Delays:  map[string]time.Duration{"customers": 0, "releases": 250 * time.Millisecond},
+	})
+
+	if delay, ok := h.delay("releases"); !ok || delay != 250*time.Millisecond {
+		t.Errorf("Expected fixed delay for releases, got %v (%t)", delay, ok)
+	}
+	if _, ok := h.delay("channels"); ok {
+		t.Error("Expected classes missing from the policy not to be hedged")
+	}
+	if _, ok := h.delay("customers"); ok {
+		t.Error("Expected adaptive classes not to be hedged before enough samples")
+	}
+
+	for i := 1; i <= hedgeMinSamples*5; i++ {
+		h.observe("customers", time.Duration(i)*10*time.Millisecond)
+	}
+	delay, ok := h.delay("customers")
+	if !ok {
+		t.Fatal("Expected adaptive class to be hedged once samples are available")
+	}
+	if delay < 900*time.Millisecond || delay > time.Second {
+		t.Errorf("Expected P95 delay near 950ms, got %v", delay)
+	}
+
+	if newHedger(HedgePolicy{}) != nil {
+		t.Error("Expected no hedger when hedging is disabled")
+	}
+}
+
+func TestClient_HedgedRead(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request stalls until it is canceled; the hedged request answers immediately
+		if requests.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte(`{"source":"hedge"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{
+		APIToken: "test-token",
+		BaseURL:  server.URL,
+		Hedging: HedgePolicy{
+			Enabled: true,
+			Delays:  map[string]time.Duration{"apps": 20 * time.Millisecond},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Get(context.Background(), "/vendor/v3/apps")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `{"source":"hedge"}` {
+		t.Errorf("Expected hedged response, got %s", body)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected hedged read to avoid the stalled request, took %v", elapsed)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests.Load())
+	}
+
+	// Writes are never hedged
+	requests.Store(1)
+	if _, err := client.Put(context.Background(), "/vendor/v3/apps", "application/json", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected a single write request, got %d", requests.Load()-1)
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// InstallerService provides methods for interacting with kURL installer APIs
+type InstallerService struct {
+	client *Client
+}
+
+// NewInstallerService creates a new InstallerService
+func NewInstallerService(client *Client) *InstallerService {
+	return &InstallerService{
+		client: client,
+	}
+}
+
+// InstallerList represents a list of kURL installer specs
+type InstallerList struct {
+	Installers []models.Installer `json:"installers"`
+}
+
+// ListInstallers retrieves every kURL installer spec of an application, with the channels
+// that ship each one
+func (s *InstallerService) ListInstallers(ctx context.Context, appID string) (*InstallerList, error) {
+	var result InstallerList
+	if err := s.client.getAppResource(ctx, appID, "installers", &result); err != nil {
+		return nil, err
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed installers",
+		"app_id", appID,
+		"count", len(result.Installers))
+
+	return &result, nil
+}
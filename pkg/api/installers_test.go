@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInstallerService_ListInstallers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/installers" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"installers": [
+			{"appId": "app-1", "sequence": 2, "yaml": "kind: Installer", "channelIds": ["channel-1"]},
+			{"appId": "app-1", "sequence": 1, "yaml": "kind: Installer"}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewInstallerService(client)
+
+	tests := []struct {
+		name          string
+		appID         string
+		expectError   bool
+		expectedCount int
+	}{
+		{name: "list installers", appID: "app-1", expectedCount: 2},
+		{name: "unknown application", appID: "missing", expectError: true},
+		{name: "missing application ID", appID: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListInstallers(context.Background(), tt.appID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Installers) != tt.expectedCount {
+				t.Errorf("Expected %d installers, got %d", tt.expectedCount, len(result.Installers))
+			}
+			if !result.Installers[0].ShippedBy("channel-1") {
+				t.Errorf("Expected the first installer to be shipped by channel-1, got %+v", result.Installers[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"iter"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// listSeq adapts a list call into an iterator. The list call runs when iteration starts;
+// a failure is yielded once as an error with a zero item.
+func listSeq[T any](ctx context.Context, list func(context.Context) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		items, err := list(ctx)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+
+		for _, item := range items {
+			if ctx.Err() != nil {
+				var zero T
+				yield(zero, ctx.Err())
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Collect drains an iterator into a slice, stopping at the first error
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Applications iterates over all applications accessible to the authenticated team
+func (s *ApplicationService) Applications(ctx context.Context) iter.Seq2[models.Application, error] {
+	return listSeq(ctx, func(ctx context.Context) ([]models.Application, error) {
+		list, err := s.ListApplications(ctx, &ListApplicationsOptions{ExcludeChannels: true})
+		if err != nil {
+			return nil, err
+		}
+		return list.Applications, nil
+	})
+}
+
+// Channels iterates over the channels of an application
+func (s *ChannelService) Channels(ctx context.Context, appID string) iter.Seq2[models.Channel, error] {
+	return listSeq(ctx, func(ctx context.Context) ([]models.Channel, error) {
+		list, err := s.ListChannels(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+		return list.Channels, nil
+	})
+}
+
+// Releases iterates over the releases of an application
+func (s *ReleaseService) Releases(ctx context.Context, appID string) iter.Seq2[models.Release, error] {
+	return listSeq(ctx, func(ctx context.Context) ([]models.Release, error) {
+		list, err := s.ListReleases(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+		return list.Releases, nil
+	})
+}
+
+// Customers iterates over the customers of an application
+func (s *CustomerService) Customers(ctx context.Context, appID string) iter.Seq2[models.Customer, error] {
+	return listSeq(ctx, func(ctx context.Context) ([]models.Customer, error) {
+		list, err := s.ListCustomers(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+		return list.Customers, nil
+	})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestListSeq(t *testing.T) {
+	listErr := errors.New("list failed")
+
+	tests := []struct {
+		name        string
+		items       []string
+		err         error
+		expected    []string
+		expectError error
+	}{
+		{name: "yields every item", items: []string{"a", "b", "c"}, expected: []string{"a", "b", "c"}},
+		{name: "empty list", items: nil, expected: nil},
+		{name: "list error", err: listErr, expectError: listErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seq := listSeq(context.Background(), func(context.Context) ([]string, error) {
+				return tt.items, tt.err
+			})
+
+			got, err := Collect(seq)
+			if !errors.Is(err, tt.expectError) {
+				t.Fatalf("Expected error %v, got %v", tt.expectError, err)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Expected item %d to be %s, got %s", i, tt.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListSeq_StopsEarly(t *testing.T) {
+	seq := listSeq(context.Background(), func(context.Context) ([]int, error) {
+		return []int{1, 2, 3, 4}, nil
+	})
+
+	var seen []int
+	for item, err := range seq {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		seen = append(seen, item)
+		if item == 2 {
+			break
+		}
+	}
+
+	if len(seen) != 2 {
+		t.Errorf("Expected iteration to stop after 2 items, got %v", seen)
+	}
+}
+
+func TestListSeq_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	seq := listSeq(ctx, func(context.Context) ([]int, error) {
+		return []int{1, 2}, nil
+	})
+
+	if _, err := Collect(seq); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultMaxConcurrentRequests is the default limit on simultaneous in-flight requests per API host
+const DefaultMaxConcurrentRequests = 10
+
+// DefaultRequestsPerSecond is the default rate of requests sent to each API host
+const DefaultRequestsPerSecond = 10
+
+// hostLimiter bounds the number of simultaneous in-flight requests to each API host, so
+// that a burst of agent-triggered fan-out queues instead of overwhelming the Vendor Portal.
+// A request holds its slot until its response body is closed.
+type hostLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+// newHostLimiter creates a limiter allowing limit in-flight requests per host, or returns
+// nil when limit is zero or negative, meaning requests are not limited
+func newHostLimiter(limit int) *hostLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &hostLimiter{limit: limit, slots: make(map[string]chan struct{})}
+}
+
+// acquire waits for a free slot for host, returning a function that releases it.
+// It returns an error if ctx is done before a slot becomes free.
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	l.mu.Lock()
+	slots, ok := l.slots[host]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.slots[host] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a request slot for %s: %w", host, ctx.Err())
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { <-slots }) }, nil
+}
+
+// releaseOnClose releases a request slot when the response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close closes the body and releases the request slot
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// rateLimiter spaces out requests to each API host with a token bucket, so that bulk tools
+// and fan-out across applications stay under the Vendor Portal's abuse protection. Each host's
+// bucket holds up to one second of requests, letting short bursts through at full speed.
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// tokenBucket is the requests a host can be sent without waiting as of last. Tokens go
+// negative while requests wait for their turn.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a limiter allowing rate requests per second to each host, or
+// returns nil when rate is zero or negative, meaning requests are not rate limited
+func newRateLimiter(rate float64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   max(1, rate),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// wait takes a token for a request to host, waiting until the token is due. It returns an
+// error, giving the token back, if ctx is done first.
+func (l *rateLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := l.now()
+	bucket, ok := l.buckets[host]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[host] = bucket
+	}
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+	bucket.tokens--
+	delay := time.Duration(-bucket.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		bucket.tokens++
+		l.mu.Unlock()
+		return fmt.Errorf("waiting for the request rate limit for %s: %w", host, ctx.Err())
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_ConcurrencyLimit(t *testing.T) {
+	const limit = 2
+
+	var inFlight, peak atomic.Int32
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			observed := peak.Load()
+			if current <= observed || peak.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{
+		APIToken:              "test-token",
+		BaseURL:               server.URL,
+		MaxConcurrentRequests: limit,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(context.Background(), testPath)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+
+	// Give the requests time to queue, then let them all complete
+	time.Sleep(100 * time.Millisecond)
+	if got := inFlight.Load(); got != limit {
+		t.Errorf("Expected %d requests in flight, got %d", limit, got)
+	}
+	close(unblock)
+	wg.Wait()
+
+	if got := peak.Load(); got > limit {
+		t.Errorf("Expected at most %d concurrent requests, got %d", limit, got)
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	if newHostLimiter(0) != nil {
+		t.Error("Expected no limiter for a zero limit")
+	}
+
+	limiter := newHostLimiter(1)
+
+	release, err := limiter.acquire(context.Background(), "api.example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Other hosts have their own slots
+	other, err := limiter.acquire(context.Background(), "proxy.example.com")
+	if err != nil {
+		t.Fatalf("Expected a slot for a different host: %v", err)
+	}
+	other()
+
+	// A full host blocks until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx, "api.example.com"); err == nil {
+		t.Error("Expected acquire to fail while the host's slots are taken")
+	}
+
+	// Releasing twice frees only one slot
+	release()
+	release()
+	again, err := limiter.acquire(context.Background(), "api.example.com")
+	if err != nil {
+		t.Fatalf("Expected a slot after release: %v", err)
+	}
+	defer again()
+	if len(limiter.slots["api.example.com"]) != 1 {
+		t.Errorf("Expected one slot in use, got %d", len(limiter.slots["api.example.com"]))
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	const rate = 20
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{
+		APIToken:          "test-token",
+		BaseURL:           server.URL,
+		RequestsPerSecond: rate,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// A full bucket lets a second's worth through, then requests are spaced 50ms apart
+	start := time.Now()
+	for i := 0; i < rate+4; i++ {
+		resp, err := client.Get(context.Background(), testPath)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected requests beyond the burst to wait, took %v", elapsed)
+	}
+	if got := requests.Load(); got != rate+4 {
+		t.Errorf("Expected %d requests, got %d", rate+4, got)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	if newRateLimiter(0) != nil {
+		t.Error("Expected no limiter for a zero rate")
+	}
+	if limiter := newRateLimiter(0.5); limiter.burst != 1 {
+		t.Errorf("Expected a burst of one request below one per second, got %v", limiter.burst)
+	}
+
+	now := time.Now()
+	limiter := newRateLimiter(2)
+	limiter.now = func() time.Time { return now }
+
+	// The bucket starts full
+	for i := 0; i < 2; i++ {
+		if err := limiter.wait(context.Background(), "api.example.com"); err != nil {
+			t.Fatalf("Expected request %d within the burst, got %v", i+1, err)
+		}
+	}
+
+	// Other hosts have their own buckets
+	if err := limiter.wait(context.Background(), "proxy.example.com"); err != nil {
+		t.Fatalf("Expected a token for a different host: %v", err)
+	}
+
+	// An empty bucket waits until the context is done, then gives the token back
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx, "api.example.com"); err == nil {
+		t.Error("Expected wait to fail while the host's bucket is empty")
+	}
+	if tokens := limiter.buckets["api.example.com"].tokens; tokens != 0 {
+		t.Errorf("Expected the token to be given back, got %v tokens", tokens)
+	}
+
+	// Tokens refill with time, up to the burst
+	now = now.Add(10 * time.Second)
+	if err := limiter.wait(context.Background(), "api.example.com"); err != nil {
+		t.Fatalf("Expected a token after refilling: %v", err)
+	}
+	if tokens := limiter.buckets["api.example.com"].tokens; tokens != 1 {
+		t.Errorf("Expected the bucket to refill to its burst, got %v tokens", tokens+1)
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+)
+
+// fixtureDirPerm is the permission of directories created for recorded fixtures
+const fixtureDirPerm = 0o700
+
+// fixtureRecorder saves successful Vendor Portal API responses as fixtures, with credentials
+// and personal data scrubbed, in the layout fixtureTransport replays. Recording a session
+// and replaying it in mock mode reproduces it without a token or network, for deterministic
+// tests and reproducible bug reports. A later response to the same request, including its
+// query string, replaces the earlier one.
+type fixtureRecorder struct {
+	mu       sync.Mutex
+	dir      string
+	redactor *redact.Redactor
+}
+
+// newFixtureRecorder creates a recorder writing under dir, or returns nil when dir is empty
+func newFixtureRecorder(dir string, redactor *redact.Redactor) *fixtureRecorder {
+	if dir == "" {
+		return nil
+	}
+	return &fixtureRecorder{dir: dir, redactor: redactor}
+}
+
+// setRedactor changes how responses recorded from now on are redacted
+func (r *fixtureRecorder) setRedactor(redactor *redact.Redactor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor = redactor
+}
+
+// record saves the response to a request for path under a name for its query string, so
+// the pages of a listing are kept apart. Only successful responses are saved, since
+// fixtures are replayed as successes.
+func (r *fixtureRecorder) record(method, path string, status int, body []byte) error {
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return nil
+	}
+
+	requestURL, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("invalid request path: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := filepath.Join(r.dir, filepath.FromSlash(fixtureName(method, requestURL.Path, requestURL.Query())))
+	if err := os.MkdirAll(filepath.Dir(name), fixtureDirPerm); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(name, r.redact(body), harFilePerm); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// redact scrubs a response body, indenting JSON so fixtures are easy to read and edit
+func (r *fixtureRecorder) redact(body []byte) []byte {
+	var value any
+	if json.Unmarshal(body, &value) == nil {
+		if redacted, err := json.MarshalIndent(r.redactor.Value(value), "", "  "); err == nil {
+			return append(redacted, '\n')
+		}
+	}
+	return []byte(r.redactor.String(string(body)))
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// customersPageFixture returns the fixture recorded for a page of an application's customers
+func customersPageFixture(appID string, page int) string {
+	query := url.Values{"pageSize": {strconv.Itoa(customersPageSize)}, "currentPage": {strconv.Itoa(page)}}
+	return fixtureName(http.MethodGet, "/vendor/v3/app/"+appID+"/customers", query)
+}
+
+func TestFixtureRecorder(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"apps": [{"id": "app-1", "name": "Recorded", "slug": "recorded"}]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/app-1/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"customers": [{"id": "c-1", "name": "Acme", "email": "ops@acme.example"}], "totalCustomers": 1}`)
+	})
+	mux.HandleFunc("POST /vendor/v3/customer/c-1/archive", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/missing", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, RecordDir: dir})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := NewApplicationService(client).ListApplications(ctx, nil); err != nil {
+		t.Fatalf("Failed to list applications: %v", err)
+	}
+	if _, err := NewCustomerService(client).ListCustomers(ctx, "app-1"); err != nil {
+		t.Fatalf("Failed to list customers: %v", err)
+	}
+	if err := NewCustomerService(client).Archive(ctx, "c-1"); err != nil {
+		t.Fatalf("Failed to archive customer: %v", err)
+	}
+	if _, err := NewApplicationService(client).GetApplication(ctx, "missing"); err == nil {
+		t.Fatal("Expected an error for a missing application")
+	}
+
+	tests := []struct {
+		name      string
+		fixture   string
+		wantIn    string
+		wantNotIn string
+	}{
+		{name: "read", fixture: "vendor/v3/apps.json", wantIn: `"slug": "recorded"`},
+		{
+			name:      "read with a query, redacted",
+			fixture:   customersPageFixture("app-1", 0),
+			wantIn:    `"email": "[REDACTED]"`,
+			wantNotIn: "ops@acme.example",
+		},
+		{name: "write", fixture: "vendor/v3/customer/c-1/archive.post.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(dir, tt.fixture))
+			if err != nil {
+				t.Fatalf("Expected fixture %s: %v", tt.fixture, err)
+			}
+			if !strings.Contains(string(data), tt.wantIn) {
+				t.Errorf("Expected fixture containing '%s', got '%s'", tt.wantIn, data)
+			}
+			if tt.wantNotIn != "" && strings.Contains(string(data), tt.wantNotIn) {
+				t.Errorf("Expected fixture without '%s', got '%s'", tt.wantNotIn, data)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "vendor/v3/app/missing.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected error responses not to be recorded, got %v", err)
+	}
+
+	// The recording replays without the API
+	server.Close()
+	replay, err := NewClient(ClientConfig{BaseURL: server.URL, Fixtures: os.DirFS(dir)})
+	if err != nil {
+		t.Fatalf("Failed to create replay client: %v", err)
+	}
+	customers, err := NewCustomerService(replay).ListCustomers(ctx, "app-1")
+	if err != nil || len(customers.Customers) != 1 || customers.Customers[0].Name != "Acme" {
+		t.Errorf("Expected the recorded customers, got %+v (%v)", customers, err)
+	}
+}
+
+func TestFixtureRecorderPages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/app/app-1/customers", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, vendorCustomerPages[r.URL.Query().Get("currentPage")])
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, RecordDir: dir})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	recorded, err := NewCustomerService(client).ListCustomers(ctx, "app-1")
+	if err != nil || len(recorded.Customers) != 3 {
+		t.Fatalf("Expected 3 customers across pages, got %+v (%v)", recorded, err)
+	}
+	for page := range 2 {
+		if _, err := os.Stat(filepath.Join(dir, customersPageFixture("app-1", page))); err != nil {
+			t.Errorf("Expected a fixture for page %d: %v", page, err)
+		}
+	}
+
+	// Each page replays from its own fixture rather than the last page recorded
+	server.Close()
+	replay, err := NewClient(ClientConfig{BaseURL: server.URL, Fixtures: os.DirFS(dir)})
+	if err != nil {
+		t.Fatalf("Failed to create replay client: %v", err)
+	}
+	replayed, err := NewCustomerService(replay).ListCustomers(ctx, "app-1")
+	if err != nil {
+		t.Fatalf("Failed to replay customers: %v", err)
+	}
+	var ids []string
+	for _, customer := range replayed.Customers {
+		ids = append(ids, customer.ID)
+	}
+	if strings.Join(ids, ",") != "customer-1,customer-2,customer-3" {
+		t.Errorf("Expected the recorded pages in order, got %v", ids)
+	}
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Hosts of the Replicated registry and proxy registry, which accept the API token
+const (
+	ReplicatedRegistryHost = "registry.replicated.com"
+	ReplicatedProxyHost    = "proxy.replicated.com"
+)
+
+// Docker Hub naming: references without a registry host are pulled from Docker Hub, and
+// single-component names from its library namespace
+const (
+	dockerHubHost      = "docker.io"
+	dockerHubNamespace = "library/"
+)
+
+// maxTagPages bounds how many pages of tags are read for one repository
+const maxTagPages = 20
+
+// ErrRegistryNotSupported is returned when listing the tags of a repository on a registry
+// the API token cannot authenticate to
+var ErrRegistryNotSupported = errors.New("registry is not the Replicated registry or proxy registry")
+
+var (
+	// challengeParamPattern matches the key="value" parameters of a WWW-Authenticate challenge
+	challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	// nextLinkPattern matches the next page in a Link header
+	nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="?next"?`)
+)
+
+// RegistryService lists image tags in the Replicated registry and proxy registry using the
+// Docker Registry HTTP API, authenticating with the API token
+type RegistryService struct {
+	client *Client
+	// baseURLs overrides the https://<host> address of registries, for tests
+	baseURLs map[string]string
+}
+
+// NewRegistryService creates a new RegistryService
+func NewRegistryService(client *Client) *RegistryService {
+	return &RegistryService{
+		client: client,
+	}
+}
+
+// ImageTags lists the tags of an image repository
+type ImageTags struct {
+	Repository string   `json:"repository"`
+	Tags       []string `json:"tags"`
+}
+
+// tagsResponse is a page of the registry's tag list
+type tagsResponse struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// tokenResponse is a registry bearer token
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// IsReplicatedRegistry reports whether a registry host is the Replicated registry or proxy registry
+func IsReplicatedRegistry(host string) bool {
+	host = strings.ToLower(host)
+	return host == ReplicatedRegistryHost || host == ReplicatedProxyHost
+}
+
+// SplitRepository splits an image repository into its registry host and the repository name
+// on that registry, following Docker's rule that the first component is a host only when it
+// contains a dot or port or is localhost
+func SplitRepository(repository string) (host, name string) {
+	first, rest, found := strings.Cut(repository, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return strings.ToLower(first), rest
+	}
+	if !found {
+		return dockerHubHost, dockerHubNamespace + repository
+	}
+	return dockerHubHost, repository
+}
+
+// ListTags lists the tags of an image repository, such as registry.replicated.com/acme/api,
+// in sorted order. Repositories on other registries return ErrRegistryNotSupported.
+func (s *RegistryService) ListTags(ctx context.Context, repository string) (*ImageTags, error) {
+	host, name := SplitRepository(repository)
+	if !IsReplicatedRegistry(host) {
+		return nil, fmt.Errorf("cannot list tags of '%s': %w", repository, ErrRegistryNotSupported)
+	}
+
+	s.client.logger.DebugContext(ctx, "Listing image tags", "repository", repository)
+
+	base := "https://" + host
+	if override, ok := s.baseURLs[host]; ok {
+		base = override
+	}
+	next := base + "/v2/" + name + "/tags/list"
+
+	result := &ImageTags{Repository: repository, Tags: []string{}}
+	authorization := s.basicAuthorization()
+	for page := 0; next != "" && page < maxTagPages; page++ {
+		var tags tagsResponse
+		var err error
+		next, authorization, err = s.getTags(ctx, next, authorization, &tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of '%s': %w", repository, err)
+		}
+		result.Tags = append(result.Tags, tags.Tags...)
+	}
+	slices.Sort(result.Tags)
+	result.Tags = slices.Compact(result.Tags)
+
+	s.client.logger.DebugContext(ctx, "Successfully listed image tags",
+		"repository", repository,
+		"count", len(result.Tags))
+
+	return result, nil
+}
+
+// getTags reads a page of tags, exchanging the API token for a bearer token when the registry
+// asks for one. It returns the URL of the next page, if any, and the authorization to use for it.
+func (s *RegistryService) getTags(
+	ctx context.Context,
+	pageURL, authorization string,
+	tags *tagsResponse,
+) (next, nextAuthorization string, err error) {
+	resp, err := s.registryGet(ctx, pageURL, authorization)
+	if err != nil {
+		return "", "", err
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if resp.StatusCode == http.StatusUnauthorized && strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		resp.Body.Close()
+		token, tokenErr := s.bearerToken(ctx, challenge)
+		if tokenErr != nil {
+			return "", "", tokenErr
+		}
+		authorization = "Bearer " + token
+		if resp, err = s.registryGet(ctx, pageURL, authorization); err != nil {
+			return "", "", err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= HTTPErrorThreshold {
+		return "", "", fmt.Errorf("registry error: %w", s.client.ConvertHTTPError(resp))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(tags); err != nil {
+		return "", "", fmt.Errorf("failed to decode tags: %w", err)
+	}
+
+	if match := nextLinkPattern.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+		current, _ := url.Parse(pageURL)
+		if link, parseErr := current.Parse(match[1]); parseErr == nil {
+			next = link.String()
+		}
+	}
+	return next, authorization, nil
+}
+
+// bearerToken requests a registry token for the realm, service, and scope of a challenge
+func (s *RegistryService) bearerToken(ctx context.Context, challenge string) (string, error) {
+	params := make(map[string]string)
+	for _, match := range challengeParamPattern.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("registry sent an invalid authentication challenge: %s", challenge)
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := s.registryGet(ctx, realm.String(), s.basicAuthorization())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= HTTPErrorThreshold {
+		return "", fmt.Errorf("registry authentication failed: %w", s.client.ConvertHTTPError(resp))
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry returned an empty token")
+}
+
+// registryGet performs a GET request against a registry with the given authorization
+func (s *RegistryService) registryGet(ctx context.Context, requestURL, authorization string) (*http.Response, error) {
+	_, httpClient := s.client.snapshot()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("User-Agent", DefaultUserAgent)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", classifyRequestError(ctx, http.MethodGet, requestURL, err))
+	}
+	return resp, nil
+}
+
+// basicAuthorization returns Basic credentials using the API token as both user and password
+func (s *RegistryService) basicAuthorization() string {
+	config, _ := s.client.snapshot()
+	credentials := config.APIToken + ":" + config.APIToken
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSplitRepository(t *testing.T) {
+	tests := []struct {
+		repository, wantHost, wantName string
+	}{
+		{repository: "registry.replicated.com/acme/api", wantHost: "registry.replicated.com", wantName: "acme/api"},
+		{repository: "Proxy.Replicated.com/proxy/acme/quay.io/acme/api", wantHost: "proxy.replicated.com",
+			wantName: "proxy/acme/quay.io/acme/api"},
+		{repository: "localhost:5000/api", wantHost: "localhost:5000", wantName: "api"},
+		{repository: "acme/api", wantHost: "docker.io", wantName: "acme/api"},
+		{repository: "redis", wantHost: "docker.io", wantName: "library/redis"},
+	}
+
+	for _, tt := range tests {
+		host, name := SplitRepository(tt.repository)
+		if host != tt.wantHost || name != tt.wantName {
+			t.Errorf("SplitRepository(%s) = %s, %s, want %s, %s", tt.repository, host, name, tt.wantHost, tt.wantName)
+		}
+	}
+}
+
+// newTestRegistry serves two pages of tags for acme/api behind a bearer token challenge,
+// issuing the token for the API token as Basic credentials
+func newTestRegistry(t *testing.T) *RegistryService {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	var registryURL string
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "test-token" || password != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("scope") != "repository:acme/api:pull" {
+			t.Errorf("Expected the challenge scope to be requested, got '%s'", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"token": "registry-token"}`)
+	})
+	mux.HandleFunc("/v2/acme/api/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer registry-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="registry",scope="repository:acme/api:pull"`, registryURL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("last") == "" {
+			w.Header().Set("Link", `</v2/acme/api/tags/list?last=1.1.0&n=2>; rel="next"`)
+			fmt.Fprint(w, `{"name": "acme/api", "tags": ["1.1.0", "1.0.0"]}`)
+			return
+		}
+		fmt.Fprint(w, `{"name": "acme/api", "tags": ["1.2.0", "1.0.0"]}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	registryURL = server.URL
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewRegistryService(client)
+	service.baseURLs = map[string]string{ReplicatedRegistryHost: server.URL}
+	return service
+}
+
+func TestRegistryService_ListTags(t *testing.T) {
+	service := newTestRegistry(t)
+
+	tests := []struct {
+		name        string
+		repository  string
+		wantTags    []string
+		wantErr     error
+		expectError bool
+	}{
+		{
+			name:       "paged tags behind a token challenge",
+			repository: "registry.replicated.com/acme/api",
+			wantTags:   []string{"1.0.0", "1.1.0", "1.2.0"},
+		},
+		{name: "unknown repository", repository: "registry.replicated.com/acme/web", expectError: true},
+		{
+			name:        "other registry",
+			repository:  "quay.io/acme/api",
+			wantErr:     ErrRegistryNotSupported,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListTags(context.Background(), tt.repository)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Repository != tt.repository || !slices.Equal(result.Tags, tt.wantTags) {
+				t.Errorf("Expected tags %v of %s, got %+v", tt.wantTags, tt.repository, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// ReleaseService provides methods for interacting with release APIs
+type ReleaseService struct {
+	client *Client
+}
+
+// NewReleaseService creates a new ReleaseService
+func NewReleaseService(client *Client) *ReleaseService {
+	return &ReleaseService{
+		client: client,
+	}
+}
+
+// ReleaseList represents a list of releases
+type ReleaseList struct {
+	Releases []models.Release `json:"releases"`
+}
+
+// PromoteReleaseRequest is the request body for promoting a release to channels
+type PromoteReleaseRequest struct {
+	ChannelIDs   []string `json:"channelIds"`
+	VersionLabel string   `json:"versionLabel"`
+	ReleaseNotes string   `json:"releaseNotes,omitempty"`
+	IsRequired   bool     `json:"isRequired"`
+}
+
+// ListReleases retrieves all releases for an application
+func (s *ReleaseService) ListReleases(ctx context.Context, appID string) (*ReleaseList, error) {
+	var result ReleaseList
+	if err := s.client.getAppResource(ctx, appID, "releases", &result); err != nil {
+		return nil, err
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed releases",
+		"app_id", appID,
+		"count", len(result.Releases))
+
+	return &result, nil
+}
+
+// SearchReleases returns the releases of an application whose version, notes, or ID contains
+// the query (ignoring case), or whose sequence equals it
+func (s *ReleaseService) SearchReleases(ctx context.Context, appID, query string) (*ReleaseList, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	all, err := s.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases for search: %w", err)
+	}
+
+	matches := []models.Release{}
+	for i := range all.Releases {
+		release := &all.Releases[i]
+		if models.MatchesQuery(query, release.Version, release.Notes, release.ID) ||
+			strconv.FormatInt(release.Sequence, 10) == strings.TrimSpace(query) {
+			matches = append(matches, *release)
+		}
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully searched releases",
+		"query", query,
+		"total_releases", len(all.Releases),
+		"filtered_count", len(matches))
+
+	return &ReleaseList{Releases: matches}, nil
+}
+
+// ListByChannel returns the releases of an application that were ever promoted to a channel,
+// in the order the application lists them
+func (s *ReleaseService) ListByChannel(ctx context.Context, appID, channelID string) (*ReleaseList, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID is required")
+	}
+
+	history, err := NewChannelService(s.client).ListChannelReleases(ctx, appID, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases of channel %s: %w", channelID, err)
+	}
+
+	all, err := s.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions := firstPromotions(history.Releases)
+	matches := []models.Release{}
+	for i := range all.Releases {
+		if _, ok := promotions[all.Releases[i].Sequence]; ok {
+			matches = append(matches, all.Releases[i])
+		}
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed channel's releases",
+		"app_id", appID,
+		"channel_id", channelID,
+		"count", len(matches))
+
+	return &ReleaseList{Releases: matches}, nil
+}
+
+// GetByVersionLabel retrieves the release with a version label, ignoring case, including its
+// manifests. When several releases share the label, the latest one is returned.
+func (s *ReleaseService) GetByVersionLabel(ctx context.Context, appID, version string) (*models.Release, error) {
+	if strings.TrimSpace(version) == "" {
+		return nil, fmt.Errorf("version label is required")
+	}
+
+	all, err := s.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *models.Release
+	for i := range all.Releases {
+		release := &all.Releases[i]
+		if models.SameName(release.Version, version) && (latest == nil || release.Sequence > latest.Sequence) {
+			latest = release
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no release of application %s has version label '%s'", appID, version)
+	}
+
+	return s.GetRelease(ctx, appID, latest.Sequence)
+}
+
+// GetRelease retrieves a single release by sequence, including its manifests. Draft releases
+// can still be edited, but other releases never change, so with a disk cache they are
+// downloaded once and read from the cache afterwards.
+func (s *ReleaseService) GetRelease(ctx context.Context, appID string, sequence int64) (*models.Release, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s/release/%d", url.PathEscape(appID), sequence)
+
+	s.client.logger.DebugContext(ctx, "Getting release", "app_id", appID, "sequence", sequence)
+
+	var envelope releaseResponse
+	cacheable := func() bool { return envelope.Release.Status != models.ReleaseStatusDraft }
+	if err := s.client.getCachedJSON(ctx, path, &envelope, cacheable); err != nil {
+		return nil, fmt.Errorf("failed to get release %d: %w", sequence, err)
+	}
+
+	return &envelope.Release, nil
+}
+
+// DownloadArchive streams the source archive of the release with the given sequence, the
+// gzip-compressed tarball of its manifests as shipped, to a temporary file in dir, or the default
+// directory for temporary files when dir is empty. The caller removes the file once done with it.
+func (s *ReleaseService) DownloadArchive(
+	ctx context.Context, appID string, sequence int64, dir string,
+) (*FileBody, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s/release/%d/download", url.PathEscape(appID), sequence)
+
+	s.client.logger.DebugContext(ctx, "Downloading release archive", "app_id", appID, "sequence", sequence)
+
+	archive, err := s.client.Download(ctx, path, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download release %d: %w", sequence, err)
+	}
+
+	return archive, nil
+}
+
+// PromoteRelease promotes the release with the given sequence to the requested channels
+func (s *ReleaseService) PromoteRelease(
+	ctx context.Context,
+	appID string,
+	sequence int64,
+	request PromoteReleaseRequest,
+) error {
+	if appID == "" {
+		return fmt.Errorf("application ID is required")
+	}
+	if len(request.ChannelIDs) == 0 {
+		return fmt.Errorf("at least one channel ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/app/%s/release/%d/promote", url.PathEscape(appID), sequence)
+
+	s.client.logger.DebugContext(ctx, "Promoting release",
+		"app_id", appID,
+		"sequence", sequence,
+		"channels", request.ChannelIDs)
+
+	if err := s.client.postJSON(ctx, path, request, nil); err != nil {
+		return fmt.Errorf("failed to promote release: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully promoted release",
+		"app_id", appID,
+		"sequence", sequence)
+
+	return nil
+}
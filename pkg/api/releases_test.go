@@ -0,0 +1,437 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReleaseService_ListReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/releases" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"releases": [
+			{"id": "release-1", "version": "1.0.0", "sequence": 1},
+			{"id": "release-2", "version": "1.1.0", "sequence": 2}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewReleaseService(client)
+
+	tests := []struct {
+		name          string
+		appID         string
+		expectError   bool
+		expectedCount int
+	}{
+		{name: "list releases", appID: "app-1", expectedCount: 2},
+		{name: "unknown application", appID: "missing", expectError: true},
+		{name: "missing application ID", appID: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListReleases(context.Background(), tt.appID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Releases) != tt.expectedCount {
+				t.Errorf("Expected %d releases, got %d", tt.expectedCount, len(result.Releases))
+			}
+		})
+	}
+}
+
+func TestReleaseService_SearchReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [
+			{"id": "release-1", "version": "1.0.0", "sequence": 1, "notes": "Initial release"},
+			{"id": "release-2", "version": "1.1.0", "sequence": 2, "notes": "Adds SAML login"},
+			{"id": "release-12", "version": "2.0.0-beta.1", "sequence": 12}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewReleaseService(client)
+
+	tests := []struct {
+		name        string
+		query       string
+		expectError bool
+		expectedIDs []string
+	}{
+		{name: "match on version", query: "1.1", expectedIDs: []string{"release-2"}},
+		{name: "match on notes ignoring case", query: "saml", expectedIDs: []string{"release-2"}},
+		{name: "match on sequence", query: "12", expectedIDs: []string{"release-12"}},
+		{name: "prerelease versions", query: "BETA", expectedIDs: []string{"release-12"}},
+		{name: "no matches", query: "3.0", expectedIDs: []string{}},
+		{name: "empty query", query: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.SearchReleases(context.Background(), "app-1", tt.query)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ids := []string{}
+			for _, release := range result.Releases {
+				ids = append(ids, release.ID)
+			}
+			if !slices.Equal(ids, tt.expectedIDs) {
+				t.Errorf("Expected releases %v, got %v", tt.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestReleaseService_GetRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/app/app-1/release/2" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"release": {"id": "release-2", "version": "1.1.0", "sequence": 2, "config": "kind: Config"}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewReleaseService(client)
+
+	tests := []struct {
+		name        string
+		appID       string
+		sequence    int64
+		expectError bool
+	}{
+		{name: "get release", appID: "app-1", sequence: 2},
+		{name: "unknown release", appID: "app-1", sequence: 9, expectError: true},
+		{name: "missing application ID", sequence: 2, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := service.GetRelease(context.Background(), tt.appID, tt.sequence)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if release.Sequence != 2 || release.Config != "kind: Config" {
+				t.Errorf("Unexpected release: %+v", release)
+			}
+		})
+	}
+}
+
+// newTestReleaseLookupService serves releases 1 to 3, with 2 and 3 sharing a version label,
+// and the history of a stable channel to which releases 1 and 3 were promoted
+func newTestReleaseLookupService(t *testing.T) *ReleaseService {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/app/app-1/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [
+			{"id": "release-1", "version": "1.0.0", "sequence": 1},
+			{"id": "release-2", "version": "1.1.0", "sequence": 2},
+			{"id": "release-3", "version": "1.1.0", "sequence": 3}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/app-1/channel/channel-stable/releases",
+		func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"releases": [
+				{"channelSequence": 2, "releaseSequence": 3, "promotedAt": "2025-01-10T00:00:00Z"},
+				{"channelSequence": 1, "releaseSequence": 1, "promotedAt": "2025-01-01T00:00:00Z"}
+			]}`)
+		})
+	mux.HandleFunc("GET /vendor/v3/app/app-1/release/3/download", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		fmt.Fprint(w, "release-3 archive")
+	})
+	mux.HandleFunc("GET /vendor/v3/app/app-1/release/{sequence}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"release": {"id": "release-%[1]s", "sequence": %[1]s, "config": "kind: Config"}}`,
+			r.PathValue("sequence"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return NewReleaseService(client)
+}
+
+func TestReleaseService_ListByChannel(t *testing.T) {
+	service := newTestReleaseLookupService(t)
+
+	tests := []struct {
+		name        string
+		channelID   string
+		expectError bool
+		expectedIDs []string
+	}{
+		{
+			name:        "releases promoted to the channel",
+			channelID:   "channel-stable",
+			expectedIDs: []string{"release-1", "release-3"},
+		},
+		{name: "unknown channel", channelID: "channel-lts", expectError: true},
+		{name: "missing channel ID", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListByChannel(context.Background(), "app-1", tt.channelID)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ids := []string{}
+			for _, release := range result.Releases {
+				ids = append(ids, release.ID)
+			}
+			if !slices.Equal(ids, tt.expectedIDs) {
+				t.Errorf("Expected releases %v, got %v", tt.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestReleaseService_GetByVersionLabel(t *testing.T) {
+	service := newTestReleaseLookupService(t)
+
+	tests := []struct {
+		name             string
+		version          string
+		expectError      bool
+		expectedSequence int64
+	}{
+		{name: "unique label", version: "1.0.0", expectedSequence: 1},
+		{name: "shared label returns the latest", version: " 1.1.0 ", expectedSequence: 3},
+		{name: "unknown label", version: "2.0.0", expectError: true},
+		{name: "missing label", version: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := service.GetByVersionLabel(context.Background(), "app-1", tt.version)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if release.Sequence != tt.expectedSequence || release.Config != "kind: Config" {
+				t.Errorf("Expected release %d with its manifests, got %+v", tt.expectedSequence, release)
+			}
+		})
+	}
+}
+
+func TestReleaseService_DownloadArchive(t *testing.T) {
+	service := newTestReleaseLookupService(t)
+
+	tests := []struct {
+		name        string
+		appID       string
+		sequence    int64
+		wantContent string
+		expectError bool
+	}{
+		{name: "release archive", appID: "app-1", sequence: 3, wantContent: "release-3 archive"},
+		{name: "missing release", appID: "app-1", sequence: 9, expectError: true},
+		{name: "empty app ID", sequence: 3, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive, err := service.DownloadArchive(context.Background(), tt.appID, tt.sequence, t.TempDir())
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer archive.Remove()
+
+			data, err := os.ReadFile(archive.Path)
+			if err != nil {
+				t.Fatalf("Failed to read archive: %v", err)
+			}
+			if string(data) != tt.wantContent || archive.ContentType != "application/gzip" {
+				t.Errorf("Expected archive %q, got %q (%s)", tt.wantContent, data, archive.ContentType)
+			}
+		})
+	}
+}
+
+func TestReleaseService_GetReleaseDiskCache(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		status := "released"
+		if r.URL.Path == "/vendor/v3/app/app-1/release/3" {
+			status = "draft"
+		}
+		fmt.Fprintf(w, `{"release": {"id": "release", "sequence": 2, "status": %q, "config": "kind: Config"}}`, status)
+	}))
+	defer server.Close()
+
+	config := ClientConfig{
+		APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second, DiskCacheDir: t.TempDir(),
+	}
+
+	// Each client stands in for a server session, sharing the cache directory
+	for range 2 {
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		service := NewReleaseService(client)
+		for _, sequence := range []int64{2, 3} {
+			release, err := service.GetRelease(context.Background(), "app-1", sequence)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if release.Config != "kind: Config" {
+				t.Errorf("Unexpected release: %+v", release)
+			}
+		}
+	}
+
+	if requests["/vendor/v3/app/app-1/release/2"] != 1 {
+		t.Errorf("Expected a released release to be downloaded once, got %d requests",
+			requests["/vendor/v3/app/app-1/release/2"])
+	}
+	if requests["/vendor/v3/app/app-1/release/3"] != 2 {
+		t.Errorf("Expected a draft release to be downloaded each time, got %d requests",
+			requests["/vendor/v3/app/app-1/release/3"])
+	}
+
+	// Another token, such as another profile's, does not read what the first token cached
+	config.APIToken = "other-token"
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := NewReleaseService(client).GetRelease(context.Background(), "app-1", 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests["/vendor/v3/app/app-1/release/2"] != 2 {
+		t.Errorf("Expected another token to miss the cache, got %d requests",
+			requests["/vendor/v3/app/app-1/release/2"])
+	}
+}
+
+func TestReleaseService_PromoteRelease(t *testing.T) {
+	var received PromoteReleaseRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/vendor/v3/app/app-1/release/2/promote" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewReleaseService(client)
+
+	tests := []struct {
+		name        string
+		appID       string
+		sequence    int64
+		channelIDs  []string
+		expectError bool
+	}{
+		{name: "promote release", appID: "app-1", sequence: 2, channelIDs: []string{"channel-1"}},
+		{name: "unknown release", appID: "app-1", sequence: 9, channelIDs: []string{"channel-1"}, expectError: true},
+		{name: "missing channels", appID: "app-1", sequence: 2, expectError: true},
+		{name: "missing application ID", sequence: 2, channelIDs: []string{"channel-1"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.PromoteRelease(context.Background(), tt.appID, tt.sequence, PromoteReleaseRequest{
+				ChannelIDs:   tt.channelIDs,
+				VersionLabel: "1.1.0",
+			})
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if received.VersionLabel != "1.1.0" || len(received.ChannelIDs) != 1 {
+				t.Errorf("Unexpected promotion request: %+v", received)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/crdant/replicated-mcp-server/pkg/tracing"
+)
+
+// startRequestSpan starts a client span for an API request, named for its method and endpoint
+// class (such as "GET channels") so spans group by endpoint rather than by ID
+func startRequestSpan(ctx context.Context, base, method, path string) (context.Context, trace.Span) {
+	requestPath, _, _ := strings.Cut(path, "?")
+	attributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.URLPath(requestPath),
+		attribute.String("replicated.api.endpoint", endpointClass(path)),
+	}
+	if baseURL, err := url.Parse(base); err == nil {
+		attributes = append(attributes, semconv.ServerAddress(baseURL.Hostname()))
+	}
+
+	return tracing.Tracer().Start(ctx, method+" "+endpointClass(path),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+}
+
+// propagateTrace adds the trace context to an API request's headers
+func propagateTrace(ctx context.Context, req *http.Request) {
+	tracing.Propagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+}
+
+// endRequestSpan records an API request's outcome on its span and ends it. Failed requests
+// and error responses mark the span as an error.
+func endRequestSpan(span trace.Span, status int, err error) {
+	defer span.End()
+
+	if status != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	}
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case status >= HTTPErrorThreshold:
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if r.URL.Path == "/vendor/v3/app/app-1/customers" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantName   string
+		wantStatus int64
+		wantError  bool
+	}{
+		{
+			name:       "successful request",
+			path:       "/vendor/v3/app/app-1/channels?excludeDetail=true",
+			wantName:   "GET channels",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "error response",
+			path:       "/vendor/v3/app/app-1/customers",
+			wantName:   "GET customers",
+			wantStatus: http.StatusNotFound,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceparent = ""
+			resp, err := client.makeRequest(context.Background(), http.MethodGet, tt.path, "", nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+			if span.Name() != tt.wantName {
+				t.Errorf("Expected span '%s', got '%s'", tt.wantName, span.Name())
+			}
+			if traceparent == "" || traceparent[3:35] != span.SpanContext().TraceID().String() {
+				t.Errorf("Expected the request to carry trace %s, got '%s'", span.SpanContext().TraceID(), traceparent)
+			}
+
+			attributes := attribute.NewSet(span.Attributes()...)
+			if status, _ := attributes.Value("http.response.status_code"); status.AsInt64() != tt.wantStatus {
+				t.Errorf("Expected status attribute %d, got %v", tt.wantStatus, status.Emit())
+			}
+			if path, _ := attributes.Value("url.path"); path.AsString() != "/vendor/v3/app/app-1/"+tt.wantName[4:] {
+				t.Errorf("Expected the path without its query, got '%s'", path.AsString())
+			}
+			if isError := span.Status().Code == codes.Error; isError != tt.wantError {
+				t.Errorf("Expected error status %v, got %v", tt.wantError, span.Status())
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// SupportBundleService provides methods for interacting with support bundle APIs
+type SupportBundleService struct {
+	client *Client
+}
+
+// NewSupportBundleService creates a new SupportBundleService
+func NewSupportBundleService(client *Client) *SupportBundleService {
+	return &SupportBundleService{
+		client: client,
+	}
+}
+
+// ListSupportBundlesOptions represents options for listing support bundles
+type ListSupportBundlesOptions struct {
+	CustomerID string `json:"customer_id,omitempty"`
+	InstanceID string `json:"instance_id,omitempty"`
+}
+
+// SupportBundleList represents a list of support bundles
+type SupportBundleList struct {
+	SupportBundles []models.SupportBundle `json:"supportBundles"`
+}
+
+// ListSupportBundles retrieves the support bundles uploaded for an application,
+// optionally limited to a single customer or instance
+func (s *SupportBundleService) ListSupportBundles(
+	ctx context.Context,
+	appID string,
+	opts *ListSupportBundlesOptions,
+) (*SupportBundleList, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("application ID is required")
+	}
+
+	params := url.Values{}
+	params.Set("appId", appID)
+	if opts != nil && opts.CustomerID != "" {
+		params.Set("customerId", opts.CustomerID)
+	}
+	if opts != nil && opts.InstanceID != "" {
+		params.Set("instanceId", opts.InstanceID)
+	}
+	path := "/vendor/v3/supportbundles?" + params.Encode()
+
+	s.client.logger.DebugContext(ctx, "Listing support bundles", "path", path)
+
+	var result SupportBundleList
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to list support bundles: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed support bundles",
+		"app_id", appID,
+		"count", len(result.SupportBundles))
+
+	return &result, nil
+}
+
+// GetSupportBundle retrieves a specific support bundle, including its analyzer results
+func (s *SupportBundleService) GetSupportBundle(ctx context.Context, bundleID string) (*models.SupportBundle, error) {
+	if bundleID == "" {
+		return nil, fmt.Errorf("support bundle ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/supportbundle/%s", url.PathEscape(bundleID))
+
+	s.client.logger.DebugContext(ctx, "Getting support bundle", "bundle_id", bundleID)
+
+	var result models.SupportBundle
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to get support bundle: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully retrieved support bundle",
+		"bundle_id", result.ID,
+		"analyzers", len(result.Analysis))
+
+	return &result, nil
+}
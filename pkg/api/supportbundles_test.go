@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSupportBundleService_ListSupportBundles(t *testing.T) {
+	tests := []struct {
+		name           string
+		appID          string
+		opts           *ListSupportBundlesOptions
+		mockResponse   string
+		mockStatus     int
+		expectError    bool
+		expectedCount  int
+		expectedFilter map[string]string
+	}{
+		{
+			name:  "list for application",
+			appID: "app-1",
+			mockResponse: `{"supportBundles": [
+				{"id": "bundle-1", "appId": "app-1", "status": "analyzed",
+				 "uploadedAt": "2024-01-01T00:00:00Z", "isArchived": false}
+			]}`,
+			mockStatus:     http.StatusOK,
+			expectedCount:  1,
+			expectedFilter: map[string]string{"appId": "app-1"},
+		},
+		{
+			name:           "list for customer and instance",
+			appID:          "app-1",
+			opts:           &ListSupportBundlesOptions{CustomerID: "customer-1", InstanceID: "instance-1"},
+			mockResponse:   `{"supportBundles": []}`,
+			mockStatus:     http.StatusOK,
+			expectedCount:  0,
+			expectedFilter: map[string]string{"appId": "app-1", "customerId": "customer-1", "instanceId": "instance-1"},
+		},
+		{
+			name:        "missing application ID",
+			appID:       "",
+			expectError: true,
+		},
+		{
+			name:         "API error",
+			appID:        "app-1",
+			mockResponse: `{"message": "Forbidden"}`,
+			mockStatus:   http.StatusForbidden,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/vendor/v3/supportbundles" {
+					t.Errorf("Expected path /vendor/v3/supportbundles, got %s", r.URL.Path)
+				}
+				for key, value := range tt.expectedFilter {
+					if got := r.URL.Query().Get(key); got != value {
+						t.Errorf("Expected %s=%s, got %s", key, value, got)
+					}
+				}
+				w.WriteHeader(tt.mockStatus)
+				fmt.Fprint(w, tt.mockResponse)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			service := NewSupportBundleService(client)
+			result, err := service.ListSupportBundles(context.Background(), tt.appID, tt.opts)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.SupportBundles) != tt.expectedCount {
+				t.Errorf("Expected %d support bundles, got %d", tt.expectedCount, len(result.SupportBundles))
+			}
+		})
+	}
+}
+
+func TestSupportBundleService_GetSupportBundle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vendor/v3/supportbundle/bundle-1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"id": "bundle-1",
+			"appId": "app-1",
+			"customerId": "customer-1",
+			"status": "analyzed",
+			"uploadedAt": "2024-01-01T00:00:00Z",
+			"analysis": [
+				{"name": "storage-class", "severity": "error", "message": "No default storage class"},
+				{"name": "node-count", "severity": "pass"}
+			]
+		}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	service := NewSupportBundleService(client)
+
+	bundle, err := service.GetSupportBundle(context.Background(), "bundle-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bundle.ID != "bundle-1" || len(bundle.Analysis) != 2 {
+		t.Errorf("Unexpected support bundle: %+v", bundle)
+	}
+	if !bundle.HasErrors() {
+		t.Error("Expected analyzer errors to be decoded")
+	}
+
+	if _, err := service.GetSupportBundle(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for missing support bundle")
+	}
+	if _, err := service.GetSupportBundle(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty support bundle ID")
+	}
+}
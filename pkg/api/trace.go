@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache statuses of traced requests
+const (
+	// CacheStatusHit marks a read answered from the disk cache without a request
+	CacheStatusHit = "hit"
+	// CacheStatusRevalidated marks a conditional read the API answered with 304 Not Modified,
+	// served from the response body kept for it
+	CacheStatusRevalidated = "revalidated"
+)
+
+// TracedRequest describes a single API request captured by a RequestTrace. Retries counts
+// the requests sent for the same read before this one: the failed primary endpoint's before
+// a fallback read, and the original before a hedged one.
+type TracedRequest struct {
+	Method      string  `json:"method"`
+	URL         string  `json:"url"`
+	Status      int     `json:"status,omitempty"`
+	DurationMS  float64 `json:"duration_ms"`
+	CacheStatus string  `json:"cache_status,omitempty"`
+	Retries     int     `json:"retries"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// RequestTrace collects the API requests made while serving a single operation.
+// It is safe for concurrent use so that fan-out operations can share one trace.
+type RequestTrace struct {
+	mu       sync.Mutex
+	requests []TracedRequest
+}
+
+type requestTraceKey struct{}
+
+type retriesKey struct{}
+
+// WithRequestTrace returns a context that records API requests into trace
+func WithRequestTrace(ctx context.Context, trace *RequestTrace) context.Context {
+	return context.WithValue(ctx, requestTraceKey{}, trace)
+}
+
+// RequestTraceFromContext returns the trace attached to ctx, or nil if there is none
+func RequestTraceFromContext(ctx context.Context) *RequestTrace {
+	trace, _ := ctx.Value(requestTraceKey{}).(*RequestTrace)
+	return trace
+}
+
+// Record appends a request to the trace
+func (t *RequestTrace) Record(request TracedRequest) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = append(t.requests, request)
+}
+
+// Requests returns a copy of the requests recorded so far
+func (t *RequestTrace) Requests() []TracedRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	requests := make([]TracedRequest, len(t.requests))
+	copy(requests, t.requests)
+	return requests
+}
+
+// withRetry returns a context for sending a read again, counting one more retry of it
+func withRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retriesKey{}, retriesFrom(ctx)+1)
+}
+
+// retriesFrom returns the number of times the read sent with ctx was retried
+func retriesFrom(ctx context.Context) int {
+	retries, _ := ctx.Value(retriesKey{}).(int)
+	return retries
+}
+
+// recordRequest adds a request to the trace attached to ctx, if any, with its duration,
+// retry count, and error
+func recordRequest(ctx context.Context, request TracedRequest, duration time.Duration, err error) {
+	trace := RequestTraceFromContext(ctx)
+	if trace == nil {
+		return
+	}
+
+	request.DurationMS = float64(duration.Microseconds()) / float64(time.Millisecond/time.Microsecond)
+	request.Retries = retriesFrom(ctx)
+	if err != nil {
+		request.Error = err.Error()
+	}
+	trace.Record(request)
+}
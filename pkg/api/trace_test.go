@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_RequestTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{
+		APIToken: "test-token",
+		BaseURL:  server.URL,
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	trace := &RequestTrace{}
+	ctx := WithRequestTrace(context.Background(), trace)
+
+	for _, path := range []string{"/test", "/missing"} {
+		resp, err := client.Get(ctx, path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	requests := trace.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 traced requests, got %d", len(requests))
+	}
+
+	if requests[0].Method != testMethodGET || requests[0].URL != server.URL+"/test" {
+		t.Errorf("Unexpected first request: %+v", requests[0])
+	}
+	if requests[0].Status != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", requests[0].Status)
+	}
+	if requests[1].Status != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", requests[1].Status)
+	}
+}
+
+func TestClient_RequestTraceCaching(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"release-1"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{
+		APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second, DiskCacheDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	trace := &RequestTrace{}
+	ctx := WithRequestTrace(context.Background(), trace)
+	cacheable := func() bool { return true }
+	for range 2 {
+		var result map[string]any
+		if err := client.getCachedJSON(ctx, "/cached", &result, cacheable); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := client.getJSON(ctx, "/validated", &result); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	want := []TracedRequest{
+		{URL: server.URL + "/cached", Status: http.StatusOK},
+		{URL: server.URL + "/validated", Status: http.StatusOK},
+		{URL: server.URL + "/cached", Status: http.StatusOK, CacheStatus: CacheStatusHit},
+		{URL: server.URL + "/validated", Status: http.StatusNotModified, CacheStatus: CacheStatusRevalidated},
+	}
+	requests := trace.Requests()
+	if len(requests) != len(want) {
+		t.Fatalf("Expected %d traced requests, got %+v", len(want), requests)
+	}
+	for i, request := range requests {
+		if request.Method != testMethodGET || request.URL != want[i].URL || request.Status != want[i].Status ||
+			request.CacheStatus != want[i].CacheStatus {
+			t.Errorf("Expected request %d to be %+v, got %+v", i, want[i], request)
+		}
+	}
+}
+
+func TestClient_RequestTraceRetries(t *testing.T) {
+	primary := newEndpointServer(t, `{"source":"primary"}`)
+	fallback := newEndpointServer(t, `{"source":"fallback"}`)
+	primary.failing.Store(true)
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: primary.URL, FallbackURL: fallback.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.failoverState().threshold = 1
+
+	trace := &RequestTrace{}
+	if err := client.getJSON(WithRequestTrace(context.Background(), trace), testPath, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	requests := trace.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected the primary and fallback reads to be traced, got %+v", requests)
+	}
+	if requests[0].URL != primary.URL+testPath || requests[0].Retries != 0 {
+		t.Errorf("Expected the primary read not to be a retry, got %+v", requests[0])
+	}
+	if requests[1].URL != fallback.URL+testPath || requests[1].Retries != 1 {
+		t.Errorf("Expected the fallback read to retry the primary one, got %+v", requests[1])
+	}
+}
+
+func TestRequestTraceFromContext(t *testing.T) {
+	if trace := RequestTraceFromContext(context.Background()); trace != nil {
+		t.Errorf("Expected nil trace for bare context, got %v", trace)
+	}
+
+	trace := &RequestTrace{}
+	ctx := WithRequestTrace(context.Background(), trace)
+	if got := RequestTraceFromContext(ctx); got != trace {
+		t.Errorf("Expected trace to be returned from context")
+	}
+
+	// Recording without a trace must be a no-op
+	recordRequest(context.Background(), TracedRequest{Method: "GET", URL: "https://example.com"}, time.Millisecond, nil)
+}
@@ -2,25 +2,89 @@ package api
 
 import (
 	"fmt"
+	"io/fs"
+	"net/url"
 	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
 )
 
-// ClientConfig holds configuration for the API client
+// ClientConfig holds configuration for the API client.
+// When FallbackURL is set, reads fail over to it while BaseURL is unavailable.
+// MaxConcurrentRequests limits in-flight requests per host and RequestsPerSecond the rate they
+// are sent at; zero means unlimited.
+// When HARFile is set, API traffic is captured to it with credentials and personal data redacted
+// by Redactor, or by the default redactor when it is nil.
 type ClientConfig struct {
-	APIToken string
-	BaseURL  string
-	Timeout  time.Duration
+	APIToken              string
+	BaseURL               string
+	FallbackURL           string
+	Timeout               time.Duration
+	Hedging               HedgePolicy
+	MaxConcurrentRequests int
+	RequestsPerSecond     float64
+	HARFile               string
+	Redactor              *redact.Redactor
+
+	// DiskCacheDir is the directory immutable responses, such as published releases, are
+	// cached in across restarts. Empty disables the disk cache.
+	DiskCacheDir string
+
+	// Fixtures, when set, answers requests from JSON files instead of the Vendor Portal API,
+	// for demos and offline testing. No API token is needed. See DefaultFixtures.
+	Fixtures fs.FS
+
+	// RecordDir is the directory successful responses are recorded to as fixtures, redacted
+	// by Redactor, so the session can be replayed with Fixtures. Empty disables recording.
+	RecordDir string
+
+	// ProxyURL is the proxy requests are sent through. When it is empty, the HTTP_PROXY,
+	// HTTPS_PROXY, and NO_PROXY environment variables choose the proxy, if any.
+	ProxyURL string
+
+	// CABundle is a PEM file of certificate authorities trusted in addition to the system's,
+	// such as the one an egress proxy intercepting TLS signs with
+	CABundle string
+
+	// InsecureSkipVerify turns off verification of the API's TLS certificate. It exposes the
+	// API token to anyone able to intercept the connection, so it is for troubleshooting only.
+	InsecureSkipVerify bool
+
+	// ClientCertFile and ClientKeyFile are a PEM certificate and key presented to the API, for
+	// gateways in front of the Vendor Portal that require mutual TLS
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// Headers are added to every API request, such as a key an internal API gateway requires.
+	// They cannot replace the Authorization and User-Agent headers the client sets.
+	Headers map[string]string
+}
+
+// redactor returns the configured redactor, or the default one
+func (c ClientConfig) redactor() *redact.Redactor {
+	if c.Redactor == nil {
+		return redact.Default()
+	}
+	return c.Redactor
 }
 
 // Validate ensures the configuration is valid
 func (c ClientConfig) Validate() error {
-	if c.APIToken == "" {
+	if c.APIToken == "" && c.Fixtures == nil {
 		return fmt.Errorf("API token is required")
 	}
 	if c.BaseURL == "" {
 		return fmt.Errorf("base URL is required")
 	}
-	return nil
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		return fmt.Errorf("client certificate and key must be set together")
+	}
+	if c.ProxyURL != "" {
+		if u, err := url.Parse(c.ProxyURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL '%s': must include scheme and host", c.ProxyURL)
+		}
+	}
+	return validateFallbackURL(c.FallbackURL)
 }
 
 // Error represents an error response from the API
@@ -37,11 +101,33 @@ func (e Error) Error() string {
 	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
 }
 
-// PaginatedResponse wraps paginated API responses
-type PaginatedResponse[T any] struct {
-	Data       []T  `json:"data"`
-	TotalCount int  `json:"total_count"`
-	Page       int  `json:"page"`
-	PageSize   int  `json:"page_size"`
-	HasMore    bool `json:"has_more"`
+// RequestErrorKind classifies a request that ended before a response was received
+type RequestErrorKind string
+
+// Request error kinds
+const (
+	// RequestCanceled means the caller canceled the request
+	RequestCanceled RequestErrorKind = "canceled"
+	// RequestTimeout means the API did not respond within the client's configured timeout
+	RequestTimeout RequestErrorKind = "timeout"
+	// RequestDeadlineExceeded means the caller's own deadline passed before the API responded
+	RequestDeadlineExceeded RequestErrorKind = "deadline_exceeded"
+)
+
+// RequestError reports a request that was canceled or timed out before a response was
+// received. Kind tells callers whether to retry, wait, or treat it as a configuration problem.
+type RequestError struct {
+	Kind   RequestErrorKind
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s %s: request %s: %v", e.Method, e.URL, e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RequestError) Unwrap() error {
+	return e.Err
 }
@@ -98,46 +98,3 @@ func TestError_Error(t *testing.T) {
 		})
 	}
 }
-
-func TestPaginatedResponse(t *testing.T) {
-	// Test that PaginatedResponse works with different types
-	t.Run("string slice", func(t *testing.T) {
-		response := PaginatedResponse[string]{
-			Data:       []string{"item1", "item2", "item3"},
-			TotalCount: 100,
-			Page:       1,
-			PageSize:   3,
-			HasMore:    true,
-		}
-
-		if len(response.Data) != 3 {
-			t.Errorf("Expected 3 items, got %d", len(response.Data))
-		}
-		if response.Data[0] != "item1" {
-			t.Errorf("Expected first item to be 'item1', got %v", response.Data[0])
-		}
-		if !response.HasMore {
-			t.Error("Expected HasMore to be true")
-		}
-	})
-
-	t.Run("int slice", func(t *testing.T) {
-		response := PaginatedResponse[int]{
-			Data:       []int{1, 2, 3},
-			TotalCount: 50,
-			Page:       2,
-			PageSize:   3,
-			HasMore:    false,
-		}
-
-		if len(response.Data) != 3 {
-			t.Errorf("Expected 3 items, got %d", len(response.Data))
-		}
-		if response.Data[0] != 1 {
-			t.Errorf("Expected first item to be 1, got %v", response.Data[0])
-		}
-		if response.HasMore {
-			t.Error("Expected HasMore to be false")
-		}
-	})
-}
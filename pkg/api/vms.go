@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// VMService provides methods for interacting with Compatibility Matrix VM APIs
+type VMService struct {
+	client *Client
+}
+
+// NewVMService creates a new VMService
+func NewVMService(client *Client) *VMService {
+	return &VMService{
+		client: client,
+	}
+}
+
+// VMList represents a list of Compatibility Matrix VMs and the team's VM quota
+type VMList struct {
+	VMs   []models.VM     `json:"vms"`
+	Quota *models.VMQuota `json:"quota,omitempty"`
+}
+
+// ListVMs retrieves the team's Compatibility Matrix VMs and VM quota. Terminated VMs are
+// only included when requested.
+func (s *VMService) ListVMs(ctx context.Context, includeTerminated bool) (*VMList, error) {
+	path := "/vendor/v3/vms"
+	if includeTerminated {
+		path += "?show-terminated=true"
+	}
+
+	s.client.logger.DebugContext(ctx, "Listing VMs", "path", path)
+
+	var result VMList
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to list VMs: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully listed VMs", "count", len(result.VMs))
+
+	return &result, nil
+}
+
+// CreateVM requests a new Compatibility Matrix VM. The VM is returned while it is still
+// queued or provisioning.
+func (s *VMService) CreateVM(ctx context.Context, spec *models.VMSpec) (*models.VM, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.client.logger.DebugContext(ctx, "Creating VM",
+		"distribution", spec.Distribution,
+		"version", spec.Version)
+
+	var result vmResponse
+	if err := s.client.postJSON(ctx, "/vendor/v3/vm", spec, &result); err != nil {
+		return nil, fmt.Errorf("failed to create VM: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully created VM",
+		"vm_id", result.VM.ID,
+		"status", result.VM.Status)
+
+	return &result.VM, nil
+}
+
+// DeleteVM terminates a Compatibility Matrix VM
+func (s *VMService) DeleteVM(ctx context.Context, vmID string) error {
+	if vmID == "" {
+		return fmt.Errorf("VM ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/vm/%s", url.PathEscape(vmID))
+
+	s.client.logger.DebugContext(ctx, "Deleting VM", "vm_id", vmID)
+
+	if err := s.client.deleteJSON(ctx, path, nil); err != nil {
+		return fmt.Errorf("failed to delete VM: %w", err)
+	}
+
+	s.client.logger.DebugContext(ctx, "Successfully deleted VM", "vm_id", vmID)
+
+	return nil
+}
+
+// GetSSHEndpoint retrieves the SSH address of a running Compatibility Matrix VM
+func (s *VMService) GetSSHEndpoint(ctx context.Context, vmID string) (*models.SSHEndpoint, error) {
+	if vmID == "" {
+		return nil, fmt.Errorf("VM ID is required")
+	}
+
+	path := fmt.Sprintf("/vendor/v3/vm/%s/ssh-endpoint", url.PathEscape(vmID))
+
+	s.client.logger.DebugContext(ctx, "Getting VM SSH endpoint", "vm_id", vmID)
+
+	var result models.SSHEndpoint
+	if err := s.client.getJSON(ctx, path, &result); err != nil {
+		return nil, fmt.Errorf("failed to get VM SSH endpoint: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// newTestVMService serves a running VM, one terminated VM, and the team's VM quota
+func newTestVMService(t *testing.T) *VMService {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/vms", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("show-terminated") == "true" {
+			fmt.Fprint(w, `{"vms": [
+				{"id": "vm-1", "status": "running"}, {"id": "vm-0", "status": "terminated"}
+			], "quota": {"maxVms": 5, "activeVms": 1}}`)
+			return
+		}
+		fmt.Fprint(w, `{"vms": [{"id": "vm-1", "status": "running"}], "quota": {"maxVms": 5, "activeVms": 1}}`)
+	})
+	mux.HandleFunc("POST /vendor/v3/vm", func(w http.ResponseWriter, r *http.Request) {
+		var spec models.VMSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"vm": {"id": "vm-2", "distribution": %q, "status": "queued"}}`, spec.Distribution)
+	})
+	mux.HandleFunc("DELETE /vendor/v3/vm/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "vm-1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/vm/vm-1/ssh-endpoint", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"host": "vm-1.cmx.example.com", "port": 32222, "user": "ubuntu"}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(ClientConfig{APIToken: "test-token", BaseURL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return NewVMService(client)
+}
+
+func TestVMService_ListVMs(t *testing.T) {
+	service := newTestVMService(t)
+
+	tests := []struct {
+		name              string
+		includeTerminated bool
+		expectedCount     int
+	}{
+		{name: "active VMs", expectedCount: 1},
+		{name: "including terminated VMs", includeTerminated: true, expectedCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ListVMs(context.Background(), tt.includeTerminated)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.VMs) != tt.expectedCount {
+				t.Errorf("Expected %d VMs, got %d", tt.expectedCount, len(result.VMs))
+			}
+			if result.Quota == nil || result.Quota.MaxVMs != 5 {
+				t.Errorf("Expected the VM quota, got %+v", result.Quota)
+			}
+		})
+	}
+}
+
+func TestVMService_CreateVM(t *testing.T) {
+	service := newTestVMService(t)
+
+	vm, err := service.CreateVM(context.Background(), &models.VMSpec{Distribution: "ubuntu", TTL: "1h"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vm.ID != "vm-2" || vm.Distribution != "ubuntu" || vm.Status != models.ClusterStatusQueued {
+		t.Errorf("Unexpected VM: %+v", vm)
+	}
+
+	if _, err := service.CreateVM(context.Background(), &models.VMSpec{}); err == nil {
+		t.Error("Expected error for a spec without a distribution")
+	}
+}
+
+func TestVMService_DeleteVM(t *testing.T) {
+	service := newTestVMService(t)
+
+	tests := []struct {
+		name        string
+		vmID        string
+		expectError bool
+	}{
+		{name: "delete VM", vmID: "vm-1"},
+		{name: "unknown VM", vmID: "vm-9", expectError: true},
+		{name: "missing VM ID", vmID: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.DeleteVM(context.Background(), tt.vmID)
+			if (err != nil) != tt.expectError {
+				t.Errorf("DeleteVM() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestVMService_GetSSHEndpoint(t *testing.T) {
+	service := newTestVMService(t)
+
+	endpoint, err := service.GetSSHEndpoint(context.Background(), "vm-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if endpoint.Host != "vm-1.cmx.example.com" || endpoint.Port != 32222 || endpoint.User != "ubuntu" {
+		t.Errorf("Unexpected SSH endpoint: %+v", endpoint)
+	}
+
+	if _, err := service.GetSSHEndpoint(context.Background(), "vm-9"); err == nil {
+		t.Error("Expected error for an unknown VM")
+	}
+}
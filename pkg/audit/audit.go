@@ -0,0 +1,106 @@
+// Package audit records tool calls that change the vendor account, such as creating
+// clusters or promoting releases, to an append-only JSON Lines file. Each line is one call:
+// the tool, its arguments with secrets redacted, the client session and request it came
+// from, and its outcome. The file is only ever appended to, so it can be shipped to a log
+// pipeline or kept as a record of what agents have done.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+)
+
+// Outcomes of an audited call
+const (
+	// OutcomeSucceeded is a call that made its change
+	OutcomeSucceeded = "succeeded"
+	// OutcomeFailed is a call that returned an error
+	OutcomeFailed = "failed"
+	// OutcomeUnconfirmed is a call refused because it needed the caller's confirmation
+	OutcomeUnconfirmed = "unconfirmed"
+	// OutcomeDryRun is a call that previewed its change without making it
+	OutcomeDryRun = "dry_run"
+)
+
+// Redacted replaces the values of sensitive arguments
+const Redacted = redact.Mask
+
+// filePerm keeps the audit log readable only by its owner
+const filePerm = 0o600
+
+// Entry is one audited tool call
+type Entry struct {
+	Time      time.Time      `json:"time"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+	SessionID string         `json:"session_id,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Outcome   string         `json:"outcome"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// Log appends audit entries to a file. It is safe for concurrent use.
+type Log struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	redactor *redact.Redactor
+}
+
+// Open opens the audit log at path for appending, creating it if needed. Arguments are
+// redacted with redactor, or with the default redactor when it is nil.
+func Open(path string, redactor *redact.Redactor) (*Log, error) {
+	if redactor == nil {
+		redactor = redact.Default()
+	}
+	path = filepath.Clean(path)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Log{path: path, file: file, redactor: redactor}, nil
+}
+
+// Path returns the file the log appends to
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends an entry as a single line. The entry's arguments are redacted in a copy,
+// so the caller's arguments are left unchanged.
+func (l *Log) Record(entry Entry) error {
+	entry.Arguments = l.redactor.Map(entry.Arguments)
+	entry.Error = l.redactor.String(entry.Error)
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Close closes the audit log file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Redact returns a copy of tool call arguments with the values of sensitive arguments,
+// including those nested in objects and lists, replaced by Redacted
+func Redact(arguments map[string]any) map[string]any {
+	return redact.Default().Map(arguments)
+}
@@ -0,0 +1,200 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+)
+
+// readEntries parses the JSON lines of an audit log file
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+
+	var entries []Entry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("Failed to decode audit log line %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestLog_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	log, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	arguments := map[string]any{"app_id": "app-1", "api_token": "secret-value"}
+	entry := Entry{
+		Time:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tool:      "promote_release",
+		Arguments: arguments,
+		SessionID: "session-1",
+		RequestID: "request-1",
+		Outcome:   OutcomeSucceeded,
+	}
+	if err := log.Record(entry); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if arguments["api_token"] != "secret-value" {
+		t.Errorf("Record() changed the caller's arguments: %v", arguments)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Reopening appends rather than truncating
+	log, err = Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	unconfirmed := Entry{Tool: "delete_vm", Outcome: OutcomeUnconfirmed, Error: "confirmation required"}
+	if err := log.Record(unconfirmed); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Arguments["api_token"] != Redacted || entries[0].Arguments["app_id"] != "app-1" {
+		t.Errorf("Expected the token to be redacted, got %v", entries[0].Arguments)
+	}
+	if entries[0].SessionID != "session-1" || entries[0].RequestID != "request-1" {
+		t.Errorf("Expected session and request IDs, got %+v", entries[0])
+	}
+	if entries[1].Outcome != OutcomeUnconfirmed || entries[1].Arguments == nil {
+		t.Errorf("Expected an unconfirmed entry with empty arguments, got %+v", entries[1])
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat audit log: %v", err)
+	}
+	if info.Mode().Perm() != filePerm {
+		t.Errorf("Expected permissions %o, got %o", filePerm, info.Mode().Perm())
+	}
+}
+
+func TestLog_RecordConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	const calls = 50
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry := Entry{Tool: "create_vm", Arguments: map[string]any{"name": fmt.Sprintf("vm-%d", i)}}
+			if err := log.Record(entry); err != nil {
+				t.Errorf("Record() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if entries := readEntries(t, path); len(entries) != calls {
+		t.Errorf("Expected %d entries, got %d", calls, len(entries))
+	}
+}
+
+func TestLog_RecordWithRedactor(t *testing.T) {
+	redactor, err := redact.New([]string{`lic_[a-z0-9]+`})
+	if err != nil {
+		t.Fatalf("redact.New() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(path, redactor)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	entry := Entry{
+		Tool:      "update_customer",
+		Arguments: map[string]any{"customer_id": "cust-1", "notes": "moved from lic_abc123", "email": "ops@acme.example"},
+		Outcome:   OutcomeFailed,
+		Error:     "license lic_abc123 is archived",
+	}
+	if err := log.Record(entry); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	for _, leak := range []string{"lic_abc123", "ops@acme.example"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Audit log contains %s: %s", leak, data)
+		}
+	}
+	if entries := readEntries(t, path); entries[0].Arguments["customer_id"] != "cust-1" {
+		t.Errorf("Expected other arguments kept, got %v", entries[0].Arguments)
+	}
+}
+
+func TestOpen_Error(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing", "audit.jsonl"), nil); err == nil {
+		t.Error("Expected an error opening a log in a missing directory")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		want      map[string]any
+	}{
+		{name: "nil arguments", arguments: nil, want: map[string]any{}},
+		{
+			name:      "sensitive names in any style",
+			arguments: map[string]any{"Password": "p", "client-secret": "s", "apiKey": "k", "name": "Acme"},
+			want:      map[string]any{"Password": Redacted, "client-secret": Redacted, "apiKey": Redacted, "name": "Acme"},
+		},
+		{
+			name: "nested arguments",
+			arguments: map[string]any{"operations": []any{
+				map[string]any{"tool": "update_customer", "arguments": map[string]any{"auth_token": "t"}},
+			}},
+			want: map[string]any{"operations": []any{
+				map[string]any{"tool": "update_customer", "arguments": map[string]any{"auth_token": Redacted}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.arguments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
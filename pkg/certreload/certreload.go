@@ -0,0 +1,131 @@
+// Package certreload serves a TLS certificate and key from files, picking up new versions
+// when the files change. Renewal tools such as certbot or cert-manager replace the files in
+// place, so the HTTP transport can use renewed certificates without a restart.
+package certreload
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// checkInterval is how often the files are checked for changes. Checks happen during
+// handshakes, so an idle server does not touch the files.
+const checkInterval = 5 * time.Second
+
+// Reloader provides the current certificate for TLS handshakes
+type Reloader struct {
+	certFile string
+	keyFile  string
+	logger   logging.Logger
+	now      func() time.Time
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	loaded      fileStamps
+	checkedAt   time.Time
+}
+
+// fileStamps identifies the versions of the certificate and key files
+type fileStamps struct {
+	cert stamp
+	key  stamp
+}
+
+// stamp identifies a version of a file by its modification time and size
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// New loads the certificate and key, failing if they cannot be used, and returns a Reloader
+// serving them
+func New(certFile, keyFile string, logger logging.Logger) (*Reloader, error) {
+	r := &Reloader{
+		certFile: filepath.Clean(certFile),
+		keyFile:  filepath.Clean(keyFile),
+		logger:   logger,
+		now:      time.Now,
+	}
+
+	stamps, err := r.stamps()
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	r.certificate = &certificate
+	r.loaded = stamps
+	r.checkedAt = r.now()
+	return r, nil
+}
+
+// GetCertificate returns the current certificate, reloading it first if the files have
+// changed. It is meant for tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := r.now(); now.Sub(r.checkedAt) >= checkInterval {
+		r.checkedAt = now
+		r.reload()
+	}
+	return r.certificate, nil
+}
+
+// reload loads the files if they have changed since they were last loaded. A certificate
+// that fails to load, such as one caught halfway through being replaced, leaves the current
+// certificate in place; it is tried again once the files change again.
+func (r *Reloader) reload() {
+	stamps, err := r.stamps()
+	if err != nil {
+		r.logger.Error("Failed to check TLS certificate files", "error", err)
+		return
+	}
+	if stamps == r.loaded {
+		return
+	}
+	r.loaded = stamps
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		r.logger.Error("Failed to reload TLS certificate, keeping the current one",
+			"cert_file", r.certFile,
+			"error", err)
+		return
+	}
+
+	r.certificate = &certificate
+	r.logger.Info("Reloaded TLS certificate", "cert_file", r.certFile)
+}
+
+// stamps returns the current versions of the certificate and key files
+func (r *Reloader) stamps() (fileStamps, error) {
+	cert, err := fileStamp(r.certFile)
+	if err != nil {
+		return fileStamps{}, err
+	}
+	key, err := fileStamp(r.keyFile)
+	if err != nil {
+		return fileStamps{}, err
+	}
+	return fileStamps{cert: cert, key: key}, nil
+}
+
+// fileStamp returns the version of a file, following symlinks so that renewal tools that
+// swap links are noticed
+func fileStamp(path string) (stamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}, fmt.Errorf("failed to read TLS file: %w", err)
+	}
+	return stamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
@@ -0,0 +1,161 @@
+package certreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// writeCertificate writes a self-signed certificate for commonName and its key, dating the
+// files at modTime so changes are visible regardless of the filesystem's timestamp resolution
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	writePEM(t, certFile, "CERTIFICATE", der, modTime)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER, modTime)
+}
+
+func writePEM(t *testing.T, path, blockType string, data []byte, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to date %s: %v", path, err)
+	}
+}
+
+// commonName returns the subject of the certificate a reloader serves
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+
+	certificate, err := r.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetCertificate() unexpected error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestReloader_GetCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	written := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeCertificate(t, certFile, keyFile, "original", written)
+
+	reloader, err := New(certFile, keyFile, logging.NewLoggerWithWriter("fatal", io.Discard))
+	if err != nil {
+		t.Fatalf("New() unexpected error = %v", err)
+	}
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reloader.now = func() time.Time { return clock }
+	reloader.checkedAt = clock
+
+	if got := commonName(t, reloader); got != "original" {
+		t.Fatalf("Expected the original certificate, got %q", got)
+	}
+
+	// Renewed files are only noticed once the check interval has passed
+	writeCertificate(t, certFile, keyFile, "renewed", written.Add(time.Hour))
+	if got := commonName(t, reloader); got != "original" {
+		t.Errorf("Expected the original certificate before the next check, got %q", got)
+	}
+	clock = clock.Add(checkInterval)
+	if got := commonName(t, reloader); got != "renewed" {
+		t.Errorf("Expected the renewed certificate after the check interval, got %q", got)
+	}
+
+	// A broken replacement keeps the current certificate
+	writePEM(t, keyFile, "EC PRIVATE KEY", []byte("truncated"), written.Add(2*time.Hour))
+	clock = clock.Add(checkInterval)
+	if got := commonName(t, reloader); got != "renewed" {
+		t.Errorf("Expected the renewed certificate to stay after a failed reload, got %q", got)
+	}
+
+	// Once the replacement is complete, it is loaded
+	writeCertificate(t, certFile, keyFile, "fixed", written.Add(3*time.Hour))
+	clock = clock.Add(checkInterval)
+	if got := commonName(t, reloader); got != "fixed" {
+		t.Errorf("Expected the fixed certificate, got %q", got)
+	}
+
+	// Missing files keep the current certificate
+	if err := os.Remove(certFile); err != nil {
+		t.Fatalf("Failed to remove certificate: %v", err)
+	}
+	clock = clock.Add(checkInterval)
+	if got := commonName(t, reloader); got != "fixed" {
+		t.Errorf("Expected the current certificate while the files are missing, got %q", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	writeCertificate(t, certFile, keyFile, "server", time.Now())
+
+	otherCert := filepath.Join(dir, "other.crt")
+	otherKey := filepath.Join(dir, "other.key")
+	writeCertificate(t, otherCert, otherKey, "other", time.Now())
+
+	tests := []struct {
+		name        string
+		certFile    string
+		keyFile     string
+		expectError bool
+	}{
+		{name: "matching certificate and key", certFile: certFile, keyFile: keyFile},
+		{name: "missing certificate", certFile: filepath.Join(dir, "missing.crt"), keyFile: keyFile, expectError: true},
+		{name: "missing key", certFile: certFile, keyFile: filepath.Join(dir, "missing.key"), expectError: true},
+		{name: "mismatched key", certFile: certFile, keyFile: otherKey, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.certFile, tt.keyFile, logging.NewLoggerWithWriter("fatal", io.Discard))
+			if tt.expectError && err == nil {
+				t.Error("New() expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("New() unexpected error = %v", err)
+			}
+		})
+	}
+}
@@ -1,18 +1,30 @@
 // Package config provides configuration management for the Replicated MCP Server.
-// It supports loading configuration from environment variables and CLI flags,
-// with comprehensive validation and helpful error messages.
+// It supports loading configuration from a config file with named profiles, environment
+// variables, and CLI flags, with comprehensive validation and helpful error messages.
 package config
 
 import (
 	"fmt"
+	"maps"
+	"net"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/cors"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+	"github.com/crdant/replicated-mcp-server/pkg/render"
+	"github.com/crdant/replicated-mcp-server/pkg/slo"
 )
 
 // Config represents the application configuration
@@ -21,25 +33,270 @@ type Config struct {
 	LogLevel string
 	Timeout  time.Duration
 	Endpoint string
+	DevMode  bool
+	Locale   string
+	DataDir  string
+	ReadOnly bool
+
+	// DiskCache keeps immutable API responses, such as the manifests of published releases, in
+	// files under CacheDir so they are downloaded once across sessions
+	DiskCache bool
+	CacheDir  string
+
+	// ResourcePollInterval is how often the resources clients subscribe to are checked for
+	// changes, which are sent to the subscribers as resource update notifications. Zero turns
+	// the checks off.
+	ResourcePollInterval time.Duration
+
+	// DryRun makes mutating tools preview the change each call would make instead of making
+	// it, as if every call set dry_run
+	DryRun bool
+
+	// Mock answers Vendor Portal API requests from JSON fixtures instead of the network, so
+	// the server can be demonstrated and tested without a token. MockFixtures is the
+	// directory the fixtures are read from; the built-in demo fixtures are used when it is
+	// empty.
+	Mock         bool
+	MockFixtures string
+
+	// RecordFixtures is the directory successful API responses are recorded to, redacted, in
+	// the layout mock mode reads, so a session can be replayed with MockFixtures
+	RecordFixtures string
+
+	// App is the ID or slug of the application tools use when a call omits app_id
+	App string
+
+	// ClusterCommands exposes run_cluster_command, which runs allow-listed kubectl commands
+	// against Compatibility Matrix clusters the server created
+	ClusterCommands bool
+
+	// APITokenFile, APITokenCommand, and APITokenKeychain are alternative sources for the
+	// API token, read when no token is set directly. At most one may be set.
+	APITokenFile     string
+	APITokenCommand  string
+	APITokenKeychain string
+
+	// FallbackEndpoint receives reads while Endpoint fails persistently
+	FallbackEndpoint string
+
+	// Proxy is the proxy API requests are sent through, overriding the HTTP_PROXY, HTTPS_PROXY,
+	// and NO_PROXY environment variables, which are honored when it is empty. CABundle is a PEM
+	// file of certificate authorities trusted in addition to the system's, such as the one an
+	// egress proxy intercepting TLS signs with. InsecureSkipTLSVerify turns off certificate
+	// verification of API requests entirely, for troubleshooting only.
+	Proxy                 string
+	CABundle              string
+	InsecureSkipTLSVerify bool
+
+	// ClientCertFile and ClientKeyFile are a certificate and key presented to the API, and
+	// APIHeaders are added to every API request, for internal API gateways in front of the
+	// Vendor Portal. They are set in the config file.
+	ClientCertFile string
+	ClientKeyFile  string
+	APIHeaders     map[string]string
+
+	// HedgeReads enables hedged reads; HedgeClasses optionally limits them to endpoint
+	// classes, each either adaptive ("customers") or with a fixed delay ("releases=250ms")
+	HedgeReads   bool
+	HedgeClasses []string
+
+	// MaxConcurrentRequests limits simultaneous in-flight requests per API host (0 means unlimited)
+	MaxConcurrentRequests int
+
+	// RequestsPerSecond limits the rate requests are sent to each API host (0 means unlimited)
+	RequestsPerSecond float64
+
+	// SearchScanLimit caps how many customers a search reads before filtering (0 means no cap).
+	// Searches that stop at the cap report their results as truncated.
+	SearchScanLimit int
+
+	// SearchIndex ranks search results by relevance using a local index of each application's
+	// entities, built on the first search and refreshed when it goes stale
+	SearchIndex bool
+
+	// MaxRequestBytes caps the size of HTTP transport request bodies. MaxArgumentBytes caps the
+	// JSON-encoded size of each tool call argument, and MaxArrayLength the number of items in
+	// any list within the arguments. MaxResultBytes caps the size of each tool result, which is
+	// cut down to fit by dropping items from its longest lists. Zero means no limit.
+	MaxRequestBytes  int
+	MaxArgumentBytes int
+	MaxArrayLength   int
+	MaxResultBytes   int
+
+	// Transport is how MCP clients connect: stdio (the default, also used when empty) or
+	// http. The HTTP transport listens on ListenAddress.
+	Transport     string
+	ListenAddress string
+
+	// HTTPAuthToken is the bearer token HTTP transport clients must send in the Authorization
+	// header. It is required to listen on an address other than loopback.
+	HTTPAuthToken string
+
+	// AccessLogFile receives the HTTP transport's access logs, which go to stderr when it is
+	// empty. AccessLogSampleRate is the fraction of successful requests logged.
+	AccessLogFile       string
+	AccessLogSampleRate float64
+
+	// MetricsEndpoint serves the server's metrics for Prometheus to scrape at /metrics on the
+	// HTTP transport
+	MetricsEndpoint bool
+
+	// OTLPEndpoint is the OTLP/HTTP collector tool call and API request traces are exported
+	// to, such as http://localhost:4318. Empty turns tracing off.
+	OTLPEndpoint string
+
+	// TLSCertFile and TLSKeyFile serve the HTTP transport over TLS, picking up renewed files
+	// without a restart. TLSAutocertDomains instead obtains certificates for these domains
+	// from Let's Encrypt.
+	TLSCertFile        string
+	TLSKeyFile         string
+	TLSAutocertDomains []string
+
+	// AllowedOrigins lists the browser origins (such as https://agent.example.com) allowed to
+	// call the HTTP transport, or "*" for any. Browser requests from other origins are rejected.
+	AllowedOrigins []string
+
+	// SLOAvailability and SLOLatencyTarget are the fractions of Vendor Portal API requests that
+	// should succeed and that should complete within SLOLatency. Requests are tracked against
+	// them, and a warning is logged when the portal burns through its error budget. Zero
+	// values mean the defaults.
+	SLOAvailability  float64
+	SLOLatency       time.Duration
+	SLOLatencyTarget float64
+
+	// HARFile, when set, receives a HAR capture of API traffic with tokens and personal data redacted
+	HARFile string
+
+	// AuditLogFile, when set, receives an append-only JSON Lines record of every tool call that
+	// changes the vendor account, with secrets redacted
+	AuditLogFile string
+
+	// RedactPatterns are regular expressions, such as a license ID format, masked wherever they
+	// appear in logs, HAR captures, and audit records, in addition to credentials and emails
+	RedactPatterns []string
+
+	// EnabledTools and DisabledTools hold tool names or tool categories
+	// (e.g. "customers") that restrict which tools and resources are exposed
+	EnabledTools  []string
+	DisabledTools []string
+
+	// ToolTimeout limits how long a tool call may run before it is canceled and reported to
+	// the agent as timed out, and ToolTimeouts overrides it for the tools it names. Zero means
+	// no limit.
+	ToolTimeout  time.Duration
+	ToolTimeouts map[string]time.Duration
+
+	// ChannelPolicies holds promotion policies keyed by channel name, slug, or ID. They can
+	// only be set in the config file.
+	ChannelPolicies map[string]models.PromotionPolicy
+
+	// Profile is the config file profile the configuration was loaded from, if any
+	Profile string
 }
 
 // Validation constants
 const (
 	DefaultLogLevel = "fatal"
+	DefaultDataDir  = ".replicated-mcp-server"
+	DefaultCacheDir = "replicated-mcp-server"
 	DefaultTimeout  = 30 * time.Second
 	MinTimeout      = 1 * time.Second
 	MaxTimeout      = 300 * time.Second
+
+	DefaultMaxConcurrentRequests = api.DefaultMaxConcurrentRequests
+	DefaultRequestsPerSecond     = api.DefaultRequestsPerSecond
+	DefaultSearchScanLimit       = 5000
+
+	DefaultMaxRequestBytes  = 4 << 20
+	DefaultMaxArgumentBytes = 1 << 20
+	DefaultMaxArrayLength   = 1000
+	DefaultMaxResultBytes   = 256 << 10
+
+	DefaultListenAddress       = "127.0.0.1:8080"
+	DefaultAccessLogSampleRate = 1.0
+
+	DefaultSLOAvailability  = slo.DefaultAvailability
+	DefaultSLOLatency       = slo.DefaultLatency
+	DefaultSLOLatencyTarget = slo.DefaultLatencyTarget
+
+	DefaultResourcePollInterval = time.Minute
+	DefaultToolTimeout          = 2 * time.Minute
+)
+
+// Transports MCP clients can connect over
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
 )
 
+// ValidTransports contains all supported transport names
+var ValidTransports = []string{TransportStdio, TransportHTTP}
+
 // ValidLogLevels contains all supported log level names
 var ValidLogLevels = []string{"fatal", "error", "info", "debug", "trace"}
 
-// Load creates a new Config by loading from environment variables and CLI flags
-// CLI flags take precedence over environment variables
+// headerNamePattern matches valid HTTP header names
+var headerNamePattern = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+// reservedAPIHeaders are the headers the API client sets on every request, which extra API
+// headers cannot replace
+var reservedAPIHeaders = []string{"Authorization", "User-Agent"}
+
+// ValidProxySchemes contains the URL schemes of the proxies API requests can be sent through
+var ValidProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+// Load creates a new Config by loading from the config file, environment variables, and
+// CLI flags. CLI flags take precedence over environment variables, which take precedence
+// over the selected config file profile and then the file's top-level settings.
 func Load(cmd *cobra.Command) (*Config, error) {
-	config := &Config{}
+	config, err := LoadLocal(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the token from its credential source, if one is configured
+	if err := config.resolveAPIToken(); err != nil {
+		return nil, fmt.Errorf("failed to load API token: %w", err)
+	}
 
-	// Load from environment variables first
+	// Validate the final configuration
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return config, nil
+}
+
+// LoadLocal loads the configuration from the config file, environment variables, and CLI
+// flags like Load, but neither reads nor requires the API token. Commands that only work with
+// local state, such as backing up the data directory, use it so they run without credentials.
+func LoadLocal(cmd *cobra.Command) (*Config, error) {
+	config := &Config{
+		LogLevel:              DefaultLogLevel,
+		Timeout:               DefaultTimeout,
+		MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+		RequestsPerSecond:     DefaultRequestsPerSecond,
+		SearchScanLimit:       DefaultSearchScanLimit,
+		MaxRequestBytes:       DefaultMaxRequestBytes,
+		MaxArgumentBytes:      DefaultMaxArgumentBytes,
+		MaxArrayLength:        DefaultMaxArrayLength,
+		MaxResultBytes:        DefaultMaxResultBytes,
+		Transport:             TransportStdio,
+		ListenAddress:         DefaultListenAddress,
+		AccessLogSampleRate:   DefaultAccessLogSampleRate,
+		SLOAvailability:       DefaultSLOAvailability,
+		SLOLatency:            DefaultSLOLatency,
+		SLOLatencyTarget:      DefaultSLOLatencyTarget,
+		ResourcePollInterval:  DefaultResourcePollInterval,
+		ToolTimeout:           DefaultToolTimeout,
+	}
+
+	// Load from the config file first
+	if err := config.loadFromFile(cmd.Flags()); err != nil {
+		return nil, fmt.Errorf("failed to load configuration from file: %w", err)
+	}
+
+	// Override with environment variables
 	if err := config.loadFromEnv(); err != nil {
 		return nil, fmt.Errorf("failed to load configuration from environment: %w", err)
 	}
@@ -49,26 +306,30 @@ func Load(cmd *cobra.Command) (*Config, error) {
 		return nil, fmt.Errorf("failed to load configuration from flags: %w", err)
 	}
 
-	// Validate the final configuration
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("configuration validation failed: %w", err)
-	}
-
 	return config, nil
 }
 
-// loadFromEnv loads configuration from environment variables
+// loadFromEnv loads configuration from environment variables, overriding the config file
 func (c *Config) loadFromEnv() error {
 	// API Token (required)
 	if token := os.Getenv("REPLICATED_API_TOKEN"); token != "" {
 		c.APIToken = token
 	}
 
+	// API token sources (optional)
+	if tokenFile := os.Getenv("REPLICATED_API_TOKEN_FILE"); tokenFile != "" {
+		c.APITokenFile = tokenFile
+	}
+	if tokenCommand := os.Getenv("REPLICATED_API_TOKEN_COMMAND"); tokenCommand != "" {
+		c.APITokenCommand = tokenCommand
+	}
+	if keychain := os.Getenv("REPLICATED_API_TOKEN_KEYCHAIN"); keychain != "" {
+		c.APITokenKeychain = keychain
+	}
+
 	// Log Level (optional, has default)
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
 		c.LogLevel = level
-	} else {
-		c.LogLevel = DefaultLogLevel
 	}
 
 	// Timeout (optional, has default)
@@ -78,8 +339,6 @@ func (c *Config) loadFromEnv() error {
 			return fmt.Errorf("invalid TIMEOUT environment variable '%s': must be a number of seconds", timeoutStr)
 		}
 		c.Timeout = time.Duration(timeout) * time.Second
-	} else {
-		c.Timeout = DefaultTimeout
 	}
 
 	// Endpoint (optional)
@@ -87,6 +346,356 @@ func (c *Config) loadFromEnv() error {
 		c.Endpoint = endpoint
 	}
 
+	// Default application (optional)
+	if app := os.Getenv("REPLICATED_APP"); app != "" {
+		c.App = app
+	}
+
+	// Fallback endpoint for read failover (optional)
+	if fallback := os.Getenv("FALLBACK_ENDPOINT"); fallback != "" {
+		c.FallbackEndpoint = fallback
+	}
+
+	// Concurrent request limit (optional, has default)
+	if limitStr := os.Getenv("MAX_CONCURRENT_REQUESTS"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return fmt.Errorf("invalid MAX_CONCURRENT_REQUESTS environment variable '%s': must be a number",
+				limitStr)
+		}
+		c.MaxConcurrentRequests = limit
+	}
+
+	// Request rate limit (optional, has default)
+	if rateStr := os.Getenv("REQUESTS_PER_SECOND"); rateStr != "" {
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			return fmt.Errorf("invalid REQUESTS_PER_SECOND environment variable '%s': must be a number", rateStr)
+		}
+		c.RequestsPerSecond = rate
+	}
+
+	// Search scan limit (optional, has default)
+	if limitStr := os.Getenv("SEARCH_SCAN_LIMIT"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return fmt.Errorf("invalid SEARCH_SCAN_LIMIT environment variable '%s': must be a number", limitStr)
+		}
+		c.SearchScanLimit = limit
+	}
+
+	// Search index (optional)
+	if indexStr := os.Getenv("SEARCH_INDEX"); indexStr != "" {
+		index, err := strconv.ParseBool(indexStr)
+		if err != nil {
+			return fmt.Errorf("invalid SEARCH_INDEX environment variable '%s': must be true or false", indexStr)
+		}
+		c.SearchIndex = index
+	}
+
+	// HAR capture of API traffic (optional)
+	if harFile := os.Getenv("HAR_FILE"); harFile != "" {
+		c.HARFile = harFile
+	}
+
+	// Audit log of mutating tool calls (optional)
+	if auditLog := os.Getenv("AUDIT_LOG"); auditLog != "" {
+		c.AuditLogFile = auditLog
+	}
+
+	// Redaction patterns (optional), separated by whitespace since patterns may contain commas
+	if patterns := os.Getenv("REDACT_PATTERNS"); patterns != "" {
+		c.RedactPatterns = strings.Fields(patterns)
+	}
+
+	// Hedged reads (optional)
+	if hedgeStr := os.Getenv("HEDGE_READS"); hedgeStr != "" {
+		hedge, err := strconv.ParseBool(hedgeStr)
+		if err != nil {
+			return fmt.Errorf("invalid HEDGE_READS environment variable '%s': must be true or false", hedgeStr)
+		}
+		c.HedgeReads = hedge
+	}
+	if classes := os.Getenv("HEDGE_CLASSES"); classes != "" {
+		c.HedgeClasses = splitList(classes)
+	}
+
+	// Developer mode (optional)
+	if devStr := os.Getenv("DEV_MODE"); devStr != "" {
+		dev, err := strconv.ParseBool(devStr)
+		if err != nil {
+			return fmt.Errorf("invalid DEV_MODE environment variable '%s': must be true or false", devStr)
+		}
+		c.DevMode = dev
+	}
+
+	// Locale for formatted reports (optional, empty uses the default locale)
+	if locale := os.Getenv("LOCALE"); locale != "" {
+		c.Locale = locale
+	}
+
+	// Data directory for exports and server-side state (optional)
+	if dataDir := os.Getenv("DATA_DIR"); dataDir != "" {
+		c.DataDir = dataDir
+	}
+
+	// Read-only mode (optional)
+	if readOnlyStr := os.Getenv("REPLICATED_READ_ONLY"); readOnlyStr != "" {
+		readOnly, err := strconv.ParseBool(readOnlyStr)
+		if err != nil {
+			return fmt.Errorf("invalid REPLICATED_READ_ONLY environment variable '%s': must be true or false",
+				readOnlyStr)
+		}
+		c.ReadOnly = readOnly
+	}
+
+	// Dry-run mode (optional)
+	if dryRunStr := os.Getenv("REPLICATED_DRY_RUN"); dryRunStr != "" {
+		dryRun, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			return fmt.Errorf("invalid REPLICATED_DRY_RUN environment variable '%s': must be true or false",
+				dryRunStr)
+		}
+		c.DryRun = dryRun
+	}
+
+	// Cluster commands (optional)
+	if commandsStr := os.Getenv("CLUSTER_COMMANDS"); commandsStr != "" {
+		commands, err := strconv.ParseBool(commandsStr)
+		if err != nil {
+			return fmt.Errorf("invalid CLUSTER_COMMANDS environment variable '%s': must be true or false",
+				commandsStr)
+		}
+		c.ClusterCommands = commands
+	}
+
+	// Tool allow and deny lists (optional, comma-separated)
+	if enabled := os.Getenv("ENABLED_TOOLS"); enabled != "" {
+		c.EnabledTools = splitList(enabled)
+	}
+	if disabled := os.Getenv("DISABLED_TOOLS"); disabled != "" {
+		c.DisabledTools = splitList(disabled)
+	}
+
+	if err := c.loadInputLimitsFromEnv(); err != nil {
+		return err
+	}
+	if err := c.loadSLOFromEnv(); err != nil {
+		return err
+	}
+	if err := c.loadDiskCacheFromEnv(); err != nil {
+		return err
+	}
+	if err := c.loadResourcePollFromEnv(); err != nil {
+		return err
+	}
+	if err := c.loadMockFromEnv(); err != nil {
+		return err
+	}
+	if err := c.loadToolTimeoutsFromEnv(); err != nil {
+		return err
+	}
+	if err := c.loadProxyFromEnv(); err != nil {
+		return err
+	}
+	return c.loadTransportFromEnv()
+}
+
+// loadInputLimitsFromEnv loads the request, tool argument, and tool result size limits from environment
+// variables
+func (c *Config) loadInputLimitsFromEnv() error {
+	limits := []struct {
+		env   string
+		value *int
+	}{
+		{env: "MAX_REQUEST_BYTES", value: &c.MaxRequestBytes},
+		{env: "MAX_ARGUMENT_BYTES", value: &c.MaxArgumentBytes},
+		{env: "MAX_ARRAY_LENGTH", value: &c.MaxArrayLength},
+		{env: "MAX_RESULT_BYTES", value: &c.MaxResultBytes},
+	}
+
+	for _, limit := range limits {
+		limitStr := os.Getenv(limit.env)
+		if limitStr == "" {
+			continue
+		}
+		value, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return fmt.Errorf("invalid %s environment variable '%s': must be a number", limit.env, limitStr)
+		}
+		*limit.value = value
+	}
+	return nil
+}
+
+// loadSLOFromEnv loads the Vendor Portal API objectives from environment variables
+func (c *Config) loadSLOFromEnv() error {
+	targets := []struct {
+		env   string
+		value *float64
+	}{
+		{env: "SLO_AVAILABILITY", value: &c.SLOAvailability},
+		{env: "SLO_LATENCY_TARGET", value: &c.SLOLatencyTarget},
+	}
+
+	for _, target := range targets {
+		targetStr := os.Getenv(target.env)
+		if targetStr == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(targetStr, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s environment variable '%s': must be a number between 0 and 1",
+				target.env, targetStr)
+		}
+		*target.value = value
+	}
+
+	if latencyStr := os.Getenv("SLO_LATENCY"); latencyStr != "" {
+		latency, err := time.ParseDuration(latencyStr)
+		if err != nil {
+			return fmt.Errorf("invalid SLO_LATENCY environment variable '%s': must be a duration such as 2s",
+				latencyStr)
+		}
+		c.SLOLatency = latency
+	}
+	return nil
+}
+
+// loadDiskCacheFromEnv loads the disk cache settings from environment variables
+func (c *Config) loadDiskCacheFromEnv() error {
+	if diskCacheStr := os.Getenv("DISK_CACHE"); diskCacheStr != "" {
+		diskCache, err := strconv.ParseBool(diskCacheStr)
+		if err != nil {
+			return fmt.Errorf("invalid DISK_CACHE environment variable '%s': must be true or false", diskCacheStr)
+		}
+		c.DiskCache = diskCache
+	}
+	if cacheDir := os.Getenv("CACHE_DIR"); cacheDir != "" {
+		c.CacheDir = cacheDir
+	}
+	return nil
+}
+
+// loadResourcePollFromEnv loads the resource subscription polling interval from the environment
+func (c *Config) loadResourcePollFromEnv() error {
+	if intervalStr := os.Getenv("RESOURCE_POLL_INTERVAL"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			return fmt.Errorf("invalid RESOURCE_POLL_INTERVAL environment variable '%s': "+
+				"must be a duration such as 30s", intervalStr)
+		}
+		c.ResourcePollInterval = interval
+	}
+	return nil
+}
+
+// loadMockFromEnv loads the mock mode and fixture recording settings from environment variables
+func (c *Config) loadMockFromEnv() error {
+	if mockStr := os.Getenv("REPLICATED_MOCK"); mockStr != "" {
+		mock, err := strconv.ParseBool(mockStr)
+		if err != nil {
+			return fmt.Errorf("invalid REPLICATED_MOCK environment variable '%s': must be true or false", mockStr)
+		}
+		c.Mock = mock
+	}
+	if fixtures := os.Getenv("REPLICATED_MOCK_FIXTURES"); fixtures != "" {
+		c.MockFixtures = fixtures
+	}
+	if record := os.Getenv("REPLICATED_RECORD_FIXTURES"); record != "" {
+		c.RecordFixtures = record
+	}
+	return nil
+}
+
+// loadToolTimeoutsFromEnv loads the tool call time limits from environment variables
+func (c *Config) loadToolTimeoutsFromEnv() error {
+	if timeoutStr := os.Getenv("TOOL_TIMEOUT"); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return fmt.Errorf("invalid TOOL_TIMEOUT environment variable '%s': must be a duration such as 2m",
+				timeoutStr)
+		}
+		c.ToolTimeout = timeout
+	}
+	if timeoutsStr := os.Getenv("TOOL_TIMEOUTS"); timeoutsStr != "" {
+		timeouts, err := parseToolTimeouts(splitList(timeoutsStr))
+		if err != nil {
+			return fmt.Errorf("invalid TOOL_TIMEOUTS environment variable: %w", err)
+		}
+		c.setToolTimeouts(timeouts)
+	}
+	return nil
+}
+
+// loadProxyFromEnv loads the API proxy and certificate verification settings from environment
+// variables. HTTP_PROXY, HTTPS_PROXY, and NO_PROXY are left to the API client, which honors
+// them when no proxy is set.
+func (c *Config) loadProxyFromEnv() error {
+	if proxy := os.Getenv("PROXY_URL"); proxy != "" {
+		c.Proxy = proxy
+	}
+	if bundle := os.Getenv("CA_BUNDLE"); bundle != "" {
+		c.CABundle = bundle
+	}
+	if skipStr := os.Getenv("INSECURE_SKIP_TLS_VERIFY"); skipStr != "" {
+		skip, err := strconv.ParseBool(skipStr)
+		if err != nil {
+			return fmt.Errorf("invalid INSECURE_SKIP_TLS_VERIFY environment variable '%s': must be true or false",
+				skipStr)
+		}
+		c.InsecureSkipTLSVerify = skip
+	}
+	return nil
+}
+
+// loadTransportFromEnv loads the transport, access log, and tracing settings from environment variables
+func (c *Config) loadTransportFromEnv() error {
+	if transport := os.Getenv("TRANSPORT"); transport != "" {
+		c.Transport = transport
+	}
+	if address := os.Getenv("LISTEN_ADDRESS"); address != "" {
+		c.ListenAddress = address
+	}
+	if authToken := os.Getenv("HTTP_AUTH_TOKEN"); authToken != "" {
+		c.HTTPAuthToken = authToken
+	}
+	if accessLog := os.Getenv("ACCESS_LOG_FILE"); accessLog != "" {
+		c.AccessLogFile = accessLog
+	}
+	if otlpEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); otlpEndpoint != "" {
+		c.OTLPEndpoint = otlpEndpoint
+	}
+	if rateStr := os.Getenv("ACCESS_LOG_SAMPLE_RATE"); rateStr != "" {
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ACCESS_LOG_SAMPLE_RATE environment variable '%s': must be a number "+
+				"between 0 and 1", rateStr)
+		}
+		c.AccessLogSampleRate = rate
+	}
+	if metricsStr := os.Getenv("METRICS_ENDPOINT"); metricsStr != "" {
+		metrics, err := strconv.ParseBool(metricsStr)
+		if err != nil {
+			return fmt.Errorf("invalid METRICS_ENDPOINT environment variable '%s': must be true or false",
+				metricsStr)
+		}
+		c.MetricsEndpoint = metrics
+	}
+
+	// TLS (optional)
+	if certFile := os.Getenv("TLS_CERT_FILE"); certFile != "" {
+		c.TLSCertFile = certFile
+	}
+	if keyFile := os.Getenv("TLS_KEY_FILE"); keyFile != "" {
+		c.TLSKeyFile = keyFile
+	}
+	if domains := os.Getenv("TLS_AUTOCERT_DOMAINS"); domains != "" {
+		c.TLSAutocertDomains = splitList(domains)
+	}
+	if origins := os.Getenv("ALLOWED_ORIGINS"); origins != "" {
+		c.AllowedOrigins = splitList(origins)
+	}
 	return nil
 }
 
@@ -101,6 +710,11 @@ func (c *Config) loadFromFlags(flags *pflag.FlagSet) error {
 		c.APIToken = token
 	}
 
+	// API token sources
+	if err := c.loadTokenSourceFlags(flags); err != nil {
+		return err
+	}
+
 	// Log Level
 	if flags.Changed("log-level") {
 		level, err := flags.GetString("log-level")
@@ -128,58 +742,865 @@ func (c *Config) loadFromFlags(flags *pflag.FlagSet) error {
 		c.Endpoint = endpoint
 	}
 
-	return nil
-}
+	// Default application
+	if flags.Changed("app") {
+		app, err := flags.GetString("app")
+		if err != nil {
+			return fmt.Errorf("failed to get app flag: %w", err)
+		}
+		c.App = app
+	}
 
-// Validate ensures the configuration is valid
-func (c *Config) Validate() error {
-	var errors []string
+	// Fallback endpoint
+	if flags.Changed("fallback-endpoint") {
+		fallback, err := flags.GetString("fallback-endpoint")
+		if err != nil {
+			return fmt.Errorf("failed to get fallback-endpoint flag: %w", err)
+		}
+		c.FallbackEndpoint = fallback
+	}
 
-	// Validate API Token
-	if c.APIToken == "" {
-		errors = append(errors, "API token is required. Set REPLICATED_API_TOKEN environment variable "+
-			"or use --api-token flag")
+	// Concurrent request limit
+	if flags.Changed("max-concurrent-requests") {
+		limit, err := flags.GetInt("max-concurrent-requests")
+		if err != nil {
+			return fmt.Errorf("failed to get max-concurrent-requests flag: %w", err)
+		}
+		c.MaxConcurrentRequests = limit
 	}
 
-	// Validate Log Level
-	if !isValidLogLevel(c.LogLevel) {
-		errors = append(errors, fmt.Sprintf("invalid log level '%s'. Valid levels are: %s",
-			c.LogLevel, strings.Join(ValidLogLevels, ", ")))
+	// Request rate limit
+	if flags.Changed("requests-per-second") {
+		rate, err := flags.GetFloat64("requests-per-second")
+		if err != nil {
+			return fmt.Errorf("failed to get requests-per-second flag: %w", err)
+		}
+		c.RequestsPerSecond = rate
 	}
 
-	// Validate Timeout
-	if c.Timeout < MinTimeout || c.Timeout > MaxTimeout {
-		errors = append(errors, fmt.Sprintf("timeout must be between %v and %v seconds, got %v",
-			MinTimeout.Seconds(), MaxTimeout.Seconds(), c.Timeout.Seconds()))
+	// Search scan limit
+	if flags.Changed("search-scan-limit") {
+		limit, err := flags.GetInt("search-scan-limit")
+		if err != nil {
+			return fmt.Errorf("failed to get search-scan-limit flag: %w", err)
+		}
+		c.SearchScanLimit = limit
 	}
 
-	// Validate Endpoint (if provided)
-	if c.Endpoint != "" {
-		if u, err := url.Parse(c.Endpoint); err != nil {
-			errors = append(errors, fmt.Sprintf("invalid endpoint URL '%s': %v", c.Endpoint, err))
-		} else if u.Scheme == "" || u.Host == "" {
-			errors = append(errors, fmt.Sprintf("invalid endpoint URL '%s': must include scheme and host "+
-				"(e.g., https://api.example.com)", c.Endpoint))
+	// Search index
+	if flags.Changed("search-index") {
+		index, err := flags.GetBool("search-index")
+		if err != nil {
+			return fmt.Errorf("failed to get search-index flag: %w", err)
 		}
+		c.SearchIndex = index
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("configuration validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	// HAR capture
+	if flags.Changed("har-file") {
+		harFile, err := flags.GetString("har-file")
+		if err != nil {
+			return fmt.Errorf("failed to get har-file flag: %w", err)
+		}
+		c.HARFile = harFile
 	}
 
-	return nil
-}
+	// Audit log
+	if flags.Changed("audit-log") {
+		auditLog, err := flags.GetString("audit-log")
+		if err != nil {
+			return fmt.Errorf("failed to get audit-log flag: %w", err)
+		}
+		c.AuditLogFile = auditLog
+	}
 
-// isValidLogLevel checks if the provided log level is valid
-func isValidLogLevel(level string) bool {
-	level = strings.ToLower(level)
-	for _, valid := range ValidLogLevels {
-		if level == valid {
-			return true
+	// Redaction patterns, repeated rather than comma-separated since patterns may contain commas
+	if flags.Changed("redact-pattern") {
+		patterns, err := flags.GetStringArray("redact-pattern")
+		if err != nil {
+			return fmt.Errorf("failed to get redact-pattern flag: %w", err)
 		}
+		c.RedactPatterns = normalizeList(patterns)
 	}
-	return false
-}
+
+	// Hedged reads
+	if flags.Changed("hedge-reads") {
+		hedge, err := flags.GetBool("hedge-reads")
+		if err != nil {
+			return fmt.Errorf("failed to get hedge-reads flag: %w", err)
+		}
+		c.HedgeReads = hedge
+	}
+
+	// Hedged read classes
+	if flags.Changed("hedge-classes") {
+		classes, err := flags.GetStringSlice("hedge-classes")
+		if err != nil {
+			return fmt.Errorf("failed to get hedge-classes flag: %w", err)
+		}
+		c.HedgeClasses = normalizeList(classes)
+	}
+
+	// Developer mode
+	if flags.Changed("dev") {
+		dev, err := flags.GetBool("dev")
+		if err != nil {
+			return fmt.Errorf("failed to get dev flag: %w", err)
+		}
+		c.DevMode = dev
+	}
+
+	// Locale
+	if flags.Changed("locale") {
+		locale, err := flags.GetString("locale")
+		if err != nil {
+			return fmt.Errorf("failed to get locale flag: %w", err)
+		}
+		c.Locale = locale
+	}
+
+	// Data directory
+	if flags.Changed("data-dir") {
+		dataDir, err := flags.GetString("data-dir")
+		if err != nil {
+			return fmt.Errorf("failed to get data-dir flag: %w", err)
+		}
+		c.DataDir = dataDir
+	}
+
+	// Read-only mode
+	if flags.Changed("read-only") {
+		readOnly, err := flags.GetBool("read-only")
+		if err != nil {
+			return fmt.Errorf("failed to get read-only flag: %w", err)
+		}
+		c.ReadOnly = readOnly
+	}
+
+	// Dry-run mode
+	if flags.Changed("dry-run") {
+		dryRun, err := flags.GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("failed to get dry-run flag: %w", err)
+		}
+		c.DryRun = dryRun
+	}
+
+	// Cluster commands
+	if flags.Changed("cluster-commands") {
+		commands, err := flags.GetBool("cluster-commands")
+		if err != nil {
+			return fmt.Errorf("failed to get cluster-commands flag: %w", err)
+		}
+		c.ClusterCommands = commands
+	}
+
+	// Tool allow list
+	if flags.Changed("enabled-tools") {
+		enabled, err := flags.GetStringSlice("enabled-tools")
+		if err != nil {
+			return fmt.Errorf("failed to get enabled-tools flag: %w", err)
+		}
+		c.EnabledTools = normalizeList(enabled)
+	}
+
+	// Tool deny list
+	if flags.Changed("disable-tool") {
+		disabled, err := flags.GetStringSlice("disable-tool")
+		if err != nil {
+			return fmt.Errorf("failed to get disable-tool flag: %w", err)
+		}
+		c.DisabledTools = normalizeList(disabled)
+	}
+
+	if err := c.loadInputLimitFlags(flags); err != nil {
+		return err
+	}
+	if err := c.loadSLOFlags(flags); err != nil {
+		return err
+	}
+	if err := c.loadDiskCacheFlags(flags); err != nil {
+		return err
+	}
+	if flags.Changed("resource-poll-interval") {
+		interval, err := flags.GetDuration("resource-poll-interval")
+		if err != nil {
+			return fmt.Errorf("failed to get resource-poll-interval flag: %w", err)
+		}
+		c.ResourcePollInterval = interval
+	}
+	if err := c.loadMockFlags(flags); err != nil {
+		return err
+	}
+	if err := c.loadToolTimeoutFlags(flags); err != nil {
+		return err
+	}
+	if err := c.loadProxyFlags(flags); err != nil {
+		return err
+	}
+	return c.loadTransportFlags(flags)
+}
+
+// loadInputLimitFlags loads the request, tool argument, and tool result size limit flags
+func (c *Config) loadInputLimitFlags(flags *pflag.FlagSet) error {
+	limits := []struct {
+		flag  string
+		value *int
+	}{
+		{flag: "max-request-bytes", value: &c.MaxRequestBytes},
+		{flag: "max-argument-bytes", value: &c.MaxArgumentBytes},
+		{flag: "max-array-length", value: &c.MaxArrayLength},
+		{flag: "max-result-bytes", value: &c.MaxResultBytes},
+	}
+
+	for _, limit := range limits {
+		if !flags.Changed(limit.flag) {
+			continue
+		}
+		value, err := flags.GetInt(limit.flag)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", limit.flag, err)
+		}
+		*limit.value = value
+	}
+	return nil
+}
+
+// loadSLOFlags loads the Vendor Portal API objective flags
+func (c *Config) loadSLOFlags(flags *pflag.FlagSet) error {
+	targets := []struct {
+		flag  string
+		value *float64
+	}{
+		{flag: "slo-availability", value: &c.SLOAvailability},
+		{flag: "slo-latency-target", value: &c.SLOLatencyTarget},
+	}
+
+	for _, target := range targets {
+		if !flags.Changed(target.flag) {
+			continue
+		}
+		value, err := flags.GetFloat64(target.flag)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", target.flag, err)
+		}
+		*target.value = value
+	}
+
+	if flags.Changed("slo-latency") {
+		latency, err := flags.GetDuration("slo-latency")
+		if err != nil {
+			return fmt.Errorf("failed to get slo-latency flag: %w", err)
+		}
+		c.SLOLatency = latency
+	}
+	return nil
+}
+
+// loadDiskCacheFlags loads the disk cache flags
+func (c *Config) loadDiskCacheFlags(flags *pflag.FlagSet) error {
+	if flags.Changed("disk-cache") {
+		diskCache, err := flags.GetBool("disk-cache")
+		if err != nil {
+			return fmt.Errorf("failed to get disk-cache flag: %w", err)
+		}
+		c.DiskCache = diskCache
+	}
+	if flags.Changed("cache-dir") {
+		cacheDir, err := flags.GetString("cache-dir")
+		if err != nil {
+			return fmt.Errorf("failed to get cache-dir flag: %w", err)
+		}
+		c.CacheDir = cacheDir
+	}
+	return nil
+}
+
+// loadMockFlags loads the mock mode and fixture recording flags
+func (c *Config) loadMockFlags(flags *pflag.FlagSet) error {
+	if flags.Changed("mock") {
+		mock, err := flags.GetBool("mock")
+		if err != nil {
+			return fmt.Errorf("failed to get mock flag: %w", err)
+		}
+		c.Mock = mock
+	}
+	if flags.Changed("mock-fixtures") {
+		fixtures, err := flags.GetString("mock-fixtures")
+		if err != nil {
+			return fmt.Errorf("failed to get mock-fixtures flag: %w", err)
+		}
+		c.MockFixtures = fixtures
+	}
+	if flags.Changed("record-fixtures") {
+		record, err := flags.GetString("record-fixtures")
+		if err != nil {
+			return fmt.Errorf("failed to get record-fixtures flag: %w", err)
+		}
+		c.RecordFixtures = record
+	}
+	return nil
+}
+
+// loadToolTimeoutFlags loads the tool call time limit flags
+func (c *Config) loadToolTimeoutFlags(flags *pflag.FlagSet) error {
+	if flags.Changed("tool-timeout") {
+		timeout, err := flags.GetDuration("tool-timeout")
+		if err != nil {
+			return fmt.Errorf("failed to get tool-timeout flag: %w", err)
+		}
+		c.ToolTimeout = timeout
+	}
+	if flags.Changed("tool-timeouts") {
+		entries, err := flags.GetStringSlice("tool-timeouts")
+		if err != nil {
+			return fmt.Errorf("failed to get tool-timeouts flag: %w", err)
+		}
+		timeouts, err := parseToolTimeouts(normalizeList(entries))
+		if err != nil {
+			return fmt.Errorf("invalid tool-timeouts flag: %w", err)
+		}
+		c.setToolTimeouts(timeouts)
+	}
+	return nil
+}
+
+// parseToolTimeouts parses tool time limits written as name=duration, such as
+// create_cluster=10m
+func parseToolTimeouts(entries []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		name, durationStr, _ := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		timeout, err := time.ParseDuration(strings.TrimSpace(durationStr))
+		if name == "" || err != nil {
+			return nil, fmt.Errorf("'%s' must be a tool name and a duration, such as create_cluster=10m", entry)
+		}
+		timeouts[name] = timeout
+	}
+	return timeouts, nil
+}
+
+// setToolTimeouts sets time limits for the tools in timeouts, leaving those of other tools
+func (c *Config) setToolTimeouts(timeouts map[string]time.Duration) {
+	if len(timeouts) > 0 && c.ToolTimeouts == nil {
+		c.ToolTimeouts = make(map[string]time.Duration, len(timeouts))
+	}
+	maps.Copy(c.ToolTimeouts, timeouts)
+}
+
+// loadProxyFlags loads the API proxy and certificate verification flags
+func (c *Config) loadProxyFlags(flags *pflag.FlagSet) error {
+	options := []struct {
+		flag  string
+		value *string
+	}{
+		{flag: "proxy", value: &c.Proxy},
+		{flag: "ca-bundle", value: &c.CABundle},
+	}
+
+	for _, option := range options {
+		if !flags.Changed(option.flag) {
+			continue
+		}
+		value, err := flags.GetString(option.flag)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", option.flag, err)
+		}
+		*option.value = value
+	}
+
+	if flags.Changed("insecure-skip-tls-verify") {
+		skip, err := flags.GetBool("insecure-skip-tls-verify")
+		if err != nil {
+			return fmt.Errorf("failed to get insecure-skip-tls-verify flag: %w", err)
+		}
+		c.InsecureSkipTLSVerify = skip
+	}
+	return nil
+}
+
+// loadTransportFlags loads the transport, access log, and tracing flags
+func (c *Config) loadTransportFlags(flags *pflag.FlagSet) error {
+	options := []struct {
+		flag  string
+		value *string
+	}{
+		{flag: "transport", value: &c.Transport},
+		{flag: "listen", value: &c.ListenAddress},
+		{flag: "http-auth-token", value: &c.HTTPAuthToken},
+		{flag: "access-log-file", value: &c.AccessLogFile},
+		{flag: "otlp-endpoint", value: &c.OTLPEndpoint},
+		{flag: "tls-cert", value: &c.TLSCertFile},
+		{flag: "tls-key", value: &c.TLSKeyFile},
+	}
+
+	for _, option := range options {
+		if !flags.Changed(option.flag) {
+			continue
+		}
+		value, err := flags.GetString(option.flag)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", option.flag, err)
+		}
+		*option.value = value
+	}
+
+	if flags.Changed("access-log-sample-rate") {
+		rate, err := flags.GetFloat64("access-log-sample-rate")
+		if err != nil {
+			return fmt.Errorf("failed to get access-log-sample-rate flag: %w", err)
+		}
+		c.AccessLogSampleRate = rate
+	}
+
+	if flags.Changed("metrics-endpoint") {
+		metrics, err := flags.GetBool("metrics-endpoint")
+		if err != nil {
+			return fmt.Errorf("failed to get metrics-endpoint flag: %w", err)
+		}
+		c.MetricsEndpoint = metrics
+	}
+
+	if flags.Changed("tls-autocert-domains") {
+		domains, err := flags.GetStringSlice("tls-autocert-domains")
+		if err != nil {
+			return fmt.Errorf("failed to get tls-autocert-domains flag: %w", err)
+		}
+		c.TLSAutocertDomains = normalizeList(domains)
+	}
+
+	if flags.Changed("allowed-origins") {
+		origins, err := flags.GetStringSlice("allowed-origins")
+		if err != nil {
+			return fmt.Errorf("failed to get allowed-origins flag: %w", err)
+		}
+		c.AllowedOrigins = normalizeList(origins)
+	}
+	return nil
+}
+
+// loadTokenSourceFlags loads the API token source flags
+func (c *Config) loadTokenSourceFlags(flags *pflag.FlagSet) error {
+	sources := []struct {
+		flag  string
+		value *string
+	}{
+		{flag: "api-token-file", value: &c.APITokenFile},
+		{flag: "api-token-command", value: &c.APITokenCommand},
+		{flag: "api-token-keychain", value: &c.APITokenKeychain},
+	}
+
+	for _, source := range sources {
+		if !flags.Changed(source.flag) {
+			continue
+		}
+		value, err := flags.GetString(source.flag)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", source.flag, err)
+		}
+		*source.value = value
+	}
+	return nil
+}
+
+// splitList parses a comma-separated list from an environment variable
+func splitList(value string) []string {
+	return normalizeList(strings.Split(value, ","))
+}
+
+// normalizeList trims whitespace from list entries and drops empty ones
+func normalizeList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// Validate ensures the configuration is valid
+func (c *Config) Validate() error {
+	var errors []string
+
+	// Validate API Token, which mock mode does without
+	if c.APIToken == "" && !c.Mock {
+		errors = append(errors, "API token is required. Set REPLICATED_API_TOKEN environment variable, "+
+			"use --api-token flag, or read it with --api-token-file, --api-token-command, or --api-token-keychain")
+	}
+
+	// Validate Log Level
+	if !isValidLogLevel(c.LogLevel) {
+		errors = append(errors, fmt.Sprintf("invalid log level '%s'. Valid levels are: %s",
+			c.LogLevel, strings.Join(ValidLogLevels, ", ")))
+	}
+
+	// Validate Timeout
+	if c.Timeout < MinTimeout || c.Timeout > MaxTimeout {
+		errors = append(errors, fmt.Sprintf("timeout must be between %v and %v seconds, got %v",
+			MinTimeout.Seconds(), MaxTimeout.Seconds(), c.Timeout.Seconds()))
+	}
+
+	// Validate Endpoint (if provided)
+	if c.Endpoint != "" {
+		if err := validateEndpoint("endpoint", c.Endpoint); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	// Validate Fallback Endpoint (if provided)
+	if c.FallbackEndpoint != "" {
+		if err := validateEndpoint("fallback endpoint", c.FallbackEndpoint); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	// Validate Proxy (if provided)
+	if c.Proxy != "" {
+		if err := validateProxy(c.Proxy); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	// Validate API Gateway Settings
+	errors = append(errors, c.validateAPIGateway()...)
+
+	// Validate Concurrent Request Limit
+	if c.MaxConcurrentRequests < 0 {
+		errors = append(errors, fmt.Sprintf("max concurrent requests cannot be negative, got %d",
+			c.MaxConcurrentRequests))
+	}
+
+	// Validate Request Rate Limit
+	if c.RequestsPerSecond < 0 {
+		errors = append(errors, fmt.Sprintf("requests per second cannot be negative, got %g", c.RequestsPerSecond))
+	}
+
+	// Validate Search Scan Limit
+	if c.SearchScanLimit < 0 {
+		errors = append(errors, fmt.Sprintf("search scan limit cannot be negative, got %d", c.SearchScanLimit))
+	}
+
+	// Validate Fixture Recording, which has nothing to record in mock mode
+	if c.Mock && c.RecordFixtures != "" {
+		errors = append(errors, "fixtures cannot be recorded in mock mode")
+	}
+
+	// Validate Resource Poll Interval
+	if c.ResourcePollInterval < 0 {
+		errors = append(errors, fmt.Sprintf("resource poll interval cannot be negative, got %v",
+			c.ResourcePollInterval))
+	}
+
+	// Validate Tool Timeouts
+	if c.ToolTimeout < 0 {
+		errors = append(errors, fmt.Sprintf("tool timeout cannot be negative, got %v", c.ToolTimeout))
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.ToolTimeouts)) {
+		if timeout := c.ToolTimeouts[name]; timeout < 0 {
+			errors = append(errors, fmt.Sprintf("tool timeout for %s cannot be negative, got %v", name, timeout))
+		}
+	}
+
+	// Validate Input Limits
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{name: "max request bytes", value: c.MaxRequestBytes},
+		{name: "max argument bytes", value: c.MaxArgumentBytes},
+		{name: "max array length", value: c.MaxArrayLength},
+		{name: "max result bytes", value: c.MaxResultBytes},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			errors = append(errors, fmt.Sprintf("%s cannot be negative, got %d", limit.name, limit.value))
+		}
+	}
+
+	// Validate Hedge Classes (if provided)
+	if _, err := api.ParseHedgeClasses(c.HedgeClasses); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate Redaction Patterns (if provided)
+	if _, err := redact.New(c.RedactPatterns); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate Locale (if provided)
+	if c.Locale != "" {
+		if _, err := render.ParseLocale(c.Locale); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	// Validate Vendor Portal API Objectives
+	errors = append(errors, c.validateSLO()...)
+
+	// Validate Transport, Access Logs, TLS, and Allowed Origins
+	errors = append(errors, c.validateTransport()...)
+	errors = append(errors, c.validateTLS()...)
+	errors = append(errors, c.validateOrigins()...)
+
+	if len(errors) > 0 {
+		return fmt.Errorf("configuration validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// validateTransport checks the transport, access log, and tracing settings
+func (c *Config) validateTransport() []string {
+	var errors []string
+
+	if c.Transport != "" && !slices.Contains(ValidTransports, c.Transport) {
+		errors = append(errors, fmt.Sprintf("invalid transport '%s'. Valid transports are: %s",
+			c.Transport, strings.Join(ValidTransports, ", ")))
+	}
+
+	if c.Transport == TransportHTTP {
+		host, _, err := net.SplitHostPort(c.ListenAddress)
+		switch {
+		case err != nil:
+			errors = append(errors, fmt.Sprintf("invalid listen address '%s': must be host:port "+
+				"(e.g., 127.0.0.1:8080)", c.ListenAddress))
+		case c.HTTPAuthToken == "" && !isLoopback(host):
+			errors = append(errors, fmt.Sprintf("listen address '%s' is not loopback: set an HTTP auth token "+
+				"with --http-auth-token or HTTP_AUTH_TOKEN so clients must authenticate", c.ListenAddress))
+		}
+	}
+
+	if c.AccessLogSampleRate < 0 || c.AccessLogSampleRate > 1 {
+		errors = append(errors, fmt.Sprintf("access log sample rate must be between 0 and 1, got %v",
+			c.AccessLogSampleRate))
+	}
+
+	if c.OTLPEndpoint != "" {
+		if err := validateEndpoint("OTLP endpoint", c.OTLPEndpoint); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	return errors
+}
+
+// isLoopback reports whether a listen host only accepts connections from this machine. An
+// empty host listens on every interface.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validateSLO checks that the objectives' targets are fractions short of 1, leaving an error
+// budget to spend, and that the latency objective is not negative
+func (c *Config) validateSLO() []string {
+	var errors []string
+
+	targets := []struct {
+		name  string
+		value float64
+	}{
+		{name: "SLO availability", value: c.SLOAvailability},
+		{name: "SLO latency target", value: c.SLOLatencyTarget},
+	}
+	for _, target := range targets {
+		if target.value < 0 || target.value >= 1 {
+			errors = append(errors, fmt.Sprintf("%s must be between 0 and 1, excluding 1, got %v",
+				target.name, target.value))
+		}
+	}
+
+	if c.SLOLatency < 0 {
+		errors = append(errors, fmt.Sprintf("SLO latency cannot be negative, got %v", c.SLOLatency))
+	}
+
+	return errors
+}
+
+// SLOObjectives returns the objectives the Vendor Portal API is measured against, using the
+// defaults for any that are not set
+func (c *Config) SLOObjectives() slo.Objectives {
+	objectives := slo.DefaultObjectives()
+	if c.SLOAvailability > 0 {
+		objectives.Availability = c.SLOAvailability
+	}
+	if c.SLOLatency > 0 {
+		objectives.Latency = c.SLOLatency
+	}
+	if c.SLOLatencyTarget > 0 {
+		objectives.LatencyTarget = c.SLOLatencyTarget
+	}
+	return objectives
+}
+
+// validateTLS checks that TLS is configured with either a certificate and key or autocert
+// domains, and only for the HTTP transport with an auth token, since serving TLS means the
+// transport is meant to be reached from other machines
+func (c *Config) validateTLS() []string {
+	var errors []string
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errors = append(errors, "TLS certificate and key must be set together")
+	}
+	if c.TLSCertFile != "" && len(c.TLSAutocertDomains) > 0 {
+		errors = append(errors, "TLS certificate files and autocert domains cannot both be set")
+	}
+	if c.TLSEnabled() && c.Transport != TransportHTTP {
+		errors = append(errors, "TLS settings require the http transport")
+	}
+	if c.TLSEnabled() && c.HTTPAuthToken == "" {
+		errors = append(errors, "TLS settings require an HTTP auth token: set --http-auth-token or "+
+			"HTTP_AUTH_TOKEN so clients must authenticate")
+	}
+
+	return errors
+}
+
+// validateOrigins checks each allowed origin
+func (c *Config) validateOrigins() []string {
+	var errors []string
+	for _, origin := range c.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	return errors
+}
+
+// validateOrigin checks that an allowed origin is "*" or a scheme and host, such as
+// https://agent.example.com or http://localhost:3000
+func validateOrigin(origin string) error {
+	if origin == cors.AnyOrigin {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		strings.TrimSuffix(u.Path, "/") != "" || u.RawQuery != "" || u.User != nil {
+		return fmt.Errorf("invalid allowed origin '%s': must be a scheme and host such as "+
+			"https://agent.example.com, or *", origin)
+	}
+	return nil
+}
+
+// TLSEnabled reports whether the HTTP transport serves TLS
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" || c.TLSKeyFile != "" || len(c.TLSAutocertDomains) > 0
+}
+
+// validateEndpoint checks that an endpoint is an absolute URL with a scheme and host
+func validateEndpoint(name, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid %s URL '%s': %v", name, endpoint, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid %s URL '%s': must include scheme and host "+
+			"(e.g., https://api.example.com)", name, endpoint)
+	}
+	return nil
+}
+
+// validateProxy checks that a proxy is a URL the API client can send requests through
+func validateProxy(proxy string) error {
+	if err := validateEndpoint("proxy", proxy); err != nil {
+		return err
+	}
+	if u, _ := url.Parse(proxy); !slices.Contains(ValidProxySchemes, u.Scheme) {
+		return fmt.Errorf("invalid proxy URL '%s': scheme must be one of %s", proxy,
+			strings.Join(ValidProxySchemes, ", "))
+	}
+	return nil
+}
+
+// validateAPIGateway checks that the client certificate and key are set together and that each
+// extra API header is a valid header the client does not set itself
+func (c *Config) validateAPIGateway() []string {
+	var errors []string
+
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		errors = append(errors, "client certificate and key must be set together")
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.APIHeaders)) {
+		switch {
+		case !headerNamePattern.MatchString(name):
+			errors = append(errors, fmt.Sprintf("invalid API header name '%s'", name))
+		case slices.ContainsFunc(reservedAPIHeaders, func(reserved string) bool {
+			return strings.EqualFold(name, reserved)
+		}):
+			errors = append(errors, fmt.Sprintf("API header '%s' is set by the server and cannot be configured",
+				name))
+		case strings.ContainsAny(c.APIHeaders[name], "\r\n"):
+			errors = append(errors, fmt.Sprintf("value of API header '%s' cannot contain line breaks", name))
+		}
+	}
+
+	return errors
+}
+
+// isValidLogLevel checks if the provided log level is valid
+func isValidLogLevel(level string) bool {
+	level = strings.ToLower(level)
+	for _, valid := range ValidLogLevels {
+		if level == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolTimeoutFor returns how long a call to the named tool may run, where zero means no limit
+func (c *Config) ToolTimeoutFor(name string) time.Duration {
+	if timeout, ok := c.ToolTimeouts[name]; ok {
+		return timeout
+	}
+	return c.ToolTimeout
+}
+
+// DataDirectory returns the directory for exports and server-side state.
+// When no directory is configured it defaults to ~/.replicated-mcp-server.
+func (c *Config) DataDirectory() string {
+	if c.DataDir != "" {
+		return c.DataDir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), DefaultDataDir)
+	}
+	return filepath.Join(home, DefaultDataDir)
+}
+
+// CacheDirectory returns the directory for the disk cache. When no directory is configured
+// it defaults to replicated-mcp-server in the user's cache directory ($XDG_CACHE_HOME, or
+// ~/.cache when it is unset).
+func (c *Config) CacheDirectory() string {
+	if c.CacheDir != "" {
+		return c.CacheDir
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), DefaultCacheDir)
+	}
+	return filepath.Join(cacheDir, DefaultCacheDir)
+}
+
+// PromotionPolicy returns the promotion policy for a channel, matching policy keys against
+// the channel's ID, slug, or name (ignoring case)
+func (c *Config) PromotionPolicy(channel *models.Channel) (models.PromotionPolicy, bool) {
+	for key, policy := range c.ChannelPolicies {
+		if key == channel.ID || key == channel.ChannelSlug || strings.EqualFold(key, channel.Name) {
+			return policy, true
+		}
+	}
+	return models.PromotionPolicy{}, false
+}
 
 // String returns a string representation of the configuration (without sensitive data)
 func (c *Config) String() string {
@@ -193,6 +1614,29 @@ func (c *Config) String() string {
 		token = "(set)"
 	}
 
-	return fmt.Sprintf("Config{APIToken: %s, LogLevel: %s, Timeout: %v, Endpoint: %s}",
-		token, c.LogLevel, c.Timeout, endpoint)
+	locale := c.Locale
+	if locale == "" {
+		locale = "(default)"
+	}
+
+	profile := c.Profile
+	if profile == "" {
+		profile = "(none)"
+	}
+
+	transport := c.Transport
+	if transport == TransportHTTP {
+		transport += " " + c.ListenAddress
+		if c.TLSEnabled() {
+			transport += " (TLS)"
+		}
+		if c.HTTPAuthToken != "" {
+			transport += " (auth)"
+		}
+	}
+
+	return fmt.Sprintf("Config{Profile: %s, APIToken: %s, LogLevel: %s, Timeout: %v, Endpoint: %s, DevMode: %t, "+
+		"Locale: %s, ReadOnly: %t, DryRun: %t, EnabledTools: %v, DisabledTools: %v, Transport: %s}", profile, token,
+		c.LogLevel, c.Timeout, endpoint, c.DevMode, locale, c.ReadOnly, c.DryRun, c.EnabledTools, c.DisabledTools,
+		transport)
 }
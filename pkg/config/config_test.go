@@ -1,7 +1,10 @@
 package config
 
 import (
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -11,6 +14,9 @@ import (
 )
 
 func TestLoad(t *testing.T) {
+	// Keep any config file in the user's home directory out of the tests
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
 	tests := []struct {
 		name        string
 		envVars     map[string]string
@@ -28,10 +34,12 @@ func TestLoad(t *testing.T) {
 				"ENDPOINT":             "https://api.example.com",
 			},
 			want: &Config{
-				APIToken: "test-token",
-				LogLevel: "info",
-				Timeout:  60 * time.Second,
-				Endpoint: "https://api.example.com",
+				APIToken:              "test-token",
+				LogLevel:              "info",
+				Timeout:               60 * time.Second,
+				Endpoint:              "https://api.example.com",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
 			},
 			wantErr: false,
 		},
@@ -41,10 +49,12 @@ func TestLoad(t *testing.T) {
 				"REPLICATED_API_TOKEN": "test-token",
 			},
 			want: &Config{
-				APIToken: "test-token",
-				LogLevel: DefaultLogLevel,
-				Timeout:  DefaultTimeout,
-				Endpoint: "",
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				Endpoint:              "",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
 			},
 			wantErr: false,
 		},
@@ -61,10 +71,12 @@ func TestLoad(t *testing.T) {
 				"timeout":   120,
 			},
 			want: &Config{
-				APIToken: "flag-token",
-				LogLevel: "debug",
-				Timeout:  120 * time.Second,
-				Endpoint: "",
+				APIToken:              "flag-token",
+				LogLevel:              "debug",
+				Timeout:               120 * time.Second,
+				Endpoint:              "",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
 			},
 			wantErr: false,
 		},
@@ -110,229 +122,2001 @@ func TestLoad(t *testing.T) {
 			wantErr:     true,
 			errContains: "invalid endpoint URL",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Clear environment
-			clearTestEnv()
-
-			// Set test environment variables
-			for key, value := range tt.envVars {
-				os.Setenv(key, value)
-			}
-			defer clearTestEnv()
-
-			// Create test command with flags
-			cmd := createTestCommand()
-
-			// Build command line args from flags
-			var args []string
-			if tt.flags != nil {
-				for flag, value := range tt.flags {
-					switch v := value.(type) {
-					case string:
-						args = append(args, "--"+flag, v)
-					case int:
-						args = append(args, "--"+flag, strconv.Itoa(v))
-					}
-				}
-			}
-
-			// Parse the flags to simulate actual command execution
-			if len(args) > 0 {
-				cmd.SetArgs(args)
-				_ = cmd.ParseFlags(args)
-			}
-
-			got, err := Load(cmd)
-
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Load() expected error but got none")
-					return
-				}
-				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
-					t.Errorf("Load() error = %v, expected to contain %v", err, tt.errContains)
-				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("Load() unexpected error = %v", err)
-				return
-			}
-
-			if got.APIToken != tt.want.APIToken {
-				t.Errorf("Load() APIToken = %v, want %v", got.APIToken, tt.want.APIToken)
-			}
-			if got.LogLevel != tt.want.LogLevel {
-				t.Errorf("Load() LogLevel = %v, want %v", got.LogLevel, tt.want.LogLevel)
-			}
-			if got.Timeout != tt.want.Timeout {
-				t.Errorf("Load() Timeout = %v, want %v", got.Timeout, tt.want.Timeout)
-			}
-			if got.Endpoint != tt.want.Endpoint {
-				t.Errorf("Load() Endpoint = %v, want %v", got.Endpoint, tt.want.Endpoint)
-			}
-		})
-	}
-}
-
-func TestConfig_Validate(t *testing.T) {
-	tests := []struct {
-		name        string
-		config      *Config
-		wantErr     bool
-		errContains string
-	}{
 		{
-			name: "valid configuration",
-			config: &Config{
-				APIToken: "test-token",
-				LogLevel: "info",
-				Timeout:  30 * time.Second,
-				Endpoint: "https://api.example.com",
+			name: "fallback endpoint from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"FALLBACK_ENDPOINT":    "https://proxy.example.com",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				FallbackEndpoint:      "https://proxy.example.com",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
 			},
 			wantErr: false,
 		},
 		{
-			name: "valid minimal configuration",
-			config: &Config{
-				APIToken: "test-token",
-				LogLevel: "fatal",
-				Timeout:  1 * time.Second,
-				Endpoint: "",
+			name: "fallback endpoint flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"FALLBACK_ENDPOINT":    "https://env-proxy.example.com",
+			},
+			flags: map[string]interface{}{
+				"fallback-endpoint": "https://flag-proxy.example.com",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				FallbackEndpoint:      "https://flag-proxy.example.com",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
 			},
 			wantErr: false,
 		},
 		{
-			name: "missing API token",
-			config: &Config{
-				APIToken: "",
-				LogLevel: "info",
-				Timeout:  30 * time.Second,
+			name: "HAR file flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"HAR_FILE":             "/tmp/env.har",
 			},
-			wantErr:     true,
-			errContains: "API token is required",
+			flags: map[string]interface{}{
+				"har-file": "/tmp/flag.har",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+				HARFile:               "/tmp/flag.har",
+			},
+			wantErr: false,
 		},
 		{
-			name: "invalid log level",
-			config: &Config{
-				APIToken: "test-token",
-				LogLevel: "INVALID",
-				Timeout:  30 * time.Second,
+			name: "audit log flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"AUDIT_LOG":            "/tmp/env-audit.jsonl",
 			},
-			wantErr:     true,
-			errContains: "invalid log level",
+			flags: map[string]interface{}{
+				"audit-log": "/tmp/flag-audit.jsonl",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+				AuditLogFile:          "/tmp/flag-audit.jsonl",
+			},
+			wantErr: false,
 		},
 		{
-			name: "timeout too short",
-			config: &Config{
-				APIToken: "test-token",
-				LogLevel: "info",
-				Timeout:  500 * time.Millisecond,
+			name: "redaction patterns from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REDACT_PATTERNS":      `lic_[a-z0-9]{2,} \bcust-\d+\b`,
 			},
-			wantErr:     true,
-			errContains: "timeout must be between",
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+				RedactPatterns:        []string{`lic_[a-z0-9]{2,}`, `\bcust-\d+\b`},
+			},
+			wantErr: false,
 		},
 		{
-			name: "timeout too long",
-			config: &Config{
-				APIToken: "test-token",
-				LogLevel: "info",
-				Timeout:  400 * time.Second,
+			name: "redaction pattern flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REDACT_PATTERNS":      "env_[0-9]+",
 			},
-			wantErr:     true,
-			errContains: "timeout must be between",
+			flags: map[string]interface{}{
+				"redact-pattern": `lic_[a-z0-9]{2,}`,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+				RedactPatterns:        []string{`lic_[a-z0-9]{2,}`},
+			},
+			wantErr: false,
 		},
 		{
-			name: "invalid endpoint",
-			config: &Config{
-				APIToken: "test-token",
-				LogLevel: "info",
-				Timeout:  30 * time.Second,
-				Endpoint: "not-a-valid-url",
+			name: "invalid redaction pattern",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REDACT_PATTERNS":      "lic_[a-z",
 			},
 			wantErr:     true,
-			errContains: "invalid endpoint URL",
+			errContains: "invalid redaction pattern",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := tt.config.Validate()
-
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Validate() expected error but got none")
-					return
-				}
-				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
-					t.Errorf("Validate() error = %v, expected to contain %v", err, tt.errContains)
-				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("Validate() unexpected error = %v", err)
-			}
-		})
-	}
-}
-
-func TestIsValidLogLevel(t *testing.T) {
-	tests := []struct {
-		name  string
-		level string
-		want  bool
-	}{
-		{"valid fatal", "fatal", true},
-		{"valid error", "error", true},
-		{"valid info", "info", true},
-		{"valid debug", "debug", true},
-		{"valid trace", "trace", true},
-		{"valid uppercase", "INFO", true},
-		{"valid mixed case", "Debug", true},
-		{"invalid level", "invalid", false},
-		{"empty string", "", false},
-		{"number", "123", false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := isValidLogLevel(tt.level); got != tt.want {
-				t.Errorf("isValidLogLevel(%q) = %v, want %v", tt.level, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestConfig_String(t *testing.T) {
-	tests := []struct {
-		name   string
-		config *Config
-		want   []string // substrings that should be present
-	}{
 		{
-			name: "config with all fields",
-			config: &Config{
-				APIToken: "secret-token",
-				LogLevel: "info",
-				Timeout:  30 * time.Second,
-				Endpoint: "https://api.example.com",
+			name: "proxy settings from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN":     "test-token",
+				"PROXY_URL":                "http://proxy.example.com:3128",
+				"CA_BUNDLE":                "/etc/ssl/proxy-ca.pem",
+				"INSECURE_SKIP_TLS_VERIFY": "true",
 			},
-			want: []string{"(set)", "info", "30s", "https://api.example.com"},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+				Proxy:                 "http://proxy.example.com:3128",
+				CABundle:              "/etc/ssl/proxy-ca.pem",
+				InsecureSkipTLSVerify: true,
+			},
+			wantErr: false,
 		},
 		{
-			name: "config without token",
-			config: &Config{
-				APIToken: "",
-				LogLevel: "fatal",
-				Timeout:  15 * time.Second,
-				Endpoint: "",
+			name: "proxy flags",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"PROXY_URL":            "http://env-proxy.example.com:3128",
 			},
-			want: []string{"(not set)", "fatal", "15s", "(default)"},
+			flags: map[string]interface{}{
+				"proxy":                    "socks5://flag-proxy.example.com:1080",
+				"ca-bundle":                "/etc/ssl/proxy-ca.pem",
+				"insecure-skip-tls-verify": true,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+				Proxy:                 "socks5://flag-proxy.example.com:1080",
+				CABundle:              "/etc/ssl/proxy-ca.pem",
+				InsecureSkipTLSVerify: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid proxy scheme",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"PROXY_URL":            "ftp://proxy.example.com",
+			},
+			wantErr:     true,
+			errContains: "invalid proxy URL",
+		},
+		{
+			name: "invalid skip TLS verification setting",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN":     "test-token",
+				"INSECURE_SKIP_TLS_VERIFY": "sometimes",
+			},
+			wantErr:     true,
+			errContains: "invalid INSECURE_SKIP_TLS_VERIFY environment variable",
+		},
+		{
+			name: "invalid fallback endpoint URL",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"FALLBACK_ENDPOINT":    "proxy",
+			},
+			wantErr:     true,
+			errContains: "invalid fallback endpoint URL",
+		},
+		{
+			name: "concurrent request limit from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN":    "test-token",
+				"MAX_CONCURRENT_REQUESTS": "4",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: 4,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "concurrent request limit flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN":    "test-token",
+				"MAX_CONCURRENT_REQUESTS": "4",
+			},
+			flags: map[string]interface{}{
+				"max-concurrent-requests": 0,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: 0,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid concurrent request limit",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN":    "test-token",
+				"MAX_CONCURRENT_REQUESTS": "-1",
+			},
+			wantErr:     true,
+			errContains: "max concurrent requests cannot be negative",
+		},
+		{
+			name: "request rate limit from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REQUESTS_PER_SECOND":  "2.5",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     2.5,
+			},
+			wantErr: false,
+		},
+		{
+			name: "request rate limit flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REQUESTS_PER_SECOND":  "2.5",
+			},
+			flags: map[string]interface{}{
+				"requests-per-second": 0.0,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     0,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid request rate limit",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REQUESTS_PER_SECOND":  "fast",
+			},
+			wantErr:     true,
+			errContains: "invalid REQUESTS_PER_SECOND",
+		},
+		{
+			name: "negative request rate limit",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REQUESTS_PER_SECOND":  "-1",
+			},
+			wantErr:     true,
+			errContains: "requests per second cannot be negative",
+		},
+		{
+			name: "hedged reads from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"HEDGE_READS":          "true",
+				"HEDGE_CLASSES":        "customers,releases=250ms",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				HedgeReads:            true,
+				HedgeClasses:          []string{"customers", "releases=250ms"},
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "hedged read flags",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+			},
+			flags: map[string]interface{}{
+				"hedge-reads":   true,
+				"hedge-classes": "apps",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				HedgeReads:            true,
+				HedgeClasses:          []string{"apps"},
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid hedge class",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"HEDGE_CLASSES":        "widgets",
+			},
+			wantErr:     true,
+			errContains: "unknown hedge class",
+		},
+		{
+			name: "developer mode from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"DEV_MODE":             "true",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				DevMode:               true,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "developer mode flag overrides environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"DEV_MODE":             "true",
+			},
+			flags: map[string]interface{}{
+				"dev": false,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				DevMode:               false,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid developer mode",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"DEV_MODE":             "sometimes",
+			},
+			wantErr:     true,
+			errContains: "invalid DEV_MODE environment variable",
+		},
+		{
+			name: "locale from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"LOCALE":               "de-DE",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				Locale:                "de-DE",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "locale flag overrides environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"LOCALE":               "de-DE",
+			},
+			flags: map[string]interface{}{
+				"locale": "fr-FR",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				Locale:                "fr-FR",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported locale",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"LOCALE":               "xx-YY",
+			},
+			wantErr:     true,
+			errContains: "unsupported locale",
+		},
+		{
+			name: "data directory flag overrides environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"DATA_DIR":             "/var/lib/from-env",
+			},
+			flags: map[string]interface{}{
+				"data-dir": "/var/lib/from-flag",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				DataDir:               "/var/lib/from-flag",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "read-only mode from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REPLICATED_READ_ONLY": "true",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				ReadOnly:              true,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "read-only flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+			},
+			flags: map[string]interface{}{
+				"read-only": true,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				ReadOnly:              true,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid read-only mode",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REPLICATED_READ_ONLY": "maybe",
+			},
+			wantErr:     true,
+			errContains: "invalid REPLICATED_READ_ONLY environment variable",
+		},
+		{
+			name: "dry-run mode from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REPLICATED_DRY_RUN":   "true",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				DryRun:                true,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "dry-run flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REPLICATED_DRY_RUN":   "true",
+			},
+			flags: map[string]interface{}{
+				"dry-run": false,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				DryRun:                false,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "mock mode without a token",
+			envVars: map[string]string{
+				"REPLICATED_MOCK":          "true",
+				"REPLICATED_MOCK_FIXTURES": "/env/fixtures",
+			},
+			flags: map[string]interface{}{
+				"mock-fixtures": "/flag/fixtures",
+			},
+			want: &Config{
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				Mock:                  true,
+				MockFixtures:          "/flag/fixtures",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "mock flag",
+			flags: map[string]interface{}{
+				"mock": true,
+			},
+			want: &Config{
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				Mock:                  true,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "recording fixtures",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN":       "test-token",
+				"REPLICATED_RECORD_FIXTURES": "/env/recording",
+			},
+			flags: map[string]interface{}{
+				"record-fixtures": "/flag/recording",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				RecordFixtures:        "/flag/recording",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "recording fixtures in mock mode",
+			envVars: map[string]string{
+				"REPLICATED_MOCK":            "true",
+				"REPLICATED_RECORD_FIXTURES": "/env/recording",
+			},
+			wantErr:     true,
+			errContains: "fixtures cannot be recorded in mock mode",
+		},
+		{
+			name: "invalid mock mode",
+			envVars: map[string]string{
+				"REPLICATED_MOCK": "maybe",
+			},
+			wantErr:     true,
+			errContains: "invalid REPLICATED_MOCK environment variable",
+		},
+		{
+			name: "default application flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REPLICATED_APP":       "env-app",
+			},
+			flags: map[string]interface{}{
+				"app": "flag-app",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				App:                   "flag-app",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "cluster commands flag",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"CLUSTER_COMMANDS":     "false",
+			},
+			flags: map[string]interface{}{
+				"cluster-commands": true,
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				ClusterCommands:       true,
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid cluster commands setting",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"CLUSTER_COMMANDS":     "sometimes",
+			},
+			wantErr:     true,
+			errContains: "invalid CLUSTER_COMMANDS environment variable",
+		},
+		{
+			name: "invalid dry-run mode",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"REPLICATED_DRY_RUN":   "maybe",
+			},
+			wantErr:     true,
+			errContains: "invalid REPLICATED_DRY_RUN environment variable",
+		},
+		{
+			name: "tool lists from environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"ENABLED_TOOLS":        "customers, releases,",
+				"DISABLED_TOOLS":       "search_customers",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				EnabledTools:          []string{"customers", "releases"},
+				DisabledTools:         []string{"search_customers"},
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "tool list flags override environment",
+			envVars: map[string]string{
+				"REPLICATED_API_TOKEN": "test-token",
+				"ENABLED_TOOLS":        "applications",
+			},
+			flags: map[string]interface{}{
+				"enabled-tools": "customers,releases",
+				"disable-tool":  "search_customers",
+			},
+			want: &Config{
+				APIToken:              "test-token",
+				LogLevel:              DefaultLogLevel,
+				Timeout:               DefaultTimeout,
+				EnabledTools:          []string{"customers", "releases"},
+				DisabledTools:         []string{"search_customers"},
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clear environment
+			clearTestEnv()
+
+			// Set test environment variables
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+			defer clearTestEnv()
+
+			// Create test command with flags
+			cmd := createTestCommand()
+
+			// Build command line args from flags
+			var args []string
+			if tt.flags != nil {
+				for flag, value := range tt.flags {
+					switch v := value.(type) {
+					case string:
+						args = append(args, "--"+flag, v)
+					case int:
+						args = append(args, "--"+flag, strconv.Itoa(v))
+					case float64:
+						args = append(args, "--"+flag, strconv.FormatFloat(v, 'f', -1, 64))
+					case bool:
+						args = append(args, "--"+flag+"="+strconv.FormatBool(v))
+					}
+				}
+			}
+
+			// Parse the flags to simulate actual command execution
+			if len(args) > 0 {
+				cmd.SetArgs(args)
+				_ = cmd.ParseFlags(args)
+			}
+
+			got, err := Load(cmd)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Load() expected error but got none")
+					return
+				}
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Load() error = %v, expected to contain %v", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Load() unexpected error = %v", err)
+				return
+			}
+
+			if got.APIToken != tt.want.APIToken {
+				t.Errorf("Load() APIToken = %v, want %v", got.APIToken, tt.want.APIToken)
+			}
+			if got.LogLevel != tt.want.LogLevel {
+				t.Errorf("Load() LogLevel = %v, want %v", got.LogLevel, tt.want.LogLevel)
+			}
+			if got.Timeout != tt.want.Timeout {
+				t.Errorf("Load() Timeout = %v, want %v", got.Timeout, tt.want.Timeout)
+			}
+			if got.Endpoint != tt.want.Endpoint {
+				t.Errorf("Load() Endpoint = %v, want %v", got.Endpoint, tt.want.Endpoint)
+			}
+			if got.FallbackEndpoint != tt.want.FallbackEndpoint {
+				t.Errorf("Load() FallbackEndpoint = %v, want %v", got.FallbackEndpoint, tt.want.FallbackEndpoint)
+			}
+			if got.Proxy != tt.want.Proxy || got.CABundle != tt.want.CABundle ||
+				got.InsecureSkipTLSVerify != tt.want.InsecureSkipTLSVerify {
+				t.Errorf("Load() Proxy = %v, CABundle = %v, InsecureSkipTLSVerify = %v, want %v, %v, %v",
+					got.Proxy, got.CABundle, got.InsecureSkipTLSVerify,
+					tt.want.Proxy, tt.want.CABundle, tt.want.InsecureSkipTLSVerify)
+			}
+			if got.HARFile != tt.want.HARFile {
+				t.Errorf("Load() HARFile = %v, want %v", got.HARFile, tt.want.HARFile)
+			}
+			if got.AuditLogFile != tt.want.AuditLogFile {
+				t.Errorf("Load() AuditLogFile = %v, want %v", got.AuditLogFile, tt.want.AuditLogFile)
+			}
+			if !slices.Equal(got.RedactPatterns, tt.want.RedactPatterns) {
+				t.Errorf("Load() RedactPatterns = %v, want %v", got.RedactPatterns, tt.want.RedactPatterns)
+			}
+			if got.MaxConcurrentRequests != tt.want.MaxConcurrentRequests {
+				t.Errorf("Load() MaxConcurrentRequests = %v, want %v",
+					got.MaxConcurrentRequests, tt.want.MaxConcurrentRequests)
+			}
+			if got.RequestsPerSecond != tt.want.RequestsPerSecond {
+				t.Errorf("Load() RequestsPerSecond = %v, want %v", got.RequestsPerSecond, tt.want.RequestsPerSecond)
+			}
+			if got.HedgeReads != tt.want.HedgeReads {
+				t.Errorf("Load() HedgeReads = %v, want %v", got.HedgeReads, tt.want.HedgeReads)
+			}
+			if !slices.Equal(got.HedgeClasses, tt.want.HedgeClasses) {
+				t.Errorf("Load() HedgeClasses = %v, want %v", got.HedgeClasses, tt.want.HedgeClasses)
+			}
+			if got.DevMode != tt.want.DevMode {
+				t.Errorf("Load() DevMode = %v, want %v", got.DevMode, tt.want.DevMode)
+			}
+			if got.Locale != tt.want.Locale {
+				t.Errorf("Load() Locale = %v, want %v", got.Locale, tt.want.Locale)
+			}
+			if got.DataDir != tt.want.DataDir {
+				t.Errorf("Load() DataDir = %v, want %v", got.DataDir, tt.want.DataDir)
+			}
+			if got.ReadOnly != tt.want.ReadOnly {
+				t.Errorf("Load() ReadOnly = %v, want %v", got.ReadOnly, tt.want.ReadOnly)
+			}
+			if got.DryRun != tt.want.DryRun {
+				t.Errorf("Load() DryRun = %v, want %v", got.DryRun, tt.want.DryRun)
+			}
+			if got.RecordFixtures != tt.want.RecordFixtures {
+				t.Errorf("Load() RecordFixtures = %v, want %v", got.RecordFixtures, tt.want.RecordFixtures)
+			}
+			if got.Mock != tt.want.Mock || got.MockFixtures != tt.want.MockFixtures {
+				t.Errorf("Load() Mock = %v (%s), want %v (%s)", got.Mock, got.MockFixtures, tt.want.Mock,
+					tt.want.MockFixtures)
+			}
+			if got.ClusterCommands != tt.want.ClusterCommands {
+				t.Errorf("Load() ClusterCommands = %v, want %v", got.ClusterCommands, tt.want.ClusterCommands)
+			}
+			if got.App != tt.want.App {
+				t.Errorf("Load() App = %v, want %v", got.App, tt.want.App)
+			}
+			if !slices.Equal(got.EnabledTools, tt.want.EnabledTools) {
+				t.Errorf("Load() EnabledTools = %v, want %v", got.EnabledTools, tt.want.EnabledTools)
+			}
+			if !slices.Equal(got.DisabledTools, tt.want.DisabledTools) {
+				t.Errorf("Load() DisabledTools = %v, want %v", got.DisabledTools, tt.want.DisabledTools)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *Config
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid configuration",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				Endpoint: "https://api.example.com",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid minimal configuration",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "fatal",
+				Timeout:  1 * time.Second,
+				Endpoint: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing API token",
+			config: &Config{
+				APIToken: "",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+			},
+			wantErr:     true,
+			errContains: "API token is required",
+		},
+		{
+			name: "invalid log level",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "INVALID",
+				Timeout:  30 * time.Second,
+			},
+			wantErr:     true,
+			errContains: "invalid log level",
+		},
+		{
+			name: "timeout too short",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "info",
+				Timeout:  500 * time.Millisecond,
+			},
+			wantErr:     true,
+			errContains: "timeout must be between",
+		},
+		{
+			name: "timeout too long",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "info",
+				Timeout:  400 * time.Second,
+			},
+			wantErr:     true,
+			errContains: "timeout must be between",
+		},
+		{
+			name: "invalid endpoint",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				Endpoint: "not-a-valid-url",
+			},
+			wantErr:     true,
+			errContains: "invalid endpoint URL",
+		},
+		{
+			name: "valid locale",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				Locale:   "fr_FR",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported locale",
+			config: &Config{
+				APIToken: "test-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				Locale:   "klingon",
+			},
+			wantErr:     true,
+			errContains: "unsupported locale 'klingon'",
+		},
+		{
+			name: "API gateway settings",
+			config: &Config{
+				APIToken:       "test-token",
+				LogLevel:       "info",
+				Timeout:        30 * time.Second,
+				ClientCertFile: "/etc/gateway/client.pem",
+				ClientKeyFile:  "/etc/gateway/client-key.pem",
+				APIHeaders:     map[string]string{"X-Gateway-Key": "secret"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "client certificate without key",
+			config: &Config{
+				APIToken:       "test-token",
+				LogLevel:       "info",
+				Timeout:        30 * time.Second,
+				ClientCertFile: "/etc/gateway/client.pem",
+			},
+			wantErr:     true,
+			errContains: "client certificate and key must be set together",
+		},
+		{
+			name: "invalid API header name",
+			config: &Config{
+				APIToken:   "test-token",
+				LogLevel:   "info",
+				Timeout:    30 * time.Second,
+				APIHeaders: map[string]string{"X Gateway Key": "secret"},
+			},
+			wantErr:     true,
+			errContains: "invalid API header name",
+		},
+		{
+			name: "API header set by the server",
+			config: &Config{
+				APIToken:   "test-token",
+				LogLevel:   "info",
+				Timeout:    30 * time.Second,
+				APIHeaders: map[string]string{"authorization": "Bearer other"},
+			},
+			wantErr:     true,
+			errContains: "is set by the server",
+		},
+		{
+			name: "API header value with a line break",
+			config: &Config{
+				APIToken:   "test-token",
+				LogLevel:   "info",
+				Timeout:    30 * time.Second,
+				APIHeaders: map[string]string{"X-Tenant": "acme\r\nX-Injected: yes"},
+			},
+			wantErr:     true,
+			errContains: "cannot contain line breaks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() expected error but got none")
+					return
+				}
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Validate() error = %v, expected to contain %v", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestIsValidLogLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		want  bool
+	}{
+		{"valid fatal", "fatal", true},
+		{"valid error", "error", true},
+		{"valid info", "info", true},
+		{"valid debug", "debug", true},
+		{"valid trace", "trace", true},
+		{"valid uppercase", "INFO", true},
+		{"valid mixed case", "Debug", true},
+		{"invalid level", "invalid", false},
+		{"empty string", "", false},
+		{"number", "123", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isValidLogLevel(tt.level); got != tt.want {
+				t.Errorf("isValidLogLevel(%q) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Transport(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		envVars     map[string]string
+		args        []string
+		want        Config
+		errContains string
+	}{
+		{
+			name: "defaults",
+			want: Config{Transport: TransportStdio, ListenAddress: DefaultListenAddress, AccessLogSampleRate: 1},
+		},
+		{
+			name: "environment variables",
+			envVars: map[string]string{
+				"TRANSPORT": "http", "LISTEN_ADDRESS": "127.0.0.1:9090", "ACCESS_LOG_FILE": "/var/log/access.log",
+				"ACCESS_LOG_SAMPLE_RATE": "0.1", "METRICS_ENDPOINT": "true",
+			},
+			want: Config{
+				Transport: TransportHTTP, ListenAddress: "127.0.0.1:9090", AccessLogFile: "/var/log/access.log",
+				AccessLogSampleRate: 0.1, MetricsEndpoint: true,
+			},
+		},
+		{
+			name: "config file",
+			file: "transport: http\nlisten: localhost:9090\naccess_log_sample_rate: 0\nmetrics_endpoint: true\n",
+			want: Config{
+				Transport: TransportHTTP, ListenAddress: "localhost:9090", AccessLogSampleRate: 0, MetricsEndpoint: true,
+			},
+		},
+		{
+			name:    "flags override environment variables",
+			envVars: map[string]string{"TRANSPORT": "stdio", "ACCESS_LOG_SAMPLE_RATE": "0.1", "METRICS_ENDPOINT": "true"},
+			args: []string{
+				"--transport", "http", "--listen", "0.0.0.0:8443", "--access-log-sample-rate", "0.5",
+				"--metrics-endpoint=false", "--http-auth-token", "client-token",
+			},
+			want: Config{
+				Transport: TransportHTTP, ListenAddress: "0.0.0.0:8443", AccessLogSampleRate: 0.5,
+				HTTPAuthToken: "client-token",
+			},
+		},
+		{
+			name:    "auth token from the environment",
+			envVars: map[string]string{"HTTP_AUTH_TOKEN": "client-token"},
+			args:    []string{"--transport", "http", "--listen", ":8080"},
+			want: Config{
+				Transport: TransportHTTP, ListenAddress: ":8080", AccessLogSampleRate: 1,
+				HTTPAuthToken: "client-token",
+			},
+		},
+		{
+			name: "auth token from the config file",
+			file: "transport: http\nlisten: 10.0.0.5:8080\nhttp_auth_token: client-token\n",
+			want: Config{
+				Transport: TransportHTTP, ListenAddress: "10.0.0.5:8080", AccessLogSampleRate: 1,
+				HTTPAuthToken: "client-token",
+			},
+		},
+		{
+			name: "loopback without an auth token",
+			args: []string{"--transport", "http", "--listen", "[::1]:8080"},
+			want: Config{Transport: TransportHTTP, ListenAddress: "[::1]:8080", AccessLogSampleRate: 1},
+		},
+		{
+			name:        "all interfaces without an auth token",
+			args:        []string{"--transport", "http", "--listen", ":8080"},
+			errContains: "listen address ':8080' is not loopback",
+		},
+		{
+			name:        "public address without an auth token",
+			envVars:     map[string]string{"TRANSPORT": "http", "LISTEN_ADDRESS": "0.0.0.0:8443"},
+			errContains: "listen address '0.0.0.0:8443' is not loopback",
+		},
+		{
+			name:        "unknown transport",
+			args:        []string{"--transport", "websocket"},
+			errContains: "invalid transport 'websocket'",
+		},
+		{
+			name:        "listen address without a port",
+			args:        []string{"--transport", "http", "--listen", "localhost"},
+			errContains: "invalid listen address 'localhost'",
+		},
+		{
+			name:        "sample rate out of range",
+			args:        []string{"--access-log-sample-rate", "1.5"},
+			errContains: "access log sample rate must be between 0 and 1",
+		},
+		{
+			name:        "invalid sample rate variable",
+			envVars:     map[string]string{"ACCESS_LOG_SAMPLE_RATE": "most"},
+			errContains: "invalid ACCESS_LOG_SAMPLE_RATE",
+		},
+		{
+			name:        "invalid metrics endpoint variable",
+			envVars:     map[string]string{"METRICS_ENDPOINT": "sometimes"},
+			errContains: "invalid METRICS_ENDPOINT",
+		},
+		{
+			name:    "OTLP endpoint flag overrides the standard variable",
+			envVars: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"},
+			args:    []string{"--otlp-endpoint", "https://traces.example.com"},
+			want: Config{
+				Transport: TransportStdio, ListenAddress: DefaultListenAddress, AccessLogSampleRate: 1,
+				OTLPEndpoint: "https://traces.example.com",
+			},
+		},
+		{
+			name:        "OTLP endpoint without a scheme",
+			envVars:     map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "collector:4318"},
+			errContains: "invalid OTLP endpoint URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			configDir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", configDir)
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			if tt.file != "" {
+				path := filepath.Join(configDir, DefaultConfigFile)
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatalf("Failed to create config directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("Failed to write config file: %v", err)
+				}
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.Transport != tt.want.Transport || got.ListenAddress != tt.want.ListenAddress ||
+				got.AccessLogFile != tt.want.AccessLogFile || got.AccessLogSampleRate != tt.want.AccessLogSampleRate {
+				t.Errorf("Load() transport settings = %s %s %q %v, want %s %s %q %v",
+					got.Transport, got.ListenAddress, got.AccessLogFile, got.AccessLogSampleRate,
+					tt.want.Transport, tt.want.ListenAddress, tt.want.AccessLogFile, tt.want.AccessLogSampleRate)
+			}
+			if got.OTLPEndpoint != tt.want.OTLPEndpoint {
+				t.Errorf("Load() OTLPEndpoint = %q, want %q", got.OTLPEndpoint, tt.want.OTLPEndpoint)
+			}
+			if got.MetricsEndpoint != tt.want.MetricsEndpoint {
+				t.Errorf("Load() MetricsEndpoint = %t, want %t", got.MetricsEndpoint, tt.want.MetricsEndpoint)
+			}
+			if got.HTTPAuthToken != tt.want.HTTPAuthToken {
+				t.Errorf("Load() HTTPAuthToken = %q, want %q", got.HTTPAuthToken, tt.want.HTTPAuthToken)
+			}
+		})
+	}
+}
+
+func TestLoad_SLO(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		envVars     map[string]string
+		args        []string
+		want        Config
+		errContains string
+	}{
+		{
+			name: "defaults",
+			want: Config{
+				SLOAvailability: DefaultSLOAvailability, SLOLatency: DefaultSLOLatency,
+				SLOLatencyTarget: DefaultSLOLatencyTarget,
+			},
+		},
+		{
+			name:    "environment variables",
+			envVars: map[string]string{"SLO_AVAILABILITY": "0.999", "SLO_LATENCY": "500ms", "SLO_LATENCY_TARGET": "0.9"},
+			want:    Config{SLOAvailability: 0.999, SLOLatency: 500 * time.Millisecond, SLOLatencyTarget: 0.9},
+		},
+		{
+			name: "config file",
+			file: "slo_availability: 0.95\nslo_latency: 5s\n",
+			want: Config{SLOAvailability: 0.95, SLOLatency: 5 * time.Second, SLOLatencyTarget: DefaultSLOLatencyTarget},
+		},
+		{
+			name:    "flags override environment variables",
+			envVars: map[string]string{"SLO_AVAILABILITY": "0.999", "SLO_LATENCY": "500ms"},
+			args:    []string{"--slo-availability", "0.98", "--slo-latency", "1s"},
+			want:    Config{SLOAvailability: 0.98, SLOLatency: time.Second, SLOLatencyTarget: DefaultSLOLatencyTarget},
+		},
+		{
+			name:        "availability of 1",
+			args:        []string{"--slo-availability", "1"},
+			errContains: "SLO availability must be between 0 and 1, excluding 1",
+		},
+		{
+			name:        "negative latency",
+			args:        []string{"--slo-latency", "-1s"},
+			errContains: "SLO latency cannot be negative",
+		},
+		{
+			name:        "invalid latency variable",
+			envVars:     map[string]string{"SLO_LATENCY": "2"},
+			errContains: "invalid SLO_LATENCY environment variable",
+		},
+		{
+			name:        "invalid target variable",
+			envVars:     map[string]string{"SLO_LATENCY_TARGET": "most"},
+			errContains: "invalid SLO_LATENCY_TARGET environment variable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			configDir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", configDir)
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			if tt.file != "" {
+				path := filepath.Join(configDir, DefaultConfigFile)
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatalf("Failed to create config directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("Failed to write config file: %v", err)
+				}
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.SLOAvailability != tt.want.SLOAvailability || got.SLOLatency != tt.want.SLOLatency ||
+				got.SLOLatencyTarget != tt.want.SLOLatencyTarget {
+				t.Errorf("Load() objectives = %v %v %v, want %v %v %v",
+					got.SLOAvailability, got.SLOLatency, got.SLOLatencyTarget,
+					tt.want.SLOAvailability, tt.want.SLOLatency, tt.want.SLOLatencyTarget)
+			}
+		})
+	}
+}
+
+func TestLoad_ToolTimeouts(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		envVars     map[string]string
+		args        []string
+		want        Config
+		errContains string
+	}{
+		{
+			name: "defaults",
+			want: Config{ToolTimeout: DefaultToolTimeout},
+		},
+		{
+			name:    "environment variables",
+			envVars: map[string]string{"TOOL_TIMEOUT": "30s", "TOOL_TIMEOUTS": "create_cluster=10m, list_customers=0s"},
+			want: Config{ToolTimeout: 30 * time.Second, ToolTimeouts: map[string]time.Duration{
+				"create_cluster": 10 * time.Minute, "list_customers": 0,
+			}},
+		},
+		{
+			name: "config file",
+			file: "tool_timeout: 1m\ntool_timeouts:\n  create_cluster: 5m\n",
+			want: Config{ToolTimeout: time.Minute, ToolTimeouts: map[string]time.Duration{"create_cluster": 5 * time.Minute}},
+		},
+		{
+			name:    "flags override environment variables per tool",
+			envVars: map[string]string{"TOOL_TIMEOUT": "30s", "TOOL_TIMEOUTS": "create_cluster=10m,create_vm=10m"},
+			args:    []string{"--tool-timeout", "45s", "--tool-timeouts", "create_cluster=15m"},
+			want: Config{ToolTimeout: 45 * time.Second, ToolTimeouts: map[string]time.Duration{
+				"create_cluster": 15 * time.Minute, "create_vm": 10 * time.Minute,
+			}},
+		},
+		{
+			name:        "negative timeout",
+			args:        []string{"--tool-timeout", "-1s"},
+			errContains: "tool timeout cannot be negative",
+		},
+		{
+			name:        "negative tool timeout",
+			args:        []string{"--tool-timeouts", "create_cluster=-1m"},
+			errContains: "tool timeout for create_cluster cannot be negative",
+		},
+		{
+			name:        "invalid timeout variable",
+			envVars:     map[string]string{"TOOL_TIMEOUT": "2"},
+			errContains: "invalid TOOL_TIMEOUT environment variable",
+		},
+		{
+			name:        "tool timeout without a duration",
+			envVars:     map[string]string{"TOOL_TIMEOUTS": "create_cluster"},
+			errContains: "'create_cluster' must be a tool name and a duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			configDir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", configDir)
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			if tt.file != "" {
+				path := filepath.Join(configDir, DefaultConfigFile)
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatalf("Failed to create config directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("Failed to write config file: %v", err)
+				}
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.ToolTimeout != tt.want.ToolTimeout || !maps.Equal(got.ToolTimeouts, tt.want.ToolTimeouts) {
+				t.Errorf("Load() tool timeouts = %v %v, want %v %v",
+					got.ToolTimeout, got.ToolTimeouts, tt.want.ToolTimeout, tt.want.ToolTimeouts)
+			}
+		})
+	}
+}
+
+func TestConfig_ToolTimeoutFor(t *testing.T) {
+	cfg := &Config{ToolTimeout: time.Minute, ToolTimeouts: map[string]time.Duration{
+		"create_cluster": 10 * time.Minute, "list_customers": 0,
+	}}
+
+	tests := []struct {
+		tool string
+		want time.Duration
+	}{
+		{tool: "list_applications", want: time.Minute},
+		{tool: "create_cluster", want: 10 * time.Minute},
+		{tool: "list_customers", want: 0},
+	}
+	for _, tt := range tests {
+		if got := cfg.ToolTimeoutFor(tt.tool); got != tt.want {
+			t.Errorf("ToolTimeoutFor(%s) = %v, want %v", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_SLOObjectives(t *testing.T) {
+	// Unset objectives fall back to the defaults
+	objectives := (&Config{SLOLatency: time.Second}).SLOObjectives()
+	if objectives.Availability != DefaultSLOAvailability || objectives.Latency != time.Second ||
+		objectives.LatencyTarget != DefaultSLOLatencyTarget {
+		t.Errorf("SLOObjectives() = %+v, want the defaults with a latency of 1s", objectives)
+	}
+}
+
+func TestLoad_TLS(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		envVars     map[string]string
+		args        []string
+		want        Config
+		errContains string
+	}{
+		{
+			name: "certificate files from environment variables",
+			envVars: map[string]string{
+				"TLS_CERT_FILE": "/etc/tls/tls.crt", "TLS_KEY_FILE": "/etc/tls/tls.key", "HTTP_AUTH_TOKEN": "client-token",
+			},
+			args: []string{"--transport", "http"},
+			want: Config{TLSCertFile: "/etc/tls/tls.crt", TLSKeyFile: "/etc/tls/tls.key"},
+		},
+		{
+			name: "autocert domains from the config file",
+			file: "transport: http\nlisten: :443\nhttp_auth_token: client-token\ntls_autocert_domains: [mcp.example.com]\n",
+			want: Config{TLSAutocertDomains: []string{"mcp.example.com"}},
+		},
+		{
+			name:    "flags override environment variables",
+			envVars: map[string]string{"TLS_AUTOCERT_DOMAINS": "old.example.com"},
+			args: []string{
+				"--transport", "http", "--tls-autocert-domains", "mcp.example.com,mcp.example.org",
+				"--http-auth-token", "client-token",
+			},
+			want: Config{TLSAutocertDomains: []string{"mcp.example.com", "mcp.example.org"}},
+		},
+		{
+			name:        "certificate files without an auth token",
+			args:        []string{"--transport", "http", "--tls-cert", "tls.crt", "--tls-key", "tls.key"},
+			errContains: "TLS settings require an HTTP auth token",
+		},
+		{
+			name:        "autocert without an auth token",
+			file:        "transport: http\nlisten: 127.0.0.1:443\ntls_autocert_domains: [mcp.example.com]\n",
+			errContains: "TLS settings require an HTTP auth token",
+		},
+		{
+			name:        "certificate without a key",
+			args:        []string{"--transport", "http", "--tls-cert", "/etc/tls/tls.crt"},
+			errContains: "TLS certificate and key must be set together",
+		},
+		{
+			name: "certificate files and autocert",
+			args: []string{
+				"--transport", "http", "--tls-cert", "tls.crt", "--tls-key", "tls.key",
+				"--tls-autocert-domains", "mcp.example.com",
+			},
+			errContains: "cannot both be set",
+		},
+		{
+			name:        "TLS over stdio",
+			args:        []string{"--tls-cert", "tls.crt", "--tls-key", "tls.key"},
+			errContains: "TLS settings require the http transport",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			configDir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", configDir)
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			if tt.file != "" {
+				path := filepath.Join(configDir, DefaultConfigFile)
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatalf("Failed to create config directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("Failed to write config file: %v", err)
+				}
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.TLSCertFile != tt.want.TLSCertFile || got.TLSKeyFile != tt.want.TLSKeyFile ||
+				!slices.Equal(got.TLSAutocertDomains, tt.want.TLSAutocertDomains) {
+				t.Errorf("Load() TLS settings = %q %q %v, want %q %q %v",
+					got.TLSCertFile, got.TLSKeyFile, got.TLSAutocertDomains,
+					tt.want.TLSCertFile, tt.want.TLSKeyFile, tt.want.TLSAutocertDomains)
+			}
+			if !got.TLSEnabled() {
+				t.Error("TLSEnabled() = false, want true")
+			}
+		})
+	}
+}
+
+func TestLoad_AllowedOrigins(t *testing.T) {
+	// Keep any config file in the user's home directory out of the tests
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		args        []string
+		want        []string
+		errContains string
+	}{
+		{name: "none by default"},
+		{
+			name:    "environment variable",
+			envVars: map[string]string{"ALLOWED_ORIGINS": "https://agent.example.com, http://localhost:3000"},
+			want:    []string{"https://agent.example.com", "http://localhost:3000"},
+		},
+		{
+			name:    "flag overrides environment variable",
+			envVars: map[string]string{"ALLOWED_ORIGINS": "https://agent.example.com"},
+			args:    []string{"--allowed-origins", "*"},
+			want:    []string{"*"},
+		},
+		{
+			name: "trailing slash",
+			args: []string{"--allowed-origins", "https://agent.example.com/"},
+			want: []string{"https://agent.example.com/"},
+		},
+		{
+			name:        "origin with a path",
+			args:        []string{"--allowed-origins", "https://agent.example.com/chat"},
+			errContains: "invalid allowed origin 'https://agent.example.com/chat'",
+		},
+		{
+			name:        "origin without a scheme",
+			args:        []string{"--allowed-origins", "agent.example.com"},
+			errContains: "invalid allowed origin 'agent.example.com'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if !slices.Equal(got.AllowedOrigins, tt.want) {
+				t.Errorf("Load() AllowedOrigins = %v, want %v", got.AllowedOrigins, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Search(t *testing.T) {
+	// Keep any config file in the user's home directory out of the tests
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		name          string
+		envVars       map[string]string
+		args          []string
+		wantScanLimit int
+		wantIndex     bool
+		errContains   string
+	}{
+		{name: "defaults", wantScanLimit: DefaultSearchScanLimit},
+		{
+			name:          "environment variables",
+			envVars:       map[string]string{"SEARCH_SCAN_LIMIT": "1000", "SEARCH_INDEX": "true"},
+			wantScanLimit: 1000,
+			wantIndex:     true,
+		},
+		{
+			name:          "flags override environment variables",
+			envVars:       map[string]string{"SEARCH_SCAN_LIMIT": "1000", "SEARCH_INDEX": "true"},
+			args:          []string{"--search-scan-limit", "0", "--search-index=false"},
+			wantScanLimit: 0,
+		},
+		{
+			name:        "invalid scan limit environment variable",
+			envVars:     map[string]string{"SEARCH_SCAN_LIMIT": "all"},
+			errContains: "invalid SEARCH_SCAN_LIMIT environment variable 'all'",
+		},
+		{
+			name:        "invalid search index environment variable",
+			envVars:     map[string]string{"SEARCH_INDEX": "sometimes"},
+			errContains: "invalid SEARCH_INDEX environment variable 'sometimes'",
+		},
+		{
+			name:        "negative scan limit",
+			args:        []string{"--search-scan-limit", "-1"},
+			errContains: "search scan limit cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.SearchScanLimit != tt.wantScanLimit {
+				t.Errorf("Load() SearchScanLimit = %d, want %d", got.SearchScanLimit, tt.wantScanLimit)
+			}
+			if got.SearchIndex != tt.wantIndex {
+				t.Errorf("Load() SearchIndex = %t, want %t", got.SearchIndex, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestLoad_InputLimits(t *testing.T) {
+	// Keep any config file in the user's home directory out of the tests
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		name             string
+		envVars          map[string]string
+		args             []string
+		wantRequestBytes int
+		wantArgBytes     int
+		wantArrayLength  int
+		wantResultBytes  int
+		errContains      string
+	}{
+		{
+			name:             "defaults",
+			wantRequestBytes: DefaultMaxRequestBytes,
+			wantArgBytes:     DefaultMaxArgumentBytes,
+			wantArrayLength:  DefaultMaxArrayLength,
+			wantResultBytes:  DefaultMaxResultBytes,
+		},
+		{
+			name: "environment variables",
+			envVars: map[string]string{
+				"MAX_REQUEST_BYTES": "65536", "MAX_ARGUMENT_BYTES": "4096", "MAX_ARRAY_LENGTH": "50",
+				"MAX_RESULT_BYTES": "32768",
+			},
+			wantRequestBytes: 65536,
+			wantArgBytes:     4096,
+			wantArrayLength:  50,
+			wantResultBytes:  32768,
+		},
+		{
+			name:             "flags override environment variables",
+			envVars:          map[string]string{"MAX_ARRAY_LENGTH": "50", "MAX_RESULT_BYTES": "32768"},
+			args:             []string{"--max-array-length", "0", "--max-argument-bytes", "2048", "--max-result-bytes", "0"},
+			wantRequestBytes: DefaultMaxRequestBytes,
+			wantArgBytes:     2048,
+			wantArrayLength:  0,
+			wantResultBytes:  0,
+		},
+		{
+			name:        "invalid environment variable",
+			envVars:     map[string]string{"MAX_ARGUMENT_BYTES": "1MB"},
+			errContains: "invalid MAX_ARGUMENT_BYTES environment variable '1MB'",
+		},
+		{
+			name:        "negative limit",
+			args:        []string{"--max-request-bytes", "-1"},
+			errContains: "max request bytes cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.MaxRequestBytes != tt.wantRequestBytes {
+				t.Errorf("Load() MaxRequestBytes = %d, want %d", got.MaxRequestBytes, tt.wantRequestBytes)
+			}
+			if got.MaxArgumentBytes != tt.wantArgBytes {
+				t.Errorf("Load() MaxArgumentBytes = %d, want %d", got.MaxArgumentBytes, tt.wantArgBytes)
+			}
+			if got.MaxArrayLength != tt.wantArrayLength {
+				t.Errorf("Load() MaxArrayLength = %d, want %d", got.MaxArrayLength, tt.wantArrayLength)
+			}
+			if got.MaxResultBytes != tt.wantResultBytes {
+				t.Errorf("Load() MaxResultBytes = %d, want %d", got.MaxResultBytes, tt.wantResultBytes)
+			}
+		})
+	}
+}
+
+func TestLoadLocal(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// Neither a token nor a token source that would fail is read
+	cmd := createTestCommand()
+	args := []string{"--data-dir", "/srv/replicated", "--api-token-command", "exit 1"}
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	got, err := LoadLocal(cmd)
+	if err != nil {
+		t.Fatalf("LoadLocal() unexpected error: %v", err)
+	}
+	if got.DataDir != "/srv/replicated" {
+		t.Errorf("LoadLocal() DataDir = %v, want %v", got.DataDir, "/srv/replicated")
+	}
+	if got.APIToken != "" {
+		t.Errorf("LoadLocal() APIToken = %v, want empty", got.APIToken)
+	}
+}
+
+func TestConfig_DataDirectory(t *testing.T) {
+	configured := &Config{DataDir: "/srv/replicated"}
+	if got := configured.DataDirectory(); got != "/srv/replicated" {
+		t.Errorf("DataDirectory() = %v, want %v", got, "/srv/replicated")
+	}
+
+	defaulted := &Config{}
+	if got := defaulted.DataDirectory(); !strings.HasSuffix(got, DefaultDataDir) {
+		t.Errorf("DataDirectory() = %v, expected to end with %v", got, DefaultDataDir)
+	}
+}
+
+func TestLoad_DiskCache(t *testing.T) {
+	tests := []struct {
+		name          string
+		envVars       map[string]string
+		args          []string
+		wantDiskCache bool
+		wantCacheDir  string
+		errContains   string
+	}{
+		{name: "defaults"},
+		{
+			name:          "environment variables",
+			envVars:       map[string]string{"DISK_CACHE": "true", "CACHE_DIR": "/var/cache/replicated"},
+			wantDiskCache: true,
+			wantCacheDir:  "/var/cache/replicated",
+		},
+		{
+			name:          "flags override environment variables",
+			envVars:       map[string]string{"DISK_CACHE": "true", "CACHE_DIR": "/var/cache/replicated"},
+			args:          []string{"--disk-cache=false", "--cache-dir", "/tmp/cache"},
+			wantDiskCache: false,
+			wantCacheDir:  "/tmp/cache",
+		},
+		{
+			name:        "invalid environment variable",
+			envVars:     map[string]string{"DISK_CACHE": "sometimes"},
+			errContains: "invalid DISK_CACHE environment variable 'sometimes'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.DiskCache != tt.wantDiskCache || got.CacheDir != tt.wantCacheDir {
+				t.Errorf("Load() DiskCache = %t, CacheDir = %q, want %t, %q",
+					got.DiskCache, got.CacheDir, tt.wantDiskCache, tt.wantCacheDir)
+			}
+		})
+	}
+}
+
+func TestLoad_ResourcePollInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		args        []string
+		want        time.Duration
+		errContains string
+	}{
+		{name: "default", want: DefaultResourcePollInterval},
+		{
+			name:    "environment variable",
+			envVars: map[string]string{"RESOURCE_POLL_INTERVAL": "30s"},
+			want:    30 * time.Second,
+		},
+		{
+			name:    "flag overrides environment variable",
+			envVars: map[string]string{"RESOURCE_POLL_INTERVAL": "30s"},
+			args:    []string{"--resource-poll-interval", "0"},
+			want:    0,
+		},
+		{
+			name:        "invalid environment variable",
+			envVars:     map[string]string{"RESOURCE_POLL_INTERVAL": "often"},
+			errContains: "invalid RESOURCE_POLL_INTERVAL environment variable 'often'",
+		},
+		{
+			name:        "negative interval",
+			args:        []string{"--resource-poll-interval", "-1m"},
+			errContains: "resource poll interval cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			t.Setenv("REPLICATED_API_TOKEN", "test-token")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.ResourcePollInterval != tt.want {
+				t.Errorf("Load() ResourcePollInterval = %v, want %v", got.ResourcePollInterval, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_CacheDirectory(t *testing.T) {
+	configured := &Config{CacheDir: "/var/cache/replicated"}
+	if got := configured.CacheDirectory(); got != "/var/cache/replicated" {
+		t.Errorf("CacheDirectory() = %v, want %v", got, "/var/cache/replicated")
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "/home/user/.cache")
+	defaulted := &Config{}
+	if got := defaulted.CacheDirectory(); got != filepath.Join("/home/user/.cache", DefaultCacheDir) {
+		t.Errorf("CacheDirectory() = %v, want it under XDG_CACHE_HOME", got)
+	}
+}
+
+func TestConfig_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+		want   []string // substrings that should be present
+	}{
+		{
+			name: "config with all fields",
+			config: &Config{
+				APIToken: "secret-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				Endpoint: "https://api.example.com",
+			},
+			want: []string{"(set)", "info", "30s", "https://api.example.com"},
+		},
+		{
+			name: "config without token",
+			config: &Config{
+				APIToken: "",
+				LogLevel: "fatal",
+				Timeout:  15 * time.Second,
+				Endpoint: "",
+			},
+			want: []string{"(not set)", "fatal", "15s", "(default)"},
+		},
+		{
+			name: "config with locale",
+			config: &Config{
+				APIToken: "secret-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				Locale:   "pt-BR",
+			},
+			want: []string{"Locale: pt-BR"},
+		},
+		{
+			name: "config with tool lists",
+			config: &Config{
+				APIToken:      "secret-token",
+				LogLevel:      "info",
+				Timeout:       30 * time.Second,
+				EnabledTools:  []string{"customers"},
+				DisabledTools: []string{"search_customers"},
+			},
+			want: []string{"EnabledTools: [customers]", "DisabledTools: [search_customers]"},
+		},
+		{
+			name: "config from profile",
+			config: &Config{
+				APIToken: "secret-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				Profile:  "acme",
+			},
+			want: []string{"Profile: acme"},
 		},
 	}
 
@@ -356,9 +2140,62 @@ func TestConfig_String(t *testing.T) {
 
 func clearTestEnv() {
 	_ = os.Unsetenv("REPLICATED_API_TOKEN")
+	_ = os.Unsetenv("REPLICATED_API_TOKEN_FILE")
+	_ = os.Unsetenv("REPLICATED_API_TOKEN_COMMAND")
+	_ = os.Unsetenv("REPLICATED_API_TOKEN_KEYCHAIN")
 	_ = os.Unsetenv("LOG_LEVEL")
 	_ = os.Unsetenv("TIMEOUT")
 	_ = os.Unsetenv("ENDPOINT")
+	_ = os.Unsetenv("FALLBACK_ENDPOINT")
+	_ = os.Unsetenv("PROXY_URL")
+	_ = os.Unsetenv("CA_BUNDLE")
+	_ = os.Unsetenv("INSECURE_SKIP_TLS_VERIFY")
+	_ = os.Unsetenv("MAX_CONCURRENT_REQUESTS")
+	_ = os.Unsetenv("REQUESTS_PER_SECOND")
+	_ = os.Unsetenv("SEARCH_SCAN_LIMIT")
+	_ = os.Unsetenv("SEARCH_INDEX")
+	_ = os.Unsetenv("MAX_REQUEST_BYTES")
+	_ = os.Unsetenv("MAX_ARGUMENT_BYTES")
+	_ = os.Unsetenv("MAX_ARRAY_LENGTH")
+	_ = os.Unsetenv("MAX_RESULT_BYTES")
+	_ = os.Unsetenv("HAR_FILE")
+	_ = os.Unsetenv("AUDIT_LOG")
+	_ = os.Unsetenv("REDACT_PATTERNS")
+	_ = os.Unsetenv("HEDGE_READS")
+	_ = os.Unsetenv("HEDGE_CLASSES")
+	_ = os.Unsetenv("DEV_MODE")
+	_ = os.Unsetenv("LOCALE")
+	_ = os.Unsetenv("DATA_DIR")
+	_ = os.Unsetenv("DISK_CACHE")
+	_ = os.Unsetenv("CACHE_DIR")
+	_ = os.Unsetenv("RESOURCE_POLL_INTERVAL")
+	_ = os.Unsetenv("REPLICATED_READ_ONLY")
+	_ = os.Unsetenv("REPLICATED_DRY_RUN")
+	_ = os.Unsetenv("REPLICATED_MOCK")
+	_ = os.Unsetenv("REPLICATED_MOCK_FIXTURES")
+	_ = os.Unsetenv("REPLICATED_RECORD_FIXTURES")
+	_ = os.Unsetenv("CLUSTER_COMMANDS")
+	_ = os.Unsetenv("REPLICATED_APP")
+	_ = os.Unsetenv("SLO_AVAILABILITY")
+	_ = os.Unsetenv("SLO_LATENCY")
+	_ = os.Unsetenv("SLO_LATENCY_TARGET")
+	_ = os.Unsetenv("ENABLED_TOOLS")
+	_ = os.Unsetenv("DISABLED_TOOLS")
+	_ = os.Unsetenv("TRANSPORT")
+	_ = os.Unsetenv("LISTEN_ADDRESS")
+	_ = os.Unsetenv("HTTP_AUTH_TOKEN")
+	_ = os.Unsetenv("ACCESS_LOG_FILE")
+	_ = os.Unsetenv("ACCESS_LOG_SAMPLE_RATE")
+	_ = os.Unsetenv("METRICS_ENDPOINT")
+	_ = os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	_ = os.Unsetenv("TOOL_TIMEOUT")
+	_ = os.Unsetenv("TOOL_TIMEOUTS")
+	_ = os.Unsetenv("TLS_CERT_FILE")
+	_ = os.Unsetenv("TLS_KEY_FILE")
+	_ = os.Unsetenv("TLS_AUTOCERT_DOMAINS")
+	_ = os.Unsetenv("ALLOWED_ORIGINS")
+	_ = os.Unsetenv("REPLICATED_CONFIG_FILE")
+	_ = os.Unsetenv("REPLICATED_PROFILE")
 }
 
 func createTestCommand() *cobra.Command {
@@ -369,9 +2206,63 @@ func createTestCommand() *cobra.Command {
 
 	// Add the same flags as the real application
 	cmd.PersistentFlags().String("api-token", "", "Replicated Vendor Portal API token")
+	cmd.PersistentFlags().String("api-token-file", "", "Read the API token from a file")
+	cmd.PersistentFlags().String("api-token-command", "", "Read the API token from a command")
+	cmd.PersistentFlags().String("api-token-keychain", "", "Read the API token from the OS keychain")
 	cmd.PersistentFlags().String("log-level", "fatal", "Log level (fatal, error, info, debug, trace)")
 	cmd.PersistentFlags().Int("timeout", 30, "API request timeout in seconds")
 	cmd.PersistentFlags().String("endpoint", "", "API endpoint (hidden)")
+	cmd.PersistentFlags().String("app", "", "Default application")
+	cmd.PersistentFlags().String("fallback-endpoint", "", "Fallback API endpoint for reads")
+	cmd.PersistentFlags().String("proxy", "", "Proxy for API requests")
+	cmd.PersistentFlags().String("ca-bundle", "", "Certificate authorities to trust for API requests")
+	cmd.PersistentFlags().Bool("insecure-skip-tls-verify", false, "Skip API TLS certificate verification")
+	cmd.PersistentFlags().Int("max-concurrent-requests", 10, "Maximum in-flight requests per API host")
+	cmd.PersistentFlags().Float64("requests-per-second", 10, "Maximum requests per second per API host")
+	cmd.PersistentFlags().Int("search-scan-limit", DefaultSearchScanLimit, "Maximum customers a search reads")
+	cmd.PersistentFlags().Bool("search-index", false, "Rank search results with a local index")
+	cmd.PersistentFlags().Int("max-request-bytes", DefaultMaxRequestBytes, "Maximum request body size")
+	cmd.PersistentFlags().Int("max-argument-bytes", DefaultMaxArgumentBytes, "Maximum tool argument size")
+	cmd.PersistentFlags().Int("max-array-length", DefaultMaxArrayLength, "Maximum tool argument list length")
+	cmd.PersistentFlags().Int("max-result-bytes", DefaultMaxResultBytes, "Maximum tool result size")
+	cmd.PersistentFlags().String("har-file", "", "Capture API traffic to a HAR file")
+	cmd.PersistentFlags().String("audit-log", "", "Record mutating tool calls to a file")
+	cmd.PersistentFlags().StringArray("redact-pattern", nil, "Mask text matching this pattern")
+	cmd.PersistentFlags().Bool("hedge-reads", false, "Hedge slow reads")
+	cmd.PersistentFlags().StringSlice("hedge-classes", nil, "Endpoint classes to hedge")
+	cmd.PersistentFlags().Bool("dev", false, "Enable developer mode")
+	cmd.PersistentFlags().String("locale", "", "Locale for formatted numbers in reports")
+	cmd.PersistentFlags().String("data-dir", "", "Directory for exports and server-side state")
+	cmd.PersistentFlags().Bool("disk-cache", false, "Cache published releases on disk")
+	cmd.PersistentFlags().Duration("resource-poll-interval", DefaultResourcePollInterval,
+		"How often subscribed resources are checked for changes")
+	cmd.PersistentFlags().String("cache-dir", "", "Directory for the disk cache")
+	cmd.PersistentFlags().Bool("read-only", false, "Disable tools that modify the vendor account")
+	cmd.PersistentFlags().Bool("dry-run", false, "Preview changes instead of making them")
+	cmd.PersistentFlags().Bool("mock", false, "Answer API requests from fixtures")
+	cmd.PersistentFlags().String("mock-fixtures", "", "Directory of fixtures used in mock mode")
+	cmd.PersistentFlags().String("record-fixtures", "", "Directory to record fixtures to")
+	cmd.PersistentFlags().Bool("cluster-commands", false, "Expose run_cluster_command")
+	cmd.PersistentFlags().Float64("slo-availability", 0.99, "Fraction of API requests that should succeed")
+	cmd.PersistentFlags().Duration("slo-latency", 2*time.Second, "Latency API requests should complete within")
+	cmd.PersistentFlags().Float64("slo-latency-target", 0.95, "Fraction of API requests within the latency")
+	cmd.PersistentFlags().StringSlice("enabled-tools", nil, "Expose only these tools or tool categories")
+	cmd.PersistentFlags().StringSlice("disable-tool", nil, "Do not expose this tool or tool category")
+	cmd.PersistentFlags().String("transport", "stdio", "Transport for MCP clients (stdio, http)")
+	cmd.PersistentFlags().String("listen", "127.0.0.1:8080", "Address for the HTTP transport")
+	cmd.PersistentFlags().String("http-auth-token", "", "Bearer token HTTP transport clients must send")
+	cmd.PersistentFlags().String("access-log-file", "", "File for HTTP access logs")
+	cmd.PersistentFlags().Float64("access-log-sample-rate", 1, "Fraction of successful requests to log")
+	cmd.PersistentFlags().Bool("metrics-endpoint", false, "Serve metrics at /metrics")
+	cmd.PersistentFlags().String("otlp-endpoint", "", "OTLP/HTTP collector to export traces to")
+	cmd.PersistentFlags().Duration("tool-timeout", DefaultToolTimeout, "Time limit for each tool call")
+	cmd.PersistentFlags().StringSlice("tool-timeouts", nil, "Time limits for individual tools")
+	cmd.PersistentFlags().String("tls-cert", "", "TLS certificate file")
+	cmd.PersistentFlags().String("tls-key", "", "TLS key file")
+	cmd.PersistentFlags().StringSlice("tls-autocert-domains", nil, "Domains to obtain certificates for")
+	cmd.PersistentFlags().StringSlice("allowed-origins", nil, "Browser origins allowed to connect")
+	cmd.PersistentFlags().String("config", "", "Config file")
+	cmd.PersistentFlags().String("profile", "", "Config file profile")
 
 	return cmd
 }
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// credentialCommandTimeout bounds how long a token command or keychain lookup may run,
+// so an interactive prompt that never gets an answer does not hang startup
+const credentialCommandTimeout = 30 * time.Second
+
+// tokenSources returns the names of the configured API token sources other than the
+// token itself, using the flag names so errors point at what to change
+func (c *Config) tokenSources() []string {
+	var sources []string
+	if c.APITokenFile != "" {
+		sources = append(sources, "--api-token-file")
+	}
+	if c.APITokenCommand != "" {
+		sources = append(sources, "--api-token-command")
+	}
+	if c.APITokenKeychain != "" {
+		sources = append(sources, "--api-token-keychain")
+	}
+	return sources
+}
+
+// resolveAPIToken reads the API token from the configured credential source when no
+// token was given directly. Keeping the token in a file, the OS keychain, or a secret
+// manager avoids exposing it in environment variables and MCP client configuration.
+func (c *Config) resolveAPIToken() error {
+	if c.APIToken != "" {
+		return nil
+	}
+
+	sources := c.tokenSources()
+	switch {
+	case len(sources) == 0:
+		return nil
+	case len(sources) > 1:
+		return fmt.Errorf("only one API token source may be set, got %s", strings.Join(sources, ", "))
+	}
+
+	var token string
+	var err error
+	switch {
+	case c.APITokenFile != "":
+		token, err = readTokenFile(c.APITokenFile)
+	case c.APITokenCommand != "":
+		token, err = runTokenCommand(shellCommand(c.APITokenCommand))
+	default:
+		token, err = runTokenCommand(keychainCommand(c.APITokenKeychain))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read API token from %s: %w", sources[0], err)
+	}
+	if token == "" {
+		return fmt.Errorf("failed to read API token from %s: the token is empty", sources[0])
+	}
+
+	c.APIToken = token
+	return nil
+}
+
+// readTokenFile reads a token from a file, ignoring surrounding whitespace
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// runTokenCommand runs a command and returns its trimmed standard output as the token.
+// Standard error is included in the error when the command fails.
+func runTokenCommand(args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("%w: %s", err, detail)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// shellCommand returns the arguments that run a command line through the system shell,
+// so token commands can use quoting and pipes
+func shellCommand(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C", command}
+	}
+	return []string{"sh", "-c", command}
+}
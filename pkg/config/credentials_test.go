@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad_TokenSources(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		args        []string
+		wantToken   string
+		errContains string
+	}{
+		{
+			name:      "token file",
+			args:      []string{"--api-token-file", tokenFile},
+			wantToken: "file-token",
+		},
+		{
+			name:      "token command",
+			envVars:   map[string]string{"REPLICATED_API_TOKEN_COMMAND": "echo command-token"},
+			wantToken: "command-token",
+		},
+		{
+			name:      "direct token takes precedence",
+			envVars:   map[string]string{"REPLICATED_API_TOKEN": "env-token"},
+			args:      []string{"--api-token-command", "echo command-token"},
+			wantToken: "env-token",
+		},
+		{
+			name:        "failing command",
+			args:        []string{"--api-token-command", "echo locked >&2; exit 1"},
+			errContains: "locked",
+		},
+		{
+			name:        "empty command output",
+			args:        []string{"--api-token-command", "true"},
+			errContains: "the token is empty",
+		},
+		{
+			name:        "missing token file",
+			args:        []string{"--api-token-file", filepath.Join(t.TempDir(), "missing")},
+			errContains: "--api-token-file",
+		},
+		{
+			name:        "multiple sources",
+			args:        []string{"--api-token-file", tokenFile, "--api-token-keychain", "replicated-mcp-server"},
+			errContains: "only one API token source",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if got.APIToken != tt.wantToken {
+				t.Errorf("Load() APIToken = %s, want %s", got.APIToken, tt.wantToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// DefaultConfigFile is the config file location relative to the user's configuration
+// directory ($XDG_CONFIG_HOME, or ~/.config when it is unset)
+const DefaultConfigFile = "replicated-mcp/config.yaml"
+
+// File is the layout of the config file. Top-level settings apply to every profile;
+// the selected profile's settings are layered on top of them. Profiles let one config
+// file hold different tokens and endpoints, such as one per vendor account.
+//
+//	log_level: info
+//	default_profile: acme
+//	profiles:
+//	  acme:
+//	    api_token: ...
+//	  globex:
+//	    api_token: ...
+//	    endpoint: https://replicated-proxy.globex.example
+type File struct {
+	Settings       `yaml:",inline"`
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]Settings `yaml:"profiles"`
+}
+
+// Settings holds the options that can be set in the config file. Omitted options keep
+// their defaults and can still be overridden by environment variables and flags.
+type Settings struct {
+	APIToken              string         `yaml:"api_token"`
+	APITokenFile          string         `yaml:"api_token_file"`
+	APITokenCommand       string         `yaml:"api_token_command"`
+	APITokenKeychain      string         `yaml:"api_token_keychain"`
+	LogLevel              string         `yaml:"log_level"`
+	Timeout               int            `yaml:"timeout"`
+	Endpoint              string         `yaml:"endpoint"`
+	FallbackEndpoint      string         `yaml:"fallback_endpoint"`
+	Proxy                 string         `yaml:"proxy"`
+	CABundle              string         `yaml:"ca_bundle"`
+	InsecureSkipTLSVerify *bool          `yaml:"insecure_skip_tls_verify"`
+	ClientCertFile        string         `yaml:"client_cert"`
+	ClientKeyFile         string         `yaml:"client_key"`
+	App                   string         `yaml:"app"`
+	MaxConcurrentRequests *int           `yaml:"max_concurrent_requests"`
+	RequestsPerSecond     *float64       `yaml:"requests_per_second"`
+	SearchScanLimit       *int           `yaml:"search_scan_limit"`
+	SearchIndex           *bool          `yaml:"search_index"`
+	MaxRequestBytes       *int           `yaml:"max_request_bytes"`
+	MaxArgumentBytes      *int           `yaml:"max_argument_bytes"`
+	MaxArrayLength        *int           `yaml:"max_array_length"`
+	MaxResultBytes        *int           `yaml:"max_result_bytes"`
+	SLOAvailability       *float64       `yaml:"slo_availability"`
+	SLOLatency            *time.Duration `yaml:"slo_latency"`
+	SLOLatencyTarget      *float64       `yaml:"slo_latency_target"`
+	HARFile               string         `yaml:"har_file"`
+	AuditLogFile          string         `yaml:"audit_log"`
+	RedactPatterns        []string       `yaml:"redact_patterns"`
+	HedgeReads            *bool          `yaml:"hedge_reads"`
+	HedgeClasses          []string       `yaml:"hedge_classes"`
+	DevMode               *bool          `yaml:"dev_mode"`
+	Locale                string         `yaml:"locale"`
+	DataDir               string         `yaml:"data_dir"`
+	DiskCache             *bool          `yaml:"disk_cache"`
+	CacheDir              string         `yaml:"cache_dir"`
+	ResourcePollInterval  *time.Duration `yaml:"resource_poll_interval"`
+	ReadOnly              *bool          `yaml:"read_only"`
+	DryRun                *bool          `yaml:"dry_run"`
+	Mock                  *bool          `yaml:"mock"`
+	MockFixtures          string         `yaml:"mock_fixtures"`
+	RecordFixtures        string         `yaml:"record_fixtures"`
+	ClusterCommands       *bool          `yaml:"cluster_commands"`
+	EnabledTools          []string       `yaml:"enabled_tools"`
+	DisabledTools         []string       `yaml:"disabled_tools"`
+	ToolTimeout           *time.Duration `yaml:"tool_timeout"`
+	Transport             string         `yaml:"transport"`
+	ListenAddress         string         `yaml:"listen"`
+	HTTPAuthToken         string         `yaml:"http_auth_token"`
+	AccessLogFile         string         `yaml:"access_log_file"`
+	AccessLogSampleRate   *float64       `yaml:"access_log_sample_rate"`
+	MetricsEndpoint       *bool          `yaml:"metrics_endpoint"`
+	OTLPEndpoint          string         `yaml:"otlp_endpoint"`
+	TLSCertFile           string         `yaml:"tls_cert"`
+	TLSKeyFile            string         `yaml:"tls_key"`
+	TLSAutocertDomains    []string       `yaml:"tls_autocert_domains"`
+	AllowedOrigins        []string       `yaml:"allowed_origins"`
+
+	APIHeaders      map[string]string                 `yaml:"api_headers"`
+	ToolTimeouts    map[string]time.Duration          `yaml:"tool_timeouts"`
+	ChannelPolicies map[string]models.PromotionPolicy `yaml:"channel_policies"`
+}
+
+// apply copies the options that are set onto the configuration
+func (s Settings) apply(c *Config) {
+	s.applyConnection(c)
+	s.applyAPIGateway(c)
+	s.applyObjectives(c)
+	s.applyFeatures(c)
+	s.applyTransport(c)
+}
+
+// applyConnection copies the API connection options that are set
+func (s Settings) applyConnection(c *Config) {
+	if s.APIToken != "" {
+		c.APIToken = s.APIToken
+	}
+	if s.APITokenFile != "" {
+		c.APITokenFile = s.APITokenFile
+	}
+	if s.APITokenCommand != "" {
+		c.APITokenCommand = s.APITokenCommand
+	}
+	if s.APITokenKeychain != "" {
+		c.APITokenKeychain = s.APITokenKeychain
+	}
+	if s.LogLevel != "" {
+		c.LogLevel = s.LogLevel
+	}
+	if s.Timeout != 0 {
+		c.Timeout = time.Duration(s.Timeout) * time.Second
+	}
+	if s.Endpoint != "" {
+		c.Endpoint = s.Endpoint
+	}
+	if s.App != "" {
+		c.App = s.App
+	}
+	if s.FallbackEndpoint != "" {
+		c.FallbackEndpoint = s.FallbackEndpoint
+	}
+	if s.Proxy != "" {
+		c.Proxy = s.Proxy
+	}
+	if s.CABundle != "" {
+		c.CABundle = s.CABundle
+	}
+	if s.InsecureSkipTLSVerify != nil {
+		c.InsecureSkipTLSVerify = *s.InsecureSkipTLSVerify
+	}
+	if s.MaxConcurrentRequests != nil {
+		c.MaxConcurrentRequests = *s.MaxConcurrentRequests
+	}
+	if s.RequestsPerSecond != nil {
+		c.RequestsPerSecond = *s.RequestsPerSecond
+	}
+	if s.SearchScanLimit != nil {
+		c.SearchScanLimit = *s.SearchScanLimit
+	}
+	if s.SearchIndex != nil {
+		c.SearchIndex = *s.SearchIndex
+	}
+	if s.MaxRequestBytes != nil {
+		c.MaxRequestBytes = *s.MaxRequestBytes
+	}
+	if s.MaxArgumentBytes != nil {
+		c.MaxArgumentBytes = *s.MaxArgumentBytes
+	}
+	if s.MaxArrayLength != nil {
+		c.MaxArrayLength = *s.MaxArrayLength
+	}
+	if s.MaxResultBytes != nil {
+		c.MaxResultBytes = *s.MaxResultBytes
+	}
+	if s.HARFile != "" {
+		c.HARFile = s.HARFile
+	}
+	if s.AuditLogFile != "" {
+		c.AuditLogFile = s.AuditLogFile
+	}
+	if s.RedactPatterns != nil {
+		c.RedactPatterns = normalizeList(s.RedactPatterns)
+	}
+	if s.HedgeReads != nil {
+		c.HedgeReads = *s.HedgeReads
+	}
+	if s.HedgeClasses != nil {
+		c.HedgeClasses = normalizeList(s.HedgeClasses)
+	}
+}
+
+// applyAPIGateway copies the client certificate and extra API header options that are set
+func (s Settings) applyAPIGateway(c *Config) {
+	if s.ClientCertFile != "" {
+		c.ClientCertFile = s.ClientCertFile
+	}
+	if s.ClientKeyFile != "" {
+		c.ClientKeyFile = s.ClientKeyFile
+	}
+	if len(s.APIHeaders) > 0 && c.APIHeaders == nil {
+		c.APIHeaders = make(map[string]string, len(s.APIHeaders))
+	}
+	// A profile's value for a header replaces the top-level value for that header
+	maps.Copy(c.APIHeaders, s.APIHeaders)
+}
+
+// applyObjectives copies the Vendor Portal API objectives that are set
+func (s Settings) applyObjectives(c *Config) {
+	if s.SLOAvailability != nil {
+		c.SLOAvailability = *s.SLOAvailability
+	}
+	if s.SLOLatency != nil {
+		c.SLOLatency = *s.SLOLatency
+	}
+	if s.SLOLatencyTarget != nil {
+		c.SLOLatencyTarget = *s.SLOLatencyTarget
+	}
+}
+
+// applyFeatures copies the server feature and tool selection options that are set
+func (s Settings) applyFeatures(c *Config) {
+	if s.DevMode != nil {
+		c.DevMode = *s.DevMode
+	}
+	if s.Locale != "" {
+		c.Locale = s.Locale
+	}
+	if s.DataDir != "" {
+		c.DataDir = s.DataDir
+	}
+	if s.DiskCache != nil {
+		c.DiskCache = *s.DiskCache
+	}
+	if s.CacheDir != "" {
+		c.CacheDir = s.CacheDir
+	}
+	if s.ResourcePollInterval != nil {
+		c.ResourcePollInterval = *s.ResourcePollInterval
+	}
+	if s.ReadOnly != nil {
+		c.ReadOnly = *s.ReadOnly
+	}
+	if s.DryRun != nil {
+		c.DryRun = *s.DryRun
+	}
+	if s.Mock != nil {
+		c.Mock = *s.Mock
+	}
+	if s.MockFixtures != "" {
+		c.MockFixtures = s.MockFixtures
+	}
+	if s.RecordFixtures != "" {
+		c.RecordFixtures = s.RecordFixtures
+	}
+	if s.ClusterCommands != nil {
+		c.ClusterCommands = *s.ClusterCommands
+	}
+	if s.EnabledTools != nil {
+		c.EnabledTools = normalizeList(s.EnabledTools)
+	}
+	if s.DisabledTools != nil {
+		c.DisabledTools = normalizeList(s.DisabledTools)
+	}
+	if s.ToolTimeout != nil {
+		c.ToolTimeout = *s.ToolTimeout
+	}
+	// A profile's time limit for a tool replaces the top-level limit for that tool
+	c.setToolTimeouts(s.ToolTimeouts)
+	if len(s.ChannelPolicies) > 0 && c.ChannelPolicies == nil {
+		c.ChannelPolicies = make(map[string]models.PromotionPolicy, len(s.ChannelPolicies))
+	}
+	// A profile's policy for a channel replaces the top-level policy for that channel
+	maps.Copy(c.ChannelPolicies, s.ChannelPolicies)
+}
+
+// applyTransport copies the transport and access log options that are set
+func (s Settings) applyTransport(c *Config) {
+	if s.Transport != "" {
+		c.Transport = s.Transport
+	}
+	if s.ListenAddress != "" {
+		c.ListenAddress = s.ListenAddress
+	}
+	if s.HTTPAuthToken != "" {
+		c.HTTPAuthToken = s.HTTPAuthToken
+	}
+	if s.AccessLogFile != "" {
+		c.AccessLogFile = s.AccessLogFile
+	}
+	if s.AccessLogSampleRate != nil {
+		c.AccessLogSampleRate = *s.AccessLogSampleRate
+	}
+	if s.MetricsEndpoint != nil {
+		c.MetricsEndpoint = *s.MetricsEndpoint
+	}
+	if s.OTLPEndpoint != "" {
+		c.OTLPEndpoint = s.OTLPEndpoint
+	}
+	if s.TLSCertFile != "" {
+		c.TLSCertFile = s.TLSCertFile
+	}
+	if s.TLSKeyFile != "" {
+		c.TLSKeyFile = s.TLSKeyFile
+	}
+	if s.TLSAutocertDomains != nil {
+		c.TLSAutocertDomains = normalizeList(s.TLSAutocertDomains)
+	}
+	if s.AllowedOrigins != nil {
+		c.AllowedOrigins = normalizeList(s.AllowedOrigins)
+	}
+}
+
+// loadFromFile loads configuration from the config file and the selected profile.
+// A missing file is only an error when the file or a profile was explicitly requested.
+func (c *Config) loadFromFile(flags *pflag.FlagSet) error {
+	path, explicit, err := configFilePath(flags)
+	if err != nil {
+		return err
+	}
+	profile, err := requestedProfile(flags)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicit && profile == "" {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file, err := parseFile(data)
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	file.Settings.apply(c)
+
+	if profile == "" {
+		profile = file.DefaultProfile
+	}
+	if profile == "" {
+		return nil
+	}
+
+	settings, ok := file.Profiles[profile]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found in %s. Available profiles are: %s",
+			profile, path, strings.Join(profileNames(file), ", "))
+	}
+	settings.apply(c)
+	c.Profile = profile
+
+	return nil
+}
+
+// parseFile decodes a config file, rejecting unknown options so typos are not silently ignored
+func parseFile(data []byte) (*File, error) {
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// configFilePath returns the config file path, from the --config flag, the
+// REPLICATED_CONFIG_FILE environment variable, or the default location, and whether
+// it was set explicitly
+func configFilePath(flags *pflag.FlagSet) (string, bool, error) {
+	if flags.Changed("config") {
+		path, err := flags.GetString("config")
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get config flag: %w", err)
+		}
+		return path, true, nil
+	}
+	if path := os.Getenv("REPLICATED_CONFIG_FILE"); path != "" {
+		return path, true, nil
+	}
+
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false, fmt.Errorf("failed to locate home directory for config file: %w", err)
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, DefaultConfigFile), false, nil
+}
+
+// requestedProfile returns the profile selected by the --profile flag or the
+// REPLICATED_PROFILE environment variable, or "" if neither is set
+func requestedProfile(flags *pflag.FlagSet) (string, error) {
+	if flags.Changed("profile") {
+		profile, err := flags.GetString("profile")
+		if err != nil {
+			return "", fmt.Errorf("failed to get profile flag: %w", err)
+		}
+		return profile, nil
+	}
+	return os.Getenv("REPLICATED_PROFILE"), nil
+}
+
+// profileNames returns the names of the profiles defined in a config file, sorted
+func profileNames(file *File) []string {
+	names := make([]string, 0, len(file.Profiles))
+	for name := range file.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
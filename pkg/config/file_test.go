@@ -0,0 +1,259 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+const testConfigFile = `log_level: info
+timeout: 60
+default_profile: acme
+profiles:
+  acme:
+    api_token: acme-token
+    read_only: true
+    app: acme-app
+  globex:
+    api_token: globex-token
+    endpoint: https://replicated-proxy.globex.example
+    max_concurrent_requests: 0
+    requests_per_second: 2
+    dry_run: true
+    cluster_commands: true
+    disabled_tools: [set_customer_entitlement]
+`
+
+func TestLoad_ConfigFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		envVars     map[string]string
+		args        []string
+		want        *Config
+		errContains string
+	}{
+		{
+			name: "default profile",
+			file: testConfigFile,
+			want: &Config{
+				APIToken:              "acme-token",
+				LogLevel:              "info",
+				Timeout:               60 * time.Second,
+				ReadOnly:              true,
+				App:                   "acme-app",
+				MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+				RequestsPerSecond:     DefaultRequestsPerSecond,
+				Profile:               "acme",
+			},
+		},
+		{
+			name: "profile from flag",
+			file: testConfigFile,
+			args: []string{"--profile", "globex"},
+			want: &Config{
+				APIToken:              "globex-token",
+				LogLevel:              "info",
+				Timeout:               60 * time.Second,
+				Endpoint:              "https://replicated-proxy.globex.example",
+				MaxConcurrentRequests: 0,
+				RequestsPerSecond:     2,
+				DisabledTools:         []string{"set_customer_entitlement"},
+				DryRun:                true,
+				ClusterCommands:       true,
+				Profile:               "globex",
+			},
+		},
+		{
+			name:    "environment overrides profile",
+			file:    testConfigFile,
+			envVars: map[string]string{"REPLICATED_PROFILE": "globex", "REPLICATED_API_TOKEN": "env-token"},
+			args:    []string{"--log-level", "debug"},
+			want: &Config{
+				APIToken:              "env-token",
+				LogLevel:              "debug",
+				Timeout:               60 * time.Second,
+				Endpoint:              "https://replicated-proxy.globex.example",
+				MaxConcurrentRequests: 0,
+				RequestsPerSecond:     2,
+				DisabledTools:         []string{"set_customer_entitlement"},
+				DryRun:                true,
+				ClusterCommands:       true,
+				Profile:               "globex",
+			},
+		},
+		{
+			name:        "unknown profile",
+			file:        testConfigFile,
+			args:        []string{"--profile", "initech"},
+			errContains: "profile 'initech' not found",
+		},
+		{
+			name:        "unknown option",
+			file:        "api_tokn: typo\n",
+			errContains: "api_tokn",
+		},
+		{
+			name:        "profile without config file",
+			args:        []string{"--profile", "acme"},
+			errContains: "failed to read config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+
+			configDir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", configDir)
+			if tt.file != "" {
+				path := filepath.Join(configDir, DefaultConfigFile)
+				if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+					t.Fatalf("Failed to create config directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("Failed to write config file: %v", err)
+				}
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cmd := createTestCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := Load(cmd)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Load() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+
+			if got.APIToken != tt.want.APIToken || got.LogLevel != tt.want.LogLevel ||
+				got.Timeout != tt.want.Timeout || got.Endpoint != tt.want.Endpoint {
+				t.Errorf("Load() = %v, want %v", got, tt.want)
+			}
+			if got.ReadOnly != tt.want.ReadOnly {
+				t.Errorf("Load() ReadOnly = %v, want %v", got.ReadOnly, tt.want.ReadOnly)
+			}
+			if got.DryRun != tt.want.DryRun {
+				t.Errorf("Load() DryRun = %v, want %v", got.DryRun, tt.want.DryRun)
+			}
+			if got.ClusterCommands != tt.want.ClusterCommands {
+				t.Errorf("Load() ClusterCommands = %v, want %v", got.ClusterCommands, tt.want.ClusterCommands)
+			}
+			if got.App != tt.want.App {
+				t.Errorf("Load() App = %v, want %v", got.App, tt.want.App)
+			}
+			if got.MaxConcurrentRequests != tt.want.MaxConcurrentRequests {
+				t.Errorf("Load() MaxConcurrentRequests = %v, want %v",
+					got.MaxConcurrentRequests, tt.want.MaxConcurrentRequests)
+			}
+			if got.RequestsPerSecond != tt.want.RequestsPerSecond {
+				t.Errorf("Load() RequestsPerSecond = %v, want %v", got.RequestsPerSecond, tt.want.RequestsPerSecond)
+			}
+			if !slices.Equal(got.DisabledTools, tt.want.DisabledTools) {
+				t.Errorf("Load() DisabledTools = %v, want %v", got.DisabledTools, tt.want.DisabledTools)
+			}
+			if got.Profile != tt.want.Profile {
+				t.Errorf("Load() Profile = %v, want %v", got.Profile, tt.want.Profile)
+			}
+		})
+	}
+}
+
+func TestChannelPolicies(t *testing.T) {
+	file, err := parseFile([]byte(`channel_policies:
+  stable:
+    require_semver: true
+    require_release_notes: true
+  beta:
+    require_semver: true
+profiles:
+  acme:
+    channel_policies:
+      stable:
+        approval_label: qa-approved
+`))
+	if err != nil {
+		t.Fatalf("parseFile() unexpected error: %v", err)
+	}
+
+	config := &Config{}
+	file.Settings.apply(config)
+	file.Profiles["acme"].apply(config)
+
+	tests := []struct {
+		name    string
+		channel models.Channel
+		want    models.PromotionPolicy
+		wantOK  bool
+	}{
+		{
+			name:    "profile policy replaces the top-level policy",
+			channel: models.Channel{ID: "channel-1", Name: "Stable", ChannelSlug: "stable"},
+			want:    models.PromotionPolicy{ApprovalLabel: "qa-approved"},
+			wantOK:  true,
+		},
+		{
+			name:    "matched by name ignoring case",
+			channel: models.Channel{ID: "channel-2", Name: "BETA"},
+			want:    models.PromotionPolicy{RequireSemver: true},
+			wantOK:  true,
+		},
+		{
+			name:    "channel without a policy",
+			channel: models.Channel{ID: "channel-3", Name: "Unstable", ChannelSlug: "unstable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := config.PromotionPolicy(&tt.channel)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("PromotionPolicy() = %+v, %t, want %+v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAPIGatewaySettings(t *testing.T) {
+	file, err := parseFile([]byte(`client_cert: /etc/gateway/client.pem
+client_key: /etc/gateway/client-key.pem
+api_headers:
+  X-Gateway-Key: top-level-key
+  X-Tenant: vendor
+profiles:
+  acme:
+    api_headers:
+      X-Gateway-Key: acme-key
+`))
+	if err != nil {
+		t.Fatalf("parseFile() unexpected error: %v", err)
+	}
+
+	config := &Config{}
+	file.Settings.apply(config)
+	file.Profiles["acme"].apply(config)
+
+	if config.ClientCertFile != "/etc/gateway/client.pem" || config.ClientKeyFile != "/etc/gateway/client-key.pem" {
+		t.Errorf("Expected the client certificate and key, got %s and %s", config.ClientCertFile, config.ClientKeyFile)
+	}
+	want := map[string]string{"X-Gateway-Key": "acme-key", "X-Tenant": "vendor"}
+	if !maps.Equal(config.APIHeaders, want) {
+		t.Errorf("Expected the profile's header to replace the top-level one, got %v", config.APIHeaders)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+
+package config
+
+// keychainCommand returns the command that reads a generic password stored under the
+// given service name from the macOS login keychain
+func keychainCommand(service string) []string {
+	return []string{"security", "find-generic-password", "-s", service, "-w"}
+}
@@ -0,0 +1,9 @@
+//go:build !darwin && !windows
+
+package config
+
+// keychainCommand returns the command that reads a secret stored under the given service
+// name from the Secret Service keyring (GNOME Keyring or KWallet) using secret-tool
+func keychainCommand(service string) []string {
+	return []string{"secret-tool", "lookup", "service", service}
+}
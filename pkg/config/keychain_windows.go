@@ -0,0 +1,14 @@
+//go:build windows
+
+package config
+
+import "strings"
+
+// keychainCommand returns the command that reads a generic credential stored under the
+// given target name from the Windows Credential Manager, using the CredentialManager
+// PowerShell module
+func keychainCommand(service string) []string {
+	target := strings.ReplaceAll(service, "'", "''")
+	return []string{"powershell", "-NoProfile", "-Command",
+		"(Get-StoredCredential -Target '" + target + "').GetNetworkCredential().Password"}
+}
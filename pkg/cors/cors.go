@@ -0,0 +1,90 @@
+// Package cors validates the Origin of requests to the server's HTTP transport and answers
+// CORS preflights, so browser-based MCP clients can connect from allowed origins. Origins are
+// denied by default: a browser request from a page not on the allow list is rejected, which
+// also stops malicious pages from reaching a server bound to localhost through DNS rebinding.
+// Requests without an Origin header come from non-browser clients and are not affected.
+package cors
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AnyOrigin allows requests from every origin when it is on the allow list
+const AnyOrigin = "*"
+
+// preflightMaxAge is how long, in seconds, browsers may cache a preflight response
+const preflightMaxAge = 600
+
+// Methods and headers browser clients may use with the streamable HTTP transport
+var (
+	allowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodOptions}
+	allowedHeaders = []string{
+		"Accept", "Authorization", "Content-Type", "Last-Event-ID", "Mcp-Protocol-Version", "Mcp-Session-Id",
+	}
+	// exposedHeaders lets browser clients read the session ID that starts a session
+	exposedHeaders = []string{"Mcp-Session-Id"}
+)
+
+// Policy decides which origins may call the HTTP transport
+type Policy struct {
+	origins []string
+	any     bool
+}
+
+// New returns a policy allowing the given origins, such as https://agent.example.com. An
+// empty list allows no browser origins; AnyOrigin allows them all.
+func New(origins []string) *Policy {
+	policy := &Policy{}
+	for _, origin := range origins {
+		if origin == AnyOrigin {
+			policy.any = true
+			continue
+		}
+		policy.origins = append(policy.origins, Normalize(origin))
+	}
+	return policy
+}
+
+// Normalize returns an origin in the form browsers send it: lowercase, without a trailing slash
+func Normalize(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+}
+
+// Allowed reports whether requests from origin may be served
+func (p *Policy) Allowed(origin string) bool {
+	return p.any || slices.Contains(p.origins, Normalize(origin))
+}
+
+// Handler wraps next so that browser requests from origins that are not allowed are rejected
+// and preflights from allowed origins are answered
+func (p *Policy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		if !p.Allowed(origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(preflightMaxAge))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,131 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicy_Handler(t *testing.T) {
+	tests := []struct {
+		name            string
+		origins         []string
+		method          string
+		origin          string
+		preflight       bool
+		wantStatus      int
+		wantAllowOrigin string
+		wantNextCalled  bool
+	}{
+		{
+			name:           "no origin header",
+			method:         http.MethodPost,
+			wantStatus:     http.StatusOK,
+			wantNextCalled: true,
+		},
+		{
+			name:       "origins denied by default",
+			method:     http.MethodPost,
+			origin:     "https://agent.example.com",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:            "allowed origin",
+			origins:         []string{"https://agent.example.com"},
+			method:          http.MethodPost,
+			origin:          "https://agent.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://agent.example.com",
+			wantNextCalled:  true,
+		},
+		{
+			name:            "allow list entries are normalized",
+			origins:         []string{"HTTPS://Agent.Example.com/"},
+			method:          http.MethodPost,
+			origin:          "https://agent.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://agent.example.com",
+			wantNextCalled:  true,
+		},
+		{
+			name:       "other origin",
+			origins:    []string{"https://agent.example.com"},
+			method:     http.MethodPost,
+			origin:     "https://evil.example.com",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "different port",
+			origins:    []string{"http://localhost:3000"},
+			method:     http.MethodPost,
+			origin:     "http://localhost:8000",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:            "any origin",
+			origins:         []string{AnyOrigin},
+			method:          http.MethodGet,
+			origin:          "http://localhost:8000",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "http://localhost:8000",
+			wantNextCalled:  true,
+		},
+		{
+			name:            "preflight from allowed origin",
+			origins:         []string{"https://agent.example.com"},
+			method:          http.MethodOptions,
+			origin:          "https://agent.example.com",
+			preflight:       true,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://agent.example.com",
+		},
+		{
+			name:       "preflight from other origin",
+			origins:    []string{"https://agent.example.com"},
+			method:     http.MethodOptions,
+			origin:     "https://evil.example.com",
+			preflight:  true,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nextCalled := false
+			handler := New(tt.origins).Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				nextCalled = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			request := httptest.NewRequest(tt.method, "/mcp", nil)
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+
+			if response.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, response.Code)
+			}
+			if got := response.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.wantAllowOrigin, got)
+			}
+			if nextCalled != tt.wantNextCalled {
+				t.Errorf("Expected next handler called = %t, got %t", tt.wantNextCalled, nextCalled)
+			}
+			if tt.preflight && tt.wantStatus == http.StatusNoContent {
+				if response.Header().Get("Access-Control-Allow-Headers") == "" {
+					t.Error("Expected the preflight response to list the allowed headers")
+				}
+			}
+			if tt.wantNextCalled && tt.origin != "" {
+				if got := response.Header().Get("Access-Control-Expose-Headers"); got != "Mcp-Session-Id" {
+					t.Errorf("Expected the session header to be exposed, got %q", got)
+				}
+			}
+		})
+	}
+}
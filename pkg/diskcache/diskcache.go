@@ -0,0 +1,213 @@
+// Package diskcache keeps large API responses that never change, such as the manifests of a
+// published release, in plain files so they survive server restarts and are shared between
+// sessions. Values are looked up by key, such as the URL of the request that returned them,
+// since a response's contents are only known once it is read. Contents are stored once under
+// the SHA-256 hash of their bytes, and each key refers to the hash of its contents, so
+// identical responses share one file and a damaged file is detected and dropped rather than
+// returned. When the contents grow past the size limit, the least recently used are removed.
+package diskcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBytes is the default size limit of a cache's contents
+const DefaultMaxBytes = 256 << 20
+
+// Cache layout constants
+const (
+	blobDir  = "blobs"
+	refDir   = "refs"
+	dirMode  = 0o750
+	fileMode = 0o600
+	tempGlob = ".tmp-*"
+)
+
+// Cache stores values by key in a directory. It is safe for concurrent use, and several
+// server processes may share a directory, since every file is written whole before it is
+// renamed into place.
+type Cache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	now      func() time.Time
+}
+
+// Open returns a cache that keeps its files in dir, which is created on the first write. A
+// maxBytes of zero or less means no size limit.
+func Open(dir string, maxBytes int64) *Cache {
+	return &Cache{dir: dir, maxBytes: maxBytes, now: time.Now}
+}
+
+// Dir returns the directory holding the cache's files
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// hash returns the hex-encoded SHA-256 hash of data
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the value stored under key. Missing values, and values whose contents no
+// longer match their hash, are reported as not found.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refPath := filepath.Join(c.dir, refDir, hash([]byte(key)))
+	ref, err := os.ReadFile(filepath.Clean(refPath))
+	if err != nil {
+		return nil, false
+	}
+	contentHash := strings.TrimSpace(string(ref))
+	if decoded, err := hex.DecodeString(contentHash); err != nil || len(decoded) != sha256.Size {
+		_ = os.Remove(refPath)
+		return nil, false
+	}
+
+	blobPath := filepath.Join(c.dir, blobDir, contentHash)
+	data, err := os.ReadFile(filepath.Clean(blobPath))
+	if err != nil || hash(data) != contentHash {
+		_ = os.Remove(refPath)
+		if err == nil {
+			_ = os.Remove(blobPath)
+		}
+		return nil, false
+	}
+
+	// The modification time records use, so pruning removes the least recently used first
+	now := c.now()
+	_ = os.Chtimes(blobPath, now, now)
+	return data, true
+}
+
+// Put stores value under key, replacing any earlier value, and prunes the cache back to its
+// size limit. Values larger than the limit are not stored.
+func (c *Cache) Put(key string, value []byte) error {
+	if c.maxBytes > 0 && int64(len(value)) > c.maxBytes {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	contentHash := hash(value)
+	blobPath := filepath.Join(c.dir, blobDir, contentHash)
+	if _, err := os.Stat(blobPath); err != nil {
+		if err := writeFile(blobPath, value); err != nil {
+			return err
+		}
+	}
+	now := c.now()
+	_ = os.Chtimes(blobPath, now, now)
+
+	if err := writeFile(filepath.Join(c.dir, refDir, hash([]byte(key))), []byte(contentHash)); err != nil {
+		return err
+	}
+	return c.prune()
+}
+
+// writeFile writes data to a temporary file beside path and renames it into place, so
+// readers never see a partly written file
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, tempGlob)
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	_, writeErr := file.Write(data)
+	closeErr := file.Close()
+	if err := errors.Join(writeErr, closeErr, os.Chmod(file.Name(), fileMode)); err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// prune removes the least recently used contents until the cache fits its size limit, then
+// the keys that referred to removed contents
+func (c *Cache) prune() error {
+	if c.maxBytes <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(c.dir, blobDir))
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var blobs []fs.FileInfo
+	var total int64
+	for _, entry := range entries {
+		// Temporary files may still be being written by another process
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		blobs = append(blobs, info)
+		total += info.Size()
+	}
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	slices.SortFunc(blobs, func(a, b fs.FileInfo) int { return a.ModTime().Compare(b.ModTime()) })
+	for _, blob := range blobs {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, blobDir, blob.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to prune cache: %w", err)
+		}
+		total -= blob.Size()
+	}
+	return c.pruneRefs()
+}
+
+// pruneRefs removes the keys whose contents are gone, whether pruned here or by another
+// process sharing the directory
+func (c *Cache) pruneRefs() error {
+	entries, err := os.ReadDir(filepath.Join(c.dir, refDir))
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		refPath := filepath.Join(c.dir, refDir, entry.Name())
+		ref, err := os.ReadFile(filepath.Clean(refPath))
+		if err != nil {
+			continue
+		}
+		contentHash := strings.TrimSpace(string(ref))
+		if _, err := os.Stat(filepath.Join(c.dir, blobDir, contentHash)); !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := os.Remove(refPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to prune cache: %w", err)
+		}
+	}
+	return nil
+}
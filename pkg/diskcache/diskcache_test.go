@@ -0,0 +1,151 @@
+package diskcache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCache returns a cache in a temporary directory with a settable clock
+func newTestCache(t *testing.T, maxBytes int64) (*Cache, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := Open(t.TempDir(), maxBytes)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestCache_PutGet(t *testing.T) {
+	cache, _ := newTestCache(t, 0)
+
+	if _, found := cache.Get("release/1"); found {
+		t.Fatal("Expected an empty cache to miss")
+	}
+
+	manifest := []byte("apiVersion: kots.io/v1beta1\nkind: Application\n")
+	if err := cache.Put("release/1", manifest); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if err := cache.Put("release/2", manifest); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"release/1", "release/2"} {
+		got, found := cache.Get(key)
+		if !found || !bytes.Equal(got, manifest) {
+			t.Errorf("Get(%q) = %q, %t, want the manifest", key, got, found)
+		}
+	}
+
+	// Identical contents are stored once
+	blobs, err := os.ReadDir(filepath.Join(cache.Dir(), blobDir))
+	if err != nil || len(blobs) != 1 {
+		t.Errorf("Expected one stored copy of identical contents, got %d (%v)", len(blobs), err)
+	}
+
+	// A value survives reopening the directory, as it would a server restart
+	reopened := Open(cache.Dir(), 0)
+	if got, found := reopened.Get("release/1"); !found || !bytes.Equal(got, manifest) {
+		t.Errorf("Expected the value after reopening, got %q, %t", got, found)
+	}
+}
+
+func TestCache_Damaged(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(t *testing.T, cache *Cache)
+	}{
+		{
+			name: "changed contents",
+			damage: func(t *testing.T, cache *Cache) {
+				blob := filepath.Join(cache.Dir(), blobDir, hash([]byte("value")))
+				if err := os.WriteFile(blob, []byte("tampered"), fileMode); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "removed contents",
+			damage: func(t *testing.T, cache *Cache) {
+				if err := os.Remove(filepath.Join(cache.Dir(), blobDir, hash([]byte("value")))); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "reference outside the cache",
+			damage: func(t *testing.T, cache *Cache) {
+				ref := filepath.Join(cache.Dir(), refDir, hash([]byte("key")))
+				if err := os.WriteFile(ref, []byte("../../etc/passwd"), fileMode); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, _ := newTestCache(t, 0)
+			if err := cache.Put("key", []byte("value")); err != nil {
+				t.Fatalf("Put() unexpected error: %v", err)
+			}
+			tt.damage(t, cache)
+
+			if got, found := cache.Get("key"); found {
+				t.Errorf("Expected damaged value to miss, got %q", got)
+			}
+			if _, err := os.Stat(filepath.Join(cache.Dir(), refDir, hash([]byte("key")))); !os.IsNotExist(err) {
+				t.Errorf("Expected the damaged reference to be removed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCache_Prune(t *testing.T) {
+	cache, now := newTestCache(t, 25)
+
+	put := func(key, value string) {
+		t.Helper()
+		*now = now.Add(time.Minute)
+		if err := cache.Put(key, []byte(value)); err != nil {
+			t.Fatalf("Put() unexpected error: %v", err)
+		}
+	}
+
+	put("a", "aaaaaaaaaa")
+	put("b", "bbbbbbbbbb")
+
+	// Reading a makes b the least recently used
+	*now = now.Add(time.Minute)
+	if _, found := cache.Get("a"); !found {
+		t.Fatal("Expected a to be cached")
+	}
+	put("c", "cccccccccc")
+
+	// The key referring to b's removed contents goes with them
+	refs, err := os.ReadDir(filepath.Join(cache.Dir(), refDir))
+	if err != nil {
+		t.Fatalf("Failed to read refs: %v", err)
+	}
+	if len(refs) != 2 {
+		t.Errorf("Expected the refs of a and c to be left, got %d refs", len(refs))
+	}
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, found := cache.Get(key); found != want {
+			t.Errorf("Get(%q) found = %t, want %t", key, found, want)
+		}
+	}
+
+	// Values larger than the whole cache are not stored
+	put("d", string(bytes.Repeat([]byte("d"), 26)))
+	if _, found := cache.Get("d"); found {
+		t.Error("Expected a value over the size limit not to be stored")
+	}
+	if _, found := cache.Get("c"); !found {
+		t.Error("Expected storing an oversized value to leave the cache alone")
+	}
+}
@@ -0,0 +1,254 @@
+// Package jobs runs long-running server work, such as account exports, in the background.
+// Tools start a job and return immediately; agents poll the job until it finishes.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job status constants
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// Job manager constants
+const (
+	// DefaultRetention is the number of finished jobs kept for status queries
+	DefaultRetention = 100
+	jobIDBytes       = 8
+
+	// interruptedError explains why a job recorded as running is no longer running
+	interruptedError = "the server stopped before the job finished"
+)
+
+// Func is the work performed by a job. It returns a JSON-serializable result.
+type Func func(ctx context.Context) (any, error)
+
+// Job describes a background job and, once finished, its result or error
+type Job struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Result     any        `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not
+func (j *Job) Done() bool {
+	return j.Status != StatusRunning
+}
+
+// Recorder persists job records so finished jobs can still be queried after the server
+// restarts. Implementations handle their own failures, since a job's outcome does not
+// depend on it being recorded.
+type Recorder interface {
+	SaveJob(job Job)
+	DeleteJob(id string)
+	LoadJob(id string) (Job, bool)
+}
+
+// Manager starts jobs and tracks their status. Jobs run with a context that is
+// canceled when the manager shuts down, not when the request that started them ends.
+type Manager struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	retention int
+	now       func() time.Time
+
+	mu       sync.RWMutex
+	jobs     map[string]*Job
+	order    []string
+	done     map[string]chan struct{}
+	recorder Recorder
+}
+
+// NewManager creates a job manager that keeps up to retention finished jobs
+func NewManager(retention int) *Manager {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:       ctx,
+		cancel:    cancel,
+		retention: retention,
+		now:       time.Now,
+		jobs:      make(map[string]*Job),
+		done:      make(map[string]chan struct{}),
+	}
+}
+
+// SetRecorder persists jobs through r from now on, and looks up jobs the manager is not
+// tracking, such as those started before a restart, in r
+func (m *Manager) SetRecorder(r Recorder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recorder = r
+}
+
+// Start runs fn in the background and returns the newly created job
+func (m *Manager) Start(name string, fn Func) (Job, error) {
+	if err := m.ctx.Err(); err != nil {
+		return Job{}, fmt.Errorf("job manager is shut down: %w", err)
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	job := &Job{ID: id, Name: name, Status: StatusRunning, StartedAt: m.now()}
+	done := make(chan struct{})
+
+	m.mu.Lock()
+	m.jobs[id] = job
+	m.order = append(m.order, id)
+	m.done[id] = done
+	snapshot := *job
+	if m.recorder != nil {
+		m.recorder.SaveJob(snapshot)
+	}
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer close(done)
+
+		result, err := fn(m.ctx)
+		m.finish(id, result, err)
+	}()
+
+	return snapshot, nil
+}
+
+// finish records the outcome of a job and prunes old finished jobs
+func (m *Manager) finish(id string, result any, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.jobs[id]
+	finished := m.now()
+	job.FinishedAt = &finished
+
+	switch {
+	case err == nil:
+		job.Status = StatusSucceeded
+		job.Result = result
+	case m.ctx.Err() != nil:
+		job.Status = StatusCanceled
+		job.Error = err.Error()
+	default:
+		job.Status = StatusFailed
+		job.Error = err.Error()
+	}
+	if m.recorder != nil {
+		m.recorder.SaveJob(*job)
+	}
+
+	m.prune()
+}
+
+// prune drops the oldest finished jobs beyond the retention limit; callers hold m.mu
+func (m *Manager) prune() {
+	finished := 0
+	for _, id := range m.order {
+		if m.jobs[id].Done() {
+			finished++
+		}
+	}
+
+	kept := m.order[:0]
+	for _, id := range m.order {
+		if finished > m.retention && m.jobs[id].Done() {
+			delete(m.jobs, id)
+			delete(m.done, id)
+			if m.recorder != nil {
+				m.recorder.DeleteJob(id)
+			}
+			finished--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.order = kept
+}
+
+// Get returns a snapshot of the job with the given ID. Jobs recorded by an earlier server
+// process that were still running when it stopped are reported as failed.
+func (m *Manager) Get(id string) (Job, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if job, ok := m.jobs[id]; ok {
+		return *job, true
+	}
+	if m.recorder == nil {
+		return Job{}, false
+	}
+
+	job, ok := m.recorder.LoadJob(id)
+	if ok && !job.Done() {
+		job.Status = StatusFailed
+		job.Error = interruptedError
+	}
+	return job, ok
+}
+
+// List returns snapshots of all tracked jobs, oldest first
+func (m *Manager) List() []Job {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jobs := make([]Job, 0, len(m.order))
+	for _, id := range m.order {
+		jobs = append(jobs, *m.jobs[id])
+	}
+	return jobs
+}
+
+// Wait blocks until the job finishes or ctx is done, then returns the job's latest state
+func (m *Manager) Wait(ctx context.Context, id string) (Job, error) {
+	m.mu.RLock()
+	done, ok := m.done[id]
+	m.mu.RUnlock()
+	if !ok {
+		return Job{}, fmt.Errorf("job '%s' not found", id)
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return Job{}, ctx.Err()
+	}
+
+	job, _ := m.Get(id)
+	return job, nil
+}
+
+// Shutdown cancels running jobs and waits for them to return
+func (m *Manager) Shutdown() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// newJobID generates a random job identifier
+func newJobID() (string, error) {
+	b := make([]byte, jobIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,244 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryRecorder records jobs in memory, standing in for persistent storage
+type memoryRecorder struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func (r *memoryRecorder) SaveJob(job Job) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.ID] = job
+}
+
+func (r *memoryRecorder) DeleteJob(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.jobs, id)
+}
+
+func (r *memoryRecorder) LoadJob(id string) (Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	return job, ok
+}
+
+func TestManager_StartAndWait(t *testing.T) {
+	tests := []struct {
+		name           string
+		fn             Func
+		expectedStatus string
+		expectedError  string
+		expectResult   bool
+	}{
+		{
+			name: "successful job",
+			fn: func(context.Context) (any, error) {
+				return map[string]int{"count": 3}, nil
+			},
+			expectedStatus: StatusSucceeded,
+			expectResult:   true,
+		},
+		{
+			name: "failed job",
+			fn: func(context.Context) (any, error) {
+				return nil, errors.New("export failed")
+			},
+			expectedStatus: StatusFailed,
+			expectedError:  "export failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(0)
+			defer manager.Shutdown()
+
+			job, err := manager.Start("test", tt.fn)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if job.ID == "" || job.Name != "test" {
+				t.Errorf("Unexpected job: %+v", job)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			finished, err := manager.Wait(ctx, job.ID)
+			if err != nil {
+				t.Fatalf("Unexpected error waiting for job: %v", err)
+			}
+
+			if finished.Status != tt.expectedStatus {
+				t.Errorf("Expected status %s, got %s", tt.expectedStatus, finished.Status)
+			}
+			if finished.Error != tt.expectedError {
+				t.Errorf("Expected error '%s', got '%s'", tt.expectedError, finished.Error)
+			}
+			if (finished.Result != nil) != tt.expectResult {
+				t.Errorf("Expected result present = %t, got %v", tt.expectResult, finished.Result)
+			}
+			if finished.FinishedAt == nil || !finished.Done() {
+				t.Error("Expected job to be marked finished")
+			}
+		})
+	}
+}
+
+func TestManager_GetAndList(t *testing.T) {
+	manager := NewManager(0)
+	defer manager.Shutdown()
+
+	release := make(chan struct{})
+	job, err := manager.Start("blocked", func(context.Context) (any, error) {
+		<-release
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	running, ok := manager.Get(job.ID)
+	if !ok {
+		t.Fatal("Expected job to be found")
+	}
+	if running.Status != StatusRunning {
+		t.Errorf("Expected running job, got %s", running.Status)
+	}
+
+	if _, ok := manager.Get("missing"); ok {
+		t.Error("Expected unknown job to be missing")
+	}
+
+	close(release)
+	if _, err := manager.Wait(context.Background(), job.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	jobs := manager.List()
+	if len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Errorf("Expected list to contain the job, got %+v", jobs)
+	}
+}
+
+func TestManager_Retention(t *testing.T) {
+	manager := NewManager(2)
+	defer manager.Shutdown()
+
+	var ids []string
+	for range 4 {
+		job, err := manager.Start("quick", func(context.Context) (any, error) { return nil, nil })
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := manager.Wait(context.Background(), job.ID); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	if len(manager.List()) != 2 {
+		t.Errorf("Expected 2 retained jobs, got %d", len(manager.List()))
+	}
+	if _, ok := manager.Get(ids[0]); ok {
+		t.Error("Expected oldest job to be pruned")
+	}
+	if _, ok := manager.Get(ids[3]); !ok {
+		t.Error("Expected newest job to be retained")
+	}
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	manager := NewManager(0)
+
+	job, err := manager.Start("long", func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	manager.Shutdown()
+
+	canceled, ok := manager.Get(job.ID)
+	if !ok {
+		t.Fatal("Expected job to be found")
+	}
+	if canceled.Status != StatusCanceled {
+		t.Errorf("Expected canceled status, got %s", canceled.Status)
+	}
+
+	if _, err := manager.Start("late", func(context.Context) (any, error) { return nil, nil }); err == nil {
+		t.Error("Expected error starting a job after shutdown")
+	}
+}
+
+func TestManager_WaitUnknownJob(t *testing.T) {
+	manager := NewManager(0)
+	defer manager.Shutdown()
+
+	if _, err := manager.Wait(context.Background(), "missing"); err == nil {
+		t.Error("Expected error waiting for unknown job")
+	}
+}
+
+func TestManager_Recorder(t *testing.T) {
+	recorder := &memoryRecorder{jobs: make(map[string]Job)}
+	recorder.SaveJob(Job{ID: "interrupted", Name: "export", Status: StatusRunning})
+
+	manager := NewManager(1)
+	manager.SetRecorder(recorder)
+
+	var ids []string
+	for range 2 {
+		job, err := manager.Start("quick", func(context.Context) (any, error) { return "done", nil })
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := manager.Wait(context.Background(), job.ID); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+	manager.Shutdown()
+
+	// A new manager, as after a restart, finds jobs through the recorder
+	restarted := NewManager(1)
+	defer restarted.Shutdown()
+	restarted.SetRecorder(recorder)
+
+	tests := []struct {
+		name       string
+		id         string
+		wantFound  bool
+		wantStatus string
+	}{
+		{name: "finished job", id: ids[1], wantFound: true, wantStatus: StatusSucceeded},
+		{name: "pruned job", id: ids[0]},
+		{name: "job interrupted by a restart", id: "interrupted", wantFound: true, wantStatus: StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, ok := restarted.Get(tt.id)
+			if ok != tt.wantFound {
+				t.Fatalf("Get() found = %t, want %t", ok, tt.wantFound)
+			}
+			if ok && job.Status != tt.wantStatus {
+				t.Errorf("Expected status %s, got %s (%s)", tt.wantStatus, job.Status, job.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// Log attribute keys for correlation identifiers carried in a context
+const (
+	RequestIDKey = "request_id"
+	SessionIDKey = "session_id"
+)
+
+// requestIDBytes is the number of random bytes in a generated request ID
+const requestIDBytes = 8
+
+type requestIDContextKey struct{}
+
+type sessionIDContextKey struct{}
+
+// NewRequestID generates a random identifier for a single request
+func NewRequestID() string {
+	id := make([]byte, requestIDBytes)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// ContextWithRequestID returns a context carrying the given request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// ContextWithSessionID returns a context carrying the given client session ID
+func ContextWithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDContextKey{}, sessionID)
+}
+
+// SessionIDFromContext returns the client session ID carried by ctx, or "" if there is none
+func SessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDContextKey{}).(string)
+	return sessionID
+}
+
+// contextAttrs returns log attributes for the correlation identifiers carried by ctx
+func contextAttrs(ctx context.Context) []any {
+	var attrs []any
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		attrs = append(attrs, RequestIDKey, requestID)
+	}
+	if sessionID := SessionIDFromContext(ctx); sessionID != "" {
+		attrs = append(attrs, SessionIDKey, sessionID)
+	}
+	return attrs
+}
+
+// contextHandler adds the correlation identifiers carried by a record's context to
+// every log line, so packages logging with slog's *Context methods pick them up
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds correlation attributes from ctx before passing the record on
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		record.Add(contextAttrs(ctx)...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler with additional attributes that still adds context attributes
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler for a group that still adds context attributes
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewRequestID(t *testing.T) {
+	first := NewRequestID()
+	second := NewRequestID()
+
+	if len(first) != 2*requestIDBytes {
+		t.Errorf("Expected %d hex characters, got '%s'", 2*requestIDBytes, first)
+	}
+	if first == second {
+		t.Error("Expected request IDs to be unique")
+	}
+}
+
+func TestContextIDs(t *testing.T) {
+	ctx := context.Background()
+	if RequestIDFromContext(ctx) != "" || SessionIDFromContext(ctx) != "" {
+		t.Error("Expected no IDs in an empty context")
+	}
+
+	ctx = ContextWithSessionID(ContextWithRequestID(ctx, "req-1"), "session-1")
+	if got := RequestIDFromContext(ctx); got != "req-1" {
+		t.Errorf("Expected request ID 'req-1', got '%s'", got)
+	}
+	if got := SessionIDFromContext(ctx); got != "session-1" {
+		t.Errorf("Expected session ID 'session-1', got '%s'", got)
+	}
+}
+
+func TestContextIDsInLogLines(t *testing.T) {
+	ctx := ContextWithSessionID(ContextWithRequestID(context.Background(), "req-1"), "session-1")
+
+	tests := []struct {
+		name string
+		log  func(logger Logger)
+	}{
+		{
+			name: "logger with context",
+			log: func(logger Logger) {
+				logger.WithContext(ctx).Info("test message")
+			},
+		},
+		{
+			name: "slog context method",
+			log: func(logger Logger) {
+				logger.Slog().InfoContext(ctx, "test message")
+			},
+		},
+		{
+			name: "slog logger with attributes",
+			log: func(logger Logger) {
+				logger.Slog().With("component", "api").InfoContext(ctx, "test message")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(NewLoggerWithWriter("info", &buf))
+
+			var logEntry map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &logEntry); err != nil {
+				t.Fatalf("Failed to parse log output as JSON: %v", err)
+			}
+
+			if logEntry[RequestIDKey] != "req-1" {
+				t.Errorf("Expected request_id 'req-1', got %v", logEntry[RequestIDKey])
+			}
+			if logEntry[SessionIDKey] != "session-1" {
+				t.Errorf("Expected session_id 'session-1', got %v", logEntry[SessionIDKey])
+			}
+		})
+	}
+}
+
+func TestContextIDsOmittedWhenAbsent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLoggerWithWriter("info", &buf)
+
+	logger.WithContext(context.Background()).Info("test message")
+	logger.Slog().InfoContext(context.Background(), "test message")
+
+	if strings.Contains(buf.String(), RequestIDKey) || strings.Contains(buf.String(), SessionIDKey) {
+		t.Errorf("Expected no correlation IDs in log output, got %s", buf.String())
+	}
+}
@@ -9,17 +9,21 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
 )
 
 // Logger interface for structured logging with multiple levels
 type Logger interface {
 	Fatal(msg string, args ...any)
 	Error(msg string, args ...any)
+	Warn(msg string, args ...any)
 	Info(msg string, args ...any)
 	Debug(msg string, args ...any)
 	Trace(msg string, args ...any)
 	With(args ...any) Logger
 	WithContext(ctx context.Context) Logger
+	Slog() *slog.Logger
 }
 
 // slogLogger implements Logger using Go's slog package
@@ -42,12 +46,18 @@ func NewLogger(level string) Logger {
 
 // NewLoggerWithWriter creates a logger with a custom writer (useful for testing)
 func NewLoggerWithWriter(level string, writer io.Writer) Logger {
+	return NewLoggerWithRedactor(level, writer, redact.Default())
+}
+
+// NewLoggerWithRedactor creates a logger that masks credentials and personal data in every
+// message and attribute with the given redactor, such as tool call arguments
+func NewLoggerWithRedactor(level string, writer io.Writer, redactor *redact.Redactor) Logger {
 	slogLevel := parseLogLevel(level)
 
 	// Create custom handler options
 	opts := &slog.HandlerOptions{
 		Level: slogLevel,
-		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			// Customize level names for our custom levels
 			if a.Key == slog.LevelKey {
 				switch a.Value.Any().(slog.Level) {
@@ -64,13 +74,14 @@ func NewLoggerWithWriter(level string, writer io.Writer) Logger {
 				case slog.LevelError:
 					a.Value = slog.StringValue("ERROR")
 				}
+				return a
 			}
-			return a
+			return redactor.Attr(groups, a)
 		},
 	}
 
-	// Use JSON handler for structured logging
-	handler := slog.NewJSONHandler(writer, opts)
+	// Use JSON handler for structured logging, adding request and session IDs from the context
+	handler := contextHandler{Handler: slog.NewJSONHandler(writer, opts)}
 	logger := slog.New(handler)
 
 	return &slogLogger{
@@ -117,6 +128,11 @@ func (l *slogLogger) Error(msg string, args ...any) {
 	l.logger.Log(context.Background(), slog.LevelError, msg, args...)
 }
 
+// Warn logs at warn level
+func (l *slogLogger) Warn(msg string, args ...any) {
+	l.logger.Log(context.Background(), slog.LevelWarn, msg, args...)
+}
+
 // Info logs at info level
 func (l *slogLogger) Info(msg string, args ...any) {
 	l.logger.Log(context.Background(), slog.LevelInfo, msg, args...)
@@ -140,11 +156,19 @@ func (l *slogLogger) With(args ...any) Logger {
 	}
 }
 
-// WithContext returns a new logger with context
-func (l *slogLogger) WithContext(_ context.Context) Logger {
-	// For now, return the same logger
-	// In the future, we could extract values from context
-	return l
+// WithContext returns a new logger that includes the request and session IDs carried by ctx
+func (l *slogLogger) WithContext(ctx context.Context) Logger {
+	attrs := contextAttrs(ctx)
+	if len(attrs) == 0 {
+		return l
+	}
+	return l.With(attrs...)
+}
+
+// Slog returns the underlying slog logger for packages that log through slog directly,
+// such as the API client. Its *Context methods include the request and session IDs.
+func (l *slogLogger) Slog() *slog.Logger {
+	return l.logger
 }
 
 // IsLevelEnabled checks if the given level is enabled for this logger
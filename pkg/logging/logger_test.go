@@ -4,8 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -54,6 +60,7 @@ func TestLogger_LoggingLevels(t *testing.T) {
 		// Error level logger
 		{"error logger, fatal message", "error", "fatal", "FATAL", true},
 		{"error logger, error message", "error", "error", "ERROR", true},
+		{"error logger, warn message", "error", "warn", "WARN", false},
 		{"error logger, info message", "error", "info", "INFO", false},
 		{"error logger, debug message", "error", "debug", "DEBUG", false},
 		{"error logger, trace message", "error", "trace", "TRACE", false},
@@ -61,6 +68,7 @@ func TestLogger_LoggingLevels(t *testing.T) {
 		// Info level logger
 		{"info logger, fatal message", "info", "fatal", "FATAL", true},
 		{"info logger, error message", "info", "error", "ERROR", true},
+		{"info logger, warn message", "info", "warn", "WARN", true},
 		{"info logger, info message", "info", "info", "INFO", true},
 		{"info logger, debug message", "info", "debug", "DEBUG", false},
 		{"info logger, trace message", "info", "trace", "TRACE", false},
@@ -94,6 +102,8 @@ func TestLogger_LoggingLevels(t *testing.T) {
 			switch tt.logMethod {
 			case "error":
 				logger.Error("test message", "key", "value")
+			case "warn":
+				logger.Warn("test message", "key", "value")
 			case "info":
 				logger.Info("test message", "key", "value")
 			case "debug":
@@ -295,7 +305,7 @@ func TestLogger_OutputFormat(t *testing.T) {
 	var buf bytes.Buffer
 	logger := NewLoggerWithWriter("info", &buf)
 
-	logger.Info("test message", "request_id", "12345", "user", "test@example.com")
+	logger.Info("test message", "request_id", "12345", "user", "test-user")
 
 	output := buf.String()
 	if output == "" {
@@ -313,7 +323,7 @@ func TestLogger_OutputFormat(t *testing.T) {
 		"level":      "INFO",
 		"msg":        "test message",
 		"request_id": "12345",
-		"user":       "test@example.com",
+		"user":       "test-user",
 	}
 
 	for key, expectedValue := range expectedFields {
@@ -328,6 +338,81 @@ func TestLogger_OutputFormat(t *testing.T) {
 	}
 }
 
+func TestLogger_Redaction(t *testing.T) {
+	redactor, err := redact.New([]string{`lic_[a-z0-9]+`})
+	if err != nil {
+		t.Fatalf("redact.New() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		msg   string
+		args  []any
+		want  map[string]any
+		leaks []string
+	}{
+		{
+			name:  "sensitive keys",
+			msg:   "tool called",
+			args:  []any{"api_token", "abc123", "app_id", "app-1"},
+			want:  map[string]any{"api_token": redact.Mask, "app_id": "app-1"},
+			leaks: []string{"abc123"},
+		},
+		{
+			name: "tool arguments",
+			msg:  "create_customer tool called",
+			args: []any{"arguments", map[string]any{"name": "Acme", "email": "ops@acme.example"}},
+			want: map[string]any{
+				"arguments": map[string]any{"name": "Acme", "email": redact.Mask},
+			},
+			leaks: []string{"ops@acme.example"},
+		},
+		{
+			name:  "patterns in messages and errors",
+			msg:   "license lic_abc123 not found",
+			args:  []any{"error", errors.New("customer ops@acme.example has lic_abc123")},
+			want:  map[string]any{"msg": "license [REDACTED] not found", "error": "customer [REDACTED] has [REDACTED]"},
+			leaks: []string{"lic_abc123", "ops@acme.example"},
+		},
+		{
+			name: "headers",
+			msg:  "request",
+			args: []any{"headers", http.Header{"Authorization": {"Bearer abc123"}, "Accept": {"application/json"}}},
+			want: map[string]any{
+				"headers": map[string]any{"Authorization": []any{redact.Mask}, "Accept": []any{"application/json"}},
+			},
+			leaks: []string{"abc123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLoggerWithRedactor("info", &buf, redactor)
+			logger.Info(tt.msg, tt.args...)
+
+			for _, leak := range tt.leaks {
+				if strings.Contains(buf.String(), leak) {
+					t.Errorf("Log output contains %s: %s", leak, buf.String())
+				}
+			}
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Log output is not valid JSON: %v", err)
+			}
+			if entry["level"] != "INFO" {
+				t.Errorf("Expected level INFO, got %v", entry["level"])
+			}
+			for key, want := range tt.want {
+				if !reflect.DeepEqual(entry[key], want) {
+					t.Errorf("Expected %s = %v, got %v", key, want, entry[key])
+				}
+			}
+		})
+	}
+}
+
 func TestLogger_OutputGoesToStderr(t *testing.T) {
 	// This test verifies that NewLogger (without writer) uses stderr
 	// We can't easily test this directly, but we can verify the constructor
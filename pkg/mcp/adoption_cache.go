@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// defaultAdoptionCacheTTL controls how long an application's adoption data is reused before
+// it is fetched again
+const defaultAdoptionCacheTTL = 5 * time.Minute
+
+// adoptionData is the listings and channel release histories of an application that the
+// release adoption funnel is computed from
+type adoptionData struct {
+	channels  []models.Channel
+	releases  []models.Release
+	customers []models.Customer
+	histories map[string][]models.ChannelRelease
+	fetchedAt time.Time
+}
+
+// adoptionCache caches adoption data per application, so repeated funnel reports do not
+// fetch every channel's history again. Data is fetched on first use, reused until it is
+// older than the TTL, and fetched again on request. Concurrent reports share one fetch.
+type adoptionCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	fetches singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*adoptionData
+}
+
+// newAdoptionCache creates an empty adoption data cache
+func newAdoptionCache(ttl time.Duration) *adoptionCache {
+	return &adoptionCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*adoptionData),
+	}
+}
+
+// get returns the cached data for an application while it is fresh, or fetches it
+func (c *adoptionCache) get(
+	ctx context.Context,
+	appID string,
+	refresh bool,
+	fetch func(ctx context.Context) (*adoptionData, error),
+) (*adoptionData, error) {
+	if !refresh {
+		c.mu.Lock()
+		entry, ok := c.entries[appID]
+		c.mu.Unlock()
+		if ok && c.now().Sub(entry.fetchedAt) <= c.ttl {
+			return entry, nil
+		}
+	}
+
+	result, err, _ := c.fetches.Do(appID, func() (any, error) {
+		entry, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entry.fetchedAt = c.now()
+
+		c.mu.Lock()
+		c.entries[appID] = entry
+		c.mu.Unlock()
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*adoptionData), nil
+}
+
+// Invalidate drops all cached data so the next reports fetch it again
+func (c *adoptionCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*adoptionData)
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdoptionCache_Get(t *testing.T) {
+	cache := newAdoptionCache(time.Minute)
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return clock }
+
+	fetches := 0
+	fetch := func(_ context.Context) (*adoptionData, error) {
+		fetches++
+		return &adoptionData{}, nil
+	}
+	get := func(appID string, refresh bool) {
+		t.Helper()
+		if _, err := cache.get(context.Background(), appID, refresh, fetch); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	get("app-1", false)
+	get("app-1", false)
+	if fetches != 1 {
+		t.Errorf("Expected fresh data to be reused, got %d fetches", fetches)
+	}
+
+	get("app-2", false)
+	if fetches != 2 {
+		t.Errorf("Expected each application to have its own data, got %d fetches", fetches)
+	}
+
+	get("app-1", true)
+	if fetches != 3 {
+		t.Errorf("Expected refresh to fetch the data again, got %d fetches", fetches)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	get("app-1", false)
+	if fetches != 4 {
+		t.Errorf("Expected stale data to be fetched again, got %d fetches", fetches)
+	}
+
+	cache.Invalidate()
+	get("app-1", false)
+	if fetches != 5 {
+		t.Errorf("Expected invalidation to drop the data, got %d fetches", fetches)
+	}
+
+	failing := func(_ context.Context) (*adoptionData, error) {
+		return nil, errors.New("API unavailable")
+	}
+	if _, err := cache.get(context.Background(), "app-3", false, failing); err == nil {
+		t.Error("Expected the fetch error to be returned")
+	}
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/audit"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// withAudit wraps a mutating tool's handler so that each call is recorded in the audit log,
+// when one is configured. Calls refused for want of confirmation and dry-run previews are
+// recorded too, so the log shows what agents attempted as well as what they changed. Other tools are returned
+// unwrapped. The log is read on each call, so reconfiguring it applies to registered tools.
+func (s *Server) withAudit(tool toolDefinition, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if !tool.mutating {
+		return next
+	}
+
+	toolName := tool.definition.Name
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+
+		log := s.auditLog()
+		if log == nil {
+			return result, err
+		}
+
+		entry := audit.Entry{
+			Time:      time.Now().UTC(),
+			Tool:      toolName,
+			Arguments: request.GetArguments(),
+			SessionID: logging.SessionIDFromContext(ctx),
+			RequestID: logging.RequestIDFromContext(ctx),
+			Outcome:   audit.OutcomeSucceeded,
+		}
+		var confirmErr *confirmationError
+		switch {
+		case errors.As(err, &confirmErr):
+			entry.Outcome = audit.OutcomeUnconfirmed
+			entry.Error = err.Error()
+		case err != nil:
+			entry.Outcome = audit.OutcomeFailed
+			entry.Error = err.Error()
+		case result != nil && result.IsError:
+			entry.Outcome = audit.OutcomeFailed
+		case s.dryRun(request):
+			entry.Outcome = audit.OutcomeDryRun
+		}
+
+		// The call has already happened, so a failure to record it is logged rather than
+		// reported to the client
+		if recordErr := log.Record(entry); recordErr != nil {
+			s.logger.WithContext(ctx).Error("Failed to record audit entry", "tool", toolName, "error", recordErr)
+		}
+		return result, err
+	}
+}
+
+// auditLog returns the audit log in effect, or nil when auditing is off
+func (s *Server) auditLog() *audit.Log {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.audit
+}
+
+// openAuditLog opens the configured audit log, redacting with the configured patterns, or
+// returns nil when auditing is off
+func openAuditLog(cfg *config.Config) (*audit.Log, error) {
+	if cfg.AuditLogFile == "" {
+		return nil, nil
+	}
+	log, err := audit.Open(cfg.AuditLogFile, redactorFor(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", cfg.AuditLogFile, err)
+	}
+	return log, nil
+}
+
+// closeAuditLog closes an audit log that is no longer in use, if there is one
+func (s *Server) closeAuditLog(log *audit.Log) {
+	if log == nil {
+		return
+	}
+	if err := log.Close(); err != nil {
+		s.logger.Error("Failed to close audit log", "path", log.Path(), "error", err)
+	}
+}
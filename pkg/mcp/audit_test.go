@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/audit"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// readAuditLog parses the entries of an audit log file
+func readAuditLog(t *testing.T, path string) []audit.Entry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry audit.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse audit log line: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditLog(t *testing.T) {
+	vendorAPI := newTestClusterAPI(t)
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	cfg := &config.Config{
+		APIToken:     "test-token",
+		LogLevel:     "info",
+		Timeout:      30 * time.Second,
+		Endpoint:     vendorAPI.URL,
+		DataDir:      t.TempDir(),
+		AuditLogFile: auditPath,
+	}
+	server, err := NewServer(cfg, logging.NewLogger("info"))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { _ = server.Stop(context.Background()) })
+
+	ctx := server.mcpServer.WithContext(context.Background(), newTestSession())
+	call := func(name, arguments string) {
+		server.mcpServer.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q,"arguments":%s}}`, name, arguments)))
+	}
+
+	confirmationToken := func(arguments map[string]any) string {
+		token, _, err := server.confirmations.issue("delete_cluster", arguments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return token
+	}
+
+	call("list_clusters", `{}`)
+	call("delete_cluster", `{"cluster_id": "cluster-1"}`)
+	call("delete_cluster", fmt.Sprintf(`{"cluster_id": "cluster-1", "api_token": "secret", "confirmation_token": %q}`,
+		confirmationToken(map[string]any{"cluster_id": "cluster-1", "api_token": "secret"})))
+	call("delete_cluster", `{"cluster_id": "cluster-9"}`)
+	call("bulk", `{"operations": [{"tool": "delete_cluster", "arguments": {"cluster_id": "cluster-1"}}]}`)
+	call("delete_cluster", `{"cluster_id": "cluster-1", "dry_run": true}`)
+
+	entries := readAuditLog(t, auditPath)
+	wantOutcomes := []string{
+		audit.OutcomeUnconfirmed, audit.OutcomeSucceeded, audit.OutcomeFailed, audit.OutcomeUnconfirmed,
+		audit.OutcomeDryRun,
+	}
+	if len(entries) != len(wantOutcomes) {
+		t.Fatalf("Expected %d audit entries (only mutating calls), got %d: %+v", len(wantOutcomes), len(entries), entries)
+	}
+
+	for i, entry := range entries {
+		if entry.Tool != "delete_cluster" {
+			t.Errorf("Entry %d: expected tool delete_cluster, got %q", i, entry.Tool)
+		}
+		if entry.Outcome != wantOutcomes[i] {
+			t.Errorf("Entry %d: expected outcome %q, got %q", i, wantOutcomes[i], entry.Outcome)
+		}
+		if entry.SessionID != "test-session" || entry.RequestID == "" {
+			t.Errorf("Entry %d: expected session and request IDs, got %+v", i, entry)
+		}
+		if entry.Time.IsZero() {
+			t.Errorf("Entry %d: expected a timestamp", i)
+		}
+		refused := entry.Outcome == audit.OutcomeFailed || entry.Outcome == audit.OutcomeUnconfirmed
+		if refused != (entry.Error != "") {
+			t.Errorf("Entry %d: expected an error only for unsuccessful calls, got %q", i, entry.Error)
+		}
+	}
+
+	arguments := entries[1].Arguments
+	if arguments["api_token"] != audit.Redacted || arguments["confirmation_token"] != audit.Redacted ||
+		arguments["cluster_id"] != "cluster-1" {
+		t.Errorf("Expected secrets redacted and other arguments kept, got %v", arguments)
+	}
+}
+
+func TestAuditLogReconfigure(t *testing.T) {
+	server := newTestServer(t, newTestClusterAPI(t).URL)
+	if server.auditLog() != nil {
+		t.Fatal("Expected no audit log when none is configured")
+	}
+
+	next := *server.currentConfig()
+	next.AuditLogFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := server.Reconfigure(&next); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if log := server.auditLog(); log == nil || log.Path() != next.AuditLogFile {
+		t.Fatalf("Expected the audit log at %s to be opened", next.AuditLogFile)
+	}
+
+	// Changing the redaction patterns reopens the log so new entries use them
+	opened := server.auditLog()
+	redacting := next
+	redacting.RedactPatterns = []string{`lic_[a-z0-9]+`}
+	if err := server.Reconfigure(&redacting); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if log := server.auditLog(); log == opened || log.Path() != next.AuditLogFile {
+		t.Fatal("Expected the audit log to be reopened with the new redaction patterns")
+	}
+
+	// A path that cannot be opened leaves the previous configuration in place
+	broken := redacting
+	broken.AuditLogFile = filepath.Join(t.TempDir(), "missing", "audit.jsonl")
+	if err := server.Reconfigure(&broken); err == nil {
+		t.Fatal("Expected an error for an audit log that cannot be opened")
+	}
+	if server.currentConfig().AuditLogFile != next.AuditLogFile || server.auditLog().Path() != next.AuditLogFile {
+		t.Error("Expected a failed reconfiguration to keep the previous audit log")
+	}
+
+	if err := server.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if server.auditLog() != nil {
+		t.Error("Expected Stop to close the audit log")
+	}
+}
@@ -0,0 +1,393 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Argument completion
+const (
+	// methodCompletionComplete is the MCP method clients ask for argument completions with
+	methodCompletionComplete = "completion/complete"
+	// maxCompletionValues is the most completions the MCP specification allows in one response
+	maxCompletionValues = 100
+	// defaultCompletionCacheTTL controls how long the applications, channels, and customers
+	// completions are drawn from are reused before they are listed again
+	defaultCompletionCacheTTL = time.Minute
+)
+
+// Kinds of entity an argument is completed from
+const (
+	completeApplications = "applications"
+	completeChannels     = "channels"
+	completeCustomers    = "customers"
+)
+
+// completedArguments maps the names of the tool, prompt, and resource template arguments the
+// server completes to the kind of entity they name
+var completedArguments = map[string]string{
+	"app_id":      completeApplications,
+	"application": completeApplications,
+	"channel_id":  completeChannels,
+	"channel":     completeChannels,
+	"customer_id": completeCustomers,
+	"customer":    completeCustomers,
+}
+
+// completionRequest is a completion/complete request. The MCP library's CompleteParams lacks
+// the arguments already given, which scope channel and customer completions to an application.
+type completionRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		Argument struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"argument"`
+		Context struct {
+			Arguments map[string]string `json:"arguments"`
+		} `json:"context"`
+	} `json:"params"`
+}
+
+// completionCandidate is a value an argument can be completed with, and the text it is
+// matched on
+type completionCandidate struct {
+	value   string
+	matches []string
+}
+
+// completionEntry is a cached list of candidates
+type completionEntry struct {
+	candidates []completionCandidate
+	fetchedAt  time.Time
+}
+
+// completionCache caches completion candidates per kind of entity and application, so a
+// client completing as the user types does not list them on every keystroke
+type completionCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]completionEntry
+}
+
+// newCompletionCache creates an empty completion cache
+func newCompletionCache(ttl time.Duration) *completionCache {
+	return &completionCache{ttl: ttl, now: time.Now, entries: make(map[string]completionEntry)}
+}
+
+// get returns the cached candidates for key while they are fresh, or lists them with fetch
+func (c *completionCache) get(
+	ctx context.Context, key string, fetch func(ctx context.Context) ([]completionCandidate, error),
+) ([]completionCandidate, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.fetchedAt) <= c.ttl {
+		return entry.candidates, nil
+	}
+
+	candidates, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = completionEntry{candidates: candidates, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return candidates, nil
+}
+
+// complete returns the completions of an argument: application slugs, channel slugs, or
+// customer IDs whose ID, slug, name, or email contains the value typed so far, ignoring case
+// and accents, or all of them before anything is typed. Channels and customers are drawn from
+// the application given in the request's other arguments, or the default application. Other
+// arguments have no completions.
+func (s *Server) complete(ctx context.Context, request *completionRequest) (*mcp.CompleteResult, error) {
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+
+	kind, ok := completedArguments[request.Params.Argument.Name]
+	if !ok {
+		return result, nil
+	}
+
+	appID := ""
+	if kind != completeApplications {
+		app := request.Params.Context.Arguments["app_id"]
+		if app == "" {
+			app = request.Params.Context.Arguments["application"]
+		}
+		if app == "" {
+			app = s.currentConfig().App
+		}
+		if app == "" {
+			return result, nil
+		}
+
+		var err error
+		if appID, err = s.resolver.ResolveApplicationID(ctx, app); err != nil {
+			return nil, err
+		}
+	}
+
+	list := func(ctx context.Context) ([]completionCandidate, error) {
+		return s.completionCandidates(ctx, kind, appID)
+	}
+	candidates, err := s.completions.get(ctx, kind+"/"+appID, list)
+	if err != nil {
+		return nil, err
+	}
+
+	value := request.Params.Argument.Value
+	for _, candidate := range candidates {
+		if models.FoldText(value) != "" && !models.MatchesQuery(value, candidate.matches...) {
+			continue
+		}
+		result.Completion.Total++
+		if len(result.Completion.Values) < maxCompletionValues {
+			result.Completion.Values = append(result.Completion.Values, candidate.value)
+		}
+	}
+	result.Completion.HasMore = result.Completion.Total > len(result.Completion.Values)
+	return result, nil
+}
+
+// completionCandidates lists the applications, channels of an application, or customers of
+// an application arguments of the given kind are completed from
+func (s *Server) completionCandidates(ctx context.Context, kind, appID string) ([]completionCandidate, error) {
+	var candidates []completionCandidate
+	switch kind {
+	case completeApplications:
+		list, err := s.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range list.Applications {
+			candidates = append(candidates, completionCandidate{
+				value:   cmp.Or(app.Slug, app.ID),
+				matches: []string{app.ID, app.Slug, app.Name},
+			})
+		}
+	case completeChannels:
+		list, err := s.channels.ListChannels(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+		for _, channel := range list.Channels {
+			candidates = append(candidates, completionCandidate{
+				value:   cmp.Or(channel.ChannelSlug, channel.ID),
+				matches: []string{channel.ID, channel.ChannelSlug, channel.Name},
+			})
+		}
+	case completeCustomers:
+		list, err := s.customers.ListCustomersUpTo(ctx, appID, s.currentConfig().SearchScanLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, customer := range list.Customers {
+			candidates = append(candidates, completionCandidate{
+				value:   customer.ID,
+				matches: []string{customer.ID, customer.Name, customer.Email},
+			})
+		}
+	}
+	return candidates, nil
+}
+
+// The MCP library neither handles completion/complete nor lets a server advertise the
+// completions capability, so the transports answer completion requests before the library
+// sees them and add the capability to its initialize responses.
+
+// completionResponse is the JSON-RPC response to a completion request
+type completionResponse struct {
+	JSONRPC string              `json:"jsonrpc"`
+	ID      json.RawMessage     `json:"id"`
+	Result  *mcp.CompleteResult `json:"result,omitempty"`
+	Error   *completionError    `json:"error,omitempty"`
+}
+
+// completionError is the error of a failed completion request
+type completionError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// answerCompletion answers a JSON-RPC message when it is a completion request, returning the
+// encoded response and true, or false for messages the MCP library handles
+func (s *Server) answerCompletion(ctx context.Context, message []byte) ([]byte, bool) {
+	var request completionRequest
+	if json.Unmarshal(message, &request) != nil || request.Method != methodCompletionComplete ||
+		len(request.ID) == 0 {
+		return nil, false
+	}
+
+	response := completionResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID}
+	result, err := s.complete(ctx, &request)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to complete argument",
+			"argument", request.Params.Argument.Name, "error", err)
+		response.Error = &completionError{Code: mcp.INTERNAL_ERROR, Message: err.Error()}
+	} else {
+		response.Result = result
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// advertiseCompletions adds the completions capability to an initialize response, returning
+// other messages unchanged
+func advertiseCompletions(message []byte) []byte {
+	var response map[string]json.RawMessage
+	if json.Unmarshal(message, &response) != nil {
+		return message
+	}
+	var result map[string]json.RawMessage
+	if json.Unmarshal(response["result"], &result) != nil || result["serverInfo"] == nil {
+		return message
+	}
+	var capabilities map[string]json.RawMessage
+	if json.Unmarshal(result["capabilities"], &capabilities) != nil {
+		return message
+	}
+
+	capabilities["completions"] = json.RawMessage(`{}`)
+	var err error
+	if result["capabilities"], err = json.Marshal(capabilities); err != nil {
+		return message
+	}
+	if response["result"], err = json.Marshal(result); err != nil {
+		return message
+	}
+	rewritten, err := json.Marshal(response)
+	if err != nil {
+		return message
+	}
+	return rewritten
+}
+
+// completionWriter serializes the messages written to the stdio transport's output, adding
+// the completions capability to initialize responses. The MCP library writes each message in
+// a single call.
+type completionWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (c *completionWriter) Write(p []byte) (int, error) {
+	message := bytes.TrimSuffix(p, []byte("\n"))
+	rewritten := append(advertiseCompletions(message), '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.w.Write(rewritten); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// withStdioCompletions answers completion requests read from input by writing their
+// responses to output, returning the input with them removed and the output with the
+// completions capability advertised, for the MCP library's stdio server
+func (s *Server) withStdioCompletions(ctx context.Context, input io.Reader, output io.Writer) (io.Reader, io.Writer) {
+	writer := &completionWriter{w: output}
+	filtered, forward := io.Pipe()
+
+	go func() {
+		reader := bufio.NewReader(input)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if response, ok := s.answerCompletion(ctx, line); ok {
+					go func() { _, _ = writer.Write(append(response, '\n')) }()
+				} else if _, writeErr := forward.Write(line); writeErr != nil {
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				forward.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return filtered, writer
+}
+
+// withHTTPCompletions answers completion requests posted to the streamable HTTP transport and
+// adds the completions capability to its initialize responses
+func (s *Server) withHTTPCompletions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if response, ok := s.answerCompletion(r.Context(), body); ok {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(response)
+			return
+		}
+
+		var request struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(body, &request) != nil || request.Method != string(mcp.MethodInitialize) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+		rewritten := advertiseCompletions(buffered.body.Bytes())
+		for name, values := range buffered.header {
+			w.Header()[name] = values
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(rewritten)))
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(rewritten)
+	})
+}
+
+// bufferedResponse holds a response so it can be rewritten before it is sent
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
@@ -0,0 +1,302 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// fakeCompletionAccount serves the channels and customers of app-acme, counting how often
+// channels are listed
+type fakeCompletionAccount struct {
+	channelsService
+	customersService
+	channelLists atomic.Int32
+}
+
+func (f *fakeCompletionAccount) ListChannels(_ context.Context, appID string) (*api.ChannelList, error) {
+	f.channelLists.Add(1)
+	if appID != "app-acme" {
+		return &api.ChannelList{Channels: []models.Channel{}}, nil
+	}
+	return &api.ChannelList{Channels: []models.Channel{
+		{ID: "channel-1", ChannelSlug: "stable", Name: "Stable"},
+		{ID: "channel-2", ChannelSlug: "beta", Name: "Beta"},
+		{ID: "channel-3", Name: "Unslugged"},
+	}}, nil
+}
+
+func (f *fakeCompletionAccount) ListCustomersUpTo(_ context.Context, appID string, _ int) (*api.CustomerList, error) {
+	if appID != "app-acme" {
+		return &api.CustomerList{Customers: []models.Customer{}}, nil
+	}
+	return &api.CustomerList{Customers: []models.Customer{
+		{ID: "customer-1", Name: "Globex", Email: "ops@globex.example"},
+		{ID: "customer-2", Name: "Initech", Email: "it@initech.example"},
+	}}, nil
+}
+
+// newCompletionTestServer creates a server with two applications, the first of which has
+// channels and customers
+func newCompletionTestServer(t *testing.T, defaultApp string) (*Server, *fakeCompletionAccount) {
+	t.Helper()
+
+	account := &fakeCompletionAccount{}
+	cfg := &config.Config{LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir(), App: defaultApp}
+	server, err := NewServerWithServices(cfg, logging.NewLogger("info"), &vendorapi.Services{
+		Client: fakeClient{},
+		Applications: &fakeApplications{apps: []models.Application{
+			{ID: "app-acme", Slug: "acme-platform", Name: "Acme Platform"},
+			{ID: "app-road", Slug: "roadrunner", Name: "Roadrunner Tracker"},
+		}},
+		Channels:  account,
+		Customers: account,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server, account
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultApp  string
+		argument    string
+		value       string
+		context     map[string]string
+		wantValues  []string
+		expectError bool
+	}{
+		{name: "applications by name", argument: "app_id", value: "tracker", wantValues: []string{"roadrunner"}},
+		{
+			name:       "every application before typing",
+			argument:   "application",
+			wantValues: []string{"acme-platform", "roadrunner"},
+		},
+		{
+			name:       "channels of the given application",
+			argument:   "channel_id",
+			value:      "b",
+			context:    map[string]string{"app_id": "acme-platform"},
+			wantValues: []string{"stable", "beta"},
+		},
+		{
+			name:       "channel without a slug",
+			argument:   "channel",
+			value:      "unslug",
+			context:    map[string]string{"application": "app-acme"},
+			wantValues: []string{"channel-3"},
+		},
+		{
+			name:       "customers of the default application by email",
+			defaultApp: "acme-platform",
+			argument:   "customer_id",
+			value:      "INITECH.example",
+			wantValues: []string{"customer-2"},
+		},
+		{name: "channels without an application", argument: "channel_id", value: "st", wantValues: []string{}},
+		{name: "argument without completions", argument: "query", value: "acme", wantValues: []string{}},
+		{
+			name:        "unknown application",
+			argument:    "customer_id",
+			context:     map[string]string{"app_id": "missing"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newCompletionTestServer(t, tt.defaultApp)
+
+			request := &completionRequest{}
+			request.Params.Argument.Name = tt.argument
+			request.Params.Argument.Value = tt.value
+			request.Params.Context.Arguments = tt.context
+
+			result, err := server.complete(context.Background(), request)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !slices.Equal(result.Completion.Values, tt.wantValues) {
+				t.Errorf("Expected completions %v, got %v", tt.wantValues, result.Completion.Values)
+			}
+			if result.Completion.Total != len(tt.wantValues) || result.Completion.HasMore {
+				t.Errorf("Expected a total of %d without more, got %d (has more %t)",
+					len(tt.wantValues), result.Completion.Total, result.Completion.HasMore)
+			}
+		})
+	}
+}
+
+func TestCompleteCachesCandidates(t *testing.T) {
+	server, account := newCompletionTestServer(t, "acme-platform")
+	now := time.Now()
+	server.completions.now = func() time.Time { return now }
+
+	request := &completionRequest{}
+	request.Params.Argument.Name = "channel_id"
+	for _, value := range []string{"s", "st", "sta"} {
+		request.Params.Argument.Value = value
+		if _, err := server.complete(context.Background(), request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if lists := account.channelLists.Load(); lists != 1 {
+		t.Errorf("Expected channels to be listed once while cached, got %d", lists)
+	}
+
+	now = now.Add(defaultCompletionCacheTTL + time.Second)
+	if _, err := server.complete(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lists := account.channelLists.Load(); lists != 2 {
+		t.Errorf("Expected channels to be listed again once the cache expired, got %d", lists)
+	}
+}
+
+// completionBody is a completion request for the channel_id argument of a tool
+const completionBody = `{"jsonrpc": "2.0", "id": 2, "method": "completion/complete", "params": {
+	"ref": {"type": "ref/prompt", "name": "release_summary"}, "argument": {"name": "channel_id", "value": "bet"},
+	"context": {"arguments": {"app_id": "acme-platform"}}}}`
+
+// completionResult is the part of a completion response the tests check
+type completionResult struct {
+	ID     int `json:"id"`
+	Result struct {
+		Capabilities map[string]any `json:"capabilities"`
+		Completion   struct {
+			Values []string `json:"values"`
+		} `json:"completion"`
+	} `json:"result"`
+}
+
+func TestServeStdioCompletions(t *testing.T) {
+	server, _ := newCompletionTestServer(t, "")
+
+	stdin, input := io.Pipe()
+	output, stdout := io.Pipe()
+	responses := make(chan completionResult, 2)
+	go func() {
+		scanner := bufio.NewScanner(output)
+		for scanner.Scan() {
+			var response completionResult
+			if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+				t.Errorf("Failed to decode response %s: %v", scanner.Text(), err)
+			}
+			responses <- response
+		}
+	}()
+
+	served := make(chan error, 1)
+	go func() { served <- server.serveStdio(context.Background(), stdin, stdout) }()
+
+	for _, body := range []string{initializeBody, completionBody} {
+		if _, err := fmt.Fprintln(input, strings.ReplaceAll(body, "\n", " ")); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+	}
+
+	received := map[int]completionResult{}
+	for range 2 {
+		select {
+		case response := <-responses:
+			received[response.ID] = response
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected responses to both requests, got %+v", received)
+		}
+	}
+	if _, ok := received[1].Result.Capabilities["completions"]; !ok {
+		t.Errorf("Expected the completions capability to be advertised, got %v", received[1].Result.Capabilities)
+	}
+	if values := received[2].Result.Completion.Values; !slices.Equal(values, []string{"beta"}) {
+		t.Errorf("Expected the beta channel to be completed, got %v", values)
+	}
+
+	input.Close()
+	waitForShutdown(t, served)
+}
+
+func TestServeHTTPCompletions(t *testing.T) {
+	server, _ := newCompletionTestServer(t, "")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.serveHTTPListener(ctx, listener, &config.Config{Transport: config.TransportHTTP})
+	}()
+	defer func() {
+		cancel()
+		waitForShutdown(t, served)
+	}()
+
+	url := "http://" + listener.Addr().String() + "/mcp"
+	for _, tt := range []struct {
+		body  string
+		check func(response completionResult)
+	}{
+		{
+			body: initializeBody,
+			check: func(response completionResult) {
+				if _, ok := response.Result.Capabilities["completions"]; !ok {
+					t.Errorf("Expected the completions capability to be advertised, got %v",
+						response.Result.Capabilities)
+				}
+			},
+		},
+		{
+			body: completionBody,
+			check: func(response completionResult) {
+				if values := response.Result.Completion.Values; !slices.Equal(values, []string{"beta"}) {
+					t.Errorf("Expected the beta channel to be completed, got %v", values)
+				}
+			},
+		},
+	} {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Accept", "application/json, text/event-stream")
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("Failed to call the HTTP transport: %v", err)
+		}
+		var result completionResult
+		err = json.NewDecoder(response.Body).Decode(&result)
+		response.Body.Close()
+		if err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		tt.check(result)
+	}
+}
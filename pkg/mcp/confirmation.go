@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultConfirmationTTL is how long a confirmation token can be redeemed after it is issued
+const defaultConfirmationTTL = 2 * time.Minute
+
+// confirmationTokenBytes is the number of random bytes in a confirmation token
+const confirmationTokenBytes = 16
+
+// confirmationTokenArgument is the argument destructive calls echo a confirmation token in
+const confirmationTokenArgument = "confirmation_token"
+
+// Reasons a confirmation token does not confirm a call
+var (
+	errConfirmationUnknown  = errors.New("the confirmation token is unknown, expired, or already used")
+	errConfirmationMismatch = errors.New("the confirmation token was issued for a different call")
+)
+
+// pendingConfirmation is a destructive call awaiting confirmation. It is identified by the
+// tool and its arguments, so a token confirms only the exact call it was issued for.
+type pendingConfirmation struct {
+	tool      string
+	arguments string
+	expiresAt time.Time
+}
+
+// confirmations holds the confirmation tokens issued to destructive calls. A call without a
+// token is refused and issued one; the same call made again with the token before it expires
+// goes ahead. Tokens are single use and are kept in memory only, so they don't survive a
+// restart.
+type confirmations struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]pendingConfirmation
+}
+
+// newConfirmations creates an empty confirmation token store
+func newConfirmations(ttl time.Duration) *confirmations {
+	return &confirmations{
+		ttl:     ttl,
+		now:     time.Now,
+		pending: make(map[string]pendingConfirmation),
+	}
+}
+
+// issue records a pending confirmation for a call and returns its token and expiry.
+// Expired confirmations are dropped.
+func (c *confirmations) issue(tool string, arguments map[string]any) (string, time.Time, error) {
+	key, err := confirmationKey(arguments)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	b := make([]byte, confirmationTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	maps.DeleteFunc(c.pending, func(_ string, pending pendingConfirmation) bool {
+		return !now.Before(pending.expiresAt)
+	})
+
+	expiresAt := now.Add(c.ttl)
+	c.pending[token] = pendingConfirmation{tool: tool, arguments: key, expiresAt: expiresAt}
+	return token, expiresAt, nil
+}
+
+// redeem consumes a token, returning nil if it confirms the call and the reason otherwise.
+// A token is consumed even when it was issued for a different call.
+func (c *confirmations) redeem(token, tool string, arguments map[string]any) error {
+	key, err := confirmationKey(arguments)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.pending[token]
+	delete(c.pending, token)
+	switch {
+	case !ok || !c.now().Before(pending.expiresAt):
+		return errConfirmationUnknown
+	case pending.tool != tool || pending.arguments != key:
+		return errConfirmationMismatch
+	}
+	return nil
+}
+
+// confirmationKey encodes a call's arguments, without its confirmation token, for comparing
+// calls. Map keys are encoded in sorted order, so equal arguments have equal keys.
+func confirmationKey(arguments map[string]any) (string, error) {
+	arguments = maps.Clone(arguments)
+	delete(arguments, confirmationTokenArgument)
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments for confirmation: %w", err)
+	}
+	return string(data), nil
+}
+
+// confirmationTokenParameter returns the confirmation_token parameter of destructive tools
+func confirmationTokenParameter() mcp.ToolOption {
+	return mcp.WithString(confirmationTokenArgument,
+		mcp.Description("The token returned by the same call made without it, passed once the user has "+
+			"agreed to the change; it confirms only a call with identical arguments and expires after two minutes"),
+	)
+}
+
+// requireConfirmation returns nil if the request carries a valid confirmation token for this
+// exact call. Otherwise it issues a new token and returns a confirmationError describing the
+// action, so the agent can ask the user and repeat the call with the token.
+func (s *Server) requireConfirmation(request mcp.CallToolRequest, action string) error {
+	var reason string
+	if token := request.GetString(confirmationTokenArgument, ""); token != "" {
+		err := s.confirmations.redeem(token, request.Params.Name, request.GetArguments())
+		if err == nil {
+			return nil
+		}
+		reason = err.Error()
+	}
+
+	token, expiresAt, err := s.confirmations.issue(request.Params.Name, request.GetArguments())
+	if err != nil {
+		return err
+	}
+	return &confirmationError{action: action, token: token, expiresAt: expiresAt, reason: reason}
+}
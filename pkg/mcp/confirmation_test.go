@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callConfirmed calls a tool handler and, if the call asks for confirmation, makes it again
+// with the token it returned, as an agent does once the user agrees
+func callConfirmed(t *testing.T, tool toolDefinition, args map[string]any) (*mcp.CallToolResult, error) {
+	t.Helper()
+
+	name := tool.definition.Name
+	_, err := tool.handler(context.Background(), createMockCallToolRequest(name, args))
+	var confirmErr *confirmationError
+	if !errors.As(err, &confirmErr) {
+		t.Fatalf("Expected %s to ask for confirmation, got %v", name, err)
+	}
+
+	confirmed := maps.Clone(args)
+	confirmed[confirmationTokenArgument] = confirmErr.token
+	return tool.handler(context.Background(), createMockCallToolRequest(name, confirmed))
+}
+
+func TestConfirmations(t *testing.T) {
+	args := map[string]any{"cluster_id": "cluster-1"}
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]any
+		elapsed time.Duration
+		wantErr error
+	}{
+		{name: "same call", tool: "delete_cluster", args: args},
+		{
+			name: "token argument ignored",
+			tool: "delete_cluster",
+			args: map[string]any{"cluster_id": "cluster-1", confirmationTokenArgument: "token"},
+		},
+		{name: "different tool", tool: "delete_vm", args: args, wantErr: errConfirmationMismatch},
+		{
+			name:    "different arguments",
+			tool:    "delete_cluster",
+			args:    map[string]any{"cluster_id": "cluster-2"},
+			wantErr: errConfirmationMismatch,
+		},
+		{name: "expired", tool: "delete_cluster", args: args, elapsed: time.Minute, wantErr: errConfirmationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			store := newConfirmations(time.Minute)
+			store.now = func() time.Time { return now }
+
+			token, expiresAt, err := store.issue("delete_cluster", args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !expiresAt.Equal(now.Add(time.Minute)) {
+				t.Errorf("Expected the token to expire after the TTL, got %v", expiresAt)
+			}
+
+			now = now.Add(tt.elapsed)
+			if err := store.redeem(token, tt.tool, tt.args); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("redeem() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := store.redeem(token, tt.tool, tt.args); !errors.Is(err, errConfirmationUnknown) {
+				t.Errorf("Expected a token to be usable once, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfirmationsDropExpired(t *testing.T) {
+	now := time.Now()
+	store := newConfirmations(time.Minute)
+	store.now = func() time.Time { return now }
+
+	for range 3 {
+		if _, _, err := store.issue("delete_cluster", nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	now = now.Add(time.Minute)
+	if _, _, err := store.issue("delete_cluster", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(store.pending) != 1 {
+		t.Errorf("Expected expired confirmations to be dropped, got %d pending", len(store.pending))
+	}
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	server := newTestServer(t, newTestClusterAPI(t).URL)
+	args := map[string]any{"cluster_id": "cluster-1"}
+
+	err := server.requireConfirmation(createMockCallToolRequest("delete_cluster", args), "deleting cluster-1")
+	var confirmErr *confirmationError
+	if !errors.As(err, &confirmErr) || confirmErr.token == "" || confirmErr.reason != "" {
+		t.Fatalf("Expected a confirmation error with a token, got %v", err)
+	}
+
+	payload := describeError(err)
+	if payload.Kind != confirmationRequiredKind || payload.ConfirmationToken != confirmErr.token ||
+		payload.ConfirmationExpiresAt == nil {
+		t.Errorf("Expected the token in the error payload, got %+v", payload)
+	}
+	if contains(err.Error(), confirmErr.token) {
+		t.Error("Expected the token to be kept out of the error message")
+	}
+
+	replayed := map[string]any{"cluster_id": "cluster-2", confirmationTokenArgument: confirmErr.token}
+	err = server.requireConfirmation(createMockCallToolRequest("delete_cluster", replayed), "deleting cluster-2")
+	if !errors.As(err, &confirmErr) || confirmErr.reason != errConfirmationMismatch.Error() {
+		t.Fatalf("Expected a token for another call to be refused, got %v", err)
+	}
+
+	confirmed := map[string]any{"cluster_id": "cluster-2", confirmationTokenArgument: confirmErr.token}
+	if err := server.requireConfirmation(createMockCallToolRequest("delete_cluster", confirmed), ""); err != nil {
+		t.Errorf("Expected the reissued token to confirm the call, got %v", err)
+	}
+}
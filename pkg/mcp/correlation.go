@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// withRequestID wraps a tool handler so that each call is assigned a request ID. The ID,
+// along with the client session ID when there is one, is carried in the context so that
+// tool, API client, and error log lines for the call can be correlated.
+func (s *Server) withRequestID(toolName string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = logging.ContextWithRequestID(ctx, logging.NewRequestID())
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			ctx = logging.ContextWithSessionID(ctx, session.SessionID())
+		}
+
+		logger := s.logger.WithContext(ctx)
+		logger.Debug("Tool call started", "tool", toolName)
+
+		result, err := next(ctx, request)
+
+		logger.Debug("Tool call finished", "tool", toolName, "is_error", result != nil && result.IsError)
+		return result, err
+	}
+}
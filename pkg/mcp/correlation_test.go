@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+func TestToolCallRequestIDs(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+
+	var buf bytes.Buffer
+	cfg := &config.Config{
+		APIToken: "test-token",
+		LogLevel: "debug",
+		Timeout:  30 * time.Second,
+		Endpoint: vendorAPI.URL,
+	}
+	server, err := NewServer(cfg, logging.NewLoggerWithWriter("debug", &buf))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	call := func() {
+		server.mcpServer.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_applications","arguments":{}}}`))
+	}
+
+	buf.Reset()
+	call()
+	call()
+
+	// Every line logged during a call carries that call's request ID, including API client lines
+	idsByMessage := make(map[string][]string)
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse log line: %v", err)
+		}
+		requestID, _ := entry[logging.RequestIDKey].(string)
+		if requestID == "" {
+			t.Errorf("Expected request_id on log line %s", scanner.Text())
+		}
+		message, _ := entry["msg"].(string)
+		idsByMessage[message] = append(idsByMessage[message], requestID)
+	}
+
+	for _, message := range []string{"Tool call started", "list_applications tool called", "Making API request"} {
+		ids := idsByMessage[message]
+		if len(ids) != 2 {
+			t.Fatalf("Expected '%s' to be logged once per call, got %d", message, len(ids))
+		}
+		if ids[0] == ids[1] {
+			t.Errorf("Expected each call to get its own request ID for '%s'", message)
+		}
+		if ids[0] != idsByMessage["Tool call started"][0] {
+			t.Errorf("Expected '%s' to share the first call's request ID", message)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// cursorArgument is the argument list tools read the cursor from
+const cursorArgument = "cursor"
+
+// listCursor is the position a cursor encodes. The tool is recorded so a cursor from one
+// list is not mistaken for a position in another.
+type listCursor struct {
+	Tool   string `json:"tool"`
+	Offset int    `json:"offset"`
+}
+
+// cursorParameter is the optional cursor argument for list tools
+func cursorParameter(noun string) mcp.ToolOption {
+	return mcp.WithString(cursorArgument,
+		mcp.Description("Opaque next_cursor from a previous call, to continue listing "+noun+
+			" where that page ended; omit for the first page"),
+	)
+}
+
+// encodeCursor returns the opaque cursor for a tool's list starting at offset
+func encodeCursor(tool string, offset int) string {
+	data, _ := json.Marshal(listCursor{Tool: tool, Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the offset a cursor from the tool encodes. An empty cursor starts at
+// the beginning.
+func decodeCursor(tool, cursor string) (int, error) {
+	if cursor == "" {
+		return minOffset, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor: not a next_cursor returned by %s", tool)
+	}
+	var position listCursor
+	if err := json.Unmarshal(data, &position); err != nil || position.Offset < minOffset {
+		return 0, fmt.Errorf("invalid cursor: not a next_cursor returned by %s", tool)
+	}
+	if position.Tool != tool {
+		return 0, fmt.Errorf("invalid cursor: the cursor was returned by %s, not %s", position.Tool, tool)
+	}
+	return position.Offset, nil
+}
+
+// pageOf returns the response holding the page of items selected by the request's cursor and
+// limit, with the cursor for the next page when items remain past it
+func pageOf[T any](request mcp.CallToolRequest, items []T) (envelope, error) {
+	tool := request.Params.Name
+	offset, err := decodeCursor(tool, request.GetString(cursorArgument, ""))
+	if err != nil {
+		return envelope{}, err
+	}
+
+	page := paginate(items, offset, request.GetInt("limit", maxListLimit))
+	response := listOf(page)
+	response.Pagination.Total = len(items)
+	if next := offset + len(page); len(page) > 0 && next < len(items) {
+		response.Pagination.NextCursor = encodeCursor(tool, next)
+	}
+	return response, nil
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name       string
+		cursor     string
+		wantOffset int
+		wantErr    string
+	}{
+		{name: "first page", cursor: "", wantOffset: 0},
+		{name: "round trip", cursor: encodeCursor("list_applications", 40), wantOffset: 40},
+		{name: "not base64", cursor: "not a cursor!", wantErr: "invalid cursor"},
+		{name: "not a position", cursor: "bm90IGpzb24", wantErr: "invalid cursor"},
+		{name: "negative offset", cursor: encodeCursor("list_applications", -1), wantErr: "invalid cursor"},
+		{
+			name:    "another tool's cursor",
+			cursor:  encodeCursor("list_support_bundles", 10),
+			wantErr: "returned by list_support_bundles, not list_applications",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, err := decodeCursor("list_applications", tt.cursor)
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if offset != tt.wantOffset {
+				t.Errorf("Expected offset %d, got %d", tt.wantOffset, offset)
+			}
+		})
+	}
+}
+
+func TestPageOf(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	// Following next_cursor visits every item once and stops after the last page
+	var seen []string
+	pages := 0
+	cursor := ""
+	for {
+		request := createMockCallToolRequest("list_applications",
+			map[string]any{"limit": float64(2), "cursor": cursor})
+		page, err := pageOf(request, items)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		seen = append(seen, page.Data.([]string)...)
+		pages++
+		if page.Pagination.Total != len(items) {
+			t.Errorf("Expected a total of %d, got %d", len(items), page.Pagination.Total)
+		}
+		if page.Pagination.NextCursor == "" {
+			break
+		}
+		cursor = page.Pagination.NextCursor
+	}
+
+	if !slices.Equal(seen, items) || pages != 3 {
+		t.Errorf("Expected all items over 3 pages, got %v over %d", seen, pages)
+	}
+
+	// A page that ends exactly at the last item has no next cursor
+	page, err := pageOf(createMockCallToolRequest("list_applications", map[string]any{"limit": float64(5)}), items)
+	if err != nil || page.Pagination.NextCursor != "" || page.Pagination.Returned != len(items) {
+		t.Errorf("Expected a single complete page, got %+v (%v)", page, err)
+	}
+}
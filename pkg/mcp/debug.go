@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+)
+
+// debugInfo is the _debug block embedded in tool results when developer mode is enabled.
+type debugInfo struct {
+	Tool       string              `json:"tool"`
+	RequestID  string              `json:"request_id,omitempty"`
+	DurationMS float64             `json:"duration_ms"`
+	Requests   []api.TracedRequest `json:"requests"`
+}
+
+// withDebugInfo wraps a tool handler so that its result carries a _debug block describing
+// the API requests made, their timing, cache status, and retries. It is only applied in
+// developer mode, so normal results never include debug details. Request URLs and errors
+// are redacted like logs, since they can carry emails or license IDs.
+func (s *Server) withDebugInfo(toolName string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		trace := &api.RequestTrace{}
+		start := time.Now()
+
+		result, err := next(api.WithRequestTrace(ctx, trace), request)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		info := debugInfo{
+			Tool:       toolName,
+			RequestID:  logging.RequestIDFromContext(ctx),
+			DurationMS: float64(time.Since(start).Microseconds()) / float64(time.Millisecond/time.Microsecond),
+			Requests:   redactTracedRequests(redactorFor(s.currentConfig()), trace.Requests()),
+		}
+
+		payload, marshalErr := json.Marshal(map[string]debugInfo{"_debug": info})
+		if marshalErr != nil {
+			s.logger.Error("Failed to encode debug information", "tool", toolName, "error", marshalErr)
+			return result, nil
+		}
+
+		result.Content = append(result.Content, mcp.NewTextContent(string(payload)))
+		return result, nil
+	}
+}
+
+// redactTracedRequests masks sensitive text in the URLs and errors of traced requests
+func redactTracedRequests(redactor *redact.Redactor, requests []api.TracedRequest) []api.TracedRequest {
+	for i := range requests {
+		requests[i].URL = redactor.String(requests[i].URL)
+		requests[i].Error = redactor.String(requests[i].Error)
+	}
+	return requests
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+func TestWithDebugInfo(t *testing.T) {
+	cfg := &config.Config{
+		APIToken: "test-token",
+		LogLevel: "info",
+		Timeout:  30 * time.Second,
+		DevMode:  true,
+	}
+	logger := logging.NewLogger("info")
+
+	server, err := NewServer(cfg, logger)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	handler := server.withDebugInfo("test_tool",
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			trace := api.RequestTraceFromContext(ctx)
+			if trace == nil {
+				t.Fatal("Expected request trace in handler context")
+			}
+			trace.Record(api.TracedRequest{Method: "GET", URL: "https://example.com/vendor/v3/apps", Status: 200})
+			trace.Record(api.TracedRequest{
+				Method: "GET", URL: "https://example.com/vendor/v3/customers?email=ops@acme.example", Status: 200,
+			})
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+	result, err := handler(context.Background(), createMockCallToolRequest("test_tool", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected result and debug content, got %d items", len(result.Content))
+	}
+
+	debugContent, ok := result.Content[1].(mcp.TextContent)
+	if !ok {
+		t.Fatal("Expected debug block to be TextContent")
+	}
+
+	var payload map[string]debugInfo
+	if err := json.Unmarshal([]byte(debugContent.Text), &payload); err != nil {
+		t.Fatalf("Failed to decode debug block: %v", err)
+	}
+
+	info, ok := payload["_debug"]
+	if !ok {
+		t.Fatalf("Expected _debug key, got %s", debugContent.Text)
+	}
+	if info.Tool != "test_tool" {
+		t.Errorf("Expected tool 'test_tool', got '%s'", info.Tool)
+	}
+	if len(info.Requests) != 2 || !strings.HasSuffix(info.Requests[0].URL, "/vendor/v3/apps") {
+		t.Errorf("Expected traced requests in debug block, got %+v", info.Requests)
+	}
+	if strings.Contains(debugContent.Text, "ops@acme.example") {
+		t.Errorf("Expected emails redacted from traced requests, got %s", debugContent.Text)
+	}
+}
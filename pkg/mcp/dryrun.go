@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// dryRunPreview is the result of a mutating tool call made in dry-run mode. The call's inputs
+// have been validated and the entities it names looked up, but nothing was changed. Action
+// describes the change in words and Preview holds the result the call would have returned.
+type dryRunPreview struct {
+	DryRun  bool   `json:"dry_run"`
+	Action  string `json:"action"`
+	Preview any    `json:"preview,omitempty"`
+}
+
+// dryRunParameter returns the dry_run parameter of mutating tools
+func dryRunParameter() mcp.ToolOption {
+	return mcp.WithBoolean("dry_run",
+		mcp.Description("Validate the call and describe the change it would make without making it "+
+			"(always on when the server runs in dry-run mode); confirmation_token is not needed"),
+	)
+}
+
+// dryRun reports whether a mutating tool call should only preview its change, either because
+// it set dry_run or because the server runs in dry-run mode. A call cannot opt out of the
+// server's mode.
+func (s *Server) dryRun(request mcp.CallToolRequest) bool {
+	return s.currentConfig().DryRun || request.GetBool("dry_run", false)
+}
+
+// dryRunResult returns the preview of a change a dry-run call would have made
+func dryRunResult(ctx context.Context, action string, preview any) (*mcp.CallToolResult, error) {
+	return jsonResult(ctx, dryRunPreview{DryRun: true, Action: "would " + action, Preview: preview})
+}
@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+)
+
+// newReadOnlyProxy serves a test vendor API's reads and fails the test on any request
+// that would change the vendor account
+func newReadOnlyProxy(t *testing.T, target string) *httptest.Server {
+	t.Helper()
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		t.Fatalf("Invalid test API URL: %v", err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Dry run sent %s %s to the API", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// decodeDryRunPreview decodes the preview in the response envelope of a dry-run call
+func decodeDryRunPreview(t *testing.T, text string) dryRunPreview {
+	t.Helper()
+
+	var response testEnvelope[dryRunPreview]
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		t.Fatalf("Failed to decode dry-run preview: %v", err)
+	}
+	if !response.Data.DryRun {
+		t.Fatalf("Expected a dry-run preview, got %s", text)
+	}
+	return response.Data
+}
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		vendorAPI   func(t *testing.T) string
+		toolName    string
+		args        map[string]any
+		wantAction  string
+		expectError bool
+	}{
+		{
+			name:       "promote a release",
+			vendorAPI:  func(t *testing.T) string { return newTestReleaseAPI(t).URL },
+			toolName:   "promote_release",
+			args:       map[string]any{"app_id": testAppID, "sequence": 2, "channel_id": "stable"},
+			wantAction: "would promote sequence 2 (1.2.0) to Stable",
+		},
+		{
+			name:       "archive a customer without confirm",
+			vendorAPI:  func(t *testing.T) string { return newTestArchiveAPI(t).URL },
+			toolName:   "archive_customer",
+			args:       map[string]any{"app_id": testAppID, "customer_id": "customer-1"},
+			wantAction: "would archive customer 'Acme' (customer-1)",
+		},
+		{
+			name:       "update a customer",
+			vendorAPI:  func(t *testing.T) string { return newTestUpdateAPI(t).URL },
+			toolName:   "update_customer",
+			args:       map[string]any{"app_id": testAppID, "customer_id": "customer-1", "license_type": "paid"},
+			wantAction: "would update license_type of customer 'Acme' (customer-1)",
+		},
+		{
+			name:      "set an entitlement",
+			vendorAPI: func(t *testing.T) string { return newTestEntitlementAPI(t).URL },
+			toolName:  "set_customer_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "field_name": "seat_count", "value": "30",
+			},
+			wantAction: "would set seat_count to '30' for customer customer-1",
+		},
+		{
+			name:      "reject an invalid entitlement value",
+			vendorAPI: func(t *testing.T) string { return newTestEntitlementAPI(t).URL },
+			toolName:  "set_customer_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "field_name": "seat_count", "value": "many",
+			},
+			expectError: true,
+		},
+		{
+			name:       "create a cluster",
+			vendorAPI:  func(t *testing.T) string { return newTestClusterAPI(t).URL },
+			toolName:   "create_cluster",
+			args:       map[string]any{"distribution": "k3s", "version": "1.30"},
+			wantAction: "would create a k3s 1.30 cluster that expires after 1h",
+		},
+		{
+			name:        "reject an invalid cluster",
+			vendorAPI:   func(t *testing.T) string { return newTestClusterAPI(t).URL },
+			toolName:    "create_cluster",
+			args:        map[string]any{"distribution": "k3s", "ttl": "forever"},
+			expectError: true,
+		},
+		{
+			name:       "delete a cluster without confirm",
+			vendorAPI:  func(t *testing.T) string { return newTestClusterAPI(t).URL },
+			toolName:   "delete_cluster",
+			args:       map[string]any{"cluster_id": "cluster-1"},
+			wantAction: "would delete cluster 'ci-1' (cluster-1)",
+		},
+		{
+			name:       "create a VM",
+			vendorAPI:  func(t *testing.T) string { return newTestVMAPI(t).URL },
+			toolName:   "create_vm",
+			args:       map[string]any{"distribution": "ubuntu"},
+			wantAction: "would create a ubuntu VM that expires after 1h",
+		},
+		{
+			name:       "delete a VM",
+			vendorAPI:  func(t *testing.T) string { return newTestVMAPI(t).URL },
+			toolName:   "delete_vm",
+			args:       map[string]any{"vm_id": "vm-1"},
+			wantAction: "would delete VM 'ec-1' (vm-1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, newReadOnlyProxy(t, tt.vendorAPI(t)).URL)
+
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+			if _, ok := tool.definition.InputSchema.Properties["dry_run"]; !ok {
+				t.Errorf("Expected %s to accept dry_run", tt.toolName)
+			}
+
+			args := map[string]any{"dry_run": true}
+			for name, value := range tt.args {
+				args[name] = value
+			}
+			result, err := tool.handler(context.Background(), createMockCallToolRequest(tt.toolName, args))
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected a validation error in dry-run mode")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			preview := decodeDryRunPreview(t, resultText(t, result))
+			if preview.Action != tt.wantAction {
+				t.Errorf("Expected action %q, got %q", tt.wantAction, preview.Action)
+			}
+			if preview.Preview == nil {
+				t.Error("Expected a preview of the result")
+			}
+		})
+	}
+}
+
+func TestDryRunServerMode(t *testing.T) {
+	server := newTestServer(t, newReadOnlyProxy(t, newTestClusterAPI(t).URL).URL)
+	server.config.DryRun = true
+
+	tool, ok := server.registry.Tool("delete_cluster")
+	if !ok {
+		t.Fatal("Tool 'delete_cluster' not found")
+	}
+
+	// Calls cannot opt out of the server's dry-run mode
+	args := map[string]any{"cluster_id": "cluster-1", "dry_run": false}
+	token, _, err := server.confirmations.issue("delete_cluster", args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	args[confirmationTokenArgument] = token
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("delete_cluster", args))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	preview := decodeDryRunPreview(t, resultText(t, result))
+	if preview.Action != "would delete cluster 'ci-1' (cluster-1)" {
+		t.Errorf("Unexpected action %q", preview.Action)
+	}
+}
+
+func TestDryRunBulk(t *testing.T) {
+	server := newTestServer(t, newReadOnlyProxy(t, newTestClusterAPI(t).URL).URL)
+
+	tool, ok := server.registry.Tool(bulkToolName)
+	if !ok {
+		t.Fatal("Tool 'bulk' not found")
+	}
+
+	args := map[string]any{
+		"dry_run": true,
+		"operations": []any{
+			map[string]any{"tool": "delete_cluster", "arguments": map[string]any{"cluster_id": "cluster-1"}},
+			map[string]any{"tool": "create_cluster", "arguments": map[string]any{"distribution": "kind"}},
+			map[string]any{"tool": "list_clusters"},
+		},
+	}
+	result, err := tool.handler(context.Background(), createMockCallToolRequest(bulkToolName, args))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	summary := decodeEnvelope[struct {
+		Succeeded int `json:"succeeded"`
+		Results   []struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"results"`
+	}](t, result).Data
+	if summary.Succeeded != len(summary.Results) || len(summary.Results) != 3 {
+		t.Fatalf("Expected all operations to succeed, got %s", resultText(t, result))
+	}
+	for _, item := range summary.Results[:2] {
+		decodeDryRunPreview(t, string(item.Result))
+	}
+}
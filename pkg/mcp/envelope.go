@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// dataField is the field of the response envelope holding a tool's result
+const dataField = "data"
+
+// envelope is the response of every tool. Data is the tool's result: the entity or report it
+// asked for, or the list of entities for tools that list or search them, so every list has
+// the same shape whatever it lists. Pagination describes a list, Warnings say what the agent
+// should know about how complete the data is, and SourceRequestID is the ID the call was
+// logged under, for matching the response to the server logs.
+type envelope struct {
+	Data            any         `json:"data"`
+	Pagination      *pagination `json:"pagination,omitempty"`
+	Warnings        []string    `json:"warnings,omitempty"`
+	SourceRequestID string      `json:"source_request_id,omitempty"`
+}
+
+// pagination describes the part of a list a response holds. Total counts every entity in the
+// list, including those beyond this page; when Truncated is set, entities beyond the search
+// scan limit were not read, so the total is a lower bound. NextCursor fetches the next page
+// of a list tool when entities remain past this one.
+type pagination struct {
+	Returned   int    `json:"returned"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
+// jsonResult returns a tool's result as the data of the response envelope
+func jsonResult(ctx context.Context, data any) (*mcp.CallToolResult, error) {
+	return envelopeResult(ctx, envelope{Data: data})
+}
+
+// envelopeResult returns a response as a tool result, with its request ID set. The envelope
+// is the result's structured content and, encoded as indented JSON, its text content.
+func envelopeResult(ctx context.Context, response envelope) (*mcp.CallToolResult, error) {
+	response.SourceRequestID = logging.RequestIDFromContext(ctx)
+
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	return mcp.NewToolResultStructured(response, string(data)), nil
+}
+
+// listOf returns the response holding a whole list
+func listOf[T any](items []T) envelope {
+	if items == nil {
+		items = []T{}
+	}
+	return envelope{Data: items, Pagination: &pagination{Returned: len(items), Total: len(items)}}
+}
+
+// firstPage returns the response holding the first limit matches of a search
+func firstPage[T any](matches []T, limit int) envelope {
+	response := listOf(paginate(matches, minOffset, limit))
+	response.Pagination.Total = len(matches)
+	return response
+}
+
+// warn adds a warning to a response
+func (e *envelope) warn(format string, args ...any) {
+	e.Warnings = append(e.Warnings, fmt.Sprintf(format, args...))
+}
+
+// truncate marks a listed response as missing the entities beyond the search scan limit
+func (e *envelope) truncate(noun string) {
+	if e.Pagination != nil {
+		e.Pagination.Truncated = true
+	}
+	e.warn("only the %s within the server's search scan limit (--search-scan-limit) were read, "+
+		"so the list and its total may be incomplete", noun)
+}
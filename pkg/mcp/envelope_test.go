@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// testEnvelope is a response envelope with data of a known type, as tests decode it
+type testEnvelope[T any] struct {
+	Data            T           `json:"data"`
+	Pagination      *pagination `json:"pagination"`
+	Warnings        []string    `json:"warnings"`
+	SourceRequestID string      `json:"source_request_id"`
+}
+
+// decodeEnvelope decodes the response envelope in a tool result's text content
+func decodeEnvelope[T any](t *testing.T, result *mcp.CallToolResult) testEnvelope[T] {
+	t.Helper()
+
+	var response testEnvelope[T]
+	if err := json.Unmarshal([]byte(resultText(t, result)), &response); err != nil {
+		t.Fatalf("Failed to decode response envelope: %v", err)
+	}
+	return response
+}
+
+// resultData returns the JSON data of the response envelope in a tool result
+func resultData(t *testing.T, result *mcp.CallToolResult) []byte {
+	t.Helper()
+	return decodeEnvelope[json.RawMessage](t, result).Data
+}
+
+func TestEnvelopeResult(t *testing.T) {
+	ctx := logging.ContextWithRequestID(context.Background(), "req-123")
+
+	tests := []struct {
+		name           string
+		response       envelope
+		wantData       string
+		wantPagination *pagination
+		wantWarnings   []string
+	}{
+		{
+			name:     "entity",
+			response: envelope{Data: map[string]string{"id": "app-1"}},
+			wantData: `{"id":"app-1"}`,
+		},
+		{
+			name:           "whole list",
+			response:       listOf([]string{"a", "b"}),
+			wantData:       `["a","b"]`,
+			wantPagination: &pagination{Returned: 2, Total: 2},
+		},
+		{
+			name:           "empty list",
+			response:       listOf[string](nil),
+			wantData:       `[]`,
+			wantPagination: &pagination{},
+		},
+		{
+			name:           "first page of search matches",
+			response:       firstPage([]string{"a", "b", "c"}, 2),
+			wantData:       `["a","b"]`,
+			wantPagination: &pagination{Returned: 2, Total: 3},
+		},
+		{
+			name: "truncated list",
+			response: func() envelope {
+				response := listOf([]string{"a"})
+				response.truncate("customers")
+				return response
+			}(),
+			wantData:       `["a"]`,
+			wantPagination: &pagination{Returned: 1, Total: 1, Truncated: true},
+			wantWarnings: []string{"only the customers within the server's search scan limit " +
+				"(--search-scan-limit) were read, so the list and its total may be incomplete"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := envelopeResult(ctx, tt.response)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			response := decodeEnvelope[json.RawMessage](t, result)
+			var data bytes.Buffer
+			if err := json.Compact(&data, response.Data); err != nil || data.String() != tt.wantData {
+				t.Errorf("Expected data %s, got %s", tt.wantData, response.Data)
+			}
+			if (response.Pagination == nil) != (tt.wantPagination == nil) ||
+				response.Pagination != nil && *response.Pagination != *tt.wantPagination {
+				t.Errorf("Expected pagination %+v, got %+v", tt.wantPagination, response.Pagination)
+			}
+			if !slices.Equal(response.Warnings, tt.wantWarnings) {
+				t.Errorf("Expected warnings %v, got %v", tt.wantWarnings, response.Warnings)
+			}
+			if response.SourceRequestID != "req-123" {
+				t.Errorf("Expected the request ID to be reported, got '%s'", response.SourceRequestID)
+			}
+			if _, ok := result.StructuredContent.(envelope); !ok {
+				t.Errorf("Expected the envelope as structured content, got %T", result.StructuredContent)
+			}
+		})
+	}
+}
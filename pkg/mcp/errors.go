@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// toolError is the structured content of a tool result reporting a failed call.
+// Agents can use the status code or, for requests that never got a response, the kind
+// (canceled, timeout, or deadline_exceeded) and the remediation hint to decide how to recover.
+// Promotions rejected by a channel's promotion policy have the kind policy_violation and
+// list each broken rule. Destructive calls made without a valid confirmation token have the
+// kind confirmation_required and carry the token that confirms them, calls with arguments
+// over the configured size limits have the kind arguments_too_large, and calls canceled at
+// their time limit have the kind tool_timeout.
+type toolError struct {
+	Error                 string                   `json:"error"`
+	RequestID             string                   `json:"request_id,omitempty"`
+	Kind                  string                   `json:"kind,omitempty"`
+	StatusCode            int                      `json:"status_code,omitempty"`
+	Message               string                   `json:"message,omitempty"`
+	Details               string                   `json:"details,omitempty"`
+	Violations            []models.PolicyViolation `json:"violations,omitempty"`
+	ConfirmationToken     string                   `json:"confirmation_token,omitempty"`
+	ConfirmationExpiresAt *time.Time               `json:"confirmation_expires_at,omitempty"`
+	Remediation           string                   `json:"remediation,omitempty"`
+}
+
+// policyViolationKind is the error kind for promotions rejected by a channel's promotion policy
+const policyViolationKind = "policy_violation"
+
+// policyViolationHint applies to promotions rejected by a channel's promotion policy
+const policyViolationHint = "fix each listed violation, such as by passing a semantic version_label or " +
+	"release_notes, then promote again; policies are set under channel_policies in the config file"
+
+// confirmationRequiredKind is the error kind for destructive calls that were not confirmed
+const confirmationRequiredKind = "confirmation_required"
+
+// confirmationRequiredHint applies to destructive calls that were not confirmed
+const confirmationRequiredHint = "confirm the change with the user, then call the tool again with the same " +
+	"arguments and confirmation_token set to the token in this result before it expires"
+
+// confirmationError reports a destructive tool call made without a valid confirmation token.
+// The action describes what the call would have done, so the agent can ask the user, and the
+// token confirms the same call made again. The reason explains why a token that was passed
+// was not accepted. The token is kept out of the message, which is logged.
+type confirmationError struct {
+	action    string
+	token     string
+	expiresAt time.Time
+	reason    string
+}
+
+// Error implements the error interface
+func (e *confirmationError) Error() string {
+	if e.reason != "" {
+		return e.action + " requires confirmation: " + e.reason
+	}
+	return e.action + " requires confirmation"
+}
+
+// Remediation hints for common Vendor Portal API status codes
+var remediationHints = map[int]string{
+	http.StatusBadRequest: "check the tool arguments; the API rejected the request as invalid",
+	http.StatusUnauthorized: "check REPLICATED_API_TOKEN (or --api-token); the token is missing, invalid, " +
+		"or expired",
+	http.StatusForbidden: "the API token does not have permission for this operation; use a token with " +
+		"access to this application",
+	http.StatusNotFound: "check the IDs or slugs in the arguments; use the list and search tools to find " +
+		"valid values",
+	http.StatusConflict:            "the resource changed concurrently; fetch it again and retry",
+	http.StatusUnprocessableEntity: "check the argument values against the API's validation rules",
+	http.StatusTooManyRequests:     "the API is rate limiting requests; wait before retrying",
+}
+
+// serverErrorHint applies to any 5xx response
+const serverErrorHint = "the Vendor Portal API is unavailable or failing; retry later"
+
+// Remediation hints for requests that were canceled or timed out before a response
+var requestErrorHints = map[api.RequestErrorKind]string{
+	api.RequestCanceled: "the request was canceled by the client; retry only if the result is still needed",
+	api.RequestTimeout: "the Vendor Portal API did not respond within the configured timeout; wait and retry, " +
+		"and if it keeps happening increase TIMEOUT (or --timeout)",
+	api.RequestDeadlineExceeded: "the call's deadline passed before the API responded; retry with a longer " +
+		"deadline or a narrower request",
+}
+
+// withErrorResults wraps a tool handler so that errors are reported to agents as tool
+// results with IsError set, rather than as opaque protocol errors. Vendor Portal API
+// errors carry their status code and a remediation hint.
+func (s *Server) withErrorResults(toolName string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err == nil {
+			return result, nil
+		}
+
+		s.logger.WithContext(ctx).Error("Tool call failed", "tool", toolName, "error", err)
+		return errorResult(ctx, err), nil
+	}
+}
+
+// errorResult converts an error into a tool result with IsError set. The text content
+// carries the same JSON payload as the structured content, since not every client
+// reads structured content. The request ID lets agents and operators match the failure
+// to the server logs.
+func errorResult(ctx context.Context, err error) *mcp.CallToolResult {
+	payload := describeError(err)
+	payload.RequestID = logging.RequestIDFromContext(ctx)
+
+	text := payload.Error
+	if data, marshalErr := json.MarshalIndent(payload, "", "  "); marshalErr == nil {
+		text = string(data)
+	}
+
+	result := mcp.NewToolResultStructured(payload, text)
+	result.IsError = true
+	return result
+}
+
+// describeError builds the structured error payload, extracting API error details when present
+func describeError(err error) toolError {
+	payload := toolError{Error: err.Error()}
+
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) {
+		payload.StatusCode = apiErr.StatusCode
+		payload.Message = apiErr.Message
+		payload.Details = apiErr.Details
+		payload.Remediation = remediationFor(apiErr.StatusCode)
+	}
+
+	var policyErr *models.PromotionPolicyError
+	if errors.As(err, &policyErr) {
+		payload.Kind = policyViolationKind
+		payload.Violations = policyErr.Violations
+		payload.Remediation = policyViolationHint
+	}
+
+	var confirmErr *confirmationError
+	if errors.As(err, &confirmErr) {
+		payload.Kind = confirmationRequiredKind
+		payload.ConfirmationToken = confirmErr.token
+		payload.ConfirmationExpiresAt = &confirmErr.expiresAt
+		payload.Remediation = confirmationRequiredHint
+	}
+
+	var limitErr *argumentLimitError
+	if errors.As(err, &limitErr) {
+		payload.Kind = argumentsTooLargeKind
+		payload.Remediation = argumentsTooLargeHint
+	}
+
+	if kind, ok := requestErrorKind(err); ok {
+		payload.Kind = string(kind)
+		payload.Remediation = requestErrorHints[kind]
+	}
+
+	var timeoutErr *toolTimeoutError
+	if errors.As(err, &timeoutErr) {
+		payload.Kind = toolTimeoutKind
+		payload.Remediation = toolTimeoutHint
+	}
+
+	return payload
+}
+
+// requestErrorKind classifies an error from a request that ended without a response. Context
+// errors raised outside the API client, such as while iterating results, are classified too.
+func requestErrorKind(err error) (api.RequestErrorKind, bool) {
+	var requestErr *api.RequestError
+	switch {
+	case errors.As(err, &requestErr):
+		return requestErr.Kind, true
+	case errors.Is(err, context.Canceled):
+		return api.RequestCanceled, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return api.RequestDeadlineExceeded, true
+	}
+	return "", false
+}
+
+// remediationFor returns a remediation hint for an API status code, or "" if there is none
+func remediationFor(statusCode int) string {
+	if hint, ok := remediationHints[statusCode]; ok {
+		return hint
+	}
+	if statusCode >= http.StatusInternalServerError {
+		return serverErrorHint
+	}
+	return ""
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestErrorResult(t *testing.T) {
+	tests := []struct {
+		name              string
+		err               error
+		expectStatus      int
+		expectKind        string
+		expectMessage     string
+		expectRemediation string
+		expectInText      string
+	}{
+		{
+			name: "unauthorized API error",
+			err: fmt.Errorf("failed to list applications: %w",
+				&api.Error{StatusCode: 401, Message: "Unauthorized"}),
+			expectStatus:      401,
+			expectMessage:     "Unauthorized",
+			expectRemediation: remediationHints[http.StatusUnauthorized],
+			expectInText:      "REPLICATED_API_TOKEN",
+		},
+		{
+			name: "not found API error",
+			err: fmt.Errorf("failed to get customer: %w",
+				&api.Error{StatusCode: 404, Message: "Not Found"}),
+			expectStatus:      404,
+			expectMessage:     "Not Found",
+			expectRemediation: remediationHints[http.StatusNotFound],
+			expectInText:      "failed to get customer",
+		},
+		{
+			name: "server error",
+			err: fmt.Errorf("API error: %w",
+				&api.Error{StatusCode: 503, Message: "Service Unavailable"}),
+			expectStatus:      503,
+			expectMessage:     "Service Unavailable",
+			expectRemediation: serverErrorHint,
+			expectInText:      "retry later",
+		},
+		{
+			name: "client timeout",
+			err: fmt.Errorf("request failed: %w", &api.RequestError{
+				Kind: api.RequestTimeout, Method: "GET", URL: "https://api.replicated.com/vendor/v3/apps",
+				Err: errors.New("Client.Timeout exceeded while awaiting headers"),
+			}),
+			expectKind:        string(api.RequestTimeout),
+			expectRemediation: requestErrorHints[api.RequestTimeout],
+			expectInText:      "--timeout",
+		},
+		{
+			name:              "caller cancellation",
+			err:               fmt.Errorf("failed to list applications: %w", context.Canceled),
+			expectKind:        string(api.RequestCanceled),
+			expectRemediation: requestErrorHints[api.RequestCanceled],
+			expectInText:      `"kind": "canceled"`,
+		},
+		{
+			name:              "caller deadline",
+			err:               fmt.Errorf("failed to list customers: %w", context.DeadlineExceeded),
+			expectKind:        string(api.RequestDeadlineExceeded),
+			expectRemediation: requestErrorHints[api.RequestDeadlineExceeded],
+			expectInText:      "longer deadline",
+		},
+		{
+			name: "promotion policy violation",
+			err: &models.PromotionPolicyError{Channel: "Stable", Violations: []models.PolicyViolation{
+				{Rule: models.PolicyRuleReleaseNotes, Message: "release notes are required"},
+			}},
+			expectKind:        policyViolationKind,
+			expectRemediation: policyViolationHint,
+			expectInText:      `"rule": "require_release_notes"`,
+		},
+		{
+			name: "unconfirmed destructive call",
+			err: fmt.Errorf("failed to archive: %w",
+				&confirmationError{action: "archiving customer 'Acme'", token: "token-1"}),
+			expectKind:        confirmationRequiredKind,
+			expectRemediation: confirmationRequiredHint,
+			expectInText:      `"confirmation_token": "token-1"`,
+		},
+		{
+			name:         "non-API error",
+			err:          fmt.Errorf("app_id is required"),
+			expectInText: "app_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := errorResult(context.Background(), tt.err)
+
+			if !result.IsError {
+				t.Error("Expected result to be marked as an error")
+			}
+
+			payload, ok := result.StructuredContent.(toolError)
+			if !ok {
+				t.Fatalf("Expected toolError structured content, got %T", result.StructuredContent)
+			}
+			if payload.StatusCode != tt.expectStatus {
+				t.Errorf("Expected status code %d, got %d", tt.expectStatus, payload.StatusCode)
+			}
+			if payload.Kind != tt.expectKind {
+				t.Errorf("Expected kind '%s', got '%s'", tt.expectKind, payload.Kind)
+			}
+			if payload.Message != tt.expectMessage {
+				t.Errorf("Expected message '%s', got '%s'", tt.expectMessage, payload.Message)
+			}
+			if payload.Remediation != tt.expectRemediation {
+				t.Errorf("Expected remediation '%s', got '%s'", tt.expectRemediation, payload.Remediation)
+			}
+
+			if text := resultText(t, result); !contains(text, tt.expectInText) {
+				t.Errorf("Expected text containing '%s', got '%s'", tt.expectInText, text)
+			}
+		})
+	}
+}
+
+func TestToolCallErrorsAreResults(t *testing.T) {
+	vendorAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message": "invalid token"}`))
+	}))
+	t.Cleanup(vendorAPI.Close)
+
+	server := newTestServer(t, vendorAPI.URL)
+
+	message := server.mcpServer.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_applications","arguments":{}}}`))
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("Failed to encode tools/call response: %v", err)
+	}
+
+	var response struct {
+		Result *struct {
+			IsError bool `json:"isError"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Failed to decode tools/call response: %v", err)
+	}
+
+	if response.Result == nil || len(response.Result.Content) == 0 {
+		t.Fatalf("Expected a tool result rather than a protocol error, got %s", data)
+	}
+	if !response.Result.IsError {
+		t.Error("Expected isError to be set")
+	}
+
+	var payload toolError
+	if err := json.Unmarshal([]byte(response.Result.Content[0].Text), &payload); err != nil {
+		t.Fatalf("Failed to decode error payload: %v", err)
+	}
+	if payload.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status code 401, got %d", payload.StatusCode)
+	}
+	if payload.Message != "invalid token" {
+		t.Errorf("Expected API message 'invalid token', got '%s'", payload.Message)
+	}
+	if payload.Remediation == "" {
+		t.Error("Expected a remediation hint")
+	}
+	if payload.RequestID == "" {
+		t.Error("Expected the request ID for log correlation")
+	}
+}
+
+func TestRemediationFor(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expectHint bool
+	}{
+		{statusCode: http.StatusBadRequest, expectHint: true},
+		{statusCode: http.StatusForbidden, expectHint: true},
+		{statusCode: http.StatusTooManyRequests, expectHint: true},
+		{statusCode: http.StatusBadGateway, expectHint: true},
+		{statusCode: http.StatusTeapot, expectHint: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			if hint := remediationFor(tt.statusCode); (hint != "") != tt.expectHint {
+				t.Errorf("remediationFor(%d) = '%s', expected hint: %t", tt.statusCode, hint, tt.expectHint)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fieldsArgument is the argument get_* and list_* tools read field paths from
+const fieldsArgument = "fields"
+
+// selectsFields reports whether a tool accepts the fields argument. Only tools that read
+// entities do, since their results are the ones large enough to be worth trimming.
+func selectsFields(name string) bool {
+	return strings.HasPrefix(name, "get_") || strings.HasPrefix(name, "list_")
+}
+
+// withFieldsParameter returns a copy of a tool with the fields argument added, for tools that
+// select fields. Other tools are returned unchanged.
+func withFieldsParameter(tool mcp.Tool) mcp.Tool {
+	if !selectsFields(tool.Name) {
+		return tool
+	}
+
+	tool.InputSchema.Properties = maps.Clone(tool.InputSchema.Properties)
+	mcp.WithArray(fieldsArgument,
+		mcp.Description("Only return these fields of the result's data, as dot-separated paths such as "+
+			`"name" or "entitlements.seat_count"; paths through a list apply to each element, so "id" `+
+			`selects the ID of every listed item. Pagination and warnings are always returned. Omit for the `+
+			`full result.`),
+		mcp.WithStringItems(),
+	)(&tool)
+	return tool
+}
+
+// withFieldSelection wraps the handler of a tool that selects fields so that, when a call
+// passes fields, its JSON result is projected down to them. Results that are not JSON, such
+// as reports, and error results are returned whole.
+func withFieldSelection(name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if !selectsFields(name) {
+		return next
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		paths, err := fieldPaths(request.GetStringSlice(fieldsArgument, nil))
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError || len(paths) == 0 {
+			return result, err
+		}
+		return projectResult(result, paths)
+	}
+}
+
+// fieldPaths splits each field path into its keys, along with the fields always kept
+func fieldPaths(fields []string) ([][]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	paths := make([][]string, 0, len(fields))
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+		for _, key := range path {
+			if key == "" {
+				return nil, fmt.Errorf("invalid field path '%s': use dot-separated field names", field)
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// projectResult projects the data of each text content holding a response envelope, leaving
+// its pagination and warnings whole. A result with structured content gets the projected
+// envelope as its structured content too.
+func projectResult(result *mcp.CallToolResult, paths [][]string) (*mcp.CallToolResult, error) {
+	for i, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var response map[string]any
+		if json.Unmarshal([]byte(text.Text), &response) != nil {
+			continue
+		}
+		data, ok := response[dataField]
+		if !ok {
+			continue
+		}
+		response[dataField] = project(data, paths)
+
+		projected, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tool result: %w", err)
+		}
+		text.Text = string(projected)
+		result.Content[i] = text
+		if result.StructuredContent != nil {
+			result.StructuredContent = response
+		}
+	}
+	return result, nil
+}
+
+// project keeps the parts of a decoded JSON value named by the paths. Lists are projected
+// element by element, and paths naming fields a value does not have select nothing.
+func project(value any, paths [][]string) any {
+	switch value := value.(type) {
+	case []any:
+		projected := make([]any, len(value))
+		for i, element := range value {
+			projected[i] = project(element, paths)
+		}
+		return projected
+	case map[string]any:
+		// Group the remaining keys by the field they continue into
+		nested := make(map[string][][]string)
+		whole := make(map[string]bool)
+		for _, path := range paths {
+			if len(path) == 1 {
+				whole[path[0]] = true
+			} else {
+				nested[path[0]] = append(nested[path[0]], path[1:])
+			}
+		}
+
+		projected := make(map[string]any)
+		for key, field := range value {
+			switch {
+			case whole[key]:
+				projected[key] = field
+			case nested[key] != nil && hasFields(field):
+				projected[key] = project(field, nested[key])
+			}
+		}
+		return projected
+	default:
+		return value
+	}
+}
+
+// hasFields reports whether a decoded JSON value is an object or list that paths can continue into
+func hasFields(value any) bool {
+	switch value.(type) {
+	case map[string]any, []any:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	customer := map[string]any{
+		"id":   "customer-1",
+		"name": "Acme",
+		"entitlements": map[string]any{
+			"seat_count": 30.0,
+			"expires_at": "2026-01-01",
+		},
+		"channels": []any{
+			map[string]any{"id": "stable", "name": "Stable"},
+			map[string]any{"id": "beta", "name": "Beta"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		value  any
+		fields []string
+		want   any
+	}{
+		{
+			name:   "top-level fields",
+			value:  customer,
+			fields: []string{"id", "name"},
+			want:   map[string]any{"id": "customer-1", "name": "Acme"},
+		},
+		{
+			name:   "nested field",
+			value:  customer,
+			fields: []string{"entitlements.seat_count"},
+			want:   map[string]any{"entitlements": map[string]any{"seat_count": 30.0}},
+		},
+		{
+			name:   "fields of each list element",
+			value:  customer,
+			fields: []string{"channels.id"},
+			want:   map[string]any{"channels": []any{map[string]any{"id": "stable"}, map[string]any{"id": "beta"}}},
+		},
+		{
+			name:   "whole field and a path into it",
+			value:  customer,
+			fields: []string{"entitlements", "entitlements.seat_count"},
+			want:   map[string]any{"entitlements": customer["entitlements"]},
+		},
+		{
+			name:   "missing fields select nothing",
+			value:  customer,
+			fields: []string{"email", "name.first"},
+			want:   map[string]any{},
+		},
+		{
+			name:   "top-level list",
+			value:  []any{customer},
+			fields: []string{"name"},
+			want:   []any{map[string]any{"name": "Acme"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := fieldPaths(tt.fields)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := project(tt.value, paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFieldPaths(t *testing.T) {
+	for _, fields := range [][]string{{""}, {"entitlements."}, {"a..b"}} {
+		if _, err := fieldPaths(fields); err == nil || !contains(err.Error(), "invalid field path") {
+			t.Errorf("Expected %q to be rejected, got %v", fields, err)
+		}
+	}
+
+	if paths, err := fieldPaths(nil); paths != nil || err != nil {
+		t.Errorf("Expected no paths without fields, got %v (%v)", paths, err)
+	}
+}
+
+func TestFieldSelection(t *testing.T) {
+	server := newTestServer(t, newTestVendorAPI(t).URL)
+
+	tests := []struct {
+		toolName    string
+		args        map[string]any
+		wantFields  bool
+		want        string
+		expectError bool
+	}{
+		{
+			toolName:   "list_applications",
+			args:       map[string]any{"fields": []any{"slug"}},
+			wantFields: true,
+			want:       `{"data":[{"slug":"test-app"}],"pagination":{"returned":1,"total":1}}`,
+		},
+		{
+			toolName:   "get_application",
+			args:       map[string]any{"app_id": testAppID, "fields": []any{"id", "name"}},
+			wantFields: true,
+			want:       `{"data":{"id":"test-app-123","name":"Test App"}}`,
+		},
+		{
+			toolName:    "get_application",
+			args:        map[string]any{"app_id": testAppID, "fields": []any{"name."}},
+			wantFields:  true,
+			expectError: true,
+		},
+		{
+			toolName: "search_applications",
+			args:     map[string]any{"query": "test", "fields": []any{"id"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toolName, func(t *testing.T) {
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+			registered := server.serverTool(tool)
+			if _, ok := registered.Tool.InputSchema.Properties[fieldsArgument]; ok != tt.wantFields {
+				t.Errorf("Expected fields argument = %t, got %t", tt.wantFields, ok)
+			}
+
+			result, err := registered.Handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.IsError != tt.expectError {
+				t.Fatalf("Expected error result = %t, got %s", tt.expectError, resultText(t, result))
+			}
+			if tt.want == "" {
+				return
+			}
+
+			var got map[string]any
+			var want any
+			if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			// The request ID differs between calls, so only its presence is checked
+			if got["source_request_id"] == nil {
+				t.Errorf("Expected the request ID to be kept, got %s", resultText(t, result))
+			}
+			delete(got, "source_request_id")
+			_ = json.Unmarshal([]byte(tt.want), &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %s, got %s", tt.want, resultText(t, result))
+			}
+		})
+	}
+}
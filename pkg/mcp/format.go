@@ -0,0 +1,294 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// formatArgument is the argument list tools read their output format from
+const formatArgument = "format"
+
+// Output formats for list tools
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatTable  = "table"
+)
+
+// itemsField is the field of a list result holding its rows, when the result is an object
+const itemsField = "items"
+
+// Table layout
+const (
+	maxCellWidth  = 40
+	tableMinWidth = 0
+	tableTabWidth = 0
+	tablePadding  = 2
+)
+
+// formatsOutput reports whether a tool accepts the format argument
+func formatsOutput(name string) bool {
+	return strings.HasPrefix(name, "list_")
+}
+
+// withFormatParameter returns a copy of a tool with the format argument added, for tools that
+// format their output. Other tools are returned unchanged.
+func withFormatParameter(tool mcp.Tool) mcp.Tool {
+	if !formatsOutput(tool.Name) {
+		return tool
+	}
+
+	tool.InputSchema.Properties = maps.Clone(tool.InputSchema.Properties)
+	mcp.WithString(formatArgument,
+		mcp.Description("How to write the result: json (default), ndjson with one listed item per line, "+
+			"or table with aligned plain-text columns; fields other than the list, such as pagination, "+
+			"follow the items"),
+		mcp.Enum(formatJSON, formatNDJSON, formatTable),
+	)(&tool)
+	return tool
+}
+
+// withResultShaping wraps a tool handler with the arguments that reshape its result: fields
+// are selected first, and the selection is then written in the requested format
+func withResultShaping(name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return withOutputFormat(name, withFieldSelection(name, next))
+}
+
+// withOutputFormat wraps the handler of a tool that formats its output so that a call asking
+// for ndjson or a table gets its JSON result rewritten in that format. Results that are not
+// JSON and error results are returned unchanged.
+func withOutputFormat(name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if !formatsOutput(name) {
+		return next
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format := request.GetString(formatArgument, formatJSON)
+		if format != formatJSON && format != formatNDJSON && format != formatTable {
+			return nil, fmt.Errorf("invalid format '%s': must be one of %s, %s, or %s",
+				format, formatJSON, formatNDJSON, formatTable)
+		}
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError || format == formatJSON {
+			return result, err
+		}
+
+		for i, content := range result.Content {
+			text, ok := content.(mcp.TextContent)
+			if !ok || !json.Valid([]byte(text.Text)) {
+				continue
+			}
+			formatted, err := formatList(json.RawMessage(text.Text), format)
+			if err != nil {
+				return nil, err
+			}
+			text.Text = formatted
+			result.Content[i] = text
+		}
+		return result, nil
+	}
+}
+
+// formatList writes a JSON list result as ndjson or a table. The rows are the result itself
+// when it is a list, or else its items field or only list field; any other fields of the
+// result are written after the rows.
+func formatList(data json.RawMessage, format string) (string, error) {
+	rows, rest, err := splitList(data)
+	if err != nil {
+		return "", err
+	}
+
+	if format == formatNDJSON {
+		return formatNDJSONRows(rows, rest)
+	}
+	return formatTableRows(rows, rest)
+}
+
+// splitList separates a list result into its rows and the result's other fields. The rows of
+// a response envelope are those of its data, followed by the data's other fields and then the
+// envelope's pagination, warnings, and request ID.
+func splitList(data json.RawMessage) ([]json.RawMessage, []field, error) {
+	var rows []json.RawMessage
+	if json.Unmarshal(data, &rows) == nil {
+		return rows, nil, nil
+	}
+
+	fields, err := objectFields(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode tool result: %w", err)
+	}
+
+	for i, f := range fields {
+		if f.key != dataField {
+			continue
+		}
+		rows, rest, err := splitList(f.value)
+		if err != nil {
+			return nil, nil, err
+		}
+		return rows, slices.Concat(rest, fields[:i], fields[i+1:]), nil
+	}
+
+	var lists []int
+	for i, f := range fields {
+		if f.key == itemsField {
+			lists = []int{i}
+			break
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(f.value), []byte("[")) {
+			lists = append(lists, i)
+		}
+	}
+	if len(lists) != 1 {
+		// Without one list to tabulate, the whole result is a single row
+		return []json.RawMessage{data}, nil, nil
+	}
+	listIndex := lists[0]
+
+	if err := json.Unmarshal(fields[listIndex].value, &rows); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode tool result: %w", err)
+	}
+	return rows, append(fields[:listIndex:listIndex], fields[listIndex+1:]...), nil
+}
+
+// field is one field of a JSON object, in the order it appeared
+type field struct {
+	key   string
+	value json.RawMessage
+}
+
+// objectFields returns the fields of a JSON object in order. Decoding into a map would lose
+// the order, which tables keep as their column order.
+func objectFields(data json.RawMessage) ([]field, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	var fields []field
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		fields = append(fields, field{key: key, value: value})
+	}
+	return fields, nil
+}
+
+// formatNDJSONRows writes each row as compact JSON on its own line, followed by an object
+// holding the result's other fields
+func formatNDJSONRows(rows []json.RawMessage, rest []field) (string, error) {
+	var out bytes.Buffer
+	for _, row := range rows {
+		if err := json.Compact(&out, row); err != nil {
+			return "", fmt.Errorf("failed to encode tool result: %w", err)
+		}
+		out.WriteByte('\n')
+	}
+
+	if len(rest) > 0 {
+		out.WriteByte('{')
+		for i, f := range rest {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			key, _ := json.Marshal(f.key)
+			out.Write(key)
+			out.WriteByte(':')
+			if err := json.Compact(&out, f.value); err != nil {
+				return "", fmt.Errorf("failed to encode tool result: %w", err)
+			}
+		}
+		out.WriteString("}\n")
+	}
+	return out.String(), nil
+}
+
+// formatTableRows writes the rows as aligned columns, one per field in the order the fields
+// first appear, followed by a "key: value" line for each of the result's other fields. Rows
+// that are not objects are written in a single value column.
+func formatTableRows(rows []json.RawMessage, rest []field) (string, error) {
+	var columns []string
+	seen := make(map[string]bool)
+	cells := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		fields, err := objectFields(row)
+		if err != nil {
+			fields = []field{{key: "value", value: row}}
+		}
+		cells[i] = make(map[string]string, len(fields))
+		for _, f := range fields {
+			if !seen[f.key] {
+				seen[f.key] = true
+				columns = append(columns, f.key)
+			}
+			cells[i][f.key] = cellText(f.value)
+		}
+	}
+
+	var out strings.Builder
+	if len(columns) > 0 {
+		var aligned bytes.Buffer
+		table := tabwriter.NewWriter(&aligned, tableMinWidth, tableTabWidth, tablePadding, ' ', 0)
+		fmt.Fprintln(table, strings.Join(columns, "\t"))
+		for _, row := range cells {
+			values := make([]string, len(columns))
+			for i, column := range columns {
+				values[i] = row[column]
+			}
+			fmt.Fprintln(table, strings.Join(values, "\t"))
+		}
+		if err := table.Flush(); err != nil {
+			return "", fmt.Errorf("failed to write table: %w", err)
+		}
+
+		// Padding after the last non-empty cell only bloats the output
+		for _, line := range strings.SplitAfter(aligned.String(), "\n") {
+			if line != "" {
+				out.WriteString(strings.TrimRight(line, " \n") + "\n")
+			}
+		}
+	}
+
+	for _, f := range rest {
+		fmt.Fprintf(&out, "%s: %s\n", f.key, cellText(f.value))
+	}
+	return out.String(), nil
+}
+
+// cellText is the table text for a JSON value: strings without quotes, nothing for null, and
+// other values as compact JSON, cut off at the maximum cell width
+func cellText(value json.RawMessage) string {
+	var text string
+	if json.Unmarshal(value, &text) != nil {
+		var compact bytes.Buffer
+		if json.Compact(&compact, value) == nil {
+			text = compact.String()
+		}
+		if text == "null" {
+			text = ""
+		}
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxCellWidth {
+		text = string(runes[:maxCellWidth-1]) + "…"
+	}
+	return text
+}
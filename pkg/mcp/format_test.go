@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatList(t *testing.T) {
+	page := `{
+		"items": [
+			{"id": "app-1", "name": "Alpha", "is_active": true, "labels": null},
+			{"id": "app-2", "name": "A much longer application name that runs on and on", "is_active": false}
+		],
+		"next_cursor": "abc"
+	}`
+
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   string
+	}{
+		{
+			name:   "ndjson page",
+			data:   page,
+			format: formatNDJSON,
+			want: `{"id":"app-1","name":"Alpha","is_active":true,"labels":null}
+{"id":"app-2","name":"A much longer application name that runs on and on","is_active":false}
+{"next_cursor":"abc"}
+`,
+		},
+		{
+			name:   "table page",
+			data:   page,
+			format: formatTable,
+			want: `id     name                                      is_active  labels
+app-1  Alpha                                     true
+app-2  A much longer application name that run…  false
+next_cursor: abc
+`,
+		},
+		{
+			name:   "table of an envelope",
+			data:   `{"data": [{"id": "app-1"}], "pagination": {"returned": 1, "total": 1}, "warnings": ["stale"]}`,
+			format: formatTable,
+			want:   "id\napp-1\npagination: {\"returned\":1,\"total\":1}\nwarnings: [\"stale\"]\n",
+		},
+		{
+			name:   "ndjson list",
+			data:   `[{"id": "a"}, {"id": "b"}]`,
+			format: formatNDJSON,
+			want:   "{\"id\":\"a\"}\n{\"id\":\"b\"}\n",
+		},
+		{
+			name:   "table of the only list field",
+			data:   `{"clusters": [{"id": "c-1", "status": "running"}], "total": 1}`,
+			format: formatTable,
+			want:   "id   status\nc-1  running\ntotal: 1\n",
+		},
+		{
+			name:   "table of values",
+			data:   `["1.29", "1.30"]`,
+			format: formatTable,
+			want:   "value\n1.29\n1.30\n",
+		},
+		{
+			name:   "object without one list",
+			data:   `{"id": "x", "tags": ["a"], "notes": ["b"]}`,
+			format: formatTable,
+			want:   "id  tags   notes\nx   [\"a\"]  [\"b\"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatList(json.RawMessage(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	server := newTestServer(t, newTestVendorAPI(t).URL)
+
+	tests := []struct {
+		toolName    string
+		args        map[string]any
+		wantFormat  bool
+		wantPrefix  string
+		expectError bool
+	}{
+		{
+			toolName:   "list_applications",
+			args:       map[string]any{"format": formatTable, "fields": []any{"id", "slug"}},
+			wantFormat: true,
+			wantPrefix: "id            slug\ntest-app-123  test-app\n",
+		},
+		{
+			toolName:   "list_applications",
+			args:       map[string]any{"format": formatNDJSON},
+			wantFormat: true,
+			wantPrefix: `{"id":"test-app-123"`,
+		},
+		{
+			toolName:    "list_applications",
+			args:        map[string]any{"format": "xml"},
+			wantFormat:  true,
+			expectError: true,
+		},
+		{
+			toolName:   "get_application",
+			args:       map[string]any{"app_id": testAppID, "format": formatTable},
+			wantPrefix: "{\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toolName, func(t *testing.T) {
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+			registered := server.serverTool(tool)
+			if _, ok := registered.Tool.InputSchema.Properties[formatArgument]; ok != tt.wantFormat {
+				t.Errorf("Expected format argument = %t, got %t", tt.wantFormat, ok)
+			}
+
+			result, err := registered.Handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.IsError != tt.expectError {
+				t.Fatalf("Expected error result = %t, got %s", tt.expectError, resultText(t, result))
+			}
+			if text := resultText(t, result); !tt.expectError && !strings.HasPrefix(text, tt.wantPrefix) {
+				t.Errorf("Expected output starting with %q, got %q", tt.wantPrefix, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// argumentsTooLargeKind is the error kind for tool calls whose arguments exceed the
+// configured size limits
+const argumentsTooLargeKind = "arguments_too_large"
+
+// argumentsTooLargeHint applies to tool calls whose arguments exceed the configured size limits
+const argumentsTooLargeHint = "send less data in each call, such as by splitting a long list across several " +
+	"calls; the limits are set with --max-argument-bytes and --max-array-length"
+
+// argumentLimitError reports a tool call argument that exceeds a configured size limit
+type argumentLimitError struct {
+	argument string
+	problem  string
+}
+
+// Error implements the error interface
+func (e *argumentLimitError) Error() string {
+	return fmt.Sprintf("argument '%s' %s", e.argument, e.problem)
+}
+
+// withArgumentLimits wraps a tool handler so that calls whose arguments exceed the configured
+// size limits fail before the handler runs. Limits are read on each call, so reconfiguring
+// the server applies them to tools that are already registered.
+func (s *Server) withArgumentLimits(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg := s.currentConfig()
+		if err := checkArgumentLimits(request.GetArguments(), cfg.MaxArgumentBytes, cfg.MaxArrayLength); err != nil {
+			return nil, err
+		}
+		return next(ctx, request)
+	}
+}
+
+// checkArgumentLimits checks each argument's JSON-encoded size against maxBytes and the
+// lists it contains against maxArrayLength. A limit of zero is not checked.
+func checkArgumentLimits(arguments map[string]any, maxBytes, maxArrayLength int) error {
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		value := arguments[name]
+
+		if maxBytes > 0 {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("argument '%s' cannot be encoded as JSON: %w", name, err)
+			}
+			if len(data) > maxBytes {
+				return &argumentLimitError{argument: name,
+					problem: fmt.Sprintf("is %d bytes, more than the limit of %d bytes", len(data), maxBytes)}
+			}
+		}
+
+		if maxArrayLength > 0 {
+			if length := longestArray(value); length > maxArrayLength {
+				return &argumentLimitError{argument: name,
+					problem: fmt.Sprintf("has a list of %d items, more than the limit of %d", length, maxArrayLength)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// longestArray returns the length of the longest list in a decoded JSON value, including
+// lists nested in objects and other lists
+func longestArray(value any) int {
+	longest := 0
+	switch v := value.(type) {
+	case []any:
+		longest = len(v)
+		for _, item := range v {
+			longest = max(longest, longestArray(item))
+		}
+	case map[string]any:
+		for _, item := range v {
+			longest = max(longest, longestArray(item))
+		}
+	}
+	return longest
+}
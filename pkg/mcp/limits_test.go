@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckArgumentLimits(t *testing.T) {
+	items := func(n int) []any {
+		list := make([]any, n)
+		for i := range list {
+			list[i] = i
+		}
+		return list
+	}
+
+	tests := []struct {
+		name           string
+		arguments      map[string]any
+		maxBytes       int
+		maxArrayLength int
+		errContains    string
+	}{
+		{
+			name:           "within limits",
+			arguments:      map[string]any{"app_id": "app-1", "ids": items(10)},
+			maxBytes:       100,
+			maxArrayLength: 10,
+		},
+		{
+			name:        "argument too large",
+			arguments:   map[string]any{"notes": strings.Repeat("x", 200)},
+			maxBytes:    100,
+			errContains: "argument 'notes' is 202 bytes, more than the limit of 100 bytes",
+		},
+		{
+			name:           "list too long",
+			arguments:      map[string]any{"ids": items(11)},
+			maxArrayLength: 10,
+			errContains:    "argument 'ids' has a list of 11 items, more than the limit of 10",
+		},
+		{
+			name: "nested list too long",
+			arguments: map[string]any{"operations": []any{
+				map[string]any{"tool": "bulk", "arguments": map[string]any{"operations": items(25)}},
+			}},
+			maxArrayLength: 10,
+			errContains:    "argument 'operations' has a list of 25 items",
+		},
+		{
+			name:      "no limits",
+			arguments: map[string]any{"notes": strings.Repeat("x", 200), "ids": items(5000)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkArgumentLimits(tt.arguments, tt.maxBytes, tt.maxArrayLength)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("Expected error containing '%s', got %v", tt.errContains, err)
+			}
+			var limitErr *argumentLimitError
+			if !errors.As(err, &limitErr) {
+				t.Errorf("Expected an argumentLimitError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestToolCallArgumentLimits(t *testing.T) {
+	server := newTestServer(t, "")
+
+	cfg := *server.currentConfig()
+	cfg.MaxArgumentBytes = 64
+	if err := server.Reconfigure(&cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	message := server.mcpServer.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_applications",`+
+			`"arguments":{"query":"%s"}}}`, strings.Repeat("x", 100))))
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("Failed to encode tools/call response: %v", err)
+	}
+
+	var response struct {
+		Result *struct {
+			IsError bool `json:"isError"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Failed to decode tools/call response: %v", err)
+	}
+	if response.Result == nil || len(response.Result.Content) == 0 || !response.Result.IsError {
+		t.Fatalf("Expected an error result, got %s", data)
+	}
+
+	var payload toolError
+	if err := json.Unmarshal([]byte(response.Result.Content[0].Text), &payload); err != nil {
+		t.Fatalf("Failed to decode error payload: %v", err)
+	}
+	if payload.Kind != argumentsTooLargeKind {
+		t.Errorf("Expected kind '%s', got '%s'", argumentsTooLargeKind, payload.Kind)
+	}
+	if !strings.Contains(payload.Error, "argument 'query' is 102 bytes") {
+		t.Errorf("Expected the oversized argument to be named, got '%s'", payload.Error)
+	}
+	if payload.Remediation != argumentsTooLargeHint {
+		t.Errorf("Expected the arguments too large hint, got '%s'", payload.Remediation)
+	}
+}
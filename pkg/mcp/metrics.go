@@ -0,0 +1,27 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/metrics"
+)
+
+// Metrics returns the registry tracking tool calls and API requests for this server
+func (s *Server) Metrics() *metrics.Registry {
+	return s.metrics
+}
+
+// withMetrics wraps a tool handler so that each call's outcome and latency are recorded.
+// Calls that return an error or an error result count as failures.
+func (s *Server) withMetrics(toolName string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		s.metrics.ObserveToolCall(toolName, time.Since(start), err != nil || result != nil && result.IsError)
+		return result, err
+	}
+}
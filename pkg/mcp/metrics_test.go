@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestToolCallMetrics(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	calls := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_applications","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_application","arguments":{}}}`,
+	}
+	for _, call := range calls {
+		server.mcpServer.HandleMessage(context.Background(), json.RawMessage(call))
+	}
+
+	var buf bytes.Buffer
+	if err := server.Metrics().WriteText(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		`replicated_mcp_tool_calls_total{tool="list_applications",outcome="success"} 1`,
+		`replicated_mcp_tool_calls_total{tool="get_application",outcome="error"} 1`,
+		`replicated_mcp_api_requests_total{method="GET",status="200"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, output)
+		}
+	}
+}
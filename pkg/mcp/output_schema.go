@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Structured tool output
+//
+// Tools return their response envelope both as text and as structured content, and declare
+// the schema of the structured content so typed clients can decode it without guessing its
+// shape.
+
+// outputSchema returns a tool option declaring the schema of the tool's structured content:
+// the response envelope, with the schema of T, the type of the tool's data, as its data. The
+// schema is relaxed to describe every response the tool can return: no field is required,
+// since fields can be selected with fields and empty ones omitted; any field can be null; and
+// fields the type does not declare, such as the _truncated marker of a result cut to the size
+// limit, are allowed.
+func outputSchema[T any]() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		schema, ok := reflectSchema[envelope]()
+		if !ok {
+			return
+		}
+		data, ok := reflectSchema[T]()
+		if !ok {
+			return
+		}
+		allowNull(data)
+
+		properties, _ := schema["properties"].(map[string]any)
+		if properties == nil {
+			return
+		}
+		properties[dataField] = data
+		setOutputSchema(tool, schema)
+	}
+}
+
+// alternateOutputSchema returns a tool option adding the fields of T to the data schema of a
+// tool whose data is a T instead of its usual type in some calls, such as the preview of a
+// dry-run call, or whose listed items are, such as the ranked results of a search answered
+// from the index. It must follow the tool's outputSchema option; fields the schema already
+// has are kept.
+func alternateOutputSchema[T any]() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		var schema map[string]any
+		if json.Unmarshal(tool.RawOutputSchema, &schema) != nil {
+			return
+		}
+		alternate, ok := reflectSchema[T]()
+		if !ok {
+			return
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		target, _ := properties[dataField].(map[string]any)
+		if target == nil {
+			return
+		}
+		if kinds, _ := target["type"].([]any); slices.Contains(kinds, any("array")) {
+			if target, _ = target["items"].(map[string]any); target == nil {
+				return
+			}
+		}
+
+		merged, _ := target["properties"].(map[string]any)
+		if merged == nil {
+			merged = map[string]any{}
+		}
+		added, _ := alternate["properties"].(map[string]any)
+		for name, property := range added {
+			if _, exists := merged[name]; !exists {
+				merged[name] = property
+			}
+		}
+		target["properties"] = merged
+		setOutputSchema(tool, schema)
+	}
+}
+
+// reflectSchema generates the relaxed schema of a value of type T
+func reflectSchema[T any]() (map[string]any, bool) {
+	var generated mcp.Tool
+	mcp.WithOutputSchema[T]()(&generated)
+
+	var schema map[string]any
+	if json.Unmarshal(generated.RawOutputSchema, &schema) != nil {
+		return nil, false
+	}
+	relaxSchema(schema)
+	return schema, true
+}
+
+// setOutputSchema sets a tool's output schema
+func setOutputSchema(tool *mcp.Tool, schema map[string]any) {
+	if data, err := json.Marshal(schema); err == nil {
+		tool.RawOutputSchema = data
+	}
+}
+
+// relaxSchema removes the required fields of a generated schema and of every schema nested
+// in it, and lets each typed value nested in it be null
+func relaxSchema(schema map[string]any) {
+	delete(schema, "required")
+	for _, key := range []string{"properties", "patternProperties"} {
+		nested, _ := schema[key].(map[string]any)
+		for _, value := range nested {
+			if property, ok := value.(map[string]any); ok {
+				relaxSchema(property)
+				allowNull(property)
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties"} {
+		if nested, ok := schema[key].(map[string]any); ok {
+			relaxSchema(nested)
+			allowNull(nested)
+		}
+	}
+}
+
+// allowNull lets a schema with a single type also match null, which is how JSON encodes nil
+// slices, maps, and pointers
+func allowNull(schema map[string]any) {
+	if kind, ok := schema["type"].(string); ok && kind != "null" {
+		schema["type"] = []any{kind, "null"}
+	}
+}
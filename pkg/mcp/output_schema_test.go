@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// schemaExample is a result type exercising the parts of a generated schema that are relaxed
+type schemaExample struct {
+	Name     string            `json:"name"`
+	Count    int               `json:"count"`
+	Labels   map[string]string `json:"labels"`
+	Children []schemaChild     `json:"children"`
+}
+
+// schemaChild is an element of a list in schemaExample
+type schemaChild struct {
+	Count int `json:"count"`
+}
+
+// schemaAlternate is a result a tool returns instead of schemaExample in some calls
+type schemaAlternate struct {
+	Name    int  `json:"name"`
+	Preview bool `json:"preview"`
+}
+
+// conformsTo checks a decoded JSON value against the parts of a JSON schema the generated
+// output schemas use: types, object properties, and list items
+func conformsTo(value any, schema map[string]any, path string) error {
+	var types []any
+	switch kind := schema["type"].(type) {
+	case string:
+		types = []any{kind}
+	case []any:
+		types = kind
+	}
+	kind := jsonType(value)
+	if len(types) > 0 && !slices.Contains(types, any(kind)) &&
+		!(kind == "integer" && slices.Contains(types, any("number"))) {
+		return fmt.Errorf("%s: expected %v, got %s", path, types, kind)
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for key, field := range value {
+			property, ok := properties[key].(map[string]any)
+			if !ok {
+				continue
+			}
+			if err := conformsTo(field, property, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		for i, element := range value {
+			if err := conformsTo(element, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonType names the JSON schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func decodeSchema(t *testing.T, tool *mcp.Tool) map[string]any {
+	t.Helper()
+
+	var schema map[string]any
+	if err := json.Unmarshal(tool.RawOutputSchema, &schema); err != nil {
+		t.Fatalf("Failed to decode output schema of %s: %v", tool.Name, err)
+	}
+	return schema
+}
+
+func TestOutputSchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []mcp.ToolOption
+		value       any
+		expectError bool
+	}{
+		{
+			name:    "object result",
+			options: []mcp.ToolOption{outputSchema[schemaExample]()},
+			value:   map[string]any{"name": "acme", "children": []any{map[string]any{"count": float64(2)}}},
+		},
+		{
+			name:    "null fields",
+			options: []mcp.ToolOption{outputSchema[schemaExample]()},
+			value:   map[string]any{"labels": nil, "children": nil},
+		},
+		{
+			name:    "list result",
+			options: []mcp.ToolOption{outputSchema[[]schemaExample]()},
+			value:   []any{map[string]any{"name": "acme"}},
+		},
+		{
+			name:        "mistyped field",
+			options:     []mcp.ToolOption{outputSchema[schemaExample]()},
+			value:       map[string]any{"count": "two"},
+			expectError: true,
+		},
+		{
+			name:    "alternate result",
+			options: []mcp.ToolOption{outputSchema[schemaExample](), alternateOutputSchema[schemaAlternate]()},
+			value:   map[string]any{"preview": true},
+		},
+		{
+			name:        "alternate keeps existing fields",
+			options:     []mcp.ToolOption{outputSchema[schemaExample](), alternateOutputSchema[schemaAlternate]()},
+			value:       map[string]any{"name": float64(1)},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := mcp.NewTool("example", tt.options...)
+			schema := decodeSchema(t, &tool)
+
+			if schema["type"] != "object" {
+				t.Errorf("Expected an object schema, got %v", schema["type"])
+			}
+			if data := string(tool.RawOutputSchema); contains(data, `"required"`) {
+				t.Errorf("Expected no required fields, got %s", data)
+			}
+
+			properties, _ := schema["properties"].(map[string]any)
+			data, ok := properties[dataField].(map[string]any)
+			if !ok {
+				t.Fatalf("Expected the envelope's data in the schema, got %s", tool.RawOutputSchema)
+			}
+			err := conformsTo(tt.value, data, "data")
+			if tt.expectError != (err != nil) {
+				t.Errorf("Expected error = %t, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestToolOutputSchemas(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	for _, tool := range server.registry.Tools() {
+		if len(tool.definition.RawOutputSchema) == 0 {
+			t.Errorf("Expected tool %s to declare an output schema", tool.definition.Name)
+			continue
+		}
+		if schema := decodeSchema(t, tool.definition); schema["type"] != "object" {
+			t.Errorf("Expected tool %s to declare an object schema, got %v", tool.definition.Name, schema["type"])
+		}
+	}
+}
+
+func TestToolStructuredContent(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+
+	tests := []struct {
+		name        string
+		toolName    string
+		args        map[string]any
+		searchIndex bool
+	}{
+		{name: "page", toolName: "list_applications", args: map[string]any{}},
+		{name: "entity", toolName: "get_application", args: map[string]any{"app_id": testAppID}},
+		{name: "list", toolName: "search_channels", args: map[string]any{"app_id": testAppID, "query": "stable"}},
+		{name: "channel page", toolName: "list_channels", args: map[string]any{"app_id": testAppID}},
+		{name: "channel", toolName: "get_channel", args: map[string]any{"app_id": testAppID, "channel_id": "stable"}},
+		{
+			name:     "customer",
+			toolName: "get_customer",
+			args:     map[string]any{"app_id": testAppID, "customer_id": "customer-1"},
+		},
+		{
+			name:        "ranked search",
+			toolName:    "search_channels",
+			args:        map[string]any{"app_id": testAppID, "query": "stable"},
+			searchIndex: true,
+		},
+		{
+			name:     "selected fields",
+			toolName: "list_customers",
+			args:     map[string]any{"app_id": testAppID, "fields": []any{"name"}},
+		},
+		{name: "table format", toolName: "list_customers", args: map[string]any{"app_id": testAppID, "format": "table"}},
+		{
+			name:     "dry run",
+			toolName: "create_application",
+			args:     map[string]any{"name": "New App", "dry_run": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, vendorAPI.URL)
+			server.config.SearchIndex = tt.searchIndex
+
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+			registered := server.serverTool(tool)
+
+			result, err := registered.Handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("Unexpected error result: %s", resultText(t, result))
+			}
+
+			data, err := json.Marshal(result.StructuredContent)
+			if err != nil {
+				t.Fatalf("Failed to encode structured content: %v", err)
+			}
+			var content any
+			if err := json.Unmarshal(data, &content); err != nil {
+				t.Fatalf("Failed to decode structured content: %v", err)
+			}
+			if _, ok := content.(map[string]any); !ok {
+				t.Fatalf("Expected structured content to be an object, got %s", data)
+			}
+
+			if err := conformsTo(content, decodeSchema(t, &registered.Tool), "result"); err != nil {
+				t.Errorf("Structured content %s does not match the output schema: %v", data, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+)
+
+// Tool categories, usable in the enabled and disabled tool lists alongside tool names
+const (
+	categoryApplications   = "applications"
+	categoryReleases       = "releases"
+	categoryChannels       = "channels"
+	categoryCustomers      = "customers"
+	categorySupportBundles = "support_bundles"
+	categoryEntitlements   = "entitlements"
+	categoryClusters       = "clusters"
+	categorySnapshots      = "snapshots"
+	categoryJobs           = "jobs"
+	categoryBulk           = "bulk"
+	categorySearch         = "search"
+	categoryTags           = "tags"
+	categoryNotes          = "notes"
+	categoryServer         = "server"
+)
+
+// exposedTools returns the registry's tool definitions that the current configuration
+// permits clients to see, in registry order
+func (s *Server) exposedTools() []toolDefinition {
+	tools := s.registry.Tools()
+
+	exposed := make([]toolDefinition, 0, len(tools))
+	for _, tool := range tools {
+		if s.toolPermitted(tool) {
+			exposed = append(exposed, tool)
+			continue
+		}
+		s.logger.Debug("Tool withheld by policy", "name", tool.definition.Name)
+	}
+	return exposed
+}
+
+// exposedResources returns the registry's resource definitions that the current
+// configuration permits clients to see, in registry order
+func (s *Server) exposedResources() []resourceDefinition {
+	resources := s.registry.Resources()
+
+	exposed := make([]resourceDefinition, 0, len(resources))
+	for _, resource := range resources {
+		if s.resourcePermitted(resource) {
+			exposed = append(exposed, resource)
+			continue
+		}
+		s.logger.Debug("Resource withheld by policy", "uri", resource.definition.URI)
+	}
+	return exposed
+}
+
+// toolPermitted reports whether the current configuration allows a tool to be exposed.
+// When an enabled list is configured, only tools named there or in a listed category are
+// exposed; tools named in the disabled list or in a disabled category are never exposed.
+// In read-only mode, tools that modify the vendor account are never registered, and tools
+// that run local commands are registered only when cluster commands are enabled.
+func (s *Server) toolPermitted(tool toolDefinition) bool {
+	cfg := s.currentConfig()
+
+	if tool.mutating && cfg.ReadOnly {
+		return false
+	}
+	if tool.runsCommands && !cfg.ClusterCommands {
+		return false
+	}
+
+	selectors := []string{tool.definition.Name}
+	if tool.category != "" {
+		selectors = append(selectors, tool.category)
+	}
+	return selectionPermitted(cfg, selectors...)
+}
+
+// resourcePermitted reports whether the current configuration allows a resource to be
+// exposed. Resources follow the enabled and disabled lists for their tool category.
+func (s *Server) resourcePermitted(resource resourceDefinition) bool {
+	if resource.category == "" {
+		return true
+	}
+	return selectionPermitted(s.currentConfig(), resource.category)
+}
+
+// selectionPermitted applies the enabled and disabled tool lists to an entry identified
+// by any of the given selectors (a tool name and/or a category)
+func selectionPermitted(cfg *config.Config, selectors ...string) bool {
+	if len(cfg.EnabledTools) > 0 && !containsAny(cfg.EnabledTools, selectors) {
+		return false
+	}
+	return !containsAny(cfg.DisabledTools, selectors)
+}
+
+// containsAny reports whether list contains any of the values
+func containsAny(list, values []string) bool {
+	for _, value := range values {
+		if slices.Contains(list, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateToolSelection ensures every entry in the enabled and disabled tool lists names
+// a known tool or tool category, and every tool time limit a known tool, so a typo does not
+// silently expose or hide tools or leave a tool without its limit
+func (s *Server) validateToolSelection(cfg *config.Config) error {
+	known := make(map[string]bool)
+	for _, tool := range s.registry.Tools() {
+		known[tool.definition.Name] = true
+		if tool.category != "" {
+			known[tool.category] = true
+		}
+	}
+
+	var unknown []string
+	for _, name := range slices.Concat(cfg.EnabledTools, cfg.DisabledTools) {
+		if !known[name] && !slices.Contains(unknown, name) {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown tools or tool categories: %s", strings.Join(unknown, ", "))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.ToolTimeouts)) {
+		if _, ok := s.registry.Tool(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown tools in tool timeouts: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
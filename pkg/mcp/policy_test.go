@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+func TestReadOnlyModeWithholdsMutatingTools(t *testing.T) {
+	tests := []struct {
+		name          string
+		readOnly      bool
+		expectMutable bool
+	}{
+		{name: "read-write", readOnly: false, expectMutable: true},
+		{name: "read-only", readOnly: true, expectMutable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				APIToken: "test-token",
+				LogLevel: "info",
+				Timeout:  30 * time.Second,
+				ReadOnly: tt.readOnly,
+				// Expose the opt-in tools so only read-only mode withholds any
+				ClusterCommands: true,
+			}
+
+			server, err := NewServer(cfg, logging.NewLogger("info"))
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			listed := listedToolNames(t, server)
+			for _, tool := range server.registry.Tools() {
+				expected := !tool.mutating || tt.expectMutable
+				if listed[tool.definition.Name] != expected {
+					t.Errorf("Expected tool '%s' listed = %t, got %t",
+						tool.definition.Name, expected, listed[tool.definition.Name])
+				}
+			}
+
+			// Read-only tools remain available either way
+			if !listed["get_customer_entitlements"] {
+				t.Error("Expected read-only tools to stay registered")
+			}
+		})
+	}
+}
+
+func TestMutatingToolsAreMarked(t *testing.T) {
+	server := newTestServer(t, "")
+
+	mutating := map[string]bool{
+		"set_customer_entitlement": true,
+		"promote_release":          true,
+		"create_application":       true,
+		"archive_application":      true,
+		"set_channel_versioning":   true,
+		"update_channel_settings":  true,
+		"archive_customer":         true,
+		"unarchive_customer":       true,
+		"update_customer":          true,
+		"tag_customer":             true,
+		"annotate_customer":        true,
+		"create_cluster":           true,
+		"delete_cluster":           true,
+		"create_vm":                true,
+		"delete_vm":                true,
+	}
+	for _, tool := range server.registry.Tools() {
+		if tool.mutating != mutating[tool.definition.Name] {
+			t.Errorf("Expected tool '%s' mutating = %t, got %t",
+				tool.definition.Name, mutating[tool.definition.Name], tool.mutating)
+		}
+	}
+}
+
+func TestReconfigureReadOnlyMode(t *testing.T) {
+	server := newTestServer(t, "")
+
+	session := newTestSession()
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	readOnly := *server.currentConfig()
+	readOnly.ReadOnly = true
+	if err := server.Reconfigure(&readOnly); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	session.expectNotification(t, mcp.MethodNotificationToolsListChanged)
+
+	if listedToolNames(t, server)["set_customer_entitlement"] {
+		t.Error("Expected set_customer_entitlement to be withdrawn in read-only mode")
+	}
+
+	// Tools added at runtime honor the policy too
+	tool := mcp.NewTool("dynamic_write", mcp.WithDescription("Mutating tool added at runtime"))
+	server.addTool(toolDefinition{
+		definition: &tool,
+		handler: func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		},
+		mutating: true,
+	})
+	if listedToolNames(t, server)["dynamic_write"] {
+		t.Error("Expected mutating tool added at runtime to be withheld in read-only mode")
+	}
+
+	readWrite := readOnly
+	readWrite.ReadOnly = false
+	if err := server.Reconfigure(&readWrite); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	session.expectNotification(t, mcp.MethodNotificationToolsListChanged)
+
+	listed := listedToolNames(t, server)
+	if !listed["set_customer_entitlement"] || !listed["dynamic_write"] {
+		t.Error("Expected mutating tools to return when read-only mode is turned off")
+	}
+}
+
+func TestToolSelection(t *testing.T) {
+	tests := []struct {
+		name            string
+		enabled         []string
+		disabled        []string
+		expectTools     []string
+		expectHidden    []string
+		expectResources int
+	}{
+		{
+			name:            "everything by default",
+			expectTools:     []string{"list_applications", "search_customers", "get_job"},
+			expectResources: 6,
+		},
+		{
+			name:            "enabled categories",
+			enabled:         []string{"customers", "releases"},
+			expectTools:     []string{"list_customers", "search_customers", "get_release"},
+			expectHidden:    []string{"list_applications", "get_job"},
+			expectResources: 4,
+		},
+		{
+			name:            "enabled category and tool name",
+			enabled:         []string{"customers", "get_job"},
+			expectTools:     []string{"get_customer", "get_job"},
+			expectHidden:    []string{"list_releases"},
+			expectResources: 2,
+		},
+		{
+			name:            "disabled tool name",
+			disabled:        []string{"search_customers"},
+			expectTools:     []string{"list_customers", "get_customer"},
+			expectHidden:    []string{"search_customers"},
+			expectResources: 6,
+		},
+		{
+			name:            "disabled category within enabled categories",
+			enabled:         []string{"customers", "channels"},
+			disabled:        []string{"channels"},
+			expectTools:     []string{"get_customer"},
+			expectHidden:    []string{"get_channel"},
+			expectResources: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				APIToken:      "test-token",
+				LogLevel:      "info",
+				Timeout:       30 * time.Second,
+				EnabledTools:  tt.enabled,
+				DisabledTools: tt.disabled,
+			}
+
+			server, err := NewServer(cfg, logging.NewLogger("info"))
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			listed := listedToolNames(t, server)
+			for _, name := range tt.expectTools {
+				if !listed[name] {
+					t.Errorf("Expected tool '%s' to be listed", name)
+				}
+			}
+			for _, name := range tt.expectHidden {
+				if listed[name] {
+					t.Errorf("Expected tool '%s' to be hidden", name)
+				}
+			}
+
+			if resources := listedResourceCount(t, server); resources != tt.expectResources {
+				t.Errorf("Expected %d resources, got %d", tt.expectResources, resources)
+			}
+		})
+	}
+}
+
+func TestToolSelectionRejectsUnknownNames(t *testing.T) {
+	cfg := &config.Config{
+		APIToken:      "test-token",
+		LogLevel:      "info",
+		Timeout:       30 * time.Second,
+		DisabledTools: []string{"search_customer"},
+	}
+
+	_, err := NewServer(cfg, logging.NewLogger("info"))
+	if err == nil || !contains(err.Error(), "search_customer") {
+		t.Errorf("Expected error naming the unknown tool, got %v", err)
+	}
+
+	server := newTestServer(t, "")
+	unknown := *server.currentConfig()
+	unknown.EnabledTools = []string{"widgets"}
+	if err := server.Reconfigure(&unknown); err == nil {
+		t.Error("Expected Reconfigure to reject an unknown tool category")
+	}
+
+	unknown = *server.currentConfig()
+	unknown.ToolTimeouts = map[string]time.Duration{"create_clusters": time.Minute}
+	if err := server.Reconfigure(&unknown); err == nil || !contains(err.Error(), "create_clusters") {
+		t.Errorf("Expected error naming the unknown tool in the timeouts, got %v", err)
+	}
+}
+
+func TestReconfigureToolSelection(t *testing.T) {
+	server := newTestServer(t, "")
+
+	session := newTestSession()
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	restricted := *server.currentConfig()
+	restricted.DisabledTools = []string{"customers"}
+	if err := server.Reconfigure(&restricted); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	session.expectNotification(t, mcp.MethodNotificationToolsListChanged)
+	session.expectNotification(t, mcp.MethodNotificationResourcesListChanged)
+
+	if listedToolNames(t, server)["list_customers"] {
+		t.Error("Expected list_customers to be withdrawn when customers are disabled")
+	}
+	if resources := listedResourceCount(t, server); resources != 4 {
+		t.Errorf("Expected 4 resources with customers disabled, got %d", resources)
+	}
+}
+
+// listedResourceCount returns the number of resources reported by resources/list
+func listedResourceCount(t *testing.T, server *Server) int {
+	t.Helper()
+
+	message := server.mcpServer.HandleMessage(context.Background(),
+		json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("Failed to encode resources/list response: %v", err)
+	}
+
+	var response struct {
+		Result mcp.ListResourcesResult `json:"result"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Failed to decode resources/list response: %v", err)
+	}
+	return len(response.Result.Resources)
+}
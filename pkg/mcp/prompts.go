@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Constants for prompt argument names
+const (
+	promptArgAppID      = "app_id"
+	promptArgCustomerID = "customer_id"
+	promptArgChannelID  = "channel_id"
+	promptArgChannelA   = "channel_a"
+	promptArgChannelB   = "channel_b"
+	promptArgCount      = "count"
+)
+
+// defaultReleaseNotesCount is the number of recent releases used when drafting release notes
+const defaultReleaseNotesCount = "5"
+
+// promptDefinition represents a complete prompt definition with its handler function.
+type promptDefinition struct {
+	definition *mcp.Prompt
+	handler    server.PromptHandlerFunc
+}
+
+// definePrompts returns all MCP prompts for common vendor workflows.
+// Prompts are reusable, parameterized instructions that guide an agent through
+// a multi-step workflow using the tools this server exposes.
+//
+// Available prompts:
+// - summarize_customer_health: Summarize the health of a customer's deployment
+// - draft_release_notes: Draft release notes from recent releases
+// - compare_channels: Compare the releases on two channels
+//
+// Note: ID arguments accept both IDs and slugs, consistent with the tool arguments.
+//
+// Returns:
+//
+//	[]promptDefinition: All prompt definitions with handlers
+func (s *Server) definePrompts() []promptDefinition {
+	return []promptDefinition{
+		s.defineSummarizeCustomerHealthPrompt(),
+		s.defineDraftReleaseNotesPrompt(),
+		s.defineCompareChannelsPrompt(),
+	}
+}
+
+// defineSummarizeCustomerHealthPrompt creates the summarize_customer_health prompt definition.
+// Guides the agent from a customer summary and its channel to a health summary.
+func (s *Server) defineSummarizeCustomerHealthPrompt() promptDefinition {
+	prompt := mcp.NewPrompt("summarize_customer_health",
+		mcp.WithPromptDescription("Summarize the health of a customer's deployment, "+
+			"including license status, entitlements, instance uptime, and recent events."),
+		mcp.WithArgument(promptArgAppID,
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The application ID or slug the customer belongs to"),
+		),
+		mcp.WithArgument(promptArgCustomerID,
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The customer ID to summarize"),
+		),
+	)
+
+	handler := func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		s.logger.Info("summarize_customer_health prompt requested", "arguments", request.Params.Arguments)
+
+		args, err := requirePromptArguments(request, promptArgAppID, promptArgCustomerID)
+		if err != nil {
+			return nil, err
+		}
+
+		text := fmt.Sprintf(
+			"Summarize the health of customer %q for application %q.\n\n"+
+				"1. Use summarize_customer to retrieve the customer's details, license, entitlements, "+
+				"and the health and recent events of each instance in one call.\n"+
+				"2. Use get_channel to inspect the channel the customer is assigned to.\n"+
+				"3. Report the license type, expiration date, and whether the license is expired "+
+				"or expiring within 30 days.\n"+
+				"4. Report whether each instance is active and checking in, its last status, when it last "+
+				"upgraded, and whether it runs the release the channel currently ships.\n"+
+				"5. Note whether the customer is archived and any warnings about data that could not be read.\n"+
+				"Finish with a short list of recommended follow-up actions.",
+			args[promptArgCustomerID], args[promptArgAppID])
+
+		return mcp.NewGetPromptResult(
+			"Customer health summary",
+			[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+		), nil
+	}
+
+	return promptDefinition{definition: &prompt, handler: handler}
+}
+
+// defineDraftReleaseNotesPrompt creates the draft_release_notes prompt definition.
+// Guides the agent through collecting recent releases and turning them into release notes.
+func (s *Server) defineDraftReleaseNotesPrompt() promptDefinition {
+	prompt := mcp.NewPrompt("draft_release_notes",
+		mcp.WithPromptDescription("Draft customer-facing release notes from the most recent releases "+
+			"of an application, optionally limited to a single channel."),
+		mcp.WithArgument(promptArgAppID,
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The application ID or slug"),
+		),
+		mcp.WithArgument(promptArgChannelID,
+			mcp.ArgumentDescription("Optional channel ID to limit releases to"),
+		),
+		mcp.WithArgument(promptArgCount,
+			mcp.ArgumentDescription("Number of recent releases to include (default 5)"),
+		),
+	)
+
+	handler := func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		s.logger.Info("draft_release_notes prompt requested", "arguments", request.Params.Arguments)
+
+		args, err := requirePromptArguments(request, promptArgAppID)
+		if err != nil {
+			return nil, err
+		}
+
+		count := args[promptArgCount]
+		if count == "" {
+			count = defaultReleaseNotesCount
+		}
+
+		scope := "across all channels"
+		if channelID := args[promptArgChannelID]; channelID != "" {
+			scope = fmt.Sprintf("promoted to channel %q", channelID)
+		}
+
+		text := fmt.Sprintf(
+			"Draft release notes for application %q covering the %s most recent releases %s.\n\n"+
+				"1. Use list_releases to find the most recent releases.\n"+
+				"2. Use get_release on each one to read its version label and release notes.\n"+
+				"3. Group changes into New Features, Improvements, and Bug Fixes.\n"+
+				"Write for customers, not engineers, and omit internal-only details.",
+			args[promptArgAppID], count, scope)
+
+		return mcp.NewGetPromptResult(
+			"Release notes draft",
+			[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+		), nil
+	}
+
+	return promptDefinition{definition: &prompt, handler: handler}
+}
+
+// defineCompareChannelsPrompt creates the compare_channels prompt definition.
+// Guides the agent through comparing the releases and customers on two channels.
+func (s *Server) defineCompareChannelsPrompt() promptDefinition {
+	prompt := mcp.NewPrompt("compare_channels",
+		mcp.WithPromptDescription("Compare two channels of an application, highlighting "+
+			"differences in their current releases and customer adoption."),
+		mcp.WithArgument(promptArgAppID,
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The application ID or slug"),
+		),
+		mcp.WithArgument(promptArgChannelA,
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The first channel ID (for example, Beta)"),
+		),
+		mcp.WithArgument(promptArgChannelB,
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The second channel ID (for example, Stable)"),
+		),
+	)
+
+	handler := func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		s.logger.Info("compare_channels prompt requested", "arguments", request.Params.Arguments)
+
+		args, err := requirePromptArguments(request, promptArgAppID, promptArgChannelA, promptArgChannelB)
+		if err != nil {
+			return nil, err
+		}
+
+		text := fmt.Sprintf(
+			"Compare channels %q and %q of application %q.\n\n"+
+				"1. Use get_channel on both channels to find their current releases.\n"+
+				"2. Use get_release to compare the version labels and release notes of each head release.\n"+
+				"3. Use list_customers to see which customers are assigned to each channel.\n"+
+				"Summarize which changes are on %q but not yet on %q, and whether promotion looks safe.",
+			args[promptArgChannelA], args[promptArgChannelB], args[promptArgAppID],
+			args[promptArgChannelA], args[promptArgChannelB])
+
+		return mcp.NewGetPromptResult(
+			"Channel comparison",
+			[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+		), nil
+	}
+
+	return promptDefinition{definition: &prompt, handler: handler}
+}
+
+// requirePromptArguments returns the prompt arguments after verifying that every
+// required argument is present and non-empty.
+func requirePromptArguments(request mcp.GetPromptRequest, required ...string) (map[string]string, error) {
+	args := request.Params.Arguments
+	if args == nil {
+		args = map[string]string{}
+	}
+
+	var missing []string
+	for _, name := range required {
+		if strings.TrimSpace(args[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required prompt arguments: %s", strings.Join(missing, ", "))
+	}
+
+	return args, nil
+}
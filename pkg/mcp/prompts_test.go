@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+func TestPromptDefinitions(t *testing.T) {
+	cfg := &config.Config{
+		APIToken: "test-token",
+		LogLevel: "info",
+		Timeout:  30 * time.Second,
+	}
+	logger := logging.NewLogger("info")
+
+	server, err := NewServer(cfg, logger)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	prompts := server.definePrompts()
+
+	expected := map[string][]string{
+		"summarize_customer_health": {"app_id", "customer_id"},
+		"draft_release_notes":       {"app_id"},
+		"compare_channels":          {"app_id", "channel_a", "channel_b"},
+	}
+
+	if len(prompts) != len(expected) {
+		t.Errorf("Expected %d prompts to be defined, got %d", len(expected), len(prompts))
+	}
+
+	for _, prompt := range prompts {
+		required, ok := expected[prompt.definition.Name]
+		if !ok {
+			t.Errorf("Unexpected prompt '%s'", prompt.definition.Name)
+			continue
+		}
+
+		if prompt.definition.Description == "" {
+			t.Errorf("Expected prompt '%s' to have a description", prompt.definition.Name)
+		}
+
+		if prompt.handler == nil {
+			t.Errorf("Expected prompt '%s' to have a handler", prompt.definition.Name)
+		}
+
+		requiredArgs := make(map[string]bool)
+		for _, arg := range prompt.definition.Arguments {
+			if arg.Required {
+				requiredArgs[arg.Name] = true
+			}
+		}
+
+		for _, name := range required {
+			if !requiredArgs[name] {
+				t.Errorf("Expected prompt '%s' to require argument '%s'", prompt.definition.Name, name)
+			}
+		}
+	}
+}
+
+func TestPromptHandlers(t *testing.T) {
+	cfg := &config.Config{
+		APIToken: "test-token",
+		LogLevel: "info",
+		Timeout:  30 * time.Second,
+	}
+	logger := logging.NewLogger("info")
+
+	server, err := NewServer(cfg, logger)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	prompts := server.definePrompts()
+
+	tests := []struct {
+		name         string
+		promptName   string
+		args         map[string]string
+		expectError  bool
+		expectInText string
+	}{
+		{
+			name:         "summarize customer health",
+			promptName:   "summarize_customer_health",
+			args:         map[string]string{"app_id": "my-app", "customer_id": "cust-123"},
+			expectInText: "cust-123",
+		},
+		{
+			name:         "summarize customer health uses the customer summary",
+			promptName:   "summarize_customer_health",
+			args:         map[string]string{"app_id": "my-app", "customer_id": "cust-123"},
+			expectInText: "Use summarize_customer",
+		},
+		{
+			name:        "summarize customer health missing customer",
+			promptName:  "summarize_customer_health",
+			args:        map[string]string{"app_id": "my-app"},
+			expectError: true,
+		},
+		{
+			name:         "draft release notes with default count",
+			promptName:   "draft_release_notes",
+			args:         map[string]string{"app_id": "my-app"},
+			expectInText: "5 most recent releases",
+		},
+		{
+			name:         "draft release notes for a channel",
+			promptName:   "draft_release_notes",
+			args:         map[string]string{"app_id": "my-app", "channel_id": "beta", "count": "3"},
+			expectInText: "promoted to channel \"beta\"",
+		},
+		{
+			name:         "compare channels",
+			promptName:   "compare_channels",
+			args:         map[string]string{"app_id": "my-app", "channel_a": "beta", "channel_b": "stable"},
+			expectInText: "\"beta\" and \"stable\"",
+		},
+		{
+			name:        "compare channels without arguments",
+			promptName:  "compare_channels",
+			args:        nil,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompt *promptDefinition
+			for _, promptDef := range prompts {
+				if promptDef.definition.Name == tt.promptName {
+					prompt = &promptDef
+					break
+				}
+			}
+
+			if prompt == nil {
+				t.Fatalf("Prompt '%s' not found", tt.promptName)
+			}
+
+			request := mcp.GetPromptRequest{
+				Params: mcp.GetPromptParams{
+					Name:      tt.promptName,
+					Arguments: tt.args,
+				},
+			}
+
+			result, err := prompt.handler(context.Background(), request)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(result.Messages) == 0 {
+				t.Fatal("Expected at least one prompt message")
+			}
+
+			textContent, ok := result.Messages[0].Content.(mcp.TextContent)
+			if !ok {
+				t.Fatal("Expected TextContent")
+			}
+
+			if !contains(textContent.Text, tt.expectInText) {
+				t.Errorf("Expected prompt text to contain '%s', got '%s'", tt.expectInText, textContent.Text)
+			}
+		})
+	}
+}
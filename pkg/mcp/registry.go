@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"sync"
+)
+
+// registry holds the tool and resource definitions built once when the server is created.
+// It preserves definition order and supports adding or removing entries at runtime, so
+// the set of exposed tools can change without rebuilding every definition.
+type registry struct {
+	mu        sync.RWMutex
+	tools     []toolDefinition
+	resources []resourceDefinition
+}
+
+// newRegistry creates a registry seeded with the given tool and resource definitions
+func newRegistry(tools []toolDefinition, resources []resourceDefinition) *registry {
+	return &registry{
+		tools:     tools,
+		resources: resources,
+	}
+}
+
+// Tools returns a snapshot of all registered tool definitions
+func (r *registry) Tools() []toolDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]toolDefinition, len(r.tools))
+	copy(tools, r.tools)
+	return tools
+}
+
+// Tool returns the tool definition with the given name
+func (r *registry) Tool(name string) (toolDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, tool := range r.tools {
+		if tool.definition.Name == name {
+			return tool, true
+		}
+	}
+	return toolDefinition{}, false
+}
+
+// Resources returns a snapshot of all registered resource definitions
+func (r *registry) Resources() []resourceDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resources := make([]resourceDefinition, len(r.resources))
+	copy(resources, r.resources)
+	return resources
+}
+
+// Resource returns the resource definition with the given URI
+func (r *registry) Resource(uri string) (resourceDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, resource := range r.resources {
+		if resource.definition.URI == uri {
+			return resource, true
+		}
+	}
+	return resourceDefinition{}, false
+}
+
+// putTool adds a tool definition, replacing any existing tool with the same name
+func (r *registry) putTool(tool toolDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.tools {
+		if r.tools[i].definition.Name == tool.definition.Name {
+			r.tools[i] = tool
+			return
+		}
+	}
+	r.tools = append(r.tools, tool)
+}
+
+// deleteTool removes the named tool, reporting whether it was present
+func (r *registry) deleteTool(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.tools {
+		if r.tools[i].definition.Name == name {
+			r.tools = append(r.tools[:i], r.tools[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// putResource adds a resource definition, replacing any existing resource with the same URI
+func (r *registry) putResource(resource resourceDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.resources {
+		if r.resources[i].definition.URI == resource.definition.URI {
+			r.resources[i] = resource
+			return
+		}
+	}
+	r.resources = append(r.resources, resource)
+}
+
+// deleteResource removes the resource with the given URI, reporting whether it was present
+func (r *registry) deleteResource(uri string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.resources {
+		if r.resources[i].definition.URI == uri {
+			r.resources = append(r.resources[:i], r.resources[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testSession is a minimal client session that captures notifications
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newTestSession() *testSession {
+	return &testSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func (s *testSession) SessionID() string                                   { return "test-session" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+
+// expectNotification waits for a notification with the given method
+func (s *testSession) expectNotification(t *testing.T, method string) {
+	t.Helper()
+
+	select {
+	case notification := <-s.notifications:
+		if notification.Method != method {
+			t.Errorf("Expected notification '%s', got '%s'", method, notification.Method)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Timed out waiting for notification '%s'", method)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	first := mcp.NewTool("first_tool")
+	second := mcp.NewTool("second_tool")
+	replacement := mcp.NewTool("first_tool", mcp.WithDescription("replacement"))
+
+	r := newRegistry([]toolDefinition{{definition: &first}, {definition: &second}}, nil)
+
+	// Snapshots must not alias internal state
+	tools := r.Tools()
+	tools[0] = toolDefinition{}
+	if _, ok := r.Tool("first_tool"); !ok {
+		t.Error("Expected snapshot modification not to affect registry")
+	}
+
+	r.putTool(toolDefinition{definition: &replacement})
+	tool, ok := r.Tool("first_tool")
+	if !ok || tool.definition.Description != "replacement" {
+		t.Errorf("Expected first_tool to be replaced, got %+v", tool.definition)
+	}
+	if len(r.Tools()) != 2 {
+		t.Errorf("Expected replacement not to add a tool, got %d tools", len(r.Tools()))
+	}
+
+	if !r.deleteTool("second_tool") {
+		t.Error("Expected second_tool to be deleted")
+	}
+	if r.deleteTool("second_tool") {
+		t.Error("Expected deleting a missing tool to report false")
+	}
+	if len(r.Tools()) != 1 {
+		t.Errorf("Expected 1 tool after deletion, got %d", len(r.Tools()))
+	}
+}
+
+func TestServerRegistryBuiltOnce(t *testing.T) {
+	server := newTestServer(t, "")
+
+	if len(server.registry.Tools()) != len(server.defineTools()) {
+		t.Errorf("Expected registry to contain all defined tools")
+	}
+	if len(server.registry.Resources()) != len(server.defineResources()) {
+		t.Errorf("Expected registry to contain all defined resources")
+	}
+}
+
+func TestServerDynamicTools(t *testing.T) {
+	server := newTestServer(t, "")
+
+	session := newTestSession()
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	tool := mcp.NewTool("dynamic_tool", mcp.WithDescription("Added at runtime"))
+	server.addTool(toolDefinition{
+		definition: &tool,
+		handler: func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("dynamic"), nil
+		},
+	})
+	session.expectNotification(t, mcp.MethodNotificationToolsListChanged)
+
+	if !listedToolNames(t, server)["dynamic_tool"] {
+		t.Error("Expected dynamic_tool to be listed after adding it")
+	}
+
+	server.removeTool("dynamic_tool")
+	session.expectNotification(t, mcp.MethodNotificationToolsListChanged)
+
+	if listedToolNames(t, server)["dynamic_tool"] {
+		t.Error("Expected dynamic_tool not to be listed after removing it")
+	}
+
+	if _, ok := server.registry.Tool("dynamic_tool"); ok {
+		t.Error("Expected dynamic_tool to be removed from the registry")
+	}
+}
+
+func TestServerDynamicResources(t *testing.T) {
+	server := newTestServer(t, "")
+
+	session := newTestSession()
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	resource := mcp.NewResource("replicated://test", "Test Resource")
+	server.addResource(resourceDefinition{
+		definition: &resource,
+		handler: func(_ context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{}, nil
+		},
+	})
+	session.expectNotification(t, mcp.MethodNotificationResourcesListChanged)
+
+	server.removeResource("replicated://test")
+	session.expectNotification(t, mcp.MethodNotificationResourcesListChanged)
+
+	if _, ok := server.registry.Resource("replicated://test"); ok {
+		t.Error("Expected resource to be removed from the registry")
+	}
+}
+
+// listedToolNames returns the names of the tools reported by tools/list
+func listedToolNames(t *testing.T, server *Server) map[string]bool {
+	t.Helper()
+
+	message := server.mcpServer.HandleMessage(context.Background(),
+		json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("Failed to encode tools/list response: %v", err)
+	}
+
+	var response struct {
+		Result mcp.ListToolsResult `json:"result"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Failed to decode tools/list response: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, tool := range response.Result.Tools {
+		names[tool.Name] = true
+	}
+	return names
+}
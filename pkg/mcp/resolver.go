@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// defaultResolverTTL controls how long resolved application slugs are cached
+const defaultResolverTTL = 5 * time.Minute
+
+// resolverCacheName identifies the resolver's cache in metrics
+const resolverCacheName = "application_slugs"
+
+// applicationLister is the subset of the application API used by the resolver
+type applicationLister interface {
+	ListApplications(ctx context.Context, opts *api.ListApplicationsOptions) (*api.ApplicationList, error)
+}
+
+// cacheObserver receives the outcome of cache lookups, for example to record metrics
+type cacheObserver interface {
+	ObserveCacheLookup(cache string, hit bool)
+}
+
+// slugCacheStore persists the slug mapping so a restarted server can resolve slugs without
+// listing applications again while the mapping is fresh
+type slugCacheStore interface {
+	loadSlugCache(maxAge time.Duration) (slugCache, time.Time, bool)
+	saveSlugCache(cache slugCache)
+}
+
+// slugCache is the persisted form of the resolver's mapping
+type slugCache struct {
+	SlugToID map[string]string `json:"slug_to_id"`
+	IDs      []string          `json:"ids"`
+}
+
+// resolver translates human-readable application slugs into application IDs.
+// Tools and resources accept either form; the resolver caches the slug-to-ID
+// mapping so repeated lookups do not require additional API calls.
+type resolver struct {
+	applications applicationLister
+	ttl          time.Duration
+	now          func() time.Time
+	observer     cacheObserver
+	persist      slugCacheStore
+	restoreOnce  sync.Once
+
+	mu        sync.RWMutex
+	slugToID  map[string]string
+	knownIDs  map[string]bool
+	refreshed time.Time
+}
+
+// newResolver creates a resolver backed by the given application lister
+func newResolver(applications applicationLister, ttl time.Duration) *resolver {
+	return &resolver{
+		applications: applications,
+		ttl:          ttl,
+		now:          time.Now,
+		slugToID:     make(map[string]string),
+		knownIDs:     make(map[string]bool),
+	}
+}
+
+// ResolveApplicationID returns the application ID for the given ID or slug.
+// Cached mappings are used while fresh; otherwise the application list is reloaded.
+func (r *resolver) ResolveApplicationID(ctx context.Context, idOrSlug string) (string, error) {
+	idOrSlug = strings.TrimSpace(idOrSlug)
+	if idOrSlug == "" {
+		return "", fmt.Errorf("application ID or slug is required")
+	}
+
+	r.restore()
+	id, ok := r.lookup(idOrSlug)
+	if r.observer != nil {
+		r.observer.ObserveCacheLookup(resolverCacheName, ok)
+	}
+	if ok {
+		return id, nil
+	}
+
+	if err := r.refresh(ctx); err != nil {
+		return "", fmt.Errorf("failed to resolve application '%s': %w", idOrSlug, err)
+	}
+
+	if id, ok := r.lookup(idOrSlug); ok {
+		return id, nil
+	}
+
+	return "", fmt.Errorf("application '%s' not found: no application has that ID or slug", idOrSlug)
+}
+
+// lookup checks the cache for an ID or slug, honoring the cache TTL
+func (r *resolver) lookup(idOrSlug string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.refreshed.IsZero() || r.now().Sub(r.refreshed) > r.ttl {
+		return "", false
+	}
+
+	if r.knownIDs[idOrSlug] {
+		return idOrSlug, true
+	}
+
+	id, ok := r.slugToID[models.NormalizeSlug(idOrSlug)]
+	return id, ok
+}
+
+// refresh reloads the slug-to-ID mapping from the application list
+func (r *resolver) refresh(ctx context.Context) error {
+	list, err := r.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+	if err != nil {
+		return err
+	}
+
+	slugToID := make(map[string]string, len(list.Applications))
+	knownIDs := make(map[string]bool, len(list.Applications))
+	for i := range list.Applications {
+		app := &list.Applications[i]
+		knownIDs[app.ID] = true
+		if app.Slug != "" {
+			slugToID[models.NormalizeSlug(app.Slug)] = app.ID
+		}
+	}
+
+	r.mu.Lock()
+	r.slugToID = slugToID
+	r.knownIDs = knownIDs
+	r.refreshed = r.now()
+	r.mu.Unlock()
+
+	if r.persist != nil {
+		cache := slugCache{SlugToID: slugToID, IDs: make([]string, 0, len(knownIDs))}
+		for id := range knownIDs {
+			cache.IDs = append(cache.IDs, id)
+		}
+		r.persist.saveSlugCache(cache)
+	}
+
+	return nil
+}
+
+// restore loads the persisted slug mapping the first time the resolver is used, keeping
+// the time it was saved so it expires as if it had never left memory
+func (r *resolver) restore() {
+	if r.persist == nil {
+		return
+	}
+
+	r.restoreOnce.Do(func() {
+		cache, savedAt, ok := r.persist.loadSlugCache(r.ttl)
+		if !ok {
+			return
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if !r.refreshed.IsZero() {
+			return
+		}
+		r.slugToID = cache.SlugToID
+		r.knownIDs = make(map[string]bool, len(cache.IDs))
+		for _, id := range cache.IDs {
+			r.knownIDs[id] = true
+		}
+		r.refreshed = savedAt
+	})
+}
+
+// Invalidate clears the cache so the next lookup reloads the application list
+func (r *resolver) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshed = time.Time{}
+}
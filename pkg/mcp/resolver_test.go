@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// fakeApplicationLister returns a fixed set of applications and counts calls
+type fakeApplicationLister struct {
+	apps  []models.Application
+	err   error
+	calls int
+}
+
+func (f *fakeApplicationLister) ListApplications(
+	_ context.Context, _ *api.ListApplicationsOptions,
+) (*api.ApplicationList, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &api.ApplicationList{Applications: f.apps}, nil
+}
+
+func TestResolver_ResolveApplicationID(t *testing.T) {
+	lister := &fakeApplicationLister{
+		apps: []models.Application{
+			{ID: "app-1", Slug: "first-app"},
+			{ID: "app-2", Slug: "second-app"},
+		},
+	}
+	r := newResolver(lister, time.Minute)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "resolves slug", input: "first-app", expected: "app-1"},
+		{name: "resolves slug case-insensitively", input: "Second-App", expected: "app-2"},
+		{name: "passes through known ID", input: "app-2", expected: "app-2"},
+		{name: "trims whitespace", input: "  first-app ", expected: "app-1"},
+		{name: "unknown slug", input: "missing-app", wantErr: true},
+		{name: "empty input", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.ResolveApplicationID(context.Background(), tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %s", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ResolveApplicationID(%q) = %s, want %s", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolver_Caching(t *testing.T) {
+	lister := &fakeApplicationLister{
+		apps: []models.Application{{ID: "app-1", Slug: "first-app"}},
+	}
+	r := newResolver(lister, time.Minute)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := r.ResolveApplicationID(context.Background(), "first-app"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if lister.calls != 1 {
+		t.Errorf("Expected 1 API call while cache is fresh, got %d", lister.calls)
+	}
+
+	// Expire the cache
+	now = now.Add(2 * time.Minute)
+	if _, err := r.ResolveApplicationID(context.Background(), "first-app"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lister.calls != 2 {
+		t.Errorf("Expected cache refresh after TTL, got %d calls", lister.calls)
+	}
+
+	// Explicit invalidation forces a refresh
+	r.Invalidate()
+	if _, err := r.ResolveApplicationID(context.Background(), "app-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lister.calls != 3 {
+		t.Errorf("Expected refresh after invalidation, got %d calls", lister.calls)
+	}
+}
+
+func TestResolver_APIError(t *testing.T) {
+	lister := &fakeApplicationLister{err: fmt.Errorf("API unavailable")}
+	r := newResolver(lister, time.Minute)
+
+	if _, err := r.ResolveApplicationID(context.Background(), "first-app"); err == nil {
+		t.Error("Expected error when the application list cannot be loaded")
+	}
+}
@@ -2,15 +2,22 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/releasediff"
 )
 
 // resourceDefinition represents a complete resource definition with its handler function.
 type resourceDefinition struct {
 	definition *mcp.Resource
 	handler    server.ResourceHandlerFunc
+	category   string
 }
 
 // defineResources returns all MCP resource definitions for Replicated entities.
@@ -19,30 +26,42 @@ type resourceDefinition struct {
 // Resource URI patterns:
 // - Applications: replicated://applications/{application}
 // - Releases: replicated://applications/{application}/releases/{release}
+// - Release manifests: replicated://applications/{application}/releases/{release}/manifests
 // - Channels: replicated://applications/{application}/channels/{channel}
 // - Customers: replicated://applications/{application}/customers/{customer}
+// - Customer licenses: replicated://applications/{application}/customers/{customer}/license
 //
 // Note: Parameters accept both IDs and slugs (e.g., application accepts both
-// application IDs and application slugs). Handlers determine the parameter type at runtime.
+// application IDs and application slugs). Handlers resolve slugs to IDs at runtime
+// using the server's resolver.
 //
 // Each resource includes:
 // - Standardized URI scheme for consistent addressing
 // - MIME type specification for content format
 // - Comprehensive metadata and descriptions
-// - Empty handler that returns placeholder responses
+// - Handler reading the entity from the Vendor Portal
 //
 // Returns:
 //
 //	[]resourceDefinition: All resource definitions with handlers
 func (s *Server) defineResources() []resourceDefinition {
 	return []resourceDefinition{
-		s.defineApplicationResource(),
-		s.defineReleaseResource(),
-		s.defineChannelResource(),
-		s.defineCustomerResource(),
+		inResourceCategory(categoryApplications, s.defineApplicationResource()),
+		inResourceCategory(categoryReleases, s.defineReleaseResource()),
+		inResourceCategory(categoryReleases, s.defineReleaseManifestsResource()),
+		inResourceCategory(categoryChannels, s.defineChannelResource()),
+		inResourceCategory(categoryCustomers, s.defineCustomerResource()),
+		inResourceCategory(categoryCustomers, s.defineCustomerLicenseResource()),
 	}
 }
 
+// inResourceCategory assigns the category of the tools covering the same entity to a resource,
+// so disabling a tool category also hides its resources
+func inResourceCategory(category string, resource resourceDefinition) resourceDefinition {
+	resource.category = category
+	return resource
+}
+
 // defineApplicationResource creates the application resource definition.
 // Provides access to application data through the replicated://applications/{application} URI pattern.
 // The application parameter accepts both application IDs and application slugs.
@@ -55,13 +74,20 @@ func (s *Server) defineApplicationResource() resourceDefinition {
 		mcp.WithMIMEType("application/json"),
 	)
 
-	handler := func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	handler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 		s.logger.Info("Application resource accessed", "uri", request.Params.URI)
 
-		// TODO: Implement actual application resource retrieval in Step 7
-		// Return empty slice for now - actual implementation will be in Step 7
-		// The MCP library expects this signature for resource handlers
-		return []mcp.ResourceContents{}, nil
+		appID, err := s.resolveResourceAppID(ctx, request.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		app, err := s.applications.GetApplication(ctx, appID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get application: %w", err)
+		}
+
+		return jsonResourceContents(request.Params.URI, app)
 	}
 
 	return resourceDefinition{definition: &resource, handler: handler}
@@ -79,17 +105,93 @@ func (s *Server) defineReleaseResource() resourceDefinition {
 		mcp.WithMIMEType("application/json"),
 	)
 
-	handler := func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	handler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 		s.logger.Info("Release resource accessed", "uri", request.Params.URI)
 
-		// TODO: Implement actual release resource retrieval in Step 7
-		// Return empty slice for now - actual implementation will be in Step 7
-		return []mcp.ResourceContents{}, nil
+		release, err := s.readEntityResource(ctx, request.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResourceContents(request.Params.URI, release)
 	}
 
 	return resourceDefinition{definition: &resource, handler: handler}
 }
 
+// defineReleaseManifestsResource creates the release manifests resource definition.
+// Provides a release's spec files through the
+// replicated://applications/{application}/releases/{release}/manifests URI pattern, one YAML
+// resource content per file, so clients can attach manifests directly. The release parameter
+// accepts a sequence number, release ID, or version label.
+func (s *Server) defineReleaseManifestsResource() resourceDefinition {
+	resource := mcp.NewResource(
+		"replicated://applications/{application}/releases/{release}/manifests",
+		"Release Manifests",
+		mcp.WithResourceDescription("The manifest files of a release, such as the application's Kubernetes, "+
+			"KOTS, and Helm specs, each as a separate YAML document addressed by its path in the release"),
+		mcp.WithMIMEType(yamlMIMEType),
+	)
+
+	handler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		s.logger.Info("Release manifests resource accessed", "uri", request.Params.URI)
+
+		appID, err := s.resolveResourceAppID(ctx, request.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		releaseID, err := nestedResourceID(request.Params.URI, "releases", "manifests")
+		if err != nil {
+			return nil, err
+		}
+
+		sequence, err := s.resolveReleaseSequence(ctx, appID, releaseID)
+		if err != nil {
+			return nil, err
+		}
+		release, err := s.releases.GetRelease(ctx, appID, sequence)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get release: %w", err)
+		}
+		files, err := releasediff.Files(release)
+		if err != nil {
+			return nil, err
+		}
+
+		contents := make([]mcp.ResourceContents, 0, len(files))
+		for _, file := range files {
+			contents = append(contents, mcp.TextResourceContents{
+				URI:      request.Params.URI + "/" + file.Path,
+				MIMEType: yamlMIMEType,
+				Text:     file.Content,
+			})
+		}
+		return contents, nil
+	}
+
+	return resourceDefinition{definition: &resource, handler: handler}
+}
+
+// resolveReleaseSequence resolves a release given by sequence number, ID, or version label to
+// its sequence
+func (s *Server) resolveReleaseSequence(ctx context.Context, appID, release string) (int64, error) {
+	if sequence, err := strconv.ParseInt(release, 10, 64); err == nil {
+		return sequence, nil
+	}
+
+	list, err := s.releases.ListReleases(ctx, appID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list releases: %w", err)
+	}
+	for i := range list.Releases {
+		if list.Releases[i].ID == release || list.Releases[i].Version == release {
+			return list.Releases[i].Sequence, nil
+		}
+	}
+	return 0, fmt.Errorf("release '%s' not found for application '%s'", release, appID)
+}
+
 // defineChannelResource creates the channel resource definition.
 // Provides access to channel data through the replicated://applications/{application}/channels/{channel} URI pattern.
 // Both application and channel parameters accept IDs and slugs.
@@ -102,12 +204,15 @@ func (s *Server) defineChannelResource() resourceDefinition {
 		mcp.WithMIMEType("application/json"),
 	)
 
-	handler := func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	handler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 		s.logger.Info("Channel resource accessed", "uri", request.Params.URI)
 
-		// TODO: Implement actual channel resource retrieval in Step 7
-		// Return empty slice for now - actual implementation will be in Step 7
-		return []mcp.ResourceContents{}, nil
+		channel, err := s.readEntityResource(ctx, request.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResourceContents(request.Params.URI, channel)
 	}
 
 	return resourceDefinition{definition: &resource, handler: handler}
@@ -126,13 +231,142 @@ func (s *Server) defineCustomerResource() resourceDefinition {
 		mcp.WithMIMEType("application/json"),
 	)
 
-	handler := func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	handler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 		s.logger.Info("Customer resource accessed", "uri", request.Params.URI)
 
-		// TODO: Implement actual customer resource retrieval in Step 7
-		// Return empty slice for now - actual implementation will be in Step 7
-		return []mcp.ResourceContents{}, nil
+		customer, err := s.readEntityResource(ctx, request.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResourceContents(request.Params.URI, customer)
 	}
 
 	return resourceDefinition{definition: &resource, handler: handler}
 }
+
+// defineCustomerLicenseResource creates the customer license resource definition.
+// Provides a customer's license file through the
+// replicated://applications/{application}/customers/{customer}/license URI pattern, so
+// clients can reference a license by a stable URI.
+func (s *Server) defineCustomerLicenseResource() resourceDefinition {
+	resource := mcp.NewResource(
+		"replicated://applications/{application}/customers/{customer}/license",
+		"Customer License",
+		mcp.WithResourceDescription("The license file of a customer, as the YAML document the customer installs "+
+			"the application with"),
+		mcp.WithMIMEType(yamlMIMEType),
+	)
+
+	handler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		s.logger.Info("Customer license resource accessed", "uri", request.Params.URI)
+
+		appID, err := s.resolveResourceAppID(ctx, request.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := nestedResourceID(request.Params.URI, "customers", "license")
+		if err != nil {
+			return nil, err
+		}
+
+		license, err := s.customers.DownloadLicense(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: request.Params.URI, MIMEType: yamlMIMEType, Text: license},
+		}, nil
+	}
+
+	return resourceDefinition{definition: &resource, handler: handler}
+}
+
+// resourceURIPrefix is the common prefix of all application-scoped resource URIs
+const resourceURIPrefix = "replicated://applications/"
+
+// nestedResourceSegments is the number of path segments after resourceURIPrefix in the URI of
+// a resource nested under an entity, such as {application}/releases/{release}/manifests
+const nestedResourceSegments = 4
+
+// yamlMIMEType is the MIME type of manifest resource contents
+const yamlMIMEType = "text/yaml"
+
+// resolveResourceAppID extracts the application segment from a resource URI and
+// resolves it to an application ID, accepting either an ID or a slug.
+func (s *Server) resolveResourceAppID(ctx context.Context, uri string) (string, error) {
+	if !strings.HasPrefix(uri, resourceURIPrefix) {
+		return "", fmt.Errorf("invalid resource URI '%s': expected %s{application}", uri, resourceURIPrefix)
+	}
+
+	application := strings.SplitN(strings.TrimPrefix(uri, resourceURIPrefix), "/", 2)[0]
+	if application == "" {
+		return "", fmt.Errorf("invalid resource URI '%s': application is required", uri)
+	}
+
+	return s.resolver.ResolveApplicationID(ctx, application)
+}
+
+// readEntityResource reads the application, channel, release, or customer a resource URI
+// addresses. Reading resources and watching subscribed ones for changes share it, so an
+// update notification means a read returns something new.
+func (s *Server) readEntityResource(ctx context.Context, uri string) (any, error) {
+	appID, err := s.resolveResourceAppID(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(strings.TrimPrefix(uri, resourceURIPrefix), "/")
+	switch {
+	case len(segments) == 1:
+		return s.applications.GetApplication(ctx, appID)
+	case len(segments) != 3 || segments[2] == "":
+		return nil, fmt.Errorf("unsupported resource URI '%s'", uri)
+	}
+
+	switch kind, id := segments[1], segments[2]; kind {
+	case "channels":
+		return s.findChannel(ctx, appID, id)
+	case "customers":
+		return s.findCustomer(ctx, appID, id)
+	case "releases":
+		sequence, err := s.resolveReleaseSequence(ctx, appID, id)
+		if err != nil {
+			return nil, err
+		}
+		return s.findRelease(ctx, appID, sequence)
+	default:
+		return nil, fmt.Errorf("unsupported resource URI '%s'", uri)
+	}
+}
+
+// nestedResourceID returns the entity ID in the URI of a resource nested under an entity, such
+// as the release in {application}/releases/{release}/manifests, when the URI names that
+// collection and nested resource
+func nestedResourceID(uri, collection, nested string) (string, error) {
+	segments := strings.Split(strings.TrimPrefix(uri, resourceURIPrefix), "/")
+	if len(segments) != nestedResourceSegments ||
+		segments[1] != collection || segments[2] == "" || segments[3] != nested {
+		return "", fmt.Errorf("invalid resource URI '%s': expected %s{application}/%s/{id}/%s",
+			uri, resourceURIPrefix, collection, nested)
+	}
+	return segments[2], nil
+}
+
+// jsonResourceContents encodes a value as JSON text resource contents for the given URI
+func jsonResourceContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
@@ -2,6 +2,12 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,39 +18,35 @@ import (
 )
 
 func TestResourceHandlers(t *testing.T) {
-	cfg := &config.Config{
-		APIToken: "test-token",
-		LogLevel: "info",
-		Timeout:  30 * time.Second,
-	}
-	logger := logging.NewLogger("info")
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
 
-	server, err := NewServer(cfg, logger)
-	if err != nil {
-		t.Fatalf("Failed to create server: %v", err)
-	}
-
-	resources := server.defineResources()
+	resources := server.registry.Resources()
 
 	tests := []struct {
 		resourceURI string
 		testURI     string
+		wantID      string
 	}{
 		{
 			resourceURI: "replicated://applications/{application}",
 			testURI:     "replicated://applications/test-app-123",
+			wantID:      testAppID,
 		},
 		{
 			resourceURI: "replicated://applications/{application}/releases/{release}",
-			testURI:     "replicated://applications/test-app-123/releases/test-release-456",
+			testURI:     "replicated://applications/test-app/releases/release-2",
+			wantID:      "release-2",
 		},
 		{
 			resourceURI: "replicated://applications/{application}/channels/{channel}",
-			testURI:     "replicated://applications/test-app-123/channels/test-channel-789",
+			testURI:     "replicated://applications/test-app-123/channels/Beta",
+			wantID:      "channel-2",
 		},
 		{
 			resourceURI: "replicated://applications/{application}/customers/{customer}",
-			testURI:     "replicated://applications/test-app-123/customers/test-customer-101",
+			testURI:     "replicated://applications/test-app-123/customers/customer-1",
+			wantID:      "customer-1",
 		},
 	}
 
@@ -63,25 +65,56 @@ func TestResourceHandlers(t *testing.T) {
 				t.Fatalf("Resource '%s' not found", tt.resourceURI)
 			}
 
-			// Create a mock request
-			request := createMockReadResourceRequest(tt.testURI)
-
-			// Call the handler
-			ctx := context.Background()
-			contents, err := resource.handler(ctx, request)
-
-			// Verify the response
+			contents, err := resource.handler(context.Background(), createMockReadResourceRequest(tt.testURI))
 			if err != nil {
-				t.Errorf("Unexpected error: %v", err)
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(contents) != 1 {
+				t.Fatalf("Expected one resource content, got %d", len(contents))
 			}
 
-			if contents == nil {
-				t.Error("Expected contents slice, got nil")
-				return
+			text, ok := contents[0].(mcp.TextResourceContents)
+			if !ok || text.URI != tt.testURI || text.MIMEType != "application/json" {
+				t.Fatalf("Expected JSON contents for %s, got %+v", tt.testURI, contents[0])
 			}
+			var entity struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal([]byte(text.Text), &entity); err != nil || entity.ID != tt.wantID {
+				t.Errorf("Expected the resource to hold %s, got %s", tt.wantID, text.Text)
+			}
+		})
+	}
+}
+
+func TestReadEntityResource(t *testing.T) {
+	server := newTestServer(t, newTestVendorAPI(t).URL)
+
+	tests := []struct {
+		uri         string
+		expectError bool
+	}{
+		{uri: "replicated://applications/test-app"},
+		{uri: "replicated://applications/test-app/channels/Beta"},
+		{uri: "replicated://applications/test-app/customers/customer-2"},
+		{uri: "replicated://applications/test-app/releases/2"},
+		{uri: "replicated://applications/test-app/releases/release-1"},
+		{uri: "replicated://applications/test-app/releases/1.1.0"},
+		{uri: "replicated://applications/test-app/releases/9", expectError: true},
+		{uri: "replicated://applications/test-app/releases/latest", expectError: true},
+		{uri: "replicated://applications/test-app/installers/1", expectError: true},
+		{uri: "replicated://applications/test-app/channels", expectError: true},
+	}
 
-			// For now, we expect empty content since actual implementation is in Step 7
-			// The test mainly verifies the handler executes without error and returns proper type
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			value, err := server.readEntityResource(context.Background(), tt.uri)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("readEntityResource() error = %v, expectError %t", err, tt.expectError)
+			}
+			if !tt.expectError && value == nil {
+				t.Error("Expected the resource to be read")
+			}
 		})
 	}
 }
@@ -99,7 +132,7 @@ func TestResourceDefinitions(t *testing.T) {
 		t.Fatalf("Failed to create server: %v", err)
 	}
 
-	resources := server.defineResources()
+	resources := server.registry.Resources()
 
 	tests := []struct {
 		uri         string
@@ -119,6 +152,12 @@ func TestResourceDefinitions(t *testing.T) {
 			description: "Access to detailed release information",
 			mimeType:    "application/json",
 		},
+		{
+			uri:         "replicated://applications/{application}/releases/{release}/manifests",
+			name:        "Release Manifests",
+			description: "The manifest files of a release",
+			mimeType:    "text/yaml",
+		},
 		{
 			uri:         "replicated://applications/{application}/channels/{channel}",
 			name:        "Channel Data",
@@ -131,6 +170,12 @@ func TestResourceDefinitions(t *testing.T) {
 			description: "Access to detailed customer information",
 			mimeType:    "application/json",
 		},
+		{
+			uri:         "replicated://applications/{application}/customers/{customer}/license",
+			name:        "Customer License",
+			description: "The license file of a customer",
+			mimeType:    "text/yaml",
+		},
 	}
 
 	for _, tt := range tests {
@@ -185,7 +230,7 @@ func TestResourceURIPatterns(t *testing.T) {
 		t.Fatalf("Failed to create server: %v", err)
 	}
 
-	resources := server.defineResources()
+	resources := server.registry.Resources()
 
 	// Test that all resources follow the expected URI pattern
 	expectedPatterns := []struct {
@@ -200,6 +245,11 @@ func TestResourceURIPatterns(t *testing.T) {
 			pattern:     "replicated://applications/{application}/releases/{release}",
 			description: "Release resources should follow replicated://applications/{application}/releases/{release} pattern",
 		},
+		{
+			pattern: "replicated://applications/{application}/releases/{release}/manifests",
+			description: "Release manifest resources should follow " +
+				"replicated://applications/{application}/releases/{release}/manifests pattern",
+		},
 		{
 			pattern:     "replicated://applications/{application}/channels/{channel}",
 			description: "Channel resources should follow replicated://applications/{application}/channels/{channel} pattern",
@@ -208,6 +258,11 @@ func TestResourceURIPatterns(t *testing.T) {
 			pattern:     "replicated://applications/{application}/customers/{customer}",
 			description: "Customer resources should follow replicated://applications/{application}/customers/{customer} pattern",
 		},
+		{
+			pattern: "replicated://applications/{application}/customers/{customer}/license",
+			description: "Customer license resources should follow " +
+				"replicated://applications/{application}/customers/{customer}/license pattern",
+		},
 	}
 
 	foundPatterns := make(map[string]bool)
@@ -230,36 +285,215 @@ func TestResourceURIPatterns(t *testing.T) {
 }
 
 func TestResourceHandlerErrorHandling(t *testing.T) {
-	cfg := &config.Config{
-		APIToken: "test-token",
-		LogLevel: "info",
-		Timeout:  30 * time.Second,
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	resources := server.registry.Resources()
+
+	invalidURIs := map[string]string{
+		"empty_uri":           "",
+		"missing_application": "replicated://applications/",
+		"unknown_application": "replicated://applications/unknown-app",
 	}
-	logger := logging.NewLogger("info")
 
-	server, err := NewServer(cfg, logger)
+	for _, resource := range resources {
+		for name, uri := range invalidURIs {
+			t.Run(resource.definition.URI+"_"+name, func(t *testing.T) {
+				ctx := context.Background()
+				contents, err := resource.handler(ctx, createMockReadResourceRequest(uri))
+
+				// The application segment must resolve to a known application
+				if err == nil {
+					t.Errorf("Expected error for URI '%s', got contents %v", uri, contents)
+				}
+			})
+		}
+	}
+}
+
+func TestApplicationResourceContents(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	resource := server.defineApplicationResource()
+
+	contents, err := resource.handler(context.Background(),
+		createMockReadResourceRequest("replicated://applications/"+testAppSlug))
 	if err != nil {
-		t.Fatalf("Failed to create server: %v", err)
+		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	resources := server.defineResources()
+	if len(contents) != 1 {
+		t.Fatalf("Expected 1 resource content, got %d", len(contents))
+	}
 
-	// Test with empty URI
-	emptyRequest := createMockReadResourceRequest("")
+	textContents, ok := contents[0].(mcp.TextResourceContents)
+	if !ok {
+		t.Fatal("Expected TextResourceContents")
+	}
 
-	for _, resource := range resources {
-		t.Run(resource.definition.URI+"_empty_uri", func(t *testing.T) {
-			ctx := context.Background()
-			contents, err := resource.handler(ctx, emptyRequest)
+	if textContents.MIMEType != "application/json" {
+		t.Errorf("Expected MIME type 'application/json', got '%s'", textContents.MIMEType)
+	}
 
-			// The handler should still work with empty URI (it's just logged)
-			// The actual URI validation would happen in the MCP library
+	if !contains(textContents.Text, testAppID) {
+		t.Errorf("Expected resource to contain application ID, got '%s'", textContents.Text)
+	}
+}
+
+func TestReleaseManifestsResourceContents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [{"id": "release-1", "version": "1.0.0", "sequence": 1},
+			{"id": "release-2", "version": "1.1.0", "sequence": 2}]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/release/{sequence}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("sequence") != "2" {
+			fmt.Fprint(w, `{"release": {"id": "release-1", "sequence": 1}}`)
+			return
+		}
+		tree, _ := json.Marshal([]map[string]any{
+			{"name": "manifests", "path": "/manifests", "children": []map[string]any{
+				{"name": "kots-app.yaml", "path": "/manifests/kots-app.yaml", "content": "kind: Application\n"},
+				{"name": "config.yaml", "path": "/manifests/config.yaml", "content": "kind: Config\n"},
+			}},
+		})
+		fmt.Fprintf(w, `{"release": {"id": "release-2", "sequence": 2, "config": %q}}`, tree)
+	})
+	vendorAPI := httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+
+	server := newTestServer(t, vendorAPI.URL)
+	resource := server.defineReleaseManifestsResource()
+
+	tests := []struct {
+		name        string
+		uri         string
+		wantPaths   []string
+		expectError bool
+	}{
+		{
+			name:      "by sequence",
+			uri:       "replicated://applications/" + testAppSlug + "/releases/2/manifests",
+			wantPaths: []string{"manifests/config.yaml", "manifests/kots-app.yaml"},
+		},
+		{
+			name:      "by version label",
+			uri:       "replicated://applications/" + testAppID + "/releases/1.1.0/manifests",
+			wantPaths: []string{"manifests/config.yaml", "manifests/kots-app.yaml"},
+		},
+		{
+			name:      "by release ID without manifests",
+			uri:       "replicated://applications/" + testAppID + "/releases/release-1/manifests",
+			wantPaths: []string{},
+		},
+		{
+			name:        "unknown release",
+			uri:         "replicated://applications/" + testAppID + "/releases/9.9.9/manifests",
+			expectError: true,
+		},
+		{
+			name:        "not a manifests URI",
+			uri:         "replicated://applications/" + testAppID + "/releases/2",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents, err := resource.handler(context.Background(), createMockReadResourceRequest(tt.uri))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got contents %v", contents)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			paths := []string{}
+			for _, content := range contents {
+				file, ok := content.(mcp.TextResourceContents)
+				if !ok {
+					t.Fatalf("Expected TextResourceContents, got %T", content)
+				}
+				if file.MIMEType != "text/yaml" {
+					t.Errorf("Expected MIME type 'text/yaml', got '%s'", file.MIMEType)
+				}
+				if !strings.HasPrefix(file.Text, "kind: ") {
+					t.Errorf("Expected the file's manifest, got '%s'", file.Text)
+				}
+				paths = append(paths, strings.TrimPrefix(file.URI, tt.uri+"/"))
+			}
+			if !slices.Equal(paths, tt.wantPaths) {
+				t.Errorf("Expected files %v, got %v", tt.wantPaths, paths)
+			}
+		})
+	}
+}
+
+func TestCustomerLicenseResourceContents(t *testing.T) {
+	const license = "apiVersion: kots.io/v1beta1\nkind: License\nspec:\n  customerName: Acme Corp\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customer/customer-1/license-download",
+		func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, license)
+		})
+	vendorAPI := httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+
+	server := newTestServer(t, vendorAPI.URL)
+	resource := server.defineCustomerLicenseResource()
+
+	tests := []struct {
+		name        string
+		uri         string
+		expectError bool
+	}{
+		{name: "by application slug", uri: "replicated://applications/" + testAppSlug + "/customers/customer-1/license"},
+		{name: "by application ID", uri: "replicated://applications/" + testAppID + "/customers/customer-1/license"},
+		{
+			name:        "unknown customer",
+			uri:         "replicated://applications/" + testAppID + "/customers/customer-9/license",
+			expectError: true,
+		},
+		{
+			name:        "not a license URI",
+			uri:         "replicated://applications/" + testAppID + "/customers/customer-1",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents, err := resource.handler(context.Background(), createMockReadResourceRequest(tt.uri))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got contents %v", contents)
+				}
+				return
+			}
 			if err != nil {
-				t.Errorf("Unexpected error with empty URI: %v", err)
+				t.Fatalf("Unexpected error: %v", err)
 			}
 
-			if contents == nil {
-				t.Error("Expected contents slice even with empty URI")
+			if len(contents) != 1 {
+				t.Fatalf("Expected 1 resource content, got %d", len(contents))
+			}
+			file, ok := contents[0].(mcp.TextResourceContents)
+			if !ok {
+				t.Fatalf("Expected TextResourceContents, got %T", contents[0])
+			}
+			if file.URI != tt.uri || file.MIMEType != "text/yaml" || file.Text != license {
+				t.Errorf("Expected the license as YAML at %s, got %+v", tt.uri, file)
 			}
 		})
 	}
@@ -0,0 +1,277 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// truncatedField is the field added to a JSON result that was cut down to fit the result size
+// limit. The leading underscore keeps it apart from fields of the result itself, such as the
+// truncated flag search_customers reports.
+const truncatedField = "_truncated"
+
+// truncationReserve is the room kept for the truncation marker when cutting a result down
+const truncationReserve = 512
+
+// maxTruncatedLists bounds how many lists are cut down to fit a single result
+const maxTruncatedLists = 8
+
+// truncationHint tells the agent how to get what was cut from a result
+const truncationHint = "the result was cut to fit the server's result size limit (--max-result-bytes); " +
+	"ask for fewer items with limit (passing the same cursor) and page through the rest with next_cursor, " +
+	"select fewer fields with fields, or narrow the query"
+
+// truncatedList describes a list in a result that lost items to the result size limit. The
+// path is the dot-separated path of the list, empty when the result itself was the list.
+type truncatedList struct {
+	Path     string `json:"path,omitempty"`
+	Returned int    `json:"returned"`
+	Total    int    `json:"total"`
+}
+
+// truncationMarker is the _truncated field of a result cut down to fit the size limit
+type truncationMarker struct {
+	LimitBytes int             `json:"limit_bytes"`
+	Lists      []truncatedList `json:"lists,omitempty"`
+	Lines      *truncatedList  `json:"lines,omitempty"`
+	Hint       string          `json:"hint"`
+}
+
+// withResultLimit wraps a tool handler so that results larger than the configured size limit
+// are cut down to fit: JSON results lose items from the end of their longest lists, and other
+// text results lose lines from their end, with a marker saying what was dropped. The limit is
+// read on each call, so reconfiguring the server applies it to tools that are already
+// registered.
+func (s *Server) withResultLimit(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		limit := s.currentConfig().MaxResultBytes
+		if err != nil || result == nil || result.IsError || limit <= 0 {
+			return result, err
+		}
+		return limitResult(result, limit)
+	}
+}
+
+// limitResult cuts each text content of a result that is larger than the limit, and the
+// structured content when it is larger too. A result with structured content gets the cut
+// JSON as its structured content. A result that still does not fit once its lists are cut,
+// such as a single large value, is replaced by an error result rather than sent whole.
+func limitResult(result *mcp.CallToolResult, limit int) (*mcp.CallToolResult, error) {
+	for i, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok || len(text.Text) <= limit {
+			continue
+		}
+
+		var value any
+		if json.Unmarshal([]byte(text.Text), &value) != nil {
+			text.Text = truncateLines(text.Text, limit)
+			result.Content[i] = text
+			continue
+		}
+
+		limited := truncateJSON(value, limit)
+		data, err := json.MarshalIndent(limited, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tool result: %w", err)
+		}
+		if len(data) > limit {
+			return oversizedResult(limit), nil
+		}
+		text.Text = string(data)
+		result.Content[i] = text
+		if result.StructuredContent != nil {
+			result.StructuredContent = limited
+		}
+	}
+
+	return limitStructuredContent(result, limit)
+}
+
+// limitStructuredContent cuts a result's structured content when its encoding is larger than
+// the limit, as when the text content is a table or NDJSON rendering that was cut by lines
+func limitStructuredContent(result *mcp.CallToolResult, limit int) (*mcp.CallToolResult, error) {
+	if result.StructuredContent == nil {
+		return result, nil
+	}
+
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	if len(data) <= limit {
+		return result, nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode tool result: %w", err)
+	}
+	limited := truncateJSON(value, limit)
+	if encodedSize(limited) > limit {
+		return oversizedResult(limit), nil
+	}
+	result.StructuredContent = limited
+	return result, nil
+}
+
+// oversizedResult is the error result returned in place of a result that cannot be cut down
+// to fit the result size limit
+func oversizedResult(limit int) *mcp.CallToolResult {
+	return mcp.NewToolResultError(fmt.Sprintf("the result is larger than the server's result size limit "+
+		"of %d bytes (--max-result-bytes) and has no list that could be cut to fit it; select fewer fields "+
+		"with fields, or narrow the query", limit))
+}
+
+// truncateJSON drops items from the end of a decoded JSON value's lists until its encoding
+// fits the limit, cutting the largest list first. A result whose own top level is a list is
+// moved into an items field so the marker can be added beside it. Values without lists to
+// cut are returned whole.
+func truncateJSON(value any, limit int) any {
+	budget := max(limit-truncationReserve, 0)
+	if list, ok := value.([]any); ok && encodedSize(list) > budget {
+		value = map[string]any{itemsField: list}
+	}
+
+	totals := make(map[string]int)
+	var order []string
+
+	for range maxTruncatedLists {
+		if encodedSize(value) <= budget {
+			break
+		}
+		target, ok := largestList(value)
+		if !ok {
+			break
+		}
+
+		if _, seen := totals[target.path]; !seen {
+			totals[target.path] = len(target.items)
+			order = append(order, target.path)
+		}
+
+		// Keep as many items as fit, down to none when even the first is too large
+		keep := sort.Search(len(target.items), func(n int) bool {
+			return encodedSize(target.set(target.items[:n+1])) > budget
+		})
+		value = target.set(target.items[:keep])
+	}
+
+	if len(order) == 0 {
+		return value
+	}
+
+	marker := truncationMarker{LimitBytes: limit, Hint: truncationHint}
+	for _, path := range order {
+		list, _ := listAt(value, path)
+		marker.Lists = append(marker.Lists, truncatedList{Path: path, Returned: len(list), Total: totals[path]})
+	}
+
+	object, _ := value.(map[string]any)
+	// A cursor past the dropped items would skip them, so the agent pages with limit instead
+	if page, ok := object["pagination"].(map[string]any); ok {
+		delete(page, "next_cursor")
+	}
+	object[truncatedField] = marker
+	return object
+}
+
+// listTarget is a list within a decoded JSON value, with a function that replaces it and
+// returns the resulting top-level value
+type listTarget struct {
+	path  string
+	items []any
+	set   func([]any) any
+}
+
+// largestList finds the non-empty list with the largest encoding within a value
+func largestList(value any) (listTarget, bool) {
+	var best listTarget
+	bestSize := -1
+
+	var walk func(current any, path string, set func([]any) any)
+	walk = func(current any, path string, set func([]any) any) {
+		switch current := current.(type) {
+		case []any:
+			// Lists within list items are not cut, since their paths name no single list
+			if size := encodedSize(current); len(current) > 0 && size > bestSize {
+				best, bestSize = listTarget{path: path, items: current, set: set}, size
+			}
+		case map[string]any:
+			for key, field := range current {
+				walk(field, joinPath(path, key), func(items []any) any {
+					current[key] = items
+					return value
+				})
+			}
+		}
+	}
+	walk(value, "", func(items []any) any { return items })
+
+	return best, bestSize >= 0
+}
+
+// listAt returns the list at a dot-separated path within a decoded JSON value
+func listAt(value any, path string) ([]any, bool) {
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			object, ok := value.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			value = object[key]
+		}
+	}
+	list, ok := value.([]any)
+	return list, ok
+}
+
+// joinPath joins a key onto a dot-separated path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	if key == "" {
+		return path
+	}
+	return path + "." + key
+}
+
+// encodedSize is the size of a value's indented JSON encoding, as results are written
+func encodedSize(value any) int {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// truncateLines keeps the lines of a text result that fit the limit, followed by a line
+// saying how many were dropped
+func truncateLines(text string, limit int) string {
+	lines := strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	budget := max(limit-truncationReserve, 0)
+
+	kept, size := 0, 0
+	for kept < len(lines) && size+len(lines[kept]) <= budget {
+		size += len(lines[kept])
+		kept++
+	}
+
+	marker, _ := json.Marshal(map[string]truncationMarker{truncatedField: {
+		LimitBytes: limit,
+		Lines:      &truncatedList{Returned: kept, Total: len(lines)},
+		Hint:       truncationHint,
+	}})
+	if kept == 0 {
+		return string(marker) + "\n"
+	}
+	return strings.TrimSuffix(strings.Join(lines[:kept], ""), "\n") + "\n" + string(marker) + "\n"
+}
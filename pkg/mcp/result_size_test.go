@@ -0,0 +1,194 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testCustomers returns n decoded customers, each with a long description
+func testCustomers(n int) []any {
+	customers := make([]any, n)
+	for i := range customers {
+		customers[i] = map[string]any{
+			"id":          fmt.Sprintf("customer-%d", i),
+			"description": strings.Repeat("x", 100),
+		}
+	}
+	return customers
+}
+
+// testPage returns a decoded response envelope holding a page of n customers
+func testPage(n int) map[string]any {
+	return map[string]any{
+		"data":       testCustomers(n),
+		"pagination": map[string]any{"returned": float64(n), "total": float64(n + 1), "next_cursor": "abc"},
+	}
+}
+
+func TestTruncateJSON(t *testing.T) {
+	const limit = 4096
+
+	tests := []struct {
+		name       string
+		value      any
+		wantLists  []string
+		wantCursor bool
+	}{
+		{
+			name:       "small result is unchanged",
+			value:      testPage(2),
+			wantCursor: true,
+		},
+		{
+			name:      "page of customers",
+			value:     testPage(500),
+			wantLists: []string{"data"},
+		},
+		{
+			name:      "top-level list",
+			value:     testCustomers(500),
+			wantLists: []string{"items"},
+		},
+		{
+			name: "several lists",
+			value: map[string]any{
+				"customers": testCustomers(300),
+				"report":    map[string]any{"rows": testCustomers(200)},
+			},
+			wantLists: []string{"customers", "report.rows"},
+		},
+		{
+			name:  "nothing to cut",
+			value: map[string]any{"notes": strings.Repeat("x", 2*limit)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := json.Marshal(truncateJSON(tt.value, limit))
+			var result map[string]any
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("Expected a JSON object, got %s", data)
+			}
+			page, _ := result["pagination"].(map[string]any)
+			if _, ok := page["next_cursor"]; ok != tt.wantCursor {
+				t.Errorf("Expected next_cursor kept = %t, got %s", tt.wantCursor, data)
+			}
+
+			marker, truncated := result[truncatedField].(map[string]any)
+			if truncated != (len(tt.wantLists) > 0) {
+				t.Fatalf("Expected truncated = %t, got %s", len(tt.wantLists) > 0, data)
+			}
+			if !truncated {
+				return
+			}
+			if size := encodedSize(result); size > limit {
+				t.Errorf("Expected the result to fit %d bytes, got %d", limit, size)
+			}
+
+			lists, _ := marker["lists"].([]any)
+			if len(lists) != len(tt.wantLists) {
+				t.Fatalf("Expected %d truncated lists, got %v", len(tt.wantLists), lists)
+			}
+			for i, list := range lists {
+				list, _ := list.(map[string]any)
+				returned, _ := list["returned"].(float64)
+				total, _ := list["total"].(float64)
+				if list["path"] != tt.wantLists[i] || returned >= total {
+					t.Errorf("Expected %s to be cut, got %v", tt.wantLists[i], list)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	lines := make([]string, 200)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("customer-%03d  %s", i, strings.Repeat("x", 40))
+	}
+
+	got := truncateLines(strings.Join(lines, "\n")+"\n", 2048)
+	if len(got) > 2048 {
+		t.Errorf("Expected the text to fit 2048 bytes, got %d", len(got))
+	}
+	if !strings.HasPrefix(got, lines[0]+"\n") {
+		t.Errorf("Expected the first lines to be kept, got %q", got)
+	}
+
+	last := got[strings.LastIndex(strings.TrimSuffix(got, "\n"), "\n")+1:]
+	var marker map[string]truncationMarker
+	if err := json.Unmarshal([]byte(last), &marker); err != nil {
+		t.Fatalf("Expected a JSON marker line, got %q", last)
+	}
+	if lines := marker[truncatedField].Lines; lines == nil || lines.Total != 200 || lines.Returned == 0 ||
+		strings.Count(got, "\n") != lines.Returned+1 {
+		t.Errorf("Expected the marker to count the kept lines, got %+v", marker)
+	}
+}
+
+func TestWithResultLimit(t *testing.T) {
+	srv := newTestServer(t, "")
+	large := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return jsonResult(context.Background(), testCustomers(500))
+	}
+	table := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		lines := make([]string, 500)
+		for i := range lines {
+			lines[i] = fmt.Sprintf("customer-%03d  %s", i, strings.Repeat("x", 40))
+		}
+		return mcp.NewToolResultStructured(listOf(testCustomers(500)), strings.Join(lines, "\n")), nil
+	}
+	single := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return jsonResult(context.Background(), map[string]any{"notes": strings.Repeat("x", 16384)})
+	}
+
+	tests := []struct {
+		name          string
+		handler       server.ToolHandlerFunc
+		limit         int
+		wantTruncated bool
+		wantError     bool
+	}{
+		{name: "over the limit", handler: large, limit: 8192, wantTruncated: true},
+		{name: "no limit", handler: large, limit: 0},
+		{name: "table over the limit", handler: table, limit: 8192, wantTruncated: true},
+		{name: "nothing to cut", handler: single, limit: 8192, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv.config.MaxResultBytes = tt.limit
+
+			result, err := srv.withResultLimit(tt.handler)(context.Background(), mcp.CallToolRequest{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("Expected error result = %t, got %s", tt.wantError, resultText(t, result))
+			}
+			if tt.wantError {
+				return
+			}
+
+			text := resultText(t, result)
+			if strings.Contains(text, truncatedField) != tt.wantTruncated {
+				t.Errorf("Expected truncated = %t, got %d bytes", tt.wantTruncated, len(text))
+			}
+			if tt.wantTruncated {
+				structured, _ := result.StructuredContent.(map[string]any)
+				data, _ := json.Marshal(structured)
+				if len(text) > tt.limit || len(data) > tt.limit || structured[truncatedField] == nil {
+					t.Errorf("Expected text and structured content within %d bytes, got %d and %d",
+						tt.limit, len(text), len(data))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/search"
+)
+
+// defaultSearchIndexTTL controls how long an application's search index is used before it
+// is rebuilt
+const defaultSearchIndexTTL = 5 * time.Minute
+
+// Entity kinds with a search index
+const (
+	searchKindApplications = "applications"
+	searchKindReleases     = "releases"
+	searchKindChannels     = "channels"
+	searchKindCustomers    = "customers"
+)
+
+// Field weights: names count most, then identifiers, then descriptive text
+const (
+	nameWeight        = 3.0
+	identifierWeight  = 2.0
+	descriptionWeight = 1.0
+)
+
+// searchRankingDescription explains to agents how the search tools order their results
+const searchRankingDescription = "When the server's search index is enabled, results are ranked by relevance " +
+	"with a score for each, and the last word of the query also matches the start of longer words; otherwise " +
+	"every match is returned in the API's order."
+
+// scorePrecision is the number of decimal places scores are reported with
+const scorePrecision = 1000
+
+// indexedEntities is a search index over one kind of entity and the entities it ranks
+type indexedEntities struct {
+	index     *search.Index
+	entities  map[string]any
+	truncated bool
+	builtAt   time.Time
+}
+
+// searchIndexes caches a search index per entity kind and application. Indexes are built
+// on the first search, used until they are older than the TTL, and rebuilt on request.
+// Concurrent searches that need the same index share a single build.
+type searchIndexes struct {
+	ttl    time.Duration
+	now    func() time.Time
+	builds singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*indexedEntities
+}
+
+// newSearchIndexes creates an empty index cache
+func newSearchIndexes(ttl time.Duration) *searchIndexes {
+	return &searchIndexes{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*indexedEntities),
+	}
+}
+
+// get returns the cached index for key while it is fresh, or builds a new one
+func (c *searchIndexes) get(
+	ctx context.Context,
+	key string,
+	refresh bool,
+	build func(ctx context.Context) (*indexedEntities, error),
+) (*indexedEntities, error) {
+	if !refresh {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && c.now().Sub(entry.builtAt) <= c.ttl {
+			return entry, nil
+		}
+	}
+
+	result, err, _ := c.builds.Do(key, func() (any, error) {
+		entry, err := build(ctx)
+		if err != nil {
+			return nil, err
+		}
+		entry.builtAt = c.now()
+
+		c.mu.Lock()
+		c.entries[key] = entry
+		c.mu.Unlock()
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*indexedEntities), nil
+}
+
+// Invalidate drops every index so the next searches rebuild them
+func (c *searchIndexes) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*indexedEntities)
+}
+
+// rankedResult is one search result and its relevance score
+type rankedResult struct {
+	Score float64 `json:"score"`
+	Item  any     `json:"item"`
+}
+
+// withSearchIndexOptions adds the refresh argument shared by the search tools
+func withSearchIndexOptions() mcp.ToolOption {
+	return mcp.WithBoolean("refresh",
+		mcp.Description("Rebuild the search index before searching, to include changes made in the last few "+
+			"minutes (only used when the server's search index is enabled)"),
+	)
+}
+
+// rankedSearch answers a search tool from the index for one kind of entity in an application
+// (appID is empty for applications). The pagination total counts every match, including
+// those beyond the requested limit, and is truncated when the index stopped at the search
+// scan limit, so some entities were not searched. A warning says when the index was built.
+func (s *Server) rankedSearch(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	kind, appID, query string,
+) (*mcp.CallToolResult, error) {
+	entry, err := s.searchIndex(ctx, kind, appID, request.GetBool("refresh", false))
+	if err != nil {
+		return nil, err
+	}
+
+	hits := entry.index.Search(query)
+	results := []rankedResult{}
+	for _, hit := range paginate(hits, minOffset, request.GetInt("limit", maxSearchLimit)) {
+		results = append(results, rankedResult{
+			Score: roundScore(hit.Score),
+			Item:  entry.entities[hit.ID],
+		})
+	}
+
+	response := listOf(results)
+	response.Pagination.Total = len(hits)
+	if entry.truncated {
+		response.truncate(kind)
+	}
+	response.warn("results come from the search index built at %s; pass refresh: true to include changes "+
+		"made since", entry.builtAt.UTC().Format(time.RFC3339))
+	return envelopeResult(ctx, response)
+}
+
+// searchIndex returns the index for one kind of entity in an application, building it when
+// there is no fresh one or refresh is set
+func (s *Server) searchIndex(ctx context.Context, kind, appID string, refresh bool) (*indexedEntities, error) {
+	entry, err := s.searchIndexes.get(ctx, kind+"/"+appID, refresh,
+		func(ctx context.Context) (*indexedEntities, error) {
+			return s.buildSearchIndex(ctx, kind, appID)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index %s for search: %w", kind, err)
+	}
+	return entry, nil
+}
+
+// roundScore rounds a relevance score to the precision it is reported with
+func roundScore(score float64) float64 {
+	return math.Round(score*scorePrecision) / scorePrecision
+}
+
+// buildSearchIndex fetches the entities of one kind and indexes them
+func (s *Server) buildSearchIndex(ctx context.Context, kind, appID string) (*indexedEntities, error) {
+	s.logger.WithContext(ctx).Debug("Building search index", "kind", kind, "app_id", appID)
+
+	entry := &indexedEntities{entities: make(map[string]any)}
+	var documents []search.Document
+
+	switch kind {
+	case searchKindApplications:
+		list, err := s.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Applications {
+			app := &list.Applications[i]
+			entry.entities[app.ID] = app
+			documents = append(documents, search.Document{ID: app.ID, Fields: []search.Field{
+				{Text: app.Name, Weight: nameWeight},
+				{Text: app.Slug, Weight: identifierWeight},
+				{Text: app.ID, Weight: identifierWeight},
+				{Text: app.Description, Weight: descriptionWeight},
+			}})
+		}
+
+	case searchKindReleases:
+		list, err := s.releases.ListReleases(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Releases {
+			release := &list.Releases[i]
+			entry.entities[release.ID] = release
+			documents = append(documents, search.Document{ID: release.ID, Fields: []search.Field{
+				{Text: release.Version, Weight: nameWeight},
+				{Text: strconv.FormatInt(release.Sequence, 10), Weight: identifierWeight},
+				{Text: release.ID, Weight: identifierWeight},
+				{Text: release.Notes, Weight: descriptionWeight},
+				{Text: release.Status, Weight: descriptionWeight},
+			}})
+		}
+
+	case searchKindChannels:
+		list, err := s.channels.ListChannels(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Channels {
+			channel := &list.Channels[i]
+			entry.entities[channel.ID] = channel
+			documents = append(documents, search.Document{ID: channel.ID, Fields: []search.Field{
+				{Text: channel.Name, Weight: nameWeight},
+				{Text: channel.ChannelSlug, Weight: identifierWeight},
+				{Text: channel.ID, Weight: identifierWeight},
+				{Text: channel.Description, Weight: descriptionWeight},
+			}})
+		}
+
+	case searchKindCustomers:
+		list, err := s.customers.ListCustomersUpTo(ctx, appID, s.currentConfig().SearchScanLimit)
+		if err != nil {
+			return nil, err
+		}
+		entry.truncated = list.Truncated
+		for i := range list.Customers {
+			customer := &list.Customers[i]
+			entry.entities[customer.ID] = customer
+			documents = append(documents, search.Document{ID: customer.ID, Fields: []search.Field{
+				{Text: customer.Name, Weight: nameWeight},
+				{Text: customer.Email, Weight: identifierWeight},
+				{Text: customer.ID, Weight: identifierWeight},
+				{Text: customer.ChannelName, Weight: descriptionWeight},
+				{Text: customer.Type, Weight: descriptionWeight},
+			}})
+		}
+
+	default:
+		return nil, fmt.Errorf("no search index for %s", kind)
+	}
+
+	entry.index = search.New(documents)
+	return entry, nil
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/search"
+)
+
+func TestSearchIndexes_Get(t *testing.T) {
+	indexes := newSearchIndexes(time.Minute)
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	indexes.now = func() time.Time { return clock }
+
+	builds := 0
+	build := func(_ context.Context) (*indexedEntities, error) {
+		builds++
+		return &indexedEntities{index: search.New(nil)}, nil
+	}
+	get := func(key string, refresh bool) {
+		t.Helper()
+		if _, err := indexes.get(context.Background(), key, refresh, build); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	get("customers/app-1", false)
+	get("customers/app-1", false)
+	if builds != 1 {
+		t.Errorf("Expected a fresh index to be reused, got %d builds", builds)
+	}
+
+	get("customers/app-2", false)
+	if builds != 2 {
+		t.Errorf("Expected each application to have its own index, got %d builds", builds)
+	}
+
+	get("customers/app-1", true)
+	if builds != 3 {
+		t.Errorf("Expected refresh to rebuild the index, got %d builds", builds)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	get("customers/app-1", false)
+	if builds != 4 {
+		t.Errorf("Expected a stale index to be rebuilt, got %d builds", builds)
+	}
+
+	indexes.Invalidate()
+	get("customers/app-1", false)
+	if builds != 5 {
+		t.Errorf("Expected invalidation to drop the index, got %d builds", builds)
+	}
+
+	failing := func(_ context.Context) (*indexedEntities, error) {
+		return nil, errors.New("API unavailable")
+	}
+	if _, err := indexes.get(context.Background(), "customers/app-3", false, failing); err == nil {
+		t.Error("Expected the build error to be returned")
+	}
+}
+
+func TestSearchToolsRankedByIndex(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	cfg := *server.currentConfig()
+	cfg.SearchIndex = true
+	if err := server.Reconfigure(&cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		toolName   string
+		args       map[string]any
+		expectedID []string
+	}{
+		{
+			toolName:   "search_applications",
+			args:       map[string]any{"query": "test"},
+			expectedID: []string{testAppID},
+		},
+		{
+			toolName:   "search_releases",
+			args:       map[string]any{"app_id": testAppSlug, "query": "saml"},
+			expectedID: []string{"release-2"},
+		},
+		{
+			toolName:   "search_channels",
+			args:       map[string]any{"app_id": testAppID, "query": "sta"},
+			expectedID: []string{"channel-1"},
+		},
+		{
+			toolName:   "search_customers",
+			args:       map[string]any{"app_id": testAppID, "query": "acme", "refresh": true},
+			expectedID: []string{"customer-1"},
+		},
+		{
+			toolName:   "search_customers",
+			args:       map[string]any{"app_id": testAppID, "query": "hooli"},
+			expectedID: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toolName, func(t *testing.T) {
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ranked := decodeEnvelope[[]struct {
+				Score float64 `json:"score"`
+				Item  struct {
+					ID string `json:"id"`
+				} `json:"item"`
+			}](t, result)
+
+			if len(ranked.Data) != len(tt.expectedID) || ranked.Pagination.Total != len(tt.expectedID) {
+				t.Fatalf("Expected %d results, got %s", len(tt.expectedID), resultText(t, result))
+			}
+			for i, id := range tt.expectedID {
+				if ranked.Data[i].Item.ID != id || ranked.Data[i].Score <= 0 {
+					t.Errorf("Expected %s with a positive score at %d, got %+v", id, i, ranked.Data[i])
+				}
+			}
+			if !slices.ContainsFunc(ranked.Warnings, func(warning string) bool {
+				return contains(warning, "search index built at")
+			}) {
+				t.Errorf("Expected the index build time to be reported, got %v", ranked.Warnings)
+			}
+		})
+	}
+}
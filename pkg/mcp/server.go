@@ -1,33 +1,92 @@
 // Package mcp provides the Model Context Protocol server implementation for the Replicated Vendor Portal API.
-// It enables AI agents to interact with Replicated resources through the MCP protocol over stdio transport.
+// It enables AI agents to interact with Replicated resources through the MCP protocol over stdio or
+// streamable HTTP transport.
 package mcp
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/audit"
 	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/jobs"
 	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/metrics"
+	"github.com/crdant/replicated-mcp-server/pkg/redact"
+	"github.com/crdant/replicated-mcp-server/pkg/slo"
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
 )
 
+// insecureTLSWarning is logged whenever the server starts or is reconfigured to skip TLS
+// certificate verification of Vendor Portal API requests
+const insecureTLSWarning = "TLS certificate verification of Vendor Portal API requests is DISABLED: " +
+	"anyone able to intercept the connection can read the API token and alter responses. " +
+	"Trust the intercepting proxy's certificate authority with --ca-bundle instead"
+
 // Server represents the MCP server instance that handles communication with AI agents.
 // It integrates with the Replicated Vendor Portal API to provide access to applications,
 // releases, channels, and customer data through the MCP protocol.
 type Server struct {
-	logger    logging.Logger
-	config    *config.Config
-	mcpServer *server.MCPServer
+	mu             sync.RWMutex
+	logger         logging.Logger
+	config         *config.Config
+	mcpServer      *server.MCPServer
+	client         vendorapi.Client
+	applications   vendorapi.Applications
+	channels       vendorapi.Channels
+	releases       vendorapi.Releases
+	customers      vendorapi.Customers
+	supportBundles vendorapi.SupportBundles
+	entitlements   vendorapi.Entitlements
+	images         vendorapi.Registry
+	installers     vendorapi.Installers
+	clusters       vendorapi.Clusters
+	vms            vendorapi.VMs
+	jobs           *jobs.Manager
+	state          *store.Store
+	metrics        *metrics.Registry
+	slo            *slo.Tracker
+	resolver       *resolver
+	searchIndexes  *searchIndexes
+	adoptionData   *adoptionCache
+	completions    *completionCache
+	confirmations  *confirmations
+	subscriptions  *resourceSubscriptions
+	audit          *audit.Log
+	registry       *registry
+	build          BuildInfo
+
+	// calls tracks in-flight tool calls, so shutdown can let them finish
+	calls sync.WaitGroup
+	// drainTimeout bounds how long shutdown waits for in-flight tool calls
+	drainTimeout time.Duration
+	// stopServing ends Start, once it is serving
+	stopServing context.CancelFunc
 }
 
 // NewServer creates a new MCP server instance with the provided configuration and logger.
-// It initializes the server with stdio transport and registers all available tools and resources.
+// It registers all available tools and resources; Start serves them over the configured transport.
 //
 // The server is configured with:
-// - Stdio transport for communication with AI agents
+// - Stdio or streamable HTTP transport for communication with AI agents
 // - Tool capabilities for all Replicated Vendor Portal operations
 // - Resource capabilities for accessing Replicated entities
+// - Prompt capabilities for common vendor workflows
+// - A Vendor Portal API client and slug resolver shared by all handlers
+// - A metrics registry recording tool calls and API requests
+// - A tracker measuring API requests against the Vendor Portal's service level objectives
+// - An audit log of mutating tool calls, when one is configured
 // - Proper logging integration (stderr only)
 //
 // Args:
@@ -47,20 +106,98 @@ func NewServer(cfg *config.Config, logger logging.Logger) (*Server, error) {
 		return nil, fmt.Errorf("logger is required")
 	}
 
+	// Create the Vendor Portal API client shared by all handlers
+	if cfg.Mock {
+		fixtures := cmp.Or(cfg.MockFixtures, "built-in")
+		logger.Warn("Mock mode: answering API requests from fixtures", "fixtures", fixtures)
+	}
+	if cfg.InsecureSkipTLSVerify {
+		logger.Warn(insecureTLSWarning)
+	}
+	client, err := api.NewClientWithLogger(clientConfig(cfg), logger.Slog())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	return NewServerWithServices(cfg, logger, vendorapi.NewHTTPServices(client))
+}
+
+// NewServerWithServices creates a server like NewServer whose handlers call the given Vendor
+// Portal services instead of the HTTP API client, such as test doubles, services wrapped in
+// middleware, or an alternative backend. Configuration changes are passed to services.Client.
+func NewServerWithServices(cfg *config.Config, logger logging.Logger, services *vendorapi.Services) (*Server, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration is required")
+	}
+	if logger == nil {
+		return nil, fmt.Errorf("logger is required")
+	}
+	if services == nil {
+		return nil, fmt.Errorf("vendor portal services are required")
+	}
+
 	logger.Info("Initializing MCP server", "version", "1.0.0")
 
-	// Create MCP server with tool and resource capabilities
+	// Create MCP server with tool and resource capabilities, recording resource subscriptions
+	subscriptions := newResourceSubscriptions()
 	mcpServer := server.NewMCPServer(
 		"replicated-mcp-server",
 		"1.0.0",
 		server.WithToolCapabilities(true),
-		server.WithResourceCapabilities(true, false), // subscribe=true, listChanged=false
+		server.WithResourceCapabilities(true, true), // subscribe=true, listChanged=true
+		server.WithPromptCapabilities(false),
+		server.WithHooks(subscriptions.hooks()),
 	)
 
+	serverMetrics := metrics.NewRegistry()
+	dependencySLO := slo.New(cfg.SLOObjectives(), logger)
+	services.Client.SetObserver(api.Observers(serverMetrics, dependencySLO))
+
+	auditLog, err := openAuditLog(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &Server{
-		logger:    logger,
-		config:    cfg,
-		mcpServer: mcpServer,
+		logger:         logger,
+		config:         cfg,
+		mcpServer:      mcpServer,
+		client:         services.Client,
+		applications:   services.Applications,
+		channels:       services.Channels,
+		releases:       services.Releases,
+		customers:      services.Customers,
+		supportBundles: services.SupportBundles,
+		entitlements:   services.Entitlements,
+		images:         services.Registry,
+		installers:     services.Installers,
+		clusters:       services.Clusters,
+		vms:            services.VMs,
+		jobs:           jobs.NewManager(jobs.DefaultRetention),
+		metrics:        serverMetrics,
+		slo:            dependencySLO,
+		resolver:       newResolver(services.Applications, defaultResolverTTL),
+		searchIndexes:  newSearchIndexes(defaultSearchIndexTTL),
+		adoptionData:   newAdoptionCache(defaultAdoptionCacheTTL),
+		completions:    newCompletionCache(defaultCompletionCacheTTL),
+		confirmations:  newConfirmations(defaultConfirmationTTL),
+		subscriptions:  subscriptions,
+		audit:          auditLog,
+		drainTimeout:   toolCallDrainTimeout,
+	}
+
+	// Record slug cache hit rates alongside the other metrics
+	s.resolver.observer = serverMetrics
+
+	// Keep job records and the slug cache in the state store so they survive restarts
+	s.resolver.persist = serverSlugCache{server: s}
+	s.jobs.SetRecorder(jobRecorder{server: s})
+
+	// Build tool and resource definitions once; registration and runtime changes share them
+	s.registry = newRegistry(s.defineTools(), s.defineResources())
+
+	if err := s.validateToolSelection(cfg); err != nil {
+		return nil, fmt.Errorf("invalid tool selection: %w", err)
 	}
 
 	// Register all tools and resources
@@ -72,13 +209,53 @@ func NewServer(cfg *config.Config, logger logging.Logger) (*Server, error) {
 		return nil, fmt.Errorf("failed to register resources: %w", err)
 	}
 
+	if err := s.registerPrompts(); err != nil {
+		return nil, fmt.Errorf("failed to register prompts: %w", err)
+	}
+
 	logger.Info("MCP server initialized successfully")
 	return s, nil
 }
 
-// Start begins serving the MCP protocol over stdio transport.
+// services returns the Vendor Portal services the handlers call
+func (s *Server) services() *vendorapi.Services {
+	return &vendorapi.Services{
+		Client:         s.client,
+		Applications:   s.applications,
+		Channels:       s.channels,
+		Releases:       s.releases,
+		Customers:      s.customers,
+		SupportBundles: s.supportBundles,
+		Entitlements:   s.entitlements,
+		Registry:       s.images,
+		Installers:     s.installers,
+		Clusters:       s.clusters,
+		VMs:            s.vms,
+	}
+}
+
+// SetBuildInfo records the server build reported by the get_server_info tool
+func (s *Server) SetBuildInfo(info BuildInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.build = info
+}
+
+// buildInfo returns the recorded server build
+func (s *Server) buildInfo() BuildInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.build
+}
+
+// Start begins serving the MCP protocol over the configured transport.
 // This method blocks until the server is stopped or encounters an error.
-// All MCP communication happens on stdout, while logging goes to stderr.
+// Over stdio, all MCP communication happens on stdout, while logging goes to stderr.
+// Over HTTP, clients connect to /mcp on the listen address, over TLS when a certificate or
+// autocert domains are configured, and each request is written to the access log,
+// separately from the application logs. While serving, the resources clients subscribe to
+// are checked for changes every resource poll interval. Once ctx is canceled or Stop is
+// called, no new requests are accepted and in-flight tool calls are given time to finish.
 //
 // Args:
 //
@@ -87,20 +264,26 @@ func NewServer(cfg *config.Config, logger logging.Logger) (*Server, error) {
 // Returns:
 //
 //	error: Error if server startup or operation fails
-func (s *Server) Start(_ context.Context) error {
-	s.logger.Info("Starting MCP server on stdio transport")
+func (s *Server) Start(ctx context.Context) error {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	s.mu.Lock()
+	s.stopServing = stop
+	s.mu.Unlock()
 
-	// Start serving on stdio - this blocks until shutdown
-	if err := server.ServeStdio(s.mcpServer); err != nil {
-		s.logger.Error("MCP server error", "error", err)
-		return fmt.Errorf("stdio server error: %w", err)
-	}
+	// Check subscribed resources for changes while serving
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	go s.watchResources(watchCtx)
 
-	return nil
+	if s.currentConfig().Transport == config.TransportHTTP {
+		return s.serveHTTP(ctx)
+	}
+	return s.serveStdio(ctx, os.Stdin, os.Stdout)
 }
 
 // Stop gracefully shuts down the MCP server.
-// It ensures all ongoing operations complete and resources are cleaned up properly.
+// It stops serving, waits for in-flight tool calls to finish, and cleans up resources.
 //
 // Args:
 //
@@ -109,18 +292,63 @@ func (s *Server) Start(_ context.Context) error {
 // Returns:
 //
 //	error: Error if shutdown fails
-func (s *Server) Stop(_ context.Context) error {
+func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info("Stopping MCP server")
 
-	// Note: The mark3labs/mcp-go library doesn't expose a Stop method for stdio servers
-	// The server will stop when the stdio connection closes or context is canceled
+	s.mu.RLock()
+	stopServing := s.stopServing
+	s.mu.RUnlock()
+	if stopServing != nil {
+		stopServing()
+	}
+	if err := s.drainToolCalls(ctx); err != nil {
+		s.logger.Warn("Stopping with tool calls still running", "error", err)
+	}
+	s.jobs.Shutdown()
+
+	s.mu.Lock()
+	auditLog := s.audit
+	s.audit = nil
+	s.mu.Unlock()
+	s.closeAuditLog(auditLog)
+
+	if err := s.client.Close(); err != nil {
+		s.logger.Warn("Failed to close the API client", "error", err)
+	}
+
 	s.logger.Info("MCP server stopped")
 	return nil
 }
 
+// withCallTracking wraps a tool handler so shutdown knows the call is in flight
+func (s *Server) withCallTracking(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.calls.Add(1)
+		defer s.calls.Done()
+		return next(ctx, request)
+	}
+}
+
+// drainToolCalls waits for in-flight tool calls to finish, returning ctx's error if it
+// ends first
+func (s *Server) drainToolCalls(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		s.calls.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for tool calls to finish: %w", ctx.Err())
+	}
+}
+
 // registerTools registers all available MCP tools with the server.
-// Each tool is defined with proper JSON schema validation and empty handler implementations.
-// The actual business logic will be implemented in Step 7 (MCP Handlers).
+// Tool definitions come from the registry built once in NewServer; tools withheld
+// by the configured policy (such as mutating tools in read-only mode) are skipped.
 //
 // Returns:
 //
@@ -128,18 +356,47 @@ func (s *Server) Stop(_ context.Context) error {
 func (s *Server) registerTools() error {
 	s.logger.Debug("Registering MCP tools")
 
-	tools := s.defineTools()
+	tools := s.exposedTools()
 	for _, tool := range tools {
-		s.mcpServer.AddTool(*tool.definition, tool.handler)
-		s.logger.Debug("Registered tool", "name", tool.definition.Name)
+		s.registerTool(tool)
 	}
 
 	s.logger.Info("Successfully registered tools", "count", len(tools))
 	return nil
 }
 
+// registerTool registers a single tool with the MCP server, applying any handler wrappers
+func (s *Server) registerTool(tool toolDefinition) {
+	s.mcpServer.AddTools(s.serverTool(tool))
+	s.logger.Debug("Registered tool", "name", tool.definition.Name)
+}
+
+// serverTool builds the MCP library registration for a tool, wrapping its handler
+// according to the current configuration. With a default application, app_id becomes
+// optional, get_* and list_* tools accept fields to trim their results, and list_* tools
+// accept a format for their output. Results are cut down to the result size limit. Errors are
+// always reported as tool results, every call is recorded in the server's metrics and traced,
+// mutating calls are recorded in the audit log, and every call is assigned a request ID for log
+// correlation.
+func (s *Server) serverTool(tool toolDefinition) server.ServerTool {
+	handler := withResultShaping(tool.definition.Name, tool.handler)
+	handler = s.withErrorResults(tool.definition.Name, s.withTimeout(tool,
+		s.withAudit(tool, s.withArgumentLimits(s.withResultLimit(handler)))))
+	if s.currentConfig().DevMode {
+		handler = s.withDebugInfo(tool.definition.Name, handler)
+	}
+	handler = s.withMetrics(tool.definition.Name, handler)
+	handler = s.withTracing(tool.definition.Name, handler)
+	handler = s.withRequestID(tool.definition.Name, handler)
+	handler = s.withCallTracking(handler)
+	definition := withDefaultApp(*tool.definition, s.currentConfig().App)
+	return server.ServerTool{Tool: withFormatParameter(withFieldsParameter(definition)), Handler: handler}
+}
+
 // registerResources registers all available MCP resources with the server.
 // Resources provide access to Replicated entities through standardized URIs.
+// Resource definitions come from the registry built once in NewServer; resources in
+// disabled tool categories are skipped.
 //
 // Returns:
 //
@@ -147,7 +404,7 @@ func (s *Server) registerTools() error {
 func (s *Server) registerResources() error {
 	s.logger.Debug("Registering MCP resources")
 
-	resources := s.defineResources()
+	resources := s.exposedResources()
 	for _, resource := range resources {
 		s.mcpServer.AddResource(*resource.definition, resource.handler)
 		s.logger.Debug("Registered resource", "uri", resource.definition.URI)
@@ -156,3 +413,304 @@ func (s *Server) registerResources() error {
 	s.logger.Info("Successfully registered resources", "count", len(resources))
 	return nil
 }
+
+// addTool adds or replaces a tool at runtime. Connected clients are sent a
+// notifications/tools/list_changed message so they see the new tool set.
+// Tools withheld by the configured policy are recorded but not registered.
+func (s *Server) addTool(tool toolDefinition) {
+	s.registry.putTool(tool)
+	if s.toolPermitted(tool) {
+		s.registerTool(tool)
+	}
+}
+
+// removeTool removes a tool at runtime, notifying connected clients if it was registered
+func (s *Server) removeTool(name string) {
+	if s.registry.deleteTool(name) {
+		s.mcpServer.DeleteTools(name)
+		s.logger.Debug("Removed tool", "name", name)
+	}
+}
+
+// addResource adds or replaces a resource at runtime. Connected clients are sent a
+// notifications/resources/list_changed message so they see the new resource set.
+// Resources withheld by the configured policy are recorded but not registered.
+func (s *Server) addResource(resource resourceDefinition) {
+	s.registry.putResource(resource)
+	if s.resourcePermitted(resource) {
+		s.mcpServer.AddResource(*resource.definition, resource.handler)
+		s.logger.Debug("Registered resource", "uri", resource.definition.URI)
+	}
+}
+
+// removeResource removes a resource at runtime, notifying connected clients if it was registered
+func (s *Server) removeResource(uri string) {
+	if s.registry.deleteResource(uri) {
+		s.mcpServer.RemoveResource(uri)
+		s.logger.Debug("Removed resource", "uri", uri)
+	}
+}
+
+// registerPrompts registers all available MCP prompts with the server.
+// Prompts provide parameterized starting points for common vendor workflows.
+//
+// Returns:
+//
+//	error: Error if prompt registration fails
+func (s *Server) registerPrompts() error {
+	s.logger.Debug("Registering MCP prompts")
+
+	prompts := s.definePrompts()
+	for _, prompt := range prompts {
+		s.mcpServer.AddPrompt(*prompt.definition, prompt.handler)
+		s.logger.Debug("Registered prompt", "name", prompt.definition.Name)
+	}
+
+	s.logger.Info("Successfully registered prompts", "count", len(prompts))
+	return nil
+}
+
+// Reconfigure applies a new configuration to a running server, for example after a
+// configuration reload or an API token switch. The API client picks up token, endpoint,
+// and timeout changes immediately, and a changed audit log path is opened in place of the
+// previous one. When the change affects the exposed tools, the tools
+// are re-registered and connected clients receive a notifications/tools/list_changed
+// message so they see the new tool set without reconnecting.
+//
+// Args:
+//
+//	cfg: The new configuration
+//
+// Returns:
+//
+//	error: Error if the configuration cannot be applied
+func (s *Server) Reconfigure(cfg *config.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("configuration is required")
+	}
+
+	if err := s.validateToolSelection(cfg); err != nil {
+		return fmt.Errorf("invalid tool selection: %w", err)
+	}
+
+	previous := s.currentConfig()
+
+	// Open a new audit log before changing anything, so a bad path leaves the server as it was
+	var auditLog *audit.Log
+	auditChanged := previous.AuditLogFile != cfg.AuditLogFile ||
+		!slices.Equal(previous.RedactPatterns, cfg.RedactPatterns)
+	if auditChanged {
+		var err error
+		if auditLog, err = openAuditLog(cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := s.client.UpdateConfig(clientConfig(cfg)); err != nil {
+		s.closeAuditLog(auditLog)
+		return fmt.Errorf("failed to update API client: %w", err)
+	}
+	if cfg.InsecureSkipTLSVerify && !previous.InsecureSkipTLSVerify {
+		s.logger.Warn(insecureTLSWarning)
+	}
+
+	s.slo.SetObjectives(cfg.SLOObjectives())
+
+	s.mu.Lock()
+	s.config = cfg
+	if auditChanged {
+		auditLog, s.audit = s.audit, auditLog
+	}
+	s.mu.Unlock()
+
+	// The replaced audit log, if any, is no longer written to
+	if auditChanged {
+		s.closeAuditLog(auditLog)
+	}
+
+	// A different token or endpoint may belong to a different team with different applications
+	if previous.APIToken != cfg.APIToken || previous.Endpoint != cfg.Endpoint {
+		s.resolver.Invalidate()
+		s.searchIndexes.Invalidate()
+		s.adoptionData.Invalidate()
+	}
+
+	// Indexes are dropped when the index is turned off, and when the scan limit changes since
+	// they would cover a different set of customers
+	if previous.SearchScanLimit != cfg.SearchScanLimit || !cfg.SearchIndex {
+		s.searchIndexes.Invalidate()
+	}
+
+	if toolSetAffected(previous, cfg) {
+		s.syncTools()
+	}
+
+	if resourceSetAffected(previous, cfg) {
+		s.syncResources()
+	}
+
+	if settings := restartRequired(previous, cfg); len(settings) > 0 {
+		s.logger.Warn("Reloaded settings that only take effect after a restart were not applied",
+			"settings", settings)
+	}
+
+	s.logger.Info("MCP server reconfigured", "config", cfg.String())
+	return nil
+}
+
+// syncTools replaces the registered tools with the current registry contents in a
+// single update, so clients receive one list_changed notification per change.
+func (s *Server) syncTools() {
+	tools := s.exposedTools()
+	serverTools := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		serverTools = append(serverTools, s.serverTool(tool))
+	}
+
+	s.mcpServer.SetTools(serverTools...)
+	s.logger.Info("Tool registrations updated", "count", len(serverTools))
+}
+
+// syncResources replaces the registered resources with the permitted registry contents
+// in a single update, so clients receive one list_changed notification per change.
+func (s *Server) syncResources() {
+	resources := s.exposedResources()
+	serverResources := make([]server.ServerResource, 0, len(resources))
+	for _, resource := range resources {
+		serverResources = append(serverResources,
+			server.ServerResource{Resource: *resource.definition, Handler: resource.handler})
+	}
+
+	s.mcpServer.SetResources(serverResources...)
+	s.logger.Info("Resource registrations updated", "count", len(serverResources))
+}
+
+// toolSetAffected reports whether a configuration change alters the tools clients see.
+// A new token or endpoint may carry different permissions, developer mode changes
+// the shape of every tool result, read-only mode withholds mutating tools, a default
+// application makes app_id optional, run_cluster_command is exposed only when cluster commands
+// are enabled, and the enabled and disabled lists select tools directly.
+func toolSetAffected(previous, next *config.Config) bool {
+	return previous.APIToken != next.APIToken ||
+		previous.Endpoint != next.Endpoint ||
+		previous.DevMode != next.DevMode ||
+		previous.ReadOnly != next.ReadOnly ||
+		previous.App != next.App ||
+		previous.ClusterCommands != next.ClusterCommands ||
+		resourceSetAffected(previous, next)
+}
+
+// resourceSetAffected reports whether a configuration change alters the enabled or
+// disabled tool lists, which also select resources
+func resourceSetAffected(previous, next *config.Config) bool {
+	return !slices.Equal(previous.EnabledTools, next.EnabledTools) ||
+		!slices.Equal(previous.DisabledTools, next.DisabledTools)
+}
+
+// restartRequired returns the flags of the settings a configuration change alters that are
+// only read at startup: the logger, tracing, and the HTTP transport's listener, TLS, auth, and
+// request handling are set up once. Subscribed resources are checked at the new interval, but
+// checks turned off at startup are not started by a reload.
+func restartRequired(previous, next *config.Config) []string {
+	settings := []struct {
+		flag    string
+		changed bool
+	}{
+		{flag: "log-level", changed: previous.LogLevel != next.LogLevel},
+		{flag: "otlp-endpoint", changed: previous.OTLPEndpoint != next.OTLPEndpoint},
+		{flag: "transport", changed: previous.Transport != next.Transport},
+		{flag: "listen", changed: previous.ListenAddress != next.ListenAddress},
+		{flag: "http-auth-token", changed: previous.HTTPAuthToken != next.HTTPAuthToken},
+		{flag: "tls-cert", changed: previous.TLSCertFile != next.TLSCertFile},
+		{flag: "tls-key", changed: previous.TLSKeyFile != next.TLSKeyFile},
+		{flag: "tls-autocert-domains", changed: !slices.Equal(previous.TLSAutocertDomains, next.TLSAutocertDomains)},
+		{flag: "allowed-origins", changed: !slices.Equal(previous.AllowedOrigins, next.AllowedOrigins)},
+		{flag: "max-request-bytes", changed: previous.MaxRequestBytes != next.MaxRequestBytes},
+		{flag: "access-log-file", changed: previous.AccessLogFile != next.AccessLogFile},
+		{flag: "access-log-sample-rate", changed: previous.AccessLogSampleRate != next.AccessLogSampleRate},
+		{flag: "metrics-endpoint", changed: previous.MetricsEndpoint != next.MetricsEndpoint},
+		{
+			flag:    "resource-poll-interval",
+			changed: previous.ResourcePollInterval <= 0 && next.ResourcePollInterval > 0,
+		},
+	}
+
+	var flags []string
+	for _, setting := range settings {
+		if setting.changed {
+			flags = append(flags, setting.flag)
+		}
+	}
+	return flags
+}
+
+// currentConfig returns the configuration currently in effect
+func (s *Server) currentConfig() *config.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// clientConfig derives the API client configuration from the server configuration
+func clientConfig(cfg *config.Config) api.ClientConfig {
+	baseURL := cfg.Endpoint
+	if baseURL == "" {
+		baseURL = api.DefaultBaseURL
+	}
+
+	// Hedge classes were validated when the configuration was loaded
+	delays, _ := api.ParseHedgeClasses(cfg.HedgeClasses)
+
+	// Fixture responses must not end up in the disk cache alongside real ones
+	diskCacheDir := ""
+	if cfg.DiskCache && !cfg.Mock {
+		diskCacheDir = cfg.CacheDirectory()
+	}
+
+	return api.ClientConfig{
+		APIToken:    cfg.APIToken,
+		BaseURL:     baseURL,
+		FallbackURL: cfg.FallbackEndpoint,
+		Timeout:     cfg.Timeout,
+		Hedging: api.HedgePolicy{
+			Enabled: cfg.HedgeReads || len(cfg.HedgeClasses) > 0,
+			Delays:  delays,
+		},
+		MaxConcurrentRequests: cfg.MaxConcurrentRequests,
+		RequestsPerSecond:     cfg.RequestsPerSecond,
+		HARFile:               cfg.HARFile,
+		Redactor:              redactorFor(cfg),
+		DiskCacheDir:          diskCacheDir,
+		Fixtures:              fixturesFor(cfg),
+		RecordDir:             cfg.RecordFixtures,
+		ProxyURL:              cfg.Proxy,
+		CABundle:              cfg.CABundle,
+		InsecureSkipVerify:    cfg.InsecureSkipTLSVerify,
+		ClientCertFile:        cfg.ClientCertFile,
+		ClientKeyFile:         cfg.ClientKeyFile,
+		Headers:               cfg.APIHeaders,
+	}
+}
+
+// fixturesFor returns the fixtures API requests are answered from in mock mode, or nil
+// when requests go to the Vendor Portal API
+func fixturesFor(cfg *config.Config) fs.FS {
+	switch {
+	case !cfg.Mock:
+		return nil
+	case cfg.MockFixtures != "":
+		return os.DirFS(cfg.MockFixtures)
+	default:
+		return api.DefaultFixtures()
+	}
+}
+
+// redactorFor returns the redactor for the configured redaction patterns
+func redactorFor(cfg *config.Config) *redact.Redactor {
+	// Redaction patterns were validated when the configuration was loaded
+	redactor, err := redact.New(cfg.RedactPatterns)
+	if err != nil {
+		return redact.Default()
+	}
+	return redactor
+}
@@ -2,11 +2,21 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
 	"github.com/crdant/replicated-mcp-server/pkg/config"
 	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
 )
 
 // Test constants
@@ -15,6 +25,21 @@ const (
 	applicationResourceURI   = "replicated://applications/{application}"
 )
 
+func TestMain(m *testing.M) {
+	// Servers without a data directory keep their state under the home directory; point it
+	// at a temporary directory so tests never read or write the real one
+	home, err := os.MkdirTemp("", "replicated-mcp-server-test")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create test home directory: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", home)
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
 func TestNewServer(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -128,9 +153,12 @@ func TestServerToolRegistration(t *testing.T) {
 	}
 
 	// Test that tools are registered - this happens during NewServer
-	// We expect 12 tools to be registered (3 each for applications, releases, channels, customers)
-	tools := server.defineTools()
-	expectedToolCount := 12
+	// We expect 75 tools to be registered (6 for applications, 10 for releases, 15 for channels,
+	// 16 for customers, 4 for entitlements, 9 for clusters and VMs, 4 for the server, 2 each for
+	// support bundles, snapshots, tags, and notes, and 1 each for jobs, bulk operations, and
+	// cross-entity search)
+	tools := server.registry.Tools()
+	expectedToolCount := 75
 
 	if len(tools) != expectedToolCount {
 		t.Errorf("Expected %d tools to be defined, got %d", expectedToolCount, len(tools))
@@ -138,10 +166,29 @@ func TestServerToolRegistration(t *testing.T) {
 
 	// Verify all expected tools are present
 	expectedToolNames := []string{
-		"list_applications", "get_application", "search_applications",
-		"list_releases", "get_release", "search_releases",
-		"list_channels", "get_channel", "search_channels",
-		"list_customers", "get_customer", "search_customers",
+		"list_applications", "get_application", "search_applications", "create_application",
+		"archive_application", "list_activity",
+		"list_releases", "get_release", "search_releases", "promote_release", "compare_releases",
+		"get_release_changelog", "download_release_archive", "list_release_images", "get_image_usage",
+		"list_releases_all_apps",
+		"list_channels", "get_channel", "search_channels", "get_channel_adoption",
+		"report_release_adoption", "get_adoption_timeseries", "report_version_drift",
+		"list_kurl_installers", "get_embedded_cluster_config", "compare_channels",
+		"get_channel_versioning", "set_channel_versioning", "get_channel_settings", "update_channel_settings",
+		"list_channels_all_apps",
+		"list_customers", "get_customer", "search_customers", "summarize_customer",
+		"get_customer_version_breakdown", "report_k8s_distributions", "list_customer_downloads", "list_instance_events",
+		"archive_customer", "unarchive_customer", "update_customer", "tag_customer",
+		"annotate_customer", "list_customers_all_apps", "find_customer",
+		"lookup_license",
+		"list_support_bundles", "get_support_bundle",
+		"list_license_fields", "get_customer_entitlements", "set_customer_entitlement",
+		"evaluate_entitlement",
+		"list_clusters", "create_cluster", "delete_cluster", "get_cluster_kubeconfig",
+		"list_vms", "create_vm", "delete_vm", "get_vm_ssh_endpoint", "run_cluster_command",
+		"export_account_snapshot", "diff_account_snapshots", "get_job", "bulk", "search_all",
+		"tag_entity", "list_by_tag", "add_note", "list_notes", "get_server_info", "get_server_metrics",
+		"setup_assistant", "get_dependency_slo",
 	}
 
 	foundTools := make(map[string]bool)
@@ -170,8 +217,8 @@ func TestServerResourceRegistration(t *testing.T) {
 	}
 
 	// Test that resources are registered
-	resources := server.defineResources()
-	expectedResourceCount := 4
+	resources := server.registry.Resources()
+	expectedResourceCount := 6
 
 	if len(resources) != expectedResourceCount {
 		t.Errorf("Expected %d resources to be defined, got %d", expectedResourceCount, len(resources))
@@ -181,8 +228,10 @@ func TestServerResourceRegistration(t *testing.T) {
 	expectedResourceURIs := []string{
 		"replicated://applications/{application}",
 		"replicated://applications/{application}/releases/{release}",
+		"replicated://applications/{application}/releases/{release}/manifests",
 		"replicated://applications/{application}/channels/{channel}",
 		"replicated://applications/{application}/customers/{customer}",
+		"replicated://applications/{application}/customers/{customer}/license",
 	}
 
 	foundResources := make(map[string]bool)
@@ -210,7 +259,7 @@ func TestServerToolDefinitions(t *testing.T) {
 		t.Fatalf("Failed to create server: %v", err)
 	}
 
-	tools := server.defineTools()
+	tools := server.registry.Tools()
 
 	// Test a specific tool definition (list_applications)
 	var listAppsTool *toolDefinition
@@ -252,7 +301,7 @@ func TestServerResourceDefinitions(t *testing.T) {
 		t.Fatalf("Failed to create server: %v", err)
 	}
 
-	resources := server.defineResources()
+	resources := server.registry.Resources()
 
 	// Test a specific resource definition (application)
 	var appResource *resourceDefinition
@@ -288,3 +337,344 @@ func TestServerResourceDefinitions(t *testing.T) {
 		t.Error("Expected resource to have a handler function")
 	}
 }
+
+// Test fixtures for the fake Vendor Portal API
+const (
+	testAppID   = "test-app-123"
+	testAppSlug = "test-app"
+	testAppJSON = `{
+		"id": "test-app-123",
+		"name": "Test App",
+		"slug": "test-app",
+		"teamId": "team-1",
+		"createdAt": "2023-01-01T00:00:00Z",
+		"updatedAt": "2023-01-01T00:00:00Z",
+		"isActive": true
+	}`
+)
+
+// newTestVendorAPI starts a fake Vendor Portal API that serves a single application
+func newTestVendorAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [{"id": "release-1", "version": "1.0.0", "sequence": 1},
+			{"id": "release-2", "version": "1.1.0", "sequence": 2, "notes": "Adds SAML login"}]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/"+testAppID+"/release/{sequence}", serveTestRelease)
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [{"id": "channel-1", "name": "Stable"}, {"id": "channel-2", "name": "Beta"}]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"customers": [{"id": "customer-1", "name": "Acme Corp"}, {"id": "customer-2", "name": "Globex"}],
+			"totalCustomers": 2}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestServer creates an MCP server whose API client points at the given endpoint
+func newTestServer(t *testing.T, endpoint string) *Server {
+	t.Helper()
+
+	cfg := &config.Config{
+		APIToken: "test-token",
+		LogLevel: "info",
+		Timeout:  30 * time.Second,
+		Endpoint: endpoint,
+		DataDir:  t.TempDir(),
+	}
+
+	server, err := NewServer(cfg, logging.NewLogger("info"))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server
+}
+
+// testApp is the application served by fake services, matching the fake Vendor Portal API's
+var testApp = models.Application{ID: testAppID, Name: "Test App", Slug: testAppSlug}
+
+// newTestServerWithServices creates an MCP server calling the given fake services instead of
+// the Vendor Portal API. Without a client or applications, it uses a client accepting any
+// token and serves testApp.
+func newTestServerWithServices(t *testing.T, services vendorapi.Services) *Server {
+	t.Helper()
+
+	if services.Client == nil {
+		services.Client = fakeClient{}
+	}
+	if services.Applications == nil {
+		services.Applications = &fakeApplications{apps: []models.Application{testApp}}
+	}
+
+	cfg := &config.Config{LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir()}
+	server, err := NewServerWithServices(cfg, logging.NewLogger("info"), &services)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server
+}
+
+func TestServerReconfigure(t *testing.T) {
+	server := newTestServer(t, "")
+
+	session := newTestSession()
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	if err := server.Reconfigure(nil); err == nil {
+		t.Error("Expected error for nil configuration")
+	}
+
+	// An unrelated change does not alter the tool set
+	unchanged := *server.currentConfig()
+	unchanged.Timeout = 60 * time.Second
+	if err := server.Reconfigure(&unchanged); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case notification := <-session.notifications:
+		t.Errorf("Expected no notification, got '%s'", notification.Method)
+	default:
+	}
+
+	// Enabling developer mode changes every tool result
+	devMode := unchanged
+	devMode.DevMode = true
+	if err := server.Reconfigure(&devMode); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	session.expectNotification(t, mcp.MethodNotificationToolsListChanged)
+
+	if !server.currentConfig().DevMode {
+		t.Error("Expected developer mode to be enabled after reconfiguration")
+	}
+	if len(listedToolNames(t, server)) != len(server.exposedTools()) {
+		t.Error("Expected all permitted tools to remain registered after reconfiguration")
+	}
+}
+
+func TestToolSetAffected(t *testing.T) {
+	base := config.Config{APIToken: "token", Endpoint: "https://api.example.com"}
+
+	tests := []struct {
+		name   string
+		modify func(c *config.Config)
+		want   bool
+	}{
+		{name: "no change", modify: func(_ *config.Config) {}, want: false},
+		{name: "log level", modify: func(c *config.Config) { c.LogLevel = "debug" }, want: false},
+		{name: "token switch", modify: func(c *config.Config) { c.APIToken = "other" }, want: true},
+		{name: "endpoint", modify: func(c *config.Config) { c.Endpoint = "https://other.example.com" }, want: true},
+		{name: "developer mode", modify: func(c *config.Config) { c.DevMode = true }, want: true},
+		{name: "read-only mode", modify: func(c *config.Config) { c.ReadOnly = true }, want: true},
+		{name: "default application", modify: func(c *config.Config) { c.App = "test-app" }, want: true},
+		{name: "cluster commands", modify: func(c *config.Config) { c.ClusterCommands = true }, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.modify(&next)
+			if got := toolSetAffected(&base, &next); got != tt.want {
+				t.Errorf("toolSetAffected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	base := config.Config{
+		APIToken: "token", LogLevel: "info", Transport: config.TransportHTTP, ListenAddress: "127.0.0.1:8080",
+		ResourcePollInterval: time.Minute,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *config.Config)
+		want   []string
+	}{
+		{name: "no change", modify: func(_ *config.Config) {}},
+		{name: "token switch", modify: func(c *config.Config) { c.APIToken = "other" }},
+		{name: "read-only mode", modify: func(c *config.Config) { c.ReadOnly = true }},
+		{name: "log level", modify: func(c *config.Config) { c.LogLevel = "debug" }, want: []string{"log-level"}},
+		{
+			name: "listener",
+			modify: func(c *config.Config) {
+				c.ListenAddress = "0.0.0.0:8443"
+				c.HTTPAuthToken = "client-token"
+				c.TLSAutocertDomains = []string{"mcp.example.com"}
+			},
+			want: []string{"listen", "http-auth-token", "tls-autocert-domains"},
+		},
+		{name: "poll interval", modify: func(c *config.Config) { c.ResourcePollInterval = time.Hour }},
+		{name: "polling turned off", modify: func(c *config.Config) { c.ResourcePollInterval = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.modify(&next)
+			if got := restartRequired(&base, &next); !slices.Equal(got, tt.want) {
+				t.Errorf("restartRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Checks turned off at startup are not started by a reload
+	off := base
+	off.ResourcePollInterval = 0
+	if got := restartRequired(&off, &base); !slices.Equal(got, []string{"resource-poll-interval"}) {
+		t.Errorf("restartRequired() = %v, want the poll interval", got)
+	}
+}
+
+func TestClientConfigDiskCache(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{name: "disabled", cfg: config.Config{CacheDir: "/var/cache/replicated"}},
+		{
+			name: "enabled",
+			cfg:  config.Config{DiskCache: true, CacheDir: "/var/cache/replicated"},
+			want: "/var/cache/replicated",
+		},
+		{
+			name: "mock mode",
+			cfg:  config.Config{DiskCache: true, CacheDir: "/var/cache/replicated", Mock: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientConfig(&tt.cfg).DiskCacheDir; got != tt.want {
+				t.Errorf("DiskCacheDir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerMockMode(t *testing.T) {
+	cfg := &config.Config{
+		LogLevel: "info",
+		Timeout:  30 * time.Second,
+		DataDir:  t.TempDir(),
+		Mock:     true,
+	}
+
+	server, err := NewServer(cfg, logging.NewLogger("info"))
+	if err != nil {
+		t.Fatalf("Failed to create server in mock mode without a token: %v", err)
+	}
+
+	tests := []struct {
+		toolName     string
+		args         map[string]any
+		expectInText string
+	}{
+		{toolName: "list_applications", args: map[string]any{}, expectInText: `"demo-app"`},
+		{toolName: "report_version_drift", args: map[string]any{"app_id": "demo-app"}, expectInText: `"Globex"`},
+		{
+			toolName:     "get_channel_versioning",
+			args:         map[string]any{"app_id": "demo-app", "channel_id": "stable"},
+			expectInText: `"latest_version_label": "1.2.0"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toolName, func(t *testing.T) {
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !contains(resultText(t, result), tt.expectInText) {
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, resultText(t, result))
+			}
+		})
+	}
+}
+
+// fakeClient stands in for the Vendor Portal API client, accepting any token. Methods it
+// does not override panic through the nil embedded interface.
+type fakeClient struct {
+	vendorapi.Client
+}
+
+func (fakeClient) SetObserver(api.RequestObserver) {}
+
+func (fakeClient) VerifyToken(context.Context) error { return nil }
+
+func (fakeClient) Close() error { return nil }
+
+// applicationsService lets fakeApplications embed the interface without its field name
+// clashing with the Applications method
+type applicationsService = vendorapi.Applications
+
+// fakeApplications serves a fixed list of applications without the Vendor Portal API
+type fakeApplications struct {
+	applicationsService
+	apps []models.Application
+}
+
+func (f *fakeApplications) ListApplications(
+	context.Context, *api.ListApplicationsOptions,
+) (*api.ApplicationList, error) {
+	return &api.ApplicationList{Applications: f.apps}, nil
+}
+
+func (f *fakeApplications) GetApplication(_ context.Context, id string) (*models.Application, error) {
+	for _, app := range f.apps {
+		if app.ID == id {
+			return &app, nil
+		}
+	}
+	return nil, &api.Error{StatusCode: http.StatusNotFound, Message: "Not Found"}
+}
+
+func TestNewServerWithServices(t *testing.T) {
+	cfg := &config.Config{LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir()}
+
+	if _, err := NewServerWithServices(cfg, logging.NewLogger("info"), nil); err == nil {
+		t.Error("Expected an error without services")
+	}
+
+	services := &vendorapi.Services{
+		Client:       fakeClient{},
+		Applications: &fakeApplications{apps: []models.Application{{ID: "app-fake", Name: "Fake", Slug: "fake"}}},
+	}
+	server, err := NewServerWithServices(cfg, logging.NewLogger("info"), services)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tool, ok := server.registry.Tool("get_application")
+	if !ok {
+		t.Fatal("Tool 'get_application' not found")
+	}
+	result, err := tool.handler(context.Background(),
+		createMockCallToolRequest("get_application", map[string]any{"app_id": "fake"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !contains(resultText(t, result), `"id": "app-fake"`) {
+		t.Errorf("Expected the application from the injected service, got '%s'", resultText(t, result))
+	}
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/jobs"
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+)
+
+// accountKeyBytes is how much of the account hash names per-account cache entries
+const accountKeyBytes = 8
+
+// stateStore returns the state store for the configured data directory
+func (s *Server) stateStore() *store.Store {
+	dir := s.currentConfig().DataDirectory()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil || s.state.Dir() != dir {
+		s.state = store.Open(dir)
+	}
+	return s.state
+}
+
+// jobRecorder persists background jobs in the state store so get_job can report on jobs
+// started before a restart
+type jobRecorder struct {
+	server *Server
+}
+
+// SaveJob records a job's current state
+func (r jobRecorder) SaveJob(job jobs.Job) {
+	if err := r.server.stateStore().Put(store.BucketJobs, job.ID, job); err != nil {
+		r.server.logger.Error("Failed to record job", "job_id", job.ID, "error", err)
+	}
+}
+
+// DeleteJob removes a pruned job's record
+func (r jobRecorder) DeleteJob(id string) {
+	if err := r.server.stateStore().Delete(store.BucketJobs, id); err != nil {
+		r.server.logger.Error("Failed to delete job record", "job_id", id, "error", err)
+	}
+}
+
+// LoadJob reads a job's record
+func (r jobRecorder) LoadJob(id string) (jobs.Job, bool) {
+	var job jobs.Job
+	found, err := r.server.stateStore().Get(store.BucketJobs, id, &job)
+	if err != nil {
+		r.server.logger.Error("Failed to read job record", "job_id", id, "error", err)
+		return jobs.Job{}, false
+	}
+	return job, found
+}
+
+// serverSlugCache persists the resolver's slug mapping in the state store. Entries are
+// kept per endpoint and token, since data directories can be shared by servers connected
+// to different vendor accounts.
+type serverSlugCache struct {
+	server *Server
+}
+
+// cacheName names the cache entry for the current account without revealing the token
+func (c serverSlugCache) cacheName() string {
+	cfg := c.server.currentConfig()
+	sum := sha256.Sum256([]byte(cfg.Endpoint + "\n" + cfg.APIToken))
+	return resolverCacheName + ":" + hex.EncodeToString(sum[:accountKeyBytes])
+}
+
+func (c serverSlugCache) loadSlugCache(maxAge time.Duration) (slugCache, time.Time, bool) {
+	var cache slugCache
+	savedAt, found, err := c.server.stateStore().LoadCache(c.cacheName(), maxAge, &cache)
+	if err != nil {
+		c.server.logger.Error("Failed to read cached application slugs", "error", err)
+		return slugCache{}, time.Time{}, false
+	}
+	return cache, savedAt, found
+}
+
+func (c serverSlugCache) saveSlugCache(cache slugCache) {
+	if err := c.server.stateStore().SaveCache(c.cacheName(), cache); err != nil {
+		c.server.logger.Error("Failed to cache application slugs", "error", err)
+	}
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/jobs"
+)
+
+func TestJobRecorder_SurvivesRestart(t *testing.T) {
+	dataDir := t.TempDir()
+
+	first := newTestServer(t, "")
+	first.config.DataDir = dataDir
+	job, err := first.jobs.Start("test_job", func(context.Context) (any, error) { return "done", nil })
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+	if _, err := first.jobs.Wait(context.Background(), job.ID); err != nil {
+		t.Fatalf("Failed to wait for job: %v", err)
+	}
+	first.jobs.Shutdown()
+
+	// A second server on the same data directory reports the finished job
+	second := newTestServer(t, "")
+	second.config.DataDir = dataDir
+	t.Cleanup(second.jobs.Shutdown)
+
+	got, ok := second.jobs.Get(job.ID)
+	if !ok {
+		t.Fatal("Expected the job to be found after a restart")
+	}
+	if got.Status != jobs.StatusSucceeded || got.Result != "done" {
+		t.Errorf("Unexpected job after restart: %+v", got)
+	}
+}
+
+func TestServerSlugCache(t *testing.T) {
+	var listCalls atomic.Int32
+	vendorAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		listCalls.Add(1)
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	}))
+	t.Cleanup(vendorAPI.Close)
+	dataDir := t.TempDir()
+
+	tests := []struct {
+		name      string
+		token     string
+		wantCalls int32
+	}{
+		{name: "first server lists applications", token: "test-token", wantCalls: 1},
+		{name: "restarted server uses the saved slugs", token: "test-token", wantCalls: 1},
+		{name: "another account does not", token: "other-token", wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, vendorAPI.URL)
+			server.config.DataDir = dataDir
+			server.config.APIToken = tt.token
+
+			id, err := server.resolver.ResolveApplicationID(context.Background(), testAppSlug)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if id != testAppID {
+				t.Errorf("Expected %s, got %s", testAppID, id)
+			}
+			if calls := listCalls.Load(); calls != tt.wantCalls {
+				t.Errorf("Expected %d application list calls in total, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Resource subscription requests. mcp-go advertises subscriptions but does not route these
+// methods, so they are recorded from a request hook before the library answers them.
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+// resourceSubscriptions records the resources each client session subscribed to, and the
+// fingerprint of each resource when it was last checked
+type resourceSubscriptions struct {
+	mu           sync.Mutex
+	sessions     map[string]map[string]bool
+	fingerprints map[string]string
+}
+
+// newResourceSubscriptions creates an empty subscription registry
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{
+		sessions:     make(map[string]map[string]bool),
+		fingerprints: make(map[string]string),
+	}
+}
+
+// hooks returns MCP server hooks that keep the registry up to date as clients subscribe,
+// unsubscribe, and disconnect
+func (r *resourceSubscriptions) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddOnRequestInitialization(func(ctx context.Context, _ any, message any) error {
+		raw, ok := message.(json.RawMessage)
+		session := server.ClientSessionFromContext(ctx)
+		if !ok || session == nil {
+			return nil
+		}
+
+		var request struct {
+			Method string `json:"method"`
+			Params struct {
+				URI string `json:"uri"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(raw, &request) != nil || request.Params.URI == "" {
+			return nil
+		}
+
+		switch request.Method {
+		case methodResourcesSubscribe:
+			r.subscribe(session.SessionID(), request.Params.URI)
+		case methodResourcesUnsubscribe:
+			r.unsubscribe(session.SessionID(), request.Params.URI)
+		}
+		return nil
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		r.removeSession(session.SessionID())
+	})
+	return hooks
+}
+
+// subscribe records a session's subscription to a resource
+func (r *resourceSubscriptions) subscribe(sessionID, uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uris, ok := r.sessions[sessionID]
+	if !ok {
+		uris = make(map[string]bool)
+		r.sessions[sessionID] = uris
+	}
+	uris[uri] = true
+}
+
+// unsubscribe removes a session's subscription to a resource
+func (r *resourceSubscriptions) unsubscribe(sessionID, uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions[sessionID], uri)
+	if len(r.sessions[sessionID]) == 0 {
+		delete(r.sessions, sessionID)
+	}
+}
+
+// removeSession removes every subscription of a session, such as when it disconnects
+func (r *resourceSubscriptions) removeSession(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, sessionID)
+}
+
+// uris returns the subscribed resources in order, forgetting the fingerprints of resources
+// nobody subscribes to any more
+func (r *resourceSubscriptions) uris() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscribed := make(map[string]bool)
+	for _, uris := range r.sessions {
+		for uri := range uris {
+			subscribed[uri] = true
+		}
+	}
+	for uri := range r.fingerprints {
+		if !subscribed[uri] {
+			delete(r.fingerprints, uri)
+		}
+	}
+
+	result := make([]string, 0, len(subscribed))
+	for uri := range subscribed {
+		result = append(result, uri)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// subscribers returns the sessions subscribed to a resource, in order
+func (r *resourceSubscriptions) subscribers(uri string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sessions []string
+	for sessionID, uris := range r.sessions {
+		if uris[uri] {
+			sessions = append(sessions, sessionID)
+		}
+	}
+	slices.Sort(sessions)
+	return sessions
+}
+
+// changed records a resource's latest fingerprint, reporting whether it differs from the one
+// seen at the previous check. The first check of a resource only records it.
+func (r *resourceSubscriptions) changed(uri, fingerprint string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, seen := r.fingerprints[uri]
+	r.fingerprints[uri] = fingerprint
+	return seen && previous != fingerprint
+}
+
+// watchResources checks the resources clients subscribe to for changes every poll interval
+// until ctx is done, notifying the subscribers of each resource that changed. The interval
+// is read before each wait, so reconfiguring the server applies it to the next check.
+func (s *Server) watchResources(ctx context.Context) {
+	for {
+		interval := s.currentConfig().ResourcePollInterval
+		if interval <= 0 {
+			return
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.checkSubscribedResources(ctx)
+	}
+}
+
+// checkSubscribedResources reads each subscribed resource and sends a resource updated
+// notification to its subscribers when it changed since the previous check. Resources that
+// cannot be read are checked again next time.
+func (s *Server) checkSubscribedResources(ctx context.Context) {
+	for _, uri := range s.subscriptions.uris() {
+		fingerprint, err := s.resourceFingerprint(ctx, uri)
+		if err != nil {
+			s.logger.Debug("Failed to check subscribed resource", "uri", uri, "error", err)
+			continue
+		}
+		if !s.subscriptions.changed(uri, fingerprint) {
+			continue
+		}
+
+		s.logger.Info("Subscribed resource changed", "uri", uri)
+		for _, sessionID := range s.subscriptions.subscribers(uri) {
+			err := s.mcpServer.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated,
+				map[string]any{"uri": uri})
+			if errors.Is(err, server.ErrSessionNotFound) {
+				s.subscriptions.removeSession(sessionID)
+			} else if err != nil {
+				s.logger.Warn("Failed to notify subscriber", "uri", uri, "session_id", sessionID, "error", err)
+			}
+		}
+	}
+}
+
+// resourceFingerprint returns the SHA-256 hash of a resource's JSON encoding
+func (s *Server) resourceFingerprint(ctx context.Context, uri string) (string, error) {
+	value, err := s.readEntityResource(ctx, uri)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode resource: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
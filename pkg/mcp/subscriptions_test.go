@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResourceSubscriptions(t *testing.T) {
+	const a, b = "replicated://applications/a", "replicated://applications/b"
+
+	subscriptions := newResourceSubscriptions()
+	subscriptions.subscribe("session-1", a)
+	subscriptions.subscribe("session-2", a)
+	subscriptions.subscribe("session-2", b)
+
+	if got := subscriptions.uris(); !slices.Equal(got, []string{a, b}) {
+		t.Errorf("uris() = %v", got)
+	}
+	if got := subscriptions.subscribers(a); !slices.Equal(got, []string{"session-1", "session-2"}) {
+		t.Errorf("subscribers() = %v", got)
+	}
+
+	// The first check records a resource; later checks report whether it changed
+	if subscriptions.changed(b, "v1") {
+		t.Error("Expected the first check not to report a change")
+	}
+	if subscriptions.changed(b, "v1") {
+		t.Error("Expected an unchanged fingerprint not to report a change")
+	}
+	if !subscriptions.changed(b, "v2") {
+		t.Error("Expected a new fingerprint to report a change")
+	}
+
+	subscriptions.unsubscribe("session-2", b)
+	subscriptions.removeSession("session-1")
+	if got := subscriptions.uris(); !slices.Equal(got, []string{a}) {
+		t.Errorf("uris() after unsubscribing = %v", got)
+	}
+	if got := subscriptions.subscribers(a); !slices.Equal(got, []string{"session-2"}) {
+		t.Errorf("subscribers() after removing a session = %v", got)
+	}
+
+	// A resource subscribed to again starts over rather than comparing with a stale fingerprint
+	subscriptions.subscribe("session-2", b)
+	if subscriptions.changed(b, "v3") {
+		t.Error("Expected a resubscribed resource to be recorded afresh")
+	}
+}
+
+func TestCheckSubscribedResources(t *testing.T) {
+	var channelName atomic.Value
+	channelName.Store("Stable")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"channels": [{"id": "channel-1", "name": %q}]}`, channelName.Load())
+	})
+	vendorAPI := httptest.NewServer(mux)
+	defer vendorAPI.Close()
+
+	server := newTestServer(t, vendorAPI.URL)
+	session := newTestSession()
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	// Subscribe through the MCP protocol, as a client would
+	const uri = "replicated://applications/test-app/channels/channel-1"
+	subscribe, _ := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  methodResourcesSubscribe,
+		"params":  map[string]any{"uri": uri},
+	})
+	server.mcpServer.HandleMessage(server.mcpServer.WithContext(context.Background(), session), subscribe)
+	if got := server.subscriptions.subscribers(uri); !slices.Equal(got, []string{session.SessionID()}) {
+		t.Fatalf("Expected the session to be subscribed, got %v", got)
+	}
+
+	// The first check records the channel, and an unchanged channel sends nothing
+	server.checkSubscribedResources(context.Background())
+	server.checkSubscribedResources(context.Background())
+	if len(session.notifications) != 0 {
+		t.Fatalf("Expected no notifications before the channel changes, got %d", len(session.notifications))
+	}
+
+	channelName.Store("Production")
+	server.checkSubscribedResources(context.Background())
+
+	select {
+	case notification := <-session.notifications:
+		if notification.Method != mcp.MethodNotificationResourceUpdated ||
+			notification.Params.AdditionalFields["uri"] != uri {
+			t.Errorf("Expected a resource updated notification for %s, got %+v", uri, notification)
+		}
+	default:
+		t.Fatal("Expected a notification after the channel changed")
+	}
+
+	// Disconnected sessions are forgotten
+	server.mcpServer.UnregisterSession(context.Background(), session.SessionID())
+	if got := server.subscriptions.uris(); len(got) != 0 {
+		t.Errorf("Expected no subscriptions after the session ended, got %v", got)
+	}
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// timeRangeArgument is the argument reporting tools take their window of time from
+const timeRangeArgument = "time_range"
+
+// withTimeRange adds the time_range argument shared by the reporting tools, described by
+// what the range limits
+func withTimeRange(limits string) mcp.ToolOption {
+	return mcp.WithString(timeRangeArgument,
+		mcp.Description(limits+". "+timerange.Description),
+	)
+}
+
+// requestTimeRange parses a tool call's time_range argument, returning nil when it is absent
+func requestTimeRange(request mcp.CallToolRequest) (*timerange.Range, error) {
+	value := request.GetString(timeRangeArgument, "")
+	if value == "" {
+		return nil, nil
+	}
+	return timerange.Parse(value, time.Now())
+}
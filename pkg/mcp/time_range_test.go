@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequestTimeRange(t *testing.T) {
+	absent, err := requestTimeRange(createMockCallToolRequest("get_channel_adoption", map[string]any{}))
+	if err != nil || absent != nil {
+		t.Errorf("Expected no range without a time_range argument, got %+v, %v", absent, err)
+	}
+
+	before := time.Now()
+	window, err := requestTimeRange(createMockCallToolRequest("get_channel_adoption",
+		map[string]any{"time_range": "last_7d"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if window.End.Before(before) || window.End.Sub(window.Start) != 7*24*time.Hour {
+		t.Errorf("Expected the last 7 days up to now, got %+v", window)
+	}
+
+	if _, err := requestTimeRange(createMockCallToolRequest("get_channel_adoption",
+		map[string]any{"time_range": "last_week"})); err == nil {
+		t.Error("Expected an error for an invalid time range")
+	}
+}
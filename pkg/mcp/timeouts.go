@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolTimeoutKind is the error kind for calls that ran past their time limit
+const toolTimeoutKind = "tool_timeout"
+
+// toolTimeoutHint applies to calls that ran past their time limit
+const toolTimeoutHint = "the call was canceled at its time limit; retry with a narrower request, such as " +
+	"one application or a smaller page, or raise the limit with --tool-timeout or --tool-timeouts"
+
+// toolTimeoutError reports a tool call canceled because it ran past its time limit
+type toolTimeoutError struct {
+	tool    string
+	timeout time.Duration
+}
+
+// Error implements the error interface
+func (e *toolTimeoutError) Error() string {
+	return fmt.Sprintf("tool '%s' did not finish within its %v time limit", e.tool, e.timeout)
+}
+
+// withTimeout wraps a tool handler so that each call's context is canceled once the call's
+// time limit passes, and a call that fails because of it is reported as timed out rather
+// than with whatever error the canceled request produced. Limits are read on each call, so
+// reconfiguring the server applies them to tools that are already registered.
+func (s *Server) withTimeout(tool toolDefinition, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timeout := s.toolTimeout(tool, request)
+		if timeout <= 0 {
+			return next(ctx, request)
+		}
+
+		timeoutErr := &toolTimeoutError{tool: tool.definition.Name, timeout: timeout}
+		ctx, cancel := context.WithTimeoutCause(ctx, timeout, timeoutErr)
+		defer cancel()
+
+		result, err := next(ctx, request)
+		if err != nil && errors.Is(context.Cause(ctx), timeoutErr) {
+			return nil, timeoutErr
+		}
+		return result, err
+	}
+}
+
+// toolTimeout returns the time limit of a call: the limit configured for the tool by name,
+// or else the one the tool declares for the call, or else the default. A tool's own limit
+// only applies when there is a default, so turning limits off turns them all off.
+func (s *Server) toolTimeout(tool toolDefinition, request mcp.CallToolRequest) time.Duration {
+	cfg := s.currentConfig()
+	if _, named := cfg.ToolTimeouts[tool.definition.Name]; named || tool.timeout == nil || cfg.ToolTimeout <= 0 {
+		return cfg.ToolTimeoutFor(tool.definition.Name)
+	}
+	return tool.timeout(request, cfg.ToolTimeout)
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// slowApplications answers ListApplications after a delay, or when the call is canceled
+type slowApplications struct {
+	applicationsService
+	delay time.Duration
+}
+
+func (s *slowApplications) ListApplications(
+	ctx context.Context, _ *api.ListApplicationsOptions,
+) (*api.ApplicationList, error) {
+	select {
+	case <-time.After(s.delay):
+		return &api.ApplicationList{Applications: []models.Application{{ID: "app-fake", Slug: "fake"}}}, nil
+	case <-ctx.Done():
+		return nil, &api.RequestError{Kind: api.RequestDeadlineExceeded, Err: ctx.Err()}
+	}
+}
+
+func TestToolCallTimeouts(t *testing.T) {
+	tests := []struct {
+		name         string
+		delay        time.Duration
+		timeout      time.Duration
+		toolTimeouts map[string]time.Duration
+		wantTimeout  bool
+	}{
+		{
+			name:    "call finishing within its limit",
+			delay:   0,
+			timeout: time.Minute,
+		},
+		{
+			name:        "call running past its limit",
+			delay:       time.Minute,
+			timeout:     50 * time.Millisecond,
+			wantTimeout: true,
+		},
+		{
+			name:         "limit overridden for the tool",
+			delay:        time.Minute,
+			timeout:      time.Minute,
+			toolTimeouts: map[string]time.Duration{"list_applications": 50 * time.Millisecond},
+			wantTimeout:  true,
+		},
+		{
+			name:         "limit lifted for the tool",
+			delay:        100 * time.Millisecond,
+			timeout:      10 * time.Millisecond,
+			toolTimeouts: map[string]time.Duration{"list_applications": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir(),
+				ToolTimeout: tt.timeout, ToolTimeouts: tt.toolTimeouts,
+			}
+			server, err := NewServerWithServices(cfg, logging.NewLogger("info"), &vendorapi.Services{
+				Client: fakeClient{}, Applications: &slowApplications{delay: tt.delay},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			tool, ok := server.registry.Tool("list_applications")
+			if !ok {
+				t.Fatal("Tool 'list_applications' not found")
+			}
+			started := time.Now()
+			result, err := server.serverTool(tool).Handler(context.Background(),
+				createMockCallToolRequest("list_applications", map[string]any{}))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if elapsed := time.Since(started); elapsed > 5*time.Second {
+				t.Errorf("Expected the call to end at its limit, took %v", elapsed)
+			}
+
+			if !tt.wantTimeout {
+				if result.IsError {
+					t.Errorf("Expected the call to succeed, got %s", result.Content[0].(mcp.TextContent).Text)
+				}
+				return
+			}
+
+			payload, ok := result.StructuredContent.(toolError)
+			if !result.IsError || !ok {
+				t.Fatalf("Expected an error result, got %+v", result)
+			}
+			if payload.Kind != toolTimeoutKind || payload.Remediation != toolTimeoutHint {
+				t.Errorf("Expected a tool timeout, got kind '%s' and hint '%s'", payload.Kind, payload.Remediation)
+			}
+			if !strings.Contains(payload.Error, "tool 'list_applications' did not finish within its 50ms") {
+				t.Errorf("Expected the error to name the tool and its limit, got '%s'", payload.Error)
+			}
+		})
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		tool         string
+		args         map[string]any
+		timeout      time.Duration
+		toolTimeouts map[string]time.Duration
+		want         time.Duration
+	}{
+		{name: "tool without a limit of its own", tool: "list_applications", timeout: 2 * time.Minute,
+			want: 2 * time.Minute},
+		{name: "cluster command with its default timeout", tool: runClusterCommandToolName,
+			timeout: 2 * time.Minute, want: 2*time.Minute + clusterCommandTimeoutMargin},
+		{name: "cluster command with its longest timeout", tool: runClusterCommandToolName,
+			args: map[string]any{"timeout_seconds": 600}, timeout: 2 * time.Minute,
+			want: 10*time.Minute + clusterCommandTimeoutMargin},
+		{name: "cluster command shorter than the default", tool: runClusterCommandToolName,
+			args: map[string]any{"timeout_seconds": 10}, timeout: 5 * time.Minute, want: 5 * time.Minute},
+		{name: "cluster command limit configured by name", tool: runClusterCommandToolName,
+			args: map[string]any{"timeout_seconds": 600}, timeout: 2 * time.Minute,
+			toolTimeouts: map[string]time.Duration{runClusterCommandToolName: 3 * time.Minute}, want: 3 * time.Minute},
+		{name: "cluster command with limits turned off", tool: runClusterCommandToolName,
+			args: map[string]any{"timeout_seconds": 600}},
+		{name: "bulk call", tool: bulkToolName, timeout: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, "")
+			server.config.ToolTimeout = tt.timeout
+			server.config.ToolTimeouts = tt.toolTimeouts
+
+			tool, ok := server.registry.Tool(tt.tool)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.tool)
+			}
+			if got := server.toolTimeout(tool, createMockCallToolRequest(tt.tool, tt.args)); got != tt.want {
+				t.Errorf("Expected a limit of %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBulkOperationTimeouts(t *testing.T) {
+	cfg := &config.Config{
+		LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir(), ToolTimeout: 50 * time.Millisecond,
+	}
+	server, err := NewServerWithServices(cfg, logging.NewLogger("info"), &vendorapi.Services{
+		Client: fakeClient{}, Applications: &slowApplications{delay: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tool, ok := server.registry.Tool(bulkToolName)
+	if !ok {
+		t.Fatal("Tool 'bulk' not found")
+	}
+	operations := []any{
+		map[string]any{"tool": "list_applications", "arguments": map[string]any{}},
+		map[string]any{"tool": "list_applications", "arguments": map[string]any{}},
+	}
+	result, err := server.serverTool(tool).Handler(context.Background(),
+		createMockCallToolRequest(bulkToolName, map[string]any{"operations": operations}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Expected the bulk call to outlast its operations, got %s", resultText(t, result))
+	}
+
+	var summary bulkResult
+	if err := json.Unmarshal(resultData(t, result), &summary); err != nil {
+		t.Fatalf("Failed to decode bulk result: %v", err)
+	}
+	for _, item := range summary.Results {
+		if item.Error == nil || item.Error.Kind != toolTimeoutKind {
+			t.Errorf("Expected operation %d to time out, got %+v", item.Index, item)
+		}
+	}
+}
@@ -2,9 +2,17 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/render"
 )
 
 // Constants for pagination and validation limits
@@ -15,59 +23,164 @@ const (
 	minOffset      = 0
 )
 
-// Constants for MCP protocol messages
-const (
-	step7ImplementationMsg = "will be implemented in Step 7 (MCP Handlers)"
-)
-
 // toolDefinition represents a complete tool definition with its handler function.
+// Mutating tools change the vendor account and are withheld in read-only mode.
+// The category groups related tools so they can be enabled or disabled together.
+// A tool whose calls are expected to outlast the default time limit declares its own with
+// timeout, from the call's arguments and the configured default; zero means no limit.
 type toolDefinition struct {
-	definition *mcp.Tool
-	handler    server.ToolHandlerFunc
+	definition   *mcp.Tool
+	handler      server.ToolHandlerFunc
+	mutating     bool
+	runsCommands bool
+	category     string
+	timeout      func(request mcp.CallToolRequest, defaultTimeout time.Duration) time.Duration
 }
 
-// defineTools returns all Phase 1 tools with their schemas and empty handler implementations.
-// The actual business logic will be implemented in Step 7 (MCP Handlers).
+// defineTools returns all tools with their schemas and handlers.
 //
-// Tools are organized into four categories:
-// - Application tools: list, get, search applications
-// - Release tools: list, get, search releases
-// - Channel tools: list, get, search channels
-// - Customer tools: list, get, search customers
+// Tools are organized into fourteen categories, which can be enabled or disabled by name:
+// - Application tools: list, get, search applications, list their activity feeds
+// - Release tools: list, get, search, promote, compare, and download releases, list their images and registry tags
+// - Channel tools: list, get, search channels, report release adoption, funnels, and trends, inspect installer versions
+// - Customer tools: list, get, find, summarize, update, (un)archive customers, look up licenses, report usage
+// - Support bundle tools: list support bundles, get a bundle's analysis
+// - Entitlement tools: list license fields, get, set, and evaluate customer entitlements
+// - Cluster tools: manage Compatibility Matrix clusters and VMs, get kubeconfigs and SSH endpoints, run kubectl
+// - Snapshot tools: export a full account snapshot archive, diff two archives
+// - Job tools: check the status of background jobs
+// - Bulk tools: run many tool calls at once with bounded concurrency
+// - Search tools: search applications, channels, customers, and releases in one call
+// - Tag tools: tag applications and customers locally, list entities by tag
+// - Note tools: leave notes on applications and customers locally, list an entity's notes
+// - Server tools: report the server version, connection, capabilities, metrics, and portal SLOs, guide setup
 //
 // Note: ID parameters in tools accept both IDs and slugs (e.g., app_id accepts both
-// application IDs and application slugs). Handlers determine the parameter type at runtime.
+// application IDs and application slugs). Handlers resolve slugs to IDs at runtime
+// using the server's resolver.
 //
 // Each tool includes:
 // - Proper JSON schema validation for arguments
 // - Comprehensive documentation
-// - Empty handler that returns placeholder responses
+// - A handler that returns its result in the shared {data, pagination, warnings} envelope
 //
 // Returns:
 //
 //	[]toolDefinition: All tool definitions with handlers
 func (s *Server) defineTools() []toolDefinition {
-	return []toolDefinition{
-		// Application Tools
-		s.defineListApplicationsTool(),
-		s.defineGetApplicationTool(),
-		s.defineSearchApplicationsTool(),
-
-		// Release Tools
-		s.defineListReleasesTool(),
-		s.defineGetReleaseTool(),
-		s.defineSearchReleasesTool(),
-
-		// Channel Tools
-		s.defineListChannelsTool(),
-		s.defineGetChannelTool(),
-		s.defineSearchChannelsTool(),
-
-		// Customer Tools
-		s.defineListCustomersTool(),
-		s.defineGetCustomerTool(),
-		s.defineSearchCustomersTool(),
+	return slices.Concat(
+		inToolCategory(categoryApplications,
+			s.defineListApplicationsTool(),
+			s.defineGetApplicationTool(),
+			s.defineSearchApplicationsTool(),
+			s.defineCreateApplicationTool(),
+			s.defineArchiveApplicationTool(),
+			s.defineListActivityTool(),
+		),
+		inToolCategory(categoryReleases,
+			s.defineListReleasesTool(),
+			s.defineGetReleaseTool(),
+			s.defineSearchReleasesTool(),
+			s.definePromoteReleaseTool(),
+			s.defineCompareReleasesTool(),
+			s.defineGetReleaseChangelogTool(),
+			s.defineDownloadReleaseArchiveTool(),
+			s.defineListReleaseImagesTool(),
+			s.defineGetImageUsageTool(),
+			s.defineListReleasesAllAppsTool(),
+		),
+		inToolCategory(categoryChannels,
+			s.defineListChannelsTool(),
+			s.defineGetChannelTool(),
+			s.defineSearchChannelsTool(),
+			s.defineGetChannelAdoptionTool(),
+			s.defineReportReleaseAdoptionTool(),
+			s.defineGetAdoptionTimeseriesTool(),
+			s.defineReportVersionDriftTool(),
+			s.defineListKurlInstallersTool(),
+			s.defineGetEmbeddedClusterConfigTool(),
+			s.defineCompareChannelsTool(),
+			s.defineGetChannelVersioningTool(),
+			s.defineSetChannelVersioningTool(),
+			s.defineGetChannelSettingsTool(),
+			s.defineUpdateChannelSettingsTool(),
+			s.defineListChannelsAllAppsTool(),
+		),
+		inToolCategory(categoryCustomers,
+			s.defineListCustomersTool(),
+			s.defineGetCustomerTool(),
+			s.defineSearchCustomersTool(),
+			s.defineSummarizeCustomerTool(),
+			s.defineGetCustomerVersionBreakdownTool(),
+			s.defineReportK8sDistributionsTool(),
+			s.defineListCustomerDownloadsTool(),
+			s.defineListInstanceEventsTool(),
+			s.defineArchiveCustomerTool(),
+			s.defineUnarchiveCustomerTool(),
+			s.defineUpdateCustomerTool(),
+			s.defineTagCustomerTool(),
+			s.defineAnnotateCustomerTool(),
+			s.defineListCustomersAllAppsTool(),
+			s.defineFindCustomerTool(),
+			s.defineLookupLicenseTool(),
+		),
+		inToolCategory(categorySupportBundles,
+			s.defineListSupportBundlesTool(),
+			s.defineGetSupportBundleTool(),
+		),
+		inToolCategory(categoryEntitlements,
+			s.defineListLicenseFieldsTool(),
+			s.defineGetCustomerEntitlementsTool(),
+			s.defineSetCustomerEntitlementTool(),
+			s.defineEvaluateEntitlementTool(),
+		),
+		inToolCategory(categoryClusters,
+			s.defineListClustersTool(),
+			s.defineCreateClusterTool(),
+			s.defineDeleteClusterTool(),
+			s.defineGetClusterKubeconfigTool(),
+			s.defineListVMsTool(),
+			s.defineCreateVMTool(),
+			s.defineDeleteVMTool(),
+			s.defineGetVMSSHEndpointTool(),
+			s.defineRunClusterCommandTool(),
+		),
+		inToolCategory(categorySnapshots,
+			s.defineExportAccountSnapshotTool(),
+			s.defineDiffAccountSnapshotsTool(),
+		),
+		inToolCategory(categoryJobs,
+			s.defineGetJobTool(),
+		),
+		inToolCategory(categoryBulk,
+			s.defineBulkTool(),
+		),
+		inToolCategory(categorySearch,
+			s.defineSearchAllTool(),
+		),
+		inToolCategory(categoryTags,
+			s.defineTagEntityTool(),
+			s.defineListByTagTool(),
+		),
+		inToolCategory(categoryNotes,
+			s.defineAddNoteTool(),
+			s.defineListNotesTool(),
+		),
+		inToolCategory(categoryServer,
+			s.defineGetServerInfoTool(),
+			s.defineGetServerMetricsTool(),
+			s.defineGetDependencySLOTool(),
+			s.defineSetupAssistantTool(),
+		),
+	)
+}
+
+// inToolCategory assigns a category to a group of tool definitions
+func inToolCategory(category string, tools ...toolDefinition) []toolDefinition {
+	for i := range tools {
+		tools[i].category = category
 	}
+	return tools
 }
 
 // Application Tools
@@ -83,21 +196,24 @@ func (s *Server) defineListApplicationsTool() toolDefinition {
 			mcp.Min(minLimit),
 			mcp.Max(maxListLimit),
 		),
-		mcp.WithNumber("offset",
-			mcp.Description("Number of applications to skip for pagination"),
-			mcp.Min(minOffset),
-		),
+		cursorParameter("applications"),
+		outputSchema[[]models.Application](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("list_applications tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_applications tool called", "arguments", request.GetArguments())
+
+		list, err := s.applications.ListApplications(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+
+		page, err := pageOf(request, list.Applications)
+		if err != nil {
+			return nil, err
+		}
 
-		// TODO: Implement actual application listing in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Applications listing " + step7ImplementationMsg),
-			},
-		}, nil
+		return envelopeResult(ctx, page)
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
@@ -111,30 +227,36 @@ func (s *Server) defineGetApplicationTool() toolDefinition {
 			"Returns comprehensive application data including configuration and metadata."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
 		),
+		outputSchema[models.Application](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("get_application tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_application tool called", "arguments", request.GetArguments())
 
-		// TODO: Implement actual application retrieval in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Application details " + step7ImplementationMsg),
-			},
-		}, nil
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		app, err := s.applications.GetApplication(ctx, appID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get application: %w", err)
+		}
+
+		return jsonResult(ctx, app)
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
 }
 
 // defineSearchApplicationsTool creates the search_applications tool definition.
-// Searches applications based on name or other criteria.
+// Searches applications by name, slug, description, or ID.
 func (s *Server) defineSearchApplicationsTool() toolDefinition {
 	tool := mcp.NewTool("search_applications",
-		mcp.WithDescription("Search applications by name or other criteria. "+
-			"Returns matching applications with relevance scoring."),
+		mcp.WithDescription("Search applications by name, slug, description, or ID, ignoring case. "+
+			searchRankingDescription),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("Search query string to match against application names and descriptions"),
@@ -144,17 +266,29 @@ func (s *Server) defineSearchApplicationsTool() toolDefinition {
 			mcp.Min(minLimit),
 			mcp.Max(maxSearchLimit),
 		),
+		withSearchIndexOptions(),
+		outputSchema[[]models.Application](),
+		alternateOutputSchema[rankedResult](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("search_applications tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("search_applications tool called", "arguments", request.GetArguments())
+
+		query, err := request.RequireString("query")
+		if err != nil {
+			return nil, err
+		}
 
-		// TODO: Implement actual application search in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Application search " + step7ImplementationMsg),
-			},
-		}, nil
+		if s.currentConfig().SearchIndex {
+			return s.rankedSearch(ctx, request, searchKindApplications, "", query)
+		}
+
+		list, err := s.applications.SearchApplications(ctx, query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search applications: %w", err)
+		}
+
+		return envelopeResult(ctx, firstPage(list.Applications, request.GetInt("limit", maxSearchLimit)))
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
@@ -163,79 +297,147 @@ func (s *Server) defineSearchApplicationsTool() toolDefinition {
 // Release Tools
 
 // defineListReleasesTool creates the list_releases tool definition.
-// Lists releases for a specific application.
+// Lists releases for a specific application, optionally only those promoted to a channel.
 func (s *Server) defineListReleasesTool() toolDefinition {
 	tool := mcp.NewTool("list_releases",
 		mcp.WithDescription("List releases for a specific application. "+
-			"Returns release information including version, status, and deployment details."),
+			"Returns release information including version, status, and deployment details. "+
+			"With channel_id, only the releases ever promoted to that channel are listed."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the list to"),
 		),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of releases to return (1-100)"),
 			mcp.Min(minLimit),
 			mcp.Max(maxListLimit),
 		),
-		mcp.WithNumber("offset",
-			mcp.Description("Number of releases to skip for pagination"),
-			mcp.Min(minOffset),
-		),
+		cursorParameter("releases"),
+		outputSchema[[]models.Release](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("list_releases tool called", "arguments", request.GetArguments())
-
-		// TODO: Implement actual release listing in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Release listing " + step7ImplementationMsg),
-			},
-		}, nil
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_releases tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		var list *api.ReleaseList
+		if channelID := request.GetString("channel_id", ""); channelID != "" {
+			channel, err := s.findChannel(ctx, appID, channelID)
+			if err != nil {
+				return nil, err
+			}
+			list, err = s.releases.ListByChannel(ctx, appID, channel.ID)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			list, err = s.releases.ListReleases(ctx, appID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list releases: %w", err)
+			}
+		}
+
+		page, err := pageOf(request, list.Releases)
+		if err != nil {
+			return nil, err
+		}
+
+		return envelopeResult(ctx, page)
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
 }
 
 // defineGetReleaseTool creates the get_release tool definition.
-// Retrieves detailed information about a specific release.
+// Retrieves a release by sequence, version label, or ID, including its manifests.
 func (s *Server) defineGetReleaseTool() toolDefinition {
 	tool := mcp.NewTool("get_release",
-		mcp.WithDescription("Get detailed information about a specific release by ID. "+
-			"Returns comprehensive release data including manifests and deployment configuration."),
+		mcp.WithDescription("Get detailed information about a specific release by sequence, version label, "+
+			"or ID; give exactly one. Returns comprehensive release data including manifests and deployment "+
+			"configuration. When several releases share a version label, the latest is returned."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithNumber("sequence",
+			mcp.Description("The sequence number of the release"),
+			mcp.Min(1),
+		),
+		mcp.WithString("version",
+			mcp.Description("The version label of the release, such as 1.2.0"),
 		),
 		mcp.WithString("release_id",
-			mcp.Required(),
 			mcp.Description("The unique identifier of the release"),
 		),
+		outputSchema[models.Release](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("get_release tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_release tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		release, err := s.getRelease(ctx, appID, request)
+		if err != nil {
+			return nil, err
+		}
 
-		// TODO: Implement actual release retrieval in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Release details " + step7ImplementationMsg),
-			},
-		}, nil
+		return jsonResult(ctx, release)
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
 }
 
+// getRelease retrieves the release a tool call names by sequence, version label, or ID
+func (s *Server) getRelease(ctx context.Context, appID string, request mcp.CallToolRequest) (*models.Release, error) {
+	arguments := request.GetArguments()
+	given := 0
+	for _, name := range []string{"sequence", "version", "release_id"} {
+		if _, ok := arguments[name]; ok {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, fmt.Errorf("exactly one of sequence, version, or release_id is required")
+	}
+
+	switch {
+	case request.GetString("version", "") != "":
+		return s.releases.GetByVersionLabel(ctx, appID, request.GetString("version", ""))
+	case request.GetString("release_id", "") != "":
+		sequence, err := s.resolveReleaseSequence(ctx, appID, request.GetString("release_id", ""))
+		if err != nil {
+			return nil, err
+		}
+		return s.releases.GetRelease(ctx, appID, sequence)
+	}
+
+	sequence, err := request.RequireInt("sequence")
+	if err != nil {
+		return nil, err
+	}
+	return s.releases.GetRelease(ctx, appID, int64(sequence))
+}
+
 // defineSearchReleasesTool creates the search_releases tool definition.
-// Searches releases based on version or other criteria.
+// Searches releases by version, notes, sequence, or ID.
 func (s *Server) defineSearchReleasesTool() toolDefinition {
 	tool := mcp.NewTool("search_releases",
-		mcp.WithDescription("Search releases by version or other criteria within a specific application. "+
-			"Returns matching releases with relevance scoring."),
+		mcp.WithDescription("Search the releases of a specific application by version, release notes, sequence, "+
+			"or ID, ignoring case. "+searchRankingDescription),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
 		),
 		mcp.WithString("query",
 			mcp.Required(),
@@ -246,17 +448,34 @@ func (s *Server) defineSearchReleasesTool() toolDefinition {
 			mcp.Min(minLimit),
 			mcp.Max(maxSearchLimit),
 		),
+		withSearchIndexOptions(),
+		outputSchema[[]models.Release](),
+		alternateOutputSchema[rankedResult](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("search_releases tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("search_releases tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		query, err := request.RequireString("query")
+		if err != nil {
+			return nil, err
+		}
 
-		// TODO: Implement actual release search in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Release search " + step7ImplementationMsg),
-			},
-		}, nil
+		if s.currentConfig().SearchIndex {
+			return s.rankedSearch(ctx, request, searchKindReleases, appID, query)
+		}
+
+		list, err := s.releases.SearchReleases(ctx, appID, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search releases: %w", err)
+		}
+
+		return envelopeResult(ctx, firstPage(list.Releases, request.GetInt("limit", maxSearchLimit)))
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
@@ -272,28 +491,36 @@ func (s *Server) defineListChannelsTool() toolDefinition {
 			"Returns channel information including name, release assignments, and customer adoption."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
 		),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of channels to return (1-100)"),
 			mcp.Min(minLimit),
 			mcp.Max(maxListLimit),
 		),
-		mcp.WithNumber("offset",
-			mcp.Description("Number of channels to skip for pagination"),
-			mcp.Min(minOffset),
-		),
+		cursorParameter("channels"),
+		outputSchema[[]models.Channel](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("list_channels tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_channels tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := s.channels.ListChannels(ctx, appID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list channels: %w", err)
+		}
+
+		page, err := pageOf(request, list.Channels)
+		if err != nil {
+			return nil, err
+		}
 
-		// TODO: Implement actual channel listing in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Channel listing " + step7ImplementationMsg),
-			},
-		}, nil
+		return envelopeResult(ctx, page)
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
@@ -303,41 +530,52 @@ func (s *Server) defineListChannelsTool() toolDefinition {
 // Retrieves detailed information about a specific channel.
 func (s *Server) defineGetChannelTool() toolDefinition {
 	tool := mcp.NewTool("get_channel",
-		mcp.WithDescription("Get detailed information about a specific channel by ID. "+
-			"Returns comprehensive channel data including release history and customer assignments."),
+		mcp.WithDescription("Get detailed information about a specific channel by ID, slug, or name. "+
+			"Returns the channel's settings and the release it currently ships."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
 		),
 		mcp.WithString("channel_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the channel"),
+			mcp.Description("The unique identifier, slug, or name of the channel"),
 		),
+		outputSchema[models.Channel](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("get_channel tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_channel tool called", "arguments", request.GetArguments())
 
-		// TODO: Implement actual channel retrieval in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Channel details " + step7ImplementationMsg),
-			},
-		}, nil
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		channelID, err := request.RequireString("channel_id")
+		if err != nil {
+			return nil, err
+		}
+
+		channel, err := s.findChannel(ctx, appID, channelID)
+		if err != nil {
+			return nil, err
+		}
+
+		return envelopeResult(ctx, envelope{Data: channel})
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
 }
 
 // defineSearchChannelsTool creates the search_channels tool definition.
-// Searches channels based on name or other criteria.
+// Searches channels by name, slug, description, or ID.
 func (s *Server) defineSearchChannelsTool() toolDefinition {
 	tool := mcp.NewTool("search_channels",
-		mcp.WithDescription("Search channels by name or other criteria within a specific application. "+
-			"Returns matching channels with relevance scoring."),
+		mcp.WithDescription("Search the channels of a specific application by name, slug, description, or ID, "+
+			"ignoring case. "+searchRankingDescription),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
 		),
 		mcp.WithString("query",
 			mcp.Required(),
@@ -348,17 +586,34 @@ func (s *Server) defineSearchChannelsTool() toolDefinition {
 			mcp.Min(minLimit),
 			mcp.Max(maxSearchLimit),
 		),
+		withSearchIndexOptions(),
+		outputSchema[[]models.Channel](),
+		alternateOutputSchema[rankedResult](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("search_channels tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("search_channels tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		query, err := request.RequireString("query")
+		if err != nil {
+			return nil, err
+		}
+
+		if s.currentConfig().SearchIndex {
+			return s.rankedSearch(ctx, request, searchKindChannels, appID, query)
+		}
+
+		list, err := s.channels.SearchChannels(ctx, appID, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search channels: %w", err)
+		}
 
-		// TODO: Implement actual channel search in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Channel search " + step7ImplementationMsg),
-			},
-		}, nil
+		return envelopeResult(ctx, firstPage(list.Channels, request.GetInt("limit", maxSearchLimit)))
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
@@ -367,40 +622,102 @@ func (s *Server) defineSearchChannelsTool() toolDefinition {
 // Customer Tools
 
 // defineListCustomersTool creates the list_customers tool definition.
-// Lists all customers for a specific application.
+// Lists the customers of a specific application, optionally filtered by their settings.
 func (s *Server) defineListCustomersTool() toolDefinition {
 	tool := mcp.NewTool("list_customers",
 		mcp.WithDescription("List customers for a specific application. "+
-			"Returns customer information including name, status, and channel assignments."),
+			"Returns customer information including name, status, and channel assignments. "+
+			"The optional filters narrow the list on the server; a customer must match every filter given. "+
+			"Applications with more customers than the server's search scan limit are only partly listed; "+
+			"the result's pagination then includes truncated: true, with a warning."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only list customers of this type"),
+			mcp.Enum(models.CustomerTypeTrial, models.CustomerTypePaid, models.CustomerTypeCommunity,
+				models.CustomerTypeDevelopment),
+		),
+		mcp.WithString("license_type",
+			mcp.Description("Only list customers with this license type"),
+			mcp.Enum(models.ValidLicenseTypes...),
+		),
+		mcp.WithBoolean("is_archived",
+			mcp.Description("Only list archived customers (true) or customers that are not archived (false)"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("Only list customers assigned to the channel with this ID"),
+		),
+		mcp.WithString("expiring_before",
+			mcp.Description("Only list customers whose license expires before this date (2025-12-31, meaning "+
+				"midnight UTC) or RFC 3339 timestamp. Customers without an expiration are excluded."),
 		),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of customers to return (1-100)"),
 			mcp.Min(minLimit),
 			mcp.Max(maxListLimit),
 		),
-		mcp.WithNumber("offset",
-			mcp.Description("Number of customers to skip for pagination"),
-			mcp.Min(minOffset),
-		),
+		cursorParameter("customers"),
+		outputSchema[[]models.Customer](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("list_customers tool called", "arguments", request.GetArguments())
-
-		// TODO: Implement actual customer listing in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Customer listing " + step7ImplementationMsg),
-			},
-		}, nil
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_customers tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		filter, err := customerFilter(request)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := s.customers.FilterCustomers(ctx, appID, filter, s.currentConfig().SearchScanLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list customers: %w", err)
+		}
+
+		page, err := pageOf(request, list.Customers)
+		if err != nil {
+			return nil, err
+		}
+		if list.Truncated {
+			page.truncate("customers")
+		}
+		return envelopeResult(ctx, page)
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
 }
 
+// customerFilter reads list_customers' filter arguments
+func customerFilter(request mcp.CallToolRequest) (api.CustomerFilter, error) {
+	filter := api.CustomerFilter{
+		Type:        request.GetString("type", ""),
+		LicenseType: request.GetString("license_type", ""),
+		ChannelID:   request.GetString("channel_id", ""),
+	}
+	if _, ok := request.GetArguments()["is_archived"]; ok {
+		archived := request.GetBool("is_archived", false)
+		filter.IsArchived = &archived
+	}
+
+	if value := request.GetString("expiring_before", ""); value != "" {
+		before, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			if before, err = time.Parse(time.RFC3339, value); err != nil {
+				return api.CustomerFilter{}, fmt.Errorf(
+					"expiring_before must be a date (YYYY-MM-DD) or RFC 3339 timestamp, got '%s'", value)
+			}
+		}
+		filter.ExpiringBefore = &before
+	}
+	return filter, nil
+}
+
 // defineGetCustomerTool creates the get_customer tool definition.
 // Retrieves detailed information about a specific customer.
 func (s *Server) defineGetCustomerTool() toolDefinition {
@@ -409,37 +726,50 @@ func (s *Server) defineGetCustomerTool() toolDefinition {
 			"Returns comprehensive customer data including license details and deployment status."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
 		),
 		mcp.WithString("customer_id",
 			mcp.Required(),
 			mcp.Description("The unique identifier of the customer"),
 		),
+		outputSchema[models.Customer](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("get_customer tool called", "arguments", request.GetArguments())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_customer tool called", "arguments", request.GetArguments())
 
-		// TODO: Implement actual customer retrieval in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Customer details " + step7ImplementationMsg),
-			},
-		}, nil
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		customer, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		return envelopeResult(ctx, envelope{Data: customer})
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
 }
 
 // defineSearchCustomersTool creates the search_customers tool definition.
-// Searches customers based on name or other criteria.
+// Searches customers by name, email, or ID, reading at most the configured scan limit.
 func (s *Server) defineSearchCustomersTool() toolDefinition {
 	tool := mcp.NewTool("search_customers",
-		mcp.WithDescription("Search customers by name or other criteria within a specific application. "+
-			"Returns matching customers with relevance scoring."),
+		mcp.WithDescription("Search the customers of a specific application by name, email, or ID, ignoring "+
+			"case. "+searchRankingDescription+" Applications with more customers than the server's search scan "+
+			"limit are only partly searched; the result's pagination then includes truncated: true and customers "+
+			"beyond the limit may be missing."),
 		mcp.WithString("app_id",
 			mcp.Required(),
-			mcp.Description("The unique identifier of the application"),
+			mcp.Description("The unique identifier or slug of the application"),
 		),
 		mcp.WithString("query",
 			mcp.Required(),
@@ -450,18 +780,123 @@ func (s *Server) defineSearchCustomersTool() toolDefinition {
 			mcp.Min(minLimit),
 			mcp.Max(maxSearchLimit),
 		),
+		withSearchIndexOptions(),
+		outputSchema[[]models.Customer](),
+		alternateOutputSchema[rankedResult](),
 	)
 
-	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		s.logger.Info("search_customers tool called", "arguments", request.GetArguments())
-
-		// TODO: Implement actual customer search in Step 7
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.NewTextContent("Customer search " + step7ImplementationMsg),
-			},
-		}, nil
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("search_customers tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		query, err := request.RequireString("query")
+		if err != nil {
+			return nil, err
+		}
+
+		cfg := s.currentConfig()
+		if cfg.SearchIndex {
+			return s.rankedSearch(ctx, request, searchKindCustomers, appID, query)
+		}
+
+		list, err := s.customers.SearchCustomers(ctx, appID, query, cfg.SearchScanLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search customers: %w", err)
+		}
+
+		response := firstPage(list.Customers, request.GetInt("limit", maxSearchLimit))
+		if list.Truncated {
+			response.truncate("customers")
+		}
+		return envelopeResult(ctx, response)
 	}
 
 	return toolDefinition{definition: &tool, handler: handler}
 }
+
+// resolveAppID reads the app_id argument, or the configured default application when the
+// call omits it, and resolves it to an application ID, accepting either an ID or a slug.
+func (s *Server) resolveAppID(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	idOrSlug := request.GetString("app_id", "")
+	if idOrSlug == "" {
+		idOrSlug = s.currentConfig().App
+	}
+	if idOrSlug == "" {
+		return "", fmt.Errorf("app_id is required: no default application is configured")
+	}
+
+	return s.resolver.ResolveApplicationID(ctx, idOrSlug)
+}
+
+// withDefaultApp returns a copy of a tool with its app_id argument made optional, for servers
+// configured with a default application. Tools that don't require app_id are returned unchanged.
+func withDefaultApp(tool mcp.Tool, app string) mcp.Tool {
+	property, ok := tool.InputSchema.Properties["app_id"].(map[string]any)
+	if app == "" || !ok || !slices.Contains(tool.InputSchema.Required, "app_id") {
+		return tool
+	}
+
+	optional := maps.Clone(property)
+	optional["description"] = fmt.Sprintf("%s (defaults to %s)", property["description"], app)
+	tool.InputSchema.Properties = maps.Clone(tool.InputSchema.Properties)
+	tool.InputSchema.Properties["app_id"] = optional
+	tool.InputSchema.Required = slices.DeleteFunc(slices.Clone(tool.InputSchema.Required), func(name string) bool {
+		return name == "app_id"
+	})
+	return tool
+}
+
+// paginate returns the window of items selected by offset and limit.
+// Out-of-range offsets yield an empty slice and non-positive limits return all remaining items.
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// localeParameter is the optional per-call locale argument for tools that render reports
+func localeParameter() mcp.ToolOption {
+	return mcp.WithString("locale",
+		mcp.Description("Locale for numbers in the rendered report (e.g. en-US, de-DE); "+
+			"defaults to the server's configured locale"),
+	)
+}
+
+// responseLanguageParameter is the optional per-call language argument for the support bundle
+// summary, the only text the language catalog translates
+func responseLanguageParameter() mcp.ToolOption {
+	return mcp.WithString("response_language",
+		mcp.Description("Language for the support bundle's summary text (e.g. de, ja, pt-BR); analyzer titles "+
+			"and messages, field names, and data values are not translated. Defaults to English"),
+	)
+}
+
+// formatter returns a report formatter for the locale requested by the call, falling back
+// to the configured locale, that composes text in the requested response language
+func (s *Server) formatter(request mcp.CallToolRequest) (*render.Formatter, error) {
+	locale, err := render.ResolveLocale(request.GetString("locale", ""), s.currentConfig().Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	language, err := render.ParseLanguage(request.GetString("response_language", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return render.NewFormatter(locale).WithLanguage(language), nil
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Activity Tools
+
+// defineListActivityTool creates the list_activity tool definition.
+// Lists an application's Vendor Portal activity feed, such as to reconstruct what led up to an incident.
+func (s *Server) defineListActivityTool() toolDefinition {
+	tool := mcp.NewTool("list_activity",
+		mcp.WithDescription("List an application's Vendor Portal activity feed, newest first: who made each "+
+			"change, such as promoting a release, changing a channel's settings, or updating a customer, what "+
+			"they changed, and when. Filter by time range and actor to reconstruct what happened before an "+
+			"incident."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("actor",
+			mcp.Description("Only list changes made by actors whose email address or API token name contains "+
+				"this, ignoring case"),
+		),
+		withTimeRange("Only list changes made within this range"),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of changes to return, most recent first (1-100)"),
+			mcp.Min(minLimit),
+			mcp.Max(maxListLimit),
+		),
+		outputSchema[[]models.Activity](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_activity tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		made, err := requestTimeRange(request)
+		if err != nil {
+			return nil, err
+		}
+
+		filter := api.ActivityFilter{Actor: request.GetString("actor", "")}
+		if made != nil {
+			filter.Since, filter.Until = made.Start, made.End
+		}
+
+		list, err := s.applications.ListActivity(ctx, appID, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		slices.SortStableFunc(list.Activity, func(a, b models.Activity) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+		return envelopeResult(ctx, firstPage(list.Activity, request.GetInt("limit", maxListLimit)))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// fakeActivity serves testApp's activity feed, out of order, filtered as the Vendor Portal
+// API filters it, and records the filter it was asked with
+type fakeActivity struct {
+	*fakeApplications
+	filters chan api.ActivityFilter
+}
+
+func (f *fakeActivity) ListActivity(_ context.Context, _ string, filter api.ActivityFilter) (*api.ActivityList, error) {
+	f.filters <- filter
+	feed := []models.Activity{
+		{ID: "activity-1", Actor: "ana@example.com", Action: "release.promoted",
+			CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)},
+		{ID: "activity-3", Actor: "ci-token", Action: "release.created",
+			CreatedAt: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)},
+		{ID: "activity-2", Actor: "bo@example.com", Action: "customer.updated",
+			CreatedAt: time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)},
+	}
+
+	list := &api.ActivityList{Activity: []models.Activity{}}
+	for i := range feed {
+		if filter.Matches(&feed[i]) {
+			list.Activity = append(list.Activity, feed[i])
+		}
+	}
+	return list, nil
+}
+
+func TestListActivityTool(t *testing.T) {
+	applications := &fakeActivity{
+		fakeApplications: &fakeApplications{apps: []models.Application{testApp}},
+		filters:          make(chan api.ActivityFilter, 1),
+	}
+	server := newTestServerWithServices(t, vendorapi.Services{Applications: applications})
+
+	tool, ok := server.registry.Tool("list_activity")
+	if !ok {
+		t.Fatal("Tool 'list_activity' not found")
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		expectError bool
+		wantIDs     []string
+		wantTotal   int
+		wantFilter  api.ActivityFilter
+	}{
+		{
+			name:      "whole feed, newest first",
+			args:      map[string]any{"app_id": testAppSlug},
+			wantIDs:   []string{"activity-3", "activity-2", "activity-1"},
+			wantTotal: 3,
+		},
+		{
+			name:       "changes by an actor",
+			args:       map[string]any{"app_id": testAppID, "actor": "Example.com"},
+			wantIDs:    []string{"activity-2", "activity-1"},
+			wantTotal:  2,
+			wantFilter: api.ActivityFilter{Actor: "Example.com"},
+		},
+		{
+			name:      "changes in a time range",
+			args:      map[string]any{"app_id": testAppID, "time_range": "2025-01-15/2025-01-31"},
+			wantIDs:   []string{"activity-2"},
+			wantTotal: 1,
+			wantFilter: api.ActivityFilter{
+				Since: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:      "most recent changes",
+			args:      map[string]any{"app_id": testAppID, "limit": 1},
+			wantIDs:   []string{"activity-3"},
+			wantTotal: 3,
+		},
+		{
+			name:        "invalid time range",
+			args:        map[string]any{"app_id": testAppID, "time_range": "recently"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_activity", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if filter := <-applications.filters; filter != tt.wantFilter {
+				t.Errorf("Expected filter %+v, got %+v", tt.wantFilter, filter)
+			}
+
+			response := decodeEnvelope[[]models.Activity](t, result)
+			ids := []string{}
+			for _, activity := range response.Data {
+				ids = append(ids, activity.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected activity %v, got %v", tt.wantIDs, ids)
+			}
+			if response.Pagination == nil || response.Pagination.Total != tt.wantTotal {
+				t.Errorf("Expected a total of %d, got %+v", tt.wantTotal, response.Pagination)
+			}
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crdant/replicated-mcp-server/pkg/adoption"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/render"
+	"github.com/crdant/replicated-mcp-server/pkg/timerange"
+)
+
+// channelHistoryConcurrency bounds how many channel release histories are fetched at once
+const channelHistoryConcurrency = 4
+
+// defaultTimeseriesWindow is how far back the adoption time series goes without a time range
+const defaultTimeseriesWindow = 30 * timerange.Day
+
+// Upgrade time bucket widths of the release adoption funnel
+const (
+	funnelBucketDay  = "day"
+	funnelBucketWeek = "week"
+)
+
+var funnelBucketWidths = map[string]time.Duration{
+	funnelBucketDay:  timerange.Day,
+	funnelBucketWeek: timerange.Week,
+}
+
+// Adoption Tools
+
+// releaseAdoption is a release's adoption funnel and when the data it was computed from was
+// fetched, since the data may be cached
+type releaseAdoption struct {
+	adoption.ReleaseFunnel
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// channelAdoptionTimeseries is a channel's daily release adoption, with a summary of its last
+// day rendered for the caller's locale, and when the data it was computed from was fetched,
+// since the data may be cached
+type channelAdoptionTimeseries struct {
+	adoption.AdoptionTimeseries
+	Report    string    `json:"report"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// versionDriftReport is the version drift report with a summary rendered for the caller's locale
+type versionDriftReport struct {
+	adoption.VersionDrift
+	Report string `json:"report"`
+}
+
+// defineGetChannelAdoptionTool creates the get_channel_adoption tool definition.
+// Reports how many customers and instances on each channel run the channel's latest release.
+func (s *Server) defineGetChannelAdoptionTool() toolDefinition {
+	tool := mcp.NewTool("get_channel_adoption",
+		mcp.WithDescription("Report release adoption for each channel of an application: how many "+
+			"customers and active instances run the channel's latest release, and how instances are "+
+			"spread across releases. Combines channels, releases, and customers in one call."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the report to"),
+		),
+		withTimeRange("Only count instances that last checked in within this range"),
+		outputSchema[[]adoption.ChannelAdoption](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_channel_adoption tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		account, channelID, err := s.adoptionAccount(ctx, appID, request)
+		if err != nil {
+			return nil, err
+		}
+
+		adoptions := account.ChannelAdoptions()
+		if channelID != "" {
+			adoptions = slices.DeleteFunc(adoptions, func(a adoption.ChannelAdoption) bool {
+				return a.ChannelID != channelID
+			})
+		}
+
+		return envelopeResult(ctx, listOf(adoptions))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineGetCustomerVersionBreakdownTool creates the get_customer_version_breakdown tool definition.
+// Groups customers by the releases their active instances run.
+func (s *Server) defineGetCustomerVersionBreakdownTool() toolDefinition {
+	tool := mcp.NewTool("get_customer_version_breakdown",
+		mcp.WithDescription("Group an application's customers by the releases their active instances run, "+
+			"newest release first, and list customers with no active instances. "+
+			"Useful for finding who is behind before deprecating a release."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the breakdown to"),
+		),
+		withTimeRange("Only count instances that last checked in within this range; customers with none "+
+			"are listed as not installed"),
+		outputSchema[adoption.VersionBreakdown](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_customer_version_breakdown tool called",
+			"arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		account, channelID, err := s.adoptionAccount(ctx, appID, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, account.VersionBreakdown(channelID))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineReportK8sDistributionsTool creates the report_k8s_distributions tool definition.
+// Summarizes the Kubernetes distributions and versions customers' active instances run.
+func (s *Server) defineReportK8sDistributionsTool() toolDefinition {
+	tool := mcp.NewTool("report_k8s_distributions",
+		mcp.WithDescription("Summarize the Kubernetes distributions (such as EKS, GKE, or k3s) and minor "+
+			"versions that an application's active instances run, with instance and customer counts and "+
+			"the share of instances for each, to inform which Kubernetes versions to keep supporting. "+
+			"The report holds only counts, never customer names or IDs. Instances that did not report "+
+			"their platform are counted as unknown."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the report to"),
+		),
+		withTimeRange("Only count instances that last checked in within this range"),
+		outputSchema[adoption.DistributionReport](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("report_k8s_distributions tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		account, channelID, err := s.adoptionAccount(ctx, appID, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, account.Distributions(channelID))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineReportVersionDriftTool creates the report_version_drift tool definition.
+// Lists customers whose active instances lag their channel's latest release.
+func (s *Server) defineReportVersionDriftTool() toolDefinition {
+	tool := mcp.NewTool("report_version_drift",
+		mcp.WithDescription("List the customers whose active instances run a release more than max_lag "+
+			"sequences behind their channel's latest release, furthest behind first, with each lagging "+
+			"instance's release and last check-in. Useful for finding who to contact before ending support "+
+			"for old releases."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the report to"),
+		),
+		mcp.WithNumber("max_lag",
+			mcp.Description("How many sequences behind the channel's latest release an instance may be "+
+				"without being reported (default 0, reporting any instance behind)"),
+			mcp.Min(0),
+		),
+		withTimeRange("Only count instances that last checked in within this range"),
+		localeParameter(),
+		outputSchema[versionDriftReport](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("report_version_drift tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		maxLag := request.GetInt("max_lag", 0)
+		if maxLag < 0 {
+			return nil, fmt.Errorf("max_lag must not be negative, got %d", maxLag)
+		}
+
+		formatter, err := s.formatter(request)
+		if err != nil {
+			return nil, err
+		}
+
+		account, channelID, err := s.adoptionAccount(ctx, appID, request)
+		if err != nil {
+			return nil, err
+		}
+
+		drift := account.VersionDrift(channelID, int64(maxLag))
+		return jsonResult(ctx, versionDriftReport{
+			VersionDrift: drift,
+			Report:       render.VersionDriftReport(formatter, &drift),
+		})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineReportReleaseAdoptionTool creates the report_release_adoption tool definition.
+// Reports how a release was adopted after it was promoted, and which customers are behind.
+func (s *Server) defineReportReleaseAdoptionTool() toolDefinition {
+	tool := mcp.NewTool("report_release_adoption",
+		mcp.WithDescription("Report the adoption funnel of a release: when it was promoted to each channel, "+
+			"how many active instances on those channels upgraded to it or later in each day or week since, "+
+			"and the customers whose instances are still behind. Channel histories and customers are cached "+
+			"for a few minutes, so repeat reports are fast; set refresh to fetch them again."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithNumber("sequence",
+			mcp.Required(),
+			mcp.Description("The sequence number of the release"),
+			mcp.Min(1),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the report to"),
+		),
+		mcp.WithString("bucket",
+			mcp.Description("The width of the upgrade time buckets (default week)"),
+			mcp.Enum(funnelBucketDay, funnelBucketWeek),
+		),
+		withTimeRange("Only count instances that last checked in within this range"),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Fetch channel histories and customers again instead of using cached data"),
+		),
+		outputSchema[releaseAdoption](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("report_release_adoption tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		sequence, err := request.RequireInt("sequence")
+		if err != nil {
+			return nil, err
+		}
+
+		checkedIn, err := requestTimeRange(request)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := s.adoptionData.get(ctx, appID, request.GetBool("refresh", false),
+			func(ctx context.Context) (*adoptionData, error) { return s.fetchAdoptionData(ctx, appID) })
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.ContainsFunc(data.releases, func(r models.Release) bool { return r.Sequence == int64(sequence) }) {
+			return nil, fmt.Errorf("release sequence %d not found for application '%s'", sequence, appID)
+		}
+
+		promotions, err := releasePromotions(data, int64(sequence), request.GetString("channel_id", ""))
+		if err != nil {
+			return nil, err
+		}
+
+		width := funnelBucketWidths[request.GetString("bucket", funnelBucketWeek)]
+		account := adoption.Account{
+			Channels:  data.channels,
+			Releases:  data.releases,
+			Customers: data.customers,
+			CheckedIn: checkedIn,
+		}
+
+		return jsonResult(ctx, releaseAdoption{
+			ReleaseFunnel: account.ReleaseFunnel(int64(sequence), promotions, width, time.Now()),
+			FetchedAt:     data.fetchedAt,
+		})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineGetAdoptionTimeseriesTool creates the get_adoption_timeseries tool definition.
+// Counts the instances on a channel running each release at the end of each day.
+func (s *Server) defineGetAdoptionTimeseriesTool() toolDefinition {
+	tool := mcp.NewTool("get_adoption_timeseries",
+		mcp.WithDescription("Report how adoption of releases on a channel changed over time: for each day, "+
+			"how many of the channel's instances ran each release at the end of the day, newest release "+
+			"first, with its share of that day's instances. Built from instance version histories, so "+
+			"instances count from their first install until their last check-in, and instances without "+
+			"dated history are only counted as undated. Suitable for charting or summarizing a rollout. "+
+			"Customers are cached for a few minutes; set refresh to fetch them again."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier, slug, or name of the channel"),
+		),
+		withTimeRange(fmt.Sprintf("The days to report, in UTC (default the last 30 days, at most %d days)",
+			adoption.MaxTimeseriesDays)),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Fetch channel histories and customers again instead of using cached data"),
+		),
+		localeParameter(),
+		outputSchema[channelAdoptionTimeseries](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_adoption_timeseries tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		channelID, err := request.RequireString("channel_id")
+		if err != nil {
+			return nil, err
+		}
+
+		formatter, err := s.formatter(request)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		window := timerange.Range{Start: now.Add(-defaultTimeseriesWindow)}
+		requested, err := requestTimeRange(request)
+		if err != nil {
+			return nil, err
+		}
+		if requested != nil {
+			window = *requested
+		}
+
+		data, err := s.adoptionData.get(ctx, appID, request.GetBool("refresh", false),
+			func(ctx context.Context) (*adoptionData, error) { return s.fetchAdoptionData(ctx, appID) })
+		if err != nil {
+			return nil, err
+		}
+
+		index := slices.IndexFunc(data.channels, func(c models.Channel) bool { return channelMatches(&c, channelID) })
+		if index < 0 {
+			return nil, fmt.Errorf("channel '%s' not found for application '%s'", channelID, appID)
+		}
+
+		account := adoption.Account{Channels: data.channels, Releases: data.releases, Customers: data.customers}
+		series := channelAdoptionTimeseries{
+			AdoptionTimeseries: account.Timeseries(&data.channels[index], window, now),
+			FetchedAt:          data.fetchedAt,
+		}
+		series.Report = render.AdoptionTimeseriesReport(formatter, &series.AdoptionTimeseries)
+
+		response := envelope{Data: &series}
+		if series.InstancesUndated > 0 {
+			response.warn("%d instances are not counted because their version history does not say when "+
+				"they installed releases, or they are inactive without a last check-in", series.InstancesUndated)
+		}
+		return envelopeResult(ctx, response)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// fetchAdoptionData lists an application's channels, releases, and customers and each
+// channel's release history
+func (s *Server) fetchAdoptionData(ctx context.Context, appID string) (*adoptionData, error) {
+	channels, err := s.channels.ListChannels(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channels: %w", err)
+	}
+
+	releases, err := s.releases.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	customers, err := s.customers.ListCustomers(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([][]models.ChannelRelease, len(channels.Channels))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(channelHistoryConcurrency)
+	for i := range channels.Channels {
+		group.Go(func() error {
+			history, err := s.channels.ListChannelReleases(groupCtx, appID, channels.Channels[i].ID)
+			if err != nil {
+				return err
+			}
+			histories[i] = history.Releases
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	data := &adoptionData{
+		channels:  channels.Channels,
+		releases:  releases.Releases,
+		customers: customers.Customers,
+		histories: make(map[string][]models.ChannelRelease, len(channels.Channels)),
+	}
+	for i := range channels.Channels {
+		data.histories[channels.Channels[i].ID] = histories[i]
+	}
+	return data, nil
+}
+
+// releasePromotions finds when a release was first promoted to each channel, or to one
+// channel given by ID, slug, or name, earliest first
+func releasePromotions(data *adoptionData, sequence int64, channel string) ([]adoption.ReleasePromotion, error) {
+	promotions := []adoption.ReleasePromotion{}
+	found := channel == ""
+	for i := range data.channels {
+		if channel != "" && !channelMatches(&data.channels[i], channel) {
+			continue
+		}
+		found = true
+
+		var promotion *adoption.ReleasePromotion
+		for _, entry := range data.histories[data.channels[i].ID] {
+			if entry.ReleaseSequence != sequence || (promotion != nil && !entry.PromotedAt.Before(promotion.PromotedAt)) {
+				continue
+			}
+			promotion = &adoption.ReleasePromotion{
+				ChannelID:   data.channels[i].ID,
+				ChannelName: data.channels[i].Name,
+				PromotedAt:  entry.PromotedAt,
+			}
+		}
+		if promotion != nil {
+			promotions = append(promotions, *promotion)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("channel '%s' not found", channel)
+	}
+
+	slices.SortFunc(promotions, func(a, b adoption.ReleasePromotion) int { return a.PromotedAt.Compare(b.PromotedAt) })
+	return promotions, nil
+}
+
+// adoptionAccount gathers an application's channels, releases, and customers for the
+// adoption reports, resolving an optional channel ID, slug, or name to the channel's ID and
+// applying an optional time range
+func (s *Server) adoptionAccount(
+	ctx context.Context,
+	appID string,
+	request mcp.CallToolRequest,
+) (account *adoption.Account, channelID string, err error) {
+	checkedIn, err := requestTimeRange(request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	channel := request.GetString("channel_id", "")
+	channels, err := s.channels.ListChannels(ctx, appID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list channels: %w", err)
+	}
+
+	if channel != "" {
+		for i := range channels.Channels {
+			if channelMatches(&channels.Channels[i], channel) {
+				channelID = channels.Channels[i].ID
+				break
+			}
+		}
+		if channelID == "" {
+			return nil, "", fmt.Errorf("channel '%s' not found for application '%s'", channel, appID)
+		}
+	}
+
+	releases, err := s.releases.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	customers, err := s.customers.ListCustomers(ctx, appID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	account = &adoption.Account{
+		Channels:  channels.Channels,
+		Releases:  releases.Releases,
+		Customers: customers.Customers,
+		CheckedIn: checkedIn,
+	}
+	return account, channelID, nil
+}
@@ -0,0 +1,679 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/adoption"
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// newTestAdoptionAPI serves an application whose stable channel is at release 2, with one
+// customer fully upgraded, one partly upgraded, and one without instances. Only the fully
+// upgraded customer's instance has checked in, and only its instance has a version history.
+// Release 2 was promoted to Beta on January 5 and Stable on January 10.
+func newTestAdoptionAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [
+			{"id": "channel-stable", "name": "Stable", "channelSlug": "stable", "releaseSequence": 2},
+			{"id": "channel-beta", "name": "Beta", "channelSlug": "beta", "releaseSequence": 3}
+		]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [
+			{"id": "release-1", "version": "1.0.0", "sequence": 1},
+			{"id": "release-2", "version": "1.1.0", "sequence": 2},
+			{"id": "release-3", "version": "1.2.0-beta", "sequence": 3}
+		]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"totalCustomers": 3, "customers": [
+			{"id": "customer-1", "name": "Acme", "channelId": "channel-stable", "instances": [
+				{"id": "instance-1", "releaseSequence": 2, "isActive": true,
+					"lastCheckinAt": "2025-01-15T00:00:00Z",
+					"kubernetesDistribution": "eks", "kubernetesVersion": "v1.29.3-eks-1",
+					"versionHistory": [
+						{"releaseSequence": 1, "installedAt": "2025-01-02T00:00:00Z"},
+						{"releaseSequence": 2, "installedAt": "2025-01-11T00:00:00Z"}
+					]}
+			]},
+			{"id": "customer-2", "name": "Globex", "channelId": "channel-stable", "instances": [
+				{"id": "instance-2", "releaseSequence": 2, "isActive": true,
+					"kubernetesDistribution": "k3s", "kubernetesVersion": "v1.29.1+k3s2"},
+				{"id": "instance-3", "releaseSequence": 1, "isActive": true}
+			]},
+			{"id": "customer-3", "name": "Initech", "channelId": "channel-beta"}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/"+testAppID+"/release/{sequence}", serveTestRelease)
+	mux.HandleFunc("GET /vendor/v3/app/"+testAppID+"/channel/{channel}/releases",
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.PathValue("channel") {
+			case "channel-stable":
+				fmt.Fprint(w, `{"releases": [
+					{"channelSequence": 2, "releaseSequence": 2, "promotedAt": "2025-01-10T00:00:00Z"},
+					{"channelSequence": 1, "releaseSequence": 1, "promotedAt": "2025-01-01T00:00:00Z"}
+				]}`)
+			case "channel-beta":
+				fmt.Fprint(w, `{"releases": [
+					{"channelSequence": 2, "releaseSequence": 3, "promotedAt": "2025-01-12T00:00:00Z"},
+					{"channelSequence": 1, "releaseSequence": 2, "promotedAt": "2025-01-05T00:00:00Z"}
+				]}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"message": "Not Found"}`)
+			}
+		})
+
+	vendorAPI := httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestGetChannelAdoptionTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantChannels []string
+		expectError  bool
+	}{
+		{
+			name:         "all channels",
+			args:         map[string]any{"app_id": testAppSlug},
+			wantChannels: []string{"channel-stable", "channel-beta"},
+		},
+		{
+			name:         "one channel by slug",
+			args:         map[string]any{"app_id": testAppID, "channel_id": "stable"},
+			wantChannels: []string{"channel-stable"},
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "nightly"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, ok := server.registry.Tool("get_channel_adoption")
+			if !ok {
+				t.Fatal("Tool 'get_channel_adoption' not found")
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_channel_adoption", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var adoptions []adoption.ChannelAdoption
+			if err := json.Unmarshal(resultData(t, result), &adoptions); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if len(adoptions) != len(tt.wantChannels) {
+				t.Fatalf("Expected channels %v, got %+v", tt.wantChannels, adoptions)
+			}
+			for i, channelID := range tt.wantChannels {
+				if adoptions[i].ChannelID != channelID {
+					t.Errorf("Expected channel %d to be %s, got %s", i, channelID, adoptions[i].ChannelID)
+				}
+			}
+
+			stable := adoptions[0]
+			if stable.LatestVersion != "1.1.0" || stable.CustomersOnLatest != 1 || stable.InstancesOnLatest != 2 {
+				t.Errorf("Unexpected stable channel adoption: %+v", stable)
+			}
+		})
+	}
+}
+
+func TestGetCustomerVersionBreakdownTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_customer_version_breakdown")
+	if !ok {
+		t.Fatal("Tool 'get_customer_version_breakdown' not found")
+	}
+
+	args := map[string]any{"app_id": testAppSlug, "channel_id": "Stable"}
+	result, err := tool.handler(context.Background(),
+		createMockCallToolRequest("get_customer_version_breakdown", args))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var breakdown adoption.VersionBreakdown
+	if err := json.Unmarshal(resultData(t, result), &breakdown); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+
+	if len(breakdown.Versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %+v", breakdown.Versions)
+	}
+	if latest := breakdown.Versions[0]; latest.VersionLabel != "1.1.0" || len(latest.Customers) != 2 {
+		t.Errorf("Unexpected latest version group: %+v", latest)
+	}
+	if older := breakdown.Versions[1]; older.VersionLabel != "1.0.0" || len(older.Customers) != 1 {
+		t.Errorf("Unexpected older version group: %+v", older)
+	}
+	if len(breakdown.NotInstalled) != 0 {
+		t.Errorf("Expected the beta customer to be excluded, got %+v", breakdown.NotInstalled)
+	}
+}
+
+func TestReportVersionDriftTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("report_version_drift")
+	if !ok {
+		t.Fatal("Tool 'report_version_drift' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expectError   bool
+		wantCustomers []string
+	}{
+		{
+			name:          "any lag",
+			args:          map[string]any{"app_id": testAppSlug},
+			wantCustomers: []string{"Globex"},
+		},
+		{
+			name:          "lag allowed",
+			args:          map[string]any{"app_id": testAppID, "channel_id": "Stable", "max_lag": 1},
+			wantCustomers: []string{},
+		},
+		{
+			name:        "negative lag",
+			args:        map[string]any{"app_id": testAppID, "max_lag": -1},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "lts"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("report_version_drift", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var drift adoption.VersionDrift
+			if err := json.Unmarshal(resultData(t, result), &drift); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			names := []string{}
+			for _, customer := range drift.Customers {
+				names = append(names, customer.CustomerName)
+			}
+			if !slices.Equal(names, tt.wantCustomers) {
+				t.Errorf("Expected drifting customers %v, got %v", tt.wantCustomers, names)
+			}
+			if len(drift.Customers) > 0 && drift.Customers[0].Instances[0].InstanceID != "instance-3" {
+				t.Errorf("Expected the instance on release 1 to be reported, got %+v", drift.Customers[0].Instances)
+			}
+		})
+	}
+}
+
+func TestReportVersionDriftToolLocale(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+	tool, _ := server.registry.Tool("report_version_drift")
+
+	tests := []struct {
+		name        string
+		locale      string
+		wantReport  string
+		expectError bool
+	}{
+		{
+			name: "configured locale",
+			wantReport: "1 of 2 installed customers (50.0%) run a release more than 0 sequences behind " +
+				"their channel's latest; Globex is furthest behind at 1 sequence",
+		},
+		{
+			name:   "requested locale",
+			locale: "de-DE",
+			wantReport: "1 of 2 installed customers (50,0\u00a0%) run a release more than 0 sequences behind " +
+				"their channel's latest; Globex is furthest behind at 1 sequence",
+		},
+		{name: "unsupported locale", locale: "xx-XX", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"app_id": testAppID}
+			if tt.locale != "" {
+				args["locale"] = tt.locale
+			}
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("report_version_drift", args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var drift versionDriftReport
+			if err := json.Unmarshal(resultData(t, result), &drift); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if drift.Report != tt.wantReport {
+				t.Errorf("Expected report %q, got %q", tt.wantReport, drift.Report)
+			}
+		})
+	}
+}
+
+func TestAdoptionToolsTimeRange(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	call := func(toolName, timeRange string) (*mcp.CallToolResult, error) {
+		tool, ok := server.registry.Tool(toolName)
+		if !ok {
+			t.Fatalf("Tool '%s' not found", toolName)
+		}
+		args := map[string]any{"app_id": testAppID, "channel_id": "stable", "time_range": timeRange}
+		return tool.handler(context.Background(), createMockCallToolRequest(toolName, args))
+	}
+
+	result, err := call("get_channel_adoption", "2025-01-01/2025-01-31")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var adoptions []adoption.ChannelAdoption
+	if err := json.Unmarshal(resultData(t, result), &adoptions); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(adoptions) != 1 || adoptions[0].CustomersInstalled != 1 || adoptions[0].Instances != 1 {
+		t.Errorf("Expected only the instance checked in during January to count, got %+v", adoptions)
+	}
+
+	result, err = call("get_customer_version_breakdown", "2025-02-01/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var breakdown adoption.VersionBreakdown
+	if err := json.Unmarshal(resultData(t, result), &breakdown); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(breakdown.Versions) != 0 || len(breakdown.NotInstalled) != 2 {
+		t.Errorf("Expected customers without recent check-ins to be not installed, got %+v", breakdown)
+	}
+
+	for _, toolName := range []string{"get_channel_adoption", "get_customer_version_breakdown"} {
+		if _, err := call(toolName, "last_30x"); err == nil || !contains(err.Error(), "unit must be h, d, or w") {
+			t.Errorf("Expected %s to reject an invalid time range, got %v", toolName, err)
+		}
+	}
+}
+
+func TestReportK8sDistributionsTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("report_k8s_distributions")
+	if !ok {
+		t.Fatal("Tool 'report_k8s_distributions' not found")
+	}
+
+	tests := []struct {
+		name              string
+		args              map[string]any
+		wantInstances     int
+		wantDistributions []string
+	}{
+		{
+			name:              "every instance",
+			args:              map[string]any{"app_id": testAppSlug},
+			wantInstances:     3,
+			wantDistributions: []string{"eks", "k3s", "unknown"},
+		},
+		{
+			name:              "checked in within a range",
+			args:              map[string]any{"app_id": testAppID, "time_range": "2025-01-01/2025-01-31"},
+			wantInstances:     1,
+			wantDistributions: []string{"eks"},
+		},
+		{
+			name:              "channel without instances",
+			args:              map[string]any{"app_id": testAppID, "channel_id": "beta"},
+			wantDistributions: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(),
+				createMockCallToolRequest("report_k8s_distributions", tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var report adoption.DistributionReport
+			if err := json.Unmarshal(resultData(t, result), &report); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if report.Instances != tt.wantInstances || len(report.Distributions) != len(tt.wantDistributions) {
+				t.Fatalf("Expected %d instances on %v, got %+v", tt.wantInstances, tt.wantDistributions, report)
+			}
+			for i, name := range tt.wantDistributions {
+				if report.Distributions[i].Name != name {
+					t.Errorf("Expected distribution %s at %d, got %+v", name, i, report.Distributions[i])
+				}
+			}
+			if contains(resultText(t, result), "Acme") {
+				t.Error("Expected the report to hold no customer names")
+			}
+		})
+	}
+}
+
+func TestReportReleaseAdoptionTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("report_release_adoption")
+	if !ok {
+		t.Fatal("Tool 'report_release_adoption' not found")
+	}
+
+	tests := []struct {
+		name           string
+		args           map[string]any
+		wantPromotions []string
+		wantInstances  int
+		wantUpgraded   int
+		wantStragglers []string
+		expectError    bool
+	}{
+		{
+			name:           "every channel",
+			args:           map[string]any{"app_id": testAppSlug, "sequence": 2},
+			wantPromotions: []string{"channel-beta", "channel-stable"},
+			wantInstances:  3,
+			wantUpgraded:   2,
+			wantStragglers: []string{"customer-2"},
+		},
+		{
+			name:           "one channel by name",
+			args:           map[string]any{"app_id": testAppID, "sequence": 3, "channel_id": "Beta", "bucket": "day"},
+			wantPromotions: []string{"channel-beta"},
+		},
+		{
+			name:           "release not promoted to the channel",
+			args:           map[string]any{"app_id": testAppID, "sequence": 3, "channel_id": "stable"},
+			wantPromotions: []string{},
+		},
+		{
+			name:        "unknown release",
+			args:        map[string]any{"app_id": testAppID, "sequence": 9},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "sequence": 2, "channel_id": "lts"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(),
+				createMockCallToolRequest("report_release_adoption", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var report releaseAdoption
+			if err := json.Unmarshal(resultData(t, result), &report); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+
+			promotions := []string{}
+			for _, promotion := range report.Promotions {
+				promotions = append(promotions, promotion.ChannelID)
+			}
+			stragglers := []string{}
+			for _, straggler := range report.Stragglers {
+				stragglers = append(stragglers, straggler.CustomerID)
+			}
+			if !slices.Equal(promotions, tt.wantPromotions) {
+				t.Errorf("Expected promotions to %v, got %v", tt.wantPromotions, promotions)
+			}
+			if tt.wantStragglers == nil {
+				return
+			}
+			if report.Instances != tt.wantInstances || report.InstancesUpgraded != tt.wantUpgraded ||
+				report.UpgradedUndated != 1 || report.Buckets[0].Upgraded != 1 {
+				t.Errorf("Unexpected funnel: %+v", report.ReleaseFunnel)
+			}
+			if !slices.Equal(stragglers, tt.wantStragglers) {
+				t.Errorf("Expected stragglers %v, got %v", tt.wantStragglers, stragglers)
+			}
+		})
+	}
+}
+
+func TestReportReleaseAdoptionToolCaching(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("report_release_adoption")
+	if !ok {
+		t.Fatal("Tool 'report_release_adoption' not found")
+	}
+
+	fetchedAt := func(args map[string]any) time.Time {
+		t.Helper()
+		result, err := tool.handler(context.Background(), createMockCallToolRequest("report_release_adoption", args))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var report releaseAdoption
+		if err := json.Unmarshal(resultData(t, result), &report); err != nil {
+			t.Fatalf("Failed to decode result: %v", err)
+		}
+		return report.FetchedAt
+	}
+
+	first := fetchedAt(map[string]any{"app_id": testAppID, "sequence": 2})
+	if cached := fetchedAt(map[string]any{"app_id": testAppID, "sequence": 1}); !cached.Equal(first) {
+		t.Errorf("Expected a repeat report to use cached data fetched at %v, got %v", first, cached)
+	}
+
+	time.Sleep(time.Millisecond)
+	refreshed := fetchedAt(map[string]any{"app_id": testAppID, "sequence": 2, "refresh": true})
+	if !refreshed.After(first) {
+		t.Errorf("Expected refresh to fetch the data again, got data fetched at %v", refreshed)
+	}
+}
+
+// fakeAdoptionAccount serves the channels, releases, customers, and channel histories of
+// newTestAdoptionAPI's application
+type fakeAdoptionAccount struct {
+	channelsService
+	releasesService
+	customersService
+}
+
+// adoptionDay returns midnight UTC of a day in January 2025
+func adoptionDay(day int) time.Time {
+	return time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (fakeAdoptionAccount) ListChannels(context.Context, string) (*api.ChannelList, error) {
+	return &api.ChannelList{Channels: []models.Channel{
+		{ID: "channel-stable", Name: "Stable", ChannelSlug: "stable", ReleaseSequence: 2},
+		{ID: "channel-beta", Name: "Beta", ChannelSlug: "beta", ReleaseSequence: 3},
+	}}, nil
+}
+
+func (fakeAdoptionAccount) ListReleases(context.Context, string) (*api.ReleaseList, error) {
+	return &api.ReleaseList{Releases: []models.Release{
+		{ID: "release-1", Version: "1.0.0", Sequence: 1},
+		{ID: "release-2", Version: "1.1.0", Sequence: 2},
+		{ID: "release-3", Version: "1.2.0-beta", Sequence: 3},
+	}}, nil
+}
+
+func (fakeAdoptionAccount) ListCustomers(context.Context, string) (*api.CustomerList, error) {
+	checkin := adoptionDay(15)
+	return &api.CustomerList{TotalCount: 3, Customers: []models.Customer{
+		{ID: "customer-1", Name: "Acme", ChannelID: "channel-stable", Instances: []models.Instance{{
+			ID: "instance-1", ReleaseSequence: 2, IsActive: true, LastCheckinAt: &checkin,
+			VersionHistory: []models.InstanceVersion{
+				{ReleaseSequence: 1, InstalledAt: adoptionDay(2)},
+				{ReleaseSequence: 2, InstalledAt: adoptionDay(11)},
+			},
+		}}},
+		{ID: "customer-2", Name: "Globex", ChannelID: "channel-stable", Instances: []models.Instance{
+			{ID: "instance-2", ReleaseSequence: 2, IsActive: true},
+			{ID: "instance-3", ReleaseSequence: 1, IsActive: true},
+		}},
+		{ID: "customer-3", Name: "Initech", ChannelID: "channel-beta"},
+	}}, nil
+}
+
+func (fakeAdoptionAccount) ListChannelReleases(
+	_ context.Context, _, channelID string,
+) (*api.ChannelReleaseList, error) {
+	switch channelID {
+	case "channel-stable":
+		return &api.ChannelReleaseList{Releases: []models.ChannelRelease{
+			{ChannelSequence: 2, ReleaseSequence: 2, PromotedAt: adoptionDay(10)},
+			{ChannelSequence: 1, ReleaseSequence: 1, PromotedAt: adoptionDay(1)},
+		}}, nil
+	case "channel-beta":
+		return &api.ChannelReleaseList{Releases: []models.ChannelRelease{
+			{ChannelSequence: 2, ReleaseSequence: 3, PromotedAt: adoptionDay(12)},
+			{ChannelSequence: 1, ReleaseSequence: 2, PromotedAt: adoptionDay(5)},
+		}}, nil
+	default:
+		return nil, &api.Error{StatusCode: http.StatusNotFound, Message: "Not Found"}
+	}
+}
+
+func TestGetAdoptionTimeseriesTool(t *testing.T) {
+	account := fakeAdoptionAccount{}
+	server := newTestServerWithServices(t, vendorapi.Services{
+		Channels: account, Releases: account, Customers: account,
+	})
+
+	tool, ok := server.registry.Tool("get_adoption_timeseries")
+	if !ok {
+		t.Fatal("Tool 'get_adoption_timeseries' not found")
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		wantDays    map[string][]int64
+		expectError bool
+	}{
+		{
+			name: "channel by slug",
+			args: map[string]any{"app_id": testAppSlug, "channel_id": "stable", "time_range": "2025-01-01/2025-01-12"},
+			wantDays: map[string][]int64{
+				"2025-01-01": {},
+				"2025-01-02": {1},
+				"2025-01-11": {2},
+			},
+		},
+		{
+			name:     "channel without instance history",
+			args:     map[string]any{"app_id": testAppID, "channel_id": "Beta", "time_range": "2025-01-01/2025-01-12"},
+			wantDays: map[string][]int64{"2025-01-11": {}},
+		},
+		{
+			name:        "missing channel",
+			args:        map[string]any{"app_id": testAppID},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "lts"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(),
+				createMockCallToolRequest("get_adoption_timeseries", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var series channelAdoptionTimeseries
+			if err := json.Unmarshal(resultData(t, result), &series); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+
+			if len(series.Days) != 12 {
+				t.Fatalf("Expected 12 days, got %d", len(series.Days))
+			}
+			for _, day := range series.Days {
+				want, ok := tt.wantDays[day.Date]
+				if !ok {
+					continue
+				}
+				sequences := []int64{}
+				for _, version := range day.Versions {
+					sequences = append(sequences, version.ReleaseSequence)
+				}
+				if !slices.Equal(sequences, want) {
+					t.Errorf("Expected releases %v on %s, got %v", want, day.Date, sequences)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,407 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// allAppsConcurrency bounds how many applications the *_all_apps tools list at once
+const allAppsConcurrency = 4
+
+// allAppsFailure records an application whose entities an *_all_apps tool could not list
+type allAppsFailure struct {
+	app models.Application
+	err error
+}
+
+// appLister lists one kind of entity in an application
+type appLister[T any] func(ctx context.Context, appID string) ([]T, error)
+
+// appIDField returns the field of an entity recording the application it belongs to
+type appIDField[T any] func(item *T) *string
+
+// allAppsDescription describes an *_all_apps tool listing the given kind of entity
+func allAppsDescription(noun string) string {
+	return fmt.Sprintf("List the %[1]s of every application in one call, instead of calling the list tool once "+
+		"per application. Applications are listed concurrently and their %[1]s merged in application order, "+
+		"each with its application_id set. Applications whose %[1]s cannot be listed are reported in the "+
+		"warnings with their error rather than failing the call.", noun)
+}
+
+// allAppsTool creates the definition of an *_all_apps tool listing the given kind of entity
+func allAppsTool[T any](name, noun string) mcp.Tool {
+	return mcp.NewTool(name,
+		mcp.WithDescription(allAppsDescription(noun)),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of %s to return (1-100)", noun)),
+			mcp.Min(minLimit),
+			mcp.Max(maxListLimit),
+		),
+		cursorParameter(noun),
+		outputSchema[[]T](),
+	)
+}
+
+// defineListReleasesAllAppsTool creates the list_releases_all_apps tool definition.
+// Lists the releases of every application.
+func (s *Server) defineListReleasesAllAppsTool() toolDefinition {
+	tool := allAppsTool[models.Release]("list_releases_all_apps", "releases")
+
+	handler := listAllApps(s, tool.Name, "releases",
+		func(ctx context.Context, appID string) ([]models.Release, error) {
+			list, err := s.releases.ListReleases(ctx, appID)
+			if err != nil {
+				return nil, err
+			}
+			return list.Releases, nil
+		},
+		func(release *models.Release) *string { return &release.ApplicationID },
+	)
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineListChannelsAllAppsTool creates the list_channels_all_apps tool definition.
+// Lists the channels of every application.
+func (s *Server) defineListChannelsAllAppsTool() toolDefinition {
+	tool := allAppsTool[models.Channel]("list_channels_all_apps", "channels")
+
+	handler := listAllApps(s, tool.Name, "channels",
+		func(ctx context.Context, appID string) ([]models.Channel, error) {
+			list, err := s.channels.ListChannels(ctx, appID)
+			if err != nil {
+				return nil, err
+			}
+			return list.Channels, nil
+		},
+		func(channel *models.Channel) *string { return &channel.ApplicationID },
+	)
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineListCustomersAllAppsTool creates the list_customers_all_apps tool definition.
+// Lists the customers of every application.
+func (s *Server) defineListCustomersAllAppsTool() toolDefinition {
+	tool := allAppsTool[models.Customer]("list_customers_all_apps", "customers")
+
+	handler := listAllApps(s, tool.Name, "customers",
+		func(ctx context.Context, appID string) ([]models.Customer, error) {
+			list, err := s.customers.ListCustomers(ctx, appID)
+			if err != nil {
+				return nil, err
+			}
+			return list.Customers, nil
+		},
+		func(customer *models.Customer) *string { return &customer.ApplicationID },
+	)
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// customerMatch is a customer find_customer found, with the application it belongs to
+type customerMatch struct {
+	AppSlug string `json:"app_slug"`
+	AppName string `json:"app_name"`
+	models.Customer
+}
+
+// defineFindCustomerTool creates the find_customer tool definition.
+// Finds customers by name, email, or license ID in every application, for when the application is unknown.
+func (s *Server) defineFindCustomerTool() toolDefinition {
+	tool := mcp.NewTool("find_customer",
+		mcp.WithDescription("Find customers by name, email, license ID, or customer ID in every application, "+
+			"ignoring case, when the customer's application is not known. Each match includes the "+
+			"application it belongs to (application_id, app_slug, and app_name), so it can be passed to the "+
+			"other customer tools. Matches equal to the query, such as the exact email address, come first. "+
+			"Only the customers within the server's search scan limit are read in each application; the "+
+			"result's pagination then includes truncated: true. Applications whose customers cannot be read "+
+			"are reported in the warnings rather than failing the call."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The customer name, email address, license ID, or customer ID to look for"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of customers to return (1-50)"),
+			mcp.Min(minLimit),
+			mcp.Max(maxSearchLimit),
+		),
+		outputSchema[[]customerMatch](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("find_customer tool called", "arguments", request.GetArguments())
+
+		query, err := request.RequireString("query")
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(query) == "" {
+			return nil, fmt.Errorf("query is required")
+		}
+
+		scan, err := s.scanAllCustomers(ctx, func(customer *models.Customer) bool {
+			return models.MatchesQuery(query, customer.Name, customer.Email, customer.LicenseID, customer.ID)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		response := firstPage(exactFirst(scan.matches, query), request.GetInt("limit", maxSearchLimit))
+		scan.warn(&response)
+		return envelopeResult(ctx, response)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// Kinds of ID lookup_license resolves
+const (
+	lookupByLicenseID  = "license_id"
+	lookupByInstanceID = "instance_id"
+)
+
+// licenseLookup is the customer lookup_license resolved an ID to, with its application, what
+// kind of ID it was, and for an instance ID, the instance
+type licenseLookup struct {
+	MatchedBy string           `json:"matched_by"`
+	Instance  *models.Instance `json:"instance,omitempty"`
+	customerMatch
+}
+
+// defineLookupLicenseTool creates the lookup_license tool definition.
+// Resolves a license ID or instance ID from a support ticket to its customer and application.
+func (s *Server) defineLookupLicenseTool() toolDefinition {
+	tool := mcp.NewTool("lookup_license",
+		mcp.WithDescription("Resolve a license ID or installation (instance) ID, such as one quoted in a "+
+			"support ticket, to the customer it belongs to and the customer's application, searching every "+
+			"application. Reports which kind of ID matched and, for an instance ID, the instance. Only the "+
+			"customers within the server's search scan limit are read in each application, and applications "+
+			"whose customers cannot be read are reported in the warnings."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The license ID or instance ID to resolve"),
+		),
+		outputSchema[licenseLookup](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("lookup_license tool called", "arguments", request.GetArguments())
+
+		id, err := request.RequireString("id")
+		if err != nil {
+			return nil, err
+		}
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, fmt.Errorf("id is required")
+		}
+
+		scan, err := s.scanAllCustomers(ctx, func(customer *models.Customer) bool {
+			return customer.LicenseID == id || customerInstanceIndex(customer, id) >= 0
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(scan.matches) == 0 {
+			reason := ""
+			if scan.truncated || len(scan.failures) > 0 {
+				reason = "; some applications' customers could not be read or were beyond the search scan limit"
+			}
+			return nil, fmt.Errorf("no customer has license ID or instance ID '%s'%s", id, reason)
+		}
+
+		found := licenseLookup{MatchedBy: lookupByLicenseID, customerMatch: scan.matches[0]}
+		if index := customerInstanceIndex(&found.Customer, id); index >= 0 && found.LicenseID != id {
+			found.MatchedBy = lookupByInstanceID
+			found.Instance = &found.Instances[index]
+		}
+
+		response := envelope{Data: &found}
+		if others := len(scan.matches) - 1; others > 0 {
+			response.warn("%d other customers also match '%s'; use find_customer to list them", others, id)
+		}
+		scan.warn(&response)
+		return envelopeResult(ctx, response)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// customerInstanceIndex returns the index of the customer's instance with the given ID, or -1
+func customerInstanceIndex(customer *models.Customer, instanceID string) int {
+	return slices.IndexFunc(customer.Instances, func(instance models.Instance) bool {
+		return instance.ID == instanceID
+	})
+}
+
+// exactFirst puts the customers with a name, email, license ID, or ID equal to the query first
+func exactFirst(matches []customerMatch, query string) []customerMatch {
+	exact := func(match customerMatch) bool {
+		return models.SameName(query, match.Name) || models.SameName(query, match.Email) ||
+			query == match.LicenseID || query == match.ID
+	}
+	slices.SortStableFunc(matches, func(a, b customerMatch) int {
+		switch {
+		case exact(a) && !exact(b):
+			return -1
+		case exact(b) && !exact(a):
+			return 1
+		}
+		return 0
+	})
+	return matches
+}
+
+// customerScan is the customers of every application that matched a scan, each with its
+// application, the applications whose customers could not be read, and whether any
+// application had customers beyond the search scan limit
+type customerScan struct {
+	matches   []customerMatch
+	failures  []allAppsFailure
+	truncated bool
+}
+
+// scanAllCustomers reads the customers of every application concurrently, up to the search
+// scan limit in each, and keeps those that match. The scan only fails when there are
+// applications and none of their customers could be read.
+func (s *Server) scanAllCustomers(ctx context.Context, match func(*models.Customer) bool) (*customerScan, error) {
+	apps, err := s.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	var truncated atomic.Bool
+	scanLimit := s.currentConfig().SearchScanLimit
+	customers, failures, err := fanOutApps(ctx, apps.Applications,
+		func(ctx context.Context, appID string) ([]models.Customer, error) {
+			list, err := s.customers.ListCustomersUpTo(ctx, appID, scanLimit)
+			if err != nil {
+				return nil, err
+			}
+			if list.Truncated {
+				truncated.Store(true)
+			}
+			return slices.DeleteFunc(list.Customers, func(customer models.Customer) bool {
+				return !match(&customer)
+			}), nil
+		},
+		func(customer *models.Customer) *string { return &customer.ApplicationID },
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the customers of any application: %w", err)
+	}
+
+	byID := make(map[string]*models.Application, len(apps.Applications))
+	for i := range apps.Applications {
+		byID[apps.Applications[i].ID] = &apps.Applications[i]
+	}
+
+	scan := &customerScan{matches: make([]customerMatch, 0, len(customers)), failures: failures,
+		truncated: truncated.Load()}
+	for i := range customers {
+		found := customerMatch{Customer: customers[i]}
+		if app := byID[customers[i].ApplicationID]; app != nil {
+			found.AppSlug, found.AppName = app.Slug, app.Name
+		}
+		scan.matches = append(scan.matches, found)
+	}
+	return scan, nil
+}
+
+// warn reports the applications the scan could not read, and whether it was cut off by the
+// search scan limit, in a response
+func (c *customerScan) warn(response *envelope) {
+	for _, failure := range c.failures {
+		response.warn("the customers of application %s (%s) could not be read: %v",
+			cmp.Or(failure.app.Slug, failure.app.Name), failure.app.ID, failure.err)
+	}
+	if c.truncated {
+		response.truncate("customers")
+	}
+}
+
+// listAllApps returns the handler of an *_all_apps tool, which lists entities in every
+// application with list. The call only fails when there are applications and none of them
+// could be listed.
+func listAllApps[T any](
+	s *Server, name, noun string, list appLister[T], appID appIDField[T],
+) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info(name+" tool called", "arguments", request.GetArguments())
+
+		apps, err := s.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+
+		items, failures, err := fanOutApps(ctx, apps.Applications, list, appID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the %s of any application: %w", noun, err)
+		}
+
+		page, err := pageOf(request, items)
+		if err != nil {
+			return nil, err
+		}
+		for _, failure := range failures {
+			page.warn("the %s of application %s (%s) could not be listed: %v",
+				noun, cmp.Or(failure.app.Slug, failure.app.Name), failure.app.ID, failure.err)
+		}
+		return envelopeResult(ctx, page)
+	}
+}
+
+// fanOutApps lists entities in each application with bounded concurrency, setting each
+// entity's application ID, and merges them in application order. A failed application is
+// returned among the failures rather than stopping the others; the first error is returned
+// only when every application failed.
+func fanOutApps[T any](
+	ctx context.Context, apps []models.Application, list appLister[T], appID appIDField[T],
+) ([]T, []allAppsFailure, error) {
+	listed := make([][]T, len(apps))
+	errs := make([]error, len(apps))
+
+	var group errgroup.Group
+	group.SetLimit(allAppsConcurrency)
+	for i := range apps {
+		group.Go(func() error {
+			listed[i], errs[i] = list(ctx, apps[i].ID)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	items := []T{}
+	var failures []allAppsFailure
+	for i := range apps {
+		if errs[i] != nil {
+			failures = append(failures, allAppsFailure{app: apps[i], err: errs[i]})
+			continue
+		}
+
+		for j := range listed[i] {
+			*appID(&listed[i][j]) = apps[i].ID
+		}
+		items = append(items, listed[i]...)
+	}
+
+	if len(apps) > 0 && len(failures) == len(apps) {
+		return nil, nil, failures[0].err
+	}
+	return items, failures, nil
+}
@@ -0,0 +1,412 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// Aliases let fakeAccount embed the service interfaces without their field names clashing
+// with the iterator methods of the same names
+type (
+	channelsService  = vendorapi.Channels
+	releasesService  = vendorapi.Releases
+	customersService = vendorapi.Customers
+)
+
+// fakeAccount serves the channels, releases, and customers of several applications. The
+// entities of applications listed in failing cannot be listed.
+type fakeAccount struct {
+	channelsService
+	releasesService
+	customersService
+	failing map[string]bool
+}
+
+func (f *fakeAccount) check(appID string) error {
+	if f.failing[appID] {
+		return &api.Error{StatusCode: http.StatusForbidden, Message: "Forbidden"}
+	}
+	return nil
+}
+
+func (f *fakeAccount) ListChannels(_ context.Context, appID string) (*api.ChannelList, error) {
+	if err := f.check(appID); err != nil {
+		return nil, err
+	}
+	return &api.ChannelList{Channels: []models.Channel{{ID: appID + "-stable"}, {ID: appID + "-beta"}}}, nil
+}
+
+func (f *fakeAccount) ListReleases(_ context.Context, appID string) (*api.ReleaseList, error) {
+	if err := f.check(appID); err != nil {
+		return nil, err
+	}
+	return &api.ReleaseList{Releases: []models.Release{{ID: appID + "-1", Sequence: 1}}}, nil
+}
+
+func (f *fakeAccount) ListCustomers(_ context.Context, appID string) (*api.CustomerList, error) {
+	if err := f.check(appID); err != nil {
+		return nil, err
+	}
+	return &api.CustomerList{Customers: []models.Customer{{ID: appID + "-acme"}}, TotalCount: 1}, nil
+}
+
+// allAppsItem is the part of a listed entity the tests check
+type allAppsItem struct {
+	ID            string `json:"id"`
+	ApplicationID string `json:"appId"`
+}
+
+func TestListAllApps(t *testing.T) {
+	apps := []models.Application{
+		{ID: "app-a", Slug: "alpha", Name: "Alpha"},
+		{ID: "app-b", Slug: "bravo", Name: "Bravo"},
+		{ID: "app-c", Slug: "charlie", Name: "Charlie"},
+	}
+
+	tests := []struct {
+		name        string
+		tool        string
+		failing     map[string]bool
+		arguments   map[string]any
+		wantItems   []allAppsItem
+		wantCursor  bool
+		wantError   bool
+		errContains string
+	}{
+		{
+			name: "channels of every application",
+			tool: "list_channels_all_apps",
+			wantItems: []allAppsItem{
+				{ID: "app-a-stable", ApplicationID: "app-a"}, {ID: "app-a-beta", ApplicationID: "app-a"},
+				{ID: "app-b-stable", ApplicationID: "app-b"}, {ID: "app-b-beta", ApplicationID: "app-b"},
+				{ID: "app-c-stable", ApplicationID: "app-c"}, {ID: "app-c-beta", ApplicationID: "app-c"},
+			},
+		},
+		{
+			name:    "releases with one application failing",
+			tool:    "list_releases_all_apps",
+			failing: map[string]bool{"app-b": true},
+			wantItems: []allAppsItem{
+				{ID: "app-a-1", ApplicationID: "app-a"}, {ID: "app-c-1", ApplicationID: "app-c"},
+			},
+		},
+		{
+			name:       "first page of customers",
+			tool:       "list_customers_all_apps",
+			arguments:  map[string]any{"limit": 2},
+			wantItems:  []allAppsItem{{ID: "app-a-acme", ApplicationID: "app-a"}, {ID: "app-b-acme", ApplicationID: "app-b"}},
+			wantCursor: true,
+		},
+		{
+			name:        "every application failing",
+			tool:        "list_customers_all_apps",
+			failing:     map[string]bool{"app-a": true, "app-b": true, "app-c": true},
+			wantError:   true,
+			errContains: "failed to list the customers of any application",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &fakeAccount{failing: tt.failing}
+			cfg := &config.Config{LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir()}
+			server, err := NewServerWithServices(cfg, logging.NewLogger("info"), &vendorapi.Services{
+				Client:       fakeClient{},
+				Applications: &fakeApplications{apps: apps},
+				Channels:     account,
+				Releases:     account,
+				Customers:    account,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			tool, ok := server.registry.Tool(tt.tool)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.tool)
+			}
+			arguments := tt.arguments
+			if arguments == nil {
+				arguments = map[string]any{}
+			}
+			result, err := tool.handler(context.Background(), createMockCallToolRequest(tt.tool, arguments))
+			if tt.wantError {
+				if err == nil || !contains(err.Error(), tt.errContains) {
+					t.Fatalf("Expected error containing '%s', got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			page := decodeEnvelope[[]allAppsItem](t, result)
+			if len(page.Data) != len(tt.wantItems) {
+				t.Fatalf("Expected %d items, got %+v", len(tt.wantItems), page.Data)
+			}
+			for i, item := range page.Data {
+				if item != tt.wantItems[i] {
+					t.Errorf("Expected item %d to be %+v, got %+v", i, tt.wantItems[i], item)
+				}
+			}
+			if (page.Pagination.NextCursor != "") != tt.wantCursor {
+				t.Errorf("Expected a next cursor %v, got '%s'", tt.wantCursor, page.Pagination.NextCursor)
+			}
+
+			if len(page.Warnings) != len(tt.failing) {
+				t.Fatalf("Expected a warning for each failing application, got %v", page.Warnings)
+			}
+			for i, app := range apps {
+				warned := slices.ContainsFunc(page.Warnings, func(warning string) bool {
+					return contains(warning, app.Slug)
+				})
+				if warned != tt.failing[apps[i].ID] {
+					t.Errorf("Expected %s failing %v, got warnings %v", app.Slug, tt.failing[app.ID], page.Warnings)
+				}
+			}
+		})
+	}
+}
+
+// fakeCustomerDirectory serves the customers of each application, reporting the customers
+// of applications in truncated as cut off by the scan limit
+type fakeCustomerDirectory struct {
+	customersService
+	customers map[string][]models.Customer
+	truncated map[string]bool
+	failing   map[string]bool
+}
+
+func (f *fakeCustomerDirectory) ListCustomersUpTo(_ context.Context, appID string, _ int) (*api.CustomerList, error) {
+	if f.failing[appID] {
+		return nil, &api.Error{StatusCode: http.StatusForbidden, Message: "Forbidden"}
+	}
+	customers := slices.Clone(f.customers[appID])
+	return &api.CustomerList{Customers: customers, TotalCount: len(customers), Truncated: f.truncated[appID]}, nil
+}
+
+func TestFindCustomerTool(t *testing.T) {
+	apps := []models.Application{
+		{ID: "app-a", Slug: "alpha", Name: "Alpha"},
+		{ID: "app-b", Slug: "bravo", Name: "Bravo"},
+	}
+	directory := map[string][]models.Customer{
+		"app-a": {
+			{ID: "cust-1", Name: "Acme Labs", Email: "ops@acmelabs.example", LicenseID: "license-1"},
+			{ID: "cust-2", Name: "Globex", Email: "it@globex.example", LicenseID: "license-2"},
+		},
+		"app-b": {
+			{ID: "cust-3", Name: "Acme", Email: "ops@acme.example", LicenseID: "license-3"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		arguments    map[string]any
+		truncated    map[string]bool
+		failing      map[string]bool
+		wantIDs      []string
+		wantApps     []string
+		wantWarnings int
+		wantError    bool
+	}{
+		{
+			name:      "exact name first",
+			arguments: map[string]any{"query": "acme"},
+			wantIDs:   []string{"cust-3", "cust-1"},
+			wantApps:  []string{"bravo", "alpha"},
+		},
+		{
+			name:      "email in another application",
+			arguments: map[string]any{"query": "OPS@ACME.EXAMPLE"},
+			wantIDs:   []string{"cust-3"},
+			wantApps:  []string{"bravo"},
+		},
+		{
+			name:      "license ID",
+			arguments: map[string]any{"query": "license-2"},
+			wantIDs:   []string{"cust-2"},
+			wantApps:  []string{"alpha"},
+		},
+		{
+			name:         "truncated application",
+			arguments:    map[string]any{"query": "globex"},
+			truncated:    map[string]bool{"app-b": true},
+			wantIDs:      []string{"cust-2"},
+			wantApps:     []string{"alpha"},
+			wantWarnings: 1,
+		},
+		{
+			name:         "failing application",
+			arguments:    map[string]any{"query": "acme", "limit": 1},
+			failing:      map[string]bool{"app-b": true},
+			wantIDs:      []string{"cust-1"},
+			wantApps:     []string{"alpha"},
+			wantWarnings: 1,
+		},
+		{
+			name:      "blank query",
+			arguments: map[string]any{"query": " "},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir()}
+			server, err := NewServerWithServices(cfg, logging.NewLogger("info"), &vendorapi.Services{
+				Client:       fakeClient{},
+				Applications: &fakeApplications{apps: apps},
+				Customers:    &fakeCustomerDirectory{customers: directory, truncated: tt.truncated, failing: tt.failing},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			tool, _ := server.registry.Tool("find_customer")
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("find_customer", tt.arguments))
+			if tt.wantError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			page := decodeEnvelope[[]customerMatch](t, result)
+			ids, slugs := []string{}, []string{}
+			for _, match := range page.Data {
+				ids = append(ids, match.ID)
+				slugs = append(slugs, match.AppSlug)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || !slices.Equal(slugs, tt.wantApps) {
+				t.Errorf("Expected customers %v in %v, got %v in %v", tt.wantIDs, tt.wantApps, ids, slugs)
+			}
+			if len(page.Warnings) != tt.wantWarnings {
+				t.Errorf("Expected %d warnings, got %v", tt.wantWarnings, page.Warnings)
+			}
+			if page.Pagination.Truncated != (tt.truncated != nil) {
+				t.Errorf("Expected truncated %v, got %+v", tt.truncated != nil, page.Pagination)
+			}
+		})
+	}
+}
+
+func TestLookupLicenseTool(t *testing.T) {
+	apps := []models.Application{
+		{ID: "app-a", Slug: "alpha", Name: "Alpha"},
+		{ID: "app-b", Slug: "bravo", Name: "Bravo"},
+	}
+	directory := map[string][]models.Customer{
+		"app-a": {{ID: "cust-1", Name: "Acme", LicenseID: "license-1"}},
+		"app-b": {{ID: "cust-2", Name: "Globex", LicenseID: "license-2", Instances: []models.Instance{
+			{ID: "instance-1", VersionLabel: "1.2.0"}, {ID: "instance-2", VersionLabel: "1.1.0"},
+		}}},
+	}
+
+	tests := []struct {
+		name          string
+		id            string
+		truncated     map[string]bool
+		failing       map[string]bool
+		wantCustomer  string
+		wantApp       string
+		wantMatchedBy string
+		wantInstance  string
+		wantWarnings  int
+		errContains   string
+	}{
+		{
+			name:          "license ID",
+			id:            "license-1",
+			wantCustomer:  "cust-1",
+			wantApp:       "alpha",
+			wantMatchedBy: "license_id",
+		},
+		{
+			name:          "instance ID",
+			id:            " instance-2 ",
+			wantCustomer:  "cust-2",
+			wantApp:       "bravo",
+			wantMatchedBy: "instance_id",
+			wantInstance:  "instance-2",
+		},
+		{
+			name:          "found despite an unreadable application",
+			id:            "license-2",
+			failing:       map[string]bool{"app-a": true},
+			wantCustomer:  "cust-2",
+			wantApp:       "bravo",
+			wantMatchedBy: "license_id",
+			wantWarnings:  1,
+		},
+		{
+			name:        "unknown ID",
+			id:          "license-9",
+			errContains: "no customer has license ID or instance ID 'license-9'",
+		},
+		{
+			name:        "unknown ID beyond the scan limit",
+			id:          "license-9",
+			truncated:   map[string]bool{"app-a": true},
+			errContains: "beyond the search scan limit",
+		},
+		{
+			name:        "partial license ID",
+			id:          "license",
+			errContains: "no customer has",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir()}
+			server, err := NewServerWithServices(cfg, logging.NewLogger("info"), &vendorapi.Services{
+				Client:       fakeClient{},
+				Applications: &fakeApplications{apps: apps},
+				Customers:    &fakeCustomerDirectory{customers: directory, truncated: tt.truncated, failing: tt.failing},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			tool, _ := server.registry.Tool("lookup_license")
+			result, err := tool.handler(context.Background(),
+				createMockCallToolRequest("lookup_license", map[string]any{"id": tt.id}))
+			if tt.errContains != "" {
+				if err == nil || !contains(err.Error(), tt.errContains) {
+					t.Fatalf("Expected error containing '%s', got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			response := decodeEnvelope[licenseLookup](t, result)
+			found := response.Data
+			if found.ID != tt.wantCustomer || found.AppSlug != tt.wantApp || found.ApplicationID == "" ||
+				found.MatchedBy != tt.wantMatchedBy {
+				t.Errorf("Expected customer %s of %s by %s, got %+v", tt.wantCustomer, tt.wantApp, tt.wantMatchedBy, found)
+			}
+			if (found.Instance == nil && tt.wantInstance != "") ||
+				(found.Instance != nil && found.Instance.ID != tt.wantInstance) {
+				t.Errorf("Expected instance '%s', got %+v", tt.wantInstance, found.Instance)
+			}
+			if len(response.Warnings) != tt.wantWarnings {
+				t.Errorf("Expected %d warnings, got %v", tt.wantWarnings, response.Warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// applicationArchiveResult reports an application after an archive_application call
+type applicationArchiveResult struct {
+	AppID    string `json:"app_id"`
+	AppSlug  string `json:"app_slug"`
+	AppName  string `json:"app_name"`
+	Archived bool   `json:"archived"`
+}
+
+// Application Management Tools
+
+// defineCreateApplicationTool creates the create_application tool definition.
+// Creates an application, such as when a platform team bootstraps a new product.
+func (s *Server) defineCreateApplicationTool() toolDefinition {
+	tool := mcp.NewTool("create_application",
+		mcp.WithDescription("Create an application in the Vendor Portal, such as to bootstrap a new product. "+
+			"The Vendor Portal derives the slug from the name (\"My Product\" becomes my-product), so the name "+
+			"must contain letters or digits, and a name whose slug an existing application already uses is "+
+			"rejected. Returns the created application. With dry_run, the application is previewed without "+
+			"creating it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the application"),
+		),
+		dryRunParameter(),
+		outputSchema[models.Application](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("create_application tool called", "arguments", request.GetArguments())
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, err
+		}
+		name = models.NormalizeName(name)
+		slug := models.SlugFromName(name)
+		if err := models.ValidateSlug(slug); err != nil {
+			return nil, fmt.Errorf("application name '%s' does not give a valid slug: %w", name, err)
+		}
+
+		list, err := s.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+		for i := range list.Applications {
+			if list.Applications[i].Slug == slug {
+				return nil, fmt.Errorf("application '%s' (%s) already uses the slug '%s'",
+					list.Applications[i].Name, list.Applications[i].ID, slug)
+			}
+		}
+
+		if s.dryRun(request) {
+			return dryRunResult(ctx, fmt.Sprintf("create application '%s'", name), models.Application{Name: name, Slug: slug})
+		}
+
+		app, err := s.applications.Create(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		s.resolver.Invalidate()
+		s.searchIndexes.Invalidate()
+
+		return jsonResult(ctx, app)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineArchiveApplicationTool creates the archive_application tool definition.
+// Removes an application from the team's applications once the caller confirms.
+func (s *Server) defineArchiveApplicationTool() toolDefinition {
+	tool := mcp.NewTool("archive_application",
+		mcp.WithDescription("Archive an application, removing it from the team's applications in the Vendor "+
+			"Portal along with its channels, releases, and customers. This changes the vendor account, so it "+
+			"requires confirmation: a call without confirmation_token fails, describes the change, and returns "+
+			"a token; once the user agrees, make the same call with the token within two minutes."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		confirmationTokenParameter(),
+		dryRunParameter(),
+		outputSchema[applicationArchiveResult](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("archive_application tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		app, err := s.applications.GetApplication(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := applicationArchiveResult{AppID: appID, AppSlug: app.Slug, AppName: app.Name}
+		description := fmt.Sprintf("application '%s' (%s)", app.Name, appID)
+		if s.dryRun(request) {
+			result.Archived = true
+			return dryRunResult(ctx, "archive "+description, result)
+		}
+
+		if err := s.requireConfirmation(request, "archiving "+description); err != nil {
+			return nil, err
+		}
+
+		if err := s.applications.Archive(ctx, appID); err != nil {
+			return nil, err
+		}
+		s.resolver.Invalidate()
+		s.searchIndexes.Invalidate()
+		s.adoptionData.Invalidate()
+
+		result.Archived = true
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
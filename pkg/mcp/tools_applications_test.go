@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testApplicationAPI serves the test application and records applications created and
+// archived
+type testApplicationAPI struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	changes []string
+}
+
+// newTestApplicationAPI creates a test Vendor API for the application management tools
+func newTestApplicationAPI(t *testing.T) *testApplicationAPI {
+	t.Helper()
+
+	vendorAPI := &testApplicationAPI{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/"+testAppID, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, testAppJSON)
+	})
+	mux.HandleFunc("POST /vendor/v3/app", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode create request: %v", err)
+		}
+		vendorAPI.mu.Lock()
+		vendorAPI.changes = append(vendorAPI.changes, "create "+body.Name)
+		vendorAPI.mu.Unlock()
+		fmt.Fprintf(w, `{"app": {"id": "app-new", "name": %q, "slug": "new-product"}}`, body.Name)
+	})
+	mux.HandleFunc("DELETE /vendor/v3/app/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vendorAPI.mu.Lock()
+		vendorAPI.changes = append(vendorAPI.changes, "archive "+r.PathValue("id"))
+		vendorAPI.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestApplicationManagementTools(t *testing.T) {
+	vendorAPI := newTestApplicationAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tests := []struct {
+		name            string
+		toolName        string
+		args            map[string]any
+		confirm         bool
+		expectConfirm   bool
+		expectError     bool
+		expectInText    string
+		expectAPIChange string
+	}{
+		{
+			name:            "create an application",
+			toolName:        "create_application",
+			args:            map[string]any{"name": " New  Product "},
+			expectInText:    `"slug": "new-product"`,
+			expectAPIChange: "create New Product",
+		},
+		{
+			name:         "preview an application",
+			toolName:     "create_application",
+			args:         map[string]any{"name": "New Product", "dry_run": true},
+			expectInText: `"would create application 'New Product'"`,
+		},
+		{
+			name:        "name without a valid slug",
+			toolName:    "create_application",
+			args:        map[string]any{"name": "!!!"},
+			expectError: true,
+		},
+		{
+			name:        "slug already in use",
+			toolName:    "create_application",
+			args:        map[string]any{"name": "Test App"},
+			expectError: true,
+		},
+		{
+			name:          "archive without confirmation",
+			toolName:      "archive_application",
+			args:          map[string]any{"app_id": testAppSlug},
+			expectConfirm: true,
+		},
+		{
+			name:            "archive confirmed",
+			toolName:        "archive_application",
+			args:            map[string]any{"app_id": testAppSlug},
+			confirm:         true,
+			expectInText:    `"archived": true`,
+			expectAPIChange: "archive " + testAppID,
+		},
+		{
+			name:        "unknown application",
+			toolName:    "archive_application",
+			args:        map[string]any{"app_id": "missing"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.changes = nil
+			vendorAPI.mu.Unlock()
+
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+
+			var result *mcp.CallToolResult
+			var err error
+			if tt.confirm {
+				result, err = callConfirmed(t, tool, tt.args)
+			} else {
+				result, err = tool.handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			}
+			switch {
+			case tt.expectConfirm:
+				var confirmErr *confirmationError
+				if !errors.As(err, &confirmErr) {
+					t.Fatalf("Expected a confirmation error, got %v", err)
+				}
+			case tt.expectError:
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			case err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case !contains(resultText(t, result), tt.expectInText):
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, resultText(t, result))
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			var want []string
+			if tt.expectAPIChange != "" {
+				want = []string{tt.expectAPIChange}
+			}
+			if !slices.Equal(vendorAPI.changes, want) {
+				t.Errorf("Expected API changes %v, got %v", want, vendorAPI.changes)
+			}
+		})
+	}
+}
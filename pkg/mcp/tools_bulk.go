@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// Bulk operation limits
+const (
+	defaultBulkConcurrency = 4
+	maxBulkConcurrency     = 10
+	maxBulkOperations      = 100
+)
+
+// bulkToolName is the name of the bulk tool, which cannot run itself
+const bulkToolName = "bulk"
+
+// Bulk Tools
+
+// bulkOperation is one tool call in a bulk request
+type bulkOperation struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// bulkItemResult reports the outcome of one operation, in request order. Result holds the
+// tool's response envelope when it succeeded; Error holds the same payload a failed tool call returns.
+type bulkItemResult struct {
+	Index   int        `json:"index"`
+	Tool    string     `json:"tool"`
+	Success bool       `json:"success"`
+	Result  any        `json:"result,omitempty"`
+	Error   *toolError `json:"error,omitempty"`
+}
+
+// bulkResult summarizes a bulk call
+type bulkResult struct {
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []bulkItemResult `json:"results"`
+}
+
+// defineBulkTool creates the bulk tool definition.
+// Runs many tool calls at once with bounded concurrency, so agents can make the same change
+// to many entities (such as extending 50 trials) without a round trip per call.
+func (s *Server) defineBulkTool() toolDefinition {
+	tool := mcp.NewTool(bulkToolName,
+		mcp.WithDescription(fmt.Sprintf("Run up to %d tool calls in one request, several at a time, and report "+
+			"each call's success or failure. Use it to apply the same change to many entities, such as extending "+
+			"the expiration of 50 customers with update_customer. Each operation names a tool and its arguments, "+
+			"exactly as if the tool were called directly; tools that require confirmation need a "+
+			"confirmation_token in each operation's arguments, which each operation's error returns on a first "+
+			"run. One operation failing does not stop the others. With dry_run, every operation previews its "+
+			"change instead of making it, so a plan can be checked first.", maxBulkOperations)),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithArray("operations",
+			mcp.Required(),
+			mcp.Description("The tool calls to run"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"tool": map[string]any{
+						"type":        "string",
+						"description": "The name of the tool to call",
+					},
+					"arguments": map[string]any{
+						"type":        "object",
+						"description": "The tool's arguments",
+					},
+				},
+				"required": []string{"tool"},
+			}),
+		),
+		mcp.WithNumber("concurrency",
+			mcp.Description(fmt.Sprintf("How many operations to run at once (default %d)", defaultBulkConcurrency)),
+			mcp.Min(1),
+			mcp.Max(maxBulkConcurrency),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Set dry_run on every operation, so tools that change the vendor account only "+
+				"describe the change they would make"),
+		),
+		outputSchema[bulkResult](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("bulk tool called", "arguments", request.GetArguments())
+
+		operations, err := bulkOperations(request)
+		if err != nil {
+			return nil, err
+		}
+
+		concurrency := request.GetInt("concurrency", defaultBulkConcurrency)
+		if concurrency < 1 || concurrency > maxBulkConcurrency {
+			return nil, fmt.Errorf("concurrency must be between 1 and %d", maxBulkConcurrency)
+		}
+
+		if request.GetBool("dry_run", false) {
+			operations = dryRunOperations(operations)
+		}
+
+		return jsonResult(ctx, s.runBulk(ctx, operations, concurrency))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, timeout: bulkCallTimeout}
+}
+
+// bulkCallTimeout lifts the time limit from bulk calls, whose operations each run under
+// their own tool's limit instead
+func bulkCallTimeout(mcp.CallToolRequest, time.Duration) time.Duration {
+	return 0
+}
+
+// bulkOperations decodes and checks the operations of a bulk request
+func bulkOperations(request mcp.CallToolRequest) ([]bulkOperation, error) {
+	raw, ok := request.GetArguments()["operations"]
+	if !ok {
+		return nil, fmt.Errorf("operations is required")
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operations: %w", err)
+	}
+	var operations []bulkOperation
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return nil, fmt.Errorf("operations must be a list of objects with a tool and its arguments: %w", err)
+	}
+
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("at least one operation is required")
+	}
+	if len(operations) > maxBulkOperations {
+		return nil, fmt.Errorf("at most %d operations can be run at once, got %d; split them into several calls",
+			maxBulkOperations, len(operations))
+	}
+
+	return operations, nil
+}
+
+// dryRunOperations returns copies of bulk operations with dry_run set in their arguments
+func dryRunOperations(operations []bulkOperation) []bulkOperation {
+	previews := make([]bulkOperation, len(operations))
+	for i, operation := range operations {
+		arguments := make(map[string]any, len(operation.Arguments)+1)
+		for name, value := range operation.Arguments {
+			arguments[name] = value
+		}
+		arguments["dry_run"] = true
+		previews[i] = bulkOperation{Tool: operation.Tool, Arguments: arguments}
+	}
+	return previews
+}
+
+// runBulk runs the operations with at most concurrency running at once and collects their
+// results in request order
+func (s *Server) runBulk(ctx context.Context, operations []bulkOperation, concurrency int) bulkResult {
+	results := make([]bulkItemResult, len(operations))
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, operation := range operations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results[i] = s.runBulkOperation(ctx, i, operation)
+		}()
+	}
+	wg.Wait()
+
+	summary := bulkResult{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.Success {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	s.logger.WithContext(ctx).Info("bulk tool finished",
+		"total", summary.Total,
+		"succeeded", summary.Succeeded,
+		"failed", summary.Failed)
+
+	return summary
+}
+
+// runBulkOperation runs one operation through the named tool's handler. Tools the server does
+// not expose, such as mutating tools in read-only mode, fail as if they did not exist.
+func (s *Server) runBulkOperation(ctx context.Context, index int, operation bulkOperation) bulkItemResult {
+	item := bulkItemResult{Index: index, Tool: operation.Tool}
+
+	fail := func(err error) bulkItemResult {
+		payload := describeError(err)
+		payload.RequestID = logging.RequestIDFromContext(ctx)
+		item.Error = &payload
+		return item
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fail(err)
+	}
+
+	tool, ok := s.registry.Tool(operation.Tool)
+	if !ok || operation.Tool == bulkToolName || !s.toolPermitted(tool) {
+		return fail(fmt.Errorf("tool '%s' is not available", operation.Tool))
+	}
+
+	request := mcp.CallToolRequest{
+		Request: mcp.Request{Method: string(mcp.MethodToolsCall)},
+		Params:  mcp.CallToolParams{Name: operation.Tool, Arguments: operation.Arguments},
+	}
+	handler := s.withTimeout(tool, s.withAudit(tool, s.withArgumentLimits(withResultShaping(operation.Tool,
+		tool.handler))))
+	result, err := s.withMetrics(operation.Tool, handler)(ctx, request)
+	if err != nil {
+		return fail(err)
+	}
+
+	item.Success = !result.IsError
+	item.Result = toolOutput(result)
+	return item
+}
+
+// toolOutput returns a tool result's structured content, or its text content decoded as
+// JSON when possible
+func toolOutput(result *mcp.CallToolResult) any {
+	if result.StructuredContent != nil {
+		return result.StructuredContent
+	}
+
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if json.Valid([]byte(text.Text)) {
+			return json.RawMessage(text.Text)
+		}
+		return text.Text
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// callBulk runs the bulk tool and decodes its summary
+func callBulk(t *testing.T, server *Server, args map[string]any) bulkResult {
+	t.Helper()
+
+	tool, ok := server.registry.Tool("bulk")
+	if !ok {
+		t.Fatal("Tool 'bulk' not found")
+	}
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("bulk", args))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var summary bulkResult
+	if err := json.Unmarshal(resultData(t, result), &summary); err != nil {
+		t.Fatalf("Failed to decode bulk result: %v", err)
+	}
+	return summary
+}
+
+func TestBulkTool(t *testing.T) {
+	vendorAPI := newTestUpdateAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	summary := callBulk(t, server, map[string]any{
+		"operations": []any{
+			map[string]any{"tool": "update_customer", "arguments": map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "expires_at": "2026-03-31",
+			}},
+			map[string]any{"tool": "update_customer", "arguments": map[string]any{
+				"app_id": testAppID, "customer_id": "customer-9", "expires_at": "2026-03-31",
+			}},
+			map[string]any{"tool": "archive_customer", "arguments": map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1",
+			}},
+			map[string]any{"tool": "no_such_tool"},
+			map[string]any{"tool": "bulk", "arguments": map[string]any{"operations": []any{}}},
+		},
+	})
+
+	if summary.Total != 5 || summary.Succeeded != 1 || summary.Failed != 4 {
+		t.Fatalf("Expected 1 of 5 operations to succeed, got %+v", summary)
+	}
+
+	tests := []struct {
+		index       int
+		tool        string
+		success     bool
+		errContains string
+		errKind     string
+	}{
+		{index: 0, tool: "update_customer", success: true},
+		{index: 1, tool: "update_customer", errContains: "customer 'customer-9' not found"},
+		{index: 2, tool: "archive_customer", errKind: confirmationRequiredKind},
+		{index: 3, tool: "no_such_tool", errContains: "tool 'no_such_tool' is not available"},
+		{index: 4, tool: "bulk", errContains: "tool 'bulk' is not available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s", tt.index, tt.tool), func(t *testing.T) {
+			item := summary.Results[tt.index]
+			if item.Index != tt.index || item.Tool != tt.tool || item.Success != tt.success {
+				t.Fatalf("Unexpected result %+v", item)
+			}
+			if tt.success {
+				if item.Error != nil || item.Result == nil {
+					t.Errorf("Expected a result and no error, got %+v", item)
+				}
+				return
+			}
+			if item.Error == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.errContains != "" && !contains(item.Error.Error, tt.errContains) {
+				t.Errorf("Expected error containing '%s', got '%s'", tt.errContains, item.Error.Error)
+			}
+			if tt.errKind != "" && item.Error.Kind != tt.errKind {
+				t.Errorf("Expected error kind '%s', got '%s'", tt.errKind, item.Error.Kind)
+			}
+		})
+	}
+
+	vendorAPI.mu.Lock()
+	defer vendorAPI.mu.Unlock()
+	if len(vendorAPI.updates) != 1 {
+		t.Errorf("Expected 1 customer update, got %d", len(vendorAPI.updates))
+	}
+}
+
+// concurrentApplications lists applications slowly, tracking how many lists run at once
+type concurrentApplications struct {
+	applicationsService
+	running, peak atomic.Int32
+}
+
+func (c *concurrentApplications) ListApplications(
+	context.Context, *api.ListApplicationsOptions,
+) (*api.ApplicationList, error) {
+	current := c.running.Add(1)
+	defer c.running.Add(-1)
+	for {
+		highest := c.peak.Load()
+		if current <= highest || c.peak.CompareAndSwap(highest, current) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return &api.ApplicationList{}, nil
+}
+
+func TestBulkToolConcurrency(t *testing.T) {
+	applications := &concurrentApplications{}
+	server, err := NewServerWithServices(&config.Config{LogLevel: "info", DataDir: t.TempDir()},
+		logging.NewLogger("info"), &vendorapi.Services{Client: fakeClient{}, Applications: applications})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	operations := make([]any, 12)
+	for i := range operations {
+		operations[i] = map[string]any{"tool": "list_applications"}
+	}
+
+	summary := callBulk(t, server, map[string]any{"operations": operations, "concurrency": 3})
+	if summary.Succeeded != len(operations) {
+		t.Fatalf("Expected every operation to succeed, got %+v", summary)
+	}
+	peak := applications.peak.Load()
+	if peak > 3 {
+		t.Errorf("Expected at most 3 operations at once, got %d", peak)
+	}
+	if peak < 2 {
+		t.Errorf("Expected operations to run concurrently, got a peak of %d", peak)
+	}
+}
+
+func TestBulkToolReadOnly(t *testing.T) {
+	server := newTestServer(t, "")
+
+	readOnly := *server.currentConfig()
+	readOnly.ReadOnly = true
+	if err := server.Reconfigure(&readOnly); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	summary := callBulk(t, server, map[string]any{
+		"operations": []any{
+			map[string]any{"tool": "update_customer", "arguments": map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "name": "Acme Corp",
+			}},
+		},
+	})
+	if summary.Failed != 1 || !contains(summary.Results[0].Error.Error, "is not available") {
+		t.Errorf("Expected mutating tools to be unavailable in read-only mode, got %+v", summary)
+	}
+}
+
+func TestBulkToolConfirmation(t *testing.T) {
+	server := newTestServer(t, newTestClusterAPI(t).URL)
+	operation := func(arguments map[string]any) map[string]any {
+		return map[string]any{"operations": []any{map[string]any{"tool": "delete_cluster", "arguments": arguments}}}
+	}
+
+	summary := callBulk(t, server, operation(map[string]any{"cluster_id": "cluster-1"}))
+	if summary.Failed != 1 || summary.Results[0].Error.Kind != confirmationRequiredKind {
+		t.Fatalf("Expected the deletion to need confirmation, got %+v", summary)
+	}
+
+	token := summary.Results[0].Error.ConfirmationToken
+	summary = callBulk(t, server, operation(map[string]any{"cluster_id": "cluster-1", "confirmation_token": token}))
+	if summary.Succeeded != 1 {
+		t.Errorf("Expected the confirmed deletion to succeed, got %+v", summary.Results[0].Error)
+	}
+}
+
+func TestBulkToolInvalidRequests(t *testing.T) {
+	server := newTestServer(t, "")
+
+	tooMany := make([]any, maxBulkOperations+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"tool": "get_server_info"}
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		errContains string
+	}{
+		{name: "missing operations", args: map[string]any{}, errContains: "operations is required"},
+		{name: "no operations", args: map[string]any{"operations": []any{}}, errContains: "at least one"},
+		{name: "too many operations", args: map[string]any{"operations": tooMany}, errContains: "at most 100"},
+		{
+			name:        "malformed operations",
+			args:        map[string]any{"operations": []any{"get_server_info"}},
+			errContains: "must be a list of objects",
+		},
+		{
+			name: "concurrency out of range",
+			args: map[string]any{
+				"operations": []any{map[string]any{"tool": "get_server_info"}}, "concurrency": 50,
+			},
+			errContains: "concurrency must be between 1 and 10",
+		},
+	}
+
+	tool, ok := server.registry.Tool("bulk")
+	if !ok {
+		t.Fatal("Tool 'bulk' not found")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tool.handler(context.Background(), createMockCallToolRequest("bulk", tt.args))
+			if err == nil || !contains(err.Error(), tt.errContains) {
+				t.Errorf("Expected error containing '%s', got %v", tt.errContains, err)
+			}
+		})
+	}
+}
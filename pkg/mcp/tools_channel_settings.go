@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// channelSettingArguments are the arguments of update_channel_settings that each change one
+// setting
+var channelSettingArguments = []string{
+	"is_default", "airgap_enabled", "semver_required", "enforce_version_label", "release_notes_required",
+}
+
+// channelSettings reports a channel's settings
+type channelSettings struct {
+	AppID       string `json:"app_id"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	models.ChannelSettings
+}
+
+// channelSettingsChange reports a channel's settings after an update_channel_settings call,
+// and whether they changed
+type channelSettingsChange struct {
+	channelSettings
+	Changed bool `json:"changed"`
+}
+
+// Channel Settings Tools
+
+// defineGetChannelSettingsTool creates the get_channel_settings tool definition.
+// Reports whether a channel is the default, builds air gap bundles, and what promotions must give.
+func (s *Server) defineGetChannelSettingsTool() toolDefinition {
+	tool := mcp.NewTool("get_channel_settings",
+		mcp.WithDescription("Get a channel's settings: whether it is the application's default channel, which "+
+			"new customers are assigned to (is_default); whether air gap bundles are built for its releases "+
+			"(airgap_enabled); whether releases promoted to it must have semantic version labels "+
+			"(semver_required); and whether promotions must give a version label (enforce_version_label) "+
+			"and release notes (release_notes_required)."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("The ID, slug, or name of the channel"),
+		),
+		outputSchema[channelSettings](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_channel_settings tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		channelID, err := request.RequireString("channel_id")
+		if err != nil {
+			return nil, err
+		}
+
+		channel, err := s.findChannel(ctx, appID, channelID)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, channelSettings{
+			AppID:           appID,
+			ChannelID:       channel.ID,
+			ChannelName:     channel.Name,
+			ChannelSettings: channel.Settings(),
+		})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineUpdateChannelSettingsTool creates the update_channel_settings tool definition.
+// Changes the given settings of a channel, keeping the others.
+func (s *Server) defineUpdateChannelSettingsTool() toolDefinition {
+	tool := mcp.NewTool("update_channel_settings",
+		mcp.WithDescription("Change a channel's settings: make it the application's default channel, build air "+
+			"gap bundles for its releases, or require promotions to it to have semantic version labels, a "+
+			"version label, or release notes. Only the given settings change; when nothing would change, the "+
+			"settings are returned without updating the channel. A channel stops being the default only when "+
+			"another channel is made the default. Making a channel the default requires confirmation: a call "+
+			"without confirmation_token fails, describes the change, and returns a token; once the user agrees, "+
+			"make the same call with the token within two minutes. With dry_run, the settings are previewed "+
+			"without saving them."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("The ID, slug, or name of the channel"),
+		),
+		mcp.WithBoolean("is_default",
+			mcp.Description("Make the channel the application's default channel; only true is accepted"),
+		),
+		mcp.WithBoolean("airgap_enabled",
+			mcp.Description("Build air gap bundles for releases promoted to the channel"),
+		),
+		mcp.WithBoolean("semver_required",
+			mcp.Description("Require releases promoted to the channel to have semantic version labels"),
+		),
+		mcp.WithBoolean("enforce_version_label",
+			mcp.Description("Reject promotions to the channel that do not give a version label"),
+		),
+		mcp.WithBoolean("release_notes_required",
+			mcp.Description("Reject promotions to the channel that do not give release notes"),
+		),
+		confirmationTokenParameter(),
+		dryRunParameter(),
+		outputSchema[channelSettingsChange](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("update_channel_settings tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		channelID, err := request.RequireString("channel_id")
+		if err != nil {
+			return nil, err
+		}
+
+		arguments := request.GetArguments()
+		given := false
+		for _, name := range channelSettingArguments {
+			if _, ok := arguments[name]; ok {
+				given = true
+				break
+			}
+		}
+		if !given {
+			return nil, fmt.Errorf("at least one of is_default, airgap_enabled, semver_required, " +
+				"enforce_version_label, or release_notes_required is required")
+		}
+
+		return s.updateChannelSettings(ctx, appID, channelID, request)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// updateChannelSettings changes the settings of a channel given in the request, keeping the
+// others. Making a channel the default requires confirmation, and a default channel is never
+// unset, since an application always has one.
+func (s *Server) updateChannelSettings(
+	ctx context.Context, appID, channelID string, request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	channel, err := s.findChannel(ctx, appID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := channel.Settings()
+	settings := models.ChannelSettings{
+		IsDefault:     request.GetBool("is_default", current.IsDefault),
+		AirgapEnabled: request.GetBool("airgap_enabled", current.AirgapEnabled),
+		ChannelVersioning: models.ChannelVersioning{
+			SemverRequired:      request.GetBool("semver_required", current.SemverRequired),
+			EnforceVersionLabel: request.GetBool("enforce_version_label", current.EnforceVersionLabel),
+		},
+		ReleaseNotesRequired: request.GetBool("release_notes_required", current.ReleaseNotesRequired),
+	}
+	if current.IsDefault && !settings.IsDefault {
+		return nil, fmt.Errorf("channel '%s' stops being the default only when another channel is made "+
+			"the default", channel.Name)
+	}
+
+	result := channelSettingsChange{
+		channelSettings: channelSettings{
+			AppID:           appID,
+			ChannelID:       channel.ID,
+			ChannelName:     channel.Name,
+			ChannelSettings: settings,
+		},
+		Changed: settings != current,
+	}
+	if !result.Changed {
+		return jsonResult(ctx, result)
+	}
+	if s.dryRun(request) {
+		return dryRunResult(ctx, fmt.Sprintf("update the settings of channel '%s' (%s)",
+			channel.Name, channel.ID), result)
+	}
+
+	if settings.IsDefault && !current.IsDefault {
+		action := fmt.Sprintf("making channel '%s' the default channel", channel.Name)
+		if err := s.requireConfirmation(request, action); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.channels.UpdateSettings(ctx, appID, channel, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ChannelSettings = updated.Settings()
+	return jsonResult(ctx, result)
+}
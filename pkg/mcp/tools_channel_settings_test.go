@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetChannelSettingsTool(t *testing.T) {
+	server := newTestServer(t, newTestVersioningAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_channel_settings")
+	if !ok {
+		t.Fatal("Tool 'get_channel_settings' not found")
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		expectError  bool
+		expectInText []string
+	}{
+		{
+			name: "default channel",
+			args: map[string]any{"app_id": testAppID, "channel_id": "stable"},
+			expectInText: []string{
+				`"isDefault": true`, `"airgapEnabled": true`, `"semverRequired": true`,
+				`"releaseNotesRequired": false`,
+			},
+		},
+		{
+			name:         "other channel",
+			args:         map[string]any{"app_id": testAppSlug, "channel_id": "Beta"},
+			expectInText: []string{`"channel_id": "channel-beta"`, `"isDefault": false`, `"airgapEnabled": false`},
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "lts"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_channel_settings", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, want := range tt.expectInText {
+				if !contains(resultText(t, result), want) {
+					t.Errorf("Expected response containing '%s', got '%s'", want, resultText(t, result))
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateChannelSettingsTool(t *testing.T) {
+	vendorAPI := newTestVersioningAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("update_channel_settings")
+	if !ok {
+		t.Fatal("Tool 'update_channel_settings' not found")
+	}
+
+	tests := []struct {
+		name               string
+		args               map[string]any
+		expectError        bool
+		expectConfirmation bool
+		expectInText       string
+		expectUpdate       map[string]any
+	}{
+		{
+			name: "require release notes and air gap bundles",
+			args: map[string]any{
+				"app_id": testAppID, "channel_id": "beta", "release_notes_required": true, "airgap_enabled": true,
+			},
+			expectInText: `"changed": true`,
+			expectUpdate: map[string]any{
+				"name": "Beta", "description": "Previews", "isDefault": false, "airgapEnabled": true,
+				"semverRequired": false, "enforceVersionLabel": false, "releaseNotesRequired": true,
+			},
+		},
+		{
+			name:         "settings already in place",
+			args:         map[string]any{"app_id": testAppID, "channel_id": "stable", "airgap_enabled": true},
+			expectInText: `"changed": false`,
+		},
+		{
+			name: "preview making a channel the default",
+			args: map[string]any{
+				"app_id": testAppID, "channel_id": "beta", "is_default": true, "dry_run": true,
+			},
+			expectInText: `"isDefault": true`,
+		},
+		{
+			name:               "making a channel the default needs confirmation",
+			args:               map[string]any{"app_id": testAppID, "channel_id": "beta", "is_default": true},
+			expectError:        true,
+			expectConfirmation: true,
+		},
+		{
+			name:        "default channel cannot be unset",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "stable", "is_default": false},
+			expectError: true,
+		},
+		{
+			name:        "no settings",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "stable"},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "lts", "airgap_enabled": true},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.updates = nil
+			vendorAPI.mu.Unlock()
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("update_channel_settings", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				var confirmation *confirmationError
+				if errors.As(err, &confirmation) != tt.expectConfirmation {
+					t.Errorf("Expected a confirmation error = %t, got %v", tt.expectConfirmation, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !contains(resultText(t, result), tt.expectInText) {
+					t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, resultText(t, result))
+				}
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			var want []map[string]any
+			if tt.expectUpdate != nil {
+				want = []map[string]any{tt.expectUpdate}
+			}
+			if !reflect.DeepEqual(vendorAPI.updates, want) {
+				t.Errorf("Expected API updates %v, got %v", want, vendorAPI.updates)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// channelVersioning reports a channel's semantic versioning settings. NonSemverLabels lists
+// the version labels in the channel's history that are not semantic versions, which
+// requiring semantic versions would not have allowed.
+type channelVersioning struct {
+	AppID       string `json:"app_id"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	models.ChannelVersioning
+	LatestVersionLabel string   `json:"latest_version_label,omitempty"`
+	NonSemverLabels    []string `json:"non_semver_labels"`
+}
+
+// channelVersioningChange reports a channel's semantic versioning settings after a
+// set_channel_versioning call, and whether they changed
+type channelVersioningChange struct {
+	AppID       string `json:"app_id"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	models.ChannelVersioning
+	Changed bool `json:"changed"`
+}
+
+// Channel Versioning Tools
+
+// defineGetChannelVersioningTool creates the get_channel_versioning tool definition.
+// Reports whether a channel requires semantic versions and version labels.
+func (s *Server) defineGetChannelVersioningTool() toolDefinition {
+	tool := mcp.NewTool("get_channel_versioning",
+		mcp.WithDescription("Get a channel's semantic versioning settings: whether releases promoted to it "+
+			"must have semantic version labels (semver_required) and whether promotions must give a version "+
+			"label (enforce_version_label). Also lists the labels in the channel's history that are not "+
+			"semantic versions, to check before requiring them."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("The ID, slug, or name of the channel"),
+		),
+		outputSchema[channelVersioning](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_channel_versioning tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		channelID, err := request.RequireString("channel_id")
+		if err != nil {
+			return nil, err
+		}
+
+		channel, err := s.findChannel(ctx, appID, channelID)
+		if err != nil {
+			return nil, err
+		}
+
+		history, err := s.channels.ListChannelReleases(ctx, appID, channel.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases of channel %s: %w", channel.ID, err)
+		}
+
+		result := channelVersioning{
+			AppID:              appID,
+			ChannelID:          channel.ID,
+			ChannelName:        channel.Name,
+			ChannelVersioning:  channel.Versioning(),
+			LatestVersionLabel: latestPromotionLabel(history.Releases),
+			NonSemverLabels:    []string{},
+		}
+		for _, promotion := range history.Releases {
+			if !models.IsSemanticVersion(promotion.VersionLabel) {
+				result.NonSemverLabels = append(result.NonSemverLabels, promotion.VersionLabel)
+			}
+		}
+
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineSetChannelVersioningTool creates the set_channel_versioning tool definition.
+// Turns a channel's semantic versioning requirements on or off.
+func (s *Server) defineSetChannelVersioningTool() toolDefinition {
+	tool := mcp.NewTool("set_channel_versioning",
+		mcp.WithDescription("Turn a channel's semantic versioning settings on or off: whether releases "+
+			"promoted to it must have semantic version labels, and whether promotions must give a version "+
+			"label. Only the given settings change; when nothing would change, the settings are returned "+
+			"without updating the channel. With dry_run, the settings are previewed without saving them."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("The ID, slug, or name of the channel"),
+		),
+		mcp.WithBoolean("semver_required",
+			mcp.Description("Require releases promoted to the channel to have semantic version labels"),
+		),
+		mcp.WithBoolean("enforce_version_label",
+			mcp.Description("Reject promotions to the channel that do not give a version label"),
+		),
+		dryRunParameter(),
+		outputSchema[channelVersioningChange](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("set_channel_versioning tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		channelID, err := request.RequireString("channel_id")
+		if err != nil {
+			return nil, err
+		}
+
+		arguments := request.GetArguments()
+		_, setSemver := arguments["semver_required"]
+		_, setLabel := arguments["enforce_version_label"]
+		if !setSemver && !setLabel {
+			return nil, fmt.Errorf("at least one of semver_required or enforce_version_label is required")
+		}
+
+		channel, err := s.findChannel(ctx, appID, channelID)
+		if err != nil {
+			return nil, err
+		}
+
+		versioning := channel.Versioning()
+		versioning.SemverRequired = request.GetBool("semver_required", versioning.SemverRequired)
+		versioning.EnforceVersionLabel = request.GetBool("enforce_version_label", versioning.EnforceVersionLabel)
+
+		result := channelVersioningChange{
+			AppID:             appID,
+			ChannelID:         channel.ID,
+			ChannelName:       channel.Name,
+			ChannelVersioning: versioning,
+			Changed:           versioning != channel.Versioning(),
+		}
+		if !result.Changed {
+			return jsonResult(ctx, result)
+		}
+		if s.dryRun(request) {
+			return dryRunResult(ctx, fmt.Sprintf("update the versioning settings of channel '%s' (%s)",
+				channel.Name, channel.ID), result)
+		}
+
+		updated, err := s.channels.UpdateVersioning(ctx, appID, channel, versioning)
+		if err != nil {
+			return nil, err
+		}
+
+		result.ChannelVersioning = updated.Versioning()
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// latestPromotionLabel returns the version label of the latest promotion in a channel's
+// history, by channel sequence
+func latestPromotionLabel(history []models.ChannelRelease) string {
+	var latest models.ChannelRelease
+	for _, promotion := range history {
+		if promotion.ChannelSequence > latest.ChannelSequence {
+			latest = promotion
+		}
+	}
+	return latest.VersionLabel
+}
@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// testVersioningAPI serves channels with different versioning settings and their histories,
+// recording channel updates
+type testVersioningAPI struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	updates []map[string]any
+}
+
+// newTestVersioningAPI creates a test Vendor API for the channel versioning and settings tools
+func newTestVersioningAPI(t *testing.T) *testVersioningAPI {
+	t.Helper()
+
+	vendorAPI := &testVersioningAPI{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [
+			{"id": "channel-stable", "name": "Stable", "channelSlug": "stable", "isDefault": true,
+				"airgapEnabled": true, "semverRequired": true},
+			{"id": "channel-beta", "name": "Beta", "channelSlug": "beta", "description": "Previews"}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/"+testAppID+"/channel/{channel}/releases",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.PathValue("channel") != "channel-beta" {
+				fmt.Fprint(w, `{"releases": [{"channelSequence": 1, "releaseSequence": 1, "versionLabel": "1.0.0"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"releases": [
+				{"channelSequence": 2, "releaseSequence": 3, "versionLabel": "1.1.0-beta.1"},
+				{"channelSequence": 1, "releaseSequence": 2, "versionLabel": "nightly-42"}
+			]}`)
+		})
+	mux.HandleFunc("PUT /vendor/v3/app/"+testAppID+"/channel/{channel}", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode update request: %v", err)
+		}
+		vendorAPI.mu.Lock()
+		vendorAPI.updates = append(vendorAPI.updates, body)
+		vendorAPI.mu.Unlock()
+
+		channel := maps.Clone(body)
+		channel["id"] = r.PathValue("channel")
+		if err := json.NewEncoder(w).Encode(map[string]any{"channel": channel}); err != nil {
+			t.Errorf("Failed to encode update response: %v", err)
+		}
+	})
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestGetChannelVersioningTool(t *testing.T) {
+	server := newTestServer(t, newTestVersioningAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_channel_versioning")
+	if !ok {
+		t.Fatal("Tool 'get_channel_versioning' not found")
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		expectError  bool
+		expectInText []string
+	}{
+		{
+			name:         "channel requiring semantic versions",
+			args:         map[string]any{"app_id": testAppID, "channel_id": "Stable"},
+			expectInText: []string{`"semverRequired": true`, `"latest_version_label": "1.0.0"`, `"non_semver_labels": []`},
+		},
+		{
+			name: "channel with labels that are not semantic versions",
+			args: map[string]any{"app_id": testAppSlug, "channel_id": "channel-beta"},
+			expectInText: []string{
+				`"semverRequired": false`, `"latest_version_label": "1.1.0-beta.1"`, `"nightly-42"`,
+			},
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "lts"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_channel_versioning", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, want := range tt.expectInText {
+				if !contains(resultText(t, result), want) {
+					t.Errorf("Expected response containing '%s', got '%s'", want, resultText(t, result))
+				}
+			}
+		})
+	}
+}
+
+func TestSetChannelVersioningTool(t *testing.T) {
+	vendorAPI := newTestVersioningAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("set_channel_versioning")
+	if !ok {
+		t.Fatal("Tool 'set_channel_versioning' not found")
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		expectError  bool
+		expectInText string
+		expectUpdate map[string]any
+	}{
+		{
+			name:         "require semantic versions",
+			args:         map[string]any{"app_id": testAppID, "channel_id": "beta", "semver_required": true},
+			expectInText: `"changed": true`,
+			expectUpdate: map[string]any{
+				"name": "Beta", "description": "Previews", "isDefault": false, "airgapEnabled": false,
+				"semverRequired": true, "enforceVersionLabel": false, "releaseNotesRequired": false,
+			},
+		},
+		{
+			name:         "setting already in place",
+			args:         map[string]any{"app_id": testAppID, "channel_id": "stable", "semver_required": true},
+			expectInText: `"changed": false`,
+		},
+		{
+			name: "preview enforcing version labels",
+			args: map[string]any{
+				"app_id": testAppID, "channel_id": "stable", "enforce_version_label": true, "dry_run": true,
+			},
+			expectInText: `"enforceVersionLabel": true`,
+		},
+		{
+			name:        "no settings",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "stable"},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "lts", "semver_required": false},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.updates = nil
+			vendorAPI.mu.Unlock()
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("set_channel_versioning", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !contains(resultText(t, result), tt.expectInText) {
+					t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, resultText(t, result))
+				}
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			var want []map[string]any
+			if tt.expectUpdate != nil {
+				want = []map[string]any{tt.expectUpdate}
+			}
+			if !reflect.DeepEqual(vendorAPI.updates, want) {
+				t.Errorf("Expected API updates %v, got %v", want, vendorAPI.updates)
+			}
+		})
+	}
+}
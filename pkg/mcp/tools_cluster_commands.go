@@ -0,0 +1,395 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// runClusterCommandToolName is the name of the tool that runs kubectl against clusters
+const runClusterCommandToolName = "run_cluster_command"
+
+// kubectlCommand is the program run_cluster_command runs, found on the server's PATH
+const kubectlCommand = "kubectl"
+
+// Limits on cluster commands
+const (
+	defaultClusterCommandTimeout = 2 * time.Minute
+	maxClusterCommandTimeout     = 10 * time.Minute
+	maxClusterCommandOutput      = 1 << 20
+	kubeconfigFileMode           = 0o600
+
+	// clusterCommandTimeoutMargin is the time a call is given beyond its command's timeout,
+	// to look up the cluster and its kubeconfig and to return the output
+	clusterCommandTimeoutMargin = 30 * time.Second
+
+	// clusterCommandWaitDelay bounds how long a stopped command's output is read, in case
+	// processes it started keep it open
+	clusterCommandWaitDelay = time.Second
+
+	// timedOutExitCode is reported for commands stopped at their timeout
+	timedOutExitCode = -1
+)
+
+// clusterCommandVerbs are the kubectl commands run_cluster_command allows, all of which only
+// read from the cluster. Commands with subcommands list the ones allowed; nil allows any.
+var clusterCommandVerbs = map[string][]string{
+	"api-resources": nil,
+	"api-versions":  nil,
+	"cluster-info":  nil,
+	"describe":      nil,
+	"events":        nil,
+	"explain":       nil,
+	"get":           nil,
+	"logs":          nil,
+	"rollout":       {"history", "status"},
+	"top":           nil,
+	"version":       nil,
+	"wait":          nil,
+}
+
+// forbiddenClusterCommandFlags are kubectl flags that could point a command at another cluster
+// or identity, read or write local files, or run without end
+var forbiddenClusterCommandFlags = []string{
+	"as", "as-group", "as-uid", "cache-dir", "certificate-authority", "client-certificate", "client-key",
+	"cluster", "context", "filename", "follow", "insecure-skip-tls-verify", "kubeconfig", "kustomize",
+	"log-dir", "log-file", "output-directory", "password", "profile", "profile-output", "server", "template",
+	"tls-server-name", "token", "user", "username", "watch", "watch-only",
+}
+
+// Shorthand flags: the forbidden ones stand for --server, --filename or --follow, --kustomize,
+// and --watch, and the boolean ones take no value, so more shorthands can follow them
+const (
+	forbiddenClusterCommandShorthands = "sfkw"
+	booleanClusterCommandShorthands   = "Ap"
+)
+
+// forbiddenOutputFormats read a template from a local file
+var forbiddenOutputFormats = []string{"go-template-file", "jsonpath-file"}
+
+// clusterCommandResult is the run_cluster_command result. Output combines standard output
+// and standard error, and is cut off once it reaches the output limit.
+type clusterCommandResult struct {
+	ClusterID string   `json:"cluster_id"`
+	Command   []string `json:"command"`
+	ExitCode  int      `json:"exit_code"`
+	Output    string   `json:"output"`
+	Truncated bool     `json:"truncated,omitempty"`
+	TimedOut  bool     `json:"timed_out,omitempty"`
+}
+
+// defineRunClusterCommandTool creates the run_cluster_command tool definition.
+// Runs an allow-listed, read-only kubectl command against a cluster the server created. The
+// tool is only exposed when cluster commands are enabled.
+func (s *Server) defineRunClusterCommandTool() toolDefinition {
+	tool := mcp.NewTool(runClusterCommandToolName,
+		mcp.WithDescription(fmt.Sprintf("Run a read-only kubectl command against a running Compatibility "+
+			"Matrix cluster that this server created with create_cluster, such as to check that a release "+
+			"installed and its pods are ready. Only these commands are allowed: %s (rollout only with history "+
+			"or status). Flags that change the cluster, identity, or files used are rejected, and no shell is "+
+			"involved. Output lines are sent as progress notifications when the call has a progress token.",
+			strings.Join(sortedKeys(clusterCommandVerbs), ", "))),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("cluster_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of a cluster created by this server"),
+		),
+		mcp.WithArray("args",
+			mcp.Required(),
+			mcp.Description("The kubectl arguments, starting with the command, such as "+
+				`["get", "pods", "-n", "my-app", "-o", "wide"]`),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("timeout_seconds",
+			mcp.Description(fmt.Sprintf("How long the command may run before it is stopped (default %d, at most %d)",
+				int(defaultClusterCommandTimeout.Seconds()), int(maxClusterCommandTimeout.Seconds()))),
+			mcp.Min(1),
+			mcp.Max(maxClusterCommandTimeout.Seconds()),
+		),
+		outputSchema[clusterCommandResult](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info(runClusterCommandToolName+" tool called", "arguments", request.GetArguments())
+
+		clusterID, err := request.RequireString("cluster_id")
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := request.RequireStringSlice("args")
+		if err != nil {
+			return nil, err
+		}
+		if err := validateClusterCommand(args); err != nil {
+			return nil, err
+		}
+
+		timeout := time.Duration(request.GetInt("timeout_seconds", int(defaultClusterCommandTimeout.Seconds()))) *
+			time.Second
+		if timeout <= 0 || timeout > maxClusterCommandTimeout {
+			return nil, fmt.Errorf("timeout_seconds must be between 1 and %d", int(maxClusterCommandTimeout.Seconds()))
+		}
+
+		if err := s.checkCommandCluster(ctx, clusterID); err != nil {
+			return nil, err
+		}
+
+		kubeconfig, err := s.clusters.GetKubeconfig(ctx, clusterID)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := s.runKubectl(ctx, request, kubeconfig, args, timeout)
+		if err != nil {
+			return nil, err
+		}
+		result.ClusterID = clusterID
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{
+		definition: &tool, handler: handler, runsCommands: true, timeout: clusterCommandCallTimeout,
+	}
+}
+
+// clusterCommandCallTimeout gives a run_cluster_command call the time its command may run
+// plus a margin, when that is more than the default
+func clusterCommandCallTimeout(request mcp.CallToolRequest, defaultTimeout time.Duration) time.Duration {
+	timeout := time.Duration(request.GetInt("timeout_seconds", int(defaultClusterCommandTimeout.Seconds()))) *
+		time.Second
+	return max(defaultTimeout, min(timeout, maxClusterCommandTimeout)+clusterCommandTimeoutMargin)
+}
+
+// checkCommandCluster ensures commands only run against running clusters this server created
+func (s *Server) checkCommandCluster(ctx context.Context, clusterID string) error {
+	_, created, err := s.stateStore().FindCreatedCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return fmt.Errorf("cluster '%s' was not created by this server; commands only run against clusters "+
+			"created with create_cluster", clusterID)
+	}
+
+	cluster, err := s.findCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.Status != models.ClusterStatusRunning {
+		return fmt.Errorf("cluster '%s' is %s; commands only run against running clusters", clusterID,
+			cluster.Status)
+	}
+	return nil
+}
+
+// validateClusterCommand checks kubectl arguments against the allowed commands and flags
+func validateClusterCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("args must start with a kubectl command")
+	}
+
+	subcommands, ok := clusterCommandVerbs[args[0]]
+	if !ok {
+		return fmt.Errorf("kubectl command '%s' is not allowed; allowed commands are: %s", args[0],
+			strings.Join(sortedKeys(clusterCommandVerbs), ", "))
+	}
+	if subcommands != nil && (len(args) < 2 || !slices.Contains(subcommands, args[1])) {
+		return fmt.Errorf("kubectl %s is only allowed with: %s", args[0], strings.Join(subcommands, ", "))
+	}
+
+	for i, arg := range args {
+		if err := validateClusterCommandFlag(arg); err != nil {
+			return err
+		}
+		if (arg == "-o" || arg == "--output") && i+1 < len(args) {
+			if err := validateOutputFormat(args[i+1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateClusterCommandFlag rejects a forbidden flag, in long or shorthand form
+func validateClusterCommandFlag(arg string) error {
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if slices.Contains(forbiddenClusterCommandFlags, name) {
+			return fmt.Errorf("kubectl flag '--%s' is not allowed", name)
+		}
+		if name == "output" && hasValue {
+			return validateOutputFormat(value)
+		}
+	case strings.HasPrefix(arg, "-") && len(arg) > 1:
+		// Shorthands can be combined, as in -Ap; the first one that takes a value takes the rest
+		shorthands := strings.TrimPrefix(arg, "-")
+		for i, shorthand := range shorthands {
+			if strings.ContainsRune(forbiddenClusterCommandShorthands, shorthand) {
+				return fmt.Errorf("kubectl flag '-%c' is not allowed", shorthand)
+			}
+			if strings.ContainsRune(booleanClusterCommandShorthands, shorthand) {
+				continue
+			}
+			if shorthand == 'o' {
+				return validateOutputFormat(strings.TrimPrefix(shorthands[i+1:], "="))
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// validateOutputFormat rejects output formats that read local files
+func validateOutputFormat(format string) error {
+	name, _, _ := strings.Cut(format, "=")
+	if slices.Contains(forbiddenOutputFormats, name) {
+		return fmt.Errorf("kubectl output format '%s' is not allowed", name)
+	}
+	return nil
+}
+
+// runKubectl runs kubectl with a cluster's kubeconfig, which is written to a private temporary
+// directory that also serves as kubectl's home, so the user's kubeconfig and caches are never
+// used. A command that exits with an error is reported with its exit code rather than failing.
+func (s *Server) runKubectl(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	kubeconfig string,
+	args []string,
+	timeout time.Duration,
+) (*clusterCommandResult, error) {
+	kubectl, err := exec.LookPath(kubectlCommand)
+	if err != nil {
+		return nil, fmt.Errorf("kubectl is not installed on the server: %w", err)
+	}
+
+	home, err := os.MkdirTemp("", "replicated-mcp-kubectl-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubectl directory: %w", err)
+	}
+	defer os.RemoveAll(home)
+
+	kubeconfigPath := filepath.Join(home, "kubeconfig")
+	if err := os.WriteFile(kubeconfigPath, []byte(kubeconfig), kubeconfigFileMode); err != nil {
+		return nil, fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := &commandOutput{limit: maxClusterCommandOutput, onLine: s.progressReporter(ctx, request)}
+	cmd := exec.CommandContext(ctx, kubectl, args...)
+	cmd.Env = []string{"KUBECONFIG=" + kubeconfigPath, "HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = clusterCommandWaitDelay
+
+	result := &clusterCommandResult{Command: append([]string{kubectlCommand}, args...)}
+	err = cmd.Run()
+	output.flush()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.TimedOut = true
+		result.ExitCode = timedOutExitCode
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return nil, fmt.Errorf("failed to run kubectl: %w", err)
+	}
+
+	result.Output = output.buffer.String()
+	result.Truncated = output.truncated
+	return result, nil
+}
+
+// progressReporter returns a function that sends each line of command output to the client as
+// a progress notification, or nil if the call did not ask for progress
+func (s *Server) progressReporter(ctx context.Context, request mcp.CallToolRequest) func(line string) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+
+	token := request.Params.Meta.ProgressToken
+	lines := 0
+	return func(line string) {
+		lines++
+		err := s.mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      lines,
+			"message":       line,
+		})
+		if err != nil {
+			s.logger.WithContext(ctx).Debug("Failed to send command output", "error", err)
+		}
+	}
+}
+
+// commandOutput collects a command's output up to a limit, passing each complete line to
+// onLine as it arrives
+type commandOutput struct {
+	limit     int
+	onLine    func(line string)
+	buffer    bytes.Buffer
+	pending   []byte
+	truncated bool
+}
+
+// Write implements io.Writer. Output past the limit is dropped but still reported as lines.
+func (o *commandOutput) Write(p []byte) (int, error) {
+	if remaining := o.limit - o.buffer.Len(); remaining < len(p) {
+		o.buffer.Write(p[:max(remaining, 0)])
+		o.truncated = true
+	} else {
+		o.buffer.Write(p)
+	}
+
+	if o.onLine != nil {
+		o.pending = append(o.pending, p...)
+		for {
+			line, rest, found := bytes.Cut(o.pending, []byte("\n"))
+			if !found {
+				break
+			}
+			o.onLine(string(line))
+			o.pending = rest
+		}
+		if len(o.pending) >= o.limit {
+			o.flush()
+		}
+	}
+	return len(p), nil
+}
+
+// flush passes a final line without a trailing newline to onLine
+func (o *commandOutput) flush() {
+	if o.onLine != nil && len(o.pending) > 0 {
+		o.onLine(string(o.pending))
+		o.pending = nil
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
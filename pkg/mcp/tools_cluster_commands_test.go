@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeKubectl is a kubectl stand-in that prints its arguments and the kubeconfig it was
+// given, fails "events", and outlasts any timeout on "wait"
+const fakeKubectl = `#!/bin/sh
+echo "args: $*"
+cat "$KUBECONFIG"
+echo
+case "$1" in
+events) echo "no events" >&2; exit 2 ;;
+wait) exec sleep 5 ;;
+esac
+`
+
+// installFakeKubectl puts fakeKubectl first on the PATH for the test
+func installFakeKubectl(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, kubectlCommand), []byte(fakeKubectl), 0o700); err != nil {
+		t.Fatalf("Failed to write fake kubectl: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestValidateClusterCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "get pods", args: []string{"get", "pods", "-n", "my-app", "-o", "wide"}},
+		{name: "all namespaces shorthand", args: []string{"get", "pods", "-A"}},
+		{name: "output shorthand", args: []string{"get", "deploy", "-ojson"}},
+		{name: "rollout status", args: []string{"rollout", "status", "deployment/app"}},
+		{name: "logs with tail", args: []string{"logs", "deploy/app", "--tail=50"}},
+		{name: "no command", args: nil, wantErr: "must start with a kubectl command"},
+		{name: "mutating command", args: []string{"delete", "pod", "app"}, wantErr: "'delete' is not allowed"},
+		{name: "exec", args: []string{"exec", "app", "--", "sh"}, wantErr: "'exec' is not allowed"},
+		{name: "rollout restart", args: []string{"rollout", "restart", "deploy/app"}, wantErr: "history, status"},
+		{name: "rollout alone", args: []string{"rollout"}, wantErr: "history, status"},
+		{name: "kubeconfig flag", args: []string{"get", "pods", "--kubeconfig=/tmp/other"}, wantErr: "'--kubeconfig'"},
+		{name: "server flag", args: []string{"get", "pods", "--server", "https://other"}, wantErr: "'--server'"},
+		{name: "impersonation", args: []string{"get", "secrets", "--as", "admin"}, wantErr: "'--as'"},
+		{name: "follow shorthand", args: []string{"logs", "app", "-f"}, wantErr: "'-f'"},
+		{name: "combined shorthands", args: []string{"get", "pods", "-Aw"}, wantErr: "'-w'"},
+		{name: "watch", args: []string{"get", "pods", "--watch"}, wantErr: "'--watch'"},
+		{
+			name:    "template file output",
+			args:    []string{"get", "pods", "-o", "go-template-file=/etc/passwd"},
+			wantErr: "'go-template-file'",
+		},
+		{
+			name:    "template file output flag",
+			args:    []string{"get", "pods", "--output=jsonpath-file=/etc/passwd"},
+			wantErr: "'jsonpath-file'",
+		},
+		{
+			name:    "template file output shorthand",
+			args:    []string{"get", "pods", "-ojsonpath-file=/etc/passwd"},
+			wantErr: "'jsonpath-file'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateClusterCommand(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunClusterCommandTool(t *testing.T) {
+	installFakeKubectl(t)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		created      []string
+		wantExitCode int
+		wantOutput   []string
+		wantTimedOut bool
+		expectError  string
+	}{
+		{
+			name:       "command output",
+			args:       map[string]any{"cluster_id": "cluster-1", "args": []any{"get", "pods", "-A"}},
+			created:    []string{"cluster-1"},
+			wantOutput: []string{"args: get pods -A", "apiVersion: v1"},
+		},
+		{
+			name:         "failing command",
+			args:         map[string]any{"cluster_id": "cluster-1", "args": []any{"events"}},
+			created:      []string{"cluster-1"},
+			wantExitCode: 2,
+			wantOutput:   []string{"no events"},
+		},
+		{
+			name: "timeout",
+			args: map[string]any{
+				"cluster_id": "cluster-1", "args": []any{"wait", "--for=condition=Ready", "pods", "--all"},
+				"timeout_seconds": 1,
+			},
+			created:      []string{"cluster-1"},
+			wantExitCode: timedOutExitCode,
+			wantTimedOut: true,
+		},
+		{
+			name:        "cluster not created by the server",
+			args:        map[string]any{"cluster_id": "cluster-1", "args": []any{"get", "pods"}},
+			expectError: "was not created by this server",
+		},
+		{
+			name:        "cluster not running",
+			args:        map[string]any{"cluster_id": "cluster-0", "args": []any{"get", "pods"}},
+			created:     []string{"cluster-0"},
+			expectError: "is terminated",
+		},
+		{
+			name:        "command not allowed",
+			args:        map[string]any{"cluster_id": "cluster-1", "args": []any{"delete", "pods", "--all"}},
+			created:     []string{"cluster-1"},
+			expectError: "'delete' is not allowed",
+		},
+		{
+			name: "timeout too long",
+			args: map[string]any{
+				"cluster_id": "cluster-1", "args": []any{"get", "pods"}, "timeout_seconds": 3600,
+			},
+			created:     []string{"cluster-1"},
+			expectError: "timeout_seconds must be between",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, newTestClusterAPI(t).URL)
+			for _, clusterID := range tt.created {
+				if err := server.stateStore().RecordCreatedCluster(clusterID); err != nil {
+					t.Fatalf("Failed to record cluster: %v", err)
+				}
+			}
+
+			tool, ok := server.registry.Tool(runClusterCommandToolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", runClusterCommandToolName)
+			}
+
+			request := createMockCallToolRequest(runClusterCommandToolName, tt.args)
+			result, err := tool.handler(context.Background(), request)
+			if tt.expectError != "" {
+				if err == nil || !contains(err.Error(), tt.expectError) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var commandResult clusterCommandResult
+			if err := json.Unmarshal(resultData(t, result), &commandResult); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if commandResult.ExitCode != tt.wantExitCode || commandResult.TimedOut != tt.wantTimedOut {
+				t.Errorf("Expected exit code %d (timed out %t), got %+v",
+					tt.wantExitCode, tt.wantTimedOut, commandResult)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(commandResult.Output, want) {
+					t.Errorf("Expected output to contain %q, got %q", want, commandResult.Output)
+				}
+			}
+			if commandResult.Command[0] != kubectlCommand || commandResult.ClusterID != "cluster-1" {
+				t.Errorf("Unexpected command or cluster: %+v", commandResult)
+			}
+		})
+	}
+}
+
+func TestRunClusterCommandProgress(t *testing.T) {
+	installFakeKubectl(t)
+
+	server := newTestServer(t, newTestClusterAPI(t).URL)
+	if err := server.stateStore().RecordCreatedCluster("cluster-1"); err != nil {
+		t.Fatalf("Failed to record cluster: %v", err)
+	}
+
+	session := newTestSession()
+	if err := server.mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+	ctx := server.mcpServer.WithContext(context.Background(), session)
+
+	tool, _ := server.registry.Tool(runClusterCommandToolName)
+	request := createMockCallToolRequest(runClusterCommandToolName,
+		map[string]any{"cluster_id": "cluster-1", "args": []any{"version"}})
+	request.Params.Meta = &mcp.Meta{ProgressToken: "progress-1"}
+
+	if _, err := tool.handler(ctx, request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	notification := <-session.notifications
+	if notification.Method != "notifications/progress" ||
+		notification.Params.AdditionalFields["message"] != "args: version" {
+		t.Errorf("Expected the first output line as progress, got %+v", notification.Params.AdditionalFields)
+	}
+}
+
+func TestClusterCommandsOptIn(t *testing.T) {
+	server := newTestServer(t, "")
+	if listedToolNames(t, server)[runClusterCommandToolName] {
+		t.Errorf("Expected %s to be withheld unless cluster commands are enabled", runClusterCommandToolName)
+	}
+
+	enabled := *server.currentConfig()
+	enabled.ClusterCommands = true
+	if err := server.Reconfigure(&enabled); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !listedToolNames(t, server)[runClusterCommandToolName] {
+		t.Errorf("Expected %s to be listed once cluster commands are enabled", runClusterCommandToolName)
+	}
+}
+
+func TestCommandOutput(t *testing.T) {
+	var lines []string
+	output := &commandOutput{limit: 10, onLine: func(line string) { lines = append(lines, line) }}
+
+	for _, chunk := range []string{"first\nsec", "ond\n", "third line"} {
+		if _, err := output.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	output.flush()
+
+	if got := strings.Join(lines, "|"); got != "first|second|third line" {
+		t.Errorf("Expected each line reported once, got %q", got)
+	}
+	if output.buffer.String() != "first\nseco" || !output.truncated {
+		t.Errorf("Expected output cut off at the limit, got %q (truncated %t)", output.buffer.String(), output.truncated)
+	}
+}
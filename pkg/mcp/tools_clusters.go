@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// defaultClusterTTL is how long a created cluster or VM lives unless the caller asks otherwise,
+// so ones an agent forgets to delete stop using credits
+const defaultClusterTTL = "1h"
+
+// Compatibility Matrix Cluster Tools
+
+// deleteClusterResult reports a cluster's state after a delete_cluster call
+type deleteClusterResult struct {
+	ClusterID   string `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+	Status      string `json:"status"`
+	Changed     bool   `json:"changed"`
+}
+
+// clusterKubeconfig is the kubeconfig of a cluster
+type clusterKubeconfig struct {
+	ClusterID  string `json:"cluster_id"`
+	Kubeconfig string `json:"kubeconfig"`
+}
+
+// defineListClustersTool creates the list_clusters tool definition.
+// Lists the team's Compatibility Matrix clusters.
+func (s *Server) defineListClustersTool() toolDefinition {
+	tool := mcp.NewTool("list_clusters",
+		mcp.WithDescription("List the team's Compatibility Matrix clusters with their distribution, "+
+			"Kubernetes version, status, and expiration. Terminated clusters are omitted unless requested."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithBoolean("include_terminated",
+			mcp.Description("Whether to include terminated clusters (default false)"),
+		),
+		outputSchema[[]models.Cluster](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_clusters tool called", "arguments", request.GetArguments())
+
+		list, err := s.clusters.ListClusters(ctx, request.GetBool("include_terminated", false))
+		if err != nil {
+			return nil, err
+		}
+
+		return envelopeResult(ctx, listOf(list.Clusters))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineCreateClusterTool creates the create_cluster tool definition.
+// Requests an ephemeral Compatibility Matrix cluster for testing a release.
+func (s *Server) defineCreateClusterTool() toolDefinition {
+	tool := mcp.NewTool("create_cluster",
+		mcp.WithDescription("Create an ephemeral Compatibility Matrix cluster for testing a release. The "+
+			"cluster is returned while it is queued or provisioning; poll list_clusters until it is running, "+
+			"then use get_cluster_kubeconfig to connect. Clusters use credits until they expire or are "+
+			"deleted, so keep the ttl short. With dry_run, the cluster is checked and described without "+
+			"creating it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("distribution",
+			mcp.Required(),
+			mcp.Description("The Kubernetes distribution, such as k3s, kind, eks, gke, aks, or openshift"),
+		),
+		mcp.WithString("version",
+			mcp.Description("The Kubernetes version, such as 1.30; defaults to the distribution's latest"),
+		),
+		mcp.WithString("name",
+			mcp.Description("A name for the cluster; one is generated if omitted"),
+		),
+		mcp.WithNumber("node_count",
+			mcp.Description("The number of nodes (default 1)"),
+			mcp.Min(1),
+			mcp.Max(models.MaxClusterNodeCount),
+		),
+		mcp.WithString("instance_type",
+			mcp.Description("The node instance type, such as m6i.large; defaults to the distribution's default"),
+		),
+		mcp.WithNumber("disk_gib",
+			mcp.Description("The disk size of each node in GiB; defaults to the distribution's default"),
+			mcp.Min(1),
+		),
+		mcp.WithString("ttl",
+			mcp.Description(fmt.Sprintf("How long the cluster lives before it is deleted, such as 30m or 4h "+
+				"(%s to %s, default %s)", models.MinClusterTTL, models.MaxClusterTTL, defaultClusterTTL)),
+		),
+		dryRunParameter(),
+		outputSchema[models.Cluster](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("create_cluster tool called", "arguments", request.GetArguments())
+
+		distribution, err := request.RequireString("distribution")
+		if err != nil {
+			return nil, err
+		}
+
+		spec := &models.ClusterSpec{
+			Name:         request.GetString("name", ""),
+			Distribution: distribution,
+			Version:      request.GetString("version", ""),
+			NodeCount:    request.GetInt("node_count", 0),
+			InstanceType: request.GetString("instance_type", ""),
+			DiskGiB:      request.GetInt("disk_gib", 0),
+			TTL:          request.GetString("ttl", defaultClusterTTL),
+		}
+		if s.dryRun(request) {
+			if err := spec.Validate(); err != nil {
+				return nil, err
+			}
+			return dryRunResult(ctx, fmt.Sprintf("create a %s cluster that expires after %s",
+				distributionLabel(spec.Distribution, spec.Version), spec.TTL), spec)
+		}
+
+		cluster, err := s.clusters.CreateCluster(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.stateStore().RecordCreatedCluster(cluster.ID); err != nil {
+			s.logger.WithContext(ctx).Error("Failed to record created cluster", "cluster_id", cluster.ID, "error", err)
+		}
+
+		return jsonResult(ctx, cluster)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineDeleteClusterTool creates the delete_cluster tool definition.
+// Terminates a Compatibility Matrix cluster once the caller confirms.
+func (s *Server) defineDeleteClusterTool() toolDefinition {
+	tool := mcp.NewTool("delete_cluster",
+		mcp.WithDescription("Terminate a Compatibility Matrix cluster, destroying it and anything installed "+
+			"on it. This requires confirmation: a call without confirmation_token fails, describes the change, "+
+			"and returns a token; once the user agrees, make the same call with the token within two minutes. "+
+			"Deleting a terminated cluster changes nothing. With dry_run, the deletion is described without "+
+			"making it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("cluster_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the cluster"),
+		),
+		confirmationTokenParameter(),
+		dryRunParameter(),
+		outputSchema[deleteClusterResult](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("delete_cluster tool called", "arguments", request.GetArguments())
+
+		clusterID, err := request.RequireString("cluster_id")
+		if err != nil {
+			return nil, err
+		}
+
+		cluster, err := s.findCluster(ctx, clusterID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := deleteClusterResult{ClusterID: cluster.ID, ClusterName: cluster.Name, Status: cluster.Status}
+		if cluster.Status == models.ClusterStatusTerminated {
+			return jsonResult(ctx, result)
+		}
+
+		description := fmt.Sprintf("cluster '%s' (%s)", cluster.Name, cluster.ID)
+		if s.dryRun(request) {
+			preview := result
+			preview.Status = models.ClusterStatusTerminated
+			preview.Changed = true
+			return dryRunResult(ctx, "delete "+description, preview)
+		}
+
+		if err := s.requireConfirmation(request, "deleting "+description); err != nil {
+			return nil, err
+		}
+
+		if err := s.clusters.DeleteCluster(ctx, cluster.ID); err != nil {
+			return nil, err
+		}
+		if err := s.stateStore().ForgetCreatedCluster(cluster.ID); err != nil {
+			s.logger.WithContext(ctx).Error("Failed to forget deleted cluster", "cluster_id", cluster.ID, "error", err)
+		}
+
+		result.Status = models.ClusterStatusTerminated
+		result.Changed = true
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineGetClusterKubeconfigTool creates the get_cluster_kubeconfig tool definition.
+// Returns the kubeconfig for connecting to a running cluster.
+func (s *Server) defineGetClusterKubeconfigTool() toolDefinition {
+	tool := mcp.NewTool("get_cluster_kubeconfig",
+		mcp.WithDescription("Get the kubeconfig of a running Compatibility Matrix cluster, for installing "+
+			"and testing a release on it. The kubeconfig grants admin access to the cluster."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("cluster_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the cluster"),
+		),
+		outputSchema[clusterKubeconfig](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_cluster_kubeconfig tool called", "arguments", request.GetArguments())
+
+		clusterID, err := request.RequireString("cluster_id")
+		if err != nil {
+			return nil, err
+		}
+
+		kubeconfig, err := s.clusters.GetKubeconfig(ctx, clusterID)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, clusterKubeconfig{ClusterID: clusterID, Kubeconfig: kubeconfig})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// findCluster returns one of the team's clusters, including terminated ones, by ID
+func (s *Server) findCluster(ctx context.Context, clusterID string) (*models.Cluster, error) {
+	list, err := s.clusters.ListClusters(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.Clusters {
+		if list.Clusters[i].ID == clusterID {
+			return &list.Clusters[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("cluster '%s' not found", clusterID)
+}
+
+// distributionLabel names a distribution with its version, if one was given, such as "k3s 1.30"
+func distributionLabel(distribution, version string) string {
+	if version == "" {
+		return distribution
+	}
+	return distribution + " " + version
+}
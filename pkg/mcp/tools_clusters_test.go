@@ -0,0 +1,281 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// testClusterAPI is a fake Compatibility Matrix API that records the clusters created and
+// deleted through it
+type testClusterAPI struct {
+	*httptest.Server
+	mu      sync.Mutex
+	created []models.ClusterSpec
+	deleted []string
+}
+
+// newTestClusterAPI serves a running cluster and a terminated one
+func newTestClusterAPI(t *testing.T) *testClusterAPI {
+	t.Helper()
+
+	vendorAPI := &testClusterAPI{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/clusters", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("show-terminated") != "true" {
+			fmt.Fprint(w, `{"clusters": [{"id": "cluster-1", "name": "ci-1", "status": "running"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"clusters": [
+			{"id": "cluster-1", "name": "ci-1", "status": "running"},
+			{"id": "cluster-0", "name": "ci-0", "status": "terminated"}
+		]}`)
+	})
+	mux.HandleFunc("POST /vendor/v3/cluster", func(w http.ResponseWriter, r *http.Request) {
+		var spec models.ClusterSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		vendorAPI.mu.Lock()
+		vendorAPI.created = append(vendorAPI.created, spec)
+		vendorAPI.mu.Unlock()
+
+		fmt.Fprintf(w, `{"cluster": {"id": "cluster-2", "distribution": %q, "status": "queued", "ttl": %q}}`,
+			spec.Distribution, spec.TTL)
+	})
+	mux.HandleFunc("DELETE /vendor/v3/cluster/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vendorAPI.mu.Lock()
+		vendorAPI.deleted = append(vendorAPI.deleted, r.PathValue("id"))
+		vendorAPI.mu.Unlock()
+
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/cluster/cluster-1/kubeconfig", func(w http.ResponseWriter, _ *http.Request) {
+		// "apiVersion: v1" encoded as base64
+		fmt.Fprint(w, `{"kubeconfig": "YXBpVmVyc2lvbjogdjE="}`)
+	})
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestListClustersTool(t *testing.T) {
+	server := newTestServer(t, newTestClusterAPI(t).URL)
+
+	tool, ok := server.registry.Tool("list_clusters")
+	if !ok {
+		t.Fatal("Tool 'list_clusters' not found")
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantCount int
+	}{
+		{name: "active clusters", args: map[string]any{}, wantCount: 1},
+		{name: "including terminated clusters", args: map[string]any{"include_terminated": true}, wantCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_clusters", tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			clusters := decodeEnvelope[[]models.Cluster](t, result).Data
+			if len(clusters) != tt.wantCount {
+				t.Errorf("Expected %d clusters, got %+v", tt.wantCount, clusters)
+			}
+		})
+	}
+}
+
+func TestCreateClusterTool(t *testing.T) {
+	vendorAPI := newTestClusterAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("create_cluster")
+	if !ok {
+		t.Fatal("Tool 'create_cluster' not found")
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		wantSpec    models.ClusterSpec
+		expectError bool
+	}{
+		{
+			name:     "default TTL",
+			args:     map[string]any{"distribution": "k3s"},
+			wantSpec: models.ClusterSpec{Distribution: "k3s", TTL: defaultClusterTTL},
+		},
+		{
+			name: "full spec",
+			args: map[string]any{
+				"distribution": "eks", "version": "1.30", "name": "ci-2", "node_count": 3,
+				"instance_type": "m6i.large", "disk_gib": 100, "ttl": "4h",
+			},
+			wantSpec: models.ClusterSpec{
+				Name: "ci-2", Distribution: "eks", Version: "1.30", NodeCount: 3,
+				InstanceType: "m6i.large", DiskGiB: 100, TTL: "4h",
+			},
+		},
+		{name: "TTL too long", args: map[string]any{"distribution": "k3s", "ttl": "72h"}, expectError: true},
+		{name: "missing distribution", args: map[string]any{}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.created = nil
+			vendorAPI.mu.Unlock()
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("create_cluster", tt.args))
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if len(vendorAPI.created) != 0 {
+					t.Errorf("Expected no cluster to be created, got %+v", vendorAPI.created)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(vendorAPI.created, []models.ClusterSpec{tt.wantSpec}) {
+				t.Errorf("Expected cluster %+v to be created, got %+v", tt.wantSpec, vendorAPI.created)
+			}
+			if !contains(resultText(t, result), `"status": "queued"`) {
+				t.Errorf("Expected the queued cluster, got %s", resultText(t, result))
+			}
+		})
+	}
+}
+
+func TestDeleteClusterTool(t *testing.T) {
+	vendorAPI := newTestClusterAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("delete_cluster")
+	if !ok {
+		t.Fatal("Tool 'delete_cluster' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		confirm       bool
+		expectConfirm bool
+		expectError   bool
+		expectInText  string
+		wantDeleted   []string
+	}{
+		{
+			name:          "delete without confirmation",
+			args:          map[string]any{"cluster_id": "cluster-1"},
+			expectConfirm: true,
+		},
+		{
+			name:         "delete confirmed",
+			args:         map[string]any{"cluster_id": "cluster-1"},
+			confirm:      true,
+			expectInText: `"changed": true`,
+			wantDeleted:  []string{"cluster-1"},
+		},
+		{
+			name:         "delete a terminated cluster",
+			args:         map[string]any{"cluster_id": "cluster-0"},
+			expectInText: `"changed": false`,
+		},
+		{
+			name:        "unknown cluster",
+			args:        map[string]any{"cluster_id": "cluster-9"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.deleted = nil
+			vendorAPI.mu.Unlock()
+
+			var result *mcp.CallToolResult
+			var err error
+			if tt.confirm {
+				result, err = callConfirmed(t, tool, tt.args)
+			} else {
+				result, err = tool.handler(context.Background(), createMockCallToolRequest("delete_cluster", tt.args))
+			}
+			switch {
+			case tt.expectConfirm:
+				var confirmErr *confirmationError
+				if !errors.As(err, &confirmErr) {
+					t.Fatalf("Expected a confirmation error, got %v", err)
+				}
+			case tt.expectError:
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			case err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case !contains(resultText(t, result), tt.expectInText):
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, resultText(t, result))
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			if !slices.Equal(vendorAPI.deleted, tt.wantDeleted) {
+				t.Errorf("Expected clusters %v to be deleted, got %v", tt.wantDeleted, vendorAPI.deleted)
+			}
+		})
+	}
+}
+
+func TestGetClusterKubeconfigTool(t *testing.T) {
+	server := newTestServer(t, newTestClusterAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_cluster_kubeconfig")
+	if !ok {
+		t.Fatal("Tool 'get_cluster_kubeconfig' not found")
+	}
+
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("get_cluster_kubeconfig",
+		map[string]any{"cluster_id": "cluster-1"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var kubeconfig clusterKubeconfig
+	if err := json.Unmarshal(resultData(t, result), &kubeconfig); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if kubeconfig.ClusterID != "cluster-1" || kubeconfig.Kubeconfig != "apiVersion: v1" {
+		t.Errorf("Unexpected kubeconfig: %+v", kubeconfig)
+	}
+
+	if _, err := tool.handler(context.Background(), createMockCallToolRequest("get_cluster_kubeconfig",
+		map[string]any{"cluster_id": "cluster-9"})); err == nil {
+		t.Error("Expected error for an unknown cluster")
+	}
+}
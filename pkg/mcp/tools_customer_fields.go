@@ -0,0 +1,225 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+)
+
+// customerAnnotations reports the tags and notes kept in a customer's custom fields
+type customerAnnotations struct {
+	CustomerID   string   `json:"customer_id"`
+	CustomerName string   `json:"customer_name"`
+	Tags         []string `json:"tags"`
+	Notes        string   `json:"notes,omitempty"`
+}
+
+// Customer Custom Field Tools
+
+// defineTagCustomerTool creates the tag_customer tool definition.
+// Adds or removes tags kept in a customer's tags custom field in the Vendor Portal.
+func (s *Server) defineTagCustomerTool() toolDefinition {
+	tool := mcp.NewTool("tag_customer",
+		mcp.WithDescription("Add tags to a customer, or remove them, in the customer's '"+
+			models.CustomerTagsField+"' custom field in the Vendor Portal, so account context is visible to "+
+			"the whole team. The application must define that custom field; use tag_entity to keep tags "+
+			"locally instead. Returns the customer's tags after the change. With dry_run, the tags are "+
+			"previewed without saving them."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithArray("tags",
+			mcp.Required(),
+			mcp.Description("Tags to add or remove; letters, digits, and . _ : / - (e.g. region:eu, at-risk)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("remove",
+			mcp.Description("Remove the tags instead of adding them (default false)"),
+		),
+		dryRunParameter(),
+		outputSchema[customerAnnotations](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("tag_customer tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		requested, err := request.RequireStringSlice("tags")
+		if err != nil {
+			return nil, err
+		}
+		if len(requested) == 0 {
+			return nil, fmt.Errorf("at least one tag is required")
+		}
+		for i, tag := range requested {
+			if requested[i], err = store.NormalizeTag(tag); err != nil {
+				return nil, err
+			}
+		}
+
+		customer, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		tags := changeTags(customer.Tags(), requested, request.GetBool("remove", false))
+		if slices.Equal(tags, customer.Tags()) {
+			return jsonResult(ctx, annotationsOf(customer))
+		}
+
+		updated := *customer
+		updated.ApplicationID = appID
+		updated.SetTags(tags)
+		if s.dryRun(request) {
+			action := fmt.Sprintf("set the tags of customer '%s' (%s) to [%s]",
+				customer.Name, customer.ID, strings.Join(tags, ", "))
+			return dryRunResult(ctx, action, annotationsOf(&updated))
+		}
+
+		return s.saveCustomerFields(ctx, &updated, models.CustomerTagsField)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineAnnotateCustomerTool creates the annotate_customer tool definition.
+// Adds free-form notes to a customer's notes custom field in the Vendor Portal.
+func (s *Server) defineAnnotateCustomerTool() toolDefinition {
+	tool := mcp.NewTool("annotate_customer",
+		mcp.WithDescription("Add a note to a customer's '"+models.CustomerNotesField+"' custom field in the "+
+			"Vendor Portal, such as account context gathered while investigating an issue, so it is kept with "+
+			"the customer. The note is appended to any existing notes unless replace is set. The application "+
+			"must define that custom field; use add_note to keep notes locally instead. Returns the customer's "+
+			"notes after the change. With dry_run, the notes are previewed without saving them."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithString("note",
+			mcp.Required(),
+			mcp.Description("The note to add"),
+		),
+		mcp.WithBoolean("replace",
+			mcp.Description("Replace the customer's existing notes instead of appending to them (default false)"),
+		),
+		dryRunParameter(),
+		outputSchema[customerAnnotations](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("annotate_customer tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		note, err := request.RequireString("note")
+		if err != nil {
+			return nil, err
+		}
+		if note = strings.TrimSpace(note); note == "" {
+			return nil, fmt.Errorf("note cannot be empty")
+		}
+
+		customer, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		notes := note
+		if existing := customer.Notes(); existing != "" && !request.GetBool("replace", false) {
+			notes = existing + "\n\n" + note
+		}
+
+		updated := *customer
+		updated.ApplicationID = appID
+		updated.SetNotes(notes)
+		if s.dryRun(request) {
+			action := fmt.Sprintf("update the notes of customer '%s' (%s)", customer.Name, customer.ID)
+			return dryRunResult(ctx, action, annotationsOf(&updated))
+		}
+
+		return s.saveCustomerFields(ctx, &updated, models.CustomerNotesField)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// saveCustomerFields saves a customer's custom field, explaining how to define it when the
+// application does not
+func (s *Server) saveCustomerFields(
+	ctx context.Context, customer *models.Customer, field string,
+) (*mcp.CallToolResult, error) {
+	stored, err := s.customers.UpdateCustomFields(ctx, customer,
+		map[string]string{field: customer.CustomFields[field]})
+	if errors.Is(err, api.ErrCustomFieldsNotSupported) {
+		return nil, fmt.Errorf("%w; define a '%s' custom field for the application in the Vendor Portal", err, field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(ctx, annotationsOf(stored))
+}
+
+// changeTags adds tags to or removes them from a customer's tags, returning them sorted and
+// without duplicates
+func changeTags(current, requested []string, remove bool) []string {
+	tags := slices.Clone(current)
+	if remove {
+		tags = slices.DeleteFunc(tags, func(tag string) bool { return slices.Contains(requested, tag) })
+	} else {
+		tags = append(tags, requested...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// annotationsOf returns the tags and notes kept in a customer's custom fields
+func annotationsOf(customer *models.Customer) customerAnnotations {
+	return customerAnnotations{
+		CustomerID:   customer.ID,
+		CustomerName: customer.Name,
+		Tags:         customer.Tags(),
+		Notes:        customer.Notes(),
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCustomerFieldTools(t *testing.T) {
+	vendorAPI := newTestUpdateAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tests := []struct {
+		name         string
+		tool         string
+		args         map[string]any
+		expectError  bool
+		expectInText []string
+		expectFields map[string]any
+	}{
+		{
+			name: "tag a customer",
+			tool: "tag_customer",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "tags": []any{"Enterprise", "at-risk"},
+			},
+			expectInText: []string{`"tags": [`, `"at-risk"`, `"enterprise"`},
+			expectFields: map[string]any{"tags": "at-risk,enterprise"},
+		},
+		{
+			name: "remove a tag the customer does not have",
+			tool: "tag_customer",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "tags": []any{"enterprise"}, "remove": true,
+			},
+			expectInText: []string{`"tags": []`},
+		},
+		{
+			name: "preview tags",
+			tool: "tag_customer",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "tags": []any{"tier-1"}, "dry_run": true,
+			},
+			expectInText: []string{`"dry_run": true`, `"tier-1"`},
+		},
+		{
+			name:        "invalid tag",
+			tool:        "tag_customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "tags": []any{"no spaces"}},
+			expectError: true,
+		},
+		{
+			name:        "no tags",
+			tool:        "tag_customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "tags": []any{}},
+			expectError: true,
+		},
+		{
+			name:         "annotate a customer",
+			tool:         "annotate_customer",
+			args:         map[string]any{"app_id": testAppSlug, "customer_id": "customer-1", "note": " Prefers email "},
+			expectInText: []string{`"notes": "Prefers email"`},
+			expectFields: map[string]any{"notes": "Prefers email"},
+		},
+		{
+			name:        "empty note",
+			tool:        "annotate_customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "note": "  "},
+			expectError: true,
+		},
+		{
+			name:        "unknown customer",
+			tool:        "annotate_customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-9", "note": "Churned"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.updates = nil
+			vendorAPI.mu.Unlock()
+
+			tool, ok := server.registry.Tool(tt.tool)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.tool)
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest(tt.tool, tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				for _, want := range tt.expectInText {
+					if !contains(resultText(t, result), want) {
+						t.Errorf("Expected response containing '%s', got '%s'", want, resultText(t, result))
+					}
+				}
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			var got []any
+			for _, update := range vendorAPI.updates {
+				got = append(got, update["customFields"])
+			}
+			var want []any
+			if tt.expectFields != nil {
+				want = []any{tt.expectFields}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected custom field updates %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// customerSummaryConcurrency bounds how many requests a customer summary makes at once
+const customerSummaryConcurrency = 4
+
+// defaultSummaryEvents is how many recent events of each instance a customer summary lists
+const defaultSummaryEvents = 10
+
+// customerSummary gathers what is known about a customer in one document: the customer, its
+// license, its entitlement values, and the health and recent events of each instance
+type customerSummary struct {
+	AppID        string                    `json:"app_id"`
+	Customer     models.Customer           `json:"customer"`
+	License      customerLicense           `json:"license"`
+	Entitlements []models.EntitlementValue `json:"entitlements"`
+	Instances    []instanceHealth          `json:"instances"`
+}
+
+// customerLicense summarizes a customer's license
+type customerLicense struct {
+	LicenseID         string     `json:"license_id"`
+	LicenseType       string     `json:"license_type"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	IsExpired         bool       `json:"is_expired"`
+	IsGitOpsSupported bool       `json:"is_gitops_supported"`
+}
+
+// instanceHealth summarizes an instance from its last check-in and its events. Status is the
+// status the instance last entered, and LastUpgradeAt when it last installed a later release.
+type instanceHealth struct {
+	ID                     string                 `json:"id"`
+	VersionLabel           string                 `json:"version_label"`
+	ReleaseSequence        int64                  `json:"release_sequence"`
+	IsActive               bool                   `json:"is_active"`
+	Status                 string                 `json:"status,omitempty"`
+	LastCheckinAt          *time.Time             `json:"last_checkin_at,omitempty"`
+	LastUpgradeAt          *time.Time             `json:"last_upgrade_at,omitempty"`
+	KubernetesDistribution string                 `json:"kubernetes_distribution,omitempty"`
+	KubernetesVersion      string                 `json:"kubernetes_version,omitempty"`
+	RecentEvents           []models.InstanceEvent `json:"recent_events"`
+}
+
+// Customer Summary Tools
+
+// defineSummarizeCustomerTool creates the summarize_customer tool definition.
+// Combines a customer, its license, entitlements, instance health, and recent events in one call.
+func (s *Server) defineSummarizeCustomerTool() toolDefinition {
+	tool := mcp.NewTool("summarize_customer",
+		mcp.WithDescription("Summarize a customer in one call: its details, license (type, expiration, and "+
+			"whether it has expired), entitlement values, and for each instance its version, whether it is "+
+			"active, its last status, last check-in and last upgrade, and its most recent events. Use it to "+
+			"answer questions about a customer instead of calling get_customer, get_customer_entitlements, "+
+			"and list_instance_events separately. Entitlements or events that cannot be read are left out "+
+			"with a warning."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithNumber("events_limit",
+			mcp.Description("Maximum number of recent events to list for each instance (1-100, default 10)"),
+			mcp.Min(minLimit),
+			mcp.Max(maxListLimit),
+		),
+		outputSchema[customerSummary](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("summarize_customer tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		customer, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.summarizeCustomer(ctx, appID, customer, request.GetInt("events_limit", defaultSummaryEvents))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// summarizeCustomer reads a customer's entitlements and the events of each of its instances
+// concurrently. A read that fails is reported as a warning rather than failing the summary.
+func (s *Server) summarizeCustomer(
+	ctx context.Context, appID string, customer *models.Customer, eventsLimit int,
+) (*mcp.CallToolResult, error) {
+	summary := customerSummary{
+		AppID:    appID,
+		Customer: *customer,
+		License: customerLicense{
+			LicenseID:         customer.LicenseID,
+			LicenseType:       customer.LicenseType,
+			ExpiresAt:         customer.ExpiresAt,
+			IsExpired:         customer.ExpiresAt != nil && !customer.ExpiresAt.After(time.Now()),
+			IsGitOpsSupported: customer.IsGitOpsSupported,
+		},
+		Entitlements: []models.EntitlementValue{},
+		Instances:    make([]instanceHealth, len(customer.Instances)),
+	}
+	summary.Customer.Instances = nil
+
+	var entitlementsErr error
+	eventErrs := make([]error, len(customer.Instances))
+
+	var group errgroup.Group
+	group.SetLimit(customerSummaryConcurrency)
+	group.Go(func() error {
+		entitlements, err := s.entitlements.GetCustomerEntitlements(ctx, customer.ID)
+		if err != nil {
+			entitlementsErr = err
+			return nil
+		}
+		summary.Entitlements = append(summary.Entitlements, entitlements.Entitlements...)
+		return nil
+	})
+	for i := range customer.Instances {
+		group.Go(func() error {
+			instance := &customer.Instances[i]
+			list, err := s.customers.ListInstanceEvents(ctx, customer.ID, instance.ID)
+			if err != nil {
+				eventErrs[i] = err
+				list = nil
+			}
+			summary.Instances[i] = summarizeInstance(instance, list, eventsLimit)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	response := envelope{Data: &summary}
+	if entitlementsErr != nil {
+		response.warn("the customer's entitlements could not be read: %v", entitlementsErr)
+	}
+	for i, err := range eventErrs {
+		if err != nil {
+			response.warn("the events of instance %s could not be read: %v", customer.Instances[i].ID, err)
+		}
+	}
+	return envelopeResult(ctx, response)
+}
+
+// summarizeInstance summarizes an instance's health from its last check-in and its events,
+// keeping the limit most recent events. Without events, only the check-in is summarized.
+func summarizeInstance(instance *models.Instance, events *api.InstanceEventList, limit int) instanceHealth {
+	health := instanceHealth{
+		ID:                     instance.ID,
+		VersionLabel:           instance.VersionLabel,
+		ReleaseSequence:        instance.ReleaseSequence,
+		IsActive:               instance.IsActive,
+		LastCheckinAt:          instance.LastCheckinAt,
+		KubernetesDistribution: instance.KubernetesDistribution,
+		KubernetesVersion:      instance.KubernetesVersion,
+		RecentEvents:           []models.InstanceEvent{},
+	}
+	if events == nil {
+		return health
+	}
+
+	health.RecentEvents = append(health.RecentEvents, events.Events...)
+	slices.SortStableFunc(health.RecentEvents, func(a, b models.InstanceEvent) int {
+		return b.OccurredAt.Compare(a.OccurredAt)
+	})
+
+	for i := range health.RecentEvents {
+		event := &health.RecentEvents[i]
+		switch {
+		case event.Type == models.InstanceEventCheckin && laterThan(event.OccurredAt, health.LastCheckinAt):
+			health.LastCheckinAt = &event.OccurredAt
+		case event.Type == models.InstanceEventStatusChange && health.Status == "":
+			health.Status = event.Status
+		case event.IsUpgrade() && health.LastUpgradeAt == nil:
+			health.LastUpgradeAt = &event.OccurredAt
+		}
+	}
+
+	if len(health.RecentEvents) > limit {
+		health.RecentEvents = health.RecentEvents[:limit]
+	}
+	return health
+}
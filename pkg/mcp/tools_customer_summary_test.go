@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// fakeCustomerSummary serves a customer with an expired license and two instances, only one
+// of which has readable events, and a customer whose entitlements cannot be read
+type fakeCustomerSummary struct {
+	customersService
+	vendorapi.Entitlements
+}
+
+func (fakeCustomerSummary) ListCustomers(context.Context, string) (*api.CustomerList, error) {
+	return &api.CustomerList{TotalCount: 2, Customers: []models.Customer{
+		{ID: "customer-1", Name: "Acme", LicenseID: "license-1", LicenseType: "trial",
+			ExpiresAt: summaryTime("2025-01-31T00:00:00Z"), Instances: []models.Instance{
+				{ID: "instance-1", VersionLabel: "1.2.0", ReleaseSequence: 3, IsActive: true,
+					LastCheckinAt: summaryTime("2025-01-18T10:00:00Z")},
+				{ID: "instance-2", VersionLabel: "1.0.0", ReleaseSequence: 1},
+			}},
+		{ID: "customer-2", Name: "Globex", LicenseType: "paid"},
+	}}, nil
+}
+
+func (fakeCustomerSummary) GetCustomerEntitlements(
+	_ context.Context, customerID string,
+) (*api.CustomerEntitlements, error) {
+	if customerID != "customer-1" {
+		return nil, &api.Error{StatusCode: http.StatusNotFound, Message: "Not Found"}
+	}
+	return &api.CustomerEntitlements{CustomerID: customerID, Entitlements: []models.EntitlementValue{
+		{Name: "seat_count", Type: "integer", Value: "10"},
+	}}, nil
+}
+
+func (fakeCustomerSummary) ListInstanceEvents(
+	_ context.Context, customerID, instanceID string,
+) (*api.InstanceEventList, error) {
+	if customerID != "customer-1" || instanceID != "instance-1" {
+		return nil, &api.Error{StatusCode: http.StatusNotFound, Message: "Not Found"}
+	}
+	return &api.InstanceEventList{Events: []models.InstanceEvent{
+		{Type: "version_change", OccurredAt: *summaryTime("2025-01-10T10:00:00Z"), VersionLabel: "1.2.0",
+			ReleaseSequence: 3, PreviousVersionLabel: "1.1.0", PreviousReleaseSequence: 2},
+		{Type: "status_change", OccurredAt: *summaryTime("2025-01-12T09:00:00Z"), Status: "degraded",
+			PreviousStatus: "ready"},
+		{Type: "checkin", OccurredAt: *summaryTime("2025-01-20T10:00:00Z"), ReleaseSequence: 3},
+		{Type: "status_change", OccurredAt: *summaryTime("2025-01-14T09:00:00Z"), Status: "ready",
+			PreviousStatus: "degraded"},
+	}}, nil
+}
+
+// summaryTime parses an RFC 3339 timestamp of the customer summary fixture
+func summaryTime(value string) *time.Time {
+	at, _ := time.Parse(time.RFC3339, value)
+	return &at
+}
+
+// newTestCustomerSummaryServer creates a server reading customers from fakeCustomerSummary
+func newTestCustomerSummaryServer(t *testing.T) *Server {
+	t.Helper()
+	account := fakeCustomerSummary{}
+	return newTestServerWithServices(t, vendorapi.Services{Customers: account, Entitlements: account})
+}
+
+func TestSummarizeCustomerTool(t *testing.T) {
+	server := newTestCustomerSummaryServer(t)
+
+	tool, ok := server.registry.Tool("summarize_customer")
+	if !ok {
+		t.Fatal("Tool 'summarize_customer' not found")
+	}
+
+	tests := []struct {
+		name             string
+		args             map[string]any
+		expectError      bool
+		wantEntitlements []string
+		wantInstances    []string
+		wantEvents       int
+		wantWarnings     int
+	}{
+		{
+			name:             "customer with instances",
+			args:             map[string]any{"app_id": testAppSlug, "customer_id": "customer-1"},
+			wantEntitlements: []string{"seat_count"},
+			wantInstances:    []string{"instance-1", "instance-2"},
+			wantEvents:       4,
+			wantWarnings:     1,
+		},
+		{
+			name:             "limited events",
+			args:             map[string]any{"app_id": testAppID, "customer_id": "customer-1", "events_limit": 2},
+			wantEntitlements: []string{"seat_count"},
+			wantInstances:    []string{"instance-1", "instance-2"},
+			wantEvents:       2,
+			wantWarnings:     1,
+		},
+		{
+			name:             "customer whose entitlements cannot be read",
+			args:             map[string]any{"app_id": testAppID, "customer_id": "customer-2"},
+			wantEntitlements: []string{},
+			wantInstances:    []string{},
+			wantWarnings:     1,
+		},
+		{
+			name:        "unknown customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-9"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("summarize_customer", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			response := decodeEnvelope[json.RawMessage](t, result)
+			var summary customerSummary
+			if err := json.Unmarshal(response.Data, &summary); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+
+			entitlements := []string{}
+			for _, entitlement := range summary.Entitlements {
+				entitlements = append(entitlements, entitlement.Name)
+			}
+			instances := []string{}
+			for _, instance := range summary.Instances {
+				instances = append(instances, instance.ID)
+			}
+			if !slices.Equal(entitlements, tt.wantEntitlements) {
+				t.Errorf("Expected entitlements %v, got %v", tt.wantEntitlements, entitlements)
+			}
+			if !slices.Equal(instances, tt.wantInstances) {
+				t.Errorf("Expected instances %v, got %v", tt.wantInstances, instances)
+			}
+			if len(response.Warnings) != tt.wantWarnings {
+				t.Errorf("Expected %d warnings, got %v", tt.wantWarnings, response.Warnings)
+			}
+			if len(summary.Customer.Instances) != 0 {
+				t.Errorf("Expected instances only in the instance summaries, got %+v", summary.Customer.Instances)
+			}
+			if len(summary.Instances) > 0 && len(summary.Instances[0].RecentEvents) != tt.wantEvents {
+				t.Errorf("Expected %d recent events, got %d", tt.wantEvents, len(summary.Instances[0].RecentEvents))
+			}
+		})
+	}
+}
+
+func TestSummarizeCustomerToolHealth(t *testing.T) {
+	server := newTestCustomerSummaryServer(t)
+
+	tool, _ := server.registry.Tool("summarize_customer")
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("summarize_customer",
+		map[string]any{"app_id": testAppID, "customer_id": "customer-1", "events_limit": 1}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var summary customerSummary
+	if err := json.Unmarshal(resultData(t, result), &summary); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+
+	if summary.License.LicenseID != "license-1" || summary.License.LicenseType != "trial" || !summary.License.IsExpired {
+		t.Errorf("Unexpected license: %+v", summary.License)
+	}
+
+	health := summary.Instances[0]
+	if health.Status != "ready" {
+		t.Errorf("Expected the last status entered, got '%s'", health.Status)
+	}
+	if health.LastCheckinAt == nil || !health.LastCheckinAt.Equal(time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the latest check-in event to be the last check-in, got %v", health.LastCheckinAt)
+	}
+	if health.LastUpgradeAt == nil || !health.LastUpgradeAt.Equal(time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the last upgrade, got %v", health.LastUpgradeAt)
+	}
+
+	stale := summary.Instances[1]
+	if stale.IsActive || stale.Status != "" || stale.LastCheckinAt != nil || len(stale.RecentEvents) != 0 {
+		t.Errorf("Expected only the check-in summary of an instance without events, got %+v", stale)
+	}
+}
@@ -0,0 +1,384 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Customer Archive Tools
+
+// archiveResult reports a customer's archive state after an archive or unarchive call
+type archiveResult struct {
+	AppID        string `json:"app_id"`
+	CustomerID   string `json:"customer_id"`
+	CustomerName string `json:"customer_name"`
+	IsArchived   bool   `json:"is_archived"`
+	Changed      bool   `json:"changed"`
+}
+
+// defineArchiveCustomerTool creates the archive_customer tool definition.
+// Archives a customer once the caller confirms, such as to clean up expired trials.
+func (s *Server) defineArchiveCustomerTool() toolDefinition {
+	options := append([]mcp.ToolOption{
+		mcp.WithDescription("Archive a customer, hiding it from customer lists and preventing its license " +
+			"from being used for new installations. Useful for cleaning up expired trials. This changes the " +
+			"vendor account, so it requires confirmation: a call without confirmation_token fails, describes " +
+			"the change, and returns a token; once the user agrees, make the same call with the token within " +
+			"two minutes. Archiving an archived customer changes nothing."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+	}, archiveToolParameters()...)
+	tool := mcp.NewTool("archive_customer", options...)
+
+	handler := s.setCustomerArchivedHandler("archive_customer", true)
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineUnarchiveCustomerTool creates the unarchive_customer tool definition.
+// Restores an archived customer once the caller confirms.
+func (s *Server) defineUnarchiveCustomerTool() toolDefinition {
+	options := append([]mcp.ToolOption{
+		mcp.WithDescription("Restore an archived customer so it appears in customer lists and its license " +
+			"can be used again. This changes the vendor account, so it requires confirmation: a call without " +
+			"confirmation_token fails, describes the change, and returns a token; once the user agrees, make " +
+			"the same call with the token within two minutes. Unarchiving an active customer changes nothing."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	}, archiveToolParameters()...)
+	tool := mcp.NewTool("unarchive_customer", options...)
+
+	handler := s.setCustomerArchivedHandler("unarchive_customer", false)
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// archiveToolParameters returns the parameters shared by the archive tools
+func archiveToolParameters() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		confirmationTokenParameter(),
+		dryRunParameter(),
+		outputSchema[archiveResult](),
+		alternateOutputSchema[dryRunPreview](),
+	}
+}
+
+// setCustomerArchivedHandler returns a handler that moves a customer to the given archive
+// state. The customer is looked up first, so a customer already in that state is reported
+// without calling the API and the confirmation request can name the customer.
+func (s *Server) setCustomerArchivedHandler(toolName string, archive bool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info(toolName+" tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		customer, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := archiveResult{
+			AppID:        appID,
+			CustomerID:   customer.ID,
+			CustomerName: customer.Name,
+			IsArchived:   customer.IsArchived,
+		}
+		if customer.IsArchived == archive {
+			return jsonResult(ctx, result)
+		}
+
+		verb, action := "unarchive", "unarchiving"
+		change := s.customers.Unarchive
+		if archive {
+			verb, action = "archive", "archiving"
+			change = s.customers.Archive
+		}
+
+		description := fmt.Sprintf("customer '%s' (%s)", customer.Name, customer.ID)
+		if s.dryRun(request) {
+			preview := result
+			preview.IsArchived = archive
+			preview.Changed = true
+			return dryRunResult(ctx, verb+" "+description, preview)
+		}
+
+		if err := s.requireConfirmation(request, action+" "+description); err != nil {
+			return nil, err
+		}
+
+		if err := change(ctx, customer.ID); err != nil {
+			return nil, err
+		}
+
+		result.IsArchived = archive
+		result.Changed = true
+		return jsonResult(ctx, result)
+	}
+}
+
+// findCustomer returns an application's customer by ID
+func (s *Server) findCustomer(ctx context.Context, appID, customerID string) (*models.Customer, error) {
+	list, err := s.customers.ListCustomers(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customers: %w", err)
+	}
+
+	for i := range list.Customers {
+		if list.Customers[i].ID == customerID {
+			return &list.Customers[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("customer '%s' not found in application %s", customerID, appID)
+}
+
+// Customer Update Tools
+
+// updateCustomerResult reports a customer after an update_customer call and which of its
+// fields changed
+type updateCustomerResult struct {
+	Customer *models.Customer `json:"customer"`
+	Changed  []string         `json:"changed"`
+}
+
+// defineUpdateCustomerTool creates the update_customer tool definition.
+// Changes a customer's name, email, channel, expiration, or license type, such as to
+// extend a trial or convert it to a paid license.
+func (s *Server) defineUpdateCustomerTool() toolDefinition {
+	tool := mcp.NewTool("update_customer",
+		mcp.WithDescription("Update a customer's name, email, assigned channel, expiration date, or license "+
+			"type, such as to extend a trial or convert it to a paid license. Only the given fields change. "+
+			"Returns the updated customer and the fields that changed; when nothing would change, the customer "+
+			"is returned without updating it. With dry_run, the updated customer is previewed without saving it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithString("name",
+			mcp.Description("The customer's new name"),
+		),
+		mcp.WithString("email",
+			mcp.Description("The customer's new email address"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The ID, slug, or name of the channel to assign the customer to"),
+		),
+		mcp.WithString("expires_at",
+			mcp.Description("The new license expiration: a date (2025-12-31, meaning midnight UTC), an "+
+				"RFC 3339 timestamp, or 'never' to remove the expiration"),
+		),
+		mcp.WithString("license_type",
+			mcp.Description("The new license type"),
+			mcp.Enum(models.ValidLicenseTypes...),
+		),
+		dryRunParameter(),
+		outputSchema[updateCustomerResult](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("update_customer tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		update := models.CustomerUpdate{
+			Name:        request.GetString("name", ""),
+			Email:       request.GetString("email", ""),
+			ExpiresAt:   request.GetString("expires_at", ""),
+			LicenseType: request.GetString("license_type", ""),
+		}
+		channel := request.GetString("channel_id", "")
+		if update.IsEmpty() && channel == "" {
+			return nil, fmt.Errorf("at least one of name, email, channel_id, expires_at, or license_type is required")
+		}
+		if err := update.Validate(); err != nil {
+			return nil, err
+		}
+
+		current, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.updateCustomer(ctx, appID, *current, update, channel, s.dryRun(request))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// updateCustomer applies an update to a copy of a customer, resolving the requested channel
+// first, and saves it only if something changed. In dry-run mode, the changed customer is
+// previewed instead of saved.
+func (s *Server) updateCustomer(
+	ctx context.Context,
+	appID string,
+	customer models.Customer,
+	update models.CustomerUpdate,
+	channelIDOrName string,
+	dryRun bool,
+) (*mcp.CallToolResult, error) {
+	customer.ApplicationID = appID
+
+	if channelIDOrName != "" {
+		channel, err := s.findChannel(ctx, appID, channelIDOrName)
+		if err != nil {
+			return nil, err
+		}
+		update.ChannelID = channel.ID
+		if channel.ID != customer.ChannelID {
+			customer.ChannelName = channel.Name
+		}
+	}
+
+	changed, err := update.Apply(&customer)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return jsonResult(ctx, updateCustomerResult{Customer: &customer, Changed: []string{}})
+	}
+	if dryRun {
+		action := fmt.Sprintf("update %s of customer '%s' (%s)", strings.Join(changed, ", "), customer.Name, customer.ID)
+		return dryRunResult(ctx, action, updateCustomerResult{Customer: &customer, Changed: changed})
+	}
+
+	updated, err := s.customers.Update(ctx, &customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResult(ctx, updateCustomerResult{Customer: updated, Changed: changed})
+}
+
+// Customer Download Tools
+
+// customerDownloads lists the artifacts a customer can download from the download portal.
+// Unavailable counts the artifacts that are listed but not ready, such as airgap bundles
+// that are still building or failed to build.
+type customerDownloads struct {
+	AppID        string            `json:"app_id"`
+	CustomerID   string            `json:"customer_id"`
+	CustomerName string            `json:"customer_name"`
+	ChannelID    string            `json:"channel_id,omitempty"`
+	Downloads    []models.Download `json:"downloads"`
+	Unavailable  int               `json:"unavailable"`
+}
+
+// defineListCustomerDownloadsTool creates the list_customer_downloads tool definition.
+// Lists what a customer's download portal offers, such as to check what support promised.
+func (s *Server) defineListCustomerDownloadsTool() toolDefinition {
+	tool := mcp.NewTool("list_customer_downloads",
+		mcp.WithDescription("List the artifacts a customer can download from the download portal: their "+
+			"license, airgap bundles, installers, and Helm charts, with the release each belongs to and "+
+			"whether it is ready. Use it to verify a customer's portal has what support promised."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only list artifacts of this kind"),
+			mcp.Enum(models.ValidDownloadTypes...),
+		),
+		outputSchema[customerDownloads](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_customer_downloads tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		downloadType := request.GetString("type", "")
+		if downloadType != "" && !slices.Contains(models.ValidDownloadTypes, downloadType) {
+			return nil, fmt.Errorf("type must be one of: %s", strings.Join(models.ValidDownloadTypes, ", "))
+		}
+
+		customer, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := s.customers.ListDownloads(ctx, customer.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := customerDownloads{
+			AppID:        appID,
+			CustomerID:   customer.ID,
+			CustomerName: customer.Name,
+			ChannelID:    customer.ChannelID,
+			Downloads:    []models.Download{},
+		}
+		for _, download := range list.Downloads {
+			if downloadType != "" && download.Type != downloadType {
+				continue
+			}
+			result.Downloads = append(result.Downloads, download)
+			if !download.IsAvailable() {
+				result.Unavailable++
+			}
+		}
+		slices.SortStableFunc(result.Downloads, func(a, b models.Download) int {
+			if c := strings.Compare(a.Type, b.Type); c != 0 {
+				return c
+			}
+			return cmp.Compare(b.ReleaseSequence, a.ReleaseSequence)
+		})
+
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
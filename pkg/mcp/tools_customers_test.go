@@ -0,0 +1,460 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testArchiveAPI serves an application with an active and an archived customer, the active
+// customer's download portal, and records archive and unarchive calls
+type testArchiveAPI struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	changes []string
+}
+
+func newTestArchiveAPI(t *testing.T) *testArchiveAPI {
+	t.Helper()
+
+	vendorAPI := &testArchiveAPI{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"totalCustomers": 2, "customers": [
+			{"id": "customer-1", "name": "Acme", "type": "trial"},
+			{"id": "customer-2", "name": "Globex", "type": "trial", "isArchived": true}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/customer/customer-1/downloads", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"downloads": [
+			{"id": "download-1", "type": "airgap_bundle", "name": "1.1.0 airgap bundle", "releaseSequence": 2},
+			{"id": "download-2", "type": "license", "name": "Acme license"},
+			{"id": "download-3", "type": "airgap_bundle", "name": "1.2.0 airgap bundle", "releaseSequence": 3,
+			 "status": "building"}
+		]}`)
+	})
+	mux.HandleFunc("POST /vendor/v3/customer/{id}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		vendorAPI.mu.Lock()
+		vendorAPI.changes = append(vendorAPI.changes, r.PathValue("action")+" "+r.PathValue("id"))
+		vendorAPI.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestCustomerArchiveTools(t *testing.T) {
+	vendorAPI := newTestArchiveAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tests := []struct {
+		name            string
+		toolName        string
+		args            map[string]any
+		confirm         bool
+		expectConfirm   bool
+		expectError     bool
+		expectInText    string
+		expectAPIChange string
+	}{
+		{
+			name:          "archive without confirmation",
+			toolName:      "archive_customer",
+			args:          map[string]any{"app_id": testAppID, "customer_id": "customer-1"},
+			expectConfirm: true,
+		},
+		{
+			name:     "archive with an unknown confirmation token",
+			toolName: "archive_customer",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", confirmationTokenArgument: "unknown",
+			},
+			expectConfirm: true,
+		},
+		{
+			name:            "archive confirmed",
+			toolName:        "archive_customer",
+			args:            map[string]any{"app_id": testAppSlug, "customer_id": "customer-1"},
+			confirm:         true,
+			expectInText:    `"changed": true`,
+			expectAPIChange: "archive customer-1",
+		},
+		{
+			name:         "archive an archived customer",
+			toolName:     "archive_customer",
+			args:         map[string]any{"app_id": testAppID, "customer_id": "customer-2"},
+			expectInText: `"changed": false`,
+		},
+		{
+			name:          "unarchive without confirmation",
+			toolName:      "unarchive_customer",
+			args:          map[string]any{"app_id": testAppID, "customer_id": "customer-2"},
+			expectConfirm: true,
+		},
+		{
+			name:            "unarchive confirmed",
+			toolName:        "unarchive_customer",
+			args:            map[string]any{"app_id": testAppID, "customer_id": "customer-2"},
+			confirm:         true,
+			expectInText:    `"is_archived": false`,
+			expectAPIChange: "unarchive customer-2",
+		},
+		{
+			name:        "unknown customer",
+			toolName:    "archive_customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-9"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.changes = nil
+			vendorAPI.mu.Unlock()
+
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+
+			var result *mcp.CallToolResult
+			var err error
+			if tt.confirm {
+				result, err = callConfirmed(t, tool, tt.args)
+			} else {
+				result, err = tool.handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			}
+			switch {
+			case tt.expectConfirm:
+				var confirmErr *confirmationError
+				if !errors.As(err, &confirmErr) {
+					t.Fatalf("Expected a confirmation error, got %v", err)
+				}
+			case tt.expectError:
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			case err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case !contains(resultText(t, result), tt.expectInText):
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, resultText(t, result))
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			var want []string
+			if tt.expectAPIChange != "" {
+				want = []string{tt.expectAPIChange}
+			}
+			if !slices.Equal(vendorAPI.changes, want) {
+				t.Errorf("Expected API changes %v, got %v", want, vendorAPI.changes)
+			}
+		})
+	}
+}
+
+func TestArchiveCustomerAnnotations(t *testing.T) {
+	server := newTestServer(t, "")
+
+	tests := []struct {
+		toolName    string
+		destructive bool
+	}{
+		{toolName: "archive_customer", destructive: true},
+		{toolName: "unarchive_customer", destructive: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toolName, func(t *testing.T) {
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+
+			annotations := tool.definition.Annotations
+			if annotations.ReadOnlyHint == nil || *annotations.ReadOnlyHint {
+				t.Errorf("Expected %s to be marked as not read-only", tt.toolName)
+			}
+			if annotations.DestructiveHint == nil || *annotations.DestructiveHint != tt.destructive {
+				t.Errorf("Expected %s destructive hint %t", tt.toolName, tt.destructive)
+			}
+		})
+	}
+}
+
+// testUpdateAPI serves an application with a trial customer and two channels and records
+// customer updates
+type testUpdateAPI struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	updates []map[string]any
+}
+
+func newTestUpdateAPI(t *testing.T) *testUpdateAPI {
+	t.Helper()
+
+	vendorAPI := &testUpdateAPI{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"totalCustomers": 1, "customers": [
+			{"id": "customer-1", "name": "Acme", "email": "ops@acme.example", "channelId": "channel-stable",
+			 "expiresAt": "2025-12-31T00:00:00Z", "type": "trial", "licenseType": "trial",
+			 "entitlements": {"seat_count": "10"}, "isAirgapEnabled": true, "isGitopsSupported": true}
+		]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [
+			{"id": "channel-stable", "name": "Stable", "channelSlug": "stable"},
+			{"id": "channel-beta", "name": "Beta", "channelSlug": "beta"}
+		]}`)
+	})
+	mux.HandleFunc("PUT /vendor/v3/customer/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode update request: %v", err)
+		}
+		vendorAPI.mu.Lock()
+		vendorAPI.updates = append(vendorAPI.updates, body)
+		vendorAPI.mu.Unlock()
+
+		customer := maps.Clone(body)
+		customer["id"] = r.PathValue("id")
+		customer["license_type"] = body["type"]
+		if err := json.NewEncoder(w).Encode(map[string]any{"customer": customer}); err != nil {
+			t.Errorf("Failed to encode update response: %v", err)
+		}
+	})
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+// testCustomerUpdate returns the update request expected for the test update API's customer
+// with the given fields, which also carries the entitlements and install type flags the
+// customer keeps
+func testCustomerUpdate(fields map[string]any) map[string]any {
+	update := map[string]any{
+		"entitlementValues":                []any{map[string]any{"name": "seat_count", "value": "10"}},
+		"isGitopsSupported":                true,
+		"isAirgapEnabled":                  true,
+		"isSnapshotSupported":              false,
+		"isKotsInstallEnabled":             false,
+		"isEmbeddedClusterDownloadEnabled": false,
+		"isHelmVmDownloadEnabled":          false,
+		"isIdentityServiceSupported":       false,
+		"isGeoaxisSupported":               false,
+		"isSupportBundleUploadEnabled":     false,
+	}
+	maps.Copy(update, fields)
+	return update
+}
+
+func TestUpdateCustomerTool(t *testing.T) {
+	vendorAPI := newTestUpdateAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		expectError  bool
+		expectInText []string
+		expectUpdate map[string]any
+	}{
+		{
+			name:         "extend a trial",
+			args:         map[string]any{"app_id": testAppID, "customer_id": "customer-1", "expires_at": "2026-03-31"},
+			expectInText: []string{`"changed": [`, `"expires_at"`},
+			expectUpdate: testCustomerUpdate(map[string]any{
+				"appId": testAppID, "name": "Acme", "email": "ops@acme.example", "channelId": "channel-stable",
+				"expiresAt": "2026-03-31T00:00:00Z", "type": "trial",
+			}),
+		},
+		{
+			name: "convert to paid without expiration",
+			args: map[string]any{
+				"app_id": testAppSlug, "customer_id": "customer-1", "license_type": "paid", "expires_at": "never",
+			},
+			expectInText: []string{`"license_type"`, `"expires_at"`},
+			expectUpdate: testCustomerUpdate(map[string]any{
+				"appId": testAppID, "name": "Acme", "email": "ops@acme.example", "channelId": "channel-stable",
+				"type": "paid",
+			}),
+		},
+		{
+			name:         "move to a channel by name",
+			args:         map[string]any{"app_id": testAppID, "customer_id": "customer-1", "channel_id": "beta"},
+			expectInText: []string{`"channel_id"`},
+			expectUpdate: testCustomerUpdate(map[string]any{
+				"appId": testAppID, "name": "Acme", "email": "ops@acme.example", "channelId": "channel-beta",
+				"expiresAt": "2025-12-31T00:00:00Z", "type": "trial",
+			}),
+		},
+		{
+			name: "nothing changes",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "name": "Acme", "channel_id": "Stable",
+			},
+			expectInText: []string{`"changed": []`},
+		},
+		{
+			name:        "no changes requested",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1"},
+			expectError: true,
+		},
+		{
+			name:        "invalid email",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "email": "not-an-email"},
+			expectError: true,
+		},
+		{
+			name:        "invalid expiration",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "expires_at": "soon"},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "channel_id": "lts"},
+			expectError: true,
+		},
+		{
+			name:        "unknown customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-9", "name": "Initech"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.updates = nil
+			vendorAPI.mu.Unlock()
+
+			tool, ok := server.registry.Tool("update_customer")
+			if !ok {
+				t.Fatal("Tool 'update_customer' not found")
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("update_customer", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				for _, want := range tt.expectInText {
+					if !contains(resultText(t, result), want) {
+						t.Errorf("Expected response containing '%s', got '%s'", want, resultText(t, result))
+					}
+				}
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			var want []map[string]any
+			if tt.expectUpdate != nil {
+				want = []map[string]any{tt.expectUpdate}
+			}
+			if !reflect.DeepEqual(vendorAPI.updates, want) {
+				t.Errorf("Expected API updates %v, got %v", want, vendorAPI.updates)
+			}
+		})
+	}
+}
+
+func TestListCustomerDownloadsTool(t *testing.T) {
+	server := newTestServer(t, newTestArchiveAPI(t).URL)
+
+	tool, ok := server.registry.Tool("list_customer_downloads")
+	if !ok {
+		t.Fatal("Tool 'list_customer_downloads' not found")
+	}
+
+	tests := []struct {
+		name            string
+		args            map[string]any
+		wantIDs         []string
+		wantUnavailable int
+		expectError     bool
+	}{
+		{
+			name:            "all downloads",
+			args:            map[string]any{"app_id": testAppSlug, "customer_id": "customer-1"},
+			wantIDs:         []string{"download-3", "download-1", "download-2"},
+			wantUnavailable: 1,
+		},
+		{
+			name:    "only licenses",
+			args:    map[string]any{"app_id": testAppID, "customer_id": "customer-1", "type": "license"},
+			wantIDs: []string{"download-2"},
+		},
+		{
+			name:        "unknown type",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "type": "iso"},
+			expectError: true,
+		},
+		{
+			name:        "unknown customer",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-9"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_customer_downloads", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var downloads customerDownloads
+			if err := json.Unmarshal(resultData(t, result), &downloads); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			ids := []string{}
+			for _, download := range downloads.Downloads {
+				ids = append(ids, download.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected downloads %v, got %v", tt.wantIDs, ids)
+			}
+			if downloads.Unavailable != tt.wantUnavailable {
+				t.Errorf("Expected %d unavailable downloads, got %d", tt.wantUnavailable, downloads.Unavailable)
+			}
+			if downloads.CustomerName != "Acme" {
+				t.Errorf("Expected customer Acme, got %q", downloads.CustomerName)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Entitlement Tools
+
+// defineListLicenseFieldsTool creates the list_license_fields tool definition.
+// Lists the license fields (entitlement definitions) configured for an application.
+func (s *Server) defineListLicenseFieldsTool() toolDefinition {
+	tool := mcp.NewTool("list_license_fields",
+		mcp.WithDescription("List the license fields defined for a specific application. "+
+			"Returns each field's name, type, default value, and whether it is required or hidden."),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		outputSchema[[]models.LicenseField](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_license_fields tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := s.entitlements.ListLicenseFields(ctx, appID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list license fields: %w", err)
+		}
+
+		return envelopeResult(ctx, listOf(list.LicenseFields))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineGetCustomerEntitlementsTool creates the get_customer_entitlements tool definition.
+// Retrieves the entitlement values currently assigned to a customer.
+func (s *Server) defineGetCustomerEntitlementsTool() toolDefinition {
+	tool := mcp.NewTool("get_customer_entitlements",
+		mcp.WithDescription("Get the entitlement values assigned to a specific customer. "+
+			"Returns each license field's current value and whether it is using the field default."),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		outputSchema[api.CustomerEntitlements](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_customer_entitlements tool called", "arguments", request.GetArguments())
+
+		if _, err := s.resolveAppID(ctx, request); err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		entitlements, err := s.entitlements.GetCustomerEntitlements(ctx, customerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get customer entitlements: %w", err)
+		}
+
+		return jsonResult(ctx, entitlements)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineSetCustomerEntitlementTool creates the set_customer_entitlement tool definition.
+// Updates a single entitlement value for a customer after checking it against the field's type.
+func (s *Server) defineSetCustomerEntitlementTool() toolDefinition {
+	tool := mcp.NewTool("set_customer_entitlement",
+		mcp.WithDescription("Set an entitlement value for a specific customer, such as a seat count or "+
+			"expiration date. The value is checked against the license field's type before it is saved. With "+
+			"dry_run, the value is checked and the change described without saving it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithString("field_name",
+			mcp.Required(),
+			mcp.Description("The name of the license field to set (see list_license_fields)"),
+		),
+		mcp.WithString("value",
+			mcp.Required(),
+			mcp.Description("The new value, formatted for the field's type (e.g. 25, true, 2025-12-31)"),
+		),
+		dryRunParameter(),
+		outputSchema[models.EntitlementValue](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("set_customer_entitlement tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		fieldName, err := request.RequireString("field_name")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := request.RequireString("value")
+		if err != nil {
+			return nil, err
+		}
+
+		field, err := s.findLicenseField(ctx, appID, fieldName)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := field.ValidateValue(value); err != nil {
+			return nil, err
+		}
+
+		if s.dryRun(request) {
+			return dryRunResult(ctx, fmt.Sprintf("set %s to '%s' for customer %s", field.Name, value, customerID),
+				models.EntitlementValue{Name: field.Name, Title: field.Title, Type: field.Type, Value: value})
+		}
+
+		entitlement, err := s.entitlements.SetCustomerEntitlement(ctx, customerID, field.Name, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set customer entitlement: %w", err)
+		}
+
+		return jsonResult(ctx, entitlement)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineEvaluateEntitlementTool creates the evaluate_entitlement tool definition.
+// Decides whether a customer's entitlement is satisfied, applying field defaults, value types,
+// and license expiration so callers don't have to interpret raw license field values.
+func (s *Server) defineEvaluateEntitlementTool() toolDefinition {
+	tool := mcp.NewTool("evaluate_entitlement",
+		mcp.WithDescription("Check whether a customer's entitlement is satisfied, optionally against a threshold "+
+			"(for example, at least 25 seats). Uses the field default when the customer has no value and reports "+
+			"the entitlement as unsatisfied once the license has expired. Without a threshold, a boolean must be "+
+			"true, an integer must be positive, a date must not have passed, and a string must be set."),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithString("field_name",
+			mcp.Required(),
+			mcp.Description("The name of the license field to evaluate (see list_license_fields)"),
+		),
+		mcp.WithString("operator",
+			mcp.Description("How to compare the value with the threshold. Integer and date fields support all "+
+				"operators and default to gte; other fields support eq and ne and default to eq"),
+			mcp.Enum(models.ValidComparisons...),
+		),
+		mcp.WithString("threshold",
+			mcp.Description("The value to compare against, formatted for the field's type (e.g. 25, true, 2025-12-31)"),
+		),
+		outputSchema[models.EntitlementEvaluation](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("evaluate_entitlement tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		fieldName, err := request.RequireString("field_name")
+		if err != nil {
+			return nil, err
+		}
+
+		field, err := s.findLicenseField(ctx, appID, fieldName)
+		if err != nil {
+			return nil, err
+		}
+
+		entitlements, err := s.entitlements.GetCustomerEntitlements(ctx, customerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get customer entitlements: %w", err)
+		}
+
+		check := newEntitlementCheck(field, entitlements.Entitlements)
+		check.Operator = request.GetString("operator", "")
+		check.Threshold = request.GetString("threshold", "")
+
+		evaluation, err := check.Evaluate(time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, evaluation)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// newEntitlementCheck prepares to evaluate a field against a customer's entitlement values,
+// picking out the customer's value for the field and the license expiration
+func newEntitlementCheck(field *models.LicenseField, values []models.EntitlementValue) models.EntitlementCheck {
+	check := models.EntitlementCheck{Field: *field}
+	for i := range values {
+		if values[i].Name == field.Name {
+			check.Value = &values[i]
+		}
+		if values[i].Name == models.LicenseFieldExpiresAt {
+			check.LicenseExpiresAt = values[i].Value
+		}
+	}
+	return check
+}
+
+// findLicenseField looks up a license field definition by name for an application
+func (s *Server) findLicenseField(ctx context.Context, appID, name string) (*models.LicenseField, error) {
+	list, err := s.entitlements.ListLicenseFields(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list license fields: %w", err)
+	}
+
+	for i := range list.LicenseFields {
+		if list.LicenseFields[i].Name == name {
+			return &list.LicenseFields[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("license field '%s' not found for application '%s'", name, appID)
+}
@@ -0,0 +1,223 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testEntitlementAPI serves the application and entitlement endpoints and records entitlement updates
+type testEntitlementAPI struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	updates map[string]string
+}
+
+func newTestEntitlementAPI(t *testing.T) *testEntitlementAPI {
+	t.Helper()
+
+	vendorAPI := &testEntitlementAPI{updates: make(map[string]string)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/license-fields", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"licenseFields": [
+			{"name": "seat_count", "title": "Seats", "type": "integer", "default": "10"},
+			{"name": "expires_at", "title": "Expiration", "type": "date", "isBuiltIn": true}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/customer/customer-1/entitlements", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"customerId": "customer-1", "entitlements": [
+			{"name": "seat_count", "title": "Seats", "type": "integer", "value": "25"},
+			{"name": "expires_at", "title": "Expiration", "type": "date", "value": "2025-12-31", "isDefault": false}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/customer/customer-2/entitlements", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"customerId": "customer-2", "entitlements": [
+			{"name": "expires_at", "title": "Expiration", "type": "date", "value": "2099-12-31"}
+		]}`)
+	})
+	setEntitlement := func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		vendorAPI.mu.Lock()
+		vendorAPI.updates[r.PathValue("field")] = body.Value
+		vendorAPI.mu.Unlock()
+
+		fmt.Fprintf(w, `{"name": %q, "value": %q}`, r.PathValue("field"), body.Value)
+	}
+	mux.HandleFunc("PUT /vendor/v3/customer/customer-1/entitlements/{field}", setEntitlement)
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestEntitlementToolHandlers(t *testing.T) {
+	vendorAPI := newTestEntitlementAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tests := []struct {
+		name         string
+		toolName     string
+		args         map[string]any
+		expectError  bool
+		expectInText string
+	}{
+		{
+			name:         "list license fields by slug",
+			toolName:     "list_license_fields",
+			args:         map[string]any{"app_id": testAppSlug},
+			expectInText: `"name": "seat_count"`,
+		},
+		{
+			name:         "get customer entitlements",
+			toolName:     "get_customer_entitlements",
+			args:         map[string]any{"app_id": testAppID, "customer_id": "customer-1"},
+			expectInText: `"value": "25"`,
+		},
+		{
+			name:        "get entitlements without customer",
+			toolName:    "get_customer_entitlements",
+			args:        map[string]any{"app_id": testAppID},
+			expectError: true,
+		},
+		{
+			name:     "set seat count",
+			toolName: "set_customer_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "field_name": "seat_count", "value": "50",
+			},
+			expectInText: `"value": "50"`,
+		},
+		{
+			name:     "set expiration date",
+			toolName: "set_customer_entitlement",
+			args: map[string]any{
+				"app_id": testAppSlug, "customer_id": "customer-1", "field_name": "expires_at", "value": "2026-06-30",
+			},
+			expectInText: `"value": "2026-06-30"`,
+		},
+		{
+			name:     "reject value of the wrong type",
+			toolName: "set_customer_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "field_name": "seat_count", "value": "lots",
+			},
+			expectError: true,
+		},
+		{
+			name:     "evaluate field default",
+			toolName: "evaluate_entitlement",
+			args: map[string]any{
+				"app_id": testAppSlug, "customer_id": "customer-2", "field_name": "seat_count", "threshold": "10",
+			},
+			expectInText: `"satisfied": true`,
+		},
+		{
+			name:     "evaluate below threshold",
+			toolName: "evaluate_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-2", "field_name": "seat_count",
+				"operator": "gt", "threshold": "10",
+			},
+			expectInText: `"satisfied": false`,
+		},
+		{
+			name:     "evaluate with expired license",
+			toolName: "evaluate_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "field_name": "seat_count", "threshold": "20",
+			},
+			expectInText: `"license_expired": true`,
+		},
+		{
+			name:     "evaluate unknown field",
+			toolName: "evaluate_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-2", "field_name": "unknown_field",
+			},
+			expectError: true,
+		},
+		{
+			name:     "reject unknown field",
+			toolName: "set_customer_entitlement",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "field_name": "unknown_field", "value": "1",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, ok := server.registry.Tool(tt.toolName)
+			if !ok {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			textContent, ok := result.Content[0].(mcp.TextContent)
+			if !ok {
+				t.Fatal("Expected TextContent")
+			}
+
+			if !contains(textContent.Text, tt.expectInText) {
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, textContent.Text)
+			}
+		})
+	}
+
+	vendorAPI.mu.Lock()
+	defer vendorAPI.mu.Unlock()
+
+	if len(vendorAPI.updates) != 2 {
+		t.Errorf("Expected only valid entitlement updates to reach the API, got %v", vendorAPI.updates)
+	}
+	if _, ok := vendorAPI.updates["unknown_field"]; ok {
+		t.Error("Expected unknown field update to be rejected before calling the API")
+	}
+}
+
+func TestSetCustomerEntitlementAnnotations(t *testing.T) {
+	server := newTestServer(t, "")
+
+	tool, ok := server.registry.Tool("set_customer_entitlement")
+	if !ok {
+		t.Fatal("Tool 'set_customer_entitlement' not found")
+	}
+
+	annotations := tool.definition.Annotations
+	if annotations.ReadOnlyHint == nil || *annotations.ReadOnlyHint {
+		t.Error("Expected set_customer_entitlement to be marked as not read-only")
+	}
+	if annotations.IdempotentHint == nil || !*annotations.IdempotentHint {
+		t.Error("Expected set_customer_entitlement to be marked as idempotent")
+	}
+}
@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/releasediff"
+)
+
+// Image usage settings: how many of the latest releases are scanned, and how many are
+// fetched at once
+const (
+	defaultImageUsageReleases = 10
+	maxImageUsageReleases     = 50
+	imageUsageConcurrency     = 4
+)
+
+// digestPrefix starts image versions that are digests rather than tags
+const digestPrefix = "sha256:"
+
+// Image Tools
+
+// releaseImage is a container image a release references
+type releaseImage struct {
+	Repository string   `json:"repository"`
+	Registry   string   `json:"registry"`
+	Versions   []string `json:"versions"`
+	// ReplicatedRegistry reports that the image is served by the Replicated registry or
+	// proxy registry, so get_image_usage can list its tags
+	ReplicatedRegistry bool `json:"replicated_registry"`
+}
+
+// releaseImages lists the container images a release references
+type releaseImages struct {
+	Sequence int64          `json:"sequence"`
+	Version  string         `json:"version"`
+	Images   []releaseImage `json:"images"`
+}
+
+// imageReference records the versions of an image one release references
+type imageReference struct {
+	Sequence int64    `json:"sequence"`
+	Version  string   `json:"version"`
+	Versions []string `json:"versions"`
+}
+
+// imageUsage reports which releases reference an image and, for images in the Replicated
+// registry or proxy registry, which tags it has. MissingTags are tags releases reference
+// that the registry does not have, the usual cause of pull failures.
+type imageUsage struct {
+	Repository      string           `json:"repository"`
+	Releases        []imageReference `json:"releases"`
+	ReleasesScanned int              `json:"releases_scanned"`
+	AvailableTags   []string         `json:"available_tags,omitempty"`
+	MissingTags     []string         `json:"missing_tags,omitempty"`
+	TagsError       string           `json:"tags_error,omitempty"`
+}
+
+// defineListReleaseImagesTool creates the list_release_images tool definition.
+// Lists the container images referenced by a release's manifests.
+func (s *Server) defineListReleaseImagesTool() toolDefinition {
+	tool := mcp.NewTool("list_release_images",
+		mcp.WithDescription("List the container images a release's manifests reference, with the tags or "+
+			"digests each is referenced at and its registry. Images served by the Replicated registry or "+
+			"proxy registry are marked, and get_image_usage can check their tags. Templated image "+
+			"references are skipped because they are only resolved at install time."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithNumber("sequence",
+			mcp.Required(),
+			mcp.Description("The sequence number of the release"),
+			mcp.Min(1),
+		),
+		outputSchema[releaseImages](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_release_images tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		sequence, err := request.RequireInt("sequence")
+		if err != nil {
+			return nil, err
+		}
+
+		release, err := s.releases.GetRelease(ctx, appID, int64(sequence))
+		if err != nil {
+			return nil, err
+		}
+
+		images, err := releasediff.Images(release)
+		if err != nil {
+			return nil, err
+		}
+
+		result := releaseImages{Sequence: release.Sequence, Version: release.Version, Images: []releaseImage{}}
+		for _, image := range images {
+			registry, _ := api.SplitRepository(image.Repository)
+			result.Images = append(result.Images, releaseImage{
+				Repository:         image.Repository,
+				Registry:           registry,
+				Versions:           image.Versions,
+				ReplicatedRegistry: api.IsReplicatedRegistry(registry),
+			})
+		}
+
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineGetImageUsageTool creates the get_image_usage tool definition.
+// Reports which releases reference an image and which of its tags the registry has.
+func (s *Server) defineGetImageUsageTool() toolDefinition {
+	tool := mcp.NewTool("get_image_usage",
+		mcp.WithDescription("Report which of an application's latest releases reference a container image "+
+			"and at which tags, and for images in the Replicated registry or proxy registry, the tags the "+
+			"registry has and any referenced tags it is missing. Useful for debugging image pull failures."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("image",
+			mcp.Required(),
+			mcp.Description("The image repository, such as registry.replicated.com/acme/api; a tag or digest "+
+				"is ignored"),
+		),
+		mcp.WithNumber("releases",
+			mcp.Description(fmt.Sprintf("How many of the latest releases to scan (1-%d, default %d)",
+				maxImageUsageReleases, defaultImageUsageReleases)),
+			mcp.Min(minLimit),
+			mcp.Max(maxImageUsageReleases),
+		),
+		outputSchema[imageUsage](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_image_usage tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		image, err := request.RequireString("image")
+		if err != nil {
+			return nil, err
+		}
+		repository, _ := releasediff.SplitImage(strings.TrimSpace(image))
+
+		releases, err := s.latestReleases(ctx, appID, request.GetInt("releases", defaultImageUsageReleases))
+		if err != nil {
+			return nil, err
+		}
+
+		usage := imageUsage{Repository: repository, Releases: []imageReference{}, ReleasesScanned: len(releases)}
+		for _, release := range releases {
+			if versions := imageVersions(release, repository); len(versions) > 0 {
+				usage.Releases = append(usage.Releases, imageReference{
+					Sequence: release.Sequence, Version: release.Version, Versions: versions,
+				})
+			}
+		}
+
+		s.addImageTags(ctx, &usage)
+		return jsonResult(ctx, usage)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// latestReleases fetches the manifests of an application's latest releases, newest first
+func (s *Server) latestReleases(ctx context.Context, appID string, count int) ([]*models.Release, error) {
+	list, err := s.releases.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	sequences := make([]int64, 0, len(list.Releases))
+	for i := range list.Releases {
+		sequences = append(sequences, list.Releases[i].Sequence)
+	}
+	slices.SortFunc(sequences, func(a, b int64) int { return cmp.Compare(b, a) })
+	sequences = sequences[:min(count, len(sequences))]
+
+	releases := make([]*models.Release, len(sequences))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(imageUsageConcurrency)
+	for i, sequence := range sequences {
+		group.Go(func() error {
+			release, err := s.releases.GetRelease(groupCtx, appID, sequence)
+			releases[i] = release
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// imageVersions returns the versions of a repository a release references, matching
+// repositories the way a container runtime resolves them
+func imageVersions(release *models.Release, repository string) []string {
+	images, err := releasediff.Images(release)
+	if err != nil {
+		return nil
+	}
+
+	host, name := api.SplitRepository(repository)
+	var versions []string
+	for _, image := range images {
+		imageHost, imageName := api.SplitRepository(image.Repository)
+		if imageHost == host && strings.EqualFold(imageName, name) {
+			versions = append(versions, image.Versions...)
+		}
+	}
+	return versions
+}
+
+// addImageTags lists the tags of an image in the Replicated registry or proxy registry and
+// reports the referenced tags it does not have. Tags of other registries are not listed.
+func (s *Server) addImageTags(ctx context.Context, usage *imageUsage) {
+	tags, err := s.images.ListTags(ctx, usage.Repository)
+	if err != nil {
+		if errors.Is(err, api.ErrRegistryNotSupported) {
+			usage.TagsError = "tags are only listed for images in the Replicated registry or proxy registry"
+		} else {
+			usage.TagsError = err.Error()
+		}
+		return
+	}
+
+	usage.AvailableTags = tags.Tags
+	for _, reference := range usage.Releases {
+		for _, version := range reference.Versions {
+			if strings.HasPrefix(version, digestPrefix) || slices.Contains(tags.Tags, version) ||
+				slices.Contains(usage.MissingTags, version) {
+				continue
+			}
+			usage.MissingTags = append(usage.MissingTags, version)
+		}
+	}
+	slices.Sort(usage.MissingTags)
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestListReleaseImagesTool(t *testing.T) {
+	server := newTestServer(t, newTestReleaseAPI(t).URL)
+
+	tool, ok := server.registry.Tool("list_release_images")
+	if !ok {
+		t.Fatal("Tool 'list_release_images' not found")
+	}
+
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("list_release_images",
+		map[string]any{"app_id": testAppSlug, "sequence": 2}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var images releaseImages
+	if err := json.Unmarshal(resultData(t, result), &images); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if images.Sequence != 2 || len(images.Images) != 1 {
+		t.Fatalf("Expected one image in release 2, got %+v", images)
+	}
+	image := images.Images[0]
+	if image.Repository != "example/web" || image.Registry != "docker.io" || image.ReplicatedRegistry ||
+		len(image.Versions) != 1 || image.Versions[0] != "1.1" {
+		t.Errorf("Unexpected image: %+v", image)
+	}
+
+	if _, err := tool.handler(context.Background(), createMockCallToolRequest("list_release_images",
+		map[string]any{"app_id": testAppID, "sequence": 9})); err == nil {
+		t.Error("Expected error for an unknown release")
+	}
+}
+
+func TestGetImageUsageTool(t *testing.T) {
+	server := newTestServer(t, newTestReleaseAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_image_usage")
+	if !ok {
+		t.Fatal("Tool 'get_image_usage' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		wantSequences []int64
+		wantScanned   int
+	}{
+		{
+			name:          "every release",
+			args:          map[string]any{"app_id": testAppSlug, "image": "docker.io/example/web:1.1"},
+			wantSequences: []int64{2, 1},
+			wantScanned:   2,
+		},
+		{
+			name:          "latest release only",
+			args:          map[string]any{"app_id": testAppID, "image": "example/web", "releases": 1},
+			wantSequences: []int64{2},
+			wantScanned:   1,
+		},
+		{
+			name:        "unreferenced image",
+			args:        map[string]any{"app_id": testAppID, "image": "example/worker"},
+			wantScanned: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_image_usage", tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var usage imageUsage
+			if err := json.Unmarshal(resultData(t, result), &usage); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if usage.ReleasesScanned != tt.wantScanned || len(usage.Releases) != len(tt.wantSequences) {
+				t.Fatalf("Expected releases %v of %d scanned, got %+v", tt.wantSequences, tt.wantScanned, usage)
+			}
+			for i, sequence := range tt.wantSequences {
+				if usage.Releases[i].Sequence != sequence {
+					t.Errorf("Expected release %d at %d, got %+v", sequence, i, usage.Releases[i])
+				}
+			}
+			if usage.TagsError == "" || usage.AvailableTags != nil {
+				t.Errorf("Expected tags not to be listed for a Docker Hub image, got %+v", usage)
+			}
+		})
+	}
+}
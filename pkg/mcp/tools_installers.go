@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/releasediff"
+)
+
+// Installer Tools
+
+// kurlInstaller is a kURL installer spec with the add-on versions it pins
+type kurlInstaller struct {
+	Sequence   int64                   `json:"sequence"`
+	ChannelIDs []string                `json:"channel_ids"`
+	AddOns     []models.InstallerAddOn `json:"add_ons"`
+	CreatedAt  time.Time               `json:"created_at"`
+	YAML       string                  `json:"yaml"`
+}
+
+// embeddedClusterConfig is the embedded cluster config a release ships, and the channel
+// it was looked up through, if any
+type embeddedClusterConfig struct {
+	ChannelID      string                             `json:"channel_id,omitempty"`
+	Sequence       int64                              `json:"sequence"`
+	ReleaseVersion string                             `json:"release_version"`
+	Config         *releasediff.EmbeddedClusterConfig `json:"config"`
+}
+
+// defineListKurlInstallersTool creates the list_kurl_installers tool definition.
+// Lists an application's kURL installer specs and the add-on versions each pins.
+func (s *Server) defineListKurlInstallersTool() toolDefinition {
+	tool := mcp.NewTool("list_kurl_installers",
+		mcp.WithDescription("List an application's kURL installer specs, newest first, with the channels "+
+			"that ship each one and the Kubernetes, container runtime, and other add-on versions it pins. "+
+			"Limit to a channel to see which kURL versions it ships."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the list to"),
+		),
+		outputSchema[[]kurlInstaller](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_kurl_installers tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		channelID := ""
+		if idOrName := request.GetString("channel_id", ""); idOrName != "" {
+			channel, err := s.findChannel(ctx, appID, idOrName)
+			if err != nil {
+				return nil, err
+			}
+			channelID = channel.ID
+		}
+
+		list, err := s.installers.ListInstallers(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+
+		installers := []kurlInstaller{}
+		for i := range list.Installers {
+			installer := &list.Installers[i]
+			if channelID != "" && !installer.ShippedBy(channelID) {
+				continue
+			}
+
+			addOns, err := installer.AddOns()
+			if err != nil {
+				return nil, err
+			}
+			installers = append(installers, kurlInstaller{
+				Sequence:   installer.Sequence,
+				ChannelIDs: append([]string{}, installer.ChannelIDs...),
+				AddOns:     addOns,
+				CreatedAt:  installer.CreatedAt,
+				YAML:       installer.YAML,
+			})
+		}
+		slices.SortFunc(installers, func(a, b kurlInstaller) int { return cmp.Compare(b.Sequence, a.Sequence) })
+
+		return envelopeResult(ctx, listOf(installers))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineGetEmbeddedClusterConfigTool creates the get_embedded_cluster_config tool definition.
+// Finds the embedded cluster Config resource in a release or a channel's current release.
+func (s *Server) defineGetEmbeddedClusterConfigTool() toolDefinition {
+	tool := mcp.NewTool("get_embedded_cluster_config",
+		mcp.WithDescription("Get the embedded cluster Config a release ships: the embedded cluster version "+
+			"it pins and its full spec. Give a channel to inspect the channel's current release, or a "+
+			"release sequence."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel_id",
+			mcp.Description("The unique identifier, slug, or name of a channel whose current release to "+
+				"inspect; required unless sequence is given"),
+		),
+		mcp.WithNumber("sequence",
+			mcp.Description("The sequence number of the release to inspect; required unless channel_id "+
+				"is given"),
+			mcp.Min(1),
+		),
+		outputSchema[embeddedClusterConfig](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_embedded_cluster_config tool called",
+			"arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		result := embeddedClusterConfig{Sequence: int64(request.GetInt("sequence", 0))}
+		if idOrName := request.GetString("channel_id", ""); idOrName != "" {
+			channel, err := s.findChannel(ctx, appID, idOrName)
+			if err != nil {
+				return nil, err
+			}
+			if channel.ReleaseSequence == 0 {
+				return nil, fmt.Errorf("channel '%s' has no release", channel.Name)
+			}
+			result.ChannelID = channel.ID
+			result.Sequence = channel.ReleaseSequence
+		}
+		if result.Sequence == 0 {
+			return nil, fmt.Errorf("either channel_id or sequence is required")
+		}
+
+		release, err := s.releases.GetRelease(ctx, appID, result.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		result.ReleaseVersion = release.Version
+
+		if result.Config, err = releasediff.FindEmbeddedClusterConfig(release); err != nil {
+			return nil, err
+		}
+		if result.Config == nil {
+			return nil, fmt.Errorf("release %d does not ship an embedded cluster config", result.Sequence)
+		}
+
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
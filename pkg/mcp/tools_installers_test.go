@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testEmbeddedClusterRelease is the manifest of release 2, which ships an embedded cluster config
+const testEmbeddedClusterRelease = "apiVersion: embeddedcluster.replicated.com/v1beta1\nkind: Config\n" +
+	"spec:\n  version: 2.1.3+k8s-1.30\n"
+
+// newTestInstallerAPI serves two kURL installers and two releases. The Stable channel ships
+// installer 2 and release 2, which has an embedded cluster config; Beta ships installer 1
+// and release 1, which does not; Unstable has no release.
+func newTestInstallerAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [
+			{"id": "channel-stable", "name": "Stable", "channelSlug": "stable", "releaseSequence": 2},
+			{"id": "channel-beta", "name": "Beta", "channelSlug": "beta", "releaseSequence": 1},
+			{"id": "channel-unstable", "name": "Unstable", "channelSlug": "unstable"}
+		]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/installers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"installers": [
+			{"appId": "test-app-123", "sequence": 1, "channelIds": ["channel-beta"],
+			 "yaml": "kind: Installer\nspec:\n  kubernetes:\n    version: 1.28.x\n"},
+			{"appId": "test-app-123", "sequence": 2, "channelIds": ["channel-stable"],
+			 "yaml": "kind: Installer\nspec:\n  kubernetes:\n    version: 1.29.x\n  containerd:\n    version: 1.6.x\n"}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/"+testAppID+"/release/{sequence}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("sequence") {
+		case "1":
+			fmt.Fprintf(w, `{"release": {"sequence": 1, "version": "1.0.0", "config": %q}}`, "kind: Deployment\n")
+		case "2":
+			fmt.Fprintf(w, `{"release": {"sequence": 2, "version": "1.1.0", "config": %q}}`, testEmbeddedClusterRelease)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListKurlInstallersTool(t *testing.T) {
+	server := newTestServer(t, newTestInstallerAPI(t).URL)
+
+	tool, ok := server.registry.Tool("list_kurl_installers")
+	if !ok {
+		t.Fatal("Tool 'list_kurl_installers' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		wantSequences []int64
+		expectError   bool
+	}{
+		{name: "every installer", args: map[string]any{"app_id": testAppSlug}, wantSequences: []int64{2, 1}},
+		{name: "one channel", args: map[string]any{"app_id": testAppID, "channel_id": "Beta"}, wantSequences: []int64{1}},
+		{name: "unknown channel", args: map[string]any{"app_id": testAppID, "channel_id": "lts"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_kurl_installers", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var installers []kurlInstaller
+			if err := json.Unmarshal(resultData(t, result), &installers); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if len(installers) != len(tt.wantSequences) {
+				t.Fatalf("Expected installers %v, got %+v", tt.wantSequences, installers)
+			}
+			for i, sequence := range tt.wantSequences {
+				if installers[i].Sequence != sequence {
+					t.Errorf("Expected installer %d at %d, got %+v", sequence, i, installers[i])
+				}
+			}
+		})
+	}
+
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("list_kurl_installers",
+		map[string]any{"app_id": testAppID, "channel_id": "stable"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if text := resultText(t, result); !strings.Contains(text, `"name": "kubernetes"`) ||
+		!strings.Contains(text, `"version": "1.29.x"`) {
+		t.Errorf("Expected the Stable installer's add-on versions, got %s", text)
+	}
+}
+
+func TestGetEmbeddedClusterConfigTool(t *testing.T) {
+	server := newTestServer(t, newTestInstallerAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_embedded_cluster_config")
+	if !ok {
+		t.Fatal("Tool 'get_embedded_cluster_config' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		wantChannelID string
+		expectError   bool
+	}{
+		{
+			name:          "channel's current release",
+			args:          map[string]any{"app_id": testAppSlug, "channel_id": "stable"},
+			wantChannelID: "channel-stable",
+		},
+		{name: "release sequence", args: map[string]any{"app_id": testAppID, "sequence": 2}},
+		{
+			name:        "release without a config",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "beta"},
+			expectError: true,
+		},
+		{
+			name:        "channel without a release",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "unstable"},
+			expectError: true,
+		},
+		{name: "neither channel nor sequence", args: map[string]any{"app_id": testAppID}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(),
+				createMockCallToolRequest("get_embedded_cluster_config", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var config embeddedClusterConfig
+			if err := json.Unmarshal(resultData(t, result), &config); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if config.ChannelID != tt.wantChannelID || config.Sequence != 2 || config.ReleaseVersion != "1.1.0" ||
+				config.Config == nil || config.Config.Version != "2.1.3+k8s-1.30" {
+				t.Errorf("Unexpected embedded cluster config: %+v", config)
+			}
+		})
+	}
+}
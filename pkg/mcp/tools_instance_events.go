@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// instanceEvents reports what happened to a customer's instance, newest first. LastCheckinAt
+// and LastUpgradeAt are taken from all of the instance's events, whatever the time range and
+// type the events are limited to.
+type instanceEvents struct {
+	AppID         string                 `json:"app_id"`
+	CustomerID    string                 `json:"customer_id"`
+	CustomerName  string                 `json:"customer_name"`
+	InstanceID    string                 `json:"instance_id"`
+	VersionLabel  string                 `json:"version_label"`
+	LastCheckinAt *time.Time             `json:"last_checkin_at,omitempty"`
+	LastUpgradeAt *time.Time             `json:"last_upgrade_at,omitempty"`
+	Events        []models.InstanceEvent `json:"events"`
+}
+
+// Instance Event Tools
+
+// defineListInstanceEventsTool creates the list_instance_events tool definition.
+// Lists an instance's check-ins, version changes, and status changes for troubleshooting timelines.
+func (s *Server) defineListInstanceEventsTool() toolDefinition {
+	tool := mcp.NewTool("list_instance_events",
+		mcp.WithDescription("List the recent events of a customer's instance, newest first: check-ins, "+
+			"version changes (with the release installed and the one it replaced), and status changes. Also "+
+			"reports when the instance last checked in and last upgraded. Use it to build a troubleshooting "+
+			"timeline, such as when a customer last upgraded or when an instance stopped being ready."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the customer"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Description("The unique identifier of the instance; optional when the customer has one instance"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only list events of this kind"),
+			mcp.Enum(models.ValidInstanceEventTypes...),
+		),
+		withTimeRange("Only list events that occurred within this range"),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of events to return, most recent first (1-100)"),
+			mcp.Min(minLimit),
+			mcp.Max(maxListLimit),
+		),
+		outputSchema[instanceEvents](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_instance_events tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		customerID, err := request.RequireString("customer_id")
+		if err != nil {
+			return nil, err
+		}
+
+		eventType := request.GetString("type", "")
+		if eventType != "" && !slices.Contains(models.ValidInstanceEventTypes, eventType) {
+			return nil, fmt.Errorf("type must be one of: %s", strings.Join(models.ValidInstanceEventTypes, ", "))
+		}
+
+		occurred, err := requestTimeRange(request)
+		if err != nil {
+			return nil, err
+		}
+
+		customer, err := s.findCustomer(ctx, appID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		instance, err := customerInstance(customer, request.GetString("instance_id", ""))
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := s.customers.ListInstanceEvents(ctx, customer.ID, instance.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := instanceEvents{
+			AppID:         appID,
+			CustomerID:    customer.ID,
+			CustomerName:  customer.Name,
+			InstanceID:    instance.ID,
+			VersionLabel:  instance.VersionLabel,
+			LastCheckinAt: instance.LastCheckinAt,
+			Events:        []models.InstanceEvent{},
+		}
+		for i := range list.Events {
+			event := &list.Events[i]
+			if event.Type == models.InstanceEventCheckin && laterThan(event.OccurredAt, result.LastCheckinAt) {
+				result.LastCheckinAt = &event.OccurredAt
+			}
+			if event.IsUpgrade() && laterThan(event.OccurredAt, result.LastUpgradeAt) {
+				result.LastUpgradeAt = &event.OccurredAt
+			}
+			if eventType != "" && event.Type != eventType {
+				continue
+			}
+			if occurred != nil && !occurred.Contains(event.OccurredAt) {
+				continue
+			}
+			result.Events = append(result.Events, *event)
+		}
+		slices.SortStableFunc(result.Events, func(a, b models.InstanceEvent) int {
+			return b.OccurredAt.Compare(a.OccurredAt)
+		})
+
+		response := envelope{Data: &result}
+		if matched, limit := len(result.Events), request.GetInt("limit", maxListLimit); matched > limit {
+			result.Events = result.Events[:limit]
+			response.warn("only the %d most recent of %d matching events are listed; narrow the time range "+
+				"or type to see earlier ones", limit, matched)
+		}
+		return envelopeResult(ctx, response)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// customerInstance returns the customer's instance with the given ID or, when no ID is given,
+// the customer's only instance
+func customerInstance(customer *models.Customer, instanceID string) (*models.Instance, error) {
+	if instanceID != "" {
+		for i := range customer.Instances {
+			if customer.Instances[i].ID == instanceID {
+				return &customer.Instances[i], nil
+			}
+		}
+		return nil, fmt.Errorf("instance '%s' not found for customer '%s'", instanceID, customer.ID)
+	}
+
+	switch len(customer.Instances) {
+	case 0:
+		return nil, fmt.Errorf("customer '%s' has no instances", customer.ID)
+	case 1:
+		return &customer.Instances[0], nil
+	}
+	ids := make([]string, 0, len(customer.Instances))
+	for _, instance := range customer.Instances {
+		ids = append(ids, instance.ID)
+	}
+	return nil, fmt.Errorf("customer '%s' has %d instances (%s); instance_id is required",
+		customer.ID, len(ids), strings.Join(ids, ", "))
+}
+
+// laterThan reports whether t is after latest, or latest is not set
+func laterThan(t time.Time, latest *time.Time) bool {
+	return latest == nil || t.After(*latest)
+}
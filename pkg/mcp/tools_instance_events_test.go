@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// fakeInstanceEvents serves customers with none, one, and two instances, and the events of
+// the single instance
+type fakeInstanceEvents struct {
+	customersService
+}
+
+func (fakeInstanceEvents) ListCustomers(context.Context, string) (*api.CustomerList, error) {
+	return &api.CustomerList{TotalCount: 3, Customers: []models.Customer{
+		{ID: "customer-1", Name: "Acme", Instances: []models.Instance{
+			{ID: "instance-1", VersionLabel: "1.2.0", ReleaseSequence: 3, IsActive: true},
+		}},
+		{ID: "customer-2", Name: "Globex", Instances: []models.Instance{{ID: "instance-2"}, {ID: "instance-3"}}},
+		{ID: "customer-3", Name: "Initech"},
+	}}, nil
+}
+
+func (fakeInstanceEvents) ListInstanceEvents(
+	_ context.Context, customerID, instanceID string,
+) (*api.InstanceEventList, error) {
+	if customerID != "customer-1" || instanceID != "instance-1" {
+		return nil, &api.Error{StatusCode: http.StatusNotFound, Message: "Not Found"}
+	}
+	at := func(value string) time.Time {
+		occurred, _ := time.Parse(time.RFC3339, value)
+		return occurred
+	}
+	return &api.InstanceEventList{Events: []models.InstanceEvent{
+		{Type: "version_change", OccurredAt: at("2025-01-05T10:00:00Z"), VersionLabel: "1.1.0",
+			ReleaseSequence: 2, PreviousVersionLabel: "1.0.0", PreviousReleaseSequence: 1},
+		{Type: "checkin", OccurredAt: at("2025-01-20T10:00:00Z"), ReleaseSequence: 3},
+		{Type: "version_change", OccurredAt: at("2025-01-10T10:00:00Z"), VersionLabel: "1.2.0",
+			ReleaseSequence: 3, PreviousVersionLabel: "1.1.0", PreviousReleaseSequence: 2},
+		{Type: "version_change", OccurredAt: at("2025-01-12T10:00:00Z"), VersionLabel: "1.1.0",
+			ReleaseSequence: 2, PreviousVersionLabel: "1.2.0", PreviousReleaseSequence: 3},
+		{Type: "status_change", OccurredAt: at("2025-01-12T09:00:00Z"), Status: "degraded", PreviousStatus: "ready"},
+		{Type: "checkin", OccurredAt: at("2025-01-15T10:00:00Z"), ReleaseSequence: 2},
+	}}, nil
+}
+
+func TestListInstanceEventsTool(t *testing.T) {
+	server := newTestServerWithServices(t, vendorapi.Services{Customers: fakeInstanceEvents{}})
+
+	tool, ok := server.registry.Tool("list_instance_events")
+	if !ok {
+		t.Fatal("Tool 'list_instance_events' not found")
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		expectError  bool
+		wantTimes    []string
+		wantWarnings int
+	}{
+		{
+			name: "all events of the only instance",
+			args: map[string]any{"app_id": testAppSlug, "customer_id": "customer-1"},
+			wantTimes: []string{
+				"2025-01-20T10:00:00Z", "2025-01-15T10:00:00Z", "2025-01-12T10:00:00Z",
+				"2025-01-12T09:00:00Z", "2025-01-10T10:00:00Z", "2025-01-05T10:00:00Z",
+			},
+		},
+		{
+			name: "version changes in a time range",
+			args: map[string]any{
+				"app_id": testAppID, "customer_id": "customer-1", "instance_id": "instance-1",
+				"type": "version_change", "time_range": "2025-01-08/2025-01-31",
+			},
+			wantTimes: []string{"2025-01-12T10:00:00Z", "2025-01-10T10:00:00Z"},
+		},
+		{
+			name:         "most recent events",
+			args:         map[string]any{"app_id": testAppID, "customer_id": "customer-1", "limit": 2},
+			wantTimes:    []string{"2025-01-20T10:00:00Z", "2025-01-15T10:00:00Z"},
+			wantWarnings: 1,
+		},
+		{
+			name:        "unknown type",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "type": "reboot"},
+			expectError: true,
+		},
+		{
+			name:        "invalid time range",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "time_range": "lately"},
+			expectError: true,
+		},
+		{
+			name:        "several instances without an instance ID",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-2"},
+			expectError: true,
+		},
+		{
+			name:        "customer without instances",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-3"},
+			expectError: true,
+		},
+		{
+			name:        "unknown instance",
+			args:        map[string]any{"app_id": testAppID, "customer_id": "customer-1", "instance_id": "instance-9"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_instance_events", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			response := decodeEnvelope[instanceEvents](t, result)
+			times := []string{}
+			for _, event := range response.Data.Events {
+				times = append(times, event.OccurredAt.Format(time.RFC3339))
+			}
+			if !slices.Equal(times, tt.wantTimes) {
+				t.Errorf("Expected events at %v, got %v", tt.wantTimes, times)
+			}
+			if len(response.Warnings) != tt.wantWarnings {
+				t.Errorf("Expected %d warnings, got %v", tt.wantWarnings, response.Warnings)
+			}
+
+			// The latest check-in and upgrade come from every event, whatever is listed
+			if at := response.Data.LastCheckinAt; at == nil || at.Format(time.RFC3339) != "2025-01-20T10:00:00Z" {
+				t.Errorf("Expected the last check-in at 2025-01-20T10:00:00Z, got %v", at)
+			}
+			if at := response.Data.LastUpgradeAt; at == nil || at.Format(time.RFC3339) != "2025-01-10T10:00:00Z" {
+				t.Errorf("Expected the last upgrade at 2025-01-10T10:00:00Z, got %v", at)
+			}
+			if response.Data.InstanceID != "instance-1" || response.Data.VersionLabel != "1.2.0" {
+				t.Errorf("Expected instance-1 running 1.2.0, got %+v", response.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/jobs"
+)
+
+// Job Tools
+
+// defineGetJobTool creates the get_job tool definition.
+// Reports the status, and once finished the result or error, of a background job.
+func (s *Server) defineGetJobTool() toolDefinition {
+	tool := mcp.NewTool("get_job",
+		mcp.WithDescription("Get the status of a background job started by another tool, such as "+
+			"export_account_snapshot. Returns the job's result once it has succeeded, or its error if it failed."),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("The job ID returned when the job was started"),
+		),
+		outputSchema[jobs.Job](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_job tool called", "arguments", request.GetArguments())
+
+		jobID, err := request.RequireString("job_id")
+		if err != nil {
+			return nil, err
+		}
+
+		job, ok := s.jobs.Get(jobID)
+		if !ok {
+			return nil, fmt.Errorf("job '%s' not found", jobID)
+		}
+
+		return jsonResult(ctx, job)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
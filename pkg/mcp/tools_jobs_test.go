@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetJobTool(t *testing.T) {
+	server := newTestServer(t, "")
+	t.Cleanup(server.jobs.Shutdown)
+
+	job, err := server.jobs.Start("test_job", func(context.Context) (any, error) {
+		return map[string]string{"path": "/tmp/archive.tar.gz"}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+	if _, err := server.jobs.Wait(context.Background(), job.ID); err != nil {
+		t.Fatalf("Failed to wait for job: %v", err)
+	}
+
+	tool, ok := server.registry.Tool("get_job")
+	if !ok {
+		t.Fatal("Tool 'get_job' not found")
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		expectError  bool
+		expectInText []string
+	}{
+		{
+			name:         "finished job",
+			args:         map[string]any{"job_id": job.ID},
+			expectInText: []string{`"status": "succeeded"`, "/tmp/archive.tar.gz"},
+		},
+		{
+			name:        "unknown job",
+			args:        map[string]any{"job_id": "missing"},
+			expectError: true,
+		},
+		{
+			name:        "missing job ID",
+			args:        map[string]any{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_job", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			text := resultText(t, result)
+			for _, expected := range tt.expectInText {
+				if !contains(text, expected) {
+					t.Errorf("Expected response containing '%s', got '%s'", expected, text)
+				}
+			}
+		})
+	}
+}
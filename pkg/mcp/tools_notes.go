@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+)
+
+// Note Tools
+
+// defineAddNoteTool creates the add_note tool definition.
+// Attaches a free-text note to an application or customer in the server's local metadata store.
+func (s *Server) defineAddNoteTool() toolDefinition {
+	tool := mcp.NewTool("add_note",
+		mcp.WithDescription("Leave a free-text note on an application or customer (for example "+
+			"\"customer prefers airgap installs\") so it can be read back in later sessions with list_notes. "+
+			"Notes are stored by this server in its data directory, not in the Vendor Portal."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("entity_type",
+			mcp.Required(),
+			mcp.Description("The kind of entity the note is about"),
+			mcp.Enum(store.EntityTypes...),
+		),
+		mcp.WithString("entity_id",
+			mcp.Required(),
+			mcp.Description("The application ID or slug, or the customer ID"),
+		),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("The note text (up to 4096 characters)"),
+		),
+		mcp.WithString("author",
+			mcp.Description("Who is leaving the note, for example a person's name or an agent's role"),
+		),
+		outputSchema[store.Note](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("add_note tool called", "arguments", request.GetArguments())
+
+		entityType, entityID, err := s.resolveEntity(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		text, err := request.RequireString("text")
+		if err != nil {
+			return nil, err
+		}
+
+		note, err := s.stateStore().AddNote(entityType, entityID, text, request.GetString("author", ""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add note: %w", err)
+		}
+
+		return jsonResult(ctx, note)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineListNotesTool creates the list_notes tool definition.
+// Lists the notes left on an application or customer.
+func (s *Server) defineListNotesTool() toolDefinition {
+	tool := mcp.NewTool("list_notes",
+		mcp.WithDescription("List the notes left on an application or customer with add_note, oldest first. "+
+			"Check for notes before working with an entity to pick up context from earlier sessions."),
+		mcp.WithString("entity_type",
+			mcp.Required(),
+			mcp.Description("The kind of entity"),
+			mcp.Enum(store.EntityTypes...),
+		),
+		mcp.WithString("entity_id",
+			mcp.Required(),
+			mcp.Description("The application ID or slug, or the customer ID"),
+		),
+		outputSchema[[]store.Note](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_notes tool called", "arguments", request.GetArguments())
+
+		entityType, entityID, err := s.resolveEntity(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		notes, err := s.stateStore().ListNotes(entityType, entityID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list notes: %w", err)
+		}
+
+		return envelopeResult(ctx, listOf(notes))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+)
+
+func TestNoteTools(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+	server.config.DataDir = t.TempDir()
+
+	addTool, ok := server.registry.Tool("add_note")
+	if !ok {
+		t.Fatal("Tool 'add_note' not found")
+	}
+
+	calls := []struct {
+		name        string
+		args        map[string]any
+		expectError bool
+	}{
+		{
+			name: "note on an application by slug",
+			args: map[string]any{"entity_type": "application", "entity_id": testAppSlug, "text": "Uses the OCI chart"},
+		},
+		{
+			name: "note on a customer",
+			args: map[string]any{
+				"entity_type": "customer", "entity_id": "customer-1",
+				"text": "Customer prefers airgap installs", "author": "support",
+			},
+		},
+		{
+			name:        "empty text",
+			args:        map[string]any{"entity_type": "customer", "entity_id": "customer-1", "text": " "},
+			expectError: true,
+		},
+	}
+
+	for _, call := range calls {
+		t.Run(call.name, func(t *testing.T) {
+			result, err := addTool.handler(context.Background(), createMockCallToolRequest("add_note", call.args))
+			if call.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var note store.Note
+			if err := json.Unmarshal(resultData(t, result), &note); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if note.ID == "" || note.Text != call.args["text"] {
+				t.Errorf("Unexpected note: %+v", note)
+			}
+		})
+	}
+
+	listTool, ok := server.registry.Tool("list_notes")
+	if !ok {
+		t.Fatal("Tool 'list_notes' not found")
+	}
+
+	// Notes on an application are found by ID or slug
+	for _, entityID := range []string{testAppID, testAppSlug} {
+		args := map[string]any{"entity_type": "application", "entity_id": entityID}
+		result, err := listTool.handler(context.Background(), createMockCallToolRequest("list_notes", args))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var notes []store.Note
+		if err := json.Unmarshal(resultData(t, result), &notes); err != nil {
+			t.Fatalf("Failed to decode result: %v", err)
+		}
+		if len(notes) != 1 || notes[0].EntityID != testAppID || notes[0].Text != "Uses the OCI chart" {
+			t.Errorf("Expected the application's note for %s, got %+v", entityID, notes)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+const (
+	// releaseArchiveDirName is the data directory subdirectory that holds saved release archives
+	releaseArchiveDirName = "release-archives"
+	releaseArchiveDirMode = 0o750
+
+	// maxInlineArchiveBytes is the size up to which a release archive is returned in the
+	// result, base64-encoded, instead of being saved, when results have no size limit
+	maxInlineArchiveBytes = 512 << 10
+
+	// archiveResultOverhead is the room left in a result for the fields around an inline archive
+	archiveResultOverhead = 1 << 10
+)
+
+// releaseArchive is the result of download_release_archive. An archive is either returned
+// base64-encoded in Content or saved to the local file at Path.
+type releaseArchive struct {
+	AppID        string `json:"app_id"`
+	Sequence     int64  `json:"sequence"`
+	VersionLabel string `json:"version_label"`
+	Size         int64  `json:"size"`
+	ContentType  string `json:"content_type,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Content      string `json:"content,omitempty"`
+}
+
+// Release Archive Tools
+
+// defineDownloadReleaseArchiveTool creates the download_release_archive tool definition.
+// Downloads the source archive of a release, returning small archives inline and saving larger ones.
+func (s *Server) defineDownloadReleaseArchiveTool() toolDefinition {
+	tool := mcp.NewTool("download_release_archive",
+		mcp.WithDescription("Download the source archive of a release, the gzip-compressed tarball of its "+
+			"manifests exactly as shipped, by sequence, version label, or ID; give exactly one. Archives that fit "+
+			"the server's result size limit once base64-encoded, about 190 KiB by default, are returned inline; "+
+			"larger archives, or any archive with save set, are saved under the server's data directory and "+
+			"their local path is returned instead."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithNumber("sequence",
+			mcp.Description("The sequence number of the release"),
+			mcp.Min(1),
+		),
+		mcp.WithString("version",
+			mcp.Description("The version label of the release, such as 1.2.0"),
+		),
+		mcp.WithString("release_id",
+			mcp.Description("The unique identifier of the release"),
+		),
+		mcp.WithBoolean("save",
+			mcp.Description("Save the archive to a local file even when it is small enough to return (default false)"),
+		),
+		outputSchema[releaseArchive](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("download_release_archive tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		release, err := s.getRelease(ctx, appID, request)
+		if err != nil {
+			return nil, err
+		}
+
+		archive, err := s.downloadReleaseArchive(ctx, appID, release, request.GetBool("save", false))
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, archive)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// inlineArchiveLimit returns the size up to which a release archive is returned inline: the
+// largest archive whose base64 encoding, 4 bytes for every 3, fits the result size limit
+// with room for the rest of the result
+func (s *Server) inlineArchiveLimit() int64 {
+	limit := int64(s.currentConfig().MaxResultBytes)
+	if limit <= 0 {
+		return maxInlineArchiveBytes
+	}
+	return min(maxInlineArchiveBytes, max(0, (limit-archiveResultOverhead)/4*3))
+}
+
+// downloadReleaseArchive downloads a release's archive into the release archives directory.
+// An archive small enough to return, unless save is set, is read into the result and its
+// file removed; otherwise the file is kept under a name for the application and sequence,
+// replacing any earlier download of the same release.
+func (s *Server) downloadReleaseArchive(
+	ctx context.Context, appID string, release *models.Release, save bool,
+) (*releaseArchive, error) {
+	dir := filepath.Join(s.currentConfig().DataDirectory(), releaseArchiveDirName)
+	if err := os.MkdirAll(dir, releaseArchiveDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create release archive directory: %w", err)
+	}
+
+	body, err := s.releases.DownloadArchive(ctx, appID, release.Sequence, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &releaseArchive{
+		AppID:        appID,
+		Sequence:     release.Sequence,
+		VersionLabel: release.Version,
+		Size:         body.Size,
+		ContentType:  body.ContentType,
+	}
+
+	if !save && body.Size <= s.inlineArchiveLimit() {
+		defer body.Remove()
+		content, err := os.ReadFile(body.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read release archive: %w", err)
+		}
+		archive.Content = base64.StdEncoding.EncodeToString(content)
+		return archive, nil
+	}
+
+	archive.Path = filepath.Join(dir, fmt.Sprintf("%s-%d.tar.gz", appID, release.Sequence))
+	if err := os.Rename(body.Path, archive.Path); err != nil {
+		_ = body.Remove()
+		return nil, fmt.Errorf("failed to save release archive: %w", err)
+	}
+	return archive, nil
+}
@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// testLargeArchive is a release archive too large to return inline within the default result
+// size limit once base64-encoded, though under maxInlineArchiveBytes
+var testLargeArchive = strings.Repeat("x", 300<<10)
+
+// testFittingArchive is the largest release archive returned inline within the default
+// result size limit
+var testFittingArchive = strings.Repeat("x", (config.DefaultMaxResultBytes-archiveResultOverhead)/4*3)
+
+// fakeReleaseArchives serves a small archive for release 1, an archive too large to return
+// inline for release 2, no archive for release 3, and an archive just small enough to return
+// inline for release 4
+type fakeReleaseArchives struct {
+	releasesService
+}
+
+func (fakeReleaseArchives) ListReleases(context.Context, string) (*api.ReleaseList, error) {
+	return &api.ReleaseList{Releases: []models.Release{
+		{ID: "release-1", Version: "1.0.0", Sequence: 1},
+		{ID: "release-2", Version: "1.1.0", Sequence: 2},
+		{ID: "release-3", Version: "1.2.0", Sequence: 3},
+		{ID: "release-4", Version: "1.3.0", Sequence: 4},
+	}}, nil
+}
+
+func (f fakeReleaseArchives) GetRelease(ctx context.Context, appID string, sequence int64) (*models.Release, error) {
+	return f.find(ctx, appID, func(release *models.Release) bool { return release.Sequence == sequence })
+}
+
+func (f fakeReleaseArchives) GetByVersionLabel(ctx context.Context, appID, version string) (*models.Release, error) {
+	return f.find(ctx, appID, func(release *models.Release) bool { return release.Version == version })
+}
+
+// find returns the first release matching match
+func (f fakeReleaseArchives) find(
+	ctx context.Context, appID string, match func(*models.Release) bool,
+) (*models.Release, error) {
+	list, _ := f.ListReleases(ctx, appID)
+	for i := range list.Releases {
+		if match(&list.Releases[i]) {
+			return &list.Releases[i], nil
+		}
+	}
+	return nil, &api.Error{StatusCode: http.StatusNotFound, Message: "Not Found"}
+}
+
+func (fakeReleaseArchives) DownloadArchive(
+	_ context.Context, _ string, sequence int64, dir string,
+) (*api.FileBody, error) {
+	var content string
+	switch sequence {
+	case 1:
+		content = "small archive"
+	case 2:
+		content = testLargeArchive
+	case 4:
+		content = testFittingArchive
+	default:
+		return nil, &api.Error{StatusCode: http.StatusNotFound, Message: "Not Found"}
+	}
+
+	file, err := os.CreateTemp(dir, "replicated-download-*")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		return nil, err
+	}
+	return &api.FileBody{Path: file.Name(), Size: int64(len(content)), ContentType: "application/gzip"}, nil
+}
+
+func TestDownloadReleaseArchiveTool(t *testing.T) {
+	server := newTestServerWithServices(t, vendorapi.Services{Releases: fakeReleaseArchives{}})
+	server.config.MaxResultBytes = config.DefaultMaxResultBytes
+
+	tool, ok := server.registry.Tool("download_release_archive")
+	if !ok {
+		t.Fatal("Tool 'download_release_archive' not found")
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		wantContent string
+		wantSaved   string
+		expectError bool
+	}{
+		{
+			name:        "small archive returned inline",
+			args:        map[string]any{"app_id": testAppSlug, "sequence": 1},
+			wantContent: "small archive",
+		},
+		{
+			name:      "small archive saved",
+			args:      map[string]any{"app_id": testAppID, "release_id": "release-1", "save": true},
+			wantSaved: "small archive",
+		},
+		{
+			name:      "large archive saved",
+			args:      map[string]any{"app_id": testAppID, "version": "1.1.0"},
+			wantSaved: testLargeArchive,
+		},
+		{
+			name:        "archive filling the result returned inline",
+			args:        map[string]any{"app_id": testAppID, "release_id": "release-4"},
+			wantContent: testFittingArchive,
+		},
+		{
+			name:        "release without an archive",
+			args:        map[string]any{"app_id": testAppID, "sequence": 3},
+			expectError: true,
+		},
+		{
+			name:        "no release named",
+			args:        map[string]any{"app_id": testAppID},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := server.withResultLimit(tool.handler)(context.Background(),
+				createMockCallToolRequest("download_release_archive", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("Expected the archive to fit the result, got %s", resultText(t, result))
+			}
+
+			var archive releaseArchive
+			if err := json.Unmarshal(resultData(t, result), &archive); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+
+			content, _ := base64.StdEncoding.DecodeString(archive.Content)
+			if string(content) != tt.wantContent {
+				t.Errorf("Expected %d bytes of inline content, got %d", len(tt.wantContent), len(content))
+			}
+			if tt.wantSaved == "" {
+				if archive.Path != "" {
+					t.Errorf("Expected an inline archive not to be saved, got %s", archive.Path)
+				}
+			} else {
+				saved, err := os.ReadFile(archive.Path)
+				if err != nil {
+					t.Fatalf("Failed to read saved archive: %v", err)
+				}
+				if string(saved) != tt.wantSaved {
+					t.Errorf("Expected the saved archive to hold the download, got %d bytes", len(saved))
+				}
+			}
+
+			entries, _ := os.ReadDir(filepath.Join(server.currentConfig().DataDirectory(), releaseArchiveDirName))
+			for _, entry := range entries {
+				if !strings.HasSuffix(entry.Name(), ".tar.gz") {
+					t.Errorf("Expected no temporary files left behind, got %s", entry.Name())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,497 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/releasediff"
+)
+
+// Release Promotion Tools
+
+// promotionResult reports a completed release promotion
+type promotionResult struct {
+	AppID        string `json:"app_id"`
+	ChannelID    string `json:"channel_id"`
+	ChannelName  string `json:"channel_name"`
+	Sequence     int64  `json:"sequence"`
+	VersionLabel string `json:"version_label"`
+	IsRequired   bool   `json:"is_required"`
+}
+
+// definePromoteReleaseTool creates the promote_release tool definition.
+// Promotes a release to a channel after checking the channel's promotion policy, if one is configured.
+func (s *Server) definePromoteReleaseTool() toolDefinition {
+	tool := mcp.NewTool("promote_release",
+		mcp.WithDescription("Promote a release to a channel so customers on that channel can install it. "+
+			"Channels can have promotion policies (semantic version labels, release notes, or an approval "+
+			"label on the release); a promotion that breaks the policy is rejected with the specific violations. "+
+			"Promoting to the application's default channel, which new customers install from, requires "+
+			"confirmation: a call without confirmation_token fails, describes the change, and returns a token; "+
+			"once the user agrees, make the same call with the token within two minutes. "+
+			"With dry_run, the promotion is checked against the policy and described without making it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithNumber("sequence",
+			mcp.Required(),
+			mcp.Description("The sequence number of the release to promote"),
+			mcp.Min(1),
+		),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier, slug, or name of the channel"),
+		),
+		mcp.WithString("version_label",
+			mcp.Description("The version label customers see; defaults to the release's version"),
+		),
+		mcp.WithString("release_notes",
+			mcp.Description("Release notes for the promotion; defaults to the release's notes"),
+		),
+		mcp.WithBoolean("required",
+			mcp.Description("Whether customers must install this release before later ones"),
+		),
+		confirmationTokenParameter(),
+		dryRunParameter(),
+		outputSchema[promotionResult](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("promote_release tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		sequence, err := request.RequireInt("sequence")
+		if err != nil {
+			return nil, err
+		}
+
+		channelID, err := request.RequireString("channel_id")
+		if err != nil {
+			return nil, err
+		}
+
+		return s.promoteRelease(ctx, appID, channelID, int64(sequence), request)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// promoteRelease promotes a release to a channel, rejecting promotions that break the
+// channel's promotion policy before anything is sent to the API. In dry-run mode, a
+// promotion that passes the policy is previewed instead.
+func (s *Server) promoteRelease(
+	ctx context.Context,
+	appID, channelID string,
+	sequence int64,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	channel, err := s.findChannel(ctx, appID, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.findRelease(ctx, appID, sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion := models.Promotion{
+		Release:      release,
+		VersionLabel: request.GetString("version_label", release.Version),
+		ReleaseNotes: request.GetString("release_notes", release.Notes),
+	}
+	if policy, ok := s.currentConfig().PromotionPolicy(channel); ok {
+		if violations := policy.Check(promotion); len(violations) > 0 {
+			return nil, &models.PromotionPolicyError{Channel: channel.Name, Violations: violations}
+		}
+	}
+
+	result := &promotionResult{
+		AppID:        appID,
+		ChannelID:    channel.ID,
+		ChannelName:  channel.Name,
+		Sequence:     release.Sequence,
+		VersionLabel: promotion.VersionLabel,
+		IsRequired:   request.GetBool("required", false),
+	}
+	description := fmt.Sprintf("sequence %d (%s) to %s", result.Sequence, result.VersionLabel, result.ChannelName)
+	if s.dryRun(request) {
+		return dryRunResult(ctx, "promote "+description, result)
+	}
+
+	if channel.IsDefault {
+		if err := s.requireConfirmation(request, "promoting "+description+", the default channel,"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.releases.PromoteRelease(ctx, appID, release.Sequence, api.PromoteReleaseRequest{
+		ChannelIDs:   []string{channel.ID},
+		VersionLabel: result.VersionLabel,
+		ReleaseNotes: promotion.ReleaseNotes,
+		IsRequired:   result.IsRequired,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResult(ctx, result)
+}
+
+// findChannel looks up a channel of an application by ID, slug, or name (ignoring case)
+func (s *Server) findChannel(ctx context.Context, appID, idOrName string) (*models.Channel, error) {
+	list, err := s.channels.ListChannels(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.Channels {
+		if channelMatches(&list.Channels[i], idOrName) {
+			return &list.Channels[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("channel '%s' not found for application '%s'", idOrName, appID)
+}
+
+// channelMatches reports whether a channel has the given ID, slug, or name (ignoring case and
+// diacritics)
+func channelMatches(channel *models.Channel, idOrName string) bool {
+	return channel.ID == idOrName || channel.ChannelSlug == models.NormalizeSlug(idOrName) ||
+		models.SameName(channel.Name, idOrName)
+}
+
+// findRelease looks up a release of an application by sequence
+func (s *Server) findRelease(ctx context.Context, appID string, sequence int64) (*models.Release, error) {
+	list, err := s.releases.ListReleases(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.Releases {
+		if list.Releases[i].Sequence == sequence {
+			return &list.Releases[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("release sequence %d not found for application '%s'", sequence, appID)
+}
+
+// Release Comparison Tools
+
+// defineCompareReleasesTool creates the compare_releases tool definition.
+// Compares the manifests of two releases of an application.
+func (s *Server) defineCompareReleasesTool() toolDefinition {
+	tool := mcp.NewTool("compare_releases",
+		mcp.WithDescription("Compare the manifests of two releases of an application, for questions like "+
+			"\"what changed between 1.4 and 1.5\". Returns the files added, removed, and changed, the container "+
+			"images whose tags changed, and the Helm charts whose versions changed."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithNumber("from_sequence",
+			mcp.Required(),
+			mcp.Description("The sequence number of the earlier release"),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("to_sequence",
+			mcp.Required(),
+			mcp.Description("The sequence number of the later release"),
+			mcp.Min(1),
+		),
+		outputSchema[releasediff.Diff](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("compare_releases tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		fromSequence, err := request.RequireInt("from_sequence")
+		if err != nil {
+			return nil, err
+		}
+
+		toSequence, err := request.RequireInt("to_sequence")
+		if err != nil {
+			return nil, err
+		}
+
+		from, err := s.releases.GetRelease(ctx, appID, int64(fromSequence))
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := s.releases.GetRelease(ctx, appID, int64(toSequence))
+		if err != nil {
+			return nil, err
+		}
+
+		diff, err := releasediff.Compare(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, diff)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// releaseChangelog is the result of get_release_changelog, naming the channels it follows
+type releaseChangelog struct {
+	Channel      string `json:"channel,omitempty"`
+	SinceChannel string `json:"since_channel,omitempty"`
+	*api.Changelog
+}
+
+// defineGetReleaseChangelogTool creates the get_release_changelog tool definition.
+// Gathers the release notes of a sequence range, such as everything promoted to Beta since
+// the last Stable promotion, for the agent to write customer-facing notes from.
+func (s *Server) defineGetReleaseChangelogTool() toolDefinition {
+	tool := mcp.NewTool("get_release_changelog",
+		mcp.WithDescription("Gather the release notes of a range of releases, oldest first, to write "+
+			"customer-facing release notes from. The range runs from after from_sequence, or after the release "+
+			"most recently promoted to since_channel, up to to_sequence; with channel, only releases promoted to "+
+			"that channel are included, with their promotion times and version labels. For example, channel "+
+			"beta with since_channel stable lists everything promoted to Beta since the last Stable promotion. "+
+			"Draft releases are left out."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("channel",
+			mcp.Description("The unique identifier, slug, or name of a channel to limit the changelog to, such as beta"),
+		),
+		mcp.WithString("since_channel",
+			mcp.Description("The unique identifier, slug, or name of a channel whose latest promotion the "+
+				"changelog starts after, such as stable; cannot be combined with from_sequence"),
+		),
+		mcp.WithNumber("from_sequence",
+			mcp.Description("The sequence number the changelog starts after (default: the first release)"),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("to_sequence",
+			mcp.Description("The sequence number of the last release in the changelog (default: the latest)"),
+			mcp.Min(1),
+		),
+		outputSchema[releaseChangelog](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_release_changelog tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		result := releaseChangelog{}
+		query := api.ChangelogQuery{
+			FromSequence: int64(request.GetInt("from_sequence", 0)),
+			ToSequence:   int64(request.GetInt("to_sequence", 0)),
+		}
+
+		if name := request.GetString("channel", ""); name != "" {
+			channel, err := s.findChannel(ctx, appID, name)
+			if err != nil {
+				return nil, err
+			}
+			query.ChannelID, result.Channel = channel.ID, channel.Name
+		}
+
+		if name := request.GetString("since_channel", ""); name != "" {
+			channel, err := s.findChannel(ctx, appID, name)
+			if err != nil {
+				return nil, err
+			}
+			query.SinceChannelID, result.SinceChannel = channel.ID, channel.Name
+		}
+
+		result.Changelog, err = s.releases.Changelog(ctx, appID, query)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defaultChannelHistoryDepth is how many of each channel's most recent promotions
+// compare_channels looks at when no history depth is given
+const defaultChannelHistoryDepth = 10
+
+// channelHead identifies a compared channel and the release it currently ships
+type channelHead struct {
+	ChannelID       string `json:"channel_id"`
+	ChannelName     string `json:"channel_name"`
+	ReleaseSequence int64  `json:"release_sequence,omitempty"`
+}
+
+// channelComparison lists the recent releases of two channels that the other channel never
+// shipped and the manifest differences between the releases the channels currently ship
+type channelComparison struct {
+	From       channelHead             `json:"from"`
+	To         channelHead             `json:"to"`
+	OnlyInFrom []models.ChannelRelease `json:"only_in_from"`
+	OnlyInTo   []models.ChannelRelease `json:"only_in_to"`
+	HeadDiff   *releasediff.Diff       `json:"head_diff,omitempty"`
+}
+
+// defineCompareChannelsTool creates the compare_channels tool definition.
+// Compares the release histories and current releases of two channels.
+func (s *Server) defineCompareChannelsTool() toolDefinition {
+	tool := mcp.NewTool("compare_channels",
+		mcp.WithDescription("Compare two channels of an application to plan a promotion, for questions "+
+			"like \"what's in beta but not stable\". Lists the recent releases of each channel that the other "+
+			"channel never shipped, and the files, container images, and Helm charts that differ between the "+
+			"releases the channels currently ship."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("from_channel",
+			mcp.Required(),
+			mcp.Description("The unique identifier, slug, or name of the channel to compare from, such as stable"),
+		),
+		mcp.WithString("to_channel",
+			mcp.Required(),
+			mcp.Description("The unique identifier, slug, or name of the channel to compare to, such as beta"),
+		),
+		mcp.WithNumber("history",
+			mcp.Description("How many of each channel's most recent promotions to compare (default 10)"),
+			mcp.Min(minLimit),
+			mcp.Max(maxListLimit),
+		),
+		outputSchema[channelComparison](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("compare_channels tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		fromName, err := request.RequireString("from_channel")
+		if err != nil {
+			return nil, err
+		}
+
+		toName, err := request.RequireString("to_channel")
+		if err != nil {
+			return nil, err
+		}
+
+		from, err := s.findChannel(ctx, appID, fromName)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := s.findChannel(ctx, appID, toName)
+		if err != nil {
+			return nil, err
+		}
+
+		comparison, err := s.compareChannels(ctx, appID, from, to,
+			request.GetInt("history", defaultChannelHistoryDepth))
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, comparison)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// compareChannels compares the most recent promotions of two channels and diffs the releases
+// they currently ship. A recent release counts as shipped by the other channel if it appears
+// anywhere in that channel's history.
+func (s *Server) compareChannels(ctx context.Context, appID string, from, to *models.Channel,
+	depth int) (*channelComparison, error) {
+	fromHistory, err := s.channels.ListChannelReleases(ctx, appID, from.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	toHistory, err := s.channels.ListChannelReleases(ctx, appID, to.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &channelComparison{
+		From:       channelHead{ChannelID: from.ID, ChannelName: from.Name, ReleaseSequence: from.ReleaseSequence},
+		To:         channelHead{ChannelID: to.ID, ChannelName: to.Name, ReleaseSequence: to.ReleaseSequence},
+		OnlyInFrom: releasesNotShipped(fromHistory.Releases, toHistory.Releases, depth),
+		OnlyInTo:   releasesNotShipped(toHistory.Releases, fromHistory.Releases, depth),
+	}
+
+	if from.ReleaseSequence == 0 || to.ReleaseSequence == 0 {
+		return comparison, nil
+	}
+
+	fromRelease, err := s.releases.GetRelease(ctx, appID, from.ReleaseSequence)
+	if err != nil {
+		return nil, err
+	}
+
+	toRelease, err := s.releases.GetRelease(ctx, appID, to.ReleaseSequence)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison.HeadDiff, err = releasediff.Compare(fromRelease, toRelease)
+	if err != nil {
+		return nil, err
+	}
+
+	return comparison, nil
+}
+
+// releasesNotShipped returns the releases among the depth most recent promotions of a channel
+// history that never appear in another channel's history, most recent first
+func releasesNotShipped(history, other []models.ChannelRelease, depth int) []models.ChannelRelease {
+	recent := slices.Clone(history)
+	slices.SortStableFunc(recent, func(a, b models.ChannelRelease) int {
+		return b.PromotedAt.Compare(a.PromotedAt)
+	})
+	recent = recent[:min(depth, len(recent))]
+
+	shipped := make(map[int64]bool, len(other))
+	for _, release := range other {
+		shipped[release.ReleaseSequence] = true
+	}
+
+	missing := []models.ChannelRelease{}
+	for _, release := range recent {
+		if !shipped[release.ReleaseSequence] {
+			missing = append(missing, release)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,548 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/releasediff"
+)
+
+// testReleaseConfigs holds the manifests of the test releases by sequence
+var testReleaseConfigs = map[string]string{
+	"1": "kind: Deployment\nmetadata:\n  name: web\nspec:\n  image: example/web:1.0\n",
+	"2": "kind: Deployment\nmetadata:\n  name: web\nspec:\n  image: example/web:1.1\n" +
+		"---\nkind: Service\nmetadata:\n  name: web\n",
+	"3": "kind: Deployment\nmetadata:\n  name: web\nspec:\n  image: example/web:1.2\n" +
+		"---\nkind: Service\nmetadata:\n  name: web\n",
+}
+
+// serveTestRelease serves a test release with its manifests from testReleaseConfigs
+func serveTestRelease(w http.ResponseWriter, r *http.Request) {
+	config, ok := testReleaseConfigs[r.PathValue("sequence")]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+		return
+	}
+	fmt.Fprintf(w, `{"release": {"sequence": %s, "config": %q}}`, r.PathValue("sequence"), config)
+}
+
+// testReleaseAPI serves the application, channel, and release endpoints and records promotions
+type testReleaseAPI struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	promotions []api.PromoteReleaseRequest
+}
+
+func newTestReleaseAPI(t *testing.T) *testReleaseAPI {
+	t.Helper()
+
+	vendorAPI := &testReleaseAPI{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [
+			{"id": "channel-stable", "name": "Stable", "channelSlug": "stable", "isDefault": true},
+			{"id": "channel-beta", "name": "Beta", "channelSlug": "beta"}
+		]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [
+			{"id": "release-1", "version": "nightly-42", "sequence": 1},
+			{"id": "release-2", "version": "1.2.0", "sequence": 2, "notes": "Fixes",
+			 "metadata": {"qa-approved": "alice"}}
+		]}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/app/"+testAppID+"/release/{sequence}", serveTestRelease)
+	mux.HandleFunc("POST /vendor/v3/app/"+testAppID+"/release/{sequence}/promote",
+		func(w http.ResponseWriter, r *http.Request) {
+			var body api.PromoteReleaseRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			vendorAPI.mu.Lock()
+			vendorAPI.promotions = append(vendorAPI.promotions, body)
+			vendorAPI.mu.Unlock()
+
+			fmt.Fprint(w, `{}`)
+		})
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestPromoteReleaseTool(t *testing.T) {
+	vendorAPI := newTestReleaseAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+	server.config.ChannelPolicies = map[string]models.PromotionPolicy{
+		"stable": {RequireSemver: true, RequireReleaseNotes: true, ApprovalLabel: "qa-approved"},
+	}
+
+	tests := []struct {
+		name             string
+		args             map[string]any
+		confirm          bool
+		expectConfirm    bool
+		expectError      bool
+		expectViolations []string
+		expectInText     string
+	}{
+		{
+			name:         "promote to a channel without a policy",
+			args:         map[string]any{"app_id": testAppSlug, "sequence": 1, "channel_id": "beta"},
+			expectInText: `"version_label": "nightly-42"`,
+		},
+		{
+			name:          "promote to the default channel without confirmation",
+			args:          map[string]any{"app_id": testAppID, "sequence": 2, "channel_id": "Stable"},
+			expectConfirm: true,
+		},
+		{
+			name:         "promote a compliant release",
+			args:         map[string]any{"app_id": testAppID, "sequence": 2, "channel_id": "Stable"},
+			confirm:      true,
+			expectInText: `"channel_id": "channel-stable"`,
+		},
+		{
+			name: "reject a release that breaks the policy",
+			args: map[string]any{"app_id": testAppID, "sequence": 1, "channel_id": "channel-stable"},
+			expectViolations: []string{
+				models.PolicyRuleSemver, models.PolicyRuleReleaseNotes, models.PolicyRuleApprovalLabel,
+			},
+		},
+		{
+			name: "reject a non-semantic version label override",
+			args: map[string]any{
+				"app_id": testAppID, "sequence": 2, "channel_id": "stable", "version_label": "latest",
+			},
+			expectViolations: []string{models.PolicyRuleSemver},
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "sequence": 2, "channel_id": "unstable"},
+			expectError: true,
+		},
+		{
+			name:        "unknown release",
+			args:        map[string]any{"app_id": testAppID, "sequence": 9, "channel_id": "beta"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, ok := server.registry.Tool("promote_release")
+			if !ok {
+				t.Fatal("Tool 'promote_release' not found")
+			}
+
+			var result *mcp.CallToolResult
+			var err error
+			if tt.confirm {
+				result, err = callConfirmed(t, tool, tt.args)
+			} else {
+				result, err = tool.handler(context.Background(), createMockCallToolRequest("promote_release", tt.args))
+			}
+			if tt.expectConfirm {
+				var confirmErr *confirmationError
+				if !errors.As(err, &confirmErr) {
+					t.Fatalf("Expected a confirmation error, got %v", err)
+				}
+				return
+			}
+			if tt.expectError || tt.expectViolations != nil {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				checkViolations(t, err, tt.expectViolations)
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if text := resultText(t, result); !contains(text, tt.expectInText) {
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, text)
+			}
+		})
+	}
+
+	vendorAPI.mu.Lock()
+	defer vendorAPI.mu.Unlock()
+
+	if len(vendorAPI.promotions) != 2 {
+		t.Errorf("Expected only compliant promotions to reach the API, got %+v", vendorAPI.promotions)
+	}
+}
+
+// checkViolations verifies that an error reports exactly the expected promotion policy rules
+func checkViolations(t *testing.T, err error, expectRules []string) {
+	t.Helper()
+
+	var policyErr *models.PromotionPolicyError
+	if !errors.As(err, &policyErr) {
+		if expectRules != nil {
+			t.Fatalf("Expected a promotion policy error, got %v", err)
+		}
+		return
+	}
+
+	if len(policyErr.Violations) != len(expectRules) {
+		t.Fatalf("Expected violations %v, got %+v", expectRules, policyErr.Violations)
+	}
+	for i, rule := range expectRules {
+		if policyErr.Violations[i].Rule != rule {
+			t.Errorf("Expected violation %d to be %s, got %s", i, rule, policyErr.Violations[i].Rule)
+		}
+	}
+}
+
+func TestCompareReleasesTool(t *testing.T) {
+	vendorAPI := newTestReleaseAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("compare_releases")
+	if !ok {
+		t.Fatal("Tool 'compare_releases' not found")
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		expectError bool
+	}{
+		{name: "compare two releases", args: map[string]any{"app_id": testAppSlug, "from_sequence": 1, "to_sequence": 2}},
+		{
+			name:        "unknown release",
+			args:        map[string]any{"app_id": testAppID, "from_sequence": 1, "to_sequence": 9},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("compare_releases", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var diff releasediff.Diff
+			if err := json.Unmarshal(resultData(t, result), &diff); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if len(diff.Files.Added) != 1 || diff.Files.Added[0] != "service/web.yaml" {
+				t.Errorf("Expected the service to be added, got %+v", diff.Files)
+			}
+			if len(diff.Images) != 1 || diff.Images[0].Name != "example/web" || diff.Images[0].To[0] != "1.1" {
+				t.Errorf("Expected the web image tag to change, got %+v", diff.Images)
+			}
+		})
+	}
+}
+
+func TestCompareChannelsTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("compare_channels")
+	if !ok {
+		t.Fatal("Tool 'compare_channels' not found")
+	}
+
+	tests := []struct {
+		name           string
+		args           map[string]any
+		wantOnlyInFrom []int64
+		wantOnlyInTo   []int64
+		expectError    bool
+	}{
+		{
+			name:           "beta ahead of stable",
+			args:           map[string]any{"app_id": testAppSlug, "from_channel": "stable", "to_channel": "Beta"},
+			wantOnlyInFrom: []int64{1},
+			wantOnlyInTo:   []int64{3},
+		},
+		{
+			name: "recent history only",
+			args: map[string]any{
+				"app_id": testAppID, "from_channel": "channel-stable", "to_channel": "beta", "history": 1,
+			},
+			wantOnlyInFrom: []int64{},
+			wantOnlyInTo:   []int64{3},
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "from_channel": "stable", "to_channel": "nightly"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("compare_channels", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var comparison channelComparison
+			if err := json.Unmarshal(resultData(t, result), &comparison); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if got := releaseSequences(comparison.OnlyInFrom); !slices.Equal(got, tt.wantOnlyInFrom) {
+				t.Errorf("Expected only_in_from %v, got %v", tt.wantOnlyInFrom, got)
+			}
+			if got := releaseSequences(comparison.OnlyInTo); !slices.Equal(got, tt.wantOnlyInTo) {
+				t.Errorf("Expected only_in_to %v, got %v", tt.wantOnlyInTo, got)
+			}
+
+			diff := comparison.HeadDiff
+			if diff == nil || diff.From.Sequence != 2 || diff.To.Sequence != 3 {
+				t.Fatalf("Expected a diff of releases 2 and 3, got %+v", diff)
+			}
+			if len(diff.Images) != 1 || diff.Images[0].To[0] != "1.2" {
+				t.Errorf("Expected the web image tag to change, got %+v", diff.Images)
+			}
+		})
+	}
+}
+
+func TestGetReleaseChangelogTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_release_changelog")
+	if !ok {
+		t.Fatal("Tool 'get_release_changelog' not found")
+	}
+
+	tests := []struct {
+		name             string
+		args             map[string]any
+		wantChannel      string
+		wantSinceChannel string
+		wantSequences    []int64
+		expectError      bool
+	}{
+		{
+			name:          "sequence range",
+			args:          map[string]any{"app_id": testAppSlug, "from_sequence": 1, "to_sequence": 2},
+			wantSequences: []int64{2},
+		},
+		{
+			name:             "beta since the last stable promotion",
+			args:             map[string]any{"app_id": testAppID, "channel": "beta", "since_channel": "Stable"},
+			wantChannel:      "Beta",
+			wantSinceChannel: "Stable",
+			wantSequences:    []int64{3},
+		},
+		{
+			name:        "sequence and channel start",
+			args:        map[string]any{"app_id": testAppID, "since_channel": "stable", "from_sequence": 1},
+			expectError: true,
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel": "nightly"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_release_changelog", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var changelog releaseChangelog
+			if err := json.Unmarshal(resultData(t, result), &changelog); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if changelog.Channel != tt.wantChannel || changelog.SinceChannel != tt.wantSinceChannel {
+				t.Errorf("Expected channels %q since %q, got %q since %q",
+					tt.wantChannel, tt.wantSinceChannel, changelog.Channel, changelog.SinceChannel)
+			}
+
+			sequences := []int64{}
+			for _, entry := range changelog.Entries {
+				sequences = append(sequences, entry.Sequence)
+			}
+			if !slices.Equal(sequences, tt.wantSequences) {
+				t.Errorf("Expected releases %v, got %v", tt.wantSequences, sequences)
+			}
+		})
+	}
+}
+
+// releaseSequences returns the release sequences of channel history entries
+func releaseSequences(history []models.ChannelRelease) []int64 {
+	sequences := []int64{}
+	for _, release := range history {
+		sequences = append(sequences, release.ReleaseSequence)
+	}
+	return sequences
+}
+
+func TestListReleasesTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("list_releases")
+	if !ok {
+		t.Fatal("Tool 'list_releases' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		wantSequences []int64
+		expectError   bool
+	}{
+		{
+			name:          "every release",
+			args:          map[string]any{"app_id": testAppSlug},
+			wantSequences: []int64{1, 2, 3},
+		},
+		{
+			name:          "releases promoted to a channel by slug",
+			args:          map[string]any{"app_id": testAppID, "channel_id": "stable"},
+			wantSequences: []int64{1, 2},
+		},
+		{
+			name:          "releases promoted to a channel by name",
+			args:          map[string]any{"app_id": testAppID, "channel_id": "Beta"},
+			wantSequences: []int64{2, 3},
+		},
+		{
+			name:        "unknown channel",
+			args:        map[string]any{"app_id": testAppID, "channel_id": "lts"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_releases", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			sequences := []int64{}
+			for _, release := range decodeEnvelope[[]models.Release](t, result).Data {
+				sequences = append(sequences, release.Sequence)
+			}
+			if !slices.Equal(sequences, tt.wantSequences) {
+				t.Errorf("Expected releases %v, got %v", tt.wantSequences, sequences)
+			}
+		})
+	}
+}
+
+func TestGetReleaseTool(t *testing.T) {
+	server := newTestServer(t, newTestAdoptionAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_release")
+	if !ok {
+		t.Fatal("Tool 'get_release' not found")
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantSequence int64
+		expectError  bool
+	}{
+		{
+			name:         "by sequence",
+			args:         map[string]any{"app_id": testAppSlug, "sequence": 1},
+			wantSequence: 1,
+		},
+		{
+			name:         "by version label",
+			args:         map[string]any{"app_id": testAppID, "version": "1.2.0-BETA"},
+			wantSequence: 3,
+		},
+		{
+			name:         "by ID",
+			args:         map[string]any{"app_id": testAppID, "release_id": "release-2"},
+			wantSequence: 2,
+		},
+		{
+			name:        "unknown version label",
+			args:        map[string]any{"app_id": testAppID, "version": "9.9.9"},
+			expectError: true,
+		},
+		{
+			name:        "no release named",
+			args:        map[string]any{"app_id": testAppID},
+			expectError: true,
+		},
+		{
+			name:        "several ways of naming a release",
+			args:        map[string]any{"app_id": testAppID, "sequence": 1, "version": "1.0.0"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_release", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var release models.Release
+			if err := json.Unmarshal(resultData(t, result), &release); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if release.Sequence != tt.wantSequence || release.Config == "" {
+				t.Errorf("Expected release %d with its manifests, got %+v", tt.wantSequence, release)
+			}
+		})
+	}
+}
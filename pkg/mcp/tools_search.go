@@ -0,0 +1,289 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+)
+
+// Cross-entity search settings
+const (
+	defaultSearchAllLimit = 10
+	searchAllConcurrency  = 4
+)
+
+// searchAllKinds lists the entity kinds search_all covers, in the order their groups are reported
+var searchAllKinds = []string{searchKindApplications, searchKindChannels, searchKindCustomers, searchKindReleases}
+
+// searchKindTypes names the type of entity in each kind's results
+var searchKindTypes = map[string]string{
+	searchKindApplications: "application",
+	searchKindChannels:     "channel",
+	searchKindCustomers:    "customer",
+	searchKindReleases:     "release",
+}
+
+// Search Tools
+
+// searchAllHit is one entity matching a search_all query. AppID is the application the
+// entity belongs to; Score is only set when the search index is enabled.
+type searchAllHit struct {
+	Type  string  `json:"type"`
+	AppID string  `json:"app_id,omitempty"`
+	Score float64 `json:"score,omitempty"`
+	Item  any     `json:"item"`
+}
+
+// searchAllFailure reports a kind of entity that could not be searched in one application
+type searchAllFailure struct {
+	Type  string `json:"type"`
+	AppID string `json:"app_id,omitempty"`
+	Error string `json:"error"`
+}
+
+// searchAllResult groups search_all matches by entity type. TotalMatches counts every match
+// per group, including those beyond the limit; Truncated reports that some application had
+// more customers than the search scan limit.
+type searchAllResult struct {
+	Query        string             `json:"query"`
+	Applications []searchAllHit     `json:"applications"`
+	Channels     []searchAllHit     `json:"channels"`
+	Customers    []searchAllHit     `json:"customers"`
+	Releases     []searchAllHit     `json:"releases"`
+	TotalMatches map[string]int     `json:"total_matches"`
+	Truncated    bool               `json:"truncated,omitempty"`
+	Failures     []searchAllFailure `json:"failures,omitempty"`
+}
+
+// searchAllTask searches one kind of entity in one application (appID is empty for applications)
+type searchAllTask struct {
+	kind  string
+	appID string
+
+	hits      []searchAllHit
+	truncated bool
+	err       error
+}
+
+// defineSearchAllTool creates the search_all tool definition.
+// Searches applications, channels, customers, and releases in one call.
+func (s *Server) defineSearchAllTool() toolDefinition {
+	tool := mcp.NewTool("search_all",
+		mcp.WithDescription("Search applications, channels, customers, and releases in one call, for when it is "+
+			"unclear what a name refers to, such as whether \"acme\" is a customer, a channel, or an application "+
+			"slug. Returns the matches grouped by type, each with its type and application ID. Without app_id, "+
+			"the channels, customers, and releases of every application are searched, which takes longer on large "+
+			"accounts. Kinds of entity whose search tool is disabled are skipped. "+searchRankingDescription),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Search query string, such as a name, slug, email address, version, or ID"),
+		),
+		mcp.WithString("app_id",
+			mcp.Description("Only search the channels, customers, and releases of this application "+
+				"(ID or slug)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results to return for each type (1-50, default %d)",
+				defaultSearchAllLimit)),
+			mcp.Min(minLimit),
+			mcp.Max(maxSearchLimit),
+		),
+		withSearchIndexOptions(),
+		outputSchema[searchAllResult](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("search_all tool called", "arguments", request.GetArguments())
+
+		query, err := request.RequireString("query")
+		if err != nil {
+			return nil, err
+		}
+
+		appIDs, err := s.searchAllApplications(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks := s.searchAllTasks(appIDs)
+		if len(tasks) == 0 {
+			return nil, fmt.Errorf("no entities can be searched: the search tools are all disabled")
+		}
+
+		s.runSearchAll(ctx, tasks, query, request.GetBool("refresh", false))
+		return jsonResult(ctx, s.mergeSearchAll(query, tasks, request.GetInt("limit", defaultSearchAllLimit)))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// searchAllApplications returns the applications whose entities search_all covers: the one
+// named by app_id, or every application
+func (s *Server) searchAllApplications(ctx context.Context, request mcp.CallToolRequest) ([]string, error) {
+	if request.GetString("app_id", "") != "" {
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return []string{appID}, nil
+	}
+
+	list, err := s.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	appIDs := make([]string, 0, len(list.Applications))
+	for i := range list.Applications {
+		appIDs = append(appIDs, list.Applications[i].ID)
+	}
+	return appIDs, nil
+}
+
+// searchAllTasks plans a search of each kind of entity: applications once, and the other
+// kinds in each application. Kinds whose search tool or tool category is disabled are skipped.
+func (s *Server) searchAllTasks(appIDs []string) []*searchAllTask {
+	disabled := s.currentConfig().DisabledTools
+
+	var tasks []*searchAllTask
+	for _, kind := range searchAllKinds {
+		tool, ok := s.registry.Tool("search_" + kind)
+		if !ok || containsAny(disabled, []string{tool.definition.Name, tool.category}) {
+			continue
+		}
+
+		if kind == searchKindApplications {
+			tasks = append(tasks, &searchAllTask{kind: kind})
+			continue
+		}
+		for _, appID := range appIDs {
+			tasks = append(tasks, &searchAllTask{kind: kind, appID: appID})
+		}
+	}
+	return tasks
+}
+
+// runSearchAll runs the tasks with bounded concurrency. A failed task is recorded on the task
+// rather than stopping the others.
+func (s *Server) runSearchAll(ctx context.Context, tasks []*searchAllTask, query string, refresh bool) {
+	var group errgroup.Group
+	group.SetLimit(searchAllConcurrency)
+	for _, task := range tasks {
+		group.Go(func() error {
+			task.hits, task.truncated, task.err = s.searchKind(ctx, task.kind, task.appID, query, refresh)
+			return nil
+		})
+	}
+	_ = group.Wait()
+}
+
+// searchKind searches one kind of entity in an application, from the index when it is
+// enabled and otherwise by matching the query against each entity's fields
+func (s *Server) searchKind(
+	ctx context.Context,
+	kind, appID, query string,
+	refresh bool,
+) ([]searchAllHit, bool, error) {
+	cfg := s.currentConfig()
+	hit := func(item any, score float64) searchAllHit {
+		return searchAllHit{Type: searchKindTypes[kind], AppID: appID, Score: score, Item: item}
+	}
+
+	if cfg.SearchIndex {
+		entry, err := s.searchIndex(ctx, kind, appID, refresh)
+		if err != nil {
+			return nil, false, err
+		}
+		var hits []searchAllHit
+		for _, match := range entry.index.Search(query) {
+			hits = append(hits, hit(entry.entities[match.ID], roundScore(match.Score)))
+		}
+		return hits, entry.truncated, nil
+	}
+
+	var hits []searchAllHit
+	truncated := false
+	switch kind {
+	case searchKindApplications:
+		list, err := s.applications.SearchApplications(ctx, query, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		for i := range list.Applications {
+			hits = append(hits, hit(&list.Applications[i], 0))
+		}
+	case searchKindChannels:
+		list, err := s.channels.SearchChannels(ctx, appID, query)
+		if err != nil {
+			return nil, false, err
+		}
+		for i := range list.Channels {
+			hits = append(hits, hit(&list.Channels[i], 0))
+		}
+	case searchKindCustomers:
+		list, err := s.customers.SearchCustomers(ctx, appID, query, cfg.SearchScanLimit)
+		if err != nil {
+			return nil, false, err
+		}
+		truncated = list.Truncated
+		for i := range list.Customers {
+			hits = append(hits, hit(&list.Customers[i], 0))
+		}
+	case searchKindReleases:
+		list, err := s.releases.SearchReleases(ctx, appID, query)
+		if err != nil {
+			return nil, false, err
+		}
+		for i := range list.Releases {
+			hits = append(hits, hit(&list.Releases[i], 0))
+		}
+	default:
+		return nil, false, fmt.Errorf("cannot search %s", kind)
+	}
+	return hits, truncated, nil
+}
+
+// mergeSearchAll groups the tasks' hits by type, most relevant first when they are scored,
+// and keeps at most limit of each
+func (s *Server) mergeSearchAll(query string, tasks []*searchAllTask, limit int) searchAllResult {
+	groups := make(map[string][]searchAllHit, len(searchAllKinds))
+	result := searchAllResult{Query: query, TotalMatches: make(map[string]int, len(searchAllKinds))}
+
+	for _, task := range tasks {
+		if task.err != nil {
+			result.Failures = append(result.Failures, searchAllFailure{
+				Type: searchKindTypes[task.kind], AppID: task.appID, Error: task.err.Error(),
+			})
+			continue
+		}
+		groups[task.kind] = append(groups[task.kind], task.hits...)
+		result.Truncated = result.Truncated || task.truncated
+	}
+
+	group := func(kind string) []searchAllHit {
+		hits := groups[kind]
+		slices.SortStableFunc(hits, func(a, b searchAllHit) int {
+			switch {
+			case a.Score > b.Score:
+				return -1
+			case a.Score < b.Score:
+				return 1
+			}
+			return 0
+		})
+		result.TotalMatches[kind] = len(hits)
+		return paginate(hits, minOffset, limit)
+	}
+
+	result.Applications = group(searchKindApplications)
+	result.Channels = group(searchKindChannels)
+	result.Customers = group(searchKindCustomers)
+	result.Releases = group(searchKindReleases)
+	return result
+}
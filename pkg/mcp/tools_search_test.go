@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// searchAllResponse decodes the parts of a search_all result the tests check
+type searchAllResponse struct {
+	Applications []searchAllTestHit `json:"applications"`
+	Channels     []searchAllTestHit `json:"channels"`
+	Customers    []searchAllTestHit `json:"customers"`
+	Releases     []searchAllTestHit `json:"releases"`
+	TotalMatches map[string]int     `json:"total_matches"`
+	Failures     []searchAllFailure `json:"failures"`
+}
+
+type searchAllTestHit struct {
+	Type  string  `json:"type"`
+	AppID string  `json:"app_id"`
+	Score float64 `json:"score"`
+	Item  struct {
+		ID string `json:"id"`
+	} `json:"item"`
+}
+
+// callSearchAll runs the search_all tool and decodes its result
+func callSearchAll(t *testing.T, server *Server, args map[string]any) searchAllResponse {
+	t.Helper()
+
+	tool, ok := server.registry.Tool("search_all")
+	if !ok {
+		t.Fatal("Tool 'search_all' not found")
+	}
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("search_all", args))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response searchAllResponse
+	if err := json.Unmarshal(resultData(t, result), &response); err != nil {
+		t.Fatalf("Failed to decode search_all result: %v", err)
+	}
+	return response
+}
+
+func TestSearchAllTool(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]any
+		searchIndex  bool
+		disabled     []string
+		expectedType string
+		expectedIDs  map[string][]string
+	}{
+		{
+			name:         "customer by name",
+			args:         map[string]any{"query": "acme"},
+			expectedType: "customer",
+			expectedIDs:  map[string][]string{searchKindCustomers: {"customer-1"}},
+		},
+		{
+			name:         "channel in one application",
+			args:         map[string]any{"query": "stable", "app_id": testAppSlug},
+			expectedType: "channel",
+			expectedIDs:  map[string][]string{searchKindChannels: {"channel-1"}},
+		},
+		{
+			name:         "ranked by the index",
+			args:         map[string]any{"query": "saml"},
+			searchIndex:  true,
+			expectedType: "release",
+			expectedIDs:  map[string][]string{searchKindReleases: {"release-2"}},
+		},
+		{
+			name:        "disabled kinds are skipped",
+			args:        map[string]any{"query": "acme"},
+			disabled:    []string{"search_customers"},
+			expectedIDs: map[string][]string{},
+		},
+		{
+			name:        "no matches",
+			args:        map[string]any{"query": "hooli"},
+			expectedIDs: map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, newTestVendorAPI(t).URL)
+			cfg := *server.currentConfig()
+			cfg.SearchIndex = tt.searchIndex
+			cfg.DisabledTools = tt.disabled
+			if err := server.Reconfigure(&cfg); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			response := callSearchAll(t, server, tt.args)
+			if len(response.Failures) > 0 {
+				t.Fatalf("Expected no failures, got %+v", response.Failures)
+			}
+
+			groups := map[string][]searchAllTestHit{
+				searchKindApplications: response.Applications,
+				searchKindChannels:     response.Channels,
+				searchKindCustomers:    response.Customers,
+				searchKindReleases:     response.Releases,
+			}
+			for kind, hits := range groups {
+				expected := tt.expectedIDs[kind]
+				if len(hits) != len(expected) || response.TotalMatches[kind] != len(expected) {
+					t.Fatalf("Expected %d %s, got %+v", len(expected), kind, hits)
+				}
+				for i, id := range expected {
+					hit := hits[i]
+					if hit.Item.ID != id || hit.Type != tt.expectedType || hit.AppID != testAppID {
+						t.Errorf("Expected %s %s in %s, got %+v", tt.expectedType, id, testAppID, hit)
+					}
+					if tt.searchIndex != (hit.Score > 0) {
+						t.Errorf("Expected a score only from the index, got %v", hit.Score)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSearchAllTool_Failures(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s, {"id": "app-missing", "name": "Missing", "slug": "missing"}]}`,
+			testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [{"id": "channel-1", "name": "Stable"}]}`)
+	})
+	vendorAPI := httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+
+	server := newTestServer(t, vendorAPI.URL)
+	cfg := *server.currentConfig()
+	cfg.DisabledTools = []string{"customers", "releases"}
+	if err := server.Reconfigure(&cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	response := callSearchAll(t, server, map[string]any{"query": "stable"})
+	if len(response.Channels) != 1 || response.Channels[0].Item.ID != "channel-1" {
+		t.Errorf("Expected the other application's channel to be found, got %+v", response.Channels)
+	}
+	if len(response.Failures) != 1 {
+		t.Fatalf("Expected one failure, got %+v", response.Failures)
+	}
+	failure := response.Failures[0]
+	if failure.Type != "channel" || failure.AppID != "app-missing" || failure.Error == "" {
+		t.Errorf("Expected the missing application's channels to fail, got %+v", failure)
+	}
+}
@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/metrics"
+	"github.com/crdant/replicated-mcp-server/pkg/slo"
+)
+
+// BuildInfo identifies the server build reported to agents by get_server_info
+type BuildInfo struct {
+	Version   string `json:"version"`
+	BuildDate string `json:"build_date"`
+	Commit    string `json:"commit"`
+}
+
+// serverInfo is the get_server_info result
+type serverInfo struct {
+	Build            BuildInfo          `json:"build"`
+	Profile          string             `json:"profile,omitempty"`
+	Endpoint         string             `json:"endpoint"`
+	FallbackEndpoint string             `json:"fallback_endpoint,omitempty"`
+	Authentication   authenticationInfo `json:"authentication"`
+	Capabilities     capabilitiesInfo   `json:"capabilities"`
+}
+
+// authenticationInfo reports whether the API token was accepted. TokenValid is omitted
+// when the check could not reach a verdict, such as when the endpoint is unreachable.
+type authenticationInfo struct {
+	TokenValid *bool  `json:"token_valid,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// capabilitiesInfo summarizes the features enabled by the server configuration
+type capabilitiesInfo struct {
+	ReadOnly              bool     `json:"read_only"`
+	DryRun                bool     `json:"dry_run"`
+	DefaultApp            string   `json:"default_app,omitempty"`
+	ClusterCommands       bool     `json:"cluster_commands"`
+	DevMode               bool     `json:"dev_mode"`
+	HedgedReads           bool     `json:"hedged_reads"`
+	Failover              bool     `json:"failover"`
+	MaxConcurrentRequests int      `json:"max_concurrent_requests"`
+	RequestsPerSecond     float64  `json:"requests_per_second"`
+	Tools                 []string `json:"tools"`
+}
+
+// Server Tools
+
+// defineGetServerInfoTool creates the get_server_info tool definition.
+// Reports the server build, configured endpoint, token validity, and enabled capabilities.
+func (s *Server) defineGetServerInfoTool() toolDefinition {
+	tool := mcp.NewTool("get_server_info",
+		mcp.WithDescription("Get the server's version, build, and commit, the config file profile and "+
+			"Vendor Portal API endpoint in use, whether the API token is valid, and the enabled capabilities "+
+			"and tools. Use this to verify the connection before starting a larger workflow."),
+		outputSchema[serverInfo](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_server_info tool called", "arguments", request.GetArguments())
+
+		cfg := s.currentConfig()
+		clientCfg := clientConfig(cfg)
+
+		exposed := s.exposedTools()
+		tools := make([]string, 0, len(exposed))
+		for _, exposedTool := range exposed {
+			tools = append(tools, exposedTool.definition.Name)
+		}
+
+		return jsonResult(ctx, serverInfo{
+			Build:            s.buildInfo(),
+			Profile:          cfg.Profile,
+			Endpoint:         clientCfg.BaseURL,
+			FallbackEndpoint: clientCfg.FallbackURL,
+			Authentication:   s.checkAuthentication(ctx),
+			Capabilities: capabilitiesInfo{
+				ReadOnly:              cfg.ReadOnly,
+				DryRun:                cfg.DryRun,
+				DefaultApp:            cfg.App,
+				ClusterCommands:       cfg.ClusterCommands,
+				DevMode:               cfg.DevMode,
+				HedgedReads:           clientCfg.Hedging.Enabled,
+				Failover:              clientCfg.FallbackURL != "",
+				MaxConcurrentRequests: clientCfg.MaxConcurrentRequests,
+				RequestsPerSecond:     clientCfg.RequestsPerSecond,
+				Tools:                 tools,
+			},
+		})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// checkAuthentication verifies the API token with a lightweight API call. Only a 401 or
+// 403 response marks the token invalid; other failures leave the verdict open.
+func (s *Server) checkAuthentication(ctx context.Context) authenticationInfo {
+	err := s.client.VerifyToken(ctx)
+	if err == nil {
+		valid := true
+		return authenticationInfo{TokenValid: &valid}
+	}
+
+	info := authenticationInfo{Error: err.Error()}
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		valid := false
+		info.TokenValid = &valid
+	}
+	return info
+}
+
+// defineGetServerMetricsTool creates the get_server_metrics tool definition.
+// Summarizes the server's metrics as JSON for clients without access to a metrics endpoint.
+func (s *Server) defineGetServerMetricsTool() toolDefinition {
+	tool := mcp.NewTool("get_server_metrics",
+		mcp.WithDescription("Get the server's performance metrics since it started: per-tool call counts, "+
+			"error rates, and latency percentiles; Vendor Portal API request counts, error rate, rate-limit "+
+			"hits, and latency percentiles by method; and cache hit rates. Latencies are in seconds."),
+		outputSchema[metrics.Snapshot](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_server_metrics tool called", "arguments", request.GetArguments())
+
+		return jsonResult(ctx, s.metrics.Snapshot())
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// dependencySLO is the get_dependency_slo result: the Vendor Portal API's objectives and
+// windows, with a summary of what they mean for the agent
+type dependencySLO struct {
+	slo.Status
+	Summary string `json:"summary"`
+}
+
+// defineGetDependencySLOTool creates the get_dependency_slo tool definition.
+// Reports how the Vendor Portal API measures up to its availability and latency objectives,
+// so agents and operators can tell portal problems apart from their own.
+func (s *Server) defineGetDependencySLOTool() toolDefinition {
+	tool := mcp.NewTool("get_dependency_slo",
+		mcp.WithDescription("Get how the Vendor Portal API has measured up to its availability and latency "+
+			"objectives over the last 5 minutes and hour: request, failure, and slow request counts, and the "+
+			"burn rate of each objective's error budget, where 1 spends the budget exactly as fast as the "+
+			"objective allows. Use this when calls fail or time out to tell whether the portal is having "+
+			"trouble or the problem is in the calls themselves."),
+		outputSchema[dependencySLO](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_dependency_slo tool called", "arguments", request.GetArguments())
+
+		status := s.slo.Status()
+		return jsonResult(ctx, dependencySLO{Status: status, Summary: summarizeSLO(status)})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// summarizeSLO describes the SLO state in a sentence an agent can act on
+func summarizeSLO(status slo.Status) string {
+	long := status.Windows[len(status.Windows)-1]
+
+	switch status.State {
+	case slo.StateNoData:
+		return "No Vendor Portal API requests were made in the last hour, so there is nothing to measure yet"
+	case slo.StateBurning:
+		return fmt.Sprintf("The Vendor Portal API is failing or slow far beyond its objectives right now "+
+			"(availability burn rate %.1f, latency burn rate %.1f over the last hour); errors and timeouts "+
+			"are most likely on the portal's side, so retry later rather than changing the calls",
+			long.AvailabilityBurnRate, long.LatencyBurnRate)
+	case slo.StateDegraded:
+		return fmt.Sprintf("The Vendor Portal API is spending its error budget faster than its objectives "+
+			"allow (availability burn rate %.1f, latency burn rate %.1f over the last hour); occasional "+
+			"failures or slow responses may be the portal's", long.AvailabilityBurnRate, long.LatencyBurnRate)
+	default:
+		return "The Vendor Portal API is meeting its objectives; failing calls are more likely caused by " +
+			"their arguments, permissions, or the agent than by the portal"
+	}
+}
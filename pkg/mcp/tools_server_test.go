@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/metrics"
+	"github.com/crdant/replicated-mcp-server/pkg/slo"
+)
+
+func TestGetServerInfoTool(t *testing.T) {
+	vendorAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "Unauthorized"}`)
+			return
+		}
+		fmt.Fprint(w, `{"applications": []}`)
+	}))
+	defer vendorAPI.Close()
+
+	valid, invalid := true, false
+	tests := []struct {
+		name      string
+		endpoint  string
+		token     string
+		wantValid *bool
+	}{
+		{name: "valid token", endpoint: vendorAPI.URL, token: "test-token", wantValid: &valid},
+		{name: "rejected token", endpoint: vendorAPI.URL, token: "expired-token", wantValid: &invalid},
+		{name: "unreachable endpoint", endpoint: "http://127.0.0.1:1", token: "test-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.endpoint)
+			server.config.APIToken = tt.token
+			if err := server.client.UpdateConfig(clientConfig(server.config)); err != nil {
+				t.Fatalf("Failed to update client: %v", err)
+			}
+			server.SetBuildInfo(BuildInfo{Version: "1.2.3", BuildDate: "2025-01-01", Commit: "abc123"})
+
+			tool, ok := server.registry.Tool("get_server_info")
+			if !ok {
+				t.Fatal("Tool 'get_server_info' not found")
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_server_info", nil))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var info serverInfo
+			if err := json.Unmarshal(resultData(t, result), &info); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+
+			if info.Build.Version != "1.2.3" || info.Build.Commit != "abc123" {
+				t.Errorf("Unexpected build info: %+v", info.Build)
+			}
+			if info.Endpoint != tt.endpoint {
+				t.Errorf("Expected endpoint %s, got %s", tt.endpoint, info.Endpoint)
+			}
+			if !slices.Contains(info.Capabilities.Tools, "get_server_info") {
+				t.Errorf("Expected exposed tools to include get_server_info, got %v", info.Capabilities.Tools)
+			}
+
+			got := info.Authentication.TokenValid
+			switch {
+			case tt.wantValid == nil && got != nil:
+				t.Errorf("Expected no token verdict, got %t", *got)
+			case tt.wantValid != nil && (got == nil || *got != *tt.wantValid):
+				t.Errorf("Expected token_valid %t, got %v (error: %s)", *tt.wantValid, got, info.Authentication.Error)
+			}
+			if tt.wantValid == nil && info.Authentication.Error == "" {
+				t.Error("Expected an error when the token could not be checked")
+			}
+		})
+	}
+}
+
+func TestGetServerMetricsTool(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	// Resolving the same slug twice misses the cache once, then hits it
+	calls := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_applications","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_application",` +
+			`"arguments":{"app_id":"` + testAppSlug + `"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_application",` +
+			`"arguments":{"app_id":"` + testAppSlug + `"}}}`,
+	}
+	for _, call := range calls {
+		server.mcpServer.HandleMessage(context.Background(), json.RawMessage(call))
+	}
+
+	tool, ok := server.registry.Tool("get_server_metrics")
+	if !ok {
+		t.Fatal("Tool 'get_server_metrics' not found")
+	}
+
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("get_server_metrics", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var snapshot metrics.Snapshot
+	if err := json.Unmarshal(resultData(t, result), &snapshot); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+
+	calledTools := make(map[string]uint64)
+	for _, stats := range snapshot.Tools {
+		calledTools[stats.Tool] = stats.Calls
+	}
+	if calledTools["list_applications"] != 1 || calledTools["get_application"] != 2 {
+		t.Errorf("Unexpected tool call counts: %v", calledTools)
+	}
+	if snapshot.API.Requests == 0 {
+		t.Error("Expected API requests to be counted")
+	}
+	if len(snapshot.Caches) != 1 || snapshot.Caches[0].Hits != 1 || snapshot.Caches[0].Misses != 1 {
+		t.Errorf("Unexpected cache stats: %+v", snapshot.Caches)
+	}
+}
+
+func TestGetDependencySLOTool(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("get_dependency_slo")
+	if !ok {
+		t.Fatal("Tool 'get_dependency_slo' not found")
+	}
+
+	status := func() dependencySLO {
+		t.Helper()
+		result, err := tool.handler(context.Background(), createMockCallToolRequest("get_dependency_slo", nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var report dependencySLO
+		if err := json.Unmarshal(resultData(t, result), &report); err != nil {
+			t.Fatalf("Failed to decode result: %v", err)
+		}
+		return report
+	}
+
+	if report := status(); report.State != slo.StateNoData || report.Summary == "" {
+		t.Errorf("Expected no data before any requests, got %+v", report)
+	}
+
+	server.mcpServer.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_applications","arguments":{}}}`))
+
+	report := status()
+	if report.State != slo.StateHealthy || len(report.Windows) != 2 || report.Windows[0].Requests == 0 {
+		t.Errorf("Expected a healthy portal with requests counted, got %+v", report)
+	}
+	if report.Objectives.Availability != slo.DefaultAvailability {
+		t.Errorf("Expected the default availability objective, got %v", report.Objectives.Availability)
+	}
+}
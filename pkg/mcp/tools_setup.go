@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+)
+
+// Setup step statuses
+const (
+	setupStepComplete       = "complete"
+	setupStepActionRequired = "action_required"
+	setupStepPending        = "pending"
+)
+
+// Server modes the setup assistant can recommend
+const (
+	setupModeReadOnly  = "read_only"
+	setupModeReadWrite = "read_write"
+	setupModeDryRun    = "dry_run"
+)
+
+// setupServerCommand is the command clients run to start the server
+const setupServerCommand = "replicated-mcp-server"
+
+// setupServerName is the server's key in the recommended client configuration
+const setupServerName = "replicated"
+
+// setupReport is the setup_assistant result. Steps are in order; NextStep names the first
+// step that needs the agent or user to act, or is empty once setup is complete.
+type setupReport struct {
+	Steps    []setupStep `json:"steps"`
+	NextStep string      `json:"next_step,omitempty"`
+	Complete bool        `json:"complete"`
+}
+
+// setupStep is one step of the onboarding flow. Options lists the choices for a step that
+// needs a decision, and Result holds what the step found or chose.
+type setupStep struct {
+	Name    string        `json:"name"`
+	Status  string        `json:"status"`
+	Message string        `json:"message"`
+	Options []setupOption `json:"options,omitempty"`
+	Result  any           `json:"result,omitempty"`
+}
+
+// setupOption is a choice offered by a setup step
+type setupOption struct {
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+// clientConfiguration is the mcpServers entry clients such as Claude Desktop use to start
+// the server
+type clientConfiguration struct {
+	MCPServers map[string]clientServerEntry `json:"mcpServers"`
+}
+
+// clientServerEntry is the command and environment a client starts the server with
+type clientServerEntry struct {
+	Command string            `json:"command"`
+	Env     map[string]string `json:"env"`
+}
+
+// Setup Tools
+
+// defineSetupAssistantTool creates the setup_assistant tool definition.
+// Walks an agent through onboarding: verifying the API token, selecting a default
+// application, choosing a mode, and producing the client configuration to use.
+func (s *Server) defineSetupAssistantTool() toolDefinition {
+	tool := mcp.NewTool("setup_assistant",
+		mcp.WithDescription("Walk through first-run setup: verify the API token, select the default "+
+			"application tools use when app_id is omitted, choose read-only, read-write, or dry-run mode, "+
+			"and get the recommended client configuration. Call it without arguments to see where setup "+
+			"stands, then again with each choice until the result is complete. Nothing is changed on the "+
+			"server or in the Vendor Portal; apply the configuration in the client and restart the server."),
+		mcp.WithString("app_id",
+			mcp.Description("The ID or slug of the application to use as the default"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("The mode to run the server in: read_only withholds mutating tools, "+
+				"dry_run previews every change without making it, and read_write allows changes"),
+			mcp.Enum(setupModeReadOnly, setupModeReadWrite, setupModeDryRun),
+		),
+		outputSchema[setupReport](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("setup_assistant tool called", "arguments", request.GetArguments())
+
+		mode := request.GetString("mode", "")
+		if mode != "" && !slices.Contains([]string{setupModeReadOnly, setupModeReadWrite, setupModeDryRun}, mode) {
+			return nil, fmt.Errorf("invalid mode '%s': must be one of %s, %s, or %s",
+				mode, setupModeReadOnly, setupModeReadWrite, setupModeDryRun)
+		}
+
+		cfg := s.currentConfig()
+		token := s.setupTokenStep(ctx)
+		app := s.setupApplicationStep(ctx, request.GetString("app_id", ""), cfg, token.Status)
+		modeStep := setupModeStep(mode, cfg)
+		report := setupReport{Steps: []setupStep{token, app, modeStep}}
+
+		clientStep := setupStep{
+			Name:    "client_configuration",
+			Status:  setupStepPending,
+			Message: "Complete the earlier steps to get the client configuration",
+		}
+		if nextSetupStep(report.Steps) == "" {
+			appID, _ := app.Result.(string)
+			modeName, _ := modeStep.Result.(string)
+			clientStep = setupStep{
+				Name:   "client_configuration",
+				Status: setupStepComplete,
+				Message: fmt.Sprintf("Add this entry to the client's MCP server configuration and restart "+
+					"the client; the server will use %s by default in %s mode", appID, modeName),
+				Result: setupClientConfiguration(cfg, appID, modeName),
+			}
+		}
+		report.Steps = append(report.Steps, clientStep)
+
+		report.NextStep = nextSetupStep(report.Steps)
+		report.Complete = report.NextStep == ""
+		return jsonResult(ctx, report)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// setupTokenStep verifies the API token. The step is complete only when the API accepts
+// the token; an unreachable endpoint leaves it needing attention.
+func (s *Server) setupTokenStep(ctx context.Context) setupStep {
+	step := setupStep{Name: "verify_token"}
+
+	auth := s.checkAuthentication(ctx)
+	switch {
+	case auth.TokenValid != nil && *auth.TokenValid:
+		step.Status = setupStepComplete
+		step.Message = "The API token is valid"
+	case auth.TokenValid != nil:
+		step.Status = setupStepActionRequired
+		step.Message = "The API token was rejected; create a token in the Vendor Portal and set " +
+			"REPLICATED_API_TOKEN or configure another token source"
+	default:
+		step.Status = setupStepActionRequired
+		step.Message = "The API token could not be verified: " + auth.Error
+	}
+	return step
+}
+
+// setupApplicationStep selects the default application: the one named in the call, the
+// one already configured, or the only application on the account. Otherwise the
+// account's applications are offered as options.
+func (s *Server) setupApplicationStep(ctx context.Context, idOrSlug string, cfg *config.Config,
+	tokenStatus string) setupStep {
+	step := setupStep{Name: "select_application"}
+	if tokenStatus != setupStepComplete {
+		step.Status = setupStepPending
+		step.Message = "Verify the API token before selecting an application"
+		return step
+	}
+
+	if idOrSlug == "" {
+		idOrSlug = cfg.App
+	}
+	if idOrSlug != "" {
+		appID, err := s.resolver.ResolveApplicationID(ctx, idOrSlug)
+		if err != nil {
+			step.Status = setupStepActionRequired
+			step.Message = fmt.Sprintf("Application '%s' was not found: %v", idOrSlug, err)
+			return step
+		}
+		step.Status = setupStepComplete
+		step.Message = fmt.Sprintf("Application '%s' is selected", idOrSlug)
+		step.Result = appID
+		return step
+	}
+
+	list, err := s.applications.ListApplications(ctx, &api.ListApplicationsOptions{ExcludeChannels: true})
+	if err != nil {
+		step.Status = setupStepActionRequired
+		step.Message = fmt.Sprintf("Failed to list applications: %v", err)
+		return step
+	}
+
+	switch len(list.Applications) {
+	case 0:
+		step.Status = setupStepActionRequired
+		step.Message = "The account has no applications; create one in the Vendor Portal"
+	case 1:
+		app := list.Applications[0]
+		step.Status = setupStepComplete
+		step.Message = fmt.Sprintf("Application '%s' is the only application and is selected", app.Slug)
+		step.Result = app.ID
+	default:
+		step.Status = setupStepActionRequired
+		step.Message = "Call setup_assistant again with app_id set to one of these applications"
+		for _, app := range list.Applications {
+			step.Options = append(step.Options, setupOption{Value: app.Slug, Description: app.Name})
+		}
+	}
+	return step
+}
+
+// setupModeStep records the chosen mode, or offers the modes with the one the server runs
+// in now noted
+func setupModeStep(mode string, cfg *config.Config) setupStep {
+	step := setupStep{Name: "choose_mode"}
+	if mode != "" {
+		step.Status = setupStepComplete
+		step.Message = fmt.Sprintf("The server will run in %s mode", mode)
+		step.Result = mode
+		return step
+	}
+
+	current := setupModeReadWrite
+	switch {
+	case cfg.ReadOnly:
+		current = setupModeReadOnly
+	case cfg.DryRun:
+		current = setupModeDryRun
+	}
+	step.Status = setupStepActionRequired
+	step.Message = fmt.Sprintf("Call setup_assistant again with mode set; the server runs in %s mode now. "+
+		"Read-only is recommended until the agent's workflows are trusted.", current)
+	step.Options = []setupOption{
+		{Value: setupModeReadOnly, Description: "Only read tools are available"},
+		{Value: setupModeDryRun, Description: "Mutating tools preview changes without making them"},
+		{Value: setupModeReadWrite, Description: "Mutating tools make changes after confirmation"},
+	}
+	return step
+}
+
+// setupClientConfiguration builds the client configuration for the chosen application and
+// mode. The token source in use is kept, so the token itself is never included.
+func setupClientConfiguration(cfg *config.Config, appID, mode string) clientConfiguration {
+	env := map[string]string{"REPLICATED_APP": appID}
+
+	switch {
+	case cfg.Profile != "":
+		env["REPLICATED_PROFILE"] = cfg.Profile
+	case cfg.APITokenFile != "":
+		env["REPLICATED_API_TOKEN_FILE"] = cfg.APITokenFile
+	case cfg.APITokenCommand != "":
+		env["REPLICATED_API_TOKEN_COMMAND"] = cfg.APITokenCommand
+	case cfg.APITokenKeychain != "":
+		env["REPLICATED_API_TOKEN_KEYCHAIN"] = cfg.APITokenKeychain
+	default:
+		env["REPLICATED_API_TOKEN"] = "<your API token>"
+	}
+
+	switch mode {
+	case setupModeReadOnly:
+		env["REPLICATED_READ_ONLY"] = "true"
+	case setupModeDryRun:
+		env["REPLICATED_DRY_RUN"] = "true"
+	}
+
+	if cfg.Endpoint != "" && cfg.Endpoint != api.DefaultBaseURL {
+		env["ENDPOINT"] = cfg.Endpoint
+	}
+
+	return clientConfiguration{MCPServers: map[string]clientServerEntry{
+		setupServerName: {Command: setupServerCommand, Env: env},
+	}}
+}
+
+// nextSetupStep returns the name of the first step that isn't complete, or "" if all are
+func nextSetupStep(steps []setupStep) string {
+	for _, step := range steps {
+		if step.Status != setupStepComplete {
+			return step.Name
+		}
+	}
+	return ""
+}
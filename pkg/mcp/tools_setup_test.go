@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestSetupAssistantTool(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+
+	tests := []struct {
+		name         string
+		token        string
+		configure    func(s *Server)
+		args         map[string]any
+		wantNextStep string
+		wantEnv      map[string]string
+		expectError  bool
+	}{
+		{
+			name:         "first call selects the only application",
+			args:         map[string]any{},
+			wantNextStep: "choose_mode",
+		},
+		{
+			name: "read-only setup",
+			args: map[string]any{"mode": "read_only"},
+			wantEnv: map[string]string{
+				"REPLICATED_APP": testAppID, "REPLICATED_READ_ONLY": "true", "REPLICATED_API_TOKEN": "<your API token>",
+				"ENDPOINT": vendorAPI.URL,
+			},
+		},
+		{
+			name:      "dry-run setup keeps the token source",
+			configure: func(s *Server) { s.config.APITokenFile = "/run/secrets/replicated" },
+			args:      map[string]any{"app_id": testAppSlug, "mode": "dry_run"},
+			wantEnv: map[string]string{
+				"REPLICATED_APP": testAppID, "REPLICATED_DRY_RUN": "true",
+				"REPLICATED_API_TOKEN_FILE": "/run/secrets/replicated", "ENDPOINT": vendorAPI.URL,
+			},
+		},
+		{
+			name:         "unknown application",
+			args:         map[string]any{"app_id": "unknown-app", "mode": "read_write"},
+			wantNextStep: "select_application",
+		},
+		{
+			name:         "unverified token",
+			configure:    func(s *Server) { s.config.Endpoint = "http://127.0.0.1:1" },
+			args:         map[string]any{"mode": "read_write"},
+			wantNextStep: "verify_token",
+		},
+		{
+			name:        "invalid mode",
+			args:        map[string]any{"mode": "careful"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, vendorAPI.URL)
+			if tt.configure != nil {
+				tt.configure(server)
+				if err := server.client.UpdateConfig(clientConfig(server.config)); err != nil {
+					t.Fatalf("Failed to update client: %v", err)
+				}
+			}
+
+			tool, ok := server.registry.Tool("setup_assistant")
+			if !ok {
+				t.Fatal("Tool 'setup_assistant' not found")
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("setup_assistant", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var report struct {
+				Steps []struct {
+					Name   string          `json:"name"`
+					Status string          `json:"status"`
+					Result json.RawMessage `json:"result"`
+				} `json:"steps"`
+				NextStep string `json:"next_step"`
+				Complete bool   `json:"complete"`
+			}
+			if err := json.Unmarshal(resultData(t, result), &report); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+
+			if report.NextStep != tt.wantNextStep || report.Complete != (tt.wantNextStep == "") {
+				t.Fatalf("Expected next step %q, got %s", tt.wantNextStep, resultText(t, result))
+			}
+			if len(report.Steps) != 4 || report.Steps[3].Name != "client_configuration" {
+				t.Fatalf("Expected four steps ending with the client configuration, got %s", resultText(t, result))
+			}
+			if tt.wantEnv == nil {
+				return
+			}
+
+			var configuration clientConfiguration
+			if err := json.Unmarshal(report.Steps[3].Result, &configuration); err != nil {
+				t.Fatalf("Failed to decode client configuration: %v", err)
+			}
+			entry := configuration.MCPServers[setupServerName]
+			if entry.Command != setupServerCommand || len(entry.Env) != len(tt.wantEnv) {
+				t.Errorf("Unexpected client configuration %+v", configuration)
+			}
+			for name, value := range tt.wantEnv {
+				if entry.Env[name] != value {
+					t.Errorf("Expected %s=%q, got %q", name, value, entry.Env[name])
+				}
+			}
+		})
+	}
+}
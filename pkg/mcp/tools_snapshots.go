@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/jobs"
+	"github.com/crdant/replicated-mcp-server/pkg/snapshot"
+)
+
+// snapshotDirName is the data directory subdirectory that holds snapshot archives
+const snapshotDirName = "snapshots"
+
+// snapshotExport is the job result for a completed account snapshot export
+type snapshotExport struct {
+	Path      string          `json:"path"`
+	CreatedAt time.Time       `json:"created_at"`
+	Redacted  bool            `json:"redacted"`
+	Counts    snapshot.Counts `json:"counts"`
+}
+
+// Snapshot Tools
+
+// defineExportAccountSnapshotTool creates the export_account_snapshot tool definition.
+// Starts a background job that writes every application, channel, release, and customer to an archive.
+func (s *Server) defineExportAccountSnapshotTool() toolDefinition {
+	tool := mcp.NewTool("export_account_snapshot",
+		mcp.WithDescription("Export a full snapshot of the vendor account (applications, channels, release "+
+			"metadata, and customers) to a timestamped archive for compliance or backup. Runs as a background "+
+			"job; use get_job with the returned job ID to check progress and find the archive path."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithBoolean("redact",
+			mcp.Description("Replace customer names, emails, license IDs, and custom field values with "+
+				"[REDACTED] (default false)"),
+		),
+		outputSchema[jobs.Job](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("export_account_snapshot tool called", "arguments", request.GetArguments())
+
+		opts := snapshot.Options{Redact: request.GetBool("redact", false)}
+		sink := snapshot.NewFileSink(s.snapshotDir())
+		source := snapshot.NewAPISource(s.services())
+
+		job, err := s.jobs.Start("export_account_snapshot", func(ctx context.Context) (any, error) {
+			return exportSnapshot(ctx, source, sink, opts)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start snapshot export: %w", err)
+		}
+
+		return jsonResult(ctx, job)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// exportSnapshot collects an account snapshot and writes it to the sink
+func exportSnapshot(
+	ctx context.Context,
+	source snapshot.Source,
+	sink *snapshot.FileSink,
+	opts snapshot.Options,
+) (*snapshotExport, error) {
+	snap, err := snapshot.Collect(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := sink.Write(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshotExport{
+		Path:      path,
+		CreatedAt: snap.CreatedAt,
+		Redacted:  snap.Redacted,
+		Counts:    snap.Counts(),
+	}, nil
+}
+
+// defineDiffAccountSnapshotsTool creates the diff_account_snapshots tool definition.
+// Compares two snapshot archives and reports entity additions, deletions, and field changes.
+func (s *Server) defineDiffAccountSnapshotsTool() toolDefinition {
+	tool := mcp.NewTool("diff_account_snapshots",
+		mcp.WithDescription("Compare two account snapshot archives created by export_account_snapshot. "+
+			"Reports applications, channels, releases, and customers that were added or removed, "+
+			"and the fields that changed, to audit what changed in the vendor account between dates."),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("The older snapshot archive: its file name or full path in the snapshots directory"),
+		),
+		mcp.WithString("to",
+			mcp.Required(),
+			mcp.Description("The newer snapshot archive: its file name or full path in the snapshots directory"),
+		),
+		outputSchema[snapshot.Diff](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("diff_account_snapshots tool called", "arguments", request.GetArguments())
+
+		from, err := s.readSnapshotArgument(request, "from")
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := s.readSnapshotArgument(request, "to")
+		if err != nil {
+			return nil, err
+		}
+
+		diff, err := snapshot.Compare(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare snapshots: %w", err)
+		}
+
+		return jsonResult(ctx, diff)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// readSnapshotArgument loads the snapshot archive named by a tool argument
+func (s *Server) readSnapshotArgument(request mcp.CallToolRequest, name string) (*snapshot.Snapshot, error) {
+	archive, err := request.RequireString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := resolveSnapshotPath(s.snapshotDir(), archive)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.ReadArchive(path)
+}
+
+// snapshotDir returns the directory that holds snapshot archives
+func (s *Server) snapshotDir() string {
+	return filepath.Join(s.currentConfig().DataDirectory(), snapshotDirName)
+}
+
+// resolveSnapshotPath resolves an archive name or path, refusing paths outside the snapshots directory
+func resolveSnapshotPath(dir, archive string) (string, error) {
+	path := archive
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	rel, err := filepath.Rel(dir, filepath.Clean(path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("snapshot archive '%s' is not in the snapshots directory %s", archive, dir)
+	}
+
+	return filepath.Join(dir, rel), nil
+}
@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/jobs"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/snapshot"
+)
+
+// newTestSnapshotAPI serves the list endpoints read when exporting an account snapshot
+func newTestSnapshotAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/channels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"channels": [{"id": "channel-1", "name": "Stable"}, {"id": "channel-2", "name": "Beta"}]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/releases", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"releases": [{"id": "release-1", "version": "1.0.0", "sequence": 1}]}`)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"customers": [{"id": "customer-1", "name": "Acme", "email": "ops@acme.example"}]}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExportAccountSnapshotTool(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             map[string]any
+		expectedRedacted bool
+		expectedName     string
+	}{
+		{
+			name:         "full export",
+			args:         map[string]any{},
+			expectedName: "Acme",
+		},
+		{
+			name:             "redacted export",
+			args:             map[string]any{"redact": true},
+			expectedRedacted: true,
+			expectedName:     "[REDACTED]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI := newTestSnapshotAPI(t)
+			server := newTestServer(t, vendorAPI.URL)
+			server.config.DataDir = t.TempDir()
+			t.Cleanup(server.jobs.Shutdown)
+
+			tool, ok := server.registry.Tool("export_account_snapshot")
+			if !ok {
+				t.Fatal("Tool 'export_account_snapshot' not found")
+			}
+
+			request := createMockCallToolRequest("export_account_snapshot", tt.args)
+			result, err := tool.handler(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			started := server.jobs.List()
+			if len(started) != 1 {
+				t.Fatalf("Expected one background job, got %d", len(started))
+			}
+			if !contains(resultText(t, result), started[0].ID) {
+				t.Errorf("Expected response to include job ID '%s'", started[0].ID)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			job, err := server.jobs.Wait(ctx, started[0].ID)
+			if err != nil {
+				t.Fatalf("Unexpected error waiting for export: %v", err)
+			}
+			if job.Status != jobs.StatusSucceeded {
+				t.Fatalf("Expected export to succeed, got %s: %s", job.Status, job.Error)
+			}
+
+			export, ok := job.Result.(*snapshotExport)
+			if !ok {
+				t.Fatalf("Expected snapshot export result, got %T", job.Result)
+			}
+
+			expectedCounts := snapshot.Counts{Applications: 1, Channels: 2, Releases: 1, Customers: 1}
+			if export.Counts != expectedCounts {
+				t.Errorf("Expected counts %+v, got %+v", expectedCounts, export.Counts)
+			}
+
+			archived, err := snapshot.ReadArchive(export.Path)
+			if err != nil {
+				t.Fatalf("Failed to read exported archive: %v", err)
+			}
+			if archived.Redacted != tt.expectedRedacted {
+				t.Errorf("Expected redacted = %t, got %t", tt.expectedRedacted, archived.Redacted)
+			}
+			if name := archived.Applications[0].Customers[0].Name; name != tt.expectedName {
+				t.Errorf("Expected customer name '%s', got '%s'", tt.expectedName, name)
+			}
+		})
+	}
+}
+
+func TestExportAccountSnapshotTool_Failure(t *testing.T) {
+	// The vendor API lists applications but serves none of their releases, channels, or
+	// customers, so collection fails
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	vendorAPI := httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	server := newTestServer(t, vendorAPI.URL)
+	server.config.DataDir = t.TempDir()
+	t.Cleanup(server.jobs.Shutdown)
+
+	tool, _ := server.registry.Tool("export_account_snapshot")
+	request := createMockCallToolRequest("export_account_snapshot", nil)
+	if _, err := tool.handler(context.Background(), request); err != nil {
+		t.Fatalf("Unexpected error starting export: %v", err)
+	}
+
+	job, err := server.jobs.Wait(context.Background(), server.jobs.List()[0].ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if job.Status != jobs.StatusFailed || job.Error == "" {
+		t.Errorf("Expected failed export with an error, got %s", job.Status)
+	}
+}
+
+func TestDiffAccountSnapshotsTool(t *testing.T) {
+	server := newTestServer(t, "")
+	server.config.DataDir = t.TempDir()
+
+	sink := snapshot.NewFileSink(server.snapshotDir())
+	app := models.Application{ID: testAppID, Name: "Test App"}
+
+	fromPath, err := sink.Write(&snapshot.Snapshot{
+		FormatVersion: snapshot.FormatVersion,
+		CreatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Applications: []snapshot.ApplicationSnapshot{{
+			Application: app,
+			Customers:   []models.Customer{{ID: "customer-1", Name: "Acme", ChannelName: "Beta"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	toPath, err := sink.Write(&snapshot.Snapshot{
+		FormatVersion: snapshot.FormatVersion,
+		CreatedAt:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Applications: []snapshot.ApplicationSnapshot{{
+			Application: app,
+			Customers: []models.Customer{
+				{ID: "customer-1", Name: "Acme", ChannelName: "Stable"},
+				{ID: "customer-2", Name: "Globex"},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	tool, ok := server.registry.Tool("diff_account_snapshots")
+	if !ok {
+		t.Fatal("Tool 'diff_account_snapshots' not found")
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		expectError  bool
+		expectInText []string
+	}{
+		{
+			name:         "compare by file name",
+			args:         map[string]any{"from": filepath.Base(fromPath), "to": filepath.Base(toPath)},
+			expectInText: []string{`"id": "customer-2"`, `"field": "channelName"`, `"to": "Stable"`},
+		},
+		{
+			name:         "compare by full path",
+			args:         map[string]any{"from": fromPath, "to": toPath},
+			expectInText: []string{`"id": "customer-2"`},
+		},
+		{
+			name:        "missing archive",
+			args:        map[string]any{"from": "missing.tar.gz", "to": filepath.Base(toPath)},
+			expectError: true,
+		},
+		{
+			name:        "path outside the snapshots directory",
+			args:        map[string]any{"from": "../../etc/passwd", "to": filepath.Base(toPath)},
+			expectError: true,
+		},
+		{
+			name:        "missing argument",
+			args:        map[string]any{"from": filepath.Base(fromPath)},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := createMockCallToolRequest("diff_account_snapshots", tt.args)
+			result, err := tool.handler(context.Background(), request)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			text := resultText(t, result)
+			for _, expected := range tt.expectInText {
+				if !contains(text, expected) {
+					t.Errorf("Expected response containing '%s', got '%s'", expected, text)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveSnapshotPath(t *testing.T) {
+	dir := filepath.Join(string(filepath.Separator), "data", "snapshots")
+
+	tests := []struct {
+		name        string
+		archive     string
+		expected    string
+		expectError bool
+	}{
+		{name: "file name", archive: "a.tar.gz", expected: filepath.Join(dir, "a.tar.gz")},
+		{name: "absolute path inside", archive: filepath.Join(dir, "a.tar.gz"), expected: filepath.Join(dir, "a.tar.gz")},
+		{name: "relative escape", archive: "../secrets.tar.gz", expectError: true},
+		{name: "absolute path outside", archive: "/etc/passwd", expectError: true},
+		{name: "directory itself", archive: dir, expectError: true},
+		{name: "dot-dot prefixed name", archive: "..archive.tar.gz", expected: filepath.Join(dir, "..archive.tar.gz")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSnapshotPath(dir, tt.archive)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for '%s', got %s", tt.archive, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("resolveSnapshotPath() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
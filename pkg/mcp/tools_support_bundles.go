@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/render"
+)
+
+// supportBundleAnalysis is the structured analyzer output returned by get_support_bundle
+type supportBundleAnalysis struct {
+	Bundle    *models.SupportBundle   `json:"bundle"`
+	HasErrors bool                    `json:"has_errors"`
+	Summary   map[string]int          `json:"summary"`
+	Results   []models.AnalyzerResult `json:"results"`
+	Report    string                  `json:"report"`
+}
+
+// Support Bundle Tools
+
+// defineListSupportBundlesTool creates the list_support_bundles tool definition.
+// Lists support bundles uploaded for an application, optionally for a single customer or instance.
+func (s *Server) defineListSupportBundlesTool() toolDefinition {
+	tool := mcp.NewTool("list_support_bundles",
+		mcp.WithDescription("List support bundles uploaded for a specific application. "+
+			"Can be narrowed to a single customer or instance to triage a reported issue."),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("customer_id",
+			mcp.Description("Only return bundles uploaded by this customer"),
+		),
+		mcp.WithString("instance_id",
+			mcp.Description("Only return bundles collected from this instance"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of support bundles to return (1-100)"),
+			mcp.Min(minLimit),
+			mcp.Max(maxListLimit),
+		),
+		cursorParameter("support bundles"),
+		outputSchema[[]models.SupportBundle](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_support_bundles tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := s.supportBundles.ListSupportBundles(ctx, appID, &api.ListSupportBundlesOptions{
+			CustomerID: request.GetString("customer_id", ""),
+			InstanceID: request.GetString("instance_id", ""),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list support bundles: %w", err)
+		}
+
+		page, err := pageOf(request, list.SupportBundles)
+		if err != nil {
+			return nil, err
+		}
+
+		return envelopeResult(ctx, page)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineGetSupportBundleTool creates the get_support_bundle tool definition.
+// Retrieves a support bundle along with its analyzer results as structured JSON.
+func (s *Server) defineGetSupportBundleTool() toolDefinition {
+	tool := mcp.NewTool("get_support_bundle",
+		mcp.WithDescription("Get a specific support bundle and its troubleshoot analysis. "+
+			"Returns analyzer results with a per-severity summary so issues can be triaged directly."),
+		mcp.WithString("app_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier or slug of the application"),
+		),
+		mcp.WithString("bundle_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the support bundle"),
+		),
+		localeParameter(),
+		responseLanguageParameter(),
+		outputSchema[supportBundleAnalysis](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_support_bundle tool called", "arguments", request.GetArguments())
+
+		appID, err := s.resolveAppID(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		bundleID, err := request.RequireString("bundle_id")
+		if err != nil {
+			return nil, err
+		}
+
+		formatter, err := s.formatter(request)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle, err := s.supportBundles.GetSupportBundle(ctx, bundleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get support bundle: %w", err)
+		}
+
+		if bundle.ApplicationID != "" && bundle.ApplicationID != appID {
+			return nil, fmt.Errorf("support bundle '%s' does not belong to application '%s'", bundleID, appID)
+		}
+
+		return jsonResult(ctx, analyzeSupportBundle(formatter, bundle))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// analyzeSupportBundle summarizes a support bundle's analyzer results by severity, with a
+// report rendered for the caller's locale
+func analyzeSupportBundle(formatter *render.Formatter, bundle *models.SupportBundle) supportBundleAnalysis {
+	results := bundle.Analysis
+	if results == nil {
+		results = []models.AnalyzerResult{}
+	}
+
+	return supportBundleAnalysis{
+		Bundle:    bundle,
+		HasErrors: bundle.HasErrors(),
+		Summary:   bundle.AnalysisSummary(),
+		Results:   results,
+		Report:    render.SupportBundleReport(formatter, bundle),
+	}
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+const testSupportBundleJSON = `{
+	"id": "bundle-1",
+	"appId": "test-app-123",
+	"customerId": "customer-1",
+	"instanceId": "instance-1",
+	"status": "analyzed",
+	"uploadedAt": "2024-01-01T00:00:00Z",
+	"analysis": [
+		{"name": "storage-class", "severity": "error", "message": "No default storage class"},
+		{"name": "node-count", "severity": "pass", "message": "Cluster has 3 nodes"},
+		{"name": "k8s-version", "severity": "warn", "message": "Kubernetes 1.27 is nearing end of life"}
+	]
+}`
+
+// newTestSupportBundleAPI serves the application and support bundle endpoints used by the support bundle tools
+func newTestSupportBundleAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/supportbundles", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("customerId") == "nobody" {
+			fmt.Fprint(w, `{"supportBundles": []}`)
+			return
+		}
+		fmt.Fprintf(w, `{"supportBundles": [%s]}`, testSupportBundleJSON)
+	})
+	mux.HandleFunc("/vendor/v3/supportbundle/bundle-1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, testSupportBundleJSON)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListSupportBundlesTool(t *testing.T) {
+	vendorAPI := newTestSupportBundleAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("list_support_bundles")
+	if !ok {
+		t.Fatal("Tool 'list_support_bundles' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expectError   bool
+		expectedCount int
+	}{
+		{
+			name:          "list by application slug",
+			args:          map[string]any{"app_id": testAppSlug},
+			expectedCount: 1,
+		},
+		{
+			name:          "list for customer without bundles",
+			args:          map[string]any{"app_id": testAppID, "customer_id": "nobody"},
+			expectedCount: 0,
+		},
+		{
+			name: "cursor past the end",
+			args: map[string]any{
+				"app_id": testAppID, "cursor": encodeCursor("list_support_bundles", 5),
+			},
+			expectedCount: 0,
+		},
+		{
+			name:        "cursor from another list",
+			args:        map[string]any{"app_id": testAppID, "cursor": encodeCursor("list_applications", 1)},
+			expectError: true,
+		},
+		{
+			name:        "unknown application",
+			args:        map[string]any{"app_id": "unknown-app"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_support_bundles", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			page := decodeEnvelope[[]map[string]any](t, result)
+			if len(page.Data) != tt.expectedCount || page.Pagination.NextCursor != "" {
+				t.Errorf("Expected %d support bundles on the last page, got %s", tt.expectedCount, resultText(t, result))
+			}
+		})
+	}
+}
+
+func TestGetSupportBundleTool(t *testing.T) {
+	vendorAPI := newTestSupportBundleAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("get_support_bundle")
+	if !ok {
+		t.Fatal("Tool 'get_support_bundle' not found")
+	}
+
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("get_support_bundle",
+		map[string]any{"app_id": testAppSlug, "bundle_id": "bundle-1"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	response, ok := result.StructuredContent.(envelope)
+	if !ok {
+		t.Fatalf("Expected a structured response, got %T", result.StructuredContent)
+	}
+	analysis, ok := response.Data.(supportBundleAnalysis)
+	if !ok {
+		t.Fatalf("Expected structured support bundle analysis, got %T", response.Data)
+	}
+
+	if !analysis.HasErrors {
+		t.Error("Expected analysis to report errors")
+	}
+	if len(analysis.Results) != 3 {
+		t.Errorf("Expected 3 analyzer results, got %d", len(analysis.Results))
+	}
+
+	expectedSummary := map[string]int{"pass": 1, "info": 0, "warn": 1, "error": 1}
+	for severity, count := range expectedSummary {
+		if analysis.Summary[severity] != count {
+			t.Errorf("Expected %d '%s' results, got %d", count, severity, analysis.Summary[severity])
+		}
+	}
+
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	if !contains(textContent.Text, "No default storage class") {
+		t.Errorf("Expected text content to include analyzer messages, got '%s'", textContent.Text)
+	}
+
+	if !contains(analysis.Report, "66.7% need attention") {
+		t.Errorf("Expected report formatted for the default locale, got '%s'", analysis.Report)
+	}
+
+	localized, err := tool.handler(context.Background(), createMockCallToolRequest("get_support_bundle",
+		map[string]any{"app_id": testAppID, "bundle_id": "bundle-1", "locale": "de-DE"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	report := localized.StructuredContent.(envelope).Data.(supportBundleAnalysis).Report
+	if !contains(report, "66,7\u00a0%") {
+		t.Errorf("Expected report formatted for de-DE, got '%s'", report)
+	}
+
+	translated, err := tool.handler(context.Background(), createMockCallToolRequest("get_support_bundle",
+		map[string]any{"app_id": testAppID, "bundle_id": "bundle-1", "response_language": "de"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	translatedAnalysis := translated.StructuredContent.(envelope).Data.(supportBundleAnalysis)
+	if !contains(translatedAnalysis.Report, "66.7% erfordern Aufmerksamkeit") {
+		t.Errorf("Expected German report text with en-US numbers, got '%s'", translatedAnalysis.Report)
+	}
+	if translatedAnalysis.Results[0].Message != analysis.Results[0].Message {
+		t.Errorf("Expected analyzer messages to be left untranslated, got '%s'", translatedAnalysis.Results[0].Message)
+	}
+
+	errorTests := []struct {
+		name string
+		args map[string]any
+	}{
+		{name: "missing bundle ID", args: map[string]any{"app_id": testAppID}},
+		{name: "unknown bundle", args: map[string]any{"app_id": testAppID, "bundle_id": "missing"}},
+		{name: "unknown application", args: map[string]any{"app_id": "unknown-app", "bundle_id": "bundle-1"}},
+		{name: "unsupported locale", args: map[string]any{"app_id": testAppID, "bundle_id": "bundle-1", "locale": "xx"}},
+		{
+			name: "unsupported response language",
+			args: map[string]any{"app_id": testAppID, "bundle_id": "bundle-1", "response_language": "zz"},
+		},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := createMockCallToolRequest("get_support_bundle", tt.args)
+			if _, err := tool.handler(context.Background(), request); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestAnalyzeSupportBundle(t *testing.T) {
+	formatter, err := newTestServer(t, "").formatter(createMockCallToolRequest("get_support_bundle", nil))
+	if err != nil {
+		t.Fatalf("Failed to create formatter: %v", err)
+	}
+
+	analysis := analyzeSupportBundle(formatter, &models.SupportBundle{ID: "bundle-2", Status: "uploaded"})
+	if analysis.HasErrors || analysis.Results == nil || len(analysis.Results) != 0 {
+		t.Errorf("Expected an empty analysis for a bundle not yet analyzed, got %+v", analysis)
+	}
+	data, _ := json.Marshal(analysis)
+	if !contains(string(data), `"results":[]`) {
+		t.Errorf("Expected results encoded as an empty list, got %s", data)
+	}
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+)
+
+// entityTags reports an entity's tags after they were changed
+type entityTags struct {
+	EntityType string   `json:"entity_type"`
+	EntityID   string   `json:"entity_id"`
+	Tags       []string `json:"tags"`
+}
+
+// Tag Tools
+
+// defineTagEntityTool creates the tag_entity tool definition.
+// Adds or removes tags on an application or customer in the server's local metadata store.
+func (s *Server) defineTagEntityTool() toolDefinition {
+	tool := mcp.NewTool("tag_entity",
+		mcp.WithDescription("Add tags to an application or customer, or remove them, to organize a large "+
+			"portfolio (for example by region, tier, or account owner). Tags are stored by this server in its "+
+			"data directory, not in the Vendor Portal. Returns the entity's tags after the change."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("entity_type",
+			mcp.Required(),
+			mcp.Description("The kind of entity to tag"),
+			mcp.Enum(store.EntityTypes...),
+		),
+		mcp.WithString("entity_id",
+			mcp.Required(),
+			mcp.Description("The application ID or slug, or the customer ID"),
+		),
+		mcp.WithArray("tags",
+			mcp.Required(),
+			mcp.Description("Tags to add or remove; letters, digits, and . _ : / - (e.g. region:eu, tier-1)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("remove",
+			mcp.Description("Remove the tags instead of adding them (default false)"),
+		),
+		outputSchema[entityTags](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("tag_entity tool called", "arguments", request.GetArguments())
+
+		entityType, entityID, err := s.resolveEntity(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err := request.RequireStringSlice("tags")
+		if err != nil {
+			return nil, err
+		}
+
+		metadata := s.stateStore()
+		if request.GetBool("remove", false) {
+			tags, err = metadata.RemoveTags(entityType, entityID, tags)
+		} else {
+			tags, err = metadata.AddTags(entityType, entityID, tags)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update tags: %w", err)
+		}
+
+		return jsonResult(ctx, entityTags{EntityType: entityType, EntityID: entityID, Tags: tags})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineListByTagTool creates the list_by_tag tool definition.
+// Lists the applications and customers carrying a tag.
+func (s *Server) defineListByTagTool() toolDefinition {
+	tool := mcp.NewTool("list_by_tag",
+		mcp.WithDescription("List the applications and customers carrying a tag set with tag_entity. "+
+			"Returns each entity's type, ID, and all of its tags."),
+		mcp.WithString("tag",
+			mcp.Required(),
+			mcp.Description("The tag to look for"),
+		),
+		mcp.WithString("entity_type",
+			mcp.Description("Only list entities of this kind (default all)"),
+			mcp.Enum(store.EntityTypes...),
+		),
+		outputSchema[[]store.TaggedEntity](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_by_tag tool called", "arguments", request.GetArguments())
+
+		tag, err := request.RequireString("tag")
+		if err != nil {
+			return nil, err
+		}
+
+		entities, err := s.stateStore().ListByTag(tag, request.GetString("entity_type", ""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tagged entities: %w", err)
+		}
+
+		return envelopeResult(ctx, listOf(entities))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// resolveEntity reads the entity_type and entity_id arguments, resolving application
+// slugs to IDs so an application's metadata is the same whichever was given
+func (s *Server) resolveEntity(ctx context.Context, request mcp.CallToolRequest) (string, string, error) {
+	entityType, err := request.RequireString("entity_type")
+	if err != nil {
+		return "", "", err
+	}
+
+	entityID, err := request.RequireString("entity_id")
+	if err != nil {
+		return "", "", err
+	}
+
+	if entityType == store.EntityApplication {
+		entityID, err = s.resolver.ResolveApplicationID(ctx, entityID)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return entityType, entityID, nil
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/store"
+)
+
+func TestTagTools(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+	server.config.DataDir = t.TempDir()
+
+	calls := []struct {
+		name       string
+		args       map[string]any
+		expectTags []string
+	}{
+		{
+			name:       "tag an application by slug",
+			args:       map[string]any{"entity_type": "application", "entity_id": testAppSlug, "tags": []any{"Enterprise"}},
+			expectTags: []string{"enterprise"},
+		},
+		{
+			name: "tag a customer",
+			args: map[string]any{
+				"entity_type": "customer", "entity_id": "customer-1", "tags": []any{"enterprise", "airgap"},
+			},
+			expectTags: []string{"airgap", "enterprise"},
+		},
+		{
+			name: "remove a tag",
+			args: map[string]any{
+				"entity_type": "customer", "entity_id": "customer-1", "tags": []any{"airgap"}, "remove": true,
+			},
+			expectTags: []string{"enterprise"},
+		},
+	}
+
+	tagTool, ok := server.registry.Tool("tag_entity")
+	if !ok {
+		t.Fatal("Tool 'tag_entity' not found")
+	}
+	for _, call := range calls {
+		t.Run(call.name, func(t *testing.T) {
+			result, err := tagTool.handler(context.Background(), createMockCallToolRequest("tag_entity", call.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got entityTags
+			if err := json.Unmarshal(resultData(t, result), &got); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if !slices.Equal(got.Tags, call.expectTags) {
+				t.Errorf("Expected tags %v, got %v", call.expectTags, got.Tags)
+			}
+		})
+	}
+
+	t.Run("reject an unknown entity type", func(t *testing.T) {
+		args := map[string]any{"entity_type": "release", "entity_id": "release-1", "tags": []any{"a"}}
+		if _, err := tagTool.handler(context.Background(), createMockCallToolRequest("tag_entity", args)); err == nil {
+			t.Error("Expected error but got none")
+		}
+	})
+
+	listTool, ok := server.registry.Tool("list_by_tag")
+	if !ok {
+		t.Fatal("Tool 'list_by_tag' not found")
+	}
+	result, err := listTool.handler(context.Background(),
+		createMockCallToolRequest("list_by_tag", map[string]any{"tag": "enterprise"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var entities []store.TaggedEntity
+	if err := json.Unmarshal(resultData(t, result), &entities); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(entities) != 2 || entities[0].EntityID != testAppID || entities[1].EntityID != "customer-1" {
+		t.Errorf("Expected the application (by ID) and customer, got %+v", entities)
+	}
+}
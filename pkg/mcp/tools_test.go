@@ -2,6 +2,14 @@ package mcp
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,39 +17,33 @@ import (
 
 	"github.com/crdant/replicated-mcp-server/pkg/config"
 	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
 )
 
 func TestToolHandlers(t *testing.T) {
-	cfg := &config.Config{
-		APIToken: "test-token",
-		LogLevel: "info",
-		Timeout:  30 * time.Second,
-	}
-	logger := logging.NewLogger("info")
-
-	server, err := NewServer(cfg, logger)
-	if err != nil {
-		t.Fatalf("Failed to create server: %v", err)
-	}
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
 
-	tools := server.defineTools()
+	tools := server.registry.Tools()
 
 	tests := []struct {
-		toolName string
-		args     map[string]any
+		toolName     string
+		args         map[string]any
+		expectInText string
 	}{
 		{
 			toolName: "list_applications",
 			args: map[string]any{
-				"limit":  float64(10),
-				"offset": float64(0),
+				"limit": float64(10),
 			},
+			expectInText: testAppID,
 		},
 		{
 			toolName: "get_application",
 			args: map[string]any{
 				"app_id": "test-app-123",
 			},
+			expectInText: testAppID,
 		},
 		{
 			toolName: "search_applications",
@@ -49,44 +51,48 @@ func TestToolHandlers(t *testing.T) {
 				"query": "test app",
 				"limit": float64(5),
 			},
+			expectInText: testAppID,
 		},
 		{
 			toolName: "list_releases",
 			args: map[string]any{
 				"app_id": "test-app-123",
 				"limit":  float64(10),
-				"offset": float64(0),
 			},
+			expectInText: `"release-2"`,
 		},
 		{
 			toolName: "get_release",
 			args: map[string]any{
 				"app_id":     "test-app-123",
-				"release_id": "test-release-456",
+				"release_id": "release-2",
 			},
+			expectInText: "kind: Service",
 		},
 		{
 			toolName: "search_releases",
 			args: map[string]any{
 				"app_id": "test-app-123",
-				"query":  "v1.0",
+				"query":  "1.0",
 				"limit":  float64(5),
 			},
+			expectInText: `"release-1"`,
 		},
 		{
 			toolName: "list_channels",
 			args: map[string]any{
 				"app_id": "test-app-123",
 				"limit":  float64(10),
-				"offset": float64(0),
 			},
+			expectInText: `"channel-2"`,
 		},
 		{
 			toolName: "get_channel",
 			args: map[string]any{
 				"app_id":     "test-app-123",
-				"channel_id": "test-channel-789",
+				"channel_id": "beta",
 			},
+			expectInText: `"channel-2"`,
 		},
 		{
 			toolName: "search_channels",
@@ -95,21 +101,23 @@ func TestToolHandlers(t *testing.T) {
 				"query":  "stable",
 				"limit":  float64(5),
 			},
+			expectInText: `"channel-1"`,
 		},
 		{
 			toolName: "list_customers",
 			args: map[string]any{
 				"app_id": "test-app-123",
 				"limit":  float64(10),
-				"offset": float64(0),
 			},
+			expectInText: `"customer-1"`,
 		},
 		{
 			toolName: "get_customer",
 			args: map[string]any{
 				"app_id":      "test-app-123",
-				"customer_id": "test-customer-101",
+				"customer_id": "customer-2",
 			},
+			expectInText: `"Globex"`,
 		},
 		{
 			toolName: "search_customers",
@@ -118,6 +126,7 @@ func TestToolHandlers(t *testing.T) {
 				"query":  "acme corp",
 				"limit":  float64(5),
 			},
+			expectInText: `"customer-1"`,
 		},
 	}
 
@@ -158,21 +167,156 @@ func TestToolHandlers(t *testing.T) {
 				return
 			}
 
-			// Verify the placeholder response (will be replaced in Step 7)
 			textContent, ok := result.Content[0].(mcp.TextContent)
 			if !ok {
 				t.Error("Expected TextContent")
 				return
 			}
 
-			expectedMessage := step7ImplementationMsg
-			if !contains(textContent.Text, expectedMessage) {
-				t.Errorf("Expected placeholder message containing '%s', got '%s'", expectedMessage, textContent.Text)
+			if !contains(textContent.Text, tt.expectInText) {
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, textContent.Text)
 			}
 		})
 	}
 }
 
+func TestToolHandlersResolveSlugs(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tools := server.registry.Tools()
+
+	tests := []struct {
+		toolName     string
+		args         map[string]any
+		expectError  bool
+		expectInText string
+	}{
+		{
+			toolName:     "get_application",
+			args:         map[string]any{"app_id": testAppSlug},
+			expectInText: `"id": "test-app-123"`,
+		},
+		{
+			toolName:     "list_releases",
+			args:         map[string]any{"app_id": testAppSlug},
+			expectInText: `"release-1"`,
+		},
+		{
+			toolName:    "get_customer",
+			args:        map[string]any{"app_id": "unknown-app", "customer_id": "customer-1"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toolName, func(t *testing.T) {
+			var tool *toolDefinition
+			for _, toolDef := range tools {
+				if toolDef.definition.Name == tt.toolName {
+					tool = &toolDef
+					break
+				}
+			}
+
+			if tool == nil {
+				t.Fatalf("Tool '%s' not found", tt.toolName)
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest(tt.toolName, tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error for unknown application")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			textContent, ok := result.Content[0].(mcp.TextContent)
+			if !ok {
+				t.Fatal("Expected TextContent")
+			}
+
+			if !contains(textContent.Text, tt.expectInText) {
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, textContent.Text)
+			}
+		})
+	}
+}
+
+func TestToolHandlersDefaultApplication(t *testing.T) {
+	vendorAPI := newTestVendorAPI(t)
+
+	tests := []struct {
+		name        string
+		app         string
+		args        map[string]any
+		expectError bool
+	}{
+		{name: "default application", app: testAppSlug, args: map[string]any{}},
+		{name: "explicit app_id wins", app: "unknown-app", args: map[string]any{"app_id": testAppID}},
+		{name: "no default application", args: map[string]any{}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, vendorAPI.URL)
+			server.config.App = tt.app
+
+			tool, ok := server.registry.Tool("get_application")
+			if !ok {
+				t.Fatal("Tool 'get_application' not found")
+			}
+
+			registered := server.serverTool(tool).Tool
+			if required := slices.Contains(registered.InputSchema.Required, "app_id"); required != (tt.app == "") {
+				t.Errorf("Expected app_id required = %t, got %t", tt.app == "", required)
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("get_application", tt.args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected an error without app_id or a default application")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !contains(resultText(t, result), `"id": "test-app-123"`) {
+				t.Errorf("Expected the default application, got %s", resultText(t, result))
+			}
+		})
+	}
+}
+
+func TestWithDefaultApp(t *testing.T) {
+	tool := mcp.NewTool("get_customer",
+		mcp.WithString("app_id", mcp.Required(), mcp.Description("The application")),
+		mcp.WithString("customer_id", mcp.Required(), mcp.Description("The customer")),
+	)
+
+	optional := withDefaultApp(tool, testAppSlug)
+	if !reflect.DeepEqual(optional.InputSchema.Required, []string{"customer_id"}) {
+		t.Errorf("Expected only customer_id to be required, got %v", optional.InputSchema.Required)
+	}
+	description := optional.InputSchema.Properties["app_id"].(map[string]any)["description"]
+	if description != "The application (defaults to test-app)" {
+		t.Errorf("Unexpected app_id description %q", description)
+	}
+
+	if !reflect.DeepEqual(tool.InputSchema.Required, []string{"app_id", "customer_id"}) ||
+		tool.InputSchema.Properties["app_id"].(map[string]any)["description"] != "The application" {
+		t.Error("Expected the original tool to be unchanged")
+	}
+	if unchanged := withDefaultApp(tool, ""); !reflect.DeepEqual(unchanged, tool) {
+		t.Error("Expected no change without a default application")
+	}
+}
+
 func TestToolParameterValidation(t *testing.T) {
 	cfg := &config.Config{
 		APIToken: "test-token",
@@ -186,7 +330,7 @@ func TestToolParameterValidation(t *testing.T) {
 		t.Fatalf("Failed to create server: %v", err)
 	}
 
-	tools := server.defineTools()
+	tools := server.registry.Tools()
 
 	// Test that tools have proper parameter definitions
 	parameterTests := []struct {
@@ -196,7 +340,7 @@ func TestToolParameterValidation(t *testing.T) {
 	}{
 		{
 			toolName:           "list_applications",
-			expectedParameters: []string{"limit", "offset"},
+			expectedParameters: []string{"limit", "cursor"},
 			requiredParams:     []string{}, // Both are optional
 		},
 		{
@@ -211,13 +355,13 @@ func TestToolParameterValidation(t *testing.T) {
 		},
 		{
 			toolName:           "list_releases",
-			expectedParameters: []string{"app_id", "limit", "offset"},
+			expectedParameters: []string{"app_id", "channel_id", "limit", "cursor"},
 			requiredParams:     []string{"app_id"},
 		},
 		{
 			toolName:           "get_release",
-			expectedParameters: []string{"app_id", "release_id"},
-			requiredParams:     []string{"app_id", "release_id"},
+			expectedParameters: []string{"app_id", "sequence", "version", "release_id"},
+			requiredParams:     []string{"app_id"},
 		},
 	}
 
@@ -251,6 +395,167 @@ func TestToolParameterValidation(t *testing.T) {
 }
 
 // Helper function to create a mock CallToolRequest
+func TestSearchCustomersToolScanLimit(t *testing.T) {
+	const totalCustomers = 250
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customers", func(w http.ResponseWriter, r *http.Request) {
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("currentPage"))
+		customers := []string{}
+		for i := page * pageSize; i < min(totalCustomers, (page+1)*pageSize); i++ {
+			customers = append(customers, fmt.Sprintf(`{"id": "customer-%d", "name": "Customer %d"}`, i, i))
+		}
+		fmt.Fprintf(w, `{"customers": [%s], "totalCustomers": %d}`, strings.Join(customers, ","), totalCustomers)
+	})
+	vendorAPI := httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+
+	server := newTestServer(t, vendorAPI.URL)
+	tool, ok := server.registry.Tool("search_customers")
+	if !ok {
+		t.Fatal("Tool 'search_customers' not found")
+	}
+
+	tests := []struct {
+		name          string
+		scanLimit     int
+		expectedCount int
+		wantTruncated bool
+	}{
+		{name: "every customer searched", scanLimit: 0, expectedCount: 1},
+		{name: "match beyond the scan limit", scanLimit: 200, expectedCount: 0, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *server.currentConfig()
+			cfg.SearchScanLimit = tt.scanLimit
+			if err := server.Reconfigure(&cfg); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("search_customers",
+				map[string]any{"app_id": testAppID, "query": "Customer 242"}))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			list := decodeEnvelope[[]models.Customer](t, result)
+			if len(list.Data) != tt.expectedCount {
+				t.Errorf("Expected %d customers, got %d", tt.expectedCount, len(list.Data))
+			}
+			if list.Pagination.Truncated != tt.wantTruncated {
+				t.Errorf("Expected truncated = %t, got %t", tt.wantTruncated, list.Pagination.Truncated)
+			}
+			if (len(list.Warnings) > 0) != tt.wantTruncated {
+				t.Errorf("Expected a warning only when the search was capped, got %v", list.Warnings)
+			}
+			if contains(resultText(t, result), `"truncated"`) != tt.wantTruncated {
+				t.Errorf("Expected truncated to be reported only when the search was capped, got %s",
+					resultText(t, result))
+			}
+		})
+	}
+}
+
+func TestListCustomersToolFilters(t *testing.T) {
+	// The API ignores the filter parameters, so the tool's own filtering is tested
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vendor/v3/apps", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"applications": [%s]}`, testAppJSON)
+	})
+	mux.HandleFunc("/vendor/v3/app/"+testAppID+"/customers", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"totalCustomers": 4, "customers": [
+			{"id": "customer-1", "name": "Acme", "type": "paid", "licenseType": "paid", "channelId": "channel-1",
+			 "expiresAt": "2025-06-30T00:00:00Z"},
+			{"id": "customer-2", "name": "Globex", "type": "trial", "licenseType": "trial", "channelId": "channel-2",
+			 "expiresAt": "2025-01-31T00:00:00Z"},
+			{"id": "customer-3", "name": "Initech", "type": "paid", "licenseType": "paid", "channelId": "channel-1",
+			 "isArchived": true},
+			{"id": "customer-4", "name": "Umbrella", "type": "development", "licenseType": "development",
+			 "channelId": "channel-2"}
+		]}`)
+	})
+	vendorAPI := httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+
+	server := newTestServer(t, vendorAPI.URL)
+	tool, ok := server.registry.Tool("list_customers")
+	if !ok {
+		t.Fatal("Tool 'list_customers' not found")
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		wantIDs     []string
+		expectError bool
+	}{
+		{
+			name:    "no filters",
+			args:    map[string]any{},
+			wantIDs: []string{"customer-1", "customer-2", "customer-3", "customer-4"},
+		},
+		{name: "type", args: map[string]any{"type": "paid"}, wantIDs: []string{"customer-1", "customer-3"}},
+		{name: "license type", args: map[string]any{"license_type": "trial"}, wantIDs: []string{"customer-2"}},
+		{
+			name:    "not archived",
+			args:    map[string]any{"is_archived": false},
+			wantIDs: []string{"customer-1", "customer-2", "customer-4"},
+		},
+		{name: "archived", args: map[string]any{"is_archived": true}, wantIDs: []string{"customer-3"}},
+		{name: "channel", args: map[string]any{"channel_id": "channel-2"}, wantIDs: []string{"customer-2", "customer-4"}},
+		{
+			name:    "expiring before a date",
+			args:    map[string]any{"expiring_before": "2025-03-01"},
+			wantIDs: []string{"customer-2"},
+		},
+		{
+			name:    "expiring before a timestamp",
+			args:    map[string]any{"expiring_before": "2025-07-01T00:00:00Z"},
+			wantIDs: []string{"customer-1", "customer-2"},
+		},
+		{
+			name:    "combined filters",
+			args:    map[string]any{"type": "paid", "is_archived": false, "channel_id": "channel-1"},
+			wantIDs: []string{"customer-1"},
+		},
+		{name: "no matches", args: map[string]any{"license_type": "community"}, wantIDs: []string{}},
+		{name: "invalid expiry", args: map[string]any{"expiring_before": "next week"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"app_id": testAppID}
+			maps.Copy(args, tt.args)
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_customers", args))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			page := decodeEnvelope[[]models.Customer](t, result)
+			ids := []string{}
+			for _, customer := range page.Data {
+				ids = append(ids, customer.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected customers %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
 func createMockCallToolRequest(toolName string, args map[string]any) mcp.CallToolRequest {
 	// Create a basic request structure
 	// Note: This is a simplified mock - in real usage, the MCP library would create these
@@ -265,6 +570,21 @@ func createMockCallToolRequest(toolName string, args map[string]any) mcp.CallToo
 	}
 }
 
+// Helper function to extract the text of a tool result's first content item
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	if result == nil || len(result.Content) == 0 {
+		t.Fatal("Expected content in result")
+	}
+
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatal("Expected TextContent")
+	}
+	return textContent.Text
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) &&
@@ -284,3 +604,37 @@ func indexOfSubstring(s, substr string) int {
 	}
 	return -1
 }
+
+func TestListChannelsToolPaging(t *testing.T) {
+	server := newTestServer(t, newTestVendorAPI(t).URL)
+
+	tool, ok := server.registry.Tool("list_channels")
+	if !ok {
+		t.Fatal("Tool 'list_channels' not found")
+	}
+
+	// Following next_cursor lists each channel once and stops after the last page
+	var ids []string
+	args := map[string]any{"app_id": testAppSlug, "limit": 1}
+	for range 3 {
+		result, err := tool.handler(context.Background(), createMockCallToolRequest("list_channels", args))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		response := decodeEnvelope[[]models.Channel](t, result)
+		for _, channel := range response.Data {
+			ids = append(ids, channel.ID)
+		}
+		if response.Pagination.Total != 2 {
+			t.Errorf("Expected a total of 2 channels, got %d", response.Pagination.Total)
+		}
+		if response.Pagination.NextCursor == "" {
+			break
+		}
+		args["cursor"] = response.Pagination.NextCursor
+	}
+
+	if !slices.Equal(ids, []string{"channel-1", "channel-2"}) {
+		t.Errorf("Expected each channel listed once, got %v", ids)
+	}
+}
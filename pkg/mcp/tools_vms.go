@@ -0,0 +1,294 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Compatibility Matrix VM Tools
+
+// vmSummary is a VM with the whole minutes left until it expires
+type vmSummary struct {
+	models.VM
+	ExpiresInMinutes *int `json:"expires_in_minutes,omitempty"`
+}
+
+// vmQuotaSummary reports the team's VM quota and how many more VMs it may start
+type vmQuotaSummary struct {
+	models.VMQuota
+	RemainingVMs int `json:"remaining_vms"`
+}
+
+// vmListing is the response of list_vms
+type vmListing struct {
+	VMs   []vmSummary     `json:"vms"`
+	Quota *vmQuotaSummary `json:"quota,omitempty"`
+}
+
+// deleteVMResult reports a VM's state after a delete_vm call
+type deleteVMResult struct {
+	VMID    string `json:"vm_id"`
+	VMName  string `json:"vm_name"`
+	Status  string `json:"status"`
+	Changed bool   `json:"changed"`
+}
+
+// vmSSHEndpoint is the SSH address of a VM and a command for connecting to it
+type vmSSHEndpoint struct {
+	VMID string `json:"vm_id"`
+	models.SSHEndpoint
+	Command string `json:"command"`
+}
+
+// defineListVMsTool creates the list_vms tool definition.
+// Lists the team's Compatibility Matrix VMs with their expiry and the VM quota.
+func (s *Server) defineListVMsTool() toolDefinition {
+	tool := mcp.NewTool("list_vms",
+		mcp.WithDescription("List the team's Compatibility Matrix VMs with their distribution, status, "+
+			"expiration, and minutes until they expire, and the team's VM quota with how many more VMs "+
+			"can be started. Terminated VMs are omitted unless requested."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithBoolean("include_terminated",
+			mcp.Description("Whether to include terminated VMs (default false)"),
+		),
+		outputSchema[vmListing](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("list_vms tool called", "arguments", request.GetArguments())
+
+		list, err := s.vms.ListVMs(ctx, request.GetBool("include_terminated", false))
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, summarizeVMs(list, time.Now()))
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// defineCreateVMTool creates the create_vm tool definition.
+// Requests an ephemeral Compatibility Matrix VM, such as for testing an embedded cluster install.
+func (s *Server) defineCreateVMTool() toolDefinition {
+	tool := mcp.NewTool("create_vm",
+		mcp.WithDescription("Create an ephemeral Compatibility Matrix VM, such as for testing an embedded "+
+			"cluster install. The VM is returned while it is queued or provisioning; poll list_vms until it "+
+			"is running, then use get_vm_ssh_endpoint to connect. Fails without calling the API when the "+
+			"team's VM quota is used up. VMs use credits until they expire or are deleted, so keep the "+
+			"ttl short. With dry_run, the VM and quota are checked and the VM described without creating it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("distribution",
+			mcp.Required(),
+			mcp.Description("The operating system distribution, such as ubuntu or almalinux"),
+		),
+		mcp.WithString("version",
+			mcp.Description("The distribution version, such as 24.04; defaults to the distribution's latest"),
+		),
+		mcp.WithString("name",
+			mcp.Description("A name for the VM; one is generated if omitted"),
+		),
+		mcp.WithString("instance_type",
+			mcp.Description("The VM instance type; defaults to the distribution's default"),
+		),
+		mcp.WithNumber("disk_gib",
+			mcp.Description("The disk size in GiB; defaults to the distribution's default"),
+			mcp.Min(1),
+		),
+		mcp.WithString("ttl",
+			mcp.Description(fmt.Sprintf("How long the VM lives before it is deleted, such as 30m or 4h "+
+				"(%s to %s, default %s)", models.MinClusterTTL, models.MaxClusterTTL, defaultClusterTTL)),
+		),
+		dryRunParameter(),
+		outputSchema[models.VM](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("create_vm tool called", "arguments", request.GetArguments())
+
+		distribution, err := request.RequireString("distribution")
+		if err != nil {
+			return nil, err
+		}
+
+		spec := &models.VMSpec{
+			Name:         request.GetString("name", ""),
+			Distribution: distribution,
+			Version:      request.GetString("version", ""),
+			InstanceType: request.GetString("instance_type", ""),
+			DiskGiB:      request.GetInt("disk_gib", 0),
+			TTL:          request.GetString("ttl", defaultClusterTTL),
+		}
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+
+		list, err := s.vms.ListVMs(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+		if list.Quota != nil && list.Quota.Remaining() == 0 {
+			return nil, fmt.Errorf("VM quota reached: %d of %d VMs are active; delete a VM or wait for one to "+
+				"expire first", list.Quota.ActiveVMs, list.Quota.MaxVMs)
+		}
+		if s.dryRun(request) {
+			return dryRunResult(ctx, fmt.Sprintf("create a %s VM that expires after %s",
+				distributionLabel(spec.Distribution, spec.Version), spec.TTL), spec)
+		}
+
+		vm, err := s.vms.CreateVM(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResult(ctx, vm)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineDeleteVMTool creates the delete_vm tool definition.
+// Terminates a Compatibility Matrix VM once the caller confirms.
+func (s *Server) defineDeleteVMTool() toolDefinition {
+	tool := mcp.NewTool("delete_vm",
+		mcp.WithDescription("Terminate a Compatibility Matrix VM, destroying it and anything installed on it. "+
+			"This requires confirmation: a call without confirmation_token fails, describes the change, and "+
+			"returns a token; once the user agrees, make the same call with the token within two minutes. "+
+			"Deleting a terminated VM changes nothing. With dry_run, the deletion is described without making it."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("vm_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the VM"),
+		),
+		confirmationTokenParameter(),
+		dryRunParameter(),
+		outputSchema[deleteVMResult](),
+		alternateOutputSchema[dryRunPreview](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("delete_vm tool called", "arguments", request.GetArguments())
+
+		vmID, err := request.RequireString("vm_id")
+		if err != nil {
+			return nil, err
+		}
+
+		vm, err := s.findVM(ctx, vmID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := deleteVMResult{VMID: vm.ID, VMName: vm.Name, Status: vm.Status}
+		if vm.Status == models.ClusterStatusTerminated {
+			return jsonResult(ctx, result)
+		}
+
+		description := fmt.Sprintf("VM '%s' (%s)", vm.Name, vm.ID)
+		if s.dryRun(request) {
+			preview := result
+			preview.Status = models.ClusterStatusTerminated
+			preview.Changed = true
+			return dryRunResult(ctx, "delete "+description, preview)
+		}
+
+		if err := s.requireConfirmation(request, "deleting "+description); err != nil {
+			return nil, err
+		}
+
+		if err := s.vms.DeleteVM(ctx, vm.ID); err != nil {
+			return nil, err
+		}
+
+		result.Status = models.ClusterStatusTerminated
+		result.Changed = true
+		return jsonResult(ctx, result)
+	}
+
+	return toolDefinition{definition: &tool, handler: handler, mutating: true}
+}
+
+// defineGetVMSSHEndpointTool creates the get_vm_ssh_endpoint tool definition.
+// Returns the SSH address for connecting to a running VM.
+func (s *Server) defineGetVMSSHEndpointTool() toolDefinition {
+	tool := mcp.NewTool("get_vm_ssh_endpoint",
+		mcp.WithDescription("Get the SSH host, port, and user of a running Compatibility Matrix VM, with an "+
+			"ssh command for connecting. Connecting uses the SSH key registered with the Vendor Portal."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("vm_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the VM"),
+		),
+		outputSchema[vmSSHEndpoint](),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.logger.WithContext(ctx).Info("get_vm_ssh_endpoint tool called", "arguments", request.GetArguments())
+
+		vmID, err := request.RequireString("vm_id")
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint, err := s.vms.GetSSHEndpoint(ctx, vmID)
+		if err != nil {
+			return nil, err
+		}
+
+		destination := endpoint.Host
+		if endpoint.User != "" {
+			destination = endpoint.User + "@" + endpoint.Host
+		}
+		return jsonResult(ctx, vmSSHEndpoint{
+			VMID:        vmID,
+			SSHEndpoint: *endpoint,
+			Command:     fmt.Sprintf("ssh -p %d %s", endpoint.Port, destination),
+		})
+	}
+
+	return toolDefinition{definition: &tool, handler: handler}
+}
+
+// findVM returns one of the team's VMs, including terminated ones, by ID
+func (s *Server) findVM(ctx context.Context, vmID string) (*models.VM, error) {
+	list, err := s.vms.ListVMs(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.VMs {
+		if list.VMs[i].ID == vmID {
+			return &list.VMs[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("VM '%s' not found", vmID)
+}
+
+// summarizeVMs adds the minutes until each VM expires and the remaining quota to a VM list
+func summarizeVMs(list *api.VMList, now time.Time) vmListing {
+	listing := vmListing{VMs: make([]vmSummary, 0, len(list.VMs))}
+	for _, vm := range list.VMs {
+		summary := vmSummary{VM: vm}
+		if remaining := vm.Remaining(now); remaining != nil {
+			minutes := int(remaining.Minutes())
+			summary.ExpiresInMinutes = &minutes
+		}
+		listing.VMs = append(listing.VMs, summary)
+	}
+	if list.Quota != nil {
+		listing.Quota = &vmQuotaSummary{VMQuota: *list.Quota, RemainingVMs: list.Quota.Remaining()}
+	}
+	return listing
+}
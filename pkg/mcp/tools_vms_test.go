@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// testVMAPI is a fake Compatibility Matrix VM API that records the VMs created and deleted
+// through it. Its quota allows maxVMs active VMs.
+type testVMAPI struct {
+	*httptest.Server
+	mu      sync.Mutex
+	maxVMs  int
+	created []models.VMSpec
+	deleted []string
+}
+
+// newTestVMAPI serves a running VM and a terminated one, with a quota of five VMs
+func newTestVMAPI(t *testing.T) *testVMAPI {
+	t.Helper()
+
+	vendorAPI := &testVMAPI{maxVMs: 5}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vendor/v3/vms", func(w http.ResponseWriter, r *http.Request) {
+		vendorAPI.mu.Lock()
+		quota := fmt.Sprintf(`{"maxVms": %d, "activeVms": 1}`, vendorAPI.maxVMs)
+		vendorAPI.mu.Unlock()
+
+		terminated := ""
+		if r.URL.Query().Get("show-terminated") == "true" {
+			terminated = `, {"id": "vm-0", "name": "ec-0", "status": "terminated"}`
+		}
+		fmt.Fprintf(w, `{"vms": [{"id": "vm-1", "name": "ec-1", "status": "running"}%s], "quota": %s}`,
+			terminated, quota)
+	})
+	mux.HandleFunc("POST /vendor/v3/vm", func(w http.ResponseWriter, r *http.Request) {
+		var spec models.VMSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		vendorAPI.mu.Lock()
+		vendorAPI.created = append(vendorAPI.created, spec)
+		vendorAPI.mu.Unlock()
+
+		fmt.Fprintf(w, `{"vm": {"id": "vm-2", "distribution": %q, "status": "queued"}}`, spec.Distribution)
+	})
+	mux.HandleFunc("DELETE /vendor/v3/vm/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vendorAPI.mu.Lock()
+		vendorAPI.deleted = append(vendorAPI.deleted, r.PathValue("id"))
+		vendorAPI.mu.Unlock()
+
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("GET /vendor/v3/vm/vm-1/ssh-endpoint", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"host": "vm-1.cmx.example.com", "port": 32222, "user": "ubuntu"}`)
+	})
+
+	vendorAPI.Server = httptest.NewServer(mux)
+	t.Cleanup(vendorAPI.Close)
+	return vendorAPI
+}
+
+func TestListVMsTool(t *testing.T) {
+	server := newTestServer(t, newTestVMAPI(t).URL)
+
+	tool, ok := server.registry.Tool("list_vms")
+	if !ok {
+		t.Fatal("Tool 'list_vms' not found")
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantCount int
+	}{
+		{name: "active VMs", args: map[string]any{}, wantCount: 1},
+		{name: "including terminated VMs", args: map[string]any{"include_terminated": true}, wantCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("list_vms", tt.args))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var listing vmListing
+			if err := json.Unmarshal(resultData(t, result), &listing); err != nil {
+				t.Fatalf("Failed to decode result: %v", err)
+			}
+			if len(listing.VMs) != tt.wantCount {
+				t.Errorf("Expected %d VMs, got %+v", tt.wantCount, listing.VMs)
+			}
+			if listing.Quota == nil || listing.Quota.RemainingVMs != 4 {
+				t.Errorf("Expected 4 remaining VMs, got %+v", listing.Quota)
+			}
+		})
+	}
+}
+
+func TestSummarizeVMs(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(90*time.Minute + 30*time.Second)
+
+	listing := summarizeVMs(&api.VMList{VMs: []models.VM{
+		{ID: "vm-1", ExpiresAt: &expires},
+		{ID: "vm-2"},
+	}}, now)
+
+	if listing.VMs[0].ExpiresInMinutes == nil || *listing.VMs[0].ExpiresInMinutes != 90 {
+		t.Errorf("Expected vm-1 to expire in 90 minutes, got %+v", listing.VMs[0])
+	}
+	if listing.VMs[1].ExpiresInMinutes != nil || listing.Quota != nil {
+		t.Errorf("Expected no expiry or quota, got %+v", listing)
+	}
+}
+
+func TestCreateVMTool(t *testing.T) {
+	vendorAPI := newTestVMAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("create_vm")
+	if !ok {
+		t.Fatal("Tool 'create_vm' not found")
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		maxVMs      int
+		wantSpec    models.VMSpec
+		expectError bool
+	}{
+		{
+			name:     "default TTL",
+			args:     map[string]any{"distribution": "ubuntu"},
+			maxVMs:   5,
+			wantSpec: models.VMSpec{Distribution: "ubuntu", TTL: defaultClusterTTL},
+		},
+		{
+			name:     "full spec",
+			args:     map[string]any{"distribution": "ubuntu", "version": "24.04", "disk_gib": 50, "ttl": "2h"},
+			maxVMs:   5,
+			wantSpec: models.VMSpec{Distribution: "ubuntu", Version: "24.04", DiskGiB: 50, TTL: "2h"},
+		},
+		{name: "quota reached", args: map[string]any{"distribution": "ubuntu"}, maxVMs: 1, expectError: true},
+		{name: "invalid TTL", args: map[string]any{"distribution": "ubuntu", "ttl": "1w"}, maxVMs: 5, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.created = nil
+			vendorAPI.maxVMs = tt.maxVMs
+			vendorAPI.mu.Unlock()
+
+			result, err := tool.handler(context.Background(), createMockCallToolRequest("create_vm", tt.args))
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if len(vendorAPI.created) != 0 {
+					t.Errorf("Expected no VM to be created, got %+v", vendorAPI.created)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(vendorAPI.created, []models.VMSpec{tt.wantSpec}) {
+				t.Errorf("Expected VM %+v to be created, got %+v", tt.wantSpec, vendorAPI.created)
+			}
+			if !contains(resultText(t, result), `"status": "queued"`) {
+				t.Errorf("Expected the queued VM, got %s", resultText(t, result))
+			}
+		})
+	}
+}
+
+func TestDeleteVMTool(t *testing.T) {
+	vendorAPI := newTestVMAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tool, ok := server.registry.Tool("delete_vm")
+	if !ok {
+		t.Fatal("Tool 'delete_vm' not found")
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		confirm       bool
+		expectConfirm bool
+		expectError   bool
+		expectInText  string
+		wantDeleted   []string
+	}{
+		{
+			name:          "delete without confirmation",
+			args:          map[string]any{"vm_id": "vm-1"},
+			expectConfirm: true,
+		},
+		{
+			name:         "delete confirmed",
+			args:         map[string]any{"vm_id": "vm-1"},
+			confirm:      true,
+			expectInText: `"changed": true`,
+			wantDeleted:  []string{"vm-1"},
+		},
+		{
+			name:         "delete a terminated VM",
+			args:         map[string]any{"vm_id": "vm-0"},
+			expectInText: `"changed": false`,
+		},
+		{
+			name:        "unknown VM",
+			args:        map[string]any{"vm_id": "vm-9"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendorAPI.mu.Lock()
+			vendorAPI.deleted = nil
+			vendorAPI.mu.Unlock()
+
+			var result *mcp.CallToolResult
+			var err error
+			if tt.confirm {
+				result, err = callConfirmed(t, tool, tt.args)
+			} else {
+				result, err = tool.handler(context.Background(), createMockCallToolRequest("delete_vm", tt.args))
+			}
+			switch {
+			case tt.expectConfirm:
+				var confirmErr *confirmationError
+				if !errors.As(err, &confirmErr) {
+					t.Fatalf("Expected a confirmation error, got %v", err)
+				}
+			case tt.expectError:
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			case err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case !contains(resultText(t, result), tt.expectInText):
+				t.Errorf("Expected response containing '%s', got '%s'", tt.expectInText, resultText(t, result))
+			}
+
+			vendorAPI.mu.Lock()
+			defer vendorAPI.mu.Unlock()
+			if !slices.Equal(vendorAPI.deleted, tt.wantDeleted) {
+				t.Errorf("Expected VMs %v to be deleted, got %v", tt.wantDeleted, vendorAPI.deleted)
+			}
+		})
+	}
+}
+
+func TestGetVMSSHEndpointTool(t *testing.T) {
+	server := newTestServer(t, newTestVMAPI(t).URL)
+
+	tool, ok := server.registry.Tool("get_vm_ssh_endpoint")
+	if !ok {
+		t.Fatal("Tool 'get_vm_ssh_endpoint' not found")
+	}
+
+	result, err := tool.handler(context.Background(), createMockCallToolRequest("get_vm_ssh_endpoint",
+		map[string]any{"vm_id": "vm-1"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var endpoint vmSSHEndpoint
+	if err := json.Unmarshal(resultData(t, result), &endpoint); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if endpoint.VMID != "vm-1" || endpoint.Port != 32222 ||
+		endpoint.Command != "ssh -p 32222 ubuntu@vm-1.cmx.example.com" {
+		t.Errorf("Unexpected SSH endpoint: %+v", endpoint)
+	}
+
+	if _, err := tool.handler(context.Background(), createMockCallToolRequest("get_vm_ssh_endpoint",
+		map[string]any{"vm_id": "vm-9"})); err == nil {
+		t.Error("Expected error for an unknown VM")
+	}
+}
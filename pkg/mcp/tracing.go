@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/tracing"
+)
+
+// Span attributes describing tool calls
+const (
+	toolNameAttribute  = "mcp.tool.name"
+	requestIDAttribute = "mcp.request_id"
+	appIDAttribute     = "replicated.app_id"
+)
+
+// withTracing wraps a tool handler so that each call is traced as a span, the parent of the
+// spans of the API requests the call makes. Calls that return an error or an error result
+// mark the span as an error.
+func (s *Server) withTracing(toolName string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		attributes := []attribute.KeyValue{
+			attribute.String(toolNameAttribute, toolName),
+			attribute.String(requestIDAttribute, logging.RequestIDFromContext(ctx)),
+		}
+		if appID := request.GetString("app_id", ""); appID != "" {
+			attributes = append(attributes, attribute.String(appIDAttribute, appID))
+		}
+
+		ctx, span := tracing.Tracer().Start(ctx, "tools/call "+toolName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attributes...),
+		)
+		defer span.End()
+
+		result, err := next(ctx, request)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, "tool returned an error result")
+		}
+		return result, err
+	}
+}
+
+// traceContextFromRequest continues the trace of an HTTP transport request when the client
+// sends a W3C traceparent header, so tool call spans join the agent platform's trace
+func traceContextFromRequest(ctx context.Context, r *http.Request) context.Context {
+	return tracing.Propagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+}
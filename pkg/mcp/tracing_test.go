@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestToolCallTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+
+	vendorAPI := newTestVendorAPI(t)
+	server := newTestServer(t, vendorAPI.URL)
+
+	tests := []struct {
+		name      string
+		call      string
+		wantAppID string
+		wantError bool
+	}{
+		{
+			name: "successful call",
+			call: `{"jsonrpc":"2.0","id":1,"method":"tools/call",` +
+				`"params":{"name":"get_application","arguments":{"app_id":"` + testAppID + `"}}}`,
+			wantAppID: testAppID,
+		},
+		{
+			name:      "failed call",
+			call:      `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_application","arguments":{}}}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.Reset()
+			server.mcpServer.HandleMessage(context.Background(), json.RawMessage(tt.call))
+
+			var toolSpan sdktrace.ReadOnlySpan
+			for _, span := range recorder.Ended() {
+				if span.Name() == "tools/call get_application" {
+					toolSpan = span
+				}
+			}
+			if toolSpan == nil {
+				t.Fatalf("Expected a span for the tool call, got %d other spans", len(recorder.Ended()))
+			}
+
+			attributes := attribute.NewSet(toolSpan.Attributes()...)
+			if name, _ := attributes.Value(toolNameAttribute); name.AsString() != "get_application" {
+				t.Errorf("Expected tool name attribute, got '%s'", name.AsString())
+			}
+			if appID, _ := attributes.Value(appIDAttribute); appID.AsString() != tt.wantAppID {
+				t.Errorf("Expected app ID '%s', got '%s'", tt.wantAppID, appID.AsString())
+			}
+			if isError := toolSpan.Status().Code == codes.Error; isError != tt.wantError {
+				t.Errorf("Expected error status %v, got %v", tt.wantError, toolSpan.Status())
+			}
+
+			// API requests made by the call are traced as its children
+			for _, span := range recorder.Ended() {
+				if span != toolSpan && span.Parent().SpanID() != toolSpan.SpanContext().SpanID() {
+					t.Errorf("Expected span '%s' to be a child of the tool call", span.Name())
+				}
+			}
+			if !tt.wantError && len(recorder.Ended()) < 2 {
+				t.Error("Expected the call's API requests to be traced")
+			}
+		})
+	}
+}
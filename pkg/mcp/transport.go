@@ -0,0 +1,250 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/crdant/replicated-mcp-server/pkg/accesslog"
+	"github.com/crdant/replicated-mcp-server/pkg/certreload"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/cors"
+)
+
+// Transport settings
+const (
+	httpEndpointPath      = "/mcp"
+	metricsEndpointPath   = "/metrics"
+	httpReadHeaderTimeout = 10 * time.Second
+	httpShutdownTimeout   = 10 * time.Second
+	toolCallDrainTimeout  = 10 * time.Second
+	accessLogFileMode     = 0o600
+
+	// autocertCacheDir holds certificates obtained from Let's Encrypt, relative to the data directory
+	autocertCacheDir = "autocert"
+)
+
+// serveStdio serves the MCP protocol on stdin and stdout until the connection closes or ctx
+// is canceled. Once ctx is canceled no more requests are read, and tool calls in flight are
+// given the drain timeout to finish and send their results before they are canceled.
+func (s *Server) serveStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	s.logger.Info("Starting MCP server on stdio transport")
+
+	// Tool calls run under a context of their own so they outlive ctx while draining
+	callCtx, cancelCalls := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelCalls()
+
+	input, stopReading := readUntilDone(ctx, stdin)
+	defer stopReading()
+	input, stdout = s.withStdioCompletions(callCtx, input, stdout)
+
+	// Serve on stdio - this blocks until stdin closes or ctx is canceled
+	if err := server.NewStdioServer(s.mcpServer).Listen(callCtx, input, stdout); err != nil {
+		s.logger.Error("MCP server error", "error", err)
+		return fmt.Errorf("stdio server error: %w", err)
+	}
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancelDrain()
+	if err := s.drainToolCalls(drainCtx); err != nil {
+		s.logger.Warn("Canceling tool calls still running at shutdown", "error", err)
+	}
+	return nil
+}
+
+// readUntilDone returns a reader that ends with io.EOF when r does or once ctx is canceled,
+// even while a read from r is blocked, and a function that stops reading from r. A read
+// blocked on r when reading stops is abandoned rather than interrupted.
+func readUntilDone(ctx context.Context, r io.Reader) (io.Reader, func()) {
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := io.Copy(writer, r)
+		writer.CloseWithError(err)
+	}()
+
+	stop := context.AfterFunc(ctx, func() { writer.Close() })
+	return reader, func() {
+		stop()
+		reader.Close()
+	}
+}
+
+// serveHTTP serves the MCP protocol over streamable HTTP on the configured listen address
+// until ctx is canceled
+func (s *Server) serveHTTP(ctx context.Context) error {
+	cfg := s.currentConfig()
+
+	var listenConfig net.ListenConfig
+	listener, err := listenConfig.Listen(ctx, "tcp", cfg.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.ListenAddress, err)
+	}
+
+	return s.serveHTTPListener(ctx, listener, cfg)
+}
+
+// serveHTTPListener serves the streamable HTTP transport on listener, over TLS when it is
+// configured, writing an access log entry for each request, until ctx is canceled. Browser
+// requests are only served for the allowed origins, requests without the auth token are
+// refused when one is configured, and request bodies over the configured size are refused;
+// rejected requests are still logged. Metrics are served for Prometheus
+// alongside the MCP endpoint when the metrics endpoint is turned on.
+func (s *Server) serveHTTPListener(ctx context.Context, listener net.Listener, cfg *config.Config) error {
+	tlsConfig, err := s.httpTLSConfig(cfg)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	accessLog, closeAccessLog, err := openAccessLog(cfg)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	defer closeAccessLog()
+
+	mux := http.NewServeMux()
+	mux.Handle(httpEndpointPath, s.withHTTPCompletions(server.NewStreamableHTTPServer(s.mcpServer,
+		server.WithHTTPContextFunc(traceContextFromRequest))))
+	if cfg.MetricsEndpoint {
+		mux.Handle(metricsEndpointPath, s.Metrics().Handler())
+	}
+	handler := cors.New(cfg.AllowedOrigins).Handler(
+		requireBearerToken(limitRequestBody(mux, cfg.MaxRequestBytes), cfg.HTTPAuthToken))
+
+	httpServer := &http.Server{
+		Handler:           accesslog.New(accessLog, cfg.AccessLogSampleRate).Handler(handler),
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+		TLSConfig:         tlsConfig,
+	}
+
+	// Let in-flight requests finish when the server is asked to stop
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopped:
+			return
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error("Failed to shut down HTTP transport", "error", err)
+		}
+	}()
+
+	s.logger.Info("Starting MCP server on HTTP transport",
+		"listen", listener.Addr().String(),
+		"path", httpEndpointPath,
+		"metrics", cfg.MetricsEndpoint,
+		"tls", tlsConfig != nil,
+		"auth", cfg.HTTPAuthToken != "")
+
+	serve := httpServer.Serve
+	if tlsConfig != nil {
+		// The certificate comes from the TLS configuration rather than files named here
+		serve = func(listener net.Listener) error { return httpServer.ServeTLS(listener, "", "") }
+	}
+
+	if err := serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Error("MCP server error", "error", err)
+		return fmt.Errorf("HTTP server error: %w", err)
+	}
+
+	return nil
+}
+
+// requireBearerToken refuses requests that do not send token as a bearer token in the
+// Authorization header with 401 Unauthorized, before they reach the MCP server or the metrics
+// endpoint. An empty token lets every request through.
+func requireBearerToken(next http.Handler, token string) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="replicated-mcp-server"`)
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitRequestBody refuses request bodies larger than limit bytes with 413 Request Entity Too
+// Large. Bodies that declare their length are refused before they are read; others stop being
+// read at the limit. A limit of zero leaves bodies unlimited.
+func limitRequestBody(next http.Handler, limit int) http.Handler {
+	if limit <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > int64(limit) {
+			http.Error(w, fmt.Sprintf("request body is %d bytes, more than the limit of %d bytes",
+				r.ContentLength, limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, int64(limit))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// httpTLSConfig returns the HTTP transport's TLS configuration, which is nil when it serves
+// plain HTTP. Certificates come either from files that are reloaded when they change or from
+// Let's Encrypt, cached in the data directory.
+func (s *Server) httpTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	var tlsConfig *tls.Config
+
+	switch {
+	case len(cfg.TLSAutocertDomains) > 0:
+		// Challenges are answered over TLS-ALPN, so the listen address must be reachable on
+		// port 443 from the internet
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(filepath.Join(cfg.DataDirectory(), autocertCacheDir)),
+		}
+		tlsConfig = manager.TLSConfig()
+	case cfg.TLSCertFile != "":
+		reloader, err := certreload.New(cfg.TLSCertFile, cfg.TLSKeyFile, s.logger)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = &tls.Config{GetCertificate: reloader.GetCertificate}
+	default:
+		return nil, nil
+	}
+
+	tlsConfig.MinVersion = tls.VersionTLS12
+	return tlsConfig, nil
+}
+
+// openAccessLog opens the access log destination, which is stderr unless a file is
+// configured, returning a function that closes it
+func openAccessLog(cfg *config.Config) (io.Writer, func(), error) {
+	if cfg.AccessLogFile == "" {
+		return os.Stderr, func() {}, nil
+	}
+
+	file, err := os.OpenFile(filepath.Clean(cfg.AccessLogFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND,
+		accessLogFileMode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open access log: %w", err)
+	}
+	return file, func() { file.Close() }, nil
+}
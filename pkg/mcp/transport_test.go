@@ -0,0 +1,540 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/config"
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+	"github.com/crdant/replicated-mcp-server/pkg/metrics"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// initializeBody is an MCP initialize request
+const initializeBody = `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26",
+	"capabilities": {}, "clientInfo": {"name": "test-client", "version": "1.0.0"}}}`
+
+// initializeSession sends an MCP initialize request to url and returns the session it starts
+func initializeSession(ctx context.Context, t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(initializeBody))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("Failed to call the HTTP transport: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.StatusCode)
+	}
+	session := response.Header.Get("Mcp-Session-Id")
+	if session == "" {
+		t.Fatal("Expected the initialize response to start a session")
+	}
+	return session
+}
+
+// waitForShutdown waits for a canceled HTTP transport to stop
+func waitForShutdown(t *testing.T, served <-chan error) {
+	t.Helper()
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("Expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the transport to stop when its context was canceled")
+	}
+}
+
+func TestServeHTTPAccessLog(t *testing.T) {
+	server := newTestServer(t, "")
+
+	accessLogFile := filepath.Join(t.TempDir(), "access.log")
+	cfg := &config.Config{Transport: config.TransportHTTP, AccessLogFile: accessLogFile, AccessLogSampleRate: 1}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.serveHTTPListener(ctx, listener, cfg)
+	}()
+
+	session := initializeSession(ctx, t, http.DefaultClient, "http://"+listener.Addr().String()+"/mcp")
+
+	cancel()
+	waitForShutdown(t, served)
+
+	data, err := os.ReadFile(accessLogFile)
+	if err != nil {
+		t.Fatalf("Failed to read access log: %v", err)
+	}
+	var entry struct {
+		Log     string `json:"log"`
+		Method  string `json:"method"`
+		Path    string `json:"path"`
+		Session string `json:"session"`
+		Status  int    `json:"status"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("Expected one JSON access log entry, got %q: %v", data, err)
+	}
+	if entry.Log != "access" || entry.Method != http.MethodPost || entry.Path != "/mcp" ||
+		entry.Status != http.StatusOK || entry.Session != session {
+		t.Errorf("Unexpected access log entry %+v (session %q)", entry, session)
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1 and its key, returning
+// a pool that trusts it
+func writeTestCertificate(t *testing.T, certFile, keyFile string) *x509.CertPool {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certPEM)
+	return pool
+}
+
+func TestServeHTTPTLS(t *testing.T) {
+	server := newTestServer(t, "")
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	pool := writeTestCertificate(t, certFile, keyFile)
+
+	cfg := &config.Config{
+		Transport: config.TransportHTTP, AccessLogFile: filepath.Join(dir, "access.log"),
+		TLSCertFile: certFile, TLSKeyFile: keyFile,
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.serveHTTPListener(ctx, listener, cfg)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	initializeSession(ctx, t, client, "https://"+listener.Addr().String()+"/mcp")
+
+	cancel()
+	waitForShutdown(t, served)
+}
+
+func TestServeHTTPOrigins(t *testing.T) {
+	server := newTestServer(t, "")
+
+	cfg := &config.Config{
+		Transport: config.TransportHTTP, AccessLogFile: filepath.Join(t.TempDir(), "access.log"),
+		AllowedOrigins: []string{"https://agent.example.com"},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.serveHTTPListener(ctx, listener, cfg)
+	}()
+	url := "http://" + listener.Addr().String() + "/mcp"
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+	}{
+		{name: "allowed origin", origin: "https://agent.example.com", wantStatus: http.StatusOK},
+		{name: "other origin", origin: "https://evil.example.com", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(initializeBody))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Origin", tt.origin)
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("Failed to call the HTTP transport: %v", err)
+			}
+			response.Body.Close()
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, response.StatusCode)
+			}
+		})
+	}
+
+	cancel()
+	waitForShutdown(t, served)
+}
+
+func TestServeHTTPAuth(t *testing.T) {
+	server := newTestServer(t, "")
+
+	cfg := &config.Config{
+		Transport: config.TransportHTTP, AccessLogFile: filepath.Join(t.TempDir(), "access.log"),
+		HTTPAuthToken: "client-token", MetricsEndpoint: true,
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.serveHTTPListener(ctx, listener, cfg)
+	}()
+	base := "http://" + listener.Addr().String()
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "no token", path: "/mcp", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", path: "/mcp", authorization: "Bearer other-token", wantStatus: http.StatusUnauthorized},
+		{name: "token without the scheme", path: "/mcp", authorization: "client-token",
+			wantStatus: http.StatusUnauthorized},
+		{name: "metrics without a token", path: "/metrics", wantStatus: http.StatusUnauthorized},
+		{name: "token", path: "/mcp", authorization: "Bearer client-token", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequestWithContext(ctx, http.MethodPost, base+tt.path,
+				strings.NewReader(initializeBody))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			request.Header.Set("Content-Type", "application/json")
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("Failed to call the HTTP transport: %v", err)
+			}
+			response.Body.Close()
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, response.StatusCode)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && response.Header.Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate challenge")
+			}
+		})
+	}
+
+	cancel()
+	waitForShutdown(t, served)
+}
+
+func TestServeHTTPMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		wantStatus int
+	}{
+		{name: "metrics endpoint on", enabled: true, wantStatus: http.StatusOK},
+		{name: "metrics endpoint off", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, "")
+			cfg := &config.Config{
+				Transport: config.TransportHTTP, AccessLogFile: filepath.Join(t.TempDir(), "access.log"),
+				MetricsEndpoint: tt.enabled,
+			}
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Failed to listen: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() {
+				served <- server.serveHTTPListener(ctx, listener, cfg)
+			}()
+			defer func() {
+				cancel()
+				waitForShutdown(t, served)
+			}()
+
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet,
+				"http://"+listener.Addr().String()+"/metrics", nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("Failed to call the HTTP transport: %v", err)
+			}
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatalf("Failed to read response: %v", err)
+			}
+
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, response.StatusCode)
+			}
+			if tt.enabled && !strings.Contains(string(body), metrics.ToolCallsTotal) {
+				t.Errorf("Expected the metrics, got %q", body)
+			}
+		})
+	}
+}
+
+func TestServeHTTPRequestLimit(t *testing.T) {
+	server := newTestServer(t, "")
+
+	cfg := &config.Config{
+		Transport: config.TransportHTTP, AccessLogFile: filepath.Join(t.TempDir(), "access.log"),
+		MaxRequestBytes: 1024,
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.serveHTTPListener(ctx, listener, cfg)
+	}()
+	url := "http://" + listener.Addr().String() + "/mcp"
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "body within the limit", body: initializeBody, wantStatus: http.StatusOK},
+		{
+			name:       "body over the limit",
+			body:       strings.Replace(initializeBody, `"id": 1`, `"id": "`+strings.Repeat("x", 2048)+`"`, 1),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			request.Header.Set("Content-Type", "application/json")
+
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("Failed to call the HTTP transport: %v", err)
+			}
+			response.Body.Close()
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, response.StatusCode)
+			}
+		})
+	}
+
+	cancel()
+	waitForShutdown(t, served)
+}
+
+func TestServeHTTPTLSInvalidCertificate(t *testing.T) {
+	server := newTestServer(t, "")
+
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Transport: config.TransportHTTP, AccessLogFile: filepath.Join(dir, "access.log"),
+		TLSCertFile: filepath.Join(dir, "missing.crt"), TLSKeyFile: filepath.Join(dir, "missing.key"),
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	if err := server.serveHTTPListener(context.Background(), listener, cfg); err == nil {
+		t.Fatal("Expected an error for missing TLS files")
+	}
+	if _, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+		t.Error("Expected the listener to be closed after the TLS setup failed")
+	}
+}
+
+// blockingApplications holds ListApplications calls until they are released or canceled
+type blockingApplications struct {
+	applicationsService
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func (b *blockingApplications) ListApplications(
+	ctx context.Context, _ *api.ListApplicationsOptions,
+) (*api.ApplicationList, error) {
+	close(b.started)
+	select {
+	case <-b.release:
+		return &api.ApplicationList{Applications: []models.Application{{ID: "app-fake", Slug: "fake"}}}, nil
+	case <-ctx.Done():
+		close(b.canceled)
+		return nil, ctx.Err()
+	}
+}
+
+func TestServeStdioShutdown(t *testing.T) {
+	tests := []struct {
+		name         string
+		finishes     bool
+		wantCanceled bool
+	}{
+		{
+			name:     "call finishing while draining",
+			finishes: true,
+		},
+		{
+			name:         "call outlasting the drain timeout",
+			wantCanceled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applications := &blockingApplications{
+				started:  make(chan struct{}),
+				release:  make(chan struct{}),
+				canceled: make(chan struct{}),
+			}
+			cfg := &config.Config{LogLevel: "info", Timeout: 30 * time.Second, DataDir: t.TempDir()}
+			server, err := NewServerWithServices(cfg, logging.NewLogger("info"),
+				&vendorapi.Services{Client: fakeClient{}, Applications: applications})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+			server.drainTimeout = 100 * time.Millisecond
+
+			// stdin is left open, as a client that is still connected would
+			stdin, input := io.Pipe()
+			defer input.Close()
+			output, stdout := io.Pipe()
+			responses := make(chan string, 1)
+			go func() {
+				scanner := bufio.NewScanner(output)
+				for scanner.Scan() {
+					responses <- scanner.Text()
+				}
+			}()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() { served <- server.serveStdio(ctx, stdin, stdout) }()
+
+			call := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"list_applications","arguments":{}}}`
+			if _, err := io.WriteString(input, call+"\n"); err != nil {
+				t.Fatalf("Failed to send the call: %v", err)
+			}
+			<-applications.started
+
+			cancel()
+			if tt.finishes {
+				close(applications.release)
+				select {
+				case response := <-responses:
+					if !strings.Contains(response, `"id":7`) || !strings.Contains(response, "app-fake") {
+						t.Errorf("Expected the call's result, got %s", response)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("Expected the in-flight call to send its result")
+				}
+			}
+			waitForShutdown(t, served)
+
+			select {
+			case <-applications.canceled:
+				if !tt.wantCanceled {
+					t.Error("Expected the call to finish without being canceled")
+				}
+			case <-time.After(time.Second):
+				if tt.wantCanceled {
+					t.Error("Expected the call to be canceled after the drain timeout")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Package metrics tracks usage and performance of the Replicated MCP Server: tool call
+// counts and latencies, Vendor Portal API request durations, error rates, and rate-limit
+// hits, and cache hit rates. Metrics are kept in memory and written in the Prometheus text
+// exposition format, either served over HTTP or dumped on demand, or summarized as JSON.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metric names
+const (
+	ToolCallsTotal        = "replicated_mcp_tool_calls_total"
+	ToolCallDuration      = "replicated_mcp_tool_call_duration_seconds"
+	APIRequestsTotal      = "replicated_mcp_api_requests_total"
+	APIRequestDuration    = "replicated_mcp_api_request_duration_seconds"
+	APIRateLimitHitsTotal = "replicated_mcp_api_rate_limit_hits_total"
+	CacheLookupsTotal     = "replicated_mcp_cache_lookups_total"
+)
+
+// Outcome label values
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Cache lookup result label values
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// contentType is the Prometheus text exposition format content type
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the latency histogram bucket upper bounds, in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// toolKey identifies a tool call counter
+type toolKey struct {
+	tool    string
+	outcome string
+}
+
+// requestKey identifies an API request counter
+type requestKey struct {
+	method string
+	status string
+}
+
+// cacheKey identifies a cache lookup counter
+type cacheKey struct {
+	cache  string
+	result string
+}
+
+// histogram accumulates observations into cumulative buckets
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// observe records a single observation
+func (h *histogram) observe(seconds float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(DefaultBuckets))
+	}
+	for i, bound := range DefaultBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// Registry holds the server's metrics. It is safe for concurrent use.
+type Registry struct {
+	mu            sync.Mutex
+	toolCalls     map[toolKey]uint64
+	toolLatency   map[string]*histogram
+	apiRequests   map[requestKey]uint64
+	apiLatency    map[string]*histogram
+	rateLimitHits uint64
+	cacheLookups  map[cacheKey]uint64
+}
+
+// NewRegistry creates an empty metrics registry
+func NewRegistry() *Registry {
+	return &Registry{
+		toolCalls:    make(map[toolKey]uint64),
+		toolLatency:  make(map[string]*histogram),
+		apiRequests:  make(map[requestKey]uint64),
+		apiLatency:   make(map[string]*histogram),
+		cacheLookups: make(map[cacheKey]uint64),
+	}
+}
+
+// ObserveToolCall records a completed tool call and its latency
+func (r *Registry) ObserveToolCall(tool string, duration time.Duration, failed bool) {
+	outcome := OutcomeSuccess
+	if failed {
+		outcome = OutcomeError
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.toolCalls[toolKey{tool: tool, outcome: outcome}]++
+	histogramFor(r.toolLatency, tool).observe(duration.Seconds())
+}
+
+// ObserveAPIRequest records a completed Vendor Portal API request. Requests that failed
+// before a response was received are counted with the status "error", and 429
+// responses are also counted as rate-limit hits.
+func (r *Registry) ObserveAPIRequest(method string, status int, duration time.Duration, err error) {
+	statusLabel := strconv.Itoa(status)
+	if err != nil {
+		statusLabel = OutcomeError
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.apiRequests[requestKey{method: method, status: statusLabel}]++
+	histogramFor(r.apiLatency, method).observe(duration.Seconds())
+	if status == http.StatusTooManyRequests {
+		r.rateLimitHits++
+	}
+}
+
+// ObserveCacheLookup records a lookup in a named cache and whether it was a hit
+func (r *Registry) ObserveCacheLookup(cache string, hit bool) {
+	result := CacheMiss
+	if hit {
+		result = CacheHit
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cacheLookups[cacheKey{cache: cache, result: result}]++
+}
+
+// histogramFor returns the histogram for a label value, creating it if needed
+func histogramFor(histograms map[string]*histogram, label string) *histogram {
+	h, ok := histograms[label]
+	if !ok {
+		h = &histogram{}
+		histograms[label] = h
+	}
+	return h
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := bufio.NewWriter(w)
+
+	writeHeader(out, ToolCallsTotal, "counter", "Tool calls by tool and outcome.")
+	for _, key := range sortedKeys(r.toolCalls, func(a, b toolKey) bool {
+		return a.tool < b.tool || a.tool == b.tool && a.outcome < b.outcome
+	}) {
+		fmt.Fprintf(out, "%s{tool=%q,outcome=%q} %d\n", ToolCallsTotal, key.tool, key.outcome, r.toolCalls[key])
+	}
+
+	writeHeader(out, ToolCallDuration, "histogram", "Tool call latency in seconds.")
+	writeHistograms(out, ToolCallDuration, "tool", r.toolLatency)
+
+	writeHeader(out, APIRequestsTotal, "counter", "Vendor Portal API requests by method and status.")
+	for _, key := range sortedKeys(r.apiRequests, func(a, b requestKey) bool {
+		return a.method < b.method || a.method == b.method && a.status < b.status
+	}) {
+		fmt.Fprintf(out, "%s{method=%q,status=%q} %d\n", APIRequestsTotal, key.method, key.status, r.apiRequests[key])
+	}
+
+	writeHeader(out, APIRequestDuration, "histogram", "Vendor Portal API request latency in seconds.")
+	writeHistograms(out, APIRequestDuration, "method", r.apiLatency)
+
+	writeHeader(out, APIRateLimitHitsTotal, "counter", "Vendor Portal API responses with status 429.")
+	fmt.Fprintf(out, "%s %d\n", APIRateLimitHitsTotal, r.rateLimitHits)
+
+	writeHeader(out, CacheLookupsTotal, "counter", "Cache lookups by cache and result.")
+	for _, key := range sortedKeys(r.cacheLookups, func(a, b cacheKey) bool {
+		return a.cache < b.cache || a.cache == b.cache && a.result < b.result
+	}) {
+		fmt.Fprintf(out, "%s{cache=%q,result=%q} %d\n", CacheLookupsTotal, key.cache, key.result, r.cacheLookups[key])
+	}
+
+	return out.Flush()
+}
+
+// Handler returns an HTTP handler serving the metrics for Prometheus to scrape
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_ = r.WriteText(w)
+	})
+}
+
+// writeHeader writes the HELP and TYPE lines for a metric
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeHistograms writes the bucket, sum, and count series for each labeled histogram
+func writeHistograms(w io.Writer, name, label string, histograms map[string]*histogram) {
+	for _, value := range sortedKeys(histograms, func(a, b string) bool { return a < b }) {
+		h := histograms[value]
+		for i, bound := range DefaultBuckets {
+			fmt.Fprintf(w, "%s_bucket{%s=%q,le=%q} %d\n",
+				name, label, value, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n", name, label, value, h.count)
+		fmt.Fprintf(w, "%s_sum{%s=%q} %s\n", name, label, value, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count{%s=%q} %d\n", name, label, value, h.count)
+	}
+}
+
+// sortedKeys returns a map's keys in a stable order for deterministic output
+func sortedKeys[K comparable, V any](m map[K]V, less func(a, b K) bool) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	return keys
+}
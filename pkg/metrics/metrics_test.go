@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+
+	r.ObserveToolCall("list_applications", 20*time.Millisecond, false)
+	r.ObserveToolCall("list_applications", 300*time.Millisecond, true)
+	r.ObserveToolCall("get_customer", time.Second, false)
+	r.ObserveAPIRequest(http.MethodGet, http.StatusOK, 10*time.Millisecond, nil)
+	r.ObserveAPIRequest(http.MethodGet, http.StatusTooManyRequests, 5*time.Millisecond, nil)
+	r.ObserveAPIRequest(http.MethodPut, 0, time.Second, errors.New("connection refused"))
+	r.ObserveCacheLookup("application_slugs", true)
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"# TYPE replicated_mcp_tool_calls_total counter",
+		`replicated_mcp_tool_calls_total{tool="list_applications",outcome="success"} 1`,
+		`replicated_mcp_tool_calls_total{tool="list_applications",outcome="error"} 1`,
+		`replicated_mcp_tool_calls_total{tool="get_customer",outcome="success"} 1`,
+		"# TYPE replicated_mcp_tool_call_duration_seconds histogram",
+		`replicated_mcp_tool_call_duration_seconds_bucket{tool="list_applications",le="0.025"} 1`,
+		`replicated_mcp_tool_call_duration_seconds_bucket{tool="list_applications",le="0.5"} 2`,
+		`replicated_mcp_tool_call_duration_seconds_bucket{tool="list_applications",le="+Inf"} 2`,
+		`replicated_mcp_tool_call_duration_seconds_count{tool="list_applications"} 2`,
+		`replicated_mcp_api_requests_total{method="GET",status="200"} 1`,
+		`replicated_mcp_api_requests_total{method="GET",status="429"} 1`,
+		`replicated_mcp_api_requests_total{method="PUT",status="error"} 1`,
+		`replicated_mcp_api_request_duration_seconds_count{method="GET"} 2`,
+		"replicated_mcp_api_rate_limit_hits_total 1",
+		`replicated_mcp_cache_lookups_total{cache="application_slugs",result="hit"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+
+	// Output is deterministic so dumps can be compared
+	var again bytes.Buffer
+	_ = r.WriteText(&again)
+	if again.String() != output {
+		t.Error("Expected repeated dumps of unchanged metrics to be identical")
+	}
+}
+
+func TestRegistry_EmptyWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewRegistry().WriteText(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "replicated_mcp_api_rate_limit_hits_total 0\n") {
+		t.Errorf("Expected zero rate-limit counter, got:\n%s", buf.String())
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveToolCall("get_job", time.Millisecond, false)
+
+	recorder := httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Expected Prometheus content type, got %s", got)
+	}
+	if !strings.Contains(recorder.Body.String(), `tool="get_job"`) {
+		t.Errorf("Expected tool metrics in response, got:\n%s", recorder.Body.String())
+	}
+}
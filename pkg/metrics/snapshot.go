@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Latency quantiles reported in snapshots
+const (
+	quantileP50 = 0.5
+	quantileP95 = 0.95
+	quantileP99 = 0.99
+)
+
+// Snapshot summarizes the registry's metrics as JSON-friendly values, for clients that
+// cannot scrape the Prometheus endpoint. Latency percentiles are estimated from the
+// histogram buckets, so they are only as precise as the bucket bounds.
+type Snapshot struct {
+	Tools  []ToolStats  `json:"tools"`
+	API    APIStats     `json:"api"`
+	Caches []CacheStats `json:"caches"`
+}
+
+// ToolStats summarizes calls to a single tool
+type ToolStats struct {
+	Tool      string    `json:"tool"`
+	Calls     uint64    `json:"calls"`
+	Errors    uint64    `json:"errors"`
+	ErrorRate float64   `json:"error_rate"`
+	Latency   Latencies `json:"latency_seconds"`
+}
+
+// APIStats summarizes Vendor Portal API requests. Errors counts requests that failed
+// without a response or received a 4xx or 5xx status.
+type APIStats struct {
+	Requests      uint64            `json:"requests"`
+	Errors        uint64            `json:"errors"`
+	ErrorRate     float64           `json:"error_rate"`
+	RateLimitHits uint64            `json:"rate_limit_hits"`
+	ByStatus      map[string]uint64 `json:"by_status"`
+	Methods       []MethodStats     `json:"methods"`
+}
+
+// MethodStats summarizes API requests with a single HTTP method
+type MethodStats struct {
+	Method   string    `json:"method"`
+	Requests uint64    `json:"requests"`
+	Latency  Latencies `json:"latency_seconds"`
+}
+
+// CacheStats summarizes lookups in a single cache
+type CacheStats struct {
+	Cache   string  `json:"cache"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Latencies holds estimated latency percentiles, in seconds
+type Latencies struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// Snapshot returns a summary of the current metrics
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := Snapshot{
+		Tools:  []ToolStats{},
+		Caches: []CacheStats{},
+		API: APIStats{
+			RateLimitHits: r.rateLimitHits,
+			ByStatus:      make(map[string]uint64),
+			Methods:       []MethodStats{},
+		},
+	}
+
+	for _, tool := range sortedKeys(r.toolLatency, func(a, b string) bool { return a < b }) {
+		stats := ToolStats{
+			Tool:    tool,
+			Calls:   r.toolLatency[tool].count,
+			Errors:  r.toolCalls[toolKey{tool: tool, outcome: OutcomeError}],
+			Latency: r.toolLatency[tool].latencies(),
+		}
+		stats.ErrorRate = ratio(stats.Errors, stats.Calls)
+		snapshot.Tools = append(snapshot.Tools, stats)
+	}
+
+	for key, count := range r.apiRequests {
+		snapshot.API.Requests += count
+		snapshot.API.ByStatus[key.status] += count
+		if status, err := strconv.Atoi(key.status); err != nil || status >= http.StatusBadRequest {
+			snapshot.API.Errors += count
+		}
+	}
+	snapshot.API.ErrorRate = ratio(snapshot.API.Errors, snapshot.API.Requests)
+
+	for _, method := range sortedKeys(r.apiLatency, func(a, b string) bool { return a < b }) {
+		snapshot.API.Methods = append(snapshot.API.Methods, MethodStats{
+			Method:   method,
+			Requests: r.apiLatency[method].count,
+			Latency:  r.apiLatency[method].latencies(),
+		})
+	}
+
+	caches := make(map[string]bool)
+	for key := range r.cacheLookups {
+		caches[key.cache] = true
+	}
+	for _, cache := range sortedKeys(caches, func(a, b string) bool { return a < b }) {
+		stats := CacheStats{
+			Cache:  cache,
+			Hits:   r.cacheLookups[cacheKey{cache: cache, result: CacheHit}],
+			Misses: r.cacheLookups[cacheKey{cache: cache, result: CacheMiss}],
+		}
+		stats.HitRate = ratio(stats.Hits, stats.Hits+stats.Misses)
+		snapshot.Caches = append(snapshot.Caches, stats)
+	}
+
+	return snapshot
+}
+
+// latencies estimates the reported percentiles from the histogram
+func (h *histogram) latencies() Latencies {
+	return Latencies{
+		P50: h.quantile(quantileP50),
+		P95: h.quantile(quantileP95),
+		P99: h.quantile(quantileP99),
+	}
+}
+
+// quantile estimates a quantile by linear interpolation within the bucket containing it,
+// as Prometheus's histogram_quantile does. Observations above the largest bucket bound
+// are reported at that bound.
+func (h *histogram) quantile(q float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := q * float64(h.count)
+	lowerBound, lowerCount := 0.0, uint64(0)
+	for i, bound := range DefaultBuckets {
+		if float64(h.counts[i]) >= rank {
+			inBucket := h.counts[i] - lowerCount
+			if inBucket == 0 {
+				return bound
+			}
+			return lowerBound + (bound-lowerBound)*(rank-float64(lowerCount))/float64(inBucket)
+		}
+		lowerBound, lowerCount = bound, h.counts[i]
+	}
+	return DefaultBuckets[len(DefaultBuckets)-1]
+}
+
+// ratio returns part/total, or 0 when total is 0
+func ratio(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
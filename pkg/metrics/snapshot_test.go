@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	tests := []struct {
+		name         string
+		observations []time.Duration
+		q            float64
+		want         float64
+	}{
+		{name: "empty", q: 0.5, want: 0},
+		{
+			name:         "interpolated within a bucket",
+			observations: []time.Duration{150 * time.Millisecond, 150 * time.Millisecond},
+			q:            0.5,
+			want:         0.175,
+		},
+		{
+			name:         "upper percentile in a slower bucket",
+			observations: []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 2 * time.Second},
+			q:            0.95,
+			want:         2.2,
+		},
+		{
+			name:         "above the largest bucket",
+			observations: []time.Duration{time.Minute},
+			q:            0.99,
+			want:         10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &histogram{}
+			for _, observation := range tt.observations {
+				h.observe(observation.Seconds())
+			}
+			if got := h.quantile(tt.q); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("quantile(%v) = %v, want %v", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Snapshot(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveToolCall("list_customers", 20*time.Millisecond, false)
+	r.ObserveToolCall("list_customers", 40*time.Millisecond, true)
+	r.ObserveAPIRequest(http.MethodGet, http.StatusOK, 10*time.Millisecond, nil)
+	r.ObserveAPIRequest(http.MethodGet, http.StatusNotFound, 10*time.Millisecond, nil)
+	r.ObserveAPIRequest(http.MethodGet, http.StatusTooManyRequests, 10*time.Millisecond, nil)
+	r.ObserveAPIRequest(http.MethodPut, 0, time.Second, errors.New("connection refused"))
+	r.ObserveCacheLookup("application_slugs", true)
+	r.ObserveCacheLookup("application_slugs", true)
+	r.ObserveCacheLookup("application_slugs", true)
+	r.ObserveCacheLookup("application_slugs", false)
+
+	snapshot := r.Snapshot()
+
+	if len(snapshot.Tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(snapshot.Tools))
+	}
+	tool := snapshot.Tools[0]
+	if tool.Tool != "list_customers" || tool.Calls != 2 || tool.Errors != 1 || tool.ErrorRate != 0.5 {
+		t.Errorf("Unexpected tool stats: %+v", tool)
+	}
+	if tool.Latency.P50 <= 0 || tool.Latency.P99 < tool.Latency.P50 {
+		t.Errorf("Unexpected tool latencies: %+v", tool.Latency)
+	}
+
+	api := snapshot.API
+	if api.Requests != 4 || api.Errors != 3 || api.ErrorRate != 0.75 || api.RateLimitHits != 1 {
+		t.Errorf("Unexpected API stats: %+v", api)
+	}
+	if api.ByStatus["error"] != 1 || api.ByStatus["200"] != 1 {
+		t.Errorf("Unexpected API status counts: %v", api.ByStatus)
+	}
+	if len(api.Methods) != 2 || api.Methods[0].Method != http.MethodGet || api.Methods[0].Requests != 3 {
+		t.Errorf("Unexpected API method stats: %+v", api.Methods)
+	}
+
+	if len(snapshot.Caches) != 1 || snapshot.Caches[0].HitRate != 0.75 {
+		t.Errorf("Unexpected cache stats: %+v", snapshot.Caches)
+	}
+}
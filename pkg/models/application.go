@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Validation constants
@@ -20,13 +21,27 @@ type Application struct {
 	ID          string    `json:"id"`
 	Name        string    `json:"name"`
 	Slug        string    `json:"slug"`
-	TeamID      string    `json:"team_id"`
-	TeamName    string    `json:"team_name,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	TeamID      string    `json:"teamId"`
+	TeamName    string    `json:"teamName,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 	Description string    `json:"description,omitempty"`
 	Icon        string    `json:"icon,omitempty"`
-	IsActive    bool      `json:"is_active"`
+	IsActive    bool      `json:"isActive"`
+}
+
+// Activity is an entry in an application's Vendor Portal activity feed: a change a team
+// member or API token made, such as promoting a release or updating a customer. Actor is the
+// team member's email address or the token's name, and the target is the entity changed.
+type Activity struct {
+	ID          string    `json:"id"`
+	Actor       string    `json:"actor"`
+	Action      string    `json:"action"`
+	TargetType  string    `json:"targetType,omitempty"`
+	TargetID    string    `json:"targetId,omitempty"`
+	TargetName  string    `json:"targetName,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
 }
 
 // Validate ensures the Application struct contains valid data
@@ -39,9 +54,9 @@ func (a *Application) Validate() error {
 	}
 
 	// Validate Name
-	if a.Name == "" {
+	if name := NormalizeName(a.Name); name == "" {
 		errors = append(errors, "application name is required")
-	} else if len(a.Name) > MaxNameLength {
+	} else if utf8.RuneCountInString(name) > MaxNameLength {
 		errors = append(errors, "application name must be 255 characters or less")
 	}
 
@@ -96,6 +111,37 @@ func isValidSlug(slug string) bool {
 	return !strings.HasPrefix(slug, "-") && !strings.HasSuffix(slug, "-")
 }
 
+// ValidateSlug checks that an application slug contains only lowercase letters, numbers,
+// and hyphens, and does not start or end with a hyphen
+func ValidateSlug(slug string) error {
+	if slug == "" {
+		return fmt.Errorf("application slug is required")
+	}
+	if !isValidSlug(slug) {
+		return fmt.Errorf("application slug '%s' must contain only lowercase letters, numbers, and hyphens", slug)
+	}
+	return nil
+}
+
+// SlugFromName returns the slug the Vendor Portal gives an application created with a name:
+// the name lowercased, with each run of other characters replaced by a hyphen
+func SlugFromName(name string) string {
+	var slug strings.Builder
+	hyphen := false
+	for _, r := range FoldText(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return slug.String()
+}
+
 // String returns a string representation of the Application
 func (a *Application) String() string {
 	return fmt.Sprintf("Application{ID: %s, Name: %s, Slug: %s, TeamID: %s, IsActive: %t}",
@@ -71,6 +71,29 @@ func TestApplication_Validate(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"application name must be 255 characters or less"},
 		},
+		{
+			name: "blank name",
+			app: Application{
+				ID:     "app-123",
+				Name:   " \t ",
+				Slug:   "test-app",
+				TeamID: "team-456",
+			},
+			wantErr:     true,
+			errContains: []string{"application name is required"},
+		},
+		{
+			name: "accented name counted in characters",
+			app: Application{
+				ID:        "app-123",
+				Name:      strings.Repeat("é", 255),
+				Slug:      "test-app",
+				TeamID:    "team-456",
+				CreatedAt: validTime,
+				UpdatedAt: validTime,
+			},
+			wantErr: false,
+		},
 		{
 			name: "missing slug",
 			app: Application{
@@ -273,8 +296,8 @@ func TestApplication_JSONMarshaling(t *testing.T) {
 		`"id":"app-123"`,
 		`"name":"Test Application"`,
 		`"slug":"test-app"`,
-		`"team_id":"team-456"`,
-		`"is_active":true`,
+		`"teamId":"team-456"`,
+		`"isActive":true`,
 	}
 
 	jsonString := string(jsonData)
@@ -292,6 +315,37 @@ func TestIsValidSlug(t *testing.T) {
 	testSlugValidation(t, "isValidSlug", isValidSlug, validSlugs, invalidSlugs)
 }
 
+func TestValidateSlug(t *testing.T) {
+	if err := ValidateSlug("my-app-v2"); err != nil {
+		t.Errorf("ValidateSlug() unexpected error: %v", err)
+	}
+	for _, slug := range []string{"", "My-App", "-app"} {
+		if err := ValidateSlug(slug); err == nil {
+			t.Errorf("ValidateSlug(%q) expected an error", slug)
+		}
+	}
+}
+
+func TestSlugFromName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Test App", want: "test-app"},
+		{name: "  Café   Corp  v2 ", want: "cafe-corp-v2"},
+		{name: "Acme (Enterprise) Edition!", want: "acme-enterprise-edition"},
+		{name: "---", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SlugFromName(tt.name); got != tt.want {
+				t.Errorf("SlugFromName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestApplication_String(t *testing.T) {
 	app := Application{
 		ID:       "app-123",
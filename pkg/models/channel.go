@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Channel validation constants
@@ -15,17 +16,79 @@ const (
 // Channel represents a Replicated release channel
 type Channel struct {
 	ID              string     `json:"id"`
-	ApplicationID   string     `json:"application_id"`
+	ApplicationID   string     `json:"appId"`
 	Name            string     `json:"name"`
 	Description     string     `json:"description,omitempty"`
-	ReleaseID       string     `json:"release_id,omitempty"`
-	ReleaseSequence int64      `json:"release_sequence,omitempty"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
-	ArchivedAt      *time.Time `json:"archived_at,omitempty"`
-	IsDefault       bool       `json:"is_default"`
-	IsArchived      bool       `json:"is_archived"`
-	ChannelSlug     string     `json:"channel_slug"`
+	ReleaseID       string     `json:"releaseId,omitempty"`
+	ReleaseSequence int64      `json:"releaseSequence,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	ArchivedAt      *time.Time `json:"archivedAt,omitempty"`
+	IsDefault       bool       `json:"isDefault"`
+	IsArchived      bool       `json:"isArchived"`
+	ChannelSlug     string     `json:"channelSlug"`
+	// SemverRequired requires releases promoted to the channel to have semantic version labels
+	SemverRequired bool `json:"semverRequired"`
+	// EnforceVersionLabel rejects promotions to the channel that do not give a version label
+	EnforceVersionLabel bool `json:"enforceVersionLabel"`
+	// AirgapEnabled builds air gap bundles of the releases promoted to the channel
+	AirgapEnabled bool `json:"airgapEnabled"`
+	// ReleaseNotesRequired rejects promotions to the channel that do not give release notes
+	ReleaseNotesRequired bool `json:"releaseNotesRequired"`
+}
+
+// ChannelVersioning is a channel's semantic versioning settings
+type ChannelVersioning struct {
+	SemverRequired      bool `json:"semverRequired"`
+	EnforceVersionLabel bool `json:"enforceVersionLabel"`
+}
+
+// ChannelSettings is the settings of a channel that govern its releases: whether it is the
+// default channel new customers are assigned to, whether air gap bundles are built for it,
+// and what promotions to it must give
+type ChannelSettings struct {
+	IsDefault     bool `json:"isDefault"`
+	AirgapEnabled bool `json:"airgapEnabled"`
+	ChannelVersioning
+	ReleaseNotesRequired bool `json:"releaseNotesRequired"`
+}
+
+// ChannelRelease is an entry in a channel's release history: a release promoted to the
+// channel and when it was promoted
+type ChannelRelease struct {
+	ChannelSequence int64     `json:"channelSequence"`
+	ReleaseSequence int64     `json:"releaseSequence"`
+	VersionLabel    string    `json:"versionLabel"`
+	PromotedAt      time.Time `json:"promotedAt"`
+}
+
+// Versioning returns the channel's semantic versioning settings
+func (c *Channel) Versioning() ChannelVersioning {
+	return ChannelVersioning{SemverRequired: c.SemverRequired, EnforceVersionLabel: c.EnforceVersionLabel}
+}
+
+// SetVersioning changes the channel's semantic versioning settings
+func (c *Channel) SetVersioning(versioning ChannelVersioning) {
+	c.SemverRequired = versioning.SemverRequired
+	c.EnforceVersionLabel = versioning.EnforceVersionLabel
+}
+
+// Settings returns the channel's settings
+func (c *Channel) Settings() ChannelSettings {
+	return ChannelSettings{
+		IsDefault:            c.IsDefault,
+		AirgapEnabled:        c.AirgapEnabled,
+		ChannelVersioning:    c.Versioning(),
+		ReleaseNotesRequired: c.ReleaseNotesRequired,
+	}
+}
+
+// SetSettings changes the channel's settings
+func (c *Channel) SetSettings(settings ChannelSettings) {
+	c.IsDefault = settings.IsDefault
+	c.AirgapEnabled = settings.AirgapEnabled
+	c.SetVersioning(settings.ChannelVersioning)
+	c.ReleaseNotesRequired = settings.ReleaseNotesRequired
 }
 
 // Validate ensures the Channel struct contains valid data
@@ -54,9 +117,9 @@ func (c *Channel) validateBasicFields() []string {
 	if c.ApplicationID == "" {
 		errors = append(errors, "application ID is required")
 	}
-	if c.Name == "" {
+	if name := NormalizeName(c.Name); name == "" {
 		errors = append(errors, "channel name is required")
-	} else if len(c.Name) > MaxChannelNameLength {
+	} else if utf8.RuneCountInString(name) > MaxChannelNameLength {
 		errors = append(errors, "channel name must be 100 characters or less")
 	}
 	if c.ChannelSlug == "" {
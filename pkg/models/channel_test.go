@@ -406,3 +406,36 @@ func TestChannel_String(t *testing.T) {
 		t.Errorf("Channel.String() = %v, want %v", str, expected)
 	}
 }
+
+func TestChannel_Versioning(t *testing.T) {
+	channel := Channel{ID: "channel-1", SemverRequired: true}
+	if got := channel.Versioning(); got != (ChannelVersioning{SemverRequired: true}) {
+		t.Errorf("Channel.Versioning() = %+v", got)
+	}
+
+	channel.SetVersioning(ChannelVersioning{EnforceVersionLabel: true})
+	if channel.SemverRequired || !channel.EnforceVersionLabel {
+		t.Errorf("Expected the new settings after SetVersioning, got %+v", channel)
+	}
+}
+
+func TestChannel_Settings(t *testing.T) {
+	channel := Channel{ID: "channel-1", IsDefault: true, SemverRequired: true, ReleaseNotesRequired: true}
+	want := ChannelSettings{
+		IsDefault:            true,
+		ChannelVersioning:    ChannelVersioning{SemverRequired: true},
+		ReleaseNotesRequired: true,
+	}
+	if got := channel.Settings(); got != want {
+		t.Errorf("Channel.Settings() = %+v, want %+v", got, want)
+	}
+
+	channel.SetSettings(ChannelSettings{
+		AirgapEnabled:     true,
+		ChannelVersioning: ChannelVersioning{EnforceVersionLabel: true},
+	})
+	if channel.IsDefault || !channel.AirgapEnabled || channel.SemverRequired || !channel.EnforceVersionLabel ||
+		channel.ReleaseNotesRequired {
+		t.Errorf("Expected the new settings after SetSettings, got %+v", channel)
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Compatibility Matrix cluster limits
+const (
+	MaxClusterNameLength = 100
+	MaxClusterNodeCount  = 10
+	MinClusterTTL        = 10 * time.Minute
+	MaxClusterTTL        = 48 * time.Hour
+)
+
+// Cluster represents an ephemeral Compatibility Matrix cluster
+type Cluster struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Distribution string     `json:"distribution"`
+	Version      string     `json:"version"`
+	Status       string     `json:"status"`
+	NodeCount    int        `json:"nodeCount"`
+	InstanceType string     `json:"instanceType,omitempty"`
+	DiskGiB      int        `json:"diskGib,omitempty"`
+	TTL          string     `json:"ttl,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Cluster status constants
+const (
+	ClusterStatusQueued       = "queued"
+	ClusterStatusProvisioning = "provisioning"
+	ClusterStatusRunning      = "running"
+	ClusterStatusTerminated   = "terminated"
+	ClusterStatusError        = "error"
+)
+
+// ClusterSpec describes a Compatibility Matrix cluster to create. Fields left empty use the
+// Vendor Portal's defaults for the distribution.
+type ClusterSpec struct {
+	Name         string `json:"name,omitempty"`
+	Distribution string `json:"distribution"`
+	Version      string `json:"version,omitempty"`
+	NodeCount    int    `json:"nodeCount,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	DiskGiB      int    `json:"diskGib,omitempty"`
+	TTL          string `json:"ttl,omitempty"`
+}
+
+// Validate ensures the ClusterSpec describes a cluster the Vendor Portal can create
+func (c *ClusterSpec) Validate() error {
+	var errors []string
+
+	if strings.TrimSpace(c.Distribution) == "" {
+		errors = append(errors, "cluster distribution is required")
+	}
+	if utf8.RuneCountInString(NormalizeName(c.Name)) > MaxClusterNameLength {
+		errors = append(errors, fmt.Sprintf("cluster name cannot exceed %d characters", MaxClusterNameLength))
+	}
+	if c.NodeCount < 0 || c.NodeCount > MaxClusterNodeCount {
+		errors = append(errors, fmt.Sprintf("cluster node count must be between 1 and %d", MaxClusterNodeCount))
+	}
+	if c.DiskGiB < 0 {
+		errors = append(errors, "cluster disk size cannot be negative")
+	}
+	if message := validateTTL("cluster", c.TTL); message != "" {
+		errors = append(errors, message)
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("cluster validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// validateTTL checks a Compatibility Matrix TTL, returning a message describing the problem
+// or "" when it is empty or valid
+func validateTTL(kind, value string) string {
+	if value == "" {
+		return ""
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < MinClusterTTL || ttl > MaxClusterTTL {
+		return fmt.Sprintf("%s TTL '%s' must be a duration between %s and %s", kind, value, MinClusterTTL, MaxClusterTTL)
+	}
+	return ""
+}
+
+// IsActive reports whether the cluster is running or on its way to running, and so is
+// using credits
+func (c *Cluster) IsActive() bool {
+	return isActiveStatus(c.Status)
+}
+
+// isActiveStatus reports whether a Compatibility Matrix cluster or VM status is queued,
+// provisioning, or running
+func isActiveStatus(status string) bool {
+	switch status {
+	case ClusterStatusQueued, ClusterStatusProvisioning, ClusterStatusRunning:
+		return true
+	default:
+		return false
+	}
+}
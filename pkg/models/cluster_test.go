@@ -0,0 +1,95 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClusterSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        ClusterSpec
+		wantErr     bool
+		errContains []string
+	}{
+		{
+			name:    "minimal spec",
+			spec:    ClusterSpec{Distribution: "k3s"},
+			wantErr: false,
+		},
+		{
+			name: "full spec",
+			spec: ClusterSpec{
+				Name: "ci-1234", Distribution: "eks", Version: "1.30", NodeCount: 3,
+				InstanceType: "m6i.large", DiskGiB: 100, TTL: "4h",
+			},
+			wantErr: false,
+		},
+		{
+			name:        "missing distribution",
+			spec:        ClusterSpec{Name: "ci"},
+			wantErr:     true,
+			errContains: []string{"cluster distribution is required"},
+		},
+		{
+			name:    "out of range values",
+			spec:    ClusterSpec{Distribution: "k3s", Name: strings.Repeat("a", 101), NodeCount: 11, DiskGiB: -1},
+			wantErr: true,
+			errContains: []string{
+				"cluster name cannot exceed 100 characters",
+				"cluster node count must be between 1 and 10",
+				"cluster disk size cannot be negative",
+			},
+		},
+		{
+			name:        "TTL too long",
+			spec:        ClusterSpec{Distribution: "k3s", TTL: "72h"},
+			wantErr:     true,
+			errContains: []string{"cluster TTL '72h' must be a duration between 10m0s and 48h0m0s"},
+		},
+		{
+			name:        "TTL not a duration",
+			spec:        ClusterSpec{Distribution: "k3s", TTL: "tomorrow"},
+			wantErr:     true,
+			errContains: []string{"cluster TTL 'tomorrow'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ClusterSpec.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				for _, expectedErr := range tt.errContains {
+					if !strings.Contains(err.Error(), expectedErr) {
+						t.Errorf("ClusterSpec.Validate() error = %v, should contain %v", err, expectedErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCluster_IsActive(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{status: ClusterStatusQueued, want: true},
+		{status: ClusterStatusProvisioning, want: true},
+		{status: ClusterStatusRunning, want: true},
+		{status: ClusterStatusTerminated, want: false},
+		{status: ClusterStatusError, want: false},
+	}
+
+	for _, tt := range tests {
+		cluster := Cluster{Status: tt.status}
+		if got := cluster.IsActive(); got != tt.want {
+			t.Errorf("Cluster{Status: %s}.IsActive() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package models
 
 import (
 	"fmt"
+	"maps"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Customer validation constants
@@ -15,24 +17,97 @@ const (
 // Customer represents a Replicated customer with license and installation details
 type Customer struct {
 	ID                string            `json:"id"`
-	ApplicationID     string            `json:"application_id"`
+	ApplicationID     string            `json:"appId"`
 	Name              string            `json:"name"`
 	Email             string            `json:"email,omitempty"`
-	ChannelID         string            `json:"channel_id"`
-	ChannelName       string            `json:"channel_name,omitempty"`
-	CreatedAt         time.Time         `json:"created_at"`
-	UpdatedAt         time.Time         `json:"updated_at"`
-	ArchivedAt        *time.Time        `json:"archived_at,omitempty"`
-	ExpiresAt         *time.Time        `json:"expires_at,omitempty"`
+	ChannelID         string            `json:"channelId"`
+	ChannelName       string            `json:"channelName,omitempty"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+	ArchivedAt        *time.Time        `json:"archivedAt,omitempty"`
+	ExpiresAt         *time.Time        `json:"expiresAt,omitempty"`
 	Type              string            `json:"type"`
-	IsArchived        bool              `json:"is_archived"`
-	IsGitOpsSupported bool              `json:"is_gitops_supported"`
-	LicenseID         string            `json:"license_id"`
-	LicenseType       string            `json:"license_type"`
+	IsArchived        bool              `json:"isArchived"`
+	IsGitOpsSupported bool              `json:"isGitopsSupported"`
+	LicenseID         string            `json:"licenseId"`
+	LicenseType       string            `json:"licenseType"`
 	Entitlements      map[string]string `json:"entitlements,omitempty"`
-	CustomFields      map[string]string `json:"custom_fields,omitempty"`
+	CustomFields      map[string]string `json:"customFields,omitempty"`
+	Instances         []Instance        `json:"instances,omitempty"`
+
+	// Install types and features the customer's license enables
+	IsAirgapEnabled                  bool `json:"isAirgapEnabled"`
+	IsSnapshotSupported              bool `json:"isSnapshotSupported"`
+	IsKotsInstallEnabled             bool `json:"isKotsInstallEnabled"`
+	IsEmbeddedClusterDownloadEnabled bool `json:"isEmbeddedClusterDownloadEnabled"`
+	IsHelmVMDownloadEnabled          bool `json:"isHelmVmDownloadEnabled"`
+	IsIdentityServiceSupported       bool `json:"isIdentityServiceSupported"`
+	IsGeoaxisSupported               bool `json:"isGeoaxisSupported"`
+	IsSupportBundleUploadEnabled     bool `json:"isSupportBundleUploadEnabled"`
+}
+
+// Instance represents an installation of the application in a customer environment,
+// as reported by its most recent check-in
+type Instance struct {
+	ID                     string     `json:"id"`
+	VersionLabel           string     `json:"versionLabel"`
+	ReleaseSequence        int64      `json:"releaseSequence"`
+	LastCheckinAt          *time.Time `json:"lastCheckinAt,omitempty"`
+	IsActive               bool       `json:"isActive"`
+	KubernetesDistribution string     `json:"kubernetesDistribution,omitempty"`
+	KubernetesVersion      string     `json:"kubernetesVersion,omitempty"`
+	// VersionHistory lists the releases the instance has run, in the order it installed them
+	VersionHistory []InstanceVersion `json:"versionHistory,omitempty"`
+}
+
+// InstanceVersion records when an instance installed a release
+type InstanceVersion struct {
+	VersionLabel    string    `json:"versionLabel"`
+	ReleaseSequence int64     `json:"releaseSequence"`
+	InstalledAt     time.Time `json:"installedAt"`
+}
+
+// Kinds of instance event
+const (
+	InstanceEventCheckin       = "checkin"
+	InstanceEventVersionChange = "version_change"
+	InstanceEventStatusChange  = "status_change"
+)
+
+// ValidInstanceEventTypes lists the kinds of instance event
+var ValidInstanceEventTypes = []string{
+	InstanceEventCheckin,
+	InstanceEventVersionChange,
+	InstanceEventStatusChange,
 }
 
+// InstanceEvent is something that happened to an instance: a check-in, the installation of
+// another release, or a change in its status. Version changes give the release installed and
+// the one it replaced, and status changes the status entered and the one left.
+type InstanceEvent struct {
+	Type                    string    `json:"type"`
+	OccurredAt              time.Time `json:"occurredAt"`
+	VersionLabel            string    `json:"versionLabel,omitempty"`
+	ReleaseSequence         int64     `json:"releaseSequence,omitempty"`
+	PreviousVersionLabel    string    `json:"previousVersionLabel,omitempty"`
+	PreviousReleaseSequence int64     `json:"previousReleaseSequence,omitempty"`
+	Status                  string    `json:"status,omitempty"`
+	PreviousStatus          string    `json:"previousStatus,omitempty"`
+}
+
+// IsUpgrade reports whether the event is the installation of a later release than the one
+// the instance ran before
+func (e *InstanceEvent) IsUpgrade() bool {
+	return e.Type == InstanceEventVersionChange && e.ReleaseSequence > e.PreviousReleaseSequence
+}
+
+// Custom fields in which account context about a customer is kept in the Vendor Portal:
+// tags as a comma-separated list, and free-form notes
+const (
+	CustomerTagsField  = "tags"
+	CustomerNotesField = "notes"
+)
+
 // Customer type constants
 const (
 	CustomerTypeTrial       = "trial"
@@ -57,7 +132,8 @@ var validCustomerTypes = []string{
 	CustomerTypeDevelopment,
 }
 
-var validLicenseTypes = []string{
+// ValidLicenseTypes lists the supported license types
+var ValidLicenseTypes = []string{
 	LicenseTypeTrial,
 	LicenseTypePaid,
 	LicenseTypeCommunity,
@@ -90,9 +166,9 @@ func (c *Customer) validateBasicFields() []string {
 	if c.ApplicationID == "" {
 		errors = append(errors, "application ID is required")
 	}
-	if c.Name == "" {
+	if name := NormalizeName(c.Name); name == "" {
 		errors = append(errors, "customer name is required")
-	} else if len(c.Name) > MaxCustomerNameLength {
+	} else if utf8.RuneCountInString(name) > MaxCustomerNameLength {
 		errors = append(errors, "customer name must be 255 characters or less")
 	}
 	if c.Email != "" && !isValidEmail(c.Email) {
@@ -114,7 +190,7 @@ func (c *Customer) validateBasicFields() []string {
 		errors = append(errors, "license type is required")
 	} else if !isValidLicenseType(c.LicenseType) {
 		errors = append(errors, fmt.Sprintf("invalid license type '%s'. Valid types are: %s",
-			c.LicenseType, strings.Join(validLicenseTypes, ", ")))
+			c.LicenseType, strings.Join(ValidLicenseTypes, ", ")))
 	}
 
 	return errors
@@ -173,7 +249,7 @@ func isValidCustomerType(customerType string) bool {
 
 // isValidLicenseType checks if the provided license type is valid
 func isValidLicenseType(licenseType string) bool {
-	for _, valid := range validLicenseTypes {
+	for _, valid := range ValidLicenseTypes {
 		if licenseType == valid {
 			return true
 		}
@@ -205,11 +281,77 @@ func (c *Customer) IsExpired() bool {
 	return c.ExpiresAt != nil && c.ExpiresAt.Before(time.Now())
 }
 
+// ActiveInstances returns the customer's instances that are still checking in
+func (c *Customer) ActiveInstances() []Instance {
+	var active []Instance
+	for _, instance := range c.Instances {
+		if instance.IsActive {
+			active = append(active, instance)
+		}
+	}
+	return active
+}
+
+// ReachedAt returns when the instance first installed the release with the given sequence
+// or a later one, according to its version history, or nil if it never did or the history
+// does not say
+func (i *Instance) ReachedAt(sequence int64) *time.Time {
+	var reached *time.Time
+	for j := range i.VersionHistory {
+		version := &i.VersionHistory[j]
+		if version.ReleaseSequence < sequence || version.InstalledAt.IsZero() {
+			continue
+		}
+		if reached == nil || version.InstalledAt.Before(*reached) {
+			reached = &version.InstalledAt
+		}
+	}
+	return reached
+}
+
 // IsTrialCustomer returns true if the customer is a trial customer
 func (c *Customer) IsTrialCustomer() bool {
 	return c.Type == CustomerTypeTrial || c.LicenseType == LicenseTypeTrial
 }
 
+// Tags returns the tags kept in the customer's tags custom field
+func (c *Customer) Tags() []string {
+	tags := []string{}
+	for _, tag := range strings.Split(c.CustomFields[CustomerTagsField], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// SetTags keeps tags in the customer's tags custom field. Removing every tag leaves the field
+// empty rather than deleting it, so saving the customer clears the stored tags.
+func (c *Customer) SetTags(tags []string) {
+	c.setCustomField(CustomerTagsField, strings.Join(tags, ","))
+}
+
+// Notes returns the free-form notes kept in the customer's notes custom field
+func (c *Customer) Notes() string {
+	return c.CustomFields[CustomerNotesField]
+}
+
+// SetNotes keeps notes in the customer's notes custom field
+func (c *Customer) SetNotes(notes string) {
+	c.setCustomField(CustomerNotesField, notes)
+}
+
+// setCustomField sets a custom field on a copy of the customer's custom fields, so customers
+// copied by value do not share the change
+func (c *Customer) setCustomField(name, value string) {
+	fields := maps.Clone(c.CustomFields)
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	fields[name] = value
+	c.CustomFields = fields
+}
+
 // String returns a string representation of the Customer
 func (c *Customer) String() string {
 	return fmt.Sprintf("Customer{ID: %s, ApplicationID: %s, Name: %s, Type: %s, LicenseType: %s, IsArchived: %t}",
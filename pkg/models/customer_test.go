@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -529,6 +530,50 @@ func TestCustomer_IsExpired(t *testing.T) {
 	}
 }
 
+func TestCustomer_ActiveInstances(t *testing.T) {
+	customer := Customer{Instances: []Instance{
+		{ID: "instance-1", ReleaseSequence: 3, IsActive: true},
+		{ID: "instance-2", ReleaseSequence: 1, IsActive: false},
+		{ID: "instance-3", ReleaseSequence: 2, IsActive: true},
+	}}
+
+	active := customer.ActiveInstances()
+	if len(active) != 2 || active[0].ID != "instance-1" || active[1].ID != "instance-3" {
+		t.Errorf("Customer.ActiveInstances() = %+v, want instance-1 and instance-3", active)
+	}
+	if got := (&Customer{}).ActiveInstances(); len(got) != 0 {
+		t.Errorf("Customer.ActiveInstances() = %+v, want none", got)
+	}
+}
+
+func TestInstance_ReachedAt(t *testing.T) {
+	installed := func(day int) time.Time { return time.Date(2025, time.March, day, 0, 0, 0, 0, time.UTC) }
+	instance := Instance{VersionHistory: []InstanceVersion{
+		{ReleaseSequence: 1, InstalledAt: installed(1)},
+		{ReleaseSequence: 4, InstalledAt: installed(10)},
+		{ReleaseSequence: 3, InstalledAt: installed(5)},
+		{ReleaseSequence: 5},
+	}}
+
+	// A zero want means the instance never reached the release
+	tests := []struct {
+		sequence int64
+		want     time.Time
+	}{
+		{sequence: 1, want: installed(1)},
+		{sequence: 2, want: installed(5)},
+		{sequence: 4, want: installed(10)},
+		{sequence: 5},
+	}
+
+	for _, tt := range tests {
+		got := instance.ReachedAt(tt.sequence)
+		if (got == nil) != tt.want.IsZero() || (got != nil && !got.Equal(tt.want)) {
+			t.Errorf("Instance.ReachedAt(%d) = %v, want %v", tt.sequence, got, tt.want)
+		}
+	}
+}
+
 func TestCustomer_IsTrialCustomer(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -588,3 +633,73 @@ func TestCustomer_String(t *testing.T) {
 		t.Errorf("Customer.String() = %v, want %v", str, expected)
 	}
 }
+
+func TestCustomer_TagsAndNotes(t *testing.T) {
+	customer := Customer{CustomFields: map[string]string{"region": "emea", CustomerTagsField: " at-risk, ,enterprise"}}
+	shared := customer
+
+	if got := customer.Tags(); !slices.Equal(got, []string{"at-risk", "enterprise"}) {
+		t.Errorf("Customer.Tags() = %v", got)
+	}
+	if got := (&Customer{}).Tags(); len(got) != 0 {
+		t.Errorf("Expected no tags without custom fields, got %v", got)
+	}
+
+	customer.SetTags([]string{"enterprise"})
+	customer.SetNotes("Renewal call scheduled for March")
+	if got := customer.CustomFields[CustomerTagsField]; got != "enterprise" {
+		t.Errorf("Expected tags field %q, got %q", "enterprise", got)
+	}
+	if got := customer.Notes(); got != "Renewal call scheduled for March" {
+		t.Errorf("Customer.Notes() = %q", got)
+	}
+	if customer.CustomFields["region"] != "emea" {
+		t.Error("Expected other custom fields to be kept")
+	}
+	if shared.Notes() != "" || len(shared.Tags()) != 2 {
+		t.Error("Expected a copy of the customer not to share the change")
+	}
+
+	customer.SetTags(nil)
+	if value, ok := customer.CustomFields[CustomerTagsField]; !ok || value != "" {
+		t.Errorf("Expected removing every tag to leave the field empty, got %q (present %t)", value, ok)
+	}
+	if (&Customer{}).Notes() != "" {
+		t.Error("Expected no notes without custom fields")
+	}
+}
+
+func TestInstanceEvent_IsUpgrade(t *testing.T) {
+	tests := []struct {
+		name  string
+		event InstanceEvent
+		want  bool
+	}{
+		{
+			name:  "upgrade",
+			event: InstanceEvent{Type: InstanceEventVersionChange, ReleaseSequence: 3, PreviousReleaseSequence: 2},
+			want:  true,
+		},
+		{
+			name:  "first install",
+			event: InstanceEvent{Type: InstanceEventVersionChange, ReleaseSequence: 1},
+			want:  true,
+		},
+		{
+			name:  "downgrade",
+			event: InstanceEvent{Type: InstanceEventVersionChange, ReleaseSequence: 2, PreviousReleaseSequence: 3},
+		},
+		{
+			name:  "check-in",
+			event: InstanceEvent{Type: InstanceEventCheckin, ReleaseSequence: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.IsUpgrade(); got != tt.want {
+				t.Errorf("IsUpgrade() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExpirationNever removes a customer's license expiration when used as a CustomerUpdate's ExpiresAt
+const ExpirationNever = "never"
+
+// customerExpirationLayout is the accepted format for expiration dates without a time
+const customerExpirationLayout = "2006-01-02"
+
+// Fields a CustomerUpdate can change, as reported by Apply
+const (
+	CustomerFieldName        = "name"
+	CustomerFieldEmail       = "email"
+	CustomerFieldChannel     = "channel_id"
+	CustomerFieldExpiresAt   = "expires_at"
+	CustomerFieldLicenseType = "license_type"
+)
+
+// CustomerUpdate describes changes to a customer's settings. Empty fields are left unchanged.
+type CustomerUpdate struct {
+	Name      string
+	Email     string
+	ChannelID string
+	// ExpiresAt is a date (YYYY-MM-DD, meaning midnight UTC), an RFC 3339 timestamp, or
+	// ExpirationNever to remove the expiration
+	ExpiresAt   string
+	LicenseType string
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u *CustomerUpdate) IsEmpty() bool {
+	return *u == CustomerUpdate{}
+}
+
+// Validate ensures the update's values are acceptable for a customer
+func (u *CustomerUpdate) Validate() error {
+	var errors []string
+
+	if len(u.Name) > MaxCustomerNameLength {
+		errors = append(errors, "customer name must be 255 characters or less")
+	}
+	if u.Email != "" && !isValidEmail(u.Email) {
+		errors = append(errors, "customer email must be a valid email address")
+	}
+	if u.LicenseType != "" && !isValidLicenseType(u.LicenseType) {
+		errors = append(errors, fmt.Sprintf("invalid license type '%s'. Valid types are: %s",
+			u.LicenseType, strings.Join(ValidLicenseTypes, ", ")))
+	}
+	if u.ExpiresAt != "" {
+		if _, err := parseCustomerExpiration(u.ExpiresAt); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("customer update validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// Apply validates the update and makes its changes to c, returning the names of the fields
+// whose values changed
+func (u *CustomerUpdate) Apply(c *Customer) ([]string, error) {
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	setString := func(field string, target *string, value string) {
+		if value != "" && value != *target {
+			*target = value
+			changed = append(changed, field)
+		}
+	}
+
+	setString(CustomerFieldName, &c.Name, u.Name)
+	setString(CustomerFieldEmail, &c.Email, u.Email)
+	setString(CustomerFieldChannel, &c.ChannelID, u.ChannelID)
+	setString(CustomerFieldLicenseType, &c.LicenseType, u.LicenseType)
+
+	if u.ExpiresAt != "" {
+		// Validate has already checked the expiration
+		expiresAt, _ := parseCustomerExpiration(u.ExpiresAt)
+		if !sameExpiration(c.ExpiresAt, expiresAt) {
+			c.ExpiresAt = expiresAt
+			changed = append(changed, CustomerFieldExpiresAt)
+		}
+	}
+
+	return changed, nil
+}
+
+// parseCustomerExpiration parses an expiration, returning nil for ExpirationNever
+func parseCustomerExpiration(value string) (*time.Time, error) {
+	if strings.EqualFold(value, ExpirationNever) {
+		return nil, nil
+	}
+	if date, err := time.Parse(customerExpirationLayout, value); err == nil {
+		return &date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("expiration must be a date (YYYY-MM-DD or RFC 3339) or '%s', got '%s'",
+			ExpirationNever, value)
+	}
+	return &date, nil
+}
+
+// sameExpiration reports whether two expirations are the same instant, or both absent
+func sameExpiration(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCustomerUpdate_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      CustomerUpdate
+		errContains []string
+	}{
+		{name: "empty update", update: CustomerUpdate{}},
+		{
+			name: "valid update",
+			update: CustomerUpdate{
+				Name: "Acme Corp", Email: "ops@acme.example", ChannelID: "ch-1", ExpiresAt: "2026-06-30",
+				LicenseType: LicenseTypePaid,
+			},
+		},
+		{name: "RFC 3339 expiration", update: CustomerUpdate{ExpiresAt: "2026-06-30T12:00:00Z"}},
+		{name: "remove expiration", update: CustomerUpdate{ExpiresAt: "Never"}},
+		{
+			name:        "name too long",
+			update:      CustomerUpdate{Name: strings.Repeat("a", MaxCustomerNameLength+1)},
+			errContains: []string{"255 characters or less"},
+		},
+		{
+			name:        "invalid email",
+			update:      CustomerUpdate{Email: "not-an-email"},
+			errContains: []string{"valid email address"},
+		},
+		{
+			name:        "invalid license type",
+			update:      CustomerUpdate{LicenseType: "forever"},
+			errContains: []string{"invalid license type 'forever'"},
+		},
+		{
+			name:        "invalid expiration",
+			update:      CustomerUpdate{ExpiresAt: "next month"},
+			errContains: []string{"expiration must be a date"},
+		},
+		{
+			name:        "several errors",
+			update:      CustomerUpdate{Email: "nope", LicenseType: "forever"},
+			errContains: []string{"valid email address", "invalid license type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.update.Validate()
+			if len(tt.errContains) == 0 {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() expected error but got none")
+			}
+			for _, want := range tt.errContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, expected to contain %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomerUpdate_Apply(t *testing.T) {
+	expiresAt := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	extendedAt := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	current := Customer{
+		ID:          "cust-1",
+		Name:        "Acme",
+		Email:       "ops@acme.example",
+		ChannelID:   "ch-stable",
+		ExpiresAt:   &expiresAt,
+		Type:        CustomerTypeTrial,
+		LicenseType: LicenseTypeTrial,
+	}
+
+	tests := []struct {
+		name          string
+		update        CustomerUpdate
+		wantChanged   []string
+		wantExpiresAt *time.Time
+		expectError   bool
+	}{
+		{
+			name:          "extend a trial",
+			update:        CustomerUpdate{ExpiresAt: "2026-03-31"},
+			wantChanged:   []string{CustomerFieldExpiresAt},
+			wantExpiresAt: &extendedAt,
+		},
+		{
+			name:          "convert to paid without expiration",
+			update:        CustomerUpdate{LicenseType: LicenseTypePaid, ExpiresAt: ExpirationNever},
+			wantChanged:   []string{CustomerFieldLicenseType, CustomerFieldExpiresAt},
+			wantExpiresAt: nil,
+		},
+		{
+			name:          "unchanged values",
+			update:        CustomerUpdate{Name: "Acme", ExpiresAt: "2025-12-31T00:00:00Z"},
+			wantExpiresAt: &expiresAt,
+		},
+		{
+			name:          "rename and move channels",
+			update:        CustomerUpdate{Name: "Acme Corp", ChannelID: "ch-beta"},
+			wantChanged:   []string{CustomerFieldName, CustomerFieldChannel},
+			wantExpiresAt: &expiresAt,
+		},
+		{
+			name:        "invalid update",
+			update:      CustomerUpdate{Email: "nope"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := current
+			changed, err := tt.update.Apply(&customer)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Apply() expected error but got none")
+				}
+				if customer.Email != current.Email {
+					t.Error("Apply() should not change the customer when the update is invalid")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Apply() unexpected error = %v", err)
+			}
+			if !slices.Equal(changed, tt.wantChanged) {
+				t.Errorf("Apply() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if !sameExpiration(customer.ExpiresAt, tt.wantExpiresAt) {
+				t.Errorf("Apply() ExpiresAt = %v, want %v", customer.ExpiresAt, tt.wantExpiresAt)
+			}
+		})
+	}
+}
+
+func TestCustomerUpdate_IsEmpty(t *testing.T) {
+	if !(&CustomerUpdate{}).IsEmpty() {
+		t.Error("IsEmpty() = false, want true for an empty update")
+	}
+	if (&CustomerUpdate{Email: "ops@acme.example"}).IsEmpty() {
+		t.Error("IsEmpty() = true, want false for an update with changes")
+	}
+}
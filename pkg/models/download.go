@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kinds of artifact a customer can download from the download portal
+const (
+	DownloadTypeLicense         = "license"
+	DownloadTypeAirgapBundle    = "airgap_bundle"
+	DownloadTypeInstaller       = "installer"
+	DownloadTypeHelmChart       = "helm_chart"
+	DownloadTypeEmbeddedCluster = "embedded_cluster"
+)
+
+// Download portal artifact statuses. Airgap bundles are built on demand, so they can be
+// listed before they are ready to download.
+const (
+	DownloadStatusReady    = "ready"
+	DownloadStatusBuilding = "building"
+	DownloadStatusFailed   = "failed"
+)
+
+// ValidDownloadTypes lists the supported download portal artifact kinds
+var ValidDownloadTypes = []string{
+	DownloadTypeLicense,
+	DownloadTypeAirgapBundle,
+	DownloadTypeInstaller,
+	DownloadTypeHelmChart,
+	DownloadTypeEmbeddedCluster,
+}
+
+// Download is an artifact a customer can download from the download portal, such as their
+// license, an airgap bundle, or an installer for a release on their channel
+type Download struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+	Filename        string `json:"filename,omitempty"`
+	ChannelID       string `json:"channelId,omitempty"`
+	ReleaseSequence int64  `json:"releaseSequence,omitempty"`
+	VersionLabel    string `json:"versionLabel,omitempty"`
+	SizeBytes       int64  `json:"sizeBytes,omitempty"`
+	Status          string `json:"status,omitempty"`
+}
+
+// Validate ensures the Download struct contains valid data
+func (d *Download) Validate() error {
+	var errors []string
+
+	if d.ID == "" {
+		errors = append(errors, "download ID is required")
+	}
+	if !slices.Contains(ValidDownloadTypes, d.Type) {
+		errors = append(errors, fmt.Sprintf("download type must be one of: %s", strings.Join(ValidDownloadTypes, ", ")))
+	}
+	if strings.TrimSpace(d.Name) == "" {
+		errors = append(errors, "download name is required")
+	}
+	if d.SizeBytes < 0 {
+		errors = append(errors, "download size cannot be negative")
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("download validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// IsAvailable reports whether the artifact can be downloaded now. Artifacts without a
+// status are served as-is and are always available.
+func (d *Download) IsAvailable() bool {
+	return d.Status == "" || d.Status == DownloadStatusReady
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDownload_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		download    Download
+		wantErr     bool
+		errContains []string
+	}{
+		{
+			name: "valid airgap bundle",
+			download: Download{
+				ID: "download-1", Type: DownloadTypeAirgapBundle, Name: "Acme 1.2.0 airgap bundle",
+				ReleaseSequence: 3, SizeBytes: 1024, Status: DownloadStatusReady,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "missing required fields",
+			download: Download{SizeBytes: -1},
+			wantErr:  true,
+			errContains: []string{
+				"download ID is required",
+				"download type must be one of",
+				"download name is required",
+				"download size cannot be negative",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.download.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Download.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				for _, expectedErr := range tt.errContains {
+					if !strings.Contains(err.Error(), expectedErr) {
+						t.Errorf("Download.Validate() error = %v, should contain %v", err, expectedErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDownload_IsAvailable(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{status: "", want: true},
+		{status: DownloadStatusReady, want: true},
+		{status: DownloadStatusBuilding, want: false},
+		{status: DownloadStatusFailed, want: false},
+	}
+
+	for _, tt := range tests {
+		download := Download{Status: tt.status}
+		if got := download.IsAvailable(); got != tt.want {
+			t.Errorf("Download{Status: %q}.IsAvailable() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
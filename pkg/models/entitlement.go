@@ -0,0 +1,154 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LicenseField represents an entitlement field defined for an application's licenses
+type LicenseField struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	IsRequired  bool   `json:"isRequired"`
+	IsHidden    bool   `json:"isHidden"`
+	IsBuiltIn   bool   `json:"isBuiltIn"`
+}
+
+// EntitlementValue represents the value of a license field for a specific customer
+type EntitlementValue struct {
+	Name      string `json:"name"`
+	Title     string `json:"title,omitempty"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+// License field type constants
+const (
+	LicenseFieldTypeString  = "string"
+	LicenseFieldTypeText    = "text"
+	LicenseFieldTypeInteger = "integer"
+	LicenseFieldTypeBoolean = "boolean"
+	LicenseFieldTypeDate    = "date"
+)
+
+// LicenseFieldExpiresAt is the built-in license field holding the license expiration date
+const LicenseFieldExpiresAt = "expires_at"
+
+// licenseFieldDateLayout is the accepted format for date entitlement values without a time
+const licenseFieldDateLayout = "2006-01-02"
+
+var validLicenseFieldTypes = []string{
+	LicenseFieldTypeString,
+	LicenseFieldTypeText,
+	LicenseFieldTypeInteger,
+	LicenseFieldTypeBoolean,
+	LicenseFieldTypeDate,
+}
+
+// Validate ensures the LicenseField struct contains valid data
+func (f *LicenseField) Validate() error {
+	var errors []string
+
+	if f.Name == "" {
+		errors = append(errors, "license field name is required")
+	} else if len(f.Name) > MaxKeyLength {
+		errors = append(errors, "license field name must be 100 characters or less")
+	}
+
+	if f.Type == "" {
+		errors = append(errors, "license field type is required")
+	} else if !isValidLicenseFieldType(f.Type) {
+		errors = append(errors, fmt.Sprintf("invalid license field type '%s'. Valid types are: %s",
+			f.Type, strings.Join(validLicenseFieldTypes, ", ")))
+	}
+
+	if f.Default != "" && isValidLicenseFieldType(f.Type) {
+		if err := f.ValidateValue(f.Default); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid default: %v", err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("license field validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// ValidateValue checks that a value can be assigned to this field based on its type
+func (f *LicenseField) ValidateValue(value string) error {
+	if value == "" {
+		if f.IsRequired {
+			return fmt.Errorf("license field '%s' is required and cannot be empty", f.Name)
+		}
+		return nil
+	}
+
+	switch f.Type {
+	case LicenseFieldTypeInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("license field '%s' expects an integer, got '%s'", f.Name, value)
+		}
+	case LicenseFieldTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("license field '%s' expects true or false, got '%s'", f.Name, value)
+		}
+	case LicenseFieldTypeDate:
+		if !isValidLicenseFieldDate(value) {
+			return fmt.Errorf("license field '%s' expects a date (YYYY-MM-DD or RFC 3339), got '%s'", f.Name, value)
+		}
+	case LicenseFieldTypeString:
+		if len(value) > MaxValueLength {
+			return fmt.Errorf("license field '%s' values must be 500 characters or less", f.Name)
+		}
+	}
+
+	return nil
+}
+
+// isValidLicenseFieldType checks if the provided license field type is valid
+func isValidLicenseFieldType(fieldType string) bool {
+	for _, valid := range validLicenseFieldTypes {
+		if fieldType == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidLicenseFieldDate checks if a value is a calendar date or an RFC 3339 timestamp
+func isValidLicenseFieldDate(value string) bool {
+	_, err := parseLicenseFieldDate(value)
+	return err == nil
+}
+
+// parseLicenseFieldDate parses a date entitlement value and returns the instant it ends.
+// A calendar date lasts through the end of that day in UTC.
+func parseLicenseFieldDate(value string) (time.Time, error) {
+	if date, err := time.Parse(licenseFieldDateLayout, value); err == nil {
+		return date.AddDate(0, 0, 1), nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date (YYYY-MM-DD or RFC 3339), got '%s'", value)
+	}
+	return date, nil
+}
+
+// String returns a string representation of the LicenseField
+func (f *LicenseField) String() string {
+	return fmt.Sprintf("LicenseField{Name: %s, Type: %s, Required: %t, Hidden: %t}",
+		f.Name, f.Type, f.IsRequired, f.IsHidden)
+}
+
+// String returns a string representation of the EntitlementValue
+func (e *EntitlementValue) String() string {
+	return fmt.Sprintf("EntitlementValue{Name: %s, Type: %s, Value: %s, Default: %t}",
+		e.Name, e.Type, e.Value, e.IsDefault)
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLicenseField_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		field       LicenseField
+		wantErr     bool
+		errContains []string
+	}{
+		{
+			name:    "valid integer field",
+			field:   LicenseField{Name: "seat_count", Title: "Seats", Type: LicenseFieldTypeInteger, Default: "10"},
+			wantErr: false,
+		},
+		{
+			name:    "missing required fields",
+			field:   LicenseField{},
+			wantErr: true,
+			errContains: []string{
+				"license field name is required",
+				"license field type is required",
+			},
+		},
+		{
+			name:        "invalid type",
+			field:       LicenseField{Name: "seat_count", Type: "number"},
+			wantErr:     true,
+			errContains: []string{"invalid license field type 'number'"},
+		},
+		{
+			name:        "default does not match type",
+			field:       LicenseField{Name: "seat_count", Type: LicenseFieldTypeInteger, Default: "ten"},
+			wantErr:     true,
+			errContains: []string{"invalid default", "expects an integer"},
+		},
+		{
+			name:        "name too long",
+			field:       LicenseField{Name: strings.Repeat("a", 101), Type: LicenseFieldTypeString},
+			wantErr:     true,
+			errContains: []string{"license field name must be 100 characters or less"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LicenseField.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				for _, expectedErr := range tt.errContains {
+					if !strings.Contains(err.Error(), expectedErr) {
+						t.Errorf("LicenseField.Validate() error = %v, expected to contain %v", err, expectedErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLicenseField_ValidateValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   LicenseField
+		value   string
+		wantErr bool
+	}{
+		{"integer", LicenseField{Name: "seats", Type: LicenseFieldTypeInteger}, "25", false},
+		{"integer rejects text", LicenseField{Name: "seats", Type: LicenseFieldTypeInteger}, "many", true},
+		{"boolean", LicenseField{Name: "sso", Type: LicenseFieldTypeBoolean}, "true", false},
+		{"boolean rejects text", LicenseField{Name: "sso", Type: LicenseFieldTypeBoolean}, "yes please", true},
+		{"calendar date", LicenseField{Name: "expires_at", Type: LicenseFieldTypeDate}, "2025-12-31", false},
+		{"timestamp", LicenseField{Name: "expires_at", Type: LicenseFieldTypeDate}, "2025-12-31T00:00:00Z", false},
+		{"date rejects text", LicenseField{Name: "expires_at", Type: LicenseFieldTypeDate}, "next year", true},
+		{"string", LicenseField{Name: "tier", Type: LicenseFieldTypeString}, "gold", false},
+		{"string too long", LicenseField{Name: "tier", Type: LicenseFieldTypeString}, strings.Repeat("a", 501), true},
+		{"text allows long values", LicenseField{Name: "notes", Type: LicenseFieldTypeText}, strings.Repeat("a", 501), false},
+		{"empty optional value", LicenseField{Name: "tier", Type: LicenseFieldTypeString}, "", false},
+		{"empty required value", LicenseField{Name: "tier", Type: LicenseFieldTypeString, IsRequired: true}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.ValidateValue(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LicenseField.ValidateValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLicenseField_String(t *testing.T) {
+	field := LicenseField{Name: "seat_count", Type: LicenseFieldTypeInteger, IsRequired: true}
+
+	expected := "LicenseField{Name: seat_count, Type: integer, Required: true, Hidden: false}"
+	if got := field.String(); got != expected {
+		t.Errorf("LicenseField.String() = %v, want %v", got, expected)
+	}
+}
+
+func TestEntitlementValue_String(t *testing.T) {
+	value := EntitlementValue{Name: "seat_count", Type: LicenseFieldTypeInteger, Value: "25"}
+
+	expected := "EntitlementValue{Name: seat_count, Type: integer, Value: 25, Default: false}"
+	if got := value.String(); got != expected {
+		t.Errorf("EntitlementValue.String() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,231 @@
+package models
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entitlement comparison operators
+const (
+	ComparisonEqual          = "eq"
+	ComparisonNotEqual       = "ne"
+	ComparisonGreater        = "gt"
+	ComparisonGreaterOrEqual = "gte"
+	ComparisonLess           = "lt"
+	ComparisonLessOrEqual    = "lte"
+)
+
+// ValidComparisons lists the supported entitlement comparison operators
+var ValidComparisons = []string{
+	ComparisonEqual,
+	ComparisonNotEqual,
+	ComparisonGreater,
+	ComparisonGreaterOrEqual,
+	ComparisonLess,
+	ComparisonLessOrEqual,
+}
+
+// comparisonSymbols renders operators in evaluation reasons
+var comparisonSymbols = map[string]string{
+	ComparisonEqual:          "==",
+	ComparisonNotEqual:       "!=",
+	ComparisonGreater:        ">",
+	ComparisonGreaterOrEqual: ">=",
+	ComparisonLess:           "<",
+	ComparisonLessOrEqual:    "<=",
+}
+
+// EntitlementCheck describes a customer entitlement to evaluate
+type EntitlementCheck struct {
+	Field LicenseField
+	// Value is the customer's value, or nil when the customer has none and the field default applies
+	Value *EntitlementValue
+	// Operator and Threshold optionally compare the value, such as "gte" and "25". When only a
+	// threshold is given, integer and date fields use "gte" and other fields use "eq".
+	Operator  string
+	Threshold string
+	// LicenseExpiresAt is the customer's license expiration; no entitlement is satisfied once it passes
+	LicenseExpiresAt string
+}
+
+// EntitlementEvaluation reports whether a customer's entitlement is satisfied and why
+type EntitlementEvaluation struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	Value            string `json:"value"`
+	IsDefault        bool   `json:"is_default"`
+	Operator         string `json:"operator,omitempty"`
+	Threshold        string `json:"threshold,omitempty"`
+	Satisfied        bool   `json:"satisfied"`
+	Reason           string `json:"reason"`
+	LicenseExpiresAt string `json:"license_expires_at,omitempty"`
+	LicenseExpired   bool   `json:"license_expired"`
+}
+
+// Evaluate checks the entitlement as of now. Without a comparison, a boolean must be true,
+// an integer must be positive, a date must not have passed, and a string must be set.
+func (c *EntitlementCheck) Evaluate(now time.Time) (*EntitlementEvaluation, error) {
+	operator, err := c.comparison()
+	if err != nil {
+		return nil, err
+	}
+
+	eval := &EntitlementEvaluation{
+		Name:             c.Field.Name,
+		Type:             c.Field.Type,
+		Value:            c.Field.Default,
+		IsDefault:        true,
+		Operator:         operator,
+		Threshold:        c.Threshold,
+		LicenseExpiresAt: c.LicenseExpiresAt,
+	}
+	if c.Value != nil {
+		eval.Value = c.Value.Value
+		eval.IsDefault = c.Value.IsDefault
+	}
+
+	if c.LicenseExpiresAt != "" {
+		expiresAt, err := parseLicenseFieldDate(c.LicenseExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid license expiration: %w", err)
+		}
+		if !now.Before(expiresAt) {
+			eval.LicenseExpired = true
+			eval.Reason = fmt.Sprintf("the customer's license expired (%s)", c.LicenseExpiresAt)
+			return eval, nil
+		}
+	}
+
+	if eval.Value == "" {
+		eval.Reason = "the customer has no value and the field has no default"
+		return eval, nil
+	}
+	if err := c.Field.ValidateValue(eval.Value); err != nil {
+		return nil, fmt.Errorf("customer value is invalid: %w", err)
+	}
+
+	if operator == "" {
+		eval.Satisfied, eval.Reason = c.evaluateValue(eval.Value, now)
+		return eval, nil
+	}
+
+	order, err := c.compare(eval.Value, c.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	eval.Satisfied = comparisonHolds(operator, order)
+	if eval.Satisfied {
+		eval.Reason = fmt.Sprintf("%s %s %s", eval.Value, comparisonSymbols[operator], c.Threshold)
+	} else {
+		eval.Reason = fmt.Sprintf("%s is not %s %s", eval.Value, comparisonSymbols[operator], c.Threshold)
+	}
+	return eval, nil
+}
+
+// comparison validates the requested comparison and returns the operator to apply, filling
+// in the field type's default operator when only a threshold is given
+func (c *EntitlementCheck) comparison() (string, error) {
+	ordered := c.Field.Type == LicenseFieldTypeInteger || c.Field.Type == LicenseFieldTypeDate
+
+	operator := c.Operator
+	switch {
+	case operator == "" && c.Threshold == "":
+		return "", nil
+	case operator == "" && ordered:
+		operator = ComparisonGreaterOrEqual
+	case operator == "":
+		operator = ComparisonEqual
+	case c.Threshold == "":
+		return "", fmt.Errorf("operator '%s' requires a threshold", operator)
+	}
+
+	if !slices.Contains(ValidComparisons, operator) {
+		return "", fmt.Errorf("invalid operator '%s'. Valid operators are: %s",
+			operator, strings.Join(ValidComparisons, ", "))
+	}
+	if !ordered && operator != ComparisonEqual && operator != ComparisonNotEqual {
+		return "", fmt.Errorf("operator '%s' does not apply to %s field '%s'; use eq or ne",
+			operator, c.Field.Type, c.Field.Name)
+	}
+	if err := c.Field.ValidateValue(c.Threshold); err != nil {
+		return "", fmt.Errorf("invalid threshold: %w", err)
+	}
+	return operator, nil
+}
+
+// evaluateValue decides whether a value grants the entitlement on its own
+func (c *EntitlementCheck) evaluateValue(value string, now time.Time) (bool, string) {
+	switch c.Field.Type {
+	case LicenseFieldTypeBoolean:
+		enabled, _ := strconv.ParseBool(value)
+		if enabled {
+			return true, "the entitlement is enabled"
+		}
+		return false, "the entitlement is disabled"
+	case LicenseFieldTypeInteger:
+		n, _ := strconv.ParseInt(value, 10, 64)
+		if n > 0 {
+			return true, fmt.Sprintf("the value %d is positive", n)
+		}
+		return false, fmt.Sprintf("the value %d grants nothing", n)
+	case LicenseFieldTypeDate:
+		expiresAt, _ := parseLicenseFieldDate(value)
+		if now.Before(expiresAt) {
+			return true, fmt.Sprintf("valid through %s", value)
+		}
+		return false, fmt.Sprintf("expired (%s)", value)
+	default:
+		return true, "the value is set"
+	}
+}
+
+// compare orders two values of the field's type, both already validated. Strings and
+// booleans are unordered, so any difference compares as greater.
+func (c *EntitlementCheck) compare(value, threshold string) (int, error) {
+	switch c.Field.Type {
+	case LicenseFieldTypeInteger:
+		a, _ := strconv.ParseInt(value, 10, 64)
+		b, _ := strconv.ParseInt(threshold, 10, 64)
+		return cmp.Compare(a, b), nil
+	case LicenseFieldTypeDate:
+		a, _ := parseLicenseFieldDate(value)
+		b, _ := parseLicenseFieldDate(threshold)
+		return a.Compare(b), nil
+	case LicenseFieldTypeBoolean:
+		a, _ := strconv.ParseBool(value)
+		b, _ := strconv.ParseBool(threshold)
+		if a == b {
+			return 0, nil
+		}
+		return 1, nil
+	case LicenseFieldTypeString, LicenseFieldTypeText:
+		if value == threshold {
+			return 0, nil
+		}
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("cannot compare values of license field type '%s'", c.Field.Type)
+	}
+}
+
+// comparisonHolds applies an operator to the result of a comparison
+func comparisonHolds(operator string, order int) bool {
+	switch operator {
+	case ComparisonEqual:
+		return order == 0
+	case ComparisonNotEqual:
+		return order != 0
+	case ComparisonGreater:
+		return order > 0
+	case ComparisonGreaterOrEqual:
+		return order >= 0
+	case ComparisonLess:
+		return order < 0
+	default:
+		return order <= 0
+	}
+}
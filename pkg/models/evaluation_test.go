@@ -0,0 +1,157 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEntitlementCheck_Evaluate(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	seats := LicenseField{Name: "seat_count", Type: LicenseFieldTypeInteger, Default: "10"}
+	support := LicenseField{Name: "support_end", Type: LicenseFieldTypeDate}
+	analytics := LicenseField{Name: "analytics", Type: LicenseFieldTypeBoolean}
+	tier := LicenseField{Name: "tier", Type: LicenseFieldTypeString}
+
+	tests := []struct {
+		name          string
+		check         EntitlementCheck
+		wantSatisfied bool
+		wantExpired   bool
+		wantOperator  string
+		wantReason    string
+		errContains   string
+	}{
+		{
+			name:          "positive integer",
+			check:         EntitlementCheck{Field: seats, Value: &EntitlementValue{Name: "seat_count", Value: "25"}},
+			wantSatisfied: true,
+		},
+		{
+			name:       "zero integer",
+			check:      EntitlementCheck{Field: seats, Value: &EntitlementValue{Name: "seat_count", Value: "0"}},
+			wantReason: "grants nothing",
+		},
+		{
+			name:          "threshold defaults to gte for integers",
+			check:         EntitlementCheck{Field: seats, Value: &EntitlementValue{Value: "25"}, Threshold: "25"},
+			wantSatisfied: true,
+			wantOperator:  ComparisonGreaterOrEqual,
+			wantReason:    "25 >= 25",
+		},
+		{
+			name:         "integer below threshold",
+			check:        EntitlementCheck{Field: seats, Value: &EntitlementValue{Value: "25"}, Threshold: "50"},
+			wantOperator: ComparisonGreaterOrEqual,
+			wantReason:   "25 is not >= 50",
+		},
+		{
+			name:          "field default applies",
+			check:         EntitlementCheck{Field: seats, Operator: ComparisonLess, Threshold: "11"},
+			wantSatisfied: true,
+			wantOperator:  ComparisonLess,
+		},
+		{
+			name:          "date in the future",
+			check:         EntitlementCheck{Field: support, Value: &EntitlementValue{Value: "2025-12-31"}},
+			wantSatisfied: true,
+		},
+		{
+			name:          "date lasts through the day",
+			check:         EntitlementCheck{Field: support, Value: &EntitlementValue{Value: "2025-06-15"}},
+			wantSatisfied: true,
+		},
+		{
+			name:       "date in the past",
+			check:      EntitlementCheck{Field: support, Value: &EntitlementValue{Value: "2025-01-31"}},
+			wantReason: "expired",
+		},
+		{
+			name: "date compared with a timestamp",
+			check: EntitlementCheck{
+				Field: support, Value: &EntitlementValue{Value: "2025-12-31"}, Threshold: "2026-01-01T00:00:00Z",
+			},
+			wantSatisfied: true,
+			wantOperator:  ComparisonGreaterOrEqual,
+		},
+		{
+			name:  "disabled boolean",
+			check: EntitlementCheck{Field: analytics, Value: &EntitlementValue{Value: "false"}},
+		},
+		{
+			name:          "boolean threshold defaults to eq",
+			check:         EntitlementCheck{Field: analytics, Value: &EntitlementValue{Value: "false"}, Threshold: "false"},
+			wantSatisfied: true,
+			wantOperator:  ComparisonEqual,
+		},
+		{
+			name: "string not equal",
+			check: EntitlementCheck{
+				Field: tier, Value: &EntitlementValue{Value: "gold"}, Operator: ComparisonNotEqual, Threshold: "free",
+			},
+			wantSatisfied: true,
+			wantOperator:  ComparisonNotEqual,
+		},
+		{
+			name:       "no value and no default",
+			check:      EntitlementCheck{Field: tier},
+			wantReason: "no value",
+		},
+		{
+			name: "expired license",
+			check: EntitlementCheck{
+				Field: seats, Value: &EntitlementValue{Value: "25"}, LicenseExpiresAt: "2025-06-14",
+			},
+			wantExpired: true,
+			wantReason:  "license expired",
+		},
+		{
+			name:        "ordering a string",
+			check:       EntitlementCheck{Field: tier, Value: &EntitlementValue{Value: "gold"}, Operator: "gt", Threshold: "a"},
+			errContains: "does not apply to string field",
+		},
+		{
+			name:        "operator without threshold",
+			check:       EntitlementCheck{Field: seats, Operator: ComparisonGreater},
+			errContains: "requires a threshold",
+		},
+		{
+			name:        "unknown operator",
+			check:       EntitlementCheck{Field: seats, Operator: "at_least", Threshold: "5"},
+			errContains: "invalid operator 'at_least'",
+		},
+		{
+			name:        "threshold of the wrong type",
+			check:       EntitlementCheck{Field: seats, Threshold: "many"},
+			errContains: "invalid threshold",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.check.Evaluate(now)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Evaluate() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() unexpected error: %v", err)
+			}
+
+			if got.Satisfied != tt.wantSatisfied {
+				t.Errorf("Evaluate() Satisfied = %t, want %t (reason: %s)", got.Satisfied, tt.wantSatisfied, got.Reason)
+			}
+			if got.LicenseExpired != tt.wantExpired {
+				t.Errorf("Evaluate() LicenseExpired = %t, want %t", got.LicenseExpired, tt.wantExpired)
+			}
+			if got.Operator != tt.wantOperator {
+				t.Errorf("Evaluate() Operator = %s, want %s", got.Operator, tt.wantOperator)
+			}
+			if !strings.Contains(got.Reason, tt.wantReason) {
+				t.Errorf("Evaluate() Reason = %s, expected to contain %s", got.Reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Installer represents a kURL installer spec of an application. ChannelIDs lists the
+// channels that ship it.
+type Installer struct {
+	ID            string    `json:"id,omitempty"`
+	ApplicationID string    `json:"appId"`
+	Sequence      int64     `json:"sequence"`
+	YAML          string    `json:"yaml"`
+	ChannelIDs    []string  `json:"channelIds,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// InstallerAddOn is a kURL add-on an installer spec pins, such as kubernetes or containerd
+type InstallerAddOn struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// installerSpec holds the add-ons of a kURL Installer resource, keyed by add-on name
+type installerSpec struct {
+	Spec map[string]any `yaml:"spec"`
+}
+
+// Validate ensures the Installer struct contains valid data
+func (i *Installer) Validate() error {
+	var errors []string
+
+	if i.ApplicationID == "" {
+		errors = append(errors, "application ID is required")
+	}
+	if i.Sequence <= 0 {
+		errors = append(errors, "installer sequence must be positive")
+	}
+	if strings.TrimSpace(i.YAML) == "" {
+		errors = append(errors, "installer YAML is required")
+	}
+	if i.CreatedAt.IsZero() {
+		errors = append(errors, "created_at timestamp is required")
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("installer validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// AddOns returns the add-ons the installer spec pins a version of, sorted by name. Add-ons
+// without a version, such as kurl settings, are skipped.
+func (i *Installer) AddOns() ([]InstallerAddOn, error) {
+	var spec installerSpec
+	if err := yaml.Unmarshal([]byte(i.YAML), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse installer %d: %w", i.Sequence, err)
+	}
+
+	addOns := []InstallerAddOn{}
+	for name, value := range spec.Spec {
+		settings, ok := value.(map[string]any)
+		if !ok || settings["version"] == nil {
+			continue
+		}
+		addOns = append(addOns, InstallerAddOn{Name: name, Version: fmt.Sprint(settings["version"])})
+	}
+	slices.SortFunc(addOns, func(a, b InstallerAddOn) int { return strings.Compare(a.Name, b.Name) })
+	return addOns, nil
+}
+
+// ShippedBy reports whether a channel ships the installer
+func (i *Installer) ShippedBy(channelID string) bool {
+	return slices.Contains(i.ChannelIDs, channelID)
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testInstallerYAML = `apiVersion: cluster.kurl.sh/v1beta1
+kind: Installer
+metadata:
+  name: acme
+spec:
+  kubernetes:
+    version: 1.29.x
+  containerd:
+    version: 1.6.33
+  flannel:
+    version: 0.25.x
+  kurl:
+    airgap: false
+`
+
+func TestInstaller_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		installer   Installer
+		wantErr     bool
+		errContains []string
+	}{
+		{
+			name: "valid installer",
+			installer: Installer{
+				ApplicationID: "app-456",
+				Sequence:      3,
+				YAML:          testInstallerYAML,
+				CreatedAt:     time.Now(),
+			},
+			wantErr: false,
+		},
+		{
+			name:      "missing required fields",
+			installer: Installer{},
+			wantErr:   true,
+			errContains: []string{
+				"application ID is required",
+				"installer sequence must be positive",
+				"installer YAML is required",
+				"created_at timestamp is required",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.installer.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Installer.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				for _, expectedErr := range tt.errContains {
+					if !strings.Contains(err.Error(), expectedErr) {
+						t.Errorf("Installer.Validate() error = %v, should contain %v", err, expectedErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInstaller_AddOns(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []InstallerAddOn
+		wantErr bool
+	}{
+		{
+			name: "pinned add-ons",
+			yaml: testInstallerYAML,
+			want: []InstallerAddOn{
+				{Name: "containerd", Version: "1.6.33"},
+				{Name: "flannel", Version: "0.25.x"},
+				{Name: "kubernetes", Version: "1.29.x"},
+			},
+		},
+		{name: "empty spec", yaml: "kind: Installer\n", want: []InstallerAddOn{}},
+		{name: "invalid YAML", yaml: "spec: [", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installer := Installer{Sequence: 1, YAML: tt.yaml}
+			addOns, err := installer.AddOns()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Installer.AddOns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(addOns, tt.want) {
+				t.Errorf("Installer.AddOns() = %v, want %v", addOns, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstaller_ShippedBy(t *testing.T) {
+	installer := Installer{ChannelIDs: []string{"channel-1", "channel-2"}}
+	if !installer.ShippedBy("channel-2") {
+		t.Error("ShippedBy(channel-2) = false, want true")
+	}
+	if installer.ShippedBy("channel-3") {
+		t.Error("ShippedBy(channel-3) = true, want false")
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName puts a display name in canonical form: Unicode NFC, with leading and trailing
+// whitespace removed and every run of inner whitespace collapsed to a single space
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+}
+
+// NormalizeSlug puts a slug in canonical form: Unicode NFC, lowercase, with surrounding
+// whitespace removed
+func NormalizeSlug(slug string) string {
+	return strings.ToLower(strings.TrimSpace(norm.NFC.String(slug)))
+}
+
+// FoldText reduces text to the form used to compare names and search queries: normalized as
+// a name, lowercase, and with diacritics removed, so "Café Corp" and "cafe  corp" fold alike
+func FoldText(text string) string {
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, NormalizeName(text))
+	if err != nil {
+		folded = NormalizeName(text)
+	}
+	return strings.ToLower(folded)
+}
+
+// MatchesQuery reports whether any of the fields contains the query once both are folded.
+// An empty query matches nothing.
+func MatchesQuery(query string, fields ...string) bool {
+	folded := FoldText(query)
+	if folded == "" {
+		return false
+	}
+
+	for _, field := range fields {
+		if strings.Contains(FoldText(field), folded) {
+			return true
+		}
+	}
+	return false
+}
+
+// SameName reports whether two names are equal once folded
+func SameName(a, b string) bool {
+	return FoldText(a) == FoldText(b)
+}
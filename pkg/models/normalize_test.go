@@ -0,0 +1,95 @@
+package models
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "already normal", input: "Acme Corp", expected: "Acme Corp"},
+		{name: "surrounding and inner whitespace", input: "  Acme \t\n Corp ", expected: "Acme Corp"},
+		{name: "combining accent composed", input: "Cafe\u0301 Corp", expected: "Caf\u00e9 Corp"},
+		{name: "blank", input: " \t ", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.input); got != tt.expected {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeSlug(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "test-app", expected: "test-app"},
+		{input: " Test-App\n", expected: "test-app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeSlug(tt.input); got != tt.expected {
+				t.Errorf("NormalizeSlug(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFoldText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "Café Corp", expected: "cafe corp"},
+		{input: "Café  Corp", expected: "cafe corp"},
+		{input: "Zürich GmbH", expected: "zurich gmbh"},
+		{input: "Ångström", expected: "angstrom"},
+		{input: "ops@acme.example", expected: "ops@acme.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := FoldText(tt.input); got != tt.expected {
+				t.Errorf("FoldText(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchesQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		fields   []string
+		expected bool
+	}{
+		{name: "diacritics ignored", query: "Cafe Corp", fields: []string{"Café Corp"}, expected: true},
+		{name: "accented query", query: "café", fields: []string{"CAFE CORP"}, expected: true},
+		{name: "any field", query: "ops@", fields: []string{"Acme", "ops@acme.example"}, expected: true},
+		{name: "no match", query: "globex", fields: []string{"Acme Corp"}, expected: false},
+		{name: "blank query", query: "  ", fields: []string{"Acme Corp"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesQuery(tt.query, tt.fields...); got != tt.expected {
+				t.Errorf("MatchesQuery(%q, %v) = %t, want %t", tt.query, tt.fields, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSameName(t *testing.T) {
+	if !SameName("Café Corp", " cafe corp") {
+		t.Error("Expected names differing in case, accents, and spacing to be the same")
+	}
+	if SameName("Acme", "Acme Corp") {
+		t.Error("Expected different names not to be the same")
+	}
+}
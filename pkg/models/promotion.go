@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Promotion policy rules, reported in violations so callers know what to fix
+const (
+	PolicyRuleSemver        = "require_semver"
+	PolicyRuleReleaseNotes  = "require_release_notes"
+	PolicyRuleApprovalLabel = "approval_label"
+)
+
+// PromotionPolicy restricts which releases may be promoted to a channel
+type PromotionPolicy struct {
+	// RequireSemver requires the version label to be a semantic version
+	RequireSemver bool `json:"require_semver" yaml:"require_semver"`
+	// RequireReleaseNotes requires non-empty release notes
+	RequireReleaseNotes bool `json:"require_release_notes" yaml:"require_release_notes"`
+	// ApprovalLabel requires the release's metadata to carry this label with a non-empty value,
+	// such as "qa-approved"
+	ApprovalLabel string `json:"approval_label,omitempty" yaml:"approval_label"`
+}
+
+// Promotion describes a request to promote a release to a channel
+type Promotion struct {
+	Release      *Release
+	VersionLabel string
+	ReleaseNotes string
+}
+
+// PolicyViolation describes a promotion policy rule that a promotion breaks
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PromotionPolicyError reports a promotion rejected by a channel's promotion policy
+type PromotionPolicyError struct {
+	Channel    string
+	Violations []PolicyViolation
+}
+
+func (e *PromotionPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return fmt.Sprintf("promotion to channel '%s' violates its promotion policy: %s",
+		e.Channel, strings.Join(messages, "; "))
+}
+
+// Check returns the policy's rules that the promotion breaks, or nil if it complies
+func (p *PromotionPolicy) Check(promotion Promotion) []PolicyViolation {
+	var violations []PolicyViolation
+
+	if p.RequireSemver && !isValidSemanticVersion(promotion.VersionLabel) {
+		violations = append(violations, PolicyViolation{
+			Rule:    PolicyRuleSemver,
+			Message: fmt.Sprintf("version label '%s' is not a semantic version (e.g. 1.2.3)", promotion.VersionLabel),
+		})
+	}
+
+	if p.RequireReleaseNotes && strings.TrimSpace(promotion.ReleaseNotes) == "" {
+		violations = append(violations, PolicyViolation{
+			Rule:    PolicyRuleReleaseNotes,
+			Message: "release notes are required",
+		})
+	}
+
+	if p.ApprovalLabel != "" && (promotion.Release == nil || promotion.Release.Metadata[p.ApprovalLabel] == "") {
+		violations = append(violations, PolicyViolation{
+			Rule:    PolicyRuleApprovalLabel,
+			Message: fmt.Sprintf("the release is missing the approval label '%s'", p.ApprovalLabel),
+		})
+	}
+
+	return violations
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromotionPolicy_Check(t *testing.T) {
+	approved := &Release{Version: "1.2.0", Metadata: map[string]string{"qa-approved": "alice"}}
+	unapproved := &Release{Version: "1.2.0"}
+
+	tests := []struct {
+		name       string
+		policy     PromotionPolicy
+		promotion  Promotion
+		wantRules  []string
+		wantInText string
+	}{
+		{
+			name:      "empty policy allows anything",
+			promotion: Promotion{Release: unapproved, VersionLabel: "nightly"},
+		},
+		{
+			name:      "compliant promotion",
+			policy:    PromotionPolicy{RequireSemver: true, RequireReleaseNotes: true, ApprovalLabel: "qa-approved"},
+			promotion: Promotion{Release: approved, VersionLabel: "1.2.0", ReleaseNotes: "Fixes"},
+		},
+		{
+			name:       "non-semantic version label",
+			policy:     PromotionPolicy{RequireSemver: true},
+			promotion:  Promotion{Release: approved, VersionLabel: "v1.2"},
+			wantRules:  []string{PolicyRuleSemver},
+			wantInText: "'v1.2' is not a semantic version",
+		},
+		{
+			name:       "blank release notes",
+			policy:     PromotionPolicy{RequireReleaseNotes: true},
+			promotion:  Promotion{Release: approved, VersionLabel: "1.2.0", ReleaseNotes: "  "},
+			wantRules:  []string{PolicyRuleReleaseNotes},
+			wantInText: "release notes are required",
+		},
+		{
+			name:       "every rule broken",
+			policy:     PromotionPolicy{RequireSemver: true, RequireReleaseNotes: true, ApprovalLabel: "qa-approved"},
+			promotion:  Promotion{Release: unapproved, VersionLabel: "latest"},
+			wantRules:  []string{PolicyRuleSemver, PolicyRuleReleaseNotes, PolicyRuleApprovalLabel},
+			wantInText: "missing the approval label 'qa-approved'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tt.policy.Check(tt.promotion)
+			if len(violations) != len(tt.wantRules) {
+				t.Fatalf("Check() = %+v, want rules %v", violations, tt.wantRules)
+			}
+			for i, rule := range tt.wantRules {
+				if violations[i].Rule != rule {
+					t.Errorf("Check() violation %d rule = %s, want %s", i, violations[i].Rule, rule)
+				}
+			}
+
+			if tt.wantInText == "" {
+				return
+			}
+			err := &PromotionPolicyError{Channel: "Stable", Violations: violations}
+			if !strings.Contains(err.Error(), tt.wantInText) || !strings.Contains(err.Error(), "'Stable'") {
+				t.Errorf("PromotionPolicyError.Error() = %s, expected to contain %s", err.Error(), tt.wantInText)
+			}
+		})
+	}
+}
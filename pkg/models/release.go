@@ -15,16 +15,16 @@ const (
 // Release represents a Replicated application release
 type Release struct {
 	ID            string            `json:"id"`
-	ApplicationID string            `json:"application_id"`
+	ApplicationID string            `json:"appId"`
 	Version       string            `json:"version"`
 	Sequence      int64             `json:"sequence"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
-	ReleasedAt    *time.Time        `json:"released_at,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+	ReleasedAt    *time.Time        `json:"releasedAt,omitempty"`
 	Notes         string            `json:"notes,omitempty"`
 	Metadata      map[string]string `json:"metadata,omitempty"`
-	IsRequired    bool              `json:"is_required"`
-	IsPrerelease  bool              `json:"is_prerelease"`
+	IsRequired    bool              `json:"isRequired"`
+	IsPrerelease  bool              `json:"isPrerelease"`
 	Status        string            `json:"status"`
 	Config        string            `json:"config,omitempty"`
 }
@@ -130,6 +130,11 @@ func (r *Release) validateOptionalFields() []string {
 	return errors
 }
 
+// IsSemanticVersion reports whether a version label follows semantic versioning (e.g. 1.2.3)
+func IsSemanticVersion(version string) bool {
+	return isValidSemanticVersion(version)
+}
+
 // isValidSemanticVersion checks if the version follows semantic versioning
 func isValidSemanticVersion(version string) bool {
 	return semVerRegex.MatchString(version)
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SupportBundle represents a troubleshoot support bundle uploaded for a customer instance
+type SupportBundle struct {
+	ID            string           `json:"id"`
+	ApplicationID string           `json:"appId"`
+	CustomerID    string           `json:"customerId,omitempty"`
+	InstanceID    string           `json:"instanceId,omitempty"`
+	Name          string           `json:"name,omitempty"`
+	Status        string           `json:"status"`
+	Size          int64            `json:"size,omitempty"`
+	UploadedAt    time.Time        `json:"uploadedAt"`
+	IsArchived    bool             `json:"isArchived"`
+	Analysis      []AnalyzerResult `json:"analysis,omitempty"`
+}
+
+// AnalyzerResult represents the outcome of a single troubleshoot analyzer run against a bundle
+type AnalyzerResult struct {
+	Name     string `json:"name"`
+	Title    string `json:"title,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message,omitempty"`
+	URI      string `json:"uri,omitempty"`
+}
+
+// Support bundle status constants
+const (
+	SupportBundleStatusUploaded  = "uploaded"
+	SupportBundleStatusAnalyzing = "analyzing"
+	SupportBundleStatusAnalyzed  = "analyzed"
+	SupportBundleStatusFailed    = "failed"
+)
+
+// Analyzer severity constants
+const (
+	AnalyzerSeverityPass  = "pass"
+	AnalyzerSeverityInfo  = "info"
+	AnalyzerSeverityWarn  = "warn"
+	AnalyzerSeverityError = "error"
+)
+
+var validAnalyzerSeverities = []string{
+	AnalyzerSeverityPass,
+	AnalyzerSeverityInfo,
+	AnalyzerSeverityWarn,
+	AnalyzerSeverityError,
+}
+
+// Validate ensures the SupportBundle struct contains valid data
+func (b *SupportBundle) Validate() error {
+	var errors []string
+
+	if b.ID == "" {
+		errors = append(errors, "support bundle ID is required")
+	}
+	if b.ApplicationID == "" {
+		errors = append(errors, "application ID is required")
+	}
+	if b.Status == "" {
+		errors = append(errors, "support bundle status is required")
+	}
+	if b.UploadedAt.IsZero() {
+		errors = append(errors, "uploaded_at timestamp is required")
+	}
+	if b.Size < 0 {
+		errors = append(errors, "support bundle size cannot be negative")
+	}
+
+	for i := range b.Analysis {
+		if !isValidAnalyzerSeverity(b.Analysis[i].Severity) {
+			errors = append(errors, fmt.Sprintf("invalid analyzer severity '%s' for '%s'. Valid severities are: %s",
+				b.Analysis[i].Severity, b.Analysis[i].Name, strings.Join(validAnalyzerSeverities, ", ")))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("support bundle validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// isValidAnalyzerSeverity checks if the provided analyzer severity is valid
+func isValidAnalyzerSeverity(severity string) bool {
+	for _, valid := range validAnalyzerSeverities {
+		if severity == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalysisSummary counts analyzer results by severity
+func (b *SupportBundle) AnalysisSummary() map[string]int {
+	summary := make(map[string]int, len(validAnalyzerSeverities))
+	for _, severity := range validAnalyzerSeverities {
+		summary[severity] = 0
+	}
+	for i := range b.Analysis {
+		summary[b.Analysis[i].Severity]++
+	}
+	return summary
+}
+
+// HasErrors returns true if any analyzer reported an error
+func (b *SupportBundle) HasErrors() bool {
+	for i := range b.Analysis {
+		if b.Analysis[i].Severity == AnalyzerSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the SupportBundle
+func (b *SupportBundle) String() string {
+	return fmt.Sprintf("SupportBundle{ID: %s, ApplicationID: %s, CustomerID: %s, Status: %s, Analyzers: %d}",
+		b.ID, b.ApplicationID, b.CustomerID, b.Status, len(b.Analysis))
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSupportBundle_Validate(t *testing.T) {
+	validTime := time.Now()
+
+	tests := []struct {
+		name        string
+		bundle      SupportBundle
+		wantErr     bool
+		errContains []string
+	}{
+		{
+			name: "valid support bundle",
+			bundle: SupportBundle{
+				ID:            "bundle-123",
+				ApplicationID: "app-456",
+				CustomerID:    "customer-789",
+				Status:        SupportBundleStatusAnalyzed,
+				UploadedAt:    validTime,
+				Analysis: []AnalyzerResult{
+					{Name: "node-resources", Severity: AnalyzerSeverityPass},
+					{Name: "storage-class", Severity: AnalyzerSeverityError, Message: "No default storage class"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing required fields",
+			bundle:  SupportBundle{},
+			wantErr: true,
+			errContains: []string{
+				"support bundle ID is required",
+				"application ID is required",
+				"support bundle status is required",
+				"uploaded_at timestamp is required",
+			},
+		},
+		{
+			name: "negative size",
+			bundle: SupportBundle{
+				ID:            "bundle-123",
+				ApplicationID: "app-456",
+				Status:        SupportBundleStatusUploaded,
+				UploadedAt:    validTime,
+				Size:          -1,
+			},
+			wantErr:     true,
+			errContains: []string{"support bundle size cannot be negative"},
+		},
+		{
+			name: "invalid analyzer severity",
+			bundle: SupportBundle{
+				ID:            "bundle-123",
+				ApplicationID: "app-456",
+				Status:        SupportBundleStatusAnalyzed,
+				UploadedAt:    validTime,
+				Analysis:      []AnalyzerResult{{Name: "cluster-version", Severity: "critical"}},
+			},
+			wantErr:     true,
+			errContains: []string{"invalid analyzer severity 'critical' for 'cluster-version'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bundle.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SupportBundle.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				for _, expectedErr := range tt.errContains {
+					if !strings.Contains(err.Error(), expectedErr) {
+						t.Errorf("SupportBundle.Validate() error = %v, should contain %v", err, expectedErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSupportBundle_AnalysisSummary(t *testing.T) {
+	bundle := SupportBundle{
+		Analysis: []AnalyzerResult{
+			{Name: "a", Severity: AnalyzerSeverityPass},
+			{Name: "b", Severity: AnalyzerSeverityPass},
+			{Name: "c", Severity: AnalyzerSeverityWarn},
+		},
+	}
+
+	summary := bundle.AnalysisSummary()
+	if summary[AnalyzerSeverityPass] != 2 || summary[AnalyzerSeverityWarn] != 1 || summary[AnalyzerSeverityError] != 0 {
+		t.Errorf("Unexpected analysis summary: %v", summary)
+	}
+
+	if bundle.HasErrors() {
+		t.Error("HasErrors() = true, want false")
+	}
+
+	bundle.Analysis = append(bundle.Analysis, AnalyzerResult{Name: "d", Severity: AnalyzerSeverityError})
+	if !bundle.HasErrors() {
+		t.Error("HasErrors() = false, want true")
+	}
+}
+
+func TestSupportBundle_String(t *testing.T) {
+	bundle := SupportBundle{
+		ID:            "bundle-123",
+		ApplicationID: "app-456",
+		CustomerID:    "customer-789",
+		Status:        SupportBundleStatusAnalyzed,
+		Analysis:      []AnalyzerResult{{Name: "a", Severity: AnalyzerSeverityPass}},
+	}
+
+	expected := "SupportBundle{ID: bundle-123, ApplicationID: app-456, CustomerID: customer-789, " +
+		"Status: analyzed, Analyzers: 1}"
+	if got := bundle.String(); got != expected {
+		t.Errorf("SupportBundle.String() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// VM represents an ephemeral Compatibility Matrix virtual machine. VMs share the cluster
+// statuses, name limit, and TTL limits.
+type VM struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Distribution string     `json:"distribution"`
+	Version      string     `json:"version"`
+	Status       string     `json:"status"`
+	InstanceType string     `json:"instanceType,omitempty"`
+	DiskGiB      int        `json:"diskGib,omitempty"`
+	TTL          string     `json:"ttl,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+// VMSpec describes a Compatibility Matrix VM to create. Fields left empty use the Vendor
+// Portal's defaults for the distribution.
+type VMSpec struct {
+	Name         string `json:"name,omitempty"`
+	Distribution string `json:"distribution"`
+	Version      string `json:"version,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	DiskGiB      int    `json:"diskGib,omitempty"`
+	TTL          string `json:"ttl,omitempty"`
+}
+
+// VMQuota reports how many VMs the team may run at once and how many it is running
+type VMQuota struct {
+	MaxVMs    int `json:"maxVms"`
+	ActiveVMs int `json:"activeVms"`
+}
+
+// SSHEndpoint is the address for connecting to a running VM over SSH
+type SSHEndpoint struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	User string `json:"user,omitempty"`
+}
+
+// Validate ensures the VMSpec describes a VM the Vendor Portal can create
+func (v *VMSpec) Validate() error {
+	var errors []string
+
+	if strings.TrimSpace(v.Distribution) == "" {
+		errors = append(errors, "VM distribution is required")
+	}
+	if utf8.RuneCountInString(NormalizeName(v.Name)) > MaxClusterNameLength {
+		errors = append(errors, fmt.Sprintf("VM name cannot exceed %d characters", MaxClusterNameLength))
+	}
+	if v.DiskGiB < 0 {
+		errors = append(errors, "VM disk size cannot be negative")
+	}
+	if message := validateTTL("VM", v.TTL); message != "" {
+		errors = append(errors, message)
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("VM validation errors:\n  - %s", strings.Join(errors, "\n  - "))
+	}
+
+	return nil
+}
+
+// IsActive reports whether the VM is running or on its way to running, and so counts
+// against the quota
+func (v *VM) IsActive() bool {
+	return isActiveStatus(v.Status)
+}
+
+// Remaining returns how long the VM has until it expires, or nil if it has no expiry. A VM
+// past its expiry has no time remaining.
+func (v *VM) Remaining(now time.Time) *time.Duration {
+	if v.ExpiresAt == nil {
+		return nil
+	}
+	remaining := max(v.ExpiresAt.Sub(now), 0)
+	return &remaining
+}
+
+// Remaining returns how many more VMs the team may start
+func (q *VMQuota) Remaining() int {
+	return max(q.MaxVMs-q.ActiveVMs, 0)
+}
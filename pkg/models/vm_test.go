@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVMSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        VMSpec
+		wantErr     bool
+		errContains []string
+	}{
+		{
+			name:    "minimal spec",
+			spec:    VMSpec{Distribution: "ubuntu"},
+			wantErr: false,
+		},
+		{
+			name: "full spec",
+			spec: VMSpec{
+				Name: "ec-test", Distribution: "ubuntu", Version: "24.04", InstanceType: "r1.medium",
+				DiskGiB: 50, TTL: "2h",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid values",
+			spec:    VMSpec{Name: strings.Repeat("a", 101), DiskGiB: -1, TTL: "5m"},
+			wantErr: true,
+			errContains: []string{
+				"VM distribution is required",
+				"VM name cannot exceed 100 characters",
+				"VM disk size cannot be negative",
+				"VM TTL '5m' must be a duration between 10m0s and 48h0m0s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VMSpec.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				for _, expectedErr := range tt.errContains {
+					if !strings.Contains(err.Error(), expectedErr) {
+						t.Errorf("VMSpec.Validate() error = %v, should contain %v", err, expectedErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVM_Remaining(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(90 * time.Minute)
+	earlier := now.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		vm   VM
+		want *time.Duration
+	}{
+		{name: "expires later", vm: VM{ExpiresAt: &later}, want: durationPtr(90 * time.Minute)},
+		{name: "already expired", vm: VM{ExpiresAt: &earlier}, want: durationPtr(0)},
+		{name: "no expiry", vm: VM{}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.vm.Remaining(now)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("VM.Remaining() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVM_IsActive(t *testing.T) {
+	if !(&VM{Status: ClusterStatusRunning}).IsActive() {
+		t.Error("Expected a running VM to be active")
+	}
+	if (&VM{Status: ClusterStatusTerminated}).IsActive() {
+		t.Error("Expected a terminated VM not to be active")
+	}
+}
+
+func TestVMQuota_Remaining(t *testing.T) {
+	tests := []struct {
+		quota VMQuota
+		want  int
+	}{
+		{quota: VMQuota{MaxVMs: 5, ActiveVMs: 2}, want: 3},
+		{quota: VMQuota{MaxVMs: 5, ActiveVMs: 7}, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.quota.Remaining(); got != tt.want {
+			t.Errorf("VMQuota%+v.Remaining() = %d, want %d", tt.quota, got, tt.want)
+		}
+	}
+}
+
+// durationPtr returns a pointer to a duration
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
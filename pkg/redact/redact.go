@@ -0,0 +1,172 @@
+// Package redact masks credentials and personal data before they leave the server in logs,
+// API traffic captures, audit records, and diagnostic output. Values are masked by the name
+// of the key or header holding them, such as "api_token" or Authorization, and by patterns
+// matched anywhere in text: email addresses always, plus any patterns the operator
+// configures, such as a license ID format.
+package redact
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces redacted values
+const Mask = "[REDACTED]"
+
+// sensitiveKeys are key names whose values are always masked. Keys match ignoring case,
+// underscores, and hyphens, and also within a longer key such as "api_token".
+var sensitiveKeys = []string{
+	"token", "password", "secret", "authorization", "cookie", "credential", "apikey", "privatekey",
+	"email", "phone", "address", "licenseid", "installationid",
+}
+
+// sensitiveHeaders are HTTP headers whose values are always masked
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// keySeparators are removed from key names before matching them against sensitiveKeys
+var keySeparators = strings.NewReplacer("_", "", "-", "")
+
+// emailPattern matches email addresses in free text
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// defaultRedactor masks sensitive keys, headers, and email addresses
+var defaultRedactor = &Redactor{patterns: []*regexp.Regexp{emailPattern}}
+
+// Redactor masks sensitive values. It is safe for concurrent use.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New returns a redactor that also masks text matching each of the given regular
+// expressions, such as `\blic_[A-Za-z0-9]{24}\b` for license IDs
+func New(patterns []string) (*Redactor, error) {
+	redactor := &Redactor{patterns: []*regexp.Regexp{emailPattern}}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern '%s': %w", pattern, err)
+		}
+		redactor.patterns = append(redactor.patterns, compiled)
+	}
+	return redactor, nil
+}
+
+// Default returns a redactor without configured patterns
+func Default() *Redactor {
+	return defaultRedactor
+}
+
+// String masks the text matching the redactor's patterns
+func (r *Redactor) String(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, Mask)
+	}
+	return s
+}
+
+// Value returns a copy of a decoded JSON value, such as tool call arguments, with the values
+// of sensitive keys masked and the redactor's patterns masked in strings throughout
+func (r *Redactor) Value(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, child := range v {
+			if IsSensitiveKey(key) {
+				redacted[key] = Mask
+				continue
+			}
+			redacted[key] = r.Value(child)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, child := range v {
+			redacted[i] = r.Value(child)
+		}
+		return redacted
+	case string:
+		return r.String(v)
+	default:
+		return value
+	}
+}
+
+// Map returns a redacted copy of a JSON object such as tool call arguments. A nil map
+// becomes an empty one.
+func (r *Redactor) Map(values map[string]any) map[string]any {
+	if values == nil {
+		return map[string]any{}
+	}
+	redacted, _ := r.Value(values).(map[string]any)
+	return redacted
+}
+
+// Header masks a header value if the header carries credentials, and otherwise masks the
+// redactor's patterns within it
+func (r *Redactor) Header(name, value string) string {
+	if IsSensitiveHeader(name) {
+		return Mask
+	}
+	return r.String(value)
+}
+
+// Attr redacts a log attribute. It has the signature of slog.HandlerOptions.ReplaceAttr, so
+// a handler can redact every attribute it writes, including the message. Attributes with
+// sensitive keys are masked; strings, errors, and JSON objects have patterns masked.
+func (r *Redactor) Attr(_ []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.MessageKey && IsSensitiveKey(a.Key) {
+		return slog.String(a.Key, Mask)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(r.String(a.Value.String()))
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			a.Value = slog.StringValue(r.String(v.Error()))
+		case map[string]any, []any:
+			a.Value = slog.AnyValue(r.Value(v))
+		case http.Header:
+			a.Value = slog.AnyValue(r.headers(v))
+		}
+	default:
+	}
+	return a
+}
+
+// headers returns a copy of HTTP headers with each value redacted
+func (r *Redactor) headers(headers http.Header) http.Header {
+	redacted := make(http.Header, len(headers))
+	for name, values := range headers {
+		for _, value := range values {
+			redacted[name] = append(redacted[name], r.Header(name, value))
+		}
+	}
+	return redacted
+}
+
+// IsSensitiveKey reports whether a key holds credentials or personal data
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(keySeparators.Replace(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveHeader reports whether an HTTP header carries credentials, either as one of the
+// standard credential headers or under a sensitive name, such as an API gateway's X-Api-Key
+func IsSensitiveHeader(name string) bool {
+	for _, sensitive := range sensitiveHeaders {
+		if strings.EqualFold(name, sensitive) {
+			return true
+		}
+	}
+	return IsSensitiveKey(name)
+}
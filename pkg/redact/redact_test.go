@@ -0,0 +1,189 @@
+package redact
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  bool
+	}{
+		{name: "no patterns", patterns: nil},
+		{name: "valid patterns", patterns: []string{`lic_[a-z0-9]+`, `\bcust-\d+\b`}},
+		{name: "invalid pattern", patterns: []string{`lic_[a-z`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactor, err := New(tt.patterns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(redactor.patterns) != len(tt.patterns)+1 {
+				t.Errorf("Expected the email pattern plus %d patterns, got %d", len(tt.patterns), len(redactor.patterns))
+			}
+		})
+	}
+}
+
+func TestRedactor_String(t *testing.T) {
+	redactor, err := New([]string{`lic_[a-z0-9]+`})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		redactor *Redactor
+		input    string
+		want     string
+	}{
+		{name: "plain text", redactor: Default(), input: "release 1.2.3 promoted", want: "release 1.2.3 promoted"},
+		{name: "email", redactor: Default(), input: "contact ops@acme.example", want: "contact [REDACTED]"},
+		{name: "default ignores patterns", redactor: Default(), input: "license lic_abc", want: "license lic_abc"},
+		{name: "configured pattern", redactor: redactor, input: "license lic_abc", want: "license [REDACTED]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.redactor.String(tt.input); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Map(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   map[string]any
+	}{
+		{name: "nil values", values: nil, want: map[string]any{}},
+		{
+			name:   "sensitive keys in any style",
+			values: map[string]any{"Password": "p", "client-secret": "s", "apiKey": "k", "licenseId": "l", "name": "Acme"},
+			want:   map[string]any{"Password": Mask, "client-secret": Mask, "apiKey": Mask, "licenseId": Mask, "name": "Acme"},
+		},
+		{
+			name: "nested values",
+			values: map[string]any{"operations": []any{
+				map[string]any{"tool": "update_customer", "arguments": map[string]any{"auth_token": "t", "count": 2}},
+			}},
+			want: map[string]any{"operations": []any{
+				map[string]any{"tool": "update_customer", "arguments": map[string]any{"auth_token": Mask, "count": 2}},
+			}},
+		},
+		{
+			name:   "patterns in string values",
+			values: map[string]any{"notes": "owner is ops@acme.example", "tags": []any{"a@b.example"}},
+			want:   map[string]any{"notes": "owner is [REDACTED]", "tags": []any{Mask}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Default().Map(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_MapLeavesInputUnchanged(t *testing.T) {
+	values := map[string]any{"api_token": "secret", "nested": map[string]any{"email": "ops@acme.example"}}
+	Default().Map(values)
+
+	if values["api_token"] != "secret" || values["nested"].(map[string]any)["email"] != "ops@acme.example" {
+		t.Errorf("Map() changed its input: %v", values)
+	}
+}
+
+func TestRedactor_Header(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "Authorization", value: "Bearer abc", want: Mask},
+		{name: "set-cookie", value: "session=abc", want: Mask},
+		{name: "Accept", value: "application/json", want: "application/json"},
+		{name: "X-Contact", value: "ops@acme.example", want: Mask},
+		{name: "X-Api-Key", value: "gateway-key", want: Mask},
+		{name: "X-Gateway-Token", value: "gateway-token", want: Mask},
+		{name: "X-Tenant", value: "acme", want: "acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Default().Header(tt.name, tt.value); got != tt.want {
+				t.Errorf("Header() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Attr(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want any
+	}{
+		{name: "message", attr: slog.String(slog.MessageKey, "sent to ops@acme.example"), want: "sent to [REDACTED]"},
+		{name: "sensitive key", attr: slog.String("api_token", "abc"), want: Mask},
+		{name: "sensitive key with other kind", attr: slog.Int("license_id", 42), want: Mask},
+		{name: "other string", attr: slog.String("app_id", "app-1"), want: "app-1"},
+		{name: "other kind", attr: slog.Int("count", 3), want: int64(3)},
+		{name: "error", attr: slog.Any("error", errors.New("no customer ops@acme.example")), want: "no customer [REDACTED]"},
+		{
+			name: "arguments",
+			attr: slog.Any("arguments", map[string]any{"password": "p", "name": "Acme"}),
+			want: map[string]any{"password": Mask, "name": "Acme"},
+		},
+		{
+			name: "headers",
+			attr: slog.Any("headers", http.Header{"Authorization": {"Bearer abc"}}),
+			want: http.Header{"Authorization": {Mask}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Default().Attr(nil, tt.attr)
+			if got.Key != tt.attr.Key {
+				t.Errorf("Attr() key = %q, want %q", got.Key, tt.attr.Key)
+			}
+			if !reflect.DeepEqual(got.Value.Any(), tt.want) {
+				t.Errorf("Attr() value = %v, want %v", got.Value.Any(), tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"api_token", true},
+		{"AUTHORIZATION", true},
+		{"installation-id", true},
+		{"customer_email", true},
+		{"app_id", false},
+		{"channel", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsSensitiveKey(tt.key); got != tt.want {
+				t.Errorf("IsSensitiveKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
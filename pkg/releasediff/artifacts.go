@@ -0,0 +1,145 @@
+package releasediff
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// defaultImageTag is the tag a container runtime pulls when an image reference has none
+const defaultImageTag = "latest"
+
+// chartArchivePattern matches packaged Helm charts named <chart>-<version>.tgz
+var chartArchivePattern = regexp.MustCompile(`^(.+)-(v?\d+\.\d+\.\d+[^/]*)\.tgz$`)
+
+// artifacts maps image repositories or chart names to the versions a release references
+type artifacts map[string]map[string]bool
+
+// Image is a container image repository a release references, with the tags or digests
+// it is referenced at
+type Image struct {
+	Repository string   `json:"repository"`
+	Versions   []string `json:"versions"`
+}
+
+// Images returns the container images a release's manifests reference, sorted by repository
+func Images(release *models.Release) ([]Image, error) {
+	files, err := Files(release)
+	if err != nil {
+		return nil, err
+	}
+
+	images, _ := collectArtifacts(files)
+	list := make([]Image, 0, len(images))
+	for repository, versions := range images {
+		list = append(list, Image{Repository: repository, Versions: sortedKeys(versions)})
+	}
+	slices.SortFunc(list, func(a, b Image) int {
+		return strings.Compare(a.Repository, b.Repository)
+	})
+	return list, nil
+}
+
+// add records a version of an artifact
+func (a artifacts) add(name, version string) {
+	if a[name] == nil {
+		a[name] = make(map[string]bool)
+	}
+	a[name][version] = true
+}
+
+// collectArtifacts finds the container images and Helm chart versions referenced by files.
+// Images come from "image" fields in YAML files; chart versions come from Chart.yaml files,
+// KOTS HelmChart resources, and packaged chart archive names. Files that are not valid YAML
+// are skipped, since releases may contain other content.
+func collectArtifacts(files []File) (images, charts artifacts) {
+	images, charts = artifacts{}, artifacts{}
+
+	for _, file := range files {
+		if match := chartArchivePattern.FindStringSubmatch(path.Base(file.Path)); match != nil {
+			charts.add(match[1], match[2])
+			continue
+		}
+		if ext := path.Ext(file.Path); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		decoder := yaml.NewDecoder(strings.NewReader(file.Content))
+		for {
+			// Stop at the end of the file or the first document that is not valid YAML
+			var doc any
+			if err := decoder.Decode(&doc); err != nil {
+				break
+			}
+			collectImages(doc, images)
+			collectChart(file.Path, doc, charts)
+		}
+	}
+	return images, charts
+}
+
+// collectImages records every image reference in a YAML value. Templated references are
+// skipped because their value is only known at install time.
+func collectImages(value any, images artifacts) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "image" {
+				if ref != "" && !strings.Contains(ref, "{{") {
+					images.add(SplitImage(ref))
+				}
+				continue
+			}
+			collectImages(child, images)
+		}
+	case []any:
+		for _, child := range v {
+			collectImages(child, images)
+		}
+	}
+}
+
+// collectChart records the chart version declared by a Chart.yaml file or HelmChart resource
+func collectChart(filePath string, doc any, charts artifacts) {
+	fields, ok := doc.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if path.Base(filePath) == "Chart.yaml" {
+		name, _ := fields["name"].(string)
+		version, _ := fields["version"].(string)
+		if name != "" && version != "" {
+			charts.add(name, version)
+		}
+		return
+	}
+
+	if fields["kind"] != "HelmChart" {
+		return
+	}
+	spec, _ := fields["spec"].(map[string]any)
+	chart, _ := spec["chart"].(map[string]any)
+	name, _ := chart["name"].(string)
+	version, _ := chart["chartVersion"].(string)
+	if name != "" && version != "" {
+		charts.add(name, version)
+	}
+}
+
+// SplitImage splits an image reference into its repository and its tag or digest, which is
+// "latest" when the reference has neither
+func SplitImage(ref string) (repository, version string) {
+	if at := strings.LastIndex(ref, "@"); at >= 0 {
+		return ref[:at], ref[at+1:]
+	}
+	if colon := strings.LastIndex(ref, ":"); colon > strings.LastIndex(ref, "/") {
+		return ref[:colon], ref[colon+1:]
+	}
+	return ref, defaultImageTag
+}
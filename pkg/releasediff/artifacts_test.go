@@ -0,0 +1,58 @@
+package releasediff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		ref, wantRepository, wantVersion string
+	}{
+		{ref: "nginx", wantRepository: "nginx", wantVersion: "latest"},
+		{ref: "nginx:1.25", wantRepository: "nginx", wantVersion: "1.25"},
+		{ref: "registry.example.com:5000/app/web", wantRepository: "registry.example.com:5000/app/web",
+			wantVersion: "latest"},
+		{ref: "registry.example.com:5000/app/web:2.0", wantRepository: "registry.example.com:5000/app/web",
+			wantVersion: "2.0"},
+		{ref: "app/web@sha256:abc", wantRepository: "app/web", wantVersion: "sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		repository, version := SplitImage(tt.ref)
+		if repository != tt.wantRepository || version != tt.wantVersion {
+			t.Errorf("SplitImage(%s) = %s, %s, want %s, %s", tt.ref, repository, version, tt.wantRepository, tt.wantVersion)
+		}
+	}
+}
+
+func TestImages(t *testing.T) {
+	release := releaseWithFiles(t, 3, "1.3.0", map[string]string{
+		"deployment.yaml": "kind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n" +
+			"        - image: registry.replicated.com/acme/api:1.3.0\n        - image: redis:7\n",
+		"worker.yaml": "kind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n" +
+			"        - image: registry.replicated.com/acme/api:1.3.0-worker\n" +
+			"        - image: '{{repl LocalImageName \"busybox\"}}'\n",
+		"README.md": "image: ignored\n",
+	})
+
+	images, err := Images(release)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Image{
+		{Repository: "redis", Versions: []string{"7"}},
+		{Repository: "registry.replicated.com/acme/api", Versions: []string{"1.3.0", "1.3.0-worker"}},
+	}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("Images() = %+v, want %+v", images, expected)
+	}
+
+	empty, err := Images(&models.Release{Sequence: 1})
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected no images for a release without manifests, got %+v, %v", empty, err)
+	}
+}
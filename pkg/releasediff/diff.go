@@ -0,0 +1,138 @@
+package releasediff
+
+import (
+	"slices"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Change kinds for images and charts
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Diff reports what changed between two releases
+type Diff struct {
+	From   ReleaseRef      `json:"from"`
+	To     ReleaseRef      `json:"to"`
+	Files  FileChanges     `json:"files"`
+	Images []VersionChange `json:"images"`
+	Charts []VersionChange `json:"charts"`
+}
+
+// ReleaseRef identifies a compared release
+type ReleaseRef struct {
+	Sequence int64  `json:"sequence"`
+	Version  string `json:"version"`
+}
+
+// FileChanges lists the paths of files added, removed, and changed between releases
+type FileChanges struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// VersionChange records the versions of an image repository or Helm chart referenced by
+// each release. An image may be referenced at several tags, so versions are lists.
+type VersionChange struct {
+	Name   string   `json:"name"`
+	Change string   `json:"change"`
+	From   []string `json:"from"`
+	To     []string `json:"to"`
+}
+
+// IsEmpty reports whether the releases have identical manifests
+func (d *Diff) IsEmpty() bool {
+	return len(d.Files.Added) == 0 && len(d.Files.Removed) == 0 && len(d.Files.Changed) == 0
+}
+
+// Compare reports the files, images, and charts that changed from one release to another
+func Compare(from, to *models.Release) (*Diff, error) {
+	before, err := Files(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := Files(to)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeImages, beforeCharts := collectArtifacts(before)
+	afterImages, afterCharts := collectArtifacts(after)
+
+	return &Diff{
+		From:   ReleaseRef{Sequence: from.Sequence, Version: from.Version},
+		To:     ReleaseRef{Sequence: to.Sequence, Version: to.Version},
+		Files:  compareFiles(before, after),
+		Images: compareArtifacts(beforeImages, afterImages),
+		Charts: compareArtifacts(beforeCharts, afterCharts),
+	}, nil
+}
+
+// compareFiles compares two sorted file lists by path and content
+func compareFiles(before, after []File) FileChanges {
+	changes := FileChanges{Added: []string{}, Removed: []string{}, Changed: []string{}}
+
+	contents := make(map[string]string, len(before))
+	for _, file := range before {
+		contents[file.Path] = file.Content
+	}
+	for _, file := range after {
+		previous, existed := contents[file.Path]
+		switch {
+		case !existed:
+			changes.Added = append(changes.Added, file.Path)
+		case previous != file.Content:
+			changes.Changed = append(changes.Changed, file.Path)
+		}
+		delete(contents, file.Path)
+	}
+	for _, file := range before {
+		if _, removed := contents[file.Path]; removed {
+			changes.Removed = append(changes.Removed, file.Path)
+		}
+	}
+	return changes
+}
+
+// compareArtifacts reports artifacts whose referenced versions differ, sorted by name
+func compareArtifacts(before, after artifacts) []VersionChange {
+	names := make(map[string]bool, len(before)+len(after))
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	changes := []VersionChange{}
+	for _, name := range sortedKeys(names) {
+		from, to := sortedKeys(before[name]), sortedKeys(after[name])
+		change := VersionChange{Name: name, From: from, To: to}
+		switch {
+		case len(from) == 0:
+			change.Change = ChangeAdded
+		case len(to) == 0:
+			change.Change = ChangeRemoved
+		case !slices.Equal(from, to):
+			change.Change = ChangeChanged
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
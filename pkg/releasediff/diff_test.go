@@ -0,0 +1,89 @@
+package releasediff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// releaseWithFiles builds a release whose config is a file tree with the given contents
+func releaseWithFiles(t *testing.T, sequence int64, version string, files map[string]string) *models.Release {
+	t.Helper()
+
+	var tree []specFile
+	for path, content := range files {
+		tree = append(tree, specFile{Name: path, Path: path, Content: content})
+	}
+	config, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("Failed to encode release files: %v", err)
+	}
+	return &models.Release{Sequence: sequence, Version: version, Config: string(config)}
+}
+
+func TestCompare(t *testing.T) {
+	from := releaseWithFiles(t, 4, "1.4.0", map[string]string{
+		"deployment.yaml": "kind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n" +
+			"        - image: registry.example.com/app/web:1.4.0\n        - image: redis:7\n",
+		"helmchart.yaml": "apiVersion: kots.io/v1beta2\nkind: HelmChart\nspec:\n  chart:\n" +
+			"    name: postgres\n    chartVersion: 12.1.0\n",
+		"legacy.yaml":         "kind: ConfigMap\n",
+		"postgres-12.1.0.tgz": "H4sI...",
+	})
+	to := releaseWithFiles(t, 5, "1.5.0", map[string]string{
+		"deployment.yaml": "kind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n" +
+			"        - image: registry.example.com/app/web:1.5.0\n        - image: redis:7\n" +
+			"        - image: '{{repl LocalImageName \"busybox\"}}'\n",
+		"helmchart.yaml": "apiVersion: kots.io/v1beta2\nkind: HelmChart\nspec:\n  chart:\n" +
+			"    name: postgres\n    chartVersion: 12.2.0\n",
+		"worker.yaml":         "kind: Deployment\nspec:\n  image: registry.example.com/app/worker@sha256:abc\n",
+		"postgres-12.2.0.tgz": "H4sI...",
+	})
+
+	diff, err := Compare(from, to)
+	if err != nil {
+		t.Fatalf("Compare() unexpected error: %v", err)
+	}
+
+	wantFiles := FileChanges{
+		Added:   []string{"postgres-12.2.0.tgz", "worker.yaml"},
+		Removed: []string{"legacy.yaml", "postgres-12.1.0.tgz"},
+		Changed: []string{"deployment.yaml", "helmchart.yaml"},
+	}
+	if !reflect.DeepEqual(diff.Files, wantFiles) {
+		t.Errorf("Compare() files = %+v, want %+v", diff.Files, wantFiles)
+	}
+
+	wantImages := []VersionChange{
+		{Name: "registry.example.com/app/web", Change: ChangeChanged, From: []string{"1.4.0"}, To: []string{"1.5.0"}},
+		{Name: "registry.example.com/app/worker", Change: ChangeAdded, From: []string{}, To: []string{"sha256:abc"}},
+	}
+	if !reflect.DeepEqual(diff.Images, wantImages) {
+		t.Errorf("Compare() images = %+v, want %+v", diff.Images, wantImages)
+	}
+
+	wantCharts := []VersionChange{
+		{Name: "postgres", Change: ChangeChanged, From: []string{"12.1.0"}, To: []string{"12.2.0"}},
+	}
+	if !reflect.DeepEqual(diff.Charts, wantCharts) {
+		t.Errorf("Compare() charts = %+v, want %+v", diff.Charts, wantCharts)
+	}
+
+	if diff.From.Version != "1.4.0" || diff.To.Sequence != 5 {
+		t.Errorf("Unexpected release references: %+v -> %+v", diff.From, diff.To)
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	release := releaseWithFiles(t, 1, "1.0.0", map[string]string{"app.yaml": "kind: Application\n"})
+
+	diff, err := Compare(release, release)
+	if err != nil {
+		t.Fatalf("Compare() unexpected error: %v", err)
+	}
+	if !diff.IsEmpty() || len(diff.Images) != 0 || len(diff.Charts) != 0 {
+		t.Errorf("Expected an empty diff, got %+v", diff)
+	}
+}
@@ -0,0 +1,66 @@
+package releasediff
+
+import (
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// embeddedClusterGroup is the API group of the embedded cluster Config resource
+const embeddedClusterGroup = "embeddedcluster.replicated.com/"
+
+// EmbeddedClusterConfig is the embedded cluster Config resource a release ships. Version is
+// the embedded cluster version it pins, and Spec is the resource's full spec.
+type EmbeddedClusterConfig struct {
+	Path       string         `json:"path"`
+	APIVersion string         `json:"api_version"`
+	Version    string         `json:"version"`
+	Spec       map[string]any `json:"spec"`
+}
+
+// embeddedClusterManifest holds the fields of an embedded cluster Config resource
+type embeddedClusterManifest struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Spec       map[string]any `yaml:"spec"`
+}
+
+// FindEmbeddedClusterConfig returns the embedded cluster Config resource in a release's
+// manifests, or nil if the release does not ship one. Files that are not valid YAML are
+// skipped, as they are when collecting images.
+func FindEmbeddedClusterConfig(release *models.Release) (*EmbeddedClusterConfig, error) {
+	files, err := Files(release)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if ext := path.Ext(file.Path); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		decoder := yaml.NewDecoder(strings.NewReader(file.Content))
+		for {
+			var doc embeddedClusterManifest
+			if err := decoder.Decode(&doc); err != nil {
+				break
+			}
+			if doc.Kind != "Config" || !strings.HasPrefix(doc.APIVersion, embeddedClusterGroup) {
+				continue
+			}
+
+			config := &EmbeddedClusterConfig{Path: file.Path, APIVersion: doc.APIVersion, Spec: doc.Spec}
+			if version, ok := doc.Spec["version"].(string); ok {
+				config.Version = version
+			}
+			if config.Spec == nil {
+				config.Spec = map[string]any{}
+			}
+			return config, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,56 @@
+package releasediff
+
+import (
+	"testing"
+)
+
+func TestFindEmbeddedClusterConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantPath    string
+		wantVersion string
+	}{
+		{
+			name: "config among other manifests",
+			files: map[string]string{
+				"deployment.yaml": "kind: Deployment\nmetadata:\n  name: web\n",
+				"embedded-cluster.yaml": "apiVersion: embeddedcluster.replicated.com/v1beta1\nkind: Config\n" +
+					"spec:\n  version: 2.1.3+k8s-1.30\n  unsupportedOverrides: {}\n",
+			},
+			wantPath:    "embedded-cluster.yaml",
+			wantVersion: "2.1.3+k8s-1.30",
+		},
+		{
+			name: "kots config is not an embedded cluster config",
+			files: map[string]string{
+				"config.yaml": "apiVersion: kots.io/v1beta1\nkind: Config\nspec:\n  groups: []\n",
+			},
+		},
+		{
+			name:  "no manifests",
+			files: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := FindEmbeddedClusterConfig(releaseWithFiles(t, 1, "1.0.0", tt.files))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.wantPath == "" {
+				if config != nil {
+					t.Errorf("Expected no embedded cluster config, got %+v", config)
+				}
+				return
+			}
+			if config == nil || config.Path != tt.wantPath || config.Version != tt.wantVersion {
+				t.Fatalf("Expected config at %s pinning %s, got %+v", tt.wantPath, tt.wantVersion, config)
+			}
+			if _, ok := config.Spec["unsupportedOverrides"]; !ok {
+				t.Errorf("Expected the full spec, got %+v", config.Spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Package releasediff compares the manifests of two releases of an application, reporting
+// the files, container images, and Helm chart versions that changed between them. It also lists
+// the images a release references and finds the embedded cluster config it ships.
+package releasediff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// File is a manifest file in a release
+type File struct {
+	Path    string
+	Content string
+}
+
+// specFile is a node of the file tree the Vendor Portal returns as a release's spec
+type specFile struct {
+	Name     string     `json:"name"`
+	Path     string     `json:"path"`
+	Content  string     `json:"content"`
+	Children []specFile `json:"children"`
+}
+
+// manifest holds the identifying fields of a Kubernetes-style YAML document
+type manifest struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+}
+
+// Files returns a release's manifest files sorted by path. The release config is either the
+// Vendor Portal's JSON file tree or a multi-document YAML stream; YAML documents have no paths,
+// so they are named by kind and metadata name.
+func Files(release *models.Release) ([]File, error) {
+	config := strings.TrimSpace(release.Config)
+	if config == "" {
+		return []File{}, nil
+	}
+
+	var files []File
+	var err error
+	if strings.HasPrefix(config, "[") {
+		files, err = treeFiles(config)
+	} else {
+		files, err = documentFiles(config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests of release %d: %w", release.Sequence, err)
+	}
+
+	slices.SortFunc(files, func(a, b File) int { return strings.Compare(a.Path, b.Path) })
+	return files, nil
+}
+
+// treeFiles flattens a JSON file tree into its files, skipping directories
+func treeFiles(config string) ([]File, error) {
+	var tree []specFile
+	if err := json.Unmarshal([]byte(config), &tree); err != nil {
+		return nil, err
+	}
+
+	var files []File
+	var walk func(nodes []specFile, dir string)
+	walk = func(nodes []specFile, dir string) {
+		for _, node := range nodes {
+			filePath := node.Path
+			if filePath == "" {
+				filePath = path.Join(dir, node.Name)
+			}
+			if len(node.Children) > 0 {
+				walk(node.Children, filePath)
+				continue
+			}
+			files = append(files, File{Path: strings.TrimPrefix(filePath, "/"), Content: node.Content})
+		}
+	}
+	walk(tree, "")
+	return files, nil
+}
+
+// documentFiles splits a multi-document YAML stream into one file per document
+func documentFiles(config string) ([]File, error) {
+	var files []File
+	seen := make(map[string]int)
+
+	decoder := yaml.NewDecoder(strings.NewReader(config))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if node.Kind == 0 {
+			continue
+		}
+
+		var doc manifest
+		if err := node.Decode(&doc); err != nil {
+			return nil, err
+		}
+		content, err := yaml.Marshal(&node)
+		if err != nil {
+			return nil, err
+		}
+
+		name := documentName(doc, len(files))
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, seen[name])
+		}
+		files = append(files, File{Path: name + ".yaml", Content: string(content)})
+	}
+	return files, nil
+}
+
+// documentName names a YAML document after its kind and metadata name, falling back to
+// its position in the stream
+func documentName(doc manifest, index int) string {
+	switch {
+	case doc.Kind != "" && doc.Metadata.Name != "":
+		return strings.ToLower(doc.Kind) + "/" + doc.Metadata.Name
+	case doc.Kind != "":
+		return strings.ToLower(doc.Kind)
+	default:
+		return fmt.Sprintf("document-%d", index+1)
+	}
+}
@@ -0,0 +1,62 @@
+package releasediff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		wantPaths   []string
+		errContains string
+	}{
+		{name: "no manifests", wantPaths: []string{}},
+		{
+			name: "file tree",
+			config: `[
+				{"name": "manifests", "path": "manifests", "children": [
+					{"name": "deployment.yaml", "path": "manifests/deployment.yaml", "content": "kind: Deployment"},
+					{"name": "service.yaml", "content": "kind: Service"}
+				]},
+				{"name": "app.yaml", "path": "/app.yaml", "content": "kind: Application"}
+			]`,
+			wantPaths: []string{"app.yaml", "manifests/deployment.yaml", "manifests/service.yaml"},
+		},
+		{
+			name: "YAML documents",
+			config: "kind: Deployment\nmetadata:\n  name: web\n---\nkind: Service\nmetadata:\n  name: web\n" +
+				"---\nkind: Service\nmetadata:\n  name: web\n---\nkind: Config\n---\nfoo: bar\n",
+			wantPaths: []string{
+				"config.yaml", "deployment/web.yaml", "document-5.yaml", "service/web-2.yaml", "service/web.yaml",
+			},
+		},
+		{name: "malformed file tree", config: `[{"name": `, errContains: "failed to read manifests of release 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Files(&models.Release{Sequence: 3, Config: tt.config})
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Files() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Files() unexpected error: %v", err)
+			}
+
+			paths := []string{}
+			for _, file := range files {
+				paths = append(paths, file.Path)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.wantPaths, ",") {
+				t.Errorf("Files() paths = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
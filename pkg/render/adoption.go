@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/adoption"
+)
+
+// VersionDriftReport renders a one-line summary of a version drift report, e.g. "1 of 2
+// installed customers (50.0%) run a release more than 0 sequences behind their channel's
+// latest; Globex is furthest behind at 2 sequences, last checked in 01/20/2025"
+func VersionDriftReport(f *Formatter, drift *adoption.VersionDrift) string {
+	report := fmt.Sprintf("%s of %s installed customers", f.Count(int64(drift.CustomersDrifting)),
+		f.Count(int64(drift.CustomersInstalled)))
+	if drift.CustomersInstalled > 0 {
+		report += fmt.Sprintf(" (%s)", f.Percent(
+			float64(drift.CustomersDrifting)/float64(drift.CustomersInstalled), percentDecimals))
+	}
+	report += fmt.Sprintf(" run a release more than %s behind their channel's latest",
+		sequences(f, drift.MaxLag))
+
+	if len(drift.Customers) == 0 {
+		return report
+	}
+
+	furthest := &drift.Customers[0]
+	report += fmt.Sprintf("; %s is furthest behind at %s", furthest.CustomerName,
+		sequences(f, furthest.SequencesBehind))
+
+	var lastCheckin *time.Time
+	for i := range furthest.Instances {
+		if at := furthest.Instances[i].LastCheckinAt; at != nil && (lastCheckin == nil || at.After(*lastCheckin)) {
+			lastCheckin = at
+		}
+	}
+	if lastCheckin != nil {
+		report += ", last checked in " + f.Date(*lastCheckin)
+	}
+	return report
+}
+
+// AdoptionTimeseriesReport renders a one-line summary of the last day of a channel's adoption
+// time series, e.g. "Stable on 01/11/2025: 1,204 instances, 62.5% on 1.3.0, the newest release
+// running"
+func AdoptionTimeseriesReport(f *Formatter, series *adoption.AdoptionTimeseries) string {
+	if len(series.Days) == 0 {
+		return series.ChannelName + ": no days reported"
+	}
+
+	last := &series.Days[len(series.Days)-1]
+	date := last.Date
+	if day, err := time.Parse(time.DateOnly, last.Date); err == nil {
+		date = f.Date(day)
+	}
+
+	report := fmt.Sprintf("%s on %s: ", series.ChannelName, date)
+	if last.Instances == 0 || len(last.Versions) == 0 {
+		return report + "no instances"
+	}
+
+	newest := &last.Versions[0]
+	release := newest.VersionLabel
+	if release == "" {
+		release = fmt.Sprintf("sequence %d", newest.ReleaseSequence)
+	}
+	return report + fmt.Sprintf("%s instances, %s on %s, the newest release running",
+		f.Count(int64(last.Instances)),
+		f.Percent(float64(newest.Instances)/float64(last.Instances), percentDecimals), release)
+}
+
+// sequences formats a number of release sequences, e.g. "1 sequence" or "1,200 sequences"
+func sequences(f *Formatter, n int64) string {
+	if n == 1 {
+		return "1 sequence"
+	}
+	return f.Count(n) + " sequences"
+}
@@ -0,0 +1,117 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/adoption"
+)
+
+func TestVersionDriftReport(t *testing.T) {
+	checkin := time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC)
+	earlier := time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC)
+	drift := &adoption.VersionDrift{
+		CustomersInstalled: 1500,
+		CustomersDrifting:  375,
+		Customers: []adoption.DriftingCustomer{{
+			CustomerName:    "Globex",
+			SequencesBehind: 1200,
+			Instances: []adoption.DriftingInstance{
+				{InstanceID: "instance-1", LastCheckinAt: &earlier},
+				{InstanceID: "instance-2", LastCheckinAt: &checkin},
+				{InstanceID: "instance-3"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		locale   string
+		drift    *adoption.VersionDrift
+		expected string
+	}{
+		{
+			name:   "english",
+			locale: "en-US",
+			drift:  drift,
+			expected: "375 of 1,500 installed customers (25.0%) run a release more than 0 sequences behind " +
+				"their channel's latest; Globex is furthest behind at 1,200 sequences, last checked in 01/20/2025",
+		},
+		{
+			name:   "german",
+			locale: "de-DE",
+			drift:  drift,
+			expected: "375 of 1.500 installed customers (25,0\u00a0%) run a release more than 0 sequences behind " +
+				"their channel's latest; Globex is furthest behind at 1.200 sequences, last checked in 20.01.2025",
+		},
+		{
+			name:     "no installed customers",
+			locale:   "en-US",
+			drift:    &adoption.VersionDrift{MaxLag: 2},
+			expected: "0 of 0 installed customers run a release more than 2 sequences behind their channel's latest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VersionDriftReport(mustFormatter(t, tt.locale), tt.drift); got != tt.expected {
+				t.Errorf("VersionDriftReport() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAdoptionTimeseriesReport(t *testing.T) {
+	series := &adoption.AdoptionTimeseries{
+		ChannelName: "Stable",
+		Days: []adoption.AdoptionDay{
+			{Date: "2025-01-10", Instances: 1},
+			{Date: "2025-01-11", Instances: 1600, Versions: []adoption.DayAdoption{
+				{ReleaseSequence: 4, VersionLabel: "1.3.0", Instances: 1000},
+				{ReleaseSequence: 3, Instances: 600},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		locale   string
+		series   *adoption.AdoptionTimeseries
+		expected string
+	}{
+		{
+			name:     "english",
+			locale:   "en-US",
+			series:   series,
+			expected: "Stable on 01/11/2025: 1,600 instances, 62.5% on 1.3.0, the newest release running",
+		},
+		{
+			name:     "french",
+			locale:   "fr-FR",
+			series:   series,
+			expected: "Stable on 11/01/2025: 1\u202f600 instances, 62,5\u202f% on 1.3.0, the newest release running",
+		},
+		{
+			name:   "no instances",
+			locale: "en-US",
+			series: &adoption.AdoptionTimeseries{
+				ChannelName: "Beta", Days: []adoption.AdoptionDay{{Date: "2025-01-11"}},
+			},
+			expected: "Beta on 01/11/2025: no instances",
+		},
+		{
+			name:     "no days",
+			locale:   "en-US",
+			series:   &adoption.AdoptionTimeseries{ChannelName: "Beta"},
+			expected: "Beta: no days reported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AdoptionTimeseriesReport(mustFormatter(t, tt.locale), tt.series); got != tt.expected {
+				t.Errorf("AdoptionTimeseriesReport() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
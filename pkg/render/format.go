@@ -0,0 +1,132 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatting constants
+const (
+	groupSize      = 3
+	percentScale   = 100
+	secondDecimals = 1
+)
+
+// Formatter writes counts, percentages, durations, and dates for a specific locale, and composes
+// report text in a specific language
+type Formatter struct {
+	locale   Locale
+	language Language
+}
+
+// NewFormatter creates a Formatter for the given locale that composes text in English
+func NewFormatter(locale Locale) *Formatter {
+	return &Formatter{locale: locale, language: english}
+}
+
+// WithLanguage returns a copy of the formatter that composes text in the given language
+func (f *Formatter) WithLanguage(language Language) *Formatter {
+	localized := *f
+	localized.language = language
+	return &localized
+}
+
+// Locale returns the locale used by the formatter
+func (f *Formatter) Locale() Locale {
+	return f.locale
+}
+
+// Language returns the language the formatter composes text in
+func (f *Formatter) Language() Language {
+	return f.language
+}
+
+// Count formats an integer with the locale's digit grouping (e.g. 12,345 or 12.345)
+func (f *Formatter) Count(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	return sign + f.group(digits)
+}
+
+// Number formats a value with a fixed number of decimal places using the locale's separators
+func (f *Formatter) Number(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	formatted := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	integer, fraction, hasFraction := strings.Cut(formatted, ".")
+
+	sign := ""
+	if v < 0 && strings.Trim(formatted, "0.") != "" {
+		sign = "-"
+	}
+
+	result := sign + f.group(integer)
+	if hasFraction {
+		result += f.locale.Decimal + fraction
+	}
+	return result
+}
+
+// Percent formats a ratio (0.125 for 12.5%) as a percentage with the given decimal places
+func (f *Formatter) Percent(ratio float64, decimals int) string {
+	return f.Number(ratio*percentScale, decimals) + f.locale.PercentSpacer + "%"
+}
+
+// Duration formats a duration using the largest meaningful units (e.g. 2h 5m, 45m 10s, 1.5s)
+func (f *Formatter) Duration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	if d < time.Minute {
+		return sign + f.Number(d.Seconds(), secondDecimals) + "s"
+	}
+
+	hours := int64(d / time.Hour)
+	minutes := int64((d % time.Hour) / time.Minute)
+	seconds := int64((d % time.Minute) / time.Second)
+
+	if hours > 0 {
+		return fmt.Sprintf("%s%sh %dm", sign, f.Count(hours), minutes)
+	}
+	return fmt.Sprintf("%s%dm %ds", sign, minutes, seconds)
+}
+
+// Date formats the calendar date of t in the locale's short date layout (e.g. 01/20/2025 or
+// 20.01.2025), falling back to ISO 8601 for a locale without one
+func (f *Formatter) Date(t time.Time) string {
+	layout := f.locale.DateLayout
+	if layout == "" {
+		layout = time.DateOnly
+	}
+	return t.Format(layout)
+}
+
+// group inserts the locale's group separator into a string of digits
+func (f *Formatter) group(digits string) string {
+	if len(digits) <= groupSize {
+		return digits
+	}
+
+	var b strings.Builder
+	lead := len(digits) % groupSize
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += groupSize {
+		if b.Len() > 0 {
+			b.WriteString(f.locale.Group)
+		}
+		b.WriteString(digits[i : i+groupSize])
+	}
+	return b.String()
+}
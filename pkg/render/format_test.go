@@ -0,0 +1,137 @@
+package render
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func mustFormatter(t *testing.T, tag string) *Formatter {
+	t.Helper()
+
+	locale, err := ParseLocale(tag)
+	if err != nil {
+		t.Fatalf("Failed to parse locale '%s': %v", tag, err)
+	}
+	return NewFormatter(locale)
+}
+
+func TestFormatter_Count(t *testing.T) {
+	tests := []struct {
+		locale   string
+		value    int64
+		expected string
+	}{
+		{"en-US", 0, "0"},
+		{"en-US", 999, "999"},
+		{"en-US", 1234, "1,234"},
+		{"en-US", 1234567, "1,234,567"},
+		{"en-US", -1234567, "-1,234,567"},
+		{"de-DE", 1234567, "1.234.567"},
+		{"fr-FR", 1234567, "1\u202f234\u202f567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.expected, func(t *testing.T) {
+			if got := mustFormatter(t, tt.locale).Count(tt.value); got != tt.expected {
+				t.Errorf("Count(%d) = %q, want %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatter_Number(t *testing.T) {
+	tests := []struct {
+		locale   string
+		value    float64
+		decimals int
+		expected string
+	}{
+		{"en-US", 1234.5, 2, "1,234.50"},
+		{"de-DE", 1234.5, 2, "1.234,50"},
+		{"fr-FR", 1234.5, 1, "1\u202f234,5"},
+		{"en-US", 42, 0, "42"},
+		{"en-US", -0.001, 1, "0.0"},
+		{"en-US", -12.25, 1, "-12.2"},
+		{"en-US", math.Inf(1), 2, "+Inf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.expected, func(t *testing.T) {
+			if got := mustFormatter(t, tt.locale).Number(tt.value, tt.decimals); got != tt.expected {
+				t.Errorf("Number(%v, %d) = %q, want %q", tt.value, tt.decimals, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatter_Percent(t *testing.T) {
+	tests := []struct {
+		locale   string
+		ratio    float64
+		decimals int
+		expected string
+	}{
+		{"en-US", 0.125, 1, "12.5%"},
+		{"en-US", 1, 0, "100%"},
+		{"de-DE", 0.125, 1, "12,5\u00a0%"},
+		{"fr-FR", 0.5, 0, "50\u202f%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.expected, func(t *testing.T) {
+			if got := mustFormatter(t, tt.locale).Percent(tt.ratio, tt.decimals); got != tt.expected {
+				t.Errorf("Percent(%v, %d) = %q, want %q", tt.ratio, tt.decimals, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatter_Duration(t *testing.T) {
+	tests := []struct {
+		locale   string
+		value    time.Duration
+		expected string
+	}{
+		{"en-US", 1500 * time.Millisecond, "1.5s"},
+		{"de-DE", 1500 * time.Millisecond, "1,5s"},
+		{"en-US", 45*time.Minute + 10*time.Second, "45m 10s"},
+		{"en-US", 2*time.Hour + 5*time.Minute, "2h 5m"},
+		{"en-US", 1500 * time.Hour, "1,500h 0m"},
+		{"en-US", -90 * time.Second, "-1m 30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.expected, func(t *testing.T) {
+			if got := mustFormatter(t, tt.locale).Duration(tt.value); got != tt.expected {
+				t.Errorf("Duration(%v) = %q, want %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatter_Date(t *testing.T) {
+	date := time.Date(2025, 1, 20, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{"en-US", "01/20/2025"},
+		{"en-GB", "20/01/2025"},
+		{"de-DE", "20.01.2025"},
+		{"ja-JP", "2025/01/20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"_"+tt.expected, func(t *testing.T) {
+			if got := mustFormatter(t, tt.locale).Date(date); got != tt.expected {
+				t.Errorf("Date(%v) = %q, want %q", date, got, tt.expected)
+			}
+		})
+	}
+
+	if got := NewFormatter(Locale{Tag: "xx"}).Date(date); got != "2025-01-20" {
+		t.Errorf("Expected a locale without a date layout to use ISO 8601, got %q", got)
+	}
+}
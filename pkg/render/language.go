@@ -0,0 +1,144 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the language of composed text when none is requested
+const DefaultLanguage = "en"
+
+// Message keys for the human-readable text composed into support bundle summaries, the only
+// text that is translated. Field names and data values such as statuses are never
+// translated, so only these phrases vary by language.
+const (
+	// MessageSupportBundleSummary takes the bundle ID, status, analyzer count, and severity counts
+	MessageSupportBundleSummary = "support_bundle.summary"
+	// MessageSupportBundleAttention takes the percentage of analyzers that need attention
+	MessageSupportBundleAttention = "support_bundle.attention"
+	// MessageSeverityCount takes a count and a severity name
+	MessageSeverityCount = "severity.count"
+	// MessageSeverityPrefix is followed by an analyzer severity to name it
+	MessageSeverityPrefix = "severity."
+)
+
+// Language holds the phrases composed text is written with. Phrases a language lacks fall
+// back to English.
+type Language struct {
+	Tag           string
+	ListSeparator string
+	messages      map[string]string
+}
+
+// english is the default language and the fallback for missing phrases
+var english = Language{Tag: "en", ListSeparator: ", ", messages: map[string]string{
+	MessageSupportBundleSummary:   "Support bundle %s (%s): %s analyzers, %s",
+	MessageSupportBundleAttention: " (%s need attention)",
+	MessageSeverityCount:          "%s %s",
+	"severity.error":              "error",
+	"severity.warn":               "warn",
+	"severity.info":               "info",
+	"severity.pass":               "pass",
+}}
+
+// languages contains the supported languages keyed by lowercase tag, one for each language
+// of the supported locales
+var languages = map[string]Language{
+	"en": english,
+	"de": {Tag: "de", ListSeparator: ", ", messages: map[string]string{
+		MessageSupportBundleSummary:   "Support-Bundle %s (%s): %s Analysatoren, %s",
+		MessageSupportBundleAttention: " (%s erfordern Aufmerksamkeit)",
+		MessageSeverityCount:          "%s %s",
+		"severity.error":              "Fehler",
+		"severity.warn":               "Warnungen",
+		"severity.info":               "Hinweise",
+		"severity.pass":               "bestanden",
+	}},
+	"es": {Tag: "es", ListSeparator: ", ", messages: map[string]string{
+		MessageSupportBundleSummary:   "Paquete de soporte %s (%s): %s analizadores, %s",
+		MessageSupportBundleAttention: " (%s requieren atención)",
+		MessageSeverityCount:          "%s %s",
+		"severity.error":              "errores",
+		"severity.warn":               "advertencias",
+		"severity.info":               "avisos",
+		"severity.pass":               "correctos",
+	}},
+	"fr": {Tag: "fr", ListSeparator: ", ", messages: map[string]string{
+		MessageSupportBundleSummary:   "Bundle de support %s (%s) : %s analyseurs, %s",
+		MessageSupportBundleAttention: " (%s nécessitent une attention)",
+		MessageSeverityCount:          "%s %s",
+		"severity.error":              "erreurs",
+		"severity.warn":               "avertissements",
+		"severity.info":               "informations",
+		"severity.pass":               "réussis",
+	}},
+	"ja": {Tag: "ja", ListSeparator: "、", messages: map[string]string{
+		MessageSupportBundleSummary:   "サポートバンドル %s (%s): アナライザー %s 件、%s",
+		MessageSupportBundleAttention: "（%s が要対応）",
+		MessageSeverityCount:          "%[2]s %[1]s",
+		"severity.error":              "エラー",
+		"severity.warn":               "警告",
+		"severity.info":               "情報",
+		"severity.pass":               "合格",
+	}},
+	"pt": {Tag: "pt", ListSeparator: ", ", messages: map[string]string{
+		MessageSupportBundleSummary:   "Pacote de suporte %s (%s): %s analisadores, %s",
+		MessageSupportBundleAttention: " (%s precisam de atenção)",
+		MessageSeverityCount:          "%s %s",
+		"severity.error":              "erros",
+		"severity.warn":               "avisos",
+		"severity.info":               "informações",
+		"severity.pass":               "aprovados",
+	}},
+}
+
+// ParseLanguage returns the supported language for a tag such as "de", "de-DE", or "pt_BR".
+// Only the language subtag is used, so any region of a supported language is accepted.
+func ParseLanguage(tag string) (Language, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if normalized == "" {
+		return english, nil
+	}
+
+	base, _, _ := strings.Cut(normalized, "-")
+	if language, ok := languages[base]; ok {
+		return language, nil
+	}
+
+	return Language{}, fmt.Errorf("unsupported language '%s'. Supported languages are: %s",
+		tag, strings.Join(SupportedLanguages(), ", "))
+}
+
+// SupportedLanguages returns the tags of all supported languages in sorted order
+func SupportedLanguages() []string {
+	tags := make([]string, 0, len(languages))
+	for _, language := range languages {
+		tags = append(tags, language.Tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Text formats the phrase for a message key with the given arguments
+func (l Language) Text(key string, args ...any) string {
+	message, ok := l.messages[key]
+	if !ok {
+		message = english.messages[key]
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// List joins phrases with the language's list separator
+func (l Language) List(items []string) string {
+	separator := l.ListSeparator
+	if separator == "" {
+		separator = english.ListSeparator
+	}
+	return strings.Join(items, separator)
+}
+
+// String returns the language tag
+func (l Language) String() string {
+	return l.Tag
+}
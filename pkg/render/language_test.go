@@ -0,0 +1,74 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         string
+		expectedTag string
+		expectError bool
+	}{
+		{name: "language", tag: "de", expectedTag: "de"},
+		{name: "language with region", tag: "pt-BR", expectedTag: "pt"},
+		{name: "underscore separator", tag: "FR_ca", expectedTag: "fr"},
+		{name: "empty uses default", tag: "", expectedTag: DefaultLanguage},
+		{name: "unsupported language", tag: "zz", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, err := ParseLanguage(tt.tag)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for language '%s'", tt.tag)
+				} else if !strings.Contains(err.Error(), "Supported languages are") {
+					t.Errorf("Expected error to list supported languages, got '%v'", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if language.Tag != tt.expectedTag {
+				t.Errorf("ParseLanguage(%q).Tag = %q, want %q", tt.tag, language.Tag, tt.expectedTag)
+			}
+		})
+	}
+}
+
+func TestLanguagesCoverLocales(t *testing.T) {
+	for _, tag := range SupportedLocales() {
+		if _, err := ParseLanguage(tag); err != nil {
+			t.Errorf("Expected a language for locale %s: %v", tag, err)
+		}
+	}
+}
+
+func TestLanguagesTranslateEveryMessage(t *testing.T) {
+	for _, language := range languages {
+		for key := range english.messages {
+			if _, ok := language.messages[key]; !ok {
+				t.Errorf("Language %s is missing message %q", language.Tag, key)
+			}
+		}
+	}
+}
+
+func TestLanguage_Text(t *testing.T) {
+	partial := Language{Tag: "xx", messages: map[string]string{MessageSeverityCount: "%[2]s=%[1]s"}}
+
+	if got := partial.Text(MessageSeverityCount, "3", "pass"); got != "pass=3" {
+		t.Errorf("Text() = %q, want the language's phrase", got)
+	}
+	if got := partial.Text(MessageSupportBundleAttention, "10%"); got != " (10% need attention)" {
+		t.Errorf("Text() = %q, want the English fallback", got)
+	}
+	if got := partial.List([]string{"a", "b"}); got != "a, b" {
+		t.Errorf("List() = %q, want the English separator", got)
+	}
+}
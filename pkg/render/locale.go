@@ -0,0 +1,83 @@
+// Package render formats vendor data for human-readable tables and reports.
+// It keeps presentation concerns such as locale-aware number formatting and the
+// language of composed report text out of the API and MCP layers.
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale used when none is configured or requested
+const DefaultLocale = "en-US"
+
+// Locale describes how numbers and dates are written for a language and region
+type Locale struct {
+	Tag           string
+	Decimal       string
+	Group         string
+	PercentSpacer string
+	DateLayout    string
+}
+
+// locales contains the supported locales keyed by lowercase tag. Separators and date layouts
+// follow CLDR, including the non-breaking spaces used by French and German typography, with
+// four-digit years.
+var locales = map[string]Locale{
+	"en-us": {Tag: "en-US", Decimal: ".", Group: ",", DateLayout: "01/02/2006"},
+	"en-gb": {Tag: "en-GB", Decimal: ".", Group: ",", DateLayout: "02/01/2006"},
+	"de-de": {Tag: "de-DE", Decimal: ",", Group: ".", PercentSpacer: "\u00a0", DateLayout: "02.01.2006"},
+	"es-es": {Tag: "es-ES", Decimal: ",", Group: ".", PercentSpacer: "\u00a0", DateLayout: "02/01/2006"},
+	"fr-fr": {Tag: "fr-FR", Decimal: ",", Group: "\u202f", PercentSpacer: "\u202f", DateLayout: "02/01/2006"},
+	"ja-jp": {Tag: "ja-JP", Decimal: ".", Group: ",", DateLayout: "2006/01/02"},
+	"pt-br": {Tag: "pt-BR", Decimal: ",", Group: ".", DateLayout: "02/01/2006"},
+}
+
+// ParseLocale returns the supported locale for a tag such as "de-DE", "de_DE", or "de".
+// A bare language matches the first supported region for that language in tag order.
+func ParseLocale(tag string) (Locale, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if normalized == "" {
+		return locales[strings.ToLower(DefaultLocale)], nil
+	}
+
+	if locale, ok := locales[normalized]; ok {
+		return locale, nil
+	}
+
+	if !strings.Contains(normalized, "-") {
+		for _, supported := range SupportedLocales() {
+			if strings.HasPrefix(strings.ToLower(supported), normalized+"-") {
+				return locales[strings.ToLower(supported)], nil
+			}
+		}
+	}
+
+	return Locale{}, fmt.Errorf("unsupported locale '%s'. Supported locales are: %s",
+		tag, strings.Join(SupportedLocales(), ", "))
+}
+
+// ResolveLocale selects the locale for a single call: the requested tag when present,
+// otherwise the configured default
+func ResolveLocale(requested, configured string) (Locale, error) {
+	if strings.TrimSpace(requested) != "" {
+		return ParseLocale(requested)
+	}
+	return ParseLocale(configured)
+}
+
+// SupportedLocales returns the tags of all supported locales in sorted order
+func SupportedLocales() []string {
+	tags := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		tags = append(tags, locale.Tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// String returns the locale tag
+func (l Locale) String() string {
+	return l.Tag
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         string
+		expectedTag string
+		expectError bool
+	}{
+		{name: "exact tag", tag: "de-DE", expectedTag: "de-DE"},
+		{name: "lowercase tag", tag: "fr-fr", expectedTag: "fr-FR"},
+		{name: "underscore separator", tag: "pt_BR", expectedTag: "pt-BR"},
+		{name: "language only", tag: "ja", expectedTag: "ja-JP"},
+		{name: "language with several regions", tag: "en", expectedTag: "en-GB"},
+		{name: "empty uses default", tag: "", expectedTag: DefaultLocale},
+		{name: "unsupported locale", tag: "xx-YY", expectError: true},
+		{name: "unsupported language", tag: "zz", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, err := ParseLocale(tt.tag)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for locale '%s'", tt.tag)
+				} else if !strings.Contains(err.Error(), "Supported locales are") {
+					t.Errorf("Expected error to list supported locales, got '%v'", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if locale.Tag != tt.expectedTag {
+				t.Errorf("Expected locale '%s', got '%s'", tt.expectedTag, locale.Tag)
+			}
+		})
+	}
+}
+
+func TestResolveLocale(t *testing.T) {
+	tests := []struct {
+		name        string
+		requested   string
+		configured  string
+		expectedTag string
+		expectError bool
+	}{
+		{name: "requested overrides configured", requested: "de-DE", configured: "en-US", expectedTag: "de-DE"},
+		{name: "configured when not requested", requested: "", configured: "fr-FR", expectedTag: "fr-FR"},
+		{name: "default when neither is set", expectedTag: DefaultLocale},
+		{name: "invalid requested locale", requested: "klingon", configured: "en-US", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, err := ResolveLocale(tt.requested, tt.configured)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ResolveLocale() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !tt.expectError && locale.Tag != tt.expectedTag {
+				t.Errorf("Expected locale '%s', got '%s'", tt.expectedTag, locale.Tag)
+			}
+		})
+	}
+}
+
+func TestSupportedLocales(t *testing.T) {
+	supported := SupportedLocales()
+
+	if len(supported) != len(locales) {
+		t.Errorf("Expected %d supported locales, got %d", len(locales), len(supported))
+	}
+	for i := 1; i < len(supported); i++ {
+		if supported[i-1] > supported[i] {
+			t.Errorf("Expected supported locales to be sorted, got %v", supported)
+			break
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package render
+
+import "github.com/crdant/replicated-mcp-server/pkg/models"
+
+// percentDecimals is the precision used for percentages in reports
+const percentDecimals = 1
+
+// SupportBundleReport renders a one-line summary of a support bundle's analyzer results in the
+// formatter's language, e.g. "Support bundle bundle-1 (analyzed): 12 analyzers, 1 error, 2 warn,
+// 0 info, 9 pass (25.0% need attention)". The bundle ID and status are not translated.
+func SupportBundleReport(f *Formatter, bundle *models.SupportBundle) string {
+	summary := bundle.AnalysisSummary()
+	total := int64(len(bundle.Analysis))
+	language := f.Language()
+
+	severities := []string{
+		models.AnalyzerSeverityError, models.AnalyzerSeverityWarn,
+		models.AnalyzerSeverityInfo, models.AnalyzerSeverityPass,
+	}
+	counts := make([]string, len(severities))
+	for i, severity := range severities {
+		counts[i] = language.Text(MessageSeverityCount,
+			f.Count(int64(summary[severity])), language.Text(MessageSeverityPrefix+severity))
+	}
+
+	report := language.Text(MessageSupportBundleSummary,
+		bundle.ID, bundle.Status, f.Count(total), language.List(counts))
+
+	if total > 0 {
+		attention := summary[models.AnalyzerSeverityError] + summary[models.AnalyzerSeverityWarn]
+		report += language.Text(MessageSupportBundleAttention,
+			f.Percent(float64(attention)/float64(total), percentDecimals))
+	}
+
+	return report
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestSupportBundleReport(t *testing.T) {
+	bundle := &models.SupportBundle{
+		ID:     "bundle-1",
+		Status: models.SupportBundleStatusAnalyzed,
+		Analysis: []models.AnalyzerResult{
+			{Name: "storage-class", Severity: models.AnalyzerSeverityError},
+			{Name: "k8s-version", Severity: models.AnalyzerSeverityWarn},
+			{Name: "node-count", Severity: models.AnalyzerSeverityPass},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		locale   string
+		language string
+		bundle   *models.SupportBundle
+		expected string
+	}{
+		{
+			name:   "english",
+			locale: "en-US",
+			bundle: bundle,
+			expected: "Support bundle bundle-1 (analyzed): 3 analyzers, 1 error, 1 warn, 0 info, 1 pass " +
+				"(66.7% need attention)",
+		},
+		{
+			name:   "german",
+			locale: "de-DE",
+			bundle: bundle,
+			expected: "Support bundle bundle-1 (analyzed): 3 analyzers, 1 error, 1 warn, 0 info, 1 pass " +
+				"(66,7\u00a0% need attention)",
+		},
+		{
+			name:     "german text",
+			locale:   "de-DE",
+			language: "de",
+			bundle:   bundle,
+			expected: "Support-Bundle bundle-1 (analyzed): 3 Analysatoren, 1 Fehler, 1 Warnungen, 0 Hinweise, " +
+				"1 bestanden (66,7\u00a0% erfordern Aufmerksamkeit)",
+		},
+		{
+			name:     "japanese text",
+			locale:   "ja-JP",
+			language: "ja",
+			bundle:   bundle,
+			expected: "サポートバンドル bundle-1 (analyzed): アナライザー 3 件、" +
+				"エラー 1、警告 1、情報 0、合格 1（66.7% が要対応）",
+		},
+		{
+			name:     "no analysis",
+			locale:   "en-US",
+			bundle:   &models.SupportBundle{ID: "bundle-2", Status: models.SupportBundleStatusUploaded},
+			expected: "Support bundle bundle-2 (uploaded): 0 analyzers, 0 error, 0 warn, 0 info, 0 pass",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := mustFormatter(t, tt.locale)
+			if tt.language != "" {
+				language, err := ParseLanguage(tt.language)
+				if err != nil {
+					t.Fatalf("ParseLanguage() error = %v", err)
+				}
+				formatter = formatter.WithLanguage(language)
+			}
+			if got := SupportBundleReport(formatter, tt.bundle); got != tt.expected {
+				t.Errorf("SupportBundleReport() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package search ranks documents against free-text queries using an in-memory inverted
+// index. Documents are made of weighted fields, so a match in a name can count for more
+// than a match in a description. Scores combine how rare each query term is across the
+// indexed documents (inverse document frequency), how often and in which fields it
+// occurs, and how many of the query's terms a document matches. The last query term also
+// matches as a prefix, so partial words typed by an agent still find their documents.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Scoring constants
+const (
+	// saturation dampens repeated occurrences of a term: a term's weight w counts as w/(w+saturation)
+	saturation = 1.0
+	// prefixFactor scales matches on terms that only start with a query term
+	prefixFactor = 0.5
+	// minPrefixLength is the shortest query term matched as a prefix
+	minPrefixLength = 2
+)
+
+// Field is one searchable piece of a document. Weight scales matches in the field; fields
+// with a weight of zero or less are not indexed.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Document is an entity to index, identified by ID
+type Document struct {
+	ID     string
+	Fields []Field
+}
+
+// Hit is a document matching a query and its relevance score
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// posting records how strongly a term occurs in one document
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// Index is an immutable inverted index over a set of documents. It is safe for concurrent
+// searches.
+type Index struct {
+	ids      []string
+	postings map[string][]posting
+	terms    []string
+}
+
+// New indexes the documents. Documents are ranked in the given order when their scores tie.
+func New(documents []Document) *Index {
+	index := &Index{
+		ids:      make([]string, len(documents)),
+		postings: make(map[string][]posting),
+	}
+
+	for doc, document := range documents {
+		index.ids[doc] = document.ID
+
+		weights := make(map[string]float64)
+		for _, field := range document.Fields {
+			if field.Weight <= 0 {
+				continue
+			}
+			for _, term := range Tokenize(field.Text) {
+				weights[term] += field.Weight
+			}
+		}
+		for term, weight := range weights {
+			index.postings[term] = append(index.postings[term], posting{doc: doc, weight: weight})
+		}
+	}
+
+	index.terms = make([]string, 0, len(index.postings))
+	for term := range index.postings {
+		index.terms = append(index.terms, term)
+	}
+	sort.Strings(index.terms)
+
+	return index
+}
+
+// Len returns the number of indexed documents
+func (i *Index) Len() int {
+	return len(i.ids)
+}
+
+// Search returns the documents matching at least one query term, most relevant first
+func (i *Index) Search(query string) []Hit {
+	queryTerms := uniqueTerms(Tokenize(query))
+	if len(queryTerms) == 0 || len(i.ids) == 0 {
+		return []Hit{}
+	}
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for n, queryTerm := range queryTerms {
+		// A document's score for a query term is its best match among the index terms
+		// the query term reaches
+		best := make(map[int]float64)
+		i.score(queryTerm, 1, best)
+		if n == len(queryTerms)-1 && len(queryTerm) >= minPrefixLength {
+			for _, term := range i.prefixed(queryTerm) {
+				i.score(term, prefixFactor, best)
+			}
+		}
+
+		for doc, score := range best {
+			scores[doc] += score
+			matched[doc]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	docs := make([]int, 0, len(scores))
+	for doc := range scores {
+		docs = append(docs, doc)
+	}
+	sort.Ints(docs)
+	for _, doc := range docs {
+		coverage := float64(matched[doc]) / float64(len(queryTerms))
+		hits = append(hits, Hit{ID: i.ids[doc], Score: scores[doc] * coverage})
+	}
+	sort.SliceStable(hits, func(a, b int) bool {
+		return hits[a].Score > hits[b].Score
+	})
+
+	return hits
+}
+
+// score records, for each document containing term, the term's contribution scaled by
+// factor when it beats the document's best so far
+func (i *Index) score(term string, factor float64, best map[int]float64) {
+	postings := i.postings[term]
+	if len(postings) == 0 {
+		return
+	}
+
+	total := float64(len(i.ids))
+	frequency := float64(len(postings))
+	idf := math.Log(1 + (total-frequency+0.5)/(frequency+0.5))
+
+	for _, p := range postings {
+		score := factor * idf * (p.weight / (p.weight + saturation))
+		if score > best[p.doc] {
+			best[p.doc] = score
+		}
+	}
+}
+
+// prefixed returns the index terms that start with prefix, other than prefix itself
+func (i *Index) prefixed(prefix string) []string {
+	start := sort.SearchStrings(i.terms, prefix)
+	var terms []string
+	for _, term := range i.terms[start:] {
+		if !strings.HasPrefix(term, prefix) {
+			break
+		}
+		if term != prefix {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// Tokenize splits text into terms at every character that is not a letter or digit, folding
+// case and diacritics so "Café" and "cafe" are the same term
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(models.FoldText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// uniqueTerms drops repeated terms, keeping the first occurrence of each
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,122 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+// testDocuments returns customers indexed by name (weight 3) and email (weight 1)
+func testDocuments() []Document {
+	customer := func(id, name, email string) Document {
+		return Document{ID: id, Fields: []Field{{Text: name, Weight: 3}, {Text: email, Weight: 1}}}
+	}
+	return []Document{
+		customer("customer-1", "Acme Corporation", "ops@acme.example"),
+		customer("customer-2", "Globex", "it@globex.example"),
+		customer("customer-3", "Initech", "billing@acme.example"),
+		customer("customer-4", "Acme Labs", "labs@acmelabs.example"),
+		customer("customer-5", "Umbrella", "security@umbrella.example"),
+	}
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := []string{}
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := New(testDocuments())
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "name matches rank above email matches",
+			query:    "acme",
+			expected: []string{"customer-1", "customer-4", "customer-3"},
+		},
+		{
+			name:     "documents matching every term rank first",
+			query:    "acme labs",
+			expected: []string{"customer-4", "customer-1", "customer-3"},
+		},
+		{name: "case and punctuation are ignored", query: "GLOBEX!", expected: []string{"customer-2"}},
+		{name: "last term matches as a prefix", query: "umbr", expected: []string{"customer-5"}},
+		{name: "prefix of a later word", query: "acmel", expected: []string{"customer-4"}},
+		{name: "single letters are not prefixes", query: "u", expected: []string{}},
+		{name: "no matches", query: "hooli", expected: []string{}},
+		{name: "empty query", query: "  ", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := index.Search(tt.query)
+			if got := hitIDs(hits); !slices.Equal(got, tt.expected) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.expected)
+			}
+			for i := 1; i < len(hits); i++ {
+				if hits[i].Score > hits[i-1].Score {
+					t.Errorf("Expected hits in descending score order, got %v", hits)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_SearchPrefersExactMatches(t *testing.T) {
+	index := New([]Document{
+		{ID: "prefix", Fields: []Field{{Text: "stable-candidate", Weight: 1}}},
+		{ID: "exact", Fields: []Field{{Text: "stab", Weight: 1}}},
+	})
+
+	hits := index.Search("stab")
+	if got := hitIDs(hits); !slices.Equal(got, []string{"exact", "prefix"}) {
+		t.Errorf("Expected the exact match first, got %v", hits)
+	}
+}
+
+func TestIndex_SearchTiesKeepDocumentOrder(t *testing.T) {
+	index := New([]Document{
+		{ID: "first", Fields: []Field{{Text: "beta", Weight: 1}}},
+		{ID: "second", Fields: []Field{{Text: "beta", Weight: 1}}},
+		{ID: "unindexed", Fields: []Field{{Text: "beta", Weight: 0}}},
+	})
+
+	if got := hitIDs(index.Search("beta")); !slices.Equal(got, []string{"first", "second"}) {
+		t.Errorf("Expected tied documents in index order, got %v", got)
+	}
+	if index.Len() != 3 {
+		t.Errorf("Expected 3 documents, got %d", index.Len())
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{text: "Acme Corp", expected: []string{"acme", "corp"}},
+		{text: "ops@acme.example", expected: []string{"ops", "acme", "example"}},
+		{text: "v1.2.3-beta", expected: []string{"v1", "2", "3", "beta"}},
+		{text: "Zürich GmbH", expected: []string{"zurich", "gmbh"}},
+		{text: "Cafe\u0301 Corp", expected: []string{"cafe", "corp"}},
+		{text: " -- ", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := Tokenize(tt.text)
+			if len(got) == 0 && len(tt.expected) == 0 {
+				return
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Tokenize(%q) = %v, want %v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
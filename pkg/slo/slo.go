@@ -0,0 +1,275 @@
+// Package slo tracks the Vendor Portal API against service level objectives for availability
+// and latency. Requests are counted in one-minute buckets over the last hour, and the rate at
+// which each objective's error budget is being spent, its burn rate, is reported over a short
+// and a long window. A burn rate of 1 spends the budget exactly as fast as the objective
+// allows; a sustained higher rate is logged as a warning, so operators can tell a slow or
+// failing portal apart from a problem in their own agent.
+package slo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// Default objectives
+const (
+	DefaultAvailability  = 0.99
+	DefaultLatency       = 2 * time.Second
+	DefaultLatencyTarget = 0.95
+)
+
+// Windows burn rates are reported over. Requests are kept only as long as the long window.
+const (
+	ShortWindow = 5 * time.Minute
+	LongWindow  = time.Hour
+
+	shortWindowLabel = "5m"
+	longWindowLabel  = "1h"
+
+	bucketWidth = time.Minute
+	bucketCount = int(LongWindow / bucketWidth)
+)
+
+// Burn rate warnings are logged when both windows burn at least WarningBurnRate, the short
+// window has seen at least minWarningRequests requests, and no warning was logged for the
+// objective within warningInterval
+const (
+	WarningBurnRate    = 2.0
+	minWarningRequests = 10
+	warningInterval    = 5 * time.Minute
+)
+
+// States summarizing the objectives over the long window
+const (
+	StateNoData   = "no_data"
+	StateHealthy  = "healthy"
+	StateDegraded = "degraded"
+	StateBurning  = "burning"
+)
+
+// Objectives are the targets the Vendor Portal API is measured against. Availability is the
+// fraction of requests that should succeed, and LatencyTarget the fraction that should
+// complete within Latency.
+type Objectives struct {
+	Availability  float64
+	Latency       time.Duration
+	LatencyTarget float64
+}
+
+// DefaultObjectives returns the objectives used when none are configured
+func DefaultObjectives() Objectives {
+	return Objectives{
+		Availability:  DefaultAvailability,
+		Latency:       DefaultLatency,
+		LatencyTarget: DefaultLatencyTarget,
+	}
+}
+
+// bucket counts the requests that started in one minute
+type bucket struct {
+	start    time.Time
+	requests uint64
+	failures uint64
+	slow     uint64
+}
+
+// Tracker records Vendor Portal API requests against the objectives. It implements
+// api.RequestObserver and is safe for concurrent use.
+type Tracker struct {
+	logger logging.Logger
+	now    func() time.Time
+
+	mu         sync.Mutex
+	objectives Objectives
+	buckets    [bucketCount]bucket
+	warnedAt   map[string]time.Time
+}
+
+// New creates a Tracker measuring against the given objectives
+func New(objectives Objectives, logger logging.Logger) *Tracker {
+	return &Tracker{
+		logger:     logger,
+		now:        time.Now,
+		objectives: objectives,
+		warnedAt:   make(map[string]time.Time),
+	}
+}
+
+// SetObjectives replaces the objectives, for example after a configuration reload. Recorded
+// requests are kept, so their slowness is judged against the new latency from then on.
+func (t *Tracker) SetObjectives(objectives Objectives) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objectives = objectives
+}
+
+// ObserveAPIRequest records a completed request. Requests that failed without a response,
+// except those canceled by the server itself, and responses with a 5xx or 429 status count
+// against availability; other 4xx responses are the caller's errors, not the portal's.
+func (t *Tracker) ObserveAPIRequest(_ string, status int, duration time.Duration, err error) {
+	failed := err != nil && !errors.Is(err, context.Canceled) ||
+		status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	b := t.bucketAt(now)
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if duration > t.objectives.Latency {
+		b.slow++
+	}
+
+	t.warnIfBurning(now)
+}
+
+// bucketAt returns the bucket for the minute containing now, clearing it if it last held
+// an older minute
+func (t *Tracker) bucketAt(now time.Time) *bucket {
+	start := now.Truncate(bucketWidth)
+	b := &t.buckets[int(start.Unix()/int64(bucketWidth.Seconds()))%bucketCount]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	return b
+}
+
+// Status reports the objectives and how the API has measured up to them
+type Status struct {
+	Objectives ObjectivesInfo `json:"objectives"`
+	Windows    []Window       `json:"windows"`
+	State      string         `json:"state"`
+}
+
+// ObjectivesInfo is the JSON form of Objectives
+type ObjectivesInfo struct {
+	Availability    float64 `json:"availability"`
+	LatencySeconds  float64 `json:"latency_seconds"`
+	LatencyTarget   float64 `json:"latency_target"`
+	WarningBurnRate float64 `json:"warning_burn_rate"`
+}
+
+// Window summarizes the requests made within a window. Availability and WithinLatency are
+// the fractions of requests that succeeded and completed within the latency objective; both
+// are 1 when there were no requests.
+type Window struct {
+	Window               string  `json:"window"`
+	Requests             uint64  `json:"requests"`
+	Failures             uint64  `json:"failures"`
+	Slow                 uint64  `json:"slow"`
+	Availability         float64 `json:"availability"`
+	WithinLatency        float64 `json:"within_latency"`
+	AvailabilityBurnRate float64 `json:"availability_burn_rate"`
+	LatencyBurnRate      float64 `json:"latency_burn_rate"`
+}
+
+// Status returns the objectives and the short and long windows. The state is burning when
+// either objective is burning fast enough to warn about, degraded when either is spending its
+// budget faster than it allows over the long window, and healthy otherwise.
+func (t *Tracker) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	short := t.window(now, ShortWindow, shortWindowLabel)
+	long := t.window(now, LongWindow, longWindowLabel)
+
+	status := Status{
+		Objectives: ObjectivesInfo{
+			Availability:    t.objectives.Availability,
+			LatencySeconds:  t.objectives.Latency.Seconds(),
+			LatencyTarget:   t.objectives.LatencyTarget,
+			WarningBurnRate: WarningBurnRate,
+		},
+		Windows: []Window{short, long},
+	}
+
+	switch {
+	case long.Requests == 0:
+		status.State = StateNoData
+	case burning(short, long, availabilityBurnRate) || burning(short, long, latencyBurnRate):
+		status.State = StateBurning
+	case long.AvailabilityBurnRate > 1 || long.LatencyBurnRate > 1:
+		status.State = StateDegraded
+	default:
+		status.State = StateHealthy
+	}
+	return status
+}
+
+// window totals the buckets within a window of now
+func (t *Tracker) window(now time.Time, length time.Duration, label string) Window {
+	w := Window{Window: label, Availability: 1, WithinLatency: 1}
+
+	oldest := now.Truncate(bucketWidth).Add(bucketWidth - length)
+	for _, b := range t.buckets {
+		if b.start.Before(oldest) || b.start.After(now) {
+			continue
+		}
+		w.Requests += b.requests
+		w.Failures += b.failures
+		w.Slow += b.slow
+	}
+
+	if w.Requests > 0 {
+		w.Availability = 1 - float64(w.Failures)/float64(w.Requests)
+		w.WithinLatency = 1 - float64(w.Slow)/float64(w.Requests)
+	}
+	w.AvailabilityBurnRate = burnRate(w.Availability, t.objectives.Availability)
+	w.LatencyBurnRate = burnRate(w.WithinLatency, t.objectives.LatencyTarget)
+	return w
+}
+
+// burnRate is the fraction of requests that missed an objective, relative to the fraction
+// the objective allows to miss. Targets are below 1, so some misses are always allowed.
+func burnRate(achieved, target float64) float64 {
+	return (1 - achieved) / (1 - target)
+}
+
+// Burn rate selectors, used to evaluate both objectives the same way
+var (
+	availabilityBurnRate = func(w Window) float64 { return w.AvailabilityBurnRate }
+	latencyBurnRate      = func(w Window) float64 { return w.LatencyBurnRate }
+)
+
+// burning reports whether an objective burns fast enough over both windows to warn about
+func burning(short, long Window, rate func(Window) float64) bool {
+	return short.Requests >= minWarningRequests && rate(short) >= WarningBurnRate && rate(long) >= WarningBurnRate
+}
+
+// warnIfBurning logs a warning for each objective that is burning, at most once per
+// warning interval
+func (t *Tracker) warnIfBurning(now time.Time) {
+	short := t.window(now, ShortWindow, shortWindowLabel)
+	long := t.window(now, LongWindow, longWindowLabel)
+
+	objectives := []struct {
+		name string
+		rate func(Window) float64
+	}{
+		{name: "availability", rate: availabilityBurnRate},
+		{name: "latency", rate: latencyBurnRate},
+	}
+	for _, objective := range objectives {
+		if !burning(short, long, objective.rate) || now.Sub(t.warnedAt[objective.name]) < warningInterval {
+			continue
+		}
+		t.warnedAt[objective.name] = now
+		t.logger.Warn("Vendor Portal API is burning its error budget",
+			"objective", objective.name,
+			"short_window_burn_rate", objective.rate(short),
+			"long_window_burn_rate", objective.rate(long),
+			"requests", short.Requests,
+			"failures", short.Failures,
+			"slow", short.Slow,
+		)
+	}
+}
@@ -0,0 +1,217 @@
+package slo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/logging"
+)
+
+// request is a request to observe in a test
+type request struct {
+	status   int
+	duration time.Duration
+	err      error
+	count    int
+}
+
+// newTestTracker returns a tracker with the default objectives, a settable clock, and its
+// log output
+func newTestTracker(t *testing.T) (*Tracker, *time.Time, *bytes.Buffer) {
+	t.Helper()
+
+	var logs bytes.Buffer
+	now := time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC)
+	tracker := New(DefaultObjectives(), logging.NewLoggerWithWriter("info", &logs))
+	tracker.now = func() time.Time { return now }
+	return tracker, &now, &logs
+}
+
+// observe records each request the number of times it asks for, at least once
+func observe(tracker *Tracker, requests []request) {
+	for _, r := range requests {
+		for range max(r.count, 1) {
+			tracker.ObserveAPIRequest(http.MethodGet, r.status, r.duration, r.err)
+		}
+	}
+}
+
+func TestTrackerStatus(t *testing.T) {
+	fast := 100 * time.Millisecond
+	slow := 5 * time.Second
+
+	tests := []struct {
+		name             string
+		requests         []request
+		wantState        string
+		wantFailures     uint64
+		wantSlow         uint64
+		wantAvailability float64
+	}{
+		{name: "no requests", wantState: StateNoData, wantAvailability: 1},
+		{
+			name:             "all succeed",
+			requests:         []request{{status: http.StatusOK, duration: fast, count: 20}},
+			wantState:        StateHealthy,
+			wantAvailability: 1,
+		},
+		{
+			name: "client errors are not the portal's",
+			requests: []request{
+				{status: http.StatusOK, duration: fast, count: 10},
+				{status: http.StatusNotFound, duration: fast, count: 10},
+			},
+			wantState:        StateHealthy,
+			wantAvailability: 1,
+		},
+		{
+			name: "canceled requests are not counted as failures",
+			requests: []request{
+				{status: http.StatusOK, duration: fast, count: 10},
+				{err: context.Canceled, duration: fast, count: 10},
+			},
+			wantState:        StateHealthy,
+			wantAvailability: 1,
+		},
+		{
+			name: "server errors burn the budget",
+			requests: []request{
+				{status: http.StatusOK, duration: fast, count: 15},
+				{status: http.StatusBadGateway, duration: fast, count: 3},
+				{status: http.StatusTooManyRequests, duration: fast},
+				{err: errors.New("connection refused"), duration: fast},
+			},
+			wantState:        StateBurning,
+			wantFailures:     5,
+			wantAvailability: 0.75,
+		},
+		{
+			name: "slow requests burn the latency budget",
+			requests: []request{
+				{status: http.StatusOK, duration: fast, count: 17},
+				{status: http.StatusOK, duration: slow, count: 3},
+			},
+			wantState:        StateBurning,
+			wantSlow:         3,
+			wantAvailability: 1,
+		},
+		{
+			name: "too few requests to warn about",
+			requests: []request{
+				{status: http.StatusOK, duration: fast, count: 3},
+				{status: http.StatusInternalServerError, duration: fast},
+			},
+			wantState:        StateDegraded,
+			wantFailures:     1,
+			wantAvailability: 0.75,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker, _, _ := newTestTracker(t)
+			observe(tracker, tt.requests)
+
+			status := tracker.Status()
+			if status.State != tt.wantState {
+				t.Errorf("Expected state %s, got %s", tt.wantState, status.State)
+			}
+			if len(status.Windows) != 2 {
+				t.Fatalf("Expected a short and a long window, got %+v", status.Windows)
+			}
+			long := status.Windows[1]
+			if long.Failures != tt.wantFailures || long.Slow != tt.wantSlow ||
+				long.Availability != tt.wantAvailability {
+				t.Errorf("Expected %d failures, %d slow, availability %v, got %+v",
+					tt.wantFailures, tt.wantSlow, tt.wantAvailability, long)
+			}
+		})
+	}
+}
+
+func TestTrackerBurnRate(t *testing.T) {
+	tracker, _, _ := newTestTracker(t)
+	observe(tracker, []request{
+		{status: http.StatusOK, count: 98},
+		{status: http.StatusServiceUnavailable, count: 2},
+	})
+
+	// 2% of requests failed against an objective allowing 1%
+	long := tracker.Status().Windows[1]
+	if diff := long.AvailabilityBurnRate - 2; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected an availability burn rate of 2, got %v", long.AvailabilityBurnRate)
+	}
+	if long.LatencyBurnRate != 0 {
+		t.Errorf("Expected no latency burn, got %v", long.LatencyBurnRate)
+	}
+}
+
+func TestTrackerWindows(t *testing.T) {
+	tracker, now, _ := newTestTracker(t)
+	observe(tracker, []request{{status: http.StatusInternalServerError, count: 10}})
+
+	// Ten minutes later the failures have left the short window but not the long one
+	*now = now.Add(10 * time.Minute)
+	observe(tracker, []request{{status: http.StatusOK, count: 10}})
+
+	status := tracker.Status()
+	short, long := status.Windows[0], status.Windows[1]
+	if short.Window != "5m" || short.Requests != 10 || short.Failures != 0 {
+		t.Errorf("Expected only the recent requests in the short window, got %+v", short)
+	}
+	if long.Window != "1h" || long.Requests != 20 || long.Failures != 10 {
+		t.Errorf("Expected all requests in the long window, got %+v", long)
+	}
+	if status.State != StateDegraded {
+		t.Errorf("Expected a recovering portal to be degraded, got %s", status.State)
+	}
+
+	// After an hour everything has expired, and a bucket reused for a new minute starts over
+	*now = now.Add(LongWindow)
+	if status := tracker.Status(); status.State != StateNoData {
+		t.Errorf("Expected old requests to expire, got %+v", status)
+	}
+	observe(tracker, []request{{status: http.StatusOK}})
+	if long := tracker.Status().Windows[1]; long.Requests != 1 {
+		t.Errorf("Expected a reused bucket to be cleared, got %+v", long)
+	}
+}
+
+func TestTrackerWarnings(t *testing.T) {
+	tracker, now, logs := newTestTracker(t)
+
+	observe(tracker, []request{{status: http.StatusInternalServerError, count: minWarningRequests * 2}})
+	if count := strings.Count(logs.String(), "burning its error budget"); count != 1 {
+		t.Fatalf("Expected one warning within the warning interval, got %d:\n%s", count, logs.String())
+	}
+	if !strings.Contains(logs.String(), `"objective":"availability"`) ||
+		!strings.Contains(logs.String(), `"level":"WARN"`) {
+		t.Errorf("Expected an availability warning, got %s", logs.String())
+	}
+
+	*now = now.Add(warningInterval)
+	observe(tracker, []request{{status: http.StatusInternalServerError, count: minWarningRequests}})
+	if count := strings.Count(logs.String(), "burning its error budget"); count != 2 {
+		t.Errorf("Expected another warning after the interval, got %d", count)
+	}
+}
+
+func TestTrackerSetObjectives(t *testing.T) {
+	tracker, _, _ := newTestTracker(t)
+	tracker.SetObjectives(Objectives{Availability: 0.5, Latency: 10 * time.Second, LatencyTarget: 0.5})
+
+	observe(tracker, []request{
+		{status: http.StatusOK, duration: 5 * time.Second, count: 10},
+		{status: http.StatusInternalServerError, count: 2},
+	})
+
+	status := tracker.Status()
+	if status.State != StateHealthy || status.Objectives.LatencySeconds != 10 {
+		t.Errorf("Expected the looser objectives to be met, got %+v", status)
+	}
+}
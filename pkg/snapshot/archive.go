@@ -0,0 +1,165 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archive layout constants
+const (
+	archiveEntryName   = "snapshot.json"
+	archiveNamePrefix  = "account-snapshot-"
+	archiveNameSuffix  = ".tar.gz"
+	archiveTimeLayout  = "20060102T150405.000Z"
+	archiveDirMode     = 0o750
+	archiveFileMode    = 0o600
+	maxArchiveReadSize = 512 << 20
+
+	// maxArchiveNameAttempts bounds how many numbered names are tried for snapshots taken in
+	// the same millisecond
+	maxArchiveNameAttempts = 100
+)
+
+// FileSink writes snapshots as timestamped gzip-compressed tar archives in a directory
+type FileSink struct {
+	Dir string
+}
+
+// NewFileSink creates a FileSink that writes archives to dir
+func NewFileSink(dir string) *FileSink {
+	return &FileSink{Dir: dir}
+}
+
+// ArchiveName returns the file name used for a snapshot taken at the given time, to the
+// millisecond
+func ArchiveName(createdAt time.Time) string {
+	return archiveNamePrefix + createdAt.UTC().Format(archiveTimeLayout) + archiveNameSuffix
+}
+
+// Write stores the snapshot in a new archive and returns the archive path. A snapshot taken
+// in the same millisecond as an existing archive gets a numbered name, such as
+// account-snapshot-20240305T143000.000Z-2.tar.gz, rather than replacing it.
+func (s *FileSink) Write(snapshot *Snapshot) (string, error) {
+	if err := os.MkdirAll(s.Dir, archiveDirMode); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	path, file, err := s.createArchive(ArchiveName(snapshot.CreatedAt))
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeArchive(file, snapshot); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to close snapshot archive: %w", err)
+	}
+
+	return path, nil
+}
+
+// createArchive creates a new archive file named name, or numbered after it when that name
+// is taken
+func (s *FileSink) createArchive(name string) (string, *os.File, error) {
+	base := strings.TrimSuffix(name, archiveNameSuffix)
+	for attempt := 1; attempt <= maxArchiveNameAttempts; attempt++ {
+		if attempt > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, attempt, archiveNameSuffix)
+		}
+		path := filepath.Join(s.Dir, name)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, archiveFileMode)
+		if err == nil {
+			return path, file, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", nil, fmt.Errorf("failed to create snapshot archive: %w", err)
+		}
+	}
+	return "", nil, fmt.Errorf("failed to create snapshot archive: %d archives named %s already exist",
+		maxArchiveNameAttempts, base)
+}
+
+// writeArchive encodes the snapshot as JSON inside a gzip-compressed tar stream
+func writeArchive(w io.Writer, snapshot *Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	header := &tar.Header{
+		Name:    archiveEntryName,
+		Mode:    archiveFileMode,
+		Size:    int64(len(data)),
+		ModTime: snapshot.CreatedAt,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write snapshot archive: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write snapshot archive: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot archive: %w", err)
+	}
+
+	return nil
+}
+
+// ReadArchive loads a snapshot from an archive written by FileSink
+func ReadArchive(path string) (*Snapshot, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot archive '%s': %w", path, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("snapshot archive '%s' does not contain %s", path, archiveEntryName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot archive '%s': %w", path, err)
+		}
+		if header.Name != archiveEntryName {
+			continue
+		}
+
+		var snapshot Snapshot
+		if err := json.NewDecoder(io.LimitReader(tr, maxArchiveReadSize)).Decode(&snapshot); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot archive '%s': %w", path, err)
+		}
+		if snapshot.FormatVersion != FormatVersion {
+			return nil, fmt.Errorf("snapshot archive '%s' has unsupported format version %d",
+				path, snapshot.FormatVersion)
+		}
+		return &snapshot, nil
+	}
+}
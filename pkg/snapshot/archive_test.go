@@ -0,0 +1,103 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestArchiveName(t *testing.T) {
+	createdAt := time.Date(2024, 3, 5, 14, 30, 0, int(250*time.Millisecond), time.UTC)
+
+	expected := "account-snapshot-20240305T143000.250Z.tar.gz"
+	if got := ArchiveName(createdAt); got != expected {
+		t.Errorf("ArchiveName() = %s, want %s", got, expected)
+	}
+}
+
+func TestFileSink_RoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	sink := NewFileSink(dir)
+
+	snapshot := &Snapshot{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC),
+		Redacted:      true,
+		Applications: []ApplicationSnapshot{{
+			Application: models.Application{ID: "app-1", Name: "App One"},
+			Channels:    []models.Channel{{ID: "channel-1", Name: "Stable"}},
+			Releases:    []models.Release{},
+			Customers:   []models.Customer{},
+		}},
+	}
+
+	path, err := sink.Write(snapshot)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(path, dir) || !strings.HasSuffix(path, ".tar.gz") {
+		t.Errorf("Unexpected archive path: %s", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected archive to exist: %v", err)
+	}
+	if info.Mode().Perm() != archiveFileMode {
+		t.Errorf("Expected archive mode %o, got %o", archiveFileMode, info.Mode().Perm())
+	}
+
+	loaded, err := ReadArchive(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading archive: %v", err)
+	}
+	if !loaded.CreatedAt.Equal(snapshot.CreatedAt) || !loaded.Redacted {
+		t.Errorf("Expected snapshot header to round-trip, got %+v", loaded)
+	}
+	if loaded.Counts() != snapshot.Counts() {
+		t.Errorf("Expected counts %+v, got %+v", snapshot.Counts(), loaded.Counts())
+	}
+
+	// Snapshots with the same timestamp get their own archives rather than overwriting it
+	for _, want := range []string{"-2.tar.gz", "-3.tar.gz"} {
+		again, err := sink.Write(snapshot)
+		if err != nil {
+			t.Fatalf("Unexpected error writing a snapshot taken at the same time: %v", err)
+		}
+		if again != strings.TrimSuffix(path, ".tar.gz")+want {
+			t.Errorf("Expected a numbered archive next to %s, got %s", path, again)
+		}
+	}
+	if _, err := ReadArchive(path); err != nil {
+		t.Errorf("Expected the first archive to be kept: %v", err)
+	}
+}
+
+func TestReadArchive_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	notArchive := filepath.Join(dir, "not-an-archive.tar.gz")
+	if err := os.WriteFile(notArchive, []byte("plain text"), 0o600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "missing file", path: filepath.Join(dir, "missing.tar.gz")},
+		{name: "not an archive", path: notArchive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadArchive(tt.path); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
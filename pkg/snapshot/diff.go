@@ -0,0 +1,184 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Entity kind constants used in snapshot diffs
+const (
+	KindApplication = "application"
+	KindChannel     = "channel"
+	KindRelease     = "release"
+	KindCustomer    = "customer"
+)
+
+// Diff reports the entity-level differences between two snapshots
+type Diff struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Added   []EntityRef    `json:"added"`
+	Removed []EntityRef    `json:"removed"`
+	Changed []EntityChange `json:"changed"`
+}
+
+// EntityRef identifies an entity in a snapshot
+type EntityRef struct {
+	Kind          string `json:"kind"`
+	ID            string `json:"id"`
+	ApplicationID string `json:"application_id,omitempty"`
+	Name          string `json:"name,omitempty"`
+}
+
+// EntityChange lists the fields that differ for an entity present in both snapshots
+type EntityChange struct {
+	EntityRef
+	Fields []FieldChange `json:"fields"`
+}
+
+// FieldChange records the previous and current value of a single field
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// entity is a flattened snapshot entity with its JSON fields for comparison
+type entity struct {
+	ref    EntityRef
+	fields map[string]any
+}
+
+// IsEmpty reports whether the snapshots are identical at the entity level
+func (d *Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare reports entities added, removed, and changed between the from and to snapshots
+func Compare(from, to *Snapshot) (*Diff, error) {
+	before, err := flatten(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := flatten(to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{
+		From:    from.CreatedAt,
+		To:      to.CreatedAt,
+		Added:   []EntityRef{},
+		Removed: []EntityRef{},
+		Changed: []EntityChange{},
+	}
+
+	for _, key := range sortedKeys(after) {
+		current := after[key]
+		previous, existed := before[key]
+		if !existed {
+			diff.Added = append(diff.Added, current.ref)
+			continue
+		}
+		if fields := compareFields(previous.fields, current.fields); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, EntityChange{EntityRef: current.ref, Fields: fields})
+		}
+	}
+
+	for _, key := range sortedKeys(before) {
+		if _, exists := after[key]; !exists {
+			diff.Removed = append(diff.Removed, before[key].ref)
+		}
+	}
+
+	return diff, nil
+}
+
+// flatten indexes every entity in a snapshot by kind and ID
+func flatten(snapshot *Snapshot) (map[string]entity, error) {
+	entities := make(map[string]entity)
+
+	add := func(ref EntityRef, value any) error {
+		fields, err := toFields(value)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s '%s': %w", ref.Kind, ref.ID, err)
+		}
+		entities[ref.Kind+"/"+ref.ID] = entity{ref: ref, fields: fields}
+		return nil
+	}
+
+	for i := range snapshot.Applications {
+		app := &snapshot.Applications[i]
+		appID := app.Application.ID
+
+		if err := add(EntityRef{Kind: KindApplication, ID: appID, Name: app.Application.Name}, app.Application); err != nil {
+			return nil, err
+		}
+		for j := range app.Channels {
+			ref := EntityRef{Kind: KindChannel, ID: app.Channels[j].ID, ApplicationID: appID, Name: app.Channels[j].Name}
+			if err := add(ref, app.Channels[j]); err != nil {
+				return nil, err
+			}
+		}
+		for j := range app.Releases {
+			ref := EntityRef{Kind: KindRelease, ID: app.Releases[j].ID, ApplicationID: appID, Name: app.Releases[j].Version}
+			if err := add(ref, app.Releases[j]); err != nil {
+				return nil, err
+			}
+		}
+		for j := range app.Customers {
+			ref := EntityRef{Kind: KindCustomer, ID: app.Customers[j].ID, ApplicationID: appID, Name: app.Customers[j].Name}
+			if err := add(ref, app.Customers[j]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return entities, nil
+}
+
+// toFields converts an entity into its JSON field map so fields compare as they are serialized
+func toFields(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// compareFields returns the fields whose values differ, sorted by field name
+func compareFields(before, after map[string]any) []FieldChange {
+	names := make(map[string]bool, len(before)+len(after))
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	var changes []FieldChange
+	for _, name := range sortedKeys(names) {
+		if !reflect.DeepEqual(before[name], after[name]) {
+			changes = append(changes, FieldChange{Field: name, From: before[name], To: after[name]})
+		}
+	}
+	return changes
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
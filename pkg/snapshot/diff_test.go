@@ -0,0 +1,126 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+func TestCompare(t *testing.T) {
+	from := &Snapshot{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Applications: []ApplicationSnapshot{{
+			Application: models.Application{ID: "app-1", Name: "App One"},
+			Channels: []models.Channel{
+				{ID: "channel-1", Name: "Stable", ReleaseSequence: 1},
+				{ID: "channel-2", Name: "Beta", ReleaseSequence: 2},
+			},
+			Releases: []models.Release{{ID: "release-1", Version: "1.0.0", Sequence: 1}},
+			Customers: []models.Customer{
+				{ID: "customer-1", Name: "Acme", Entitlements: map[string]string{"seat_count": "10"}},
+			},
+		}},
+	}
+
+	to := &Snapshot{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Applications: []ApplicationSnapshot{{
+			Application: models.Application{ID: "app-1", Name: "App One"},
+			Channels: []models.Channel{
+				{ID: "channel-1", Name: "Stable", ReleaseSequence: 2},
+			},
+			Releases: []models.Release{
+				{ID: "release-1", Version: "1.0.0", Sequence: 1},
+				{ID: "release-2", Version: "1.1.0", Sequence: 2},
+			},
+			Customers: []models.Customer{
+				{ID: "customer-1", Name: "Acme", Entitlements: map[string]string{"seat_count": "25"}},
+			},
+		}},
+	}
+
+	diff, err := Compare(from, to)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !diff.From.Equal(from.CreatedAt) || !diff.To.Equal(to.CreatedAt) {
+		t.Errorf("Expected diff to record snapshot times, got %v to %v", diff.From, diff.To)
+	}
+
+	if len(diff.Added) != 1 || diff.Added[0].Kind != KindRelease || diff.Added[0].ID != "release-2" {
+		t.Errorf("Expected release-2 to be added, got %+v", diff.Added)
+	}
+	if diff.Added[0].ApplicationID != "app-1" || diff.Added[0].Name != "1.1.0" {
+		t.Errorf("Expected added release to reference its application and version, got %+v", diff.Added[0])
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0].Kind != KindChannel || diff.Removed[0].ID != "channel-2" {
+		t.Errorf("Expected channel-2 to be removed, got %+v", diff.Removed)
+	}
+
+	changed := make(map[string][]FieldChange)
+	for _, change := range diff.Changed {
+		changed[change.Kind+"/"+change.ID] = change.Fields
+	}
+
+	if len(changed) != 2 {
+		t.Fatalf("Expected 2 changed entities, got %+v", diff.Changed)
+	}
+
+	channelFields := changed[KindChannel+"/channel-1"]
+	if len(channelFields) != 1 || channelFields[0].Field != "releaseSequence" {
+		t.Errorf("Expected channel releaseSequence change, got %+v", channelFields)
+	}
+	if channelFields[0].From != float64(1) || channelFields[0].To != float64(2) {
+		t.Errorf("Expected releaseSequence to change from 1 to 2, got %v to %v",
+			channelFields[0].From, channelFields[0].To)
+	}
+
+	customerFields := changed[KindCustomer+"/customer-1"]
+	if len(customerFields) != 1 || customerFields[0].Field != "entitlements" {
+		t.Errorf("Expected customer entitlements change, got %+v", customerFields)
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	snapshot := &Snapshot{
+		FormatVersion: FormatVersion,
+		Applications: []ApplicationSnapshot{{
+			Application: models.Application{ID: "app-1", Name: "App One"},
+			Channels:    []models.Channel{{ID: "channel-1", Name: "Stable"}},
+		}},
+	}
+
+	diff, err := Compare(snapshot, snapshot)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !diff.IsEmpty() {
+		t.Errorf("Expected no differences, got %+v", diff)
+	}
+	if diff.Added == nil || diff.Removed == nil || diff.Changed == nil {
+		t.Error("Expected empty diff lists to be non-nil")
+	}
+}
+
+func TestCompare_ApplicationRemoved(t *testing.T) {
+	from := &Snapshot{Applications: []ApplicationSnapshot{{
+		Application: models.Application{ID: "app-1"},
+		Customers:   []models.Customer{{ID: "customer-1"}},
+	}}}
+	to := &Snapshot{Applications: []ApplicationSnapshot{}}
+
+	diff, err := Compare(from, to)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(diff.Removed) != 2 {
+		t.Errorf("Expected application and customer to be removed, got %+v", diff.Removed)
+	}
+}
@@ -0,0 +1,187 @@
+// Package snapshot captures a point-in-time copy of a vendor account's applications,
+// channels, release metadata, and customers for compliance and backup purposes.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+	"github.com/crdant/replicated-mcp-server/pkg/vendorapi"
+)
+
+// FormatVersion identifies the snapshot layout so readers can detect incompatible archives.
+// Version 2 stores entities under the Vendor Portal's camelCase field names.
+const FormatVersion = 2
+
+// redactedValue replaces sensitive customer fields in redacted snapshots
+const redactedValue = "[REDACTED]"
+
+// Snapshot is a point-in-time copy of a vendor account
+type Snapshot struct {
+	FormatVersion int                   `json:"format_version"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Redacted      bool                  `json:"redacted"`
+	Applications  []ApplicationSnapshot `json:"applications"`
+}
+
+// ApplicationSnapshot holds an application and the entities that belong to it
+type ApplicationSnapshot struct {
+	Application models.Application `json:"application"`
+	Channels    []models.Channel   `json:"channels"`
+	Releases    []models.Release   `json:"releases"`
+	Customers   []models.Customer  `json:"customers"`
+}
+
+// Counts summarizes the number of entities in a snapshot
+type Counts struct {
+	Applications int `json:"applications"`
+	Channels     int `json:"channels"`
+	Releases     int `json:"releases"`
+	Customers    int `json:"customers"`
+}
+
+// Options controls how a snapshot is collected
+type Options struct {
+	// Redact removes customer names, emails, license IDs, and custom field values
+	Redact bool
+}
+
+// Source provides iterators over the entities captured in a snapshot
+type Source interface {
+	Applications(ctx context.Context) iter.Seq2[models.Application, error]
+	Channels(ctx context.Context, appID string) iter.Seq2[models.Channel, error]
+	Releases(ctx context.Context, appID string) iter.Seq2[models.Release, error]
+	Customers(ctx context.Context, appID string) iter.Seq2[models.Customer, error]
+}
+
+// apiSource reads snapshot entities from the Vendor Portal API
+type apiSource struct {
+	applications vendorapi.Applications
+	channels     vendorapi.Channels
+	releases     vendorapi.Releases
+	customers    vendorapi.Customers
+}
+
+// NewAPISource creates a Source backed by the Vendor Portal services
+func NewAPISource(services *vendorapi.Services) Source {
+	return &apiSource{
+		applications: services.Applications,
+		channels:     services.Channels,
+		releases:     services.Releases,
+		customers:    services.Customers,
+	}
+}
+
+func (s *apiSource) Applications(ctx context.Context) iter.Seq2[models.Application, error] {
+	return s.applications.Applications(ctx)
+}
+
+func (s *apiSource) Channels(ctx context.Context, appID string) iter.Seq2[models.Channel, error] {
+	return s.channels.Channels(ctx, appID)
+}
+
+func (s *apiSource) Releases(ctx context.Context, appID string) iter.Seq2[models.Release, error] {
+	return s.releases.Releases(ctx, appID)
+}
+
+func (s *apiSource) Customers(ctx context.Context, appID string) iter.Seq2[models.Customer, error] {
+	return s.customers.Customers(ctx, appID)
+}
+
+// Collect reads every application and its channels, releases, and customers from the source
+func Collect(ctx context.Context, source Source, opts Options) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Redacted:      opts.Redact,
+		Applications:  []ApplicationSnapshot{},
+	}
+
+	for app, err := range source.Applications(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect applications: %w", err)
+		}
+
+		appSnapshot, err := collectApplication(ctx, source, app)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.Redact {
+			for i := range appSnapshot.Customers {
+				redactCustomer(&appSnapshot.Customers[i])
+			}
+		}
+
+		snapshot.Applications = append(snapshot.Applications, *appSnapshot)
+	}
+
+	return snapshot, nil
+}
+
+// collectApplication reads the channels, releases, and customers for one application
+func collectApplication(ctx context.Context, source Source, app models.Application) (*ApplicationSnapshot, error) {
+	channels, err := api.Collect(source.Channels(ctx, app.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect channels for application '%s': %w", app.ID, err)
+	}
+
+	releases, err := api.Collect(source.Releases(ctx, app.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect releases for application '%s': %w", app.ID, err)
+	}
+
+	// Snapshots record release metadata only; release manifests can be large
+	for i := range releases {
+		releases[i].Config = ""
+	}
+
+	customers, err := api.Collect(source.Customers(ctx, app.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect customers for application '%s': %w", app.ID, err)
+	}
+
+	return &ApplicationSnapshot{
+		Application: app,
+		Channels:    nonNil(channels),
+		Releases:    nonNil(releases),
+		Customers:   nonNil(customers),
+	}, nil
+}
+
+// redactCustomer removes personally identifying and secret customer data in place
+func redactCustomer(customer *models.Customer) {
+	customer.Name = redactedValue
+	if customer.Email != "" {
+		customer.Email = redactedValue
+	}
+	if customer.LicenseID != "" {
+		customer.LicenseID = redactedValue
+	}
+	for key := range customer.CustomFields {
+		customer.CustomFields[key] = redactedValue
+	}
+}
+
+// nonNil returns an empty slice in place of nil so snapshots encode empty lists as []
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// Counts returns the number of entities of each kind in the snapshot
+func (s *Snapshot) Counts() Counts {
+	counts := Counts{Applications: len(s.Applications)}
+	for i := range s.Applications {
+		counts.Channels += len(s.Applications[i].Channels)
+		counts.Releases += len(s.Applications[i].Releases)
+		counts.Customers += len(s.Applications[i].Customers)
+	}
+	return counts
+}
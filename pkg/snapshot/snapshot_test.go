@@ -0,0 +1,139 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// fakeSource serves snapshot entities from memory
+type fakeSource struct {
+	applications []models.Application
+	channels     map[string][]models.Channel
+	releases     map[string][]models.Release
+	customers    map[string][]models.Customer
+	customersErr error
+}
+
+func seqOf[T any](items []T, err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (f *fakeSource) Applications(context.Context) iter.Seq2[models.Application, error] {
+	return seqOf(f.applications, nil)
+}
+
+func (f *fakeSource) Channels(_ context.Context, appID string) iter.Seq2[models.Channel, error] {
+	return seqOf(f.channels[appID], nil)
+}
+
+func (f *fakeSource) Releases(_ context.Context, appID string) iter.Seq2[models.Release, error] {
+	return seqOf(f.releases[appID], nil)
+}
+
+func (f *fakeSource) Customers(_ context.Context, appID string) iter.Seq2[models.Customer, error] {
+	return seqOf(f.customers[appID], f.customersErr)
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		applications: []models.Application{
+			{ID: "app-1", Name: "App One", Slug: "app-one"},
+			{ID: "app-2", Name: "App Two", Slug: "app-two"},
+		},
+		channels: map[string][]models.Channel{
+			"app-1": {{ID: "channel-1", Name: "Stable"}, {ID: "channel-2", Name: "Beta"}},
+		},
+		releases: map[string][]models.Release{
+			"app-1": {{ID: "release-1", Version: "1.0.0", Config: "apiVersion: v1"}},
+		},
+		customers: map[string][]models.Customer{
+			"app-1": {{
+				ID:           "customer-1",
+				Name:         "Acme",
+				Email:        "ops@acme.example",
+				LicenseID:    "license-1",
+				CustomFields: map[string]string{"account_manager": "Jane"},
+				Entitlements: map[string]string{"seat_count": "25"},
+			}},
+		},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	snapshot, err := Collect(context.Background(), newFakeSource(), Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := Counts{Applications: 2, Channels: 2, Releases: 1, Customers: 1}
+	if snapshot.Counts() != expected {
+		t.Errorf("Expected counts %+v, got %+v", expected, snapshot.Counts())
+	}
+
+	if snapshot.FormatVersion != FormatVersion || snapshot.CreatedAt.IsZero() {
+		t.Errorf("Expected snapshot header to be set, got %+v", snapshot)
+	}
+
+	if snapshot.Applications[0].Releases[0].Config != "" {
+		t.Error("Expected release manifests to be omitted from snapshots")
+	}
+
+	if snapshot.Applications[1].Channels == nil || snapshot.Applications[1].Customers == nil {
+		t.Error("Expected empty entity lists to be non-nil")
+	}
+
+	if snapshot.Applications[0].Customers[0].Name != "Acme" {
+		t.Error("Expected customer data to be preserved without redaction")
+	}
+}
+
+func TestCollect_Redacted(t *testing.T) {
+	snapshot, err := Collect(context.Background(), newFakeSource(), Options{Redact: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !snapshot.Redacted {
+		t.Error("Expected snapshot to be marked redacted")
+	}
+
+	customer := snapshot.Applications[0].Customers[0]
+	for field, value := range map[string]string{
+		"name":          customer.Name,
+		"email":         customer.Email,
+		"license_id":    customer.LicenseID,
+		"custom_fields": customer.CustomFields["account_manager"],
+	} {
+		if value != redactedValue {
+			t.Errorf("Expected %s to be redacted, got '%s'", field, value)
+		}
+	}
+
+	if customer.ID != "customer-1" || customer.Entitlements["seat_count"] != "25" {
+		t.Error("Expected IDs and entitlements to be preserved in redacted snapshots")
+	}
+}
+
+func TestCollect_SourceError(t *testing.T) {
+	source := newFakeSource()
+	source.customersErr = errors.New("forbidden")
+
+	if _, err := Collect(context.Background(), source, Options{}); err == nil {
+		t.Error("Expected error when a source fails")
+	}
+}
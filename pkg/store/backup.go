@@ -0,0 +1,251 @@
+package store
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Backup archive layout constants
+const (
+	// BackupFormatVersion is the version of the backup archive layout
+	BackupFormatVersion = 1
+
+	backupManifestName = "manifest.json"
+	backupSuffix       = ".bak"
+	restoreSuffix      = ".restore"
+	maxBackupSize      = 1 << 30
+	maxManifestSize    = 1 << 20
+)
+
+// ErrStateExists is returned when restoring into a data directory that already has state
+// and replacing it was not requested
+var ErrStateExists = errors.New("the data directory already has server state")
+
+// BackupManifest describes a state backup archive
+type BackupManifest struct {
+	FormatVersion int            `json:"format_version"`
+	SchemaVersion int            `json:"schema_version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Records       map[string]int `json:"records"`
+}
+
+// Backup writes a consistent copy of the database, with a manifest describing it, to w as a
+// gzip-compressed tar archive. Other servers can keep using the store while it is written.
+func (s *Store) Backup(w io.Writer) (*BackupManifest, error) {
+	manifest := &BackupManifest{
+		FormatVersion: BackupFormatVersion,
+		CreatedAt:     s.now().UTC(),
+		Records:       make(map[string]int),
+	}
+
+	err := s.view(func(tx *bolt.Tx) error {
+		version, err := schemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		manifest.SchemaVersion = version
+
+		err = tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if string(name) != bucketMeta {
+				manifest.Records[string(name)] = b.Stats().KeyN
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return writeBackup(w, manifest, tx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up state store: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeBackup writes the manifest and the transaction's view of the database as a tar stream
+func writeBackup(w io.Writer, manifest *BackupManifest, tx *bolt.Tx) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	header := &tar.Header{
+		Name: backupManifestName, Mode: storeMode, Size: int64(len(data)), ModTime: manifest.CreatedAt,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	header = &tar.Header{Name: FileName, Mode: storeMode, Size: tx.Size(), ModTime: manifest.CreatedAt}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tx.WriteTo(tw); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Restore replaces the database with the one in an archive written by Backup and returns
+// the archive's manifest. When the data directory already has a database, replace must be
+// set; the current database is then kept beside the restored one with a .bak suffix.
+// Archives from a newer schema than this server supports are rejected.
+func (s *Store) Restore(r io.Reader, replace bool) (*BackupManifest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.path()); err == nil && !replace {
+		return nil, ErrStateExists
+	}
+	if err := os.MkdirAll(s.dir, storeDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	staged := s.path() + restoreSuffix
+	defer os.Remove(staged)
+
+	manifest, err := readBackup(r, staged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state backup: %w", err)
+	}
+	if err := checkRestoredSchema(staged, manifest); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(s.path(), s.path()+backupSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to keep the current state store: %w", err)
+	}
+	if err := os.Rename(staged, s.path()); err != nil {
+		return nil, fmt.Errorf("failed to restore state store: %w", err)
+	}
+
+	// The restored database may be from an older schema
+	s.migrated = false
+	return manifest, nil
+}
+
+// readBackup reads a backup archive, writing its database to staged
+func readBackup(r io.Reader, staged string) (*BackupManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var manifest *BackupManifest
+	hasDatabase := false
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch header.Name {
+		case backupManifestName:
+			manifest = &BackupManifest{}
+			if err := json.NewDecoder(io.LimitReader(tr, maxManifestSize)).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", backupManifestName, err)
+			}
+		case FileName:
+			if err := writeStaged(staged, tr); err != nil {
+				return nil, err
+			}
+			hasDatabase = true
+		}
+	}
+
+	switch {
+	case manifest == nil:
+		return nil, fmt.Errorf("the archive has no %s", backupManifestName)
+	case !hasDatabase:
+		return nil, fmt.Errorf("the archive has no %s", FileName)
+	case manifest.FormatVersion != BackupFormatVersion:
+		return nil, fmt.Errorf("unsupported backup format version %d", manifest.FormatVersion)
+	}
+	return manifest, nil
+}
+
+// writeStaged copies a database out of an archive
+func writeStaged(staged string, r io.Reader) error {
+	file, err := os.OpenFile(filepath.Clean(staged), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, storeMode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, io.LimitReader(r, maxBackupSize)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// checkRestoredSchema opens a restored database to confirm it is intact and that this
+// server can migrate it
+func checkRestoredSchema(staged string, manifest *BackupManifest) error {
+	db, err := bolt.Open(staged, storeMode, &bolt.Options{Timeout: lockTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("the backup's database is not readable: %w", err)
+	}
+	defer db.Close()
+
+	var version int
+	err = db.View(func(tx *bolt.Tx) error {
+		version, err = schemaVersion(tx)
+		return err
+	})
+	switch {
+	case err != nil:
+		return fmt.Errorf("the backup's database is not readable: %w", err)
+	case version != manifest.SchemaVersion:
+		return fmt.Errorf("the backup's database has schema version %d, but its manifest says %d",
+			version, manifest.SchemaVersion)
+	case version > SchemaVersion():
+		return fmt.Errorf("the backup has schema version %d, newer than this server supports (%d); "+
+			"upgrade the server", version, SchemaVersion())
+	}
+	return nil
+}
+
+// schemaVersion reads a database's schema version, treating a database without one as
+// version 0
+func schemaVersion(tx *bolt.Tx) (int, error) {
+	meta := tx.Bucket([]byte(bucketMeta))
+	if meta == nil {
+		return 0, nil
+	}
+	data := meta.Get([]byte(schemaVersionKey))
+	if data == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(string(data))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q", data)
+	}
+	return version, nil
+}
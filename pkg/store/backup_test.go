@@ -0,0 +1,207 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStore_BackupRestore(t *testing.T) {
+	source := Open(t.TempDir())
+	if _, err := source.AddTags(EntityApplication, "app-1", []string{"enterprise", "beta"}); err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+	if _, err := source.AddNote(EntityCustomer, "cust-1", "renewal due in March", "sam"); err != nil {
+		t.Fatalf("AddNote() unexpected error: %v", err)
+	}
+
+	var archive bytes.Buffer
+	manifest, err := source.Backup(&archive)
+	if err != nil {
+		t.Fatalf("Backup() unexpected error: %v", err)
+	}
+	if manifest.SchemaVersion != SchemaVersion() {
+		t.Errorf("Expected schema version %d, got %d", SchemaVersion(), manifest.SchemaVersion)
+	}
+	if manifest.Records[BucketTags] != 2 || manifest.Records[BucketNotes] != 1 {
+		t.Errorf("Expected 2 tags and 1 note in the manifest, got %v", manifest.Records)
+	}
+
+	dir := t.TempDir()
+	restored, err := Open(dir).Restore(bytes.NewReader(archive.Bytes()), false)
+	if err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	if restored.Records[BucketTags] != 2 {
+		t.Errorf("Expected the restored manifest to match, got %v", restored.Records)
+	}
+
+	target := Open(dir)
+	entities, err := target.ListByTag("beta", EntityApplication)
+	if err != nil {
+		t.Fatalf("ListByTag() unexpected error: %v", err)
+	}
+	if len(entities) != 1 || len(entities[0].Tags) != 2 {
+		t.Errorf("Expected the restored tags, got %+v", entities)
+	}
+	notes, err := target.ListNotes(EntityCustomer, "cust-1")
+	if err != nil {
+		t.Fatalf("ListNotes() unexpected error: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Text != "renewal due in March" {
+		t.Errorf("Expected the restored note, got %+v", notes)
+	}
+}
+
+func TestStore_RestoreExistingState(t *testing.T) {
+	var archive bytes.Buffer
+	if _, err := Open(t.TempDir()).Backup(&archive); err != nil {
+		t.Fatalf("Backup() unexpected error: %v", err)
+	}
+
+	dir := t.TempDir()
+	existing := Open(dir)
+	if _, err := existing.AddTags(EntityApplication, "app-1", []string{"enterprise"}); err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+
+	if _, err := existing.Restore(bytes.NewReader(archive.Bytes()), false); !errors.Is(err, ErrStateExists) {
+		t.Fatalf("Restore() error = %v, want %v", err, ErrStateExists)
+	}
+
+	if _, err := existing.Restore(bytes.NewReader(archive.Bytes()), true); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	entities, err := existing.ListByTag("enterprise", "")
+	if err != nil {
+		t.Fatalf("ListByTag() unexpected error: %v", err)
+	}
+	if len(entities) != 0 {
+		t.Errorf("Expected the restored, empty state, got %+v", entities)
+	}
+
+	// The replaced database is kept beside the restored one
+	if _, err := os.Stat(filepath.Join(dir, FileName+backupSuffix)); err != nil {
+		t.Errorf("Expected the previous database to be kept: %v", err)
+	}
+}
+
+func TestStore_RestoreInvalidArchives(t *testing.T) {
+	var valid bytes.Buffer
+	if _, err := Open(t.TempDir()).Backup(&valid); err != nil {
+		t.Fatalf("Backup() unexpected error: %v", err)
+	}
+	database := archiveEntry(t, valid.Bytes(), FileName)
+
+	tests := []struct {
+		name        string
+		archive     []byte
+		errContains string
+	}{
+		{
+			name:        "not an archive",
+			archive:     []byte("not gzip"),
+			errContains: "failed to read state backup",
+		},
+		{
+			name:        "missing manifest",
+			archive:     buildArchive(t, map[string]string{FileName: string(database)}),
+			errContains: "no manifest.json",
+		},
+		{
+			name:        "missing database",
+			archive:     buildArchive(t, map[string]string{backupManifestName: `{"format_version": 1}`}),
+			errContains: "no state.db",
+		},
+		{
+			name: "unsupported format",
+			archive: buildArchive(t, map[string]string{
+				backupManifestName: `{"format_version": 99}`,
+				FileName:           string(database),
+			}),
+			errContains: "unsupported backup format version 99",
+		},
+		{
+			name: "corrupt database",
+			archive: buildArchive(t, map[string]string{
+				backupManifestName: `{"format_version": 1, "schema_version": 2}`,
+				FileName:           "{not a database",
+			}),
+			errContains: "not readable",
+		},
+		{
+			name: "mismatched schema",
+			archive: buildArchive(t, map[string]string{
+				backupManifestName: `{"format_version": 1, "schema_version": 99}`,
+				FileName:           string(database),
+			}),
+			errContains: "but its manifest says 99",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			_, err := Open(dir).Restore(bytes.NewReader(tt.archive), false)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("Restore() error = %v, expected to contain %s", err, tt.errContains)
+			}
+			if _, err := os.Stat(filepath.Join(dir, FileName)); err == nil {
+				t.Error("Expected no database after a failed restore")
+			}
+		})
+	}
+}
+
+// buildArchive writes files into a gzip-compressed tar archive
+func buildArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: storeMode, Size: int64(len(content))}); err != nil {
+			t.Fatalf("Failed to write archive header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write archive entry: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// archiveEntry reads one file out of a gzip-compressed tar archive
+func archiveEntry(t *testing.T, archive []byte, name string) []byte {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			t.Fatalf("Archive has no %s: %v", name, err)
+		}
+		if header.Name == name {
+			var buf bytes.Buffer
+			if _, err := buf.ReadFrom(tr); err != nil {
+				t.Fatalf("Failed to read %s: %v", name, err)
+			}
+			return buf.Bytes()
+		}
+	}
+}
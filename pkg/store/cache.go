@@ -0,0 +1,35 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// cacheEntry is a cached value with the time it was saved
+type cacheEntry struct {
+	SavedAt time.Time       `json:"saved_at"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// SaveCache stores a value in the cache bucket under name, stamped with the current time
+func (s *Store) SaveCache(name string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.Put(BucketCache, name, cacheEntry{SavedAt: s.now().UTC(), Value: data})
+}
+
+// LoadCache decodes the value cached under name into value if it was saved within maxAge,
+// returning when it was saved. Missing and expired entries are reported as not found.
+func (s *Store) LoadCache(name string, maxAge time.Duration, value any) (time.Time, bool, error) {
+	var entry cacheEntry
+	found, err := s.Get(BucketCache, name, &entry)
+	if err != nil || !found || s.now().Sub(entry.SavedAt) > maxAge {
+		return time.Time{}, false, err
+	}
+	if err := decodeRecord(BucketCache, name, entry.Value, value); err != nil {
+		return time.Time{}, false, err
+	}
+	return entry.SavedAt, true, nil
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_Cache(t *testing.T) {
+	store := Open(t.TempDir())
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	if err := store.SaveCache("slugs", map[string]string{"my-app": "app-1"}); err != nil {
+		t.Fatalf("SaveCache() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		cacheName string
+		age       time.Duration
+		wantFound bool
+	}{
+		{name: "fresh entry", cacheName: "slugs", age: time.Minute, wantFound: true},
+		{name: "expired entry", cacheName: "slugs", age: 10 * time.Minute},
+		{name: "missing entry", cacheName: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store.now = func() time.Time { return now.Add(tt.age) }
+
+			var slugs map[string]string
+			savedAt, found, err := store.LoadCache(tt.cacheName, 5*time.Minute, &slugs)
+			if err != nil {
+				t.Fatalf("LoadCache() unexpected error: %v", err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("LoadCache() found = %t, want %t", found, tt.wantFound)
+			}
+			if found && (slugs["my-app"] != "app-1" || !savedAt.Equal(now)) {
+				t.Errorf("LoadCache() = %v saved at %v, want the saved slugs", slugs, savedAt)
+			}
+		})
+	}
+}
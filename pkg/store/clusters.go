@@ -0,0 +1,27 @@
+package store
+
+import "time"
+
+// CreatedCluster records a Compatibility Matrix cluster the server created
+type CreatedCluster struct {
+	ClusterID string    `json:"cluster_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecordCreatedCluster notes that the server created a cluster
+func (s *Store) RecordCreatedCluster(clusterID string) error {
+	return s.Put(BucketClusters, clusterID, CreatedCluster{ClusterID: clusterID, CreatedAt: s.now().UTC()})
+}
+
+// FindCreatedCluster returns the record of a cluster the server created, reporting whether
+// the server created it
+func (s *Store) FindCreatedCluster(clusterID string) (CreatedCluster, bool, error) {
+	var cluster CreatedCluster
+	found, err := s.Get(BucketClusters, clusterID, &cluster)
+	return cluster, found, err
+}
+
+// ForgetCreatedCluster removes the record of a cluster, such as once it is deleted
+func (s *Store) ForgetCreatedCluster(clusterID string) error {
+	return s.Delete(BucketClusters, clusterID)
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_CreatedClusters(t *testing.T) {
+	store := Open(t.TempDir())
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	if err := store.RecordCreatedCluster("cluster-1"); err != nil {
+		t.Fatalf("RecordCreatedCluster() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		clusterID string
+		wantFound bool
+	}{
+		{name: "created cluster", clusterID: "cluster-1", wantFound: true},
+		{name: "other cluster", clusterID: "cluster-2", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster, found, err := store.FindCreatedCluster(tt.clusterID)
+			if err != nil {
+				t.Fatalf("FindCreatedCluster() unexpected error: %v", err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("FindCreatedCluster() found = %v, want %v", found, tt.wantFound)
+			}
+			if found && (cluster.ClusterID != tt.clusterID || !cluster.CreatedAt.Equal(now)) {
+				t.Errorf("FindCreatedCluster() = %+v, unexpected record", cluster)
+			}
+		})
+	}
+
+	if err := store.ForgetCreatedCluster("cluster-1"); err != nil {
+		t.Fatalf("ForgetCreatedCluster() unexpected error: %v", err)
+	}
+	if _, found, _ := store.FindCreatedCluster("cluster-1"); found {
+		t.Error("Expected a forgotten cluster not to be found")
+	}
+}
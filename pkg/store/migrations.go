@@ -0,0 +1,130 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Schema bookkeeping
+const (
+	bucketMeta       = "meta"
+	schemaVersionKey = "schema_version"
+
+	// legacyFileName is the JSON file that held tags and notes before the database
+	legacyFileName = "metadata.json"
+)
+
+// migration upgrades the database by one schema version
+type migration struct {
+	version     int
+	description string
+	apply       func(s *Store, tx *bolt.Tx) error
+}
+
+// migrations upgrade the database in order. Append new migrations with the next version;
+// never change or remove one that has shipped.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create record buckets",
+		apply:       createBuckets(BucketTags, BucketNotes, BucketJobs, BucketCache),
+	},
+	{
+		version:     2,
+		description: "import tags and notes from " + legacyFileName,
+		apply:       importLegacyMetadata,
+	},
+	{
+		version:     3,
+		description: "create the clusters bucket",
+		apply:       createBuckets(BucketClusters),
+	},
+}
+
+// SchemaVersion is the schema version this server migrates databases to
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate applies the migrations the database has not had yet. A database migrated by a
+// newer server is rejected rather than risk misreading it.
+func (s *Store) migrate(tx *bolt.Tx) error {
+	current, err := schemaVersion(tx)
+	if err != nil {
+		return err
+	}
+	if current > SchemaVersion() {
+		return fmt.Errorf("schema version %d is newer than this server supports (%d); upgrade the server",
+			current, SchemaVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := m.apply(s, tx); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.description, err)
+		}
+		current = m.version
+	}
+
+	meta, err := tx.CreateBucketIfNotExists([]byte(bucketMeta))
+	if err != nil {
+		return err
+	}
+	return meta.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(current)))
+}
+
+// createBuckets returns a migration that creates buckets
+func createBuckets(names ...string) func(*Store, *bolt.Tx) error {
+	return func(_ *Store, tx *bolt.Tx) error {
+		for _, name := range names {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// legacyContents is the layout of the JSON metadata file
+type legacyContents struct {
+	Tags  []Tag  `json:"tags"`
+	Notes []Note `json:"notes"`
+}
+
+// importLegacyMetadata copies tags and notes from the JSON file earlier servers kept in the
+// data directory. The file is left in place as a backup.
+func importLegacyMetadata(s *Store, tx *bolt.Tx) error {
+	data, err := os.ReadFile(filepath.Join(s.dir, legacyFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var legacy legacyContents
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", legacyFileName, err)
+	}
+
+	for _, tag := range legacy.Tags {
+		if err := putRecord(tx, BucketTags, tagKey(tag.EntityType, tag.EntityID, tag.Tag), tag); err != nil {
+			return err
+		}
+	}
+	for _, note := range legacy.Notes {
+		if err := putRecord(tx, BucketNotes, noteKey(note.EntityType, note.EntityID, note.ID), note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore_ImportsLegacyMetadata(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{
+		"tags": [{"entity_type": "customer", "entity_id": "customer-1", "tag": "airgap",
+			"created_at": "2025-01-01T00:00:00Z"}],
+		"notes": [{"id": "n1", "entity_type": "customer", "entity_id": "customer-1",
+			"text": "Prefers airgap installs", "created_at": "2025-01-01T00:00:00Z"}]
+	}`
+	if err := os.WriteFile(filepath.Join(dir, legacyFileName), []byte(legacy), storeMode); err != nil {
+		t.Fatalf("Failed to write legacy metadata: %v", err)
+	}
+
+	store := Open(dir)
+	entities, err := store.ListByTag("airgap", "")
+	if err != nil {
+		t.Fatalf("ListByTag() unexpected error: %v", err)
+	}
+	if len(entities) != 1 || entities[0].EntityID != "customer-1" {
+		t.Errorf("Expected the legacy tag to be imported, got %+v", entities)
+	}
+
+	notes, err := store.ListNotes(EntityCustomer, "customer-1")
+	if err != nil {
+		t.Fatalf("ListNotes() unexpected error: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Text != "Prefers airgap installs" {
+		t.Errorf("Expected the legacy note to be imported, got %+v", notes)
+	}
+
+	// The import runs once, so tags removed afterwards stay removed
+	if _, err := store.RemoveTags(EntityCustomer, "customer-1", []string{"airgap"}); err != nil {
+		t.Fatalf("RemoveTags() unexpected error: %v", err)
+	}
+	entities, err = Open(dir).ListByTag("airgap", "")
+	if err != nil {
+		t.Fatalf("ListByTag() unexpected error: %v", err)
+	}
+	if len(entities) != 0 {
+		t.Errorf("Expected the legacy import not to run again, got %+v", entities)
+	}
+}
+
+func TestStore_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     int
+		errContains string
+	}{
+		{name: "current schema", version: SchemaVersion()},
+		{name: "older schema is migrated", version: 1},
+		{name: "newer schema is rejected", version: SchemaVersion() + 1, errContains: "newer than this server supports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeSchemaVersion(t, dir, tt.version)
+
+			_, err := Open(dir).ListNotes(EntityCustomer, "customer-1")
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("ListNotes() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListNotes() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// writeSchemaVersion creates a database with the record buckets at a given schema version
+func writeSchemaVersion(t *testing.T, dir string, version int) {
+	t.Helper()
+
+	db, err := bolt.Open(filepath.Join(dir, FileName), storeMode, nil)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if err := createBuckets(BucketTags, BucketNotes, BucketJobs, BucketCache)(nil, tx); err != nil {
+			return err
+		}
+		meta, err := tx.CreateBucketIfNotExists([]byte(bucketMeta))
+		if err != nil {
+			return err
+		}
+		return meta.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(version)))
+	})
+	if err != nil {
+		t.Fatalf("Failed to write schema version: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Note limits
+const (
+	maxNoteLength = 4096
+	noteIDBytes   = 8
+)
+
+// Note is a free-text annotation on an application or customer
+type Note struct {
+	ID         string    `json:"id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Text       string    `json:"text"`
+	Author     string    `json:"author,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AddNote attaches a note to an entity and returns it. The author is optional.
+func (s *Store) AddNote(entityType, entityID, text, author string) (Note, error) {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return Note{}, err
+	}
+
+	text = strings.TrimSpace(text)
+	switch {
+	case text == "":
+		return Note{}, fmt.Errorf("note text cannot be empty")
+	case utf8.RuneCountInString(text) > maxNoteLength:
+		return Note{}, fmt.Errorf("note text must be %d characters or less", maxNoteLength)
+	}
+
+	id, err := newNoteID()
+	if err != nil {
+		return Note{}, err
+	}
+
+	note := Note{
+		ID:         id,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Text:       text,
+		Author:     strings.TrimSpace(author),
+		CreatedAt:  s.now().UTC(),
+	}
+	err = s.update(func(tx *bolt.Tx) error {
+		return putRecord(tx, BucketNotes, noteKey(entityType, entityID, id), note)
+	})
+	if err != nil {
+		return Note{}, err
+	}
+	return note, nil
+}
+
+// ListNotes returns an entity's notes, oldest first
+func (s *Store) ListNotes(entityType, entityID string) ([]Note, error) {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	entityRecords, err := s.Records(BucketNotes, recordKey(entityType, entityID, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []Note{}
+	for _, record := range entityRecords {
+		var note Note
+		if err := decodeRecord(BucketNotes, record.Key, record.Value, &note); err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	slices.SortStableFunc(notes, func(a, b Note) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return notes, nil
+}
+
+// noteKey is the record key of a note on an entity
+func noteKey(entityType, entityID, id string) string {
+	return recordKey(entityType, entityID, id)
+}
+
+// newNoteID generates a random note identifier
+func newNoteID() (string, error) {
+	b := make([]byte, noteIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate note ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
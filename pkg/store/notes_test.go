@@ -0,0 +1,94 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_Notes(t *testing.T) {
+	store := Open(t.TempDir())
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	first, err := store.AddNote(EntityCustomer, "customer-1", "  Prefers airgap installs ", "")
+	if err != nil {
+		t.Fatalf("AddNote() unexpected error: %v", err)
+	}
+	if first.ID == "" || first.Text != "Prefers airgap installs" {
+		t.Errorf("AddNote() = %+v, expected an ID and trimmed text", first)
+	}
+	if _, err := store.AddNote(EntityCustomer, "customer-1", "Renewal due in Q3", "alice"); err != nil {
+		t.Fatalf("AddNote() unexpected error: %v", err)
+	}
+	if _, err := store.AddNote(EntityApplication, "app-1", "Helm chart moved to OCI", ""); err != nil {
+		t.Fatalf("AddNote() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		entityType string
+		entityID   string
+		wantTexts  []string
+	}{
+		{
+			name:       "notes oldest first",
+			entityType: EntityCustomer,
+			entityID:   "customer-1",
+			wantTexts:  []string{"Prefers airgap installs", "Renewal due in Q3"},
+		},
+		{name: "other entity type", entityType: EntityApplication, entityID: "app-1",
+			wantTexts: []string{"Helm chart moved to OCI"}},
+		{name: "entity without notes", entityType: EntityCustomer, entityID: "customer-2", wantTexts: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := store.ListNotes(tt.entityType, tt.entityID)
+			if err != nil {
+				t.Fatalf("ListNotes() unexpected error: %v", err)
+			}
+			if len(notes) != len(tt.wantTexts) {
+				t.Fatalf("ListNotes() = %+v, want %v", notes, tt.wantTexts)
+			}
+			for i, text := range tt.wantTexts {
+				if notes[i].Text != text {
+					t.Errorf("Expected note %d to be %q, got %q", i, text, notes[i].Text)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_NoteValidation(t *testing.T) {
+	store := Open(t.TempDir())
+
+	tests := []struct {
+		name        string
+		entityType  string
+		entityID    string
+		text        string
+		errContains string
+	}{
+		{name: "unknown entity type", entityType: "release", entityID: "r-1", text: "a",
+			errContains: "invalid entity type 'release'"},
+		{name: "missing entity ID", entityType: EntityCustomer, text: "a",
+			errContains: "entity ID is required"},
+		{name: "empty text", entityType: EntityCustomer, entityID: "customer-1", text: "   ",
+			errContains: "cannot be empty"},
+		{name: "text too long", entityType: EntityCustomer, entityID: "customer-1",
+			text: strings.Repeat("a", maxNoteLength+1), errContains: "characters or less"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.AddNote(tt.entityType, tt.entityID, tt.text, "")
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("AddNote() error = %v, expected to contain %s", err, tt.errContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Package store persists server-side state that the Vendor Portal does not hold, such as
+// tags and notes on applications and customers, background job records, the clusters the
+// server created, and cached lookups.
+// State lives in an embedded bbolt database in the server's data directory. Each kind of
+// state has its own bucket of JSON-encoded records, and numbered migrations bring the
+// database up to date when a newer server first opens it.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store layout constants
+const (
+	FileName     = "state.db"
+	storeDirMode = 0o750
+	storeMode    = 0o600
+
+	// lockTimeout bounds how long an operation waits for another server process sharing
+	// the data directory to finish with the database
+	lockTimeout = 5 * time.Second
+
+	// keySeparator joins the parts of compound record keys; IDs never contain it
+	keySeparator = "\x00"
+)
+
+// Buckets holding each kind of record
+const (
+	BucketTags     = "tags"
+	BucketNotes    = "notes"
+	BucketJobs     = "jobs"
+	BucketCache    = "cache"
+	BucketClusters = "clusters"
+)
+
+// Entity types that metadata can be attached to
+const (
+	EntityApplication = "application"
+	EntityCustomer    = "customer"
+)
+
+// EntityTypes lists the supported entity types
+var EntityTypes = []string{EntityApplication, EntityCustomer}
+
+// Store reads and writes the state database. The database is opened for each operation
+// rather than held open, because bbolt locks the file for as long as a process has it open
+// and several server processes may share a data directory.
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	now      func() time.Time
+	migrated bool
+}
+
+// Record is a stored record with its key, as returned by Records
+type Record struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// Open returns a store that keeps its database in dir. The database is created, and
+// migrated to the current schema, on the first operation.
+func Open(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// Dir returns the directory holding the store's database
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// path returns the location of the database file
+func (s *Store) path() string {
+	return filepath.Join(s.dir, FileName)
+}
+
+// open opens the database, migrating it the first time this store opens it
+func (s *Store) open() (*bolt.DB, error) {
+	if err := os.MkdirAll(s.dir, storeDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	db, err := bolt.Open(filepath.Clean(s.path()), storeMode, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store %s: %w", s.path(), err)
+	}
+
+	if !s.migrated {
+		if err := db.Update(s.migrate); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate state store %s: %w", s.path(), err)
+		}
+		s.migrated = true
+	}
+	return db, nil
+}
+
+// update runs fn in a read-write transaction, committing its changes if it succeeds
+func (s *Store) update(fn func(tx *bolt.Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(fn)
+}
+
+// view runs fn in a read-only transaction
+func (s *Store) view(fn func(tx *bolt.Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(fn)
+}
+
+// Put stores value as a JSON-encoded record under key in a bucket, replacing any record
+// already stored there
+func (s *Store) Put(bucket, key string, value any) error {
+	return s.update(func(tx *bolt.Tx) error {
+		return putRecord(tx, bucket, key, value)
+	})
+}
+
+// Get decodes the record stored under key in a bucket into value, reporting whether the
+// record exists
+func (s *Store) Get(bucket, key string, value any) (bool, error) {
+	found := false
+	err := s.view(func(tx *bolt.Tx) error {
+		b, err := recordBucket(tx, bucket)
+		if err != nil {
+			return err
+		}
+		data := b.Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return decodeRecord(bucket, key, data, value)
+	})
+	return found, err
+}
+
+// Delete removes the record stored under key in a bucket, if there is one
+func (s *Store) Delete(bucket, key string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		b, err := recordBucket(tx, bucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// Records returns the records in a bucket whose keys start with prefix, in key order
+func (s *Store) Records(bucket, prefix string) ([]Record, error) {
+	var result []Record
+	err := s.view(func(tx *bolt.Tx) error {
+		var err error
+		result, err = records(tx, bucket, prefix)
+		return err
+	})
+	return result, err
+}
+
+// recordBucket returns a bucket created by the migrations
+func recordBucket(tx *bolt.Tx, bucket string) (*bolt.Bucket, error) {
+	b := tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil, fmt.Errorf("unknown state store bucket '%s'", bucket)
+	}
+	return b, nil
+}
+
+// putRecord stores a JSON-encoded record within a transaction
+func putRecord(tx *bolt.Tx, bucket, key string, value any) error {
+	b, err := recordBucket(tx, bucket)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", bucket, err)
+	}
+	return b.Put([]byte(key), data)
+}
+
+// records returns the records with a key prefix within a transaction
+func records(tx *bolt.Tx, bucket, prefix string) ([]Record, error) {
+	b, err := recordBucket(tx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Record{}
+	cursor := b.Cursor()
+	for k, v := cursor.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = cursor.Next() {
+		// bbolt's values are only valid for the life of the transaction
+		result = append(result, Record{Key: string(k), Value: slices.Clone(v)})
+	}
+	return result, nil
+}
+
+// decodeRecord decodes a record, naming it in the error if it is corrupt
+func decodeRecord(bucket, key string, data []byte, value any) error {
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to decode %s record '%s': %w",
+			bucket, strings.ReplaceAll(key, keySeparator, "/"), err)
+	}
+	return nil
+}
+
+// recordKey joins the parts of a compound key
+func recordKey(parts ...string) string {
+	return strings.Join(parts, keySeparator)
+}
+
+// validateEntity checks an entity type and ID
+func validateEntity(entityType, entityID string) error {
+	if err := validateEntityType(entityType); err != nil {
+		return err
+	}
+	if entityID == "" {
+		return fmt.Errorf("entity ID is required")
+	}
+	if strings.Contains(entityID, keySeparator) {
+		return fmt.Errorf("invalid entity ID '%s'", entityID)
+	}
+	return nil
+}
+
+// validateEntityType checks that an entity type is supported
+func validateEntityType(entityType string) error {
+	if !slices.Contains(EntityTypes, entityType) {
+		return fmt.Errorf("invalid entity type '%s'. Valid types are: %s",
+			entityType, strings.Join(EntityTypes, ", "))
+	}
+	return nil
+}
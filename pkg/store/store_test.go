@@ -0,0 +1,108 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStore_Persistence(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+
+	if _, err := Open(dir).AddTags(EntityApplication, "app-1", []string{"enterprise"}); err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("Expected the database to be written: %v", err)
+	}
+	if info.Mode().Perm() != storeMode {
+		t.Errorf("Expected database mode %o, got %o", storeMode, info.Mode().Perm())
+	}
+
+	// A new store on the same directory sees the saved tags
+	entities, err := Open(dir).ListByTag("enterprise", "")
+	if err != nil {
+		t.Fatalf("ListByTag() unexpected error: %v", err)
+	}
+	if len(entities) != 1 || entities[0].EntityID != "app-1" {
+		t.Errorf("Expected the saved tag to be read back, got %+v", entities)
+	}
+}
+
+func TestStore_MissingAndCorruptFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		errContains string
+	}{
+		{name: "missing file is empty"},
+		{name: "corrupt file", file: "{not a database", errContains: "failed to open state store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.file != "" {
+				if err := os.WriteFile(filepath.Join(dir, FileName), []byte(tt.file), storeMode); err != nil {
+					t.Fatalf("Failed to write database file: %v", err)
+				}
+			}
+
+			entities, err := Open(dir).ListByTag("enterprise", "")
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("ListByTag() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListByTag() unexpected error: %v", err)
+			}
+			if len(entities) != 0 {
+				t.Errorf("Expected no entities, got %+v", entities)
+			}
+		})
+	}
+}
+
+func TestStore_Records(t *testing.T) {
+	store := Open(t.TempDir())
+
+	type job struct {
+		Status string `json:"status"`
+	}
+	for key, status := range map[string]string{"job-1": "running", "job-2": "succeeded", "other": "failed"} {
+		if err := store.Put(BucketJobs, key, job{Status: status}); err != nil {
+			t.Fatalf("Put() unexpected error: %v", err)
+		}
+	}
+
+	var got job
+	found, err := store.Get(BucketJobs, "job-2", &got)
+	if err != nil || !found || got.Status != "succeeded" {
+		t.Errorf("Get() = %+v, %t, %v, want succeeded", got, found, err)
+	}
+
+	if err := store.Delete(BucketJobs, "job-2"); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if found, err := store.Get(BucketJobs, "job-2", &got); err != nil || found {
+		t.Errorf("Get() after Delete() = %t, %v, want not found", found, err)
+	}
+
+	jobs, err := store.Records(BucketJobs, "job-")
+	if err != nil {
+		t.Fatalf("Records() unexpected error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Key != "job-1" || string(jobs[0].Value) != `{"status":"running"}` {
+		t.Errorf("Records() = %+v, want only job-1", jobs)
+	}
+
+	err = store.Put("unknown", "key", job{})
+	if err == nil || !strings.Contains(err.Error(), "unknown state store bucket") {
+		t.Errorf("Put() to an unknown bucket error = %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxTagLength is the longest tag accepted
+const maxTagLength = 64
+
+// tagPattern restricts tags to lowercase letters, digits, and simple separators
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:/-]*$`)
+
+// Tag attaches a label to an application or customer
+type Tag struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Tag        string    `json:"tag"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TaggedEntity is an entity carrying a tag, with all of the entity's tags
+type TaggedEntity struct {
+	EntityType string   `json:"entity_type"`
+	EntityID   string   `json:"entity_id"`
+	Tags       []string `json:"tags"`
+}
+
+// NormalizeTag trims and lowercases a tag and checks that it is well formed
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case normalized == "":
+		return "", fmt.Errorf("tag cannot be empty")
+	case len(normalized) > maxTagLength:
+		return "", fmt.Errorf("tag '%s' must be %d characters or less", tag, maxTagLength)
+	case !tagPattern.MatchString(normalized):
+		return "", fmt.Errorf("tag '%s' may only contain letters, digits, and . _ : / -", tag)
+	}
+	return normalized, nil
+}
+
+// normalizeTags normalizes a list of tags, dropping duplicates
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("at least one tag is required")
+	}
+
+	var normalized []string
+	for _, tag := range tags {
+		n, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(normalized, n) {
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized, nil
+}
+
+// AddTags tags an entity and returns all of its tags. Tags it already has are kept as they are.
+func (s *Store) AddTags(entityType, entityID string, tags []string) ([]string, error) {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	err = s.update(func(tx *bolt.Tx) error {
+		now := s.now().UTC()
+		existing, err := tagsOf(tx, entityType, entityID)
+		if err != nil {
+			return err
+		}
+		for _, tag := range normalized {
+			if slices.Contains(existing, tag) {
+				continue
+			}
+			record := Tag{EntityType: entityType, EntityID: entityID, Tag: tag, CreatedAt: now}
+			if err := putRecord(tx, BucketTags, tagKey(entityType, entityID, tag), record); err != nil {
+				return err
+			}
+		}
+		result, err = tagsOf(tx, entityType, entityID)
+		return err
+	})
+	return result, err
+}
+
+// RemoveTags removes tags from an entity and returns its remaining tags
+func (s *Store) RemoveTags(entityType, entityID string, tags []string) ([]string, error) {
+	if err := validateEntity(entityType, entityID); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	err = s.update(func(tx *bolt.Tx) error {
+		b, err := recordBucket(tx, BucketTags)
+		if err != nil {
+			return err
+		}
+		for _, tag := range normalized {
+			if err := b.Delete([]byte(tagKey(entityType, entityID, tag))); err != nil {
+				return err
+			}
+		}
+		result, err = tagsOf(tx, entityType, entityID)
+		return err
+	})
+	return result, err
+}
+
+// ListByTag returns the entities carrying a tag, optionally limited to one entity type,
+// sorted by type and ID
+func (s *Store) ListByTag(tag, entityType string) ([]TaggedEntity, error) {
+	normalized, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	if entityType != "" {
+		if err := validateEntityType(entityType); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keys sort by entity type and ID, so matches are found in order
+	entities := []TaggedEntity{}
+	err = s.view(func(tx *bolt.Tx) error {
+		all, err := records(tx, BucketTags, "")
+		if err != nil {
+			return err
+		}
+		for _, record := range all {
+			var t Tag
+			if err := decodeRecord(BucketTags, record.Key, record.Value, &t); err != nil {
+				return err
+			}
+			if t.Tag != normalized || (entityType != "" && t.EntityType != entityType) {
+				continue
+			}
+			tags, err := tagsOf(tx, t.EntityType, t.EntityID)
+			if err != nil {
+				return err
+			}
+			entities = append(entities, TaggedEntity{EntityType: t.EntityType, EntityID: t.EntityID, Tags: tags})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// tagKey is the record key of a tag on an entity
+func tagKey(entityType, entityID, tag string) string {
+	return recordKey(entityType, entityID, tag)
+}
+
+// tagsOf returns an entity's tags, sorted
+func tagsOf(tx *bolt.Tx, entityType, entityID string) ([]string, error) {
+	entityRecords, err := records(tx, BucketTags, recordKey(entityType, entityID, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, record := range entityRecords {
+		var t Tag
+		if err := decodeRecord(BucketTags, record.Key, record.Value, &t); err != nil {
+			return nil, err
+		}
+		result = append(result, t.Tag)
+	}
+	slices.Sort(result)
+	return result, nil
+}
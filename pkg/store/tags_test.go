@@ -0,0 +1,126 @@
+package store
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tag         string
+		want        string
+		errContains string
+	}{
+		{name: "lowercased and trimmed", tag: "  Enterprise ", want: "enterprise"},
+		{name: "separators", tag: "region:eu-west/1", want: "region:eu-west/1"},
+		{name: "empty", tag: " ", errContains: "cannot be empty"},
+		{name: "spaces", tag: "big customer", errContains: "may only contain"},
+		{name: "too long", tag: strings.Repeat("a", maxTagLength+1), errContains: "64 characters or less"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTag(tt.tag)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("NormalizeTag() error = %v, expected to contain %s", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeTag() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeTag() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Tags(t *testing.T) {
+	store := Open(t.TempDir())
+
+	tags, err := store.AddTags(EntityCustomer, "customer-1", []string{"Airgap", "enterprise", "airgap"})
+	if err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+	if !slices.Equal(tags, []string{"airgap", "enterprise"}) {
+		t.Errorf("AddTags() = %v, want [airgap enterprise]", tags)
+	}
+
+	// Adding a tag the entity already has is a no-op
+	if _, err := store.AddTags(EntityCustomer, "customer-1", []string{"enterprise"}); err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+	if _, err := store.AddTags(EntityApplication, "app-1", []string{"enterprise"}); err != nil {
+		t.Fatalf("AddTags() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		tag        string
+		entityType string
+		wantIDs    []string
+	}{
+		{name: "every entity type", tag: "enterprise", wantIDs: []string{"app-1", "customer-1"}},
+		{name: "one entity type", tag: "enterprise", entityType: EntityCustomer, wantIDs: []string{"customer-1"}},
+		{name: "tag case is ignored", tag: "AIRGAP", wantIDs: []string{"customer-1"}},
+		{name: "unused tag", tag: "trial", wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entities, err := store.ListByTag(tt.tag, tt.entityType)
+			if err != nil {
+				t.Fatalf("ListByTag() unexpected error: %v", err)
+			}
+			ids := []string{}
+			for _, entity := range entities {
+				ids = append(ids, entity.EntityID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("ListByTag() IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+
+	tags, err = store.RemoveTags(EntityCustomer, "customer-1", []string{"airgap"})
+	if err != nil {
+		t.Fatalf("RemoveTags() unexpected error: %v", err)
+	}
+	if !slices.Equal(tags, []string{"enterprise"}) {
+		t.Errorf("RemoveTags() = %v, want [enterprise]", tags)
+	}
+}
+
+func TestStore_TagValidation(t *testing.T) {
+	store := Open(t.TempDir())
+
+	tests := []struct {
+		name        string
+		entityType  string
+		entityID    string
+		tags        []string
+		errContains string
+	}{
+		{name: "unknown entity type", entityType: "release", entityID: "r-1", tags: []string{"a"},
+			errContains: "invalid entity type 'release'"},
+		{name: "missing entity ID", entityType: EntityCustomer, tags: []string{"a"},
+			errContains: "entity ID is required"},
+		{name: "no tags", entityType: EntityCustomer, entityID: "customer-1",
+			errContains: "at least one tag"},
+		{name: "malformed tag", entityType: EntityCustomer, entityID: "customer-1", tags: []string{"a b"},
+			errContains: "may only contain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.AddTags(tt.entityType, tt.entityID, tt.tags)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("AddTags() error = %v, expected to contain %s", err, tt.errContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Package timerange parses the time_range argument shared by the reporting tools. A range is
+// either relative to now, such as last_30d, or absolute, such as 2025-01-01/2025-03-31, so
+// every report accepts the same windows and rejects malformed ones the same way.
+package timerange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Units of relative ranges, also used to bucket reports by day or week
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// relativePrefix starts a relative range, such as last_30d
+const relativePrefix = "last_"
+
+// separator divides the start and end of an absolute range, as in ISO 8601 intervals
+const separator = "/"
+
+// dateLayout is the layout of dates in absolute ranges
+const dateLayout = "2006-01-02"
+
+// MaxDuration is the longest range accepted, to catch mistyped values such as last_3000w
+const MaxDuration = 10 * 366 * Day
+
+// Description explains the accepted formats, for tool argument descriptions
+const Description = "A time range: relative to now as last_<n><unit> with unit h (hours), d (days), or w " +
+	"(weeks), such as last_24h or last_30d; or absolute as <start>/<end> with dates (2025-01-01, inclusive) " +
+	"or RFC 3339 timestamps, where either side may be left empty for an open-ended range, such as 2025-01-01/"
+
+// relativeUnits maps the unit suffixes of relative ranges to their durations
+var relativeUnits = map[byte]time.Duration{'h': time.Hour, 'd': Day, 'w': Week}
+
+// Range is a window of time from Start, inclusive, to End, exclusive. A zero Start or End
+// leaves that side of the range open.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Parse parses a relative or absolute range, resolving relative ranges against now
+func Parse(value string, now time.Time) (*Range, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("time range is required")
+	}
+
+	if amount, ok := strings.CutPrefix(strings.ToLower(value), relativePrefix); ok {
+		return parseRelative(amount, value, now)
+	}
+	if start, end, ok := strings.Cut(value, separator); ok {
+		return parseAbsolute(start, end, value)
+	}
+	return nil, fmt.Errorf("invalid time range '%s': expected last_<n><unit> or <start>/<end>", value)
+}
+
+// parseRelative parses the amount and unit of a relative range, such as 30d
+func parseRelative(amount, value string, now time.Time) (*Range, error) {
+	if amount == "" {
+		return nil, fmt.Errorf("invalid time range '%s': expected a number and unit after %s", value, relativePrefix)
+	}
+
+	unit, ok := relativeUnits[amount[len(amount)-1]]
+	if !ok {
+		return nil, fmt.Errorf("invalid time range '%s': unit must be h, d, or w", value)
+	}
+	count, err := strconv.Atoi(amount[:len(amount)-1])
+	if err != nil || count <= 0 {
+		return nil, fmt.Errorf("invalid time range '%s': expected a positive number before the unit", value)
+	}
+	if int64(count) > int64(MaxDuration/unit) {
+		return nil, fmt.Errorf("invalid time range '%s': ranges are limited to %d days", value, MaxDuration/Day)
+	}
+
+	return &Range{Start: now.Add(-time.Duration(count) * unit), End: now}, nil
+}
+
+// parseAbsolute parses the start and end of an absolute range. A date as the end includes
+// the whole day.
+func parseAbsolute(start, end, value string) (*Range, error) {
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if start == "" && end == "" {
+		return nil, fmt.Errorf("invalid time range '%s': expected a start, an end, or both", value)
+	}
+
+	r := &Range{}
+	if start != "" {
+		t, err := parseTime(start, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time range '%s': start %w", value, err)
+		}
+		r.Start = t
+	}
+	if end != "" {
+		t, err := parseTime(end, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time range '%s': end %w", value, err)
+		}
+		r.End = t
+	}
+
+	if !r.Start.IsZero() && !r.End.IsZero() {
+		if !r.Start.Before(r.End) {
+			return nil, fmt.Errorf("invalid time range '%s': start must be before end", value)
+		}
+		if r.End.Sub(r.Start) > MaxDuration {
+			return nil, fmt.Errorf("invalid time range '%s': ranges are limited to %d days", value, MaxDuration/Day)
+		}
+	}
+	return r, nil
+}
+
+// parseTime parses a date, as midnight UTC or, for the end of a range, the following
+// midnight, or an RFC 3339 timestamp
+func parseTime(value string, end bool) (time.Time, error) {
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		if end {
+			return date.Add(Day), nil
+		}
+		return date, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be a date (YYYY-MM-DD) or RFC 3339 timestamp, got '%s'", value)
+	}
+	return t, nil
+}
+
+// Contains reports whether t falls within the range
+func (r *Range) Contains(t time.Time) bool {
+	if !r.Start.IsZero() && t.Before(r.Start) {
+		return false
+	}
+	return r.End.IsZero() || t.Before(r.End)
+}
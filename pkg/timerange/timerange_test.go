@@ -0,0 +1,92 @@
+package timerange
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    Range
+		errContains string
+	}{
+		{name: "last days", value: "last_30d", expected: Range{Start: now.AddDate(0, 0, -30), End: now}},
+		{name: "last hours", value: "last_24h", expected: Range{Start: now.Add(-24 * time.Hour), End: now}},
+		{name: "last weeks", value: " LAST_2W ", expected: Range{Start: now.AddDate(0, 0, -14), End: now}},
+		{
+			name:     "dates include the end day",
+			value:    "2025-01-01/2025-03-31",
+			expected: Range{Start: date(2025, 1, 1), End: date(2025, 4, 1)},
+		},
+		{
+			name:     "timestamps",
+			value:    "2025-01-01T08:00:00Z/2025-01-01T17:00:00Z",
+			expected: Range{Start: date(2025, 1, 1).Add(8 * time.Hour), End: date(2025, 1, 1).Add(17 * time.Hour)},
+		},
+		{name: "open end", value: "2025-01-01/", expected: Range{Start: date(2025, 1, 1)}},
+		{name: "open start", value: "/2025-01-01", expected: Range{End: date(2025, 1, 2)}},
+		{name: "empty", value: " ", errContains: "time range is required"},
+		{name: "unknown format", value: "yesterday", errContains: "expected last_<n><unit> or <start>/<end>"},
+		{name: "missing amount", value: "last_", errContains: "expected a number and unit"},
+		{name: "unknown unit", value: "last_30x", errContains: "unit must be h, d, or w"},
+		{name: "zero amount", value: "last_0d", errContains: "expected a positive number"},
+		{name: "relative too long", value: "last_3000w", errContains: "ranges are limited to 3660 days"},
+		{name: "both sides open", value: "/", errContains: "expected a start, an end, or both"},
+		{name: "invalid start", value: "01/02/2025", errContains: "start must be a date"},
+		{name: "end before start", value: "2025-03-01/2025-01-01", errContains: "start must be before end"},
+		{name: "absolute too long", value: "2000-01-01/2025-01-01", errContains: "ranges are limited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.value, now)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Expected error containing '%s', got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Start.Equal(tt.expected.Start) || !got.End.Equal(tt.expected.End) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.value, *got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRange_Contains(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name     string
+		r        Range
+		t        time.Time
+		expected bool
+	}{
+		{name: "inside", r: Range{Start: start, End: end}, t: start.AddDate(0, 0, 10), expected: true},
+		{name: "start is included", r: Range{Start: start, End: end}, t: start, expected: true},
+		{name: "end is excluded", r: Range{Start: start, End: end}, t: end, expected: false},
+		{name: "before", r: Range{Start: start, End: end}, t: start.Add(-time.Second), expected: false},
+		{name: "open end", r: Range{Start: start}, t: end.AddDate(5, 0, 0), expected: true},
+		{name: "open start", r: Range{End: end}, t: start.AddDate(-5, 0, 0), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Contains(tt.t); got != tt.expected {
+				t.Errorf("Contains(%v) = %t, want %t", tt.t, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package tracing exports OpenTelemetry traces of tool calls and the Vendor Portal API
+// requests they make, so operators embedding the server in a larger agent platform can
+// follow a request from the agent through the server to the Vendor Portal.
+//
+// Until Setup configures an exporter, spans are recorded by OpenTelemetry's no-op provider
+// and cost next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Constants identifying the server's spans
+const (
+	// ServiceName is the service spans are reported for
+	ServiceName = "replicated-mcp-server"
+	// instrumentationName identifies the server's tracer
+	instrumentationName = "github.com/crdant/replicated-mcp-server"
+)
+
+// Tracer returns the tracer the server's spans are created with
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Propagator returns the propagator that carries trace context in W3C traceparent and
+// baggage headers, to and from other services
+func Propagator() propagation.TextMapPropagator {
+	return otel.GetTextMapPropagator()
+}
+
+// Setup exports spans over OTLP/HTTP to endpoint, such as http://localhost:4318, and
+// returns a function that flushes and stops the exporter. With an empty endpoint nothing
+// is exported and the returned function does nothing. Other exporter settings, such as
+// headers and compression, are read from the standard OTEL_EXPORTER_OTLP_* variables.
+func Setup(ctx context.Context, endpoint, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion(version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+
+	tests := []struct {
+		name     string
+		endpoint string
+		exports  bool
+	}{
+		{
+			name:     "no endpoint",
+			endpoint: "",
+			exports:  false,
+		},
+		{
+			name:     "collector endpoint",
+			endpoint: "http://127.0.0.1:4318",
+			exports:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			otel.SetTracerProvider(provider)
+
+			shutdown, err := Setup(context.Background(), tt.endpoint, "test")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if exports := otel.GetTracerProvider() != provider; exports != tt.exports {
+				t.Errorf("Expected exporting %v, got %v", tt.exports, exports)
+			}
+			if fields := Propagator().Fields(); len(fields) == 0 {
+				t.Error("Expected trace context to be propagated")
+			}
+
+			// Nothing was traced, so shutting down has nothing to send to the collector
+			if err := shutdown(context.Background()); err != nil {
+				t.Errorf("Unexpected error shutting down: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package vendorapi defines the Vendor Portal operations the MCP server's handlers depend on
+// as interfaces, so they can run against the HTTP client in pkg/api, test doubles, services
+// wrapped in middleware, or alternative backends. Request and response types are shared with
+// pkg/api, whose services are the HTTP implementation.
+package vendorapi
+
+import (
+	"context"
+	"iter"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+	"github.com/crdant/replicated-mcp-server/pkg/models"
+)
+
+// Client is the connection shared by the services: it can be reconfigured at runtime,
+// verify its credentials, report the outcome of each request, and write out what it
+// captured when the server stops
+type Client interface {
+	UpdateConfig(config api.ClientConfig) error
+	VerifyToken(ctx context.Context) error
+	SetObserver(observer api.RequestObserver)
+	Close() error
+}
+
+// Applications lists, creates, and archives applications, and lists their activity
+type Applications interface {
+	ListApplications(ctx context.Context, opts *api.ListApplicationsOptions) (*api.ApplicationList, error)
+	Applications(ctx context.Context) iter.Seq2[models.Application, error]
+	GetApplication(ctx context.Context, id string) (*models.Application, error)
+	SearchApplications(ctx context.Context, query string, opts *api.ListApplicationsOptions) (*api.ApplicationList, error)
+	Create(ctx context.Context, name string) (*models.Application, error)
+	Archive(ctx context.Context, id string) error
+	ListActivity(ctx context.Context, appID string, filter api.ActivityFilter) (*api.ActivityList, error)
+}
+
+// Channels lists an application's channels and their release histories, and updates their
+// settings
+type Channels interface {
+	ListChannels(ctx context.Context, appID string) (*api.ChannelList, error)
+	Channels(ctx context.Context, appID string) iter.Seq2[models.Channel, error]
+	SearchChannels(ctx context.Context, appID, query string) (*api.ChannelList, error)
+	ListChannelReleases(ctx context.Context, appID, channelID string) (*api.ChannelReleaseList, error)
+	UpdateVersioning(
+		ctx context.Context, appID string, channel *models.Channel, versioning models.ChannelVersioning,
+	) (*models.Channel, error)
+	UpdateSettings(
+		ctx context.Context, appID string, channel *models.Channel, settings models.ChannelSettings,
+	) (*models.Channel, error)
+}
+
+// Releases lists, reads, and promotes an application's releases
+type Releases interface {
+	ListReleases(ctx context.Context, appID string) (*api.ReleaseList, error)
+	Releases(ctx context.Context, appID string) iter.Seq2[models.Release, error]
+	SearchReleases(ctx context.Context, appID, query string) (*api.ReleaseList, error)
+	GetRelease(ctx context.Context, appID string, sequence int64) (*models.Release, error)
+	ListByChannel(ctx context.Context, appID, channelID string) (*api.ReleaseList, error)
+	GetByVersionLabel(ctx context.Context, appID, version string) (*models.Release, error)
+	DownloadArchive(ctx context.Context, appID string, sequence int64, dir string) (*api.FileBody, error)
+	PromoteRelease(ctx context.Context, appID string, sequence int64, request api.PromoteReleaseRequest) error
+	Changelog(ctx context.Context, appID string, query api.ChangelogQuery) (*api.Changelog, error)
+}
+
+// Customers lists, filters, updates, and archives an application's customers, downloads
+// their licenses, and lists their instances' events
+type Customers interface {
+	ListCustomers(ctx context.Context, appID string) (*api.CustomerList, error)
+	ListCustomersUpTo(ctx context.Context, appID string, limit int) (*api.CustomerList, error)
+	Customers(ctx context.Context, appID string) iter.Seq2[models.Customer, error]
+	SearchCustomers(ctx context.Context, appID, query string, scanLimit int) (*api.CustomerList, error)
+	FilterCustomers(ctx context.Context, appID string, filter api.CustomerFilter, scanLimit int) (*api.CustomerList, error)
+	Archive(ctx context.Context, customerID string) error
+	Unarchive(ctx context.Context, customerID string) error
+	Update(ctx context.Context, customer *models.Customer) (*models.Customer, error)
+	UpdateCustomFields(ctx context.Context, customer *models.Customer, fields map[string]string) (*models.Customer, error)
+	ListDownloads(ctx context.Context, customerID string) (*api.DownloadList, error)
+	ListInstanceEvents(ctx context.Context, customerID, instanceID string) (*api.InstanceEventList, error)
+	DownloadLicense(ctx context.Context, appID, customerID string) (string, error)
+}
+
+// SupportBundles lists and reads the support bundles customers uploaded
+type SupportBundles interface {
+	ListSupportBundles(
+		ctx context.Context, appID string, opts *api.ListSupportBundlesOptions,
+	) (*api.SupportBundleList, error)
+	GetSupportBundle(ctx context.Context, bundleID string) (*models.SupportBundle, error)
+}
+
+// Entitlements reads an application's license fields and reads and sets customers' values
+type Entitlements interface {
+	ListLicenseFields(ctx context.Context, appID string) (*api.LicenseFieldList, error)
+	GetCustomerEntitlements(ctx context.Context, customerID string) (*api.CustomerEntitlements, error)
+	SetCustomerEntitlement(ctx context.Context, customerID, fieldName, value string) (*models.EntitlementValue, error)
+}
+
+// Registry lists the tags of images in the vendor's registry
+type Registry interface {
+	ListTags(ctx context.Context, repository string) (*api.ImageTags, error)
+}
+
+// Installers lists an application's installer specs
+type Installers interface {
+	ListInstallers(ctx context.Context, appID string) (*api.InstallerList, error)
+}
+
+// Clusters manages Compatibility Matrix clusters
+type Clusters interface {
+	ListClusters(ctx context.Context, includeTerminated bool) (*api.ClusterList, error)
+	CreateCluster(ctx context.Context, spec *models.ClusterSpec) (*models.Cluster, error)
+	DeleteCluster(ctx context.Context, clusterID string) error
+	GetKubeconfig(ctx context.Context, clusterID string) (string, error)
+}
+
+// VMs manages Compatibility Matrix virtual machines
+type VMs interface {
+	ListVMs(ctx context.Context, includeTerminated bool) (*api.VMList, error)
+	CreateVM(ctx context.Context, spec *models.VMSpec) (*models.VM, error)
+	DeleteVM(ctx context.Context, vmID string) error
+	GetSSHEndpoint(ctx context.Context, vmID string) (*models.SSHEndpoint, error)
+}
+
+// Services holds the Vendor Portal services the MCP server's handlers call
+type Services struct {
+	Client         Client
+	Applications   Applications
+	Channels       Channels
+	Releases       Releases
+	Customers      Customers
+	SupportBundles SupportBundles
+	Entitlements   Entitlements
+	Registry       Registry
+	Installers     Installers
+	Clusters       Clusters
+	VMs            VMs
+}
+
+// NewHTTPServices creates Services that call the Vendor Portal API through the HTTP client
+func NewHTTPServices(client *api.Client) *Services {
+	return &Services{
+		Client:         client,
+		Applications:   api.NewApplicationService(client),
+		Channels:       api.NewChannelService(client),
+		Releases:       api.NewReleaseService(client),
+		Customers:      api.NewCustomerService(client),
+		SupportBundles: api.NewSupportBundleService(client),
+		Entitlements:   api.NewEntitlementService(client),
+		Registry:       api.NewRegistryService(client),
+		Installers:     api.NewInstallerService(client),
+		Clusters:       api.NewClusterService(client),
+		VMs:            api.NewVMService(client),
+	}
+}
+
+// The pkg/api services implement the interfaces
+var (
+	_ Client         = (*api.Client)(nil)
+	_ Applications   = (*api.ApplicationService)(nil)
+	_ Channels       = (*api.ChannelService)(nil)
+	_ Releases       = (*api.ReleaseService)(nil)
+	_ Customers      = (*api.CustomerService)(nil)
+	_ SupportBundles = (*api.SupportBundleService)(nil)
+	_ Entitlements   = (*api.EntitlementService)(nil)
+	_ Registry       = (*api.RegistryService)(nil)
+	_ Installers     = (*api.InstallerService)(nil)
+	_ Clusters       = (*api.ClusterService)(nil)
+	_ VMs            = (*api.VMService)(nil)
+)
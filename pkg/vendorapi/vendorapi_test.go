@@ -0,0 +1,22 @@
+package vendorapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/crdant/replicated-mcp-server/pkg/api"
+)
+
+func TestNewHTTPServices(t *testing.T) {
+	client, err := api.NewClient(api.ClientConfig{APIToken: "test-token", BaseURL: api.DefaultBaseURL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	services := reflect.ValueOf(*NewHTTPServices(client))
+	for i := range services.NumField() {
+		if services.Field(i).IsNil() {
+			t.Errorf("Expected %s to be set", services.Type().Field(i).Name)
+		}
+	}
+}